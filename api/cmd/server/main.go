@@ -3,29 +3,106 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/urbis/api/internal/logging"
+	"github.com/urbis/api/internal/metrics"
 	"github.com/urbis/api/internal/service"
+	"github.com/urbis/api/internal/tracing"
 	"github.com/urbis/api/pkg/pb"
 	"github.com/urbis/api/pkg/urbis"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
 var (
+	host        = flag.String("host", "", "Interface address to listen on (empty = all interfaces)")
 	port        = flag.Int("port", 50051, "The server port")
 	enableReflection = flag.Bool("reflection", true, "Enable gRPC reflection for debugging")
+	metricsPort = flag.Int("metrics-port", 0, "The Prometheus metrics port (0 = disabled)")
+	tlsCert     = flag.String("tls-cert", "", "Server TLS certificate file (enables TLS)")
+	tlsKey      = flag.String("tls-key", "", "Server TLS private key file")
+	clientCA    = flag.String("client-ca", "", "CA certificate for verifying client certificates (enables mutual TLS)")
+	stateDir    = flag.String("state-dir", "", "Directory for the index manifest (enables recovery across restarts)")
+	maxRecvMsgSize = flag.Int("max-recv-msg-size", 100, "Maximum gRPC message size the server accepts, in MB")
+	maxSendMsgSize = flag.Int("max-send-msg-size", 100, "Maximum gRPC message size the server sends, in MB")
+	logLevel    = flag.String("log-level", "info", "Minimum log level: debug, info, warn or error")
+	logFormat   = flag.String("log-format", "text", "Log output format: text or json")
+	maxConcurrentQueries = flag.Int("max-concurrent-queries", 0, "Maximum queries running at once against each index (0 = unlimited)")
+	queryQueueTimeout = flag.Duration("query-queue-timeout", 0, "How long a query waits for a free slot before failing with RESOURCE_EXHAUSTED (0 = fail immediately)")
+	queryTimeout = flag.Duration("query-timeout", 0, "Longest a spatial query may run before failing with DEADLINE_EXCEEDED (0 = only the client deadline applies)")
+	allowedFetchHosts = flag.String("allowed-fetch-hosts", "", "Comma-separated hosts LoadGeoJSONURL may download from (empty disables it)")
+	maxFetchBytes = flag.Int64("max-fetch-bytes", service.DefaultMaxFetchBytes, "Largest document LoadGeoJSONURL downloads, in bytes")
+	shutdownTimeout = flag.Duration("shutdown-timeout", 30*time.Second, "How long in-flight requests may run after a shutdown signal before the server is forcibly stopped")
+	sweepInterval = flag.Duration("sweep-interval", time.Minute, "How often expired objects (inserted with ttl_ms) are removed from every index (0 = only on SweepExpired)")
+	syncInterval = flag.Duration("sync-interval", 0, "How often persistent indexes that changed are saved to their data files in the background, and flushed at shutdown (0 = only on Save and Sync)")
+	apiKeysFile = flag.String("api-keys", "", "JSON file mapping API keys to the index-ID prefixes they may use (empty disables API keys)")
+	defaultBlockSize = flag.Uint64("default-block-size", 0, "Block size for new indexes whose config leaves it 0 (0 = library default)")
+	defaultPageCapacity = flag.Uint64("default-page-capacity", 0, "Page capacity for new indexes whose config leaves it 0 (0 = library default)")
+	defaultCacheSize = flag.Uint64("default-cache-size", 0, "Page cache size for new indexes whose config leaves it 0 (0 = library default)")
+	defaultQuadtree = flag.Bool("default-quadtree", true, "Build the quadtree in new indexes created without a config (a request's config keeps its own enable_quadtree)")
+	otelEndpoint = flag.String("otel-endpoint", "", "OTLP/gRPC collector URL to export traces to, e.g. http://localhost:4317 (empty disables tracing)")
 )
 
+// version is the server build version, set with
+// -ldflags "-X main.version=..."
+var version = "dev"
+
+// envPrefix starts the environment variable that sets each flag, e.g.
+// URBIS_PORT for --port and URBIS_LOG_LEVEL for --log-level
+const envPrefix = "URBIS_"
+
+// maxSaneMsgSizeMB is the limit above which message size flags trigger a warning
+const maxSaneMsgSizeMB = 1024
+
 func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintf(flag.CommandLine.Output(), "\nEvery flag can also be set as %s<NAME>, e.g. %sMETRICS_PORT; flags take precedence.\n", envPrefix, envPrefix)
+	}
 	flag.Parse()
+	if err := applyEnv(flag.CommandLine, os.LookupEnv); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid environment: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Operational logs go to stderr; the banner and usage stay on stdout
+	logger, err := logging.New(os.Stderr, *logLevel, *logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid logging flags: %v\n", err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	if *maxConcurrentQueries < 0 {
+		fatal("--max-concurrent-queries must not be negative", "value", *maxConcurrentQueries)
+	}
+	if *maxFetchBytes <= 0 {
+		fatal("--max-fetch-bytes must be positive", "value", *maxFetchBytes)
+	}
+	if *shutdownTimeout <= 0 {
+		fatal("--shutdown-timeout must be positive", "value", *shutdownTimeout)
+	}
+	if !urbis.IsValidBlockSize(*defaultBlockSize) {
+		fatal("--default-block-size must be 0 or a power of two", "value", *defaultBlockSize, "min", urbis.MinBlockSize, "max", urbis.MaxBlockSize)
+	}
 
 	// Print banner
 	fmt.Println("╔═══════════════════════════════════════════════════════════════╗")
@@ -33,73 +110,343 @@ func main() {
 	fmt.Println("║           Disk-Aware GIS Indexing via gRPC                    ║")
 	fmt.Println("╚═══════════════════════════════════════════════════════════════╝")
 	fmt.Printf("\nLibrary version: %s\n", urbis.Version())
+	fmt.Printf("Server version: %s\n", version)
 	fmt.Printf("Server port: %d\n\n", *port)
 
 	// Create listener
-	addr := fmt.Sprintf(":%d", *port)
+	addr := net.JoinHostPort(*host, strconv.Itoa(*port))
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
-		log.Fatalf("Failed to listen on %s: %v", addr, err)
+		fatal("Failed to listen", "addr", addr, "error", err)
+	}
+
+	var apiKeys map[string][]string
+	if *apiKeysFile != "" {
+		apiKeys, err = service.LoadAPIKeys(*apiKeysFile)
+		if err != nil {
+			fatal("Failed to load API keys", "error", err)
+		}
+		slog.Info("API keys required", "keys", len(apiKeys))
+	}
+
+	// Create Urbis service and its metrics
+	urbisServer := service.NewUrbisServer(
+		service.WithAPIKeys(apiKeys),
+		service.WithStateDir(*stateDir),
+		service.WithQueryLimit(*maxConcurrentQueries, *queryQueueTimeout),
+		service.WithQueryTimeout(*queryTimeout),
+		service.WithFetchHosts(strings.Split(*allowedFetchHosts, ","), *maxFetchBytes),
+		service.WithServerInfo(version, *enableReflection),
+		service.WithDefaultConfig(defaultIndexConfig(flag.CommandLine, *defaultBlockSize, *defaultPageCapacity, *defaultCacheSize, *defaultQuadtree)),
+	)
+	if err := urbisServer.RestoreState(); err != nil {
+		fatal("Failed to restore state", "state_dir", *stateDir, "error", err)
 	}
+	serverMetrics := metrics.New(urbisServer)
 
 	// Create gRPC server with options
-	opts := []grpc.ServerOption{
-		grpc.MaxRecvMsgSize(100 * 1024 * 1024), // 100MB max message size
-		grpc.MaxSendMsgSize(100 * 1024 * 1024),
+	opts, err := messageSizeOptions(*maxRecvMsgSize, *maxSendMsgSize)
+	if err != nil {
+		fatal("Invalid message size", "error", err)
+	}
+	unary := []grpc.UnaryServerInterceptor{
+		logging.UnaryServerInterceptor(logger),
+		serverMetrics.UnaryServerInterceptor(),
+		urbisServer.UnaryDrainInterceptor(),
+		urbisServer.UnaryAuthInterceptor(),
+		urbisServer.UnaryValidationInterceptor(),
+	}
+
+	// Trace RPCs only when a collector is configured, so there is no cost otherwise
+	var tracer *tracing.Tracer
+	if *otelEndpoint != "" {
+		tracer, err = tracing.New(context.Background(), *otelEndpoint, urbis.Version())
+		if err != nil {
+			fatal("Failed to set up tracing", "endpoint", *otelEndpoint, "error", err)
+		}
+		opts = append(opts, tracer.ServerOption())
+		unary = append([]grpc.UnaryServerInterceptor{tracing.UnaryServerInterceptor()}, unary...)
+		slog.Info("Exporting traces", "endpoint", *otelEndpoint)
+	}
+
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(
+			logging.StreamServerInterceptor(logger),
+			serverMetrics.StreamServerInterceptor(),
+			urbisServer.StreamDrainInterceptor(),
+			urbisServer.StreamAuthInterceptor(),
+		),
+	)
+
+	// Enable TLS when certificates are configured
+	if *tlsCert != "" || *tlsKey != "" || *clientCA != "" {
+		creds, err := loadTLSCredentials(*tlsCert, *tlsKey, *clientCA)
+		if err != nil {
+			fatal("Failed to load TLS credentials", "error", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+		if *clientCA != "" {
+			slog.Info("Mutual TLS enabled, client certificates required")
+		} else {
+			slog.Info("TLS enabled")
+		}
 	}
 	grpcServer := grpc.NewServer(opts...)
 
 	// Register Urbis service
-	urbisServer := service.NewUrbisServer()
 	pb.RegisterUrbisServiceServer(grpcServer, urbisServer)
 
+	// Register standard health service; reports SERVING once the listener is up
+	healthServer := registerHealth(grpcServer)
+
+	// Serve Prometheus metrics over HTTP
+	var metricsServer *http.Server
+	if *metricsPort != 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", serverMetrics.Handler())
+		metricsServer = &http.Server{
+			Addr:    net.JoinHostPort(*host, strconv.Itoa(*metricsPort)),
+			Handler: mux,
+		}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("Metrics server failed", "error", err)
+			}
+		}()
+		slog.Info("Prometheus metrics available", "addr", metricsServer.Addr+"/metrics")
+	}
+
 	// Enable reflection for grpcurl and other debugging tools
 	if *enableReflection {
 		reflection.Register(grpcServer)
-		log.Println("gRPC reflection enabled")
+		slog.Info("gRPC reflection enabled")
 	}
 
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Remove objects whose TTL has passed until shutdown
+	go urbisServer.RunSweeper(ctx, *sweepInterval)
+
+	// Save changed persistent indexes in the background until shutdown
+	go urbisServer.RunSyncer(ctx, *syncInterval)
+
 	// Handle shutdown signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		sig := <-sigChan
-		log.Printf("\nReceived signal %v, initiating graceful shutdown...", sig)
+		slog.Info("Received signal, initiating graceful shutdown", "signal", sig.String())
 		
+		// Refuse new work and tell load balancers to drain us
+		urbisServer.Drain()
+		healthServer.Shutdown()
+
 		// Give ongoing requests time to complete
-		shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 30*time.Second)
+		shutdownCtx, shutdownCancel := context.WithTimeout(ctx, *shutdownTimeout)
 		defer shutdownCancel()
 
-		// Stop accepting new connections
-		grpcServer.GracefulStop()
-		
-		select {
-		case <-shutdownCtx.Done():
-			log.Println("Shutdown timeout, forcing stop")
-			grpcServer.Stop()
-		default:
+		if !gracefulStop(shutdownCtx, grpcServer) {
+			slog.Warn("Shutdown timeout exceeded, forced stop", "timeout", *shutdownTimeout)
 		}
-		
+
+		// Save what the background syncer has not, now that requests are done
+		if *syncInterval > 0 {
+			slog.Info("Flushed pending writes", "indexes", urbisServer.FlushAll())
+		}
+
+		// Free native memory now rather than whenever the GC gets to it
+		slog.Info("Closed indexes", "count", urbisServer.CloseAll())
+
+		if metricsServer != nil {
+			metricsServer.Shutdown(shutdownCtx)
+		}
+		if tracer != nil {
+			if err := tracer.Shutdown(shutdownCtx); err != nil {
+				slog.Warn("Failed to flush traces", "error", err)
+			}
+		}
+
 		cancel()
 	}()
 
 	// Start server
-	log.Printf("Urbis gRPC server listening on %s", addr)
-	log.Println("Press Ctrl+C to stop")
+	slog.Info("Urbis gRPC server listening", "addr", addr)
+	fmt.Println("Press Ctrl+C to stop")
 	fmt.Println()
 	
 	printUsageExamples(*port)
 
+	setServingStatus(healthServer, healthpb.HealthCheckResponse_SERVING)
+
 	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve: %v", err)
+		fatal("Failed to serve", "error", err)
+	}
+
+	slog.Info("Server stopped")
+}
+
+// fatal logs msg at error level and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// applyEnv sets each flag not given on the command line from its
+// environment variable, if present. Names are upper-cased with dashes
+// turned into underscores.
+func applyEnv(fs *flag.FlagSet, lookup func(string) (string, bool)) error {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	var errs []error
+	fs.VisitAll(func(f *flag.Flag) {
+		if explicit[f.Name] {
+			return
+		}
+		name := envName(f.Name)
+		value, ok := lookup(name)
+		if !ok {
+			return
+		}
+		if err := fs.Set(f.Name, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: %w", name, value, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// defaultIndexConfig returns the config for service.WithDefaultConfig from
+// the --default-* flags, or nil when none of them was set on the command
+// line or in the environment, so requests keep the library defaults. A
+// size left 0 takes the library default.
+func defaultIndexConfig(fs *flag.FlagSet, blockSize, pageCapacity, cacheSize uint64, quadtree bool) *pb.Config {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if strings.HasPrefix(f.Name, "default-") {
+			set = true
+		}
+	})
+	if !set {
+		return nil
+	}
+
+	lib := urbis.DefaultConfig()
+	c := &pb.Config{
+		BlockSize:      lib.BlockSize,
+		PageCapacity:   lib.PageCapacity,
+		CacheSize:      lib.CacheSize,
+		EnableQuadtree: quadtree,
+	}
+	if blockSize != 0 {
+		c.BlockSize = blockSize
+	}
+	if pageCapacity != 0 {
+		c.PageCapacity = pageCapacity
+	}
+	if cacheSize != 0 {
+		c.CacheSize = cacheSize
+	}
+	return c
+}
+
+// envName returns the environment variable for a flag
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// registerHealth registers the standard health service on server. The server
+// as a whole and the Urbis service report NOT_SERVING until set otherwise.
+func registerHealth(server *grpc.Server) *health.Server {
+	healthServer := health.NewServer()
+	setServingStatus(healthServer, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	return healthServer
+}
+
+// setServingStatus reports status for the server as a whole and for the
+// Urbis service
+func setServingStatus(healthServer *health.Server, status healthpb.HealthCheckResponse_ServingStatus) {
+	healthServer.SetServingStatus("", status)
+	healthServer.SetServingStatus(pb.UrbisService_ServiceDesc.ServiceName, status)
+}
+
+// gracefulStop stops the server once in-flight RPCs finish. If ctx expires
+// first the server is stopped forcibly, cancelling the remaining RPCs, and
+// false is returned.
+func gracefulStop(ctx context.Context, server *grpc.Server) bool {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		server.Stop()
+		<-done
+		return false
+	}
+}
+
+// messageSizeOptions converts the message size limits (in MB) into server
+// options. Limits must be positive; very large limits are allowed but logged.
+func messageSizeOptions(recvMB, sendMB int) ([]grpc.ServerOption, error) {
+	if recvMB <= 0 {
+		return nil, fmt.Errorf("--max-recv-msg-size must be positive, got %d", recvMB)
+	}
+	if sendMB <= 0 {
+		return nil, fmt.Errorf("--max-send-msg-size must be positive, got %d", sendMB)
+	}
+	if recvMB > maxSaneMsgSizeMB {
+		slog.Warn("--max-recv-msg-size is unusually large", "mb", recvMB)
+	}
+	if sendMB > maxSaneMsgSizeMB {
+		slog.Warn("--max-send-msg-size is unusually large", "mb", sendMB)
+	}
+
+	return []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(recvMB * 1024 * 1024),
+		grpc.MaxSendMsgSize(sendMB * 1024 * 1024),
+	}, nil
+}
+
+// loadTLSCredentials builds server transport credentials from PEM files.
+// When caFile is set, clients must present a certificate signed by that CA.
+func loadTLSCredentials(certFile, keyFile, caFile string) (credentials.TransportCredentials, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both --tls-cert and --tls-key are required")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load key pair: %w", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
 	}
 
-	log.Println("Server stopped")
+	return credentials.NewTLS(config), nil
 }
 
 func printUsageExamples(port int) {
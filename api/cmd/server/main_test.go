@@ -0,0 +1,436 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestMessageSizeOptionsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		recvMB  int
+		sendMB  int
+		wantErr bool
+	}{
+		{"defaults", 100, 100, false},
+		{"large", 4096, 4096, false},
+		{"zero recv", 0, 100, true},
+		{"negative send", 100, -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := messageSizeOptions(tt.recvMB, tt.sendMB)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("messageSizeOptions(%d, %d) error = %v, wantErr %v", tt.recvMB, tt.sendMB, err, tt.wantErr)
+			}
+			if !tt.wantErr && len(opts) != 2 {
+				t.Fatalf("got %d options, want 2", len(opts))
+			}
+		})
+	}
+}
+
+func TestApplyEnvPrecedence(t *testing.T) {
+	env := map[string]string{
+		"URBIS_PORT":       "6000",
+		"URBIS_HOST":       "127.0.0.1",
+		"URBIS_REFLECTION": "false",
+		"URBIS_LOG_LEVEL":  "debug",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	port := fs.Int("port", 50051, "")
+	host := fs.String("host", "", "")
+	reflection := fs.Bool("reflection", true, "")
+	logLevel := fs.String("log-level", "info", "")
+	metricsPort := fs.Int("metrics-port", 9090, "")
+	if err := fs.Parse([]string{"--port", "7000"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyEnv(fs, lookup); err != nil {
+		t.Fatal(err)
+	}
+
+	// Flag beats environment, environment beats default
+	if *port != 7000 {
+		t.Errorf("port = %d, want the flag value 7000", *port)
+	}
+	if *host != "127.0.0.1" || *reflection || *logLevel != "debug" {
+		t.Errorf("host %q, reflection %v, log level %q; want the environment values", *host, *reflection, *logLevel)
+	}
+	if *metricsPort != 9090 {
+		t.Errorf("metrics port = %d, want the default 9090", *metricsPort)
+	}
+
+	env["URBIS_METRICS_PORT"] = "many"
+	if err := applyEnv(fs, lookup); err == nil || !strings.Contains(err.Error(), "URBIS_METRICS_PORT") {
+		t.Errorf("invalid value: err = %v, want one naming URBIS_METRICS_PORT", err)
+	}
+}
+
+func TestDefaultIndexConfig(t *testing.T) {
+	env := map[string]string{}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	parse := func(args ...string) *pb.Config {
+		t.Helper()
+		fs := flag.NewFlagSet("server", flag.ContinueOnError)
+		fs.Int("port", 50051, "")
+		blockSize := fs.Uint64("default-block-size", 0, "")
+		pageCapacity := fs.Uint64("default-page-capacity", 0, "")
+		cacheSize := fs.Uint64("default-cache-size", 0, "")
+		quadtree := fs.Bool("default-quadtree", true, "")
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		if err := applyEnv(fs, lookup); err != nil {
+			t.Fatal(err)
+		}
+		return defaultIndexConfig(fs, *blockSize, *pageCapacity, *cacheSize, *quadtree)
+	}
+
+	// Without a --default-* flag requests keep the library defaults
+	if c := parse("--port", "7000"); c != nil {
+		t.Errorf("no default flags set: config = %v, want none", c)
+	}
+
+	lib := urbis.DefaultConfig()
+	c := parse("--default-quadtree=false")
+	if c == nil || c.BlockSize != lib.BlockSize || c.PageCapacity != lib.PageCapacity || c.CacheSize != lib.CacheSize || c.EnableQuadtree {
+		t.Errorf("--default-quadtree=false: config = %v, want the library sizes without the quadtree", c)
+	}
+
+	env["URBIS_DEFAULT_CACHE_SIZE"] = "512"
+	c = parse("--default-page-capacity", "0")
+	if c == nil || c.CacheSize != 512 || c.PageCapacity != lib.PageCapacity || !c.EnableQuadtree {
+		t.Errorf("cache size from the environment: config = %v, want cache 512 and the library page capacity", c)
+	}
+}
+
+func TestServerEnforcesMaxRecvMsgSize(t *testing.T) {
+	opts, err := messageSizeOptions(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("small request failed: %v", err)
+	}
+
+	big := &healthpb.HealthCheckRequest{Service: strings.Repeat("x", 2*1024*1024)}
+	_, err = client.Check(context.Background(), big)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("oversized request: got %v, want ResourceExhausted", err)
+	}
+}
+
+func TestHealthFollowsServerLifecycle(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthServer := registerHealth(server)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	check := func(want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		for _, service := range []string{"", pb.UrbisService_ServiceDesc.ServiceName} {
+			resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+			if err != nil {
+				t.Fatalf("Check(%q): %v", service, err)
+			}
+			if resp.Status != want {
+				t.Errorf("Check(%q) = %v, want %v", service, resp.Status, want)
+			}
+		}
+	}
+
+	check(healthpb.HealthCheckResponse_NOT_SERVING)
+	setServingStatus(healthServer, healthpb.HealthCheckResponse_SERVING)
+	check(healthpb.HealthCheckResponse_SERVING)
+
+	// Draining flips both back, and later updates cannot undo it
+	healthServer.Shutdown()
+	check(healthpb.HealthCheckResponse_NOT_SERVING)
+	setServingStatus(healthServer, healthpb.HealthCheckResponse_SERVING)
+	check(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "other"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown service: got %v, want NotFound", err)
+	}
+}
+
+// startBlockingServer serves the health service behind an interceptor that
+// signals started and then holds each Check call for delay
+func startBlockingServer(t *testing.T, delay time.Duration, started chan<- struct{}) (*grpc.Server, healthpb.HealthClient) {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		started <- struct{}{}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return server, healthpb.NewHealthClient(conn)
+}
+
+func TestGracefulStopWaitsForInFlightRequest(t *testing.T) {
+	started := make(chan struct{}, 1)
+	server, client := startBlockingServer(t, 100*time.Millisecond, started)
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		errc <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if !gracefulStop(ctx, server) {
+		t.Error("gracefulStop forced a stop within the timeout window")
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("in-flight request failed: %v", err)
+	}
+}
+
+func TestGracefulStopForcesAfterTimeout(t *testing.T) {
+	started := make(chan struct{}, 1)
+	server, client := startBlockingServer(t, time.Minute, started)
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		errc <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if gracefulStop(ctx, server) {
+		t.Error("gracefulStop reported a clean stop for a request exceeding the timeout")
+	}
+	if err := <-errc; err == nil {
+		t.Error("request outliving the shutdown timeout succeeded")
+	}
+}
+
+// testCert is a certificate and key issued for TLS tests
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// issueCert creates a certificate for name signed by parent, or a
+// self-signed CA when parent is nil
+func issueCert(t *testing.T, name string, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// writeFile writes data to name in dir and returns its path
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// checkOverTLS serves the health service with creds and runs one Check
+// from a client using clientTLS
+func checkOverTLS(t *testing.T, creds credentials.TransportCredentials, clientTLS *tls.Config) error {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.Creds(creds))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(credentials.NewTLS(clientTLS)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestLoadTLSCredentials(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueCert(t, "urbis test CA", nil)
+	server := issueCert(t, "urbis.test", ca)
+	client := issueCert(t, "client.test", ca)
+	caPath := writeFile(t, dir, "ca.pem", ca.certPEM)
+	certPath := writeFile(t, dir, "server.pem", server.certPEM)
+	keyPath := writeFile(t, dir, "server.key", server.keyPEM)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientPair, err := tls.X509KeyPair(client.certPEM, client.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Server-side TLS: any client trusting the CA connects
+	creds, err := loadTLSCredentials(certPath, keyPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkOverTLS(t, creds, &tls.Config{RootCAs: roots, ServerName: "urbis.test"}); err != nil {
+		t.Errorf("TLS without a client certificate: %v", err)
+	}
+
+	// Mutual TLS: a client certificate from the CA is required
+	creds, err = loadTLSCredentials(certPath, keyPath, caPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkOverTLS(t, creds, &tls.Config{RootCAs: roots, ServerName: "urbis.test", Certificates: []tls.Certificate{clientPair}}); err != nil {
+		t.Errorf("mutual TLS with a client certificate: %v", err)
+	}
+	if err := checkOverTLS(t, creds, &tls.Config{RootCAs: roots, ServerName: "urbis.test"}); err == nil {
+		t.Error("mutual TLS accepted a client without a certificate")
+	}
+	strangerCert := issueCert(t, "stranger.test", issueCert(t, "other CA", nil))
+	stranger, err := tls.X509KeyPair(strangerCert.certPEM, strangerCert.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkOverTLS(t, creds, &tls.Config{RootCAs: roots, ServerName: "urbis.test", Certificates: []tls.Certificate{stranger}}); err == nil {
+		t.Error("mutual TLS accepted a client certificate from another CA")
+	}
+
+	// Incomplete or unusable configuration fails up front
+	if _, err := loadTLSCredentials(certPath, "", ""); err == nil {
+		t.Error("certificate without a key loaded")
+	}
+	if _, err := loadTLSCredentials(certPath, keyPath, writeFile(t, dir, "empty.pem", []byte("not a certificate"))); err == nil {
+		t.Error("client CA file without certificates loaded")
+	}
+	if _, err := loadTLSCredentials(certPath, writeFile(t, dir, "wrong.key", client.keyPEM), ""); err == nil {
+		t.Error("key that does not match the certificate loaded")
+	}
+}
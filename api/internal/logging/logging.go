@@ -0,0 +1,143 @@
+// Package logging provides structured, leveled logging for the Urbis gRPC
+// server, including a per-RPC access log tagged with request IDs.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDKey is the metadata key carrying the request ID. A client may
+// supply its own; otherwise one is generated. Either way it is echoed back
+// in the response header.
+const RequestIDKey = "x-request-id"
+
+// New creates a logger writing to w. level is one of debug, info, warn or
+// error; format is text or json.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("invalid log format %q (want text or json)", format)
+}
+
+type requestIDContextKey struct{}
+
+// RequestID returns the request ID assigned to the RPC handling ctx, or ""
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// UnaryServerInterceptor logs each unary RPC with its request ID, method,
+// index ID, duration and outcome
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, id := withRequestID(ctx)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logRPC(ctx, logger, id, info.FullMethod, indexID(req), start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs each streaming RPC. The index ID is not
+// known up front for streams, so it is omitted.
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, id := withRequestID(ss.Context())
+		start := time.Now()
+		err := handler(srv, &requestIDStream{ServerStream: ss, ctx: ctx})
+		logRPC(ctx, logger, id, info.FullMethod, "", start, err)
+		return err
+	}
+}
+
+// requestIDStream exposes the request ID to stream handlers via Context
+type requestIDStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestIDStream) Context() context.Context {
+	return s.ctx
+}
+
+// withRequestID takes the request ID from incoming metadata, generating one
+// if absent, and attaches it to ctx and the response header
+func withRequestID(ctx context.Context) (context.Context, string) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(RequestIDKey); len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	if id == "" {
+		id = newRequestID()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, id))
+	return context.WithValue(ctx, requestIDContextKey{}, id), id
+}
+
+func newRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// indexID extracts the index_id field from a request, if it has one
+func indexID(req interface{}) string {
+	if r, ok := req.(interface{ GetIndexId() string }); ok {
+		return r.GetIndexId()
+	}
+	return ""
+}
+
+// logRPC writes the access log line. Server-side failures are logged at
+// error level, client errors at warn and successes at info.
+func logRPC(ctx context.Context, logger *slog.Logger, id, method, index string, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("request_id", id),
+		slog.String("method", method),
+		slog.Duration("duration", time.Since(start)),
+		slog.String("code", code.String()),
+	}
+	if index != "" {
+		attrs = append(attrs, slog.String("index_id", index))
+	}
+
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+
+	logger.LogAttrs(ctx, level, "rpc", attrs...)
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/urbis/api/pkg/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewValidatesFlags(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := New(&buf, "verbose", "json"); err == nil {
+		t.Error("unknown level accepted")
+	}
+	if _, err := New(&buf, "info", "xml"); err == nil {
+		t.Error("unknown format accepted")
+	}
+
+	logger, err := New(&buf, "warn", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("dropped")
+	if buf.Len() != 0 {
+		t.Errorf("info logged at warn level: %s", buf.String())
+	}
+}
+
+func TestUnaryInterceptorLogsRequest(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intercept := UnaryServerInterceptor(logger)
+	info := &grpc.UnaryServerInfo{FullMethod: "/urbis.UrbisService/Build"}
+
+	var seen string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		seen = RequestID(ctx)
+		return nil, status.Error(codes.NotFound, "index \"city\" not found")
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDKey, "abc123"))
+	intercept(ctx, &pb.BuildRequest{IndexId: "city"}, info, handler)
+
+	if seen != "abc123" {
+		t.Errorf("handler saw request ID %q, want the client's abc123", seen)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log line is not JSON: %v: %s", err, buf.String())
+	}
+	want := map[string]any{
+		"level":      "WARN",
+		"request_id": "abc123",
+		"method":     "/urbis.UrbisService/Build",
+		"index_id":   "city",
+		"code":       "NotFound",
+		"error":      "index \"city\" not found",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s = %v, want %v", k, entry[k], v)
+		}
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Error("duration missing")
+	}
+
+	buf.Reset()
+	intercept(context.Background(), &pb.BuildRequest{}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		seen = RequestID(ctx)
+		return nil, nil
+	})
+	if len(seen) != 16 {
+		t.Errorf("generated request ID %q, want 16 hex characters", seen)
+	}
+}
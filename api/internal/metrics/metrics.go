@@ -0,0 +1,117 @@
+// Package metrics exposes Prometheus metrics for the Urbis gRPC server.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// IndexSource reports the current state of the indexes held by the server
+type IndexSource interface {
+	IndexCount() int
+	TotalObjects() uint64
+	InFlightQueries() map[string]int64
+}
+
+// Metrics holds the Prometheus collectors for the server
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+}
+
+// New creates the server metrics, reading index gauges from source
+func New(source IndexSource) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "urbis",
+			Name:      "grpc_requests_total",
+			Help:      "Total number of gRPC requests by method and status code.",
+		}, []string{"method", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "urbis",
+			Name:      "grpc_request_duration_seconds",
+			Help:      "gRPC request latency by method.",
+			Buckets:   []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.latency,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "urbis",
+			Name:      "indexes",
+			Help:      "Current number of indexes.",
+		}, func() float64 { return float64(source.IndexCount()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "urbis",
+			Name:      "objects",
+			Help:      "Total number of objects across all indexes.",
+		}, func() float64 { return float64(source.TotalObjects()) }),
+		inFlightCollector{
+			desc: prometheus.NewDesc("urbis_inflight_queries",
+				"Queries currently running against each index.", []string{"index_id"}, nil),
+			source: source,
+		},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// inFlightCollector reports the in-flight query count of each index
+type inFlightCollector struct {
+	desc   *prometheus.Desc
+	source IndexSource
+}
+
+func (c inFlightCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c inFlightCollector) Collect(ch chan<- prometheus.Metric) {
+	for id, n := range c.source.InFlightQueries() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), id)
+	}
+}
+
+// Handler returns the HTTP handler serving the metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// UnaryServerInterceptor records request counts and latency for unary RPCs
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records request counts and latency for streaming RPCs
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observe(info.FullMethod, start, err)
+		return err
+	}
+}
+
+// observe records a single completed RPC
+func (m *Metrics) observe(method string, start time.Time, err error) {
+	m.requests.WithLabelValues(method, status.Code(err).String()).Inc()
+	m.latency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
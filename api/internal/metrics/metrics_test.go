@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeSource struct{}
+
+func (fakeSource) IndexCount() int                   { return 3 }
+func (fakeSource) TotalObjects() uint64              { return 42 }
+func (fakeSource) InFlightQueries() map[string]int64 { return map[string]int64{"roads": 2} }
+
+// family returns the gathered metric family called name
+func family(t *testing.T, m *Metrics, name string) *dto.MetricFamily {
+	t.Helper()
+	families, err := m.registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() == name {
+			return f
+		}
+	}
+	t.Fatalf("no metric family %s", name)
+	return nil
+}
+
+// labels returns the label values of a metric by name
+func labels(metric *dto.Metric) map[string]string {
+	out := make(map[string]string)
+	for _, l := range metric.GetLabel() {
+		out[l.GetName()] = l.GetValue()
+	}
+	return out
+}
+
+func TestUnaryInterceptorRecords(t *testing.T) {
+	m := New(fakeSource{})
+	intercept := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/urbis.UrbisService/QueryRange"}
+
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return "done", nil }
+	fail := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "index not found")
+	}
+	for i := 0; i < 2; i++ {
+		if resp, err := intercept(context.Background(), nil, info, ok); resp != "done" || err != nil {
+			t.Fatalf("interceptor changed the response: %v, %v", resp, err)
+		}
+	}
+	if _, err := intercept(context.Background(), nil, info, fail); status.Code(err) != codes.NotFound {
+		t.Fatalf("interceptor changed the error: %v", err)
+	}
+
+	counts := make(map[string]float64)
+	for _, metric := range family(t, m, "urbis_grpc_requests_total").GetMetric() {
+		l := labels(metric)
+		if l["method"] != info.FullMethod {
+			t.Errorf("method label = %q", l["method"])
+		}
+		counts[l["code"]] = metric.GetCounter().GetValue()
+	}
+	if counts["OK"] != 2 || counts["NotFound"] != 1 {
+		t.Errorf("request counts by code = %v, want OK 2 and NotFound 1", counts)
+	}
+
+	latency := family(t, m, "urbis_grpc_request_duration_seconds").GetMetric()
+	if len(latency) != 1 || latency[0].GetHistogram().GetSampleCount() != 3 {
+		t.Errorf("latency histogram = %v, want 3 samples for one method", latency)
+	}
+}
+
+func TestStreamInterceptorRecords(t *testing.T) {
+	m := New(fakeSource{})
+	info := &grpc.StreamServerInfo{FullMethod: "/urbis.UrbisService/StreamInsert"}
+	want := errors.New("stream broke")
+
+	err := m.StreamServerInterceptor()(nil, nil, info, func(srv interface{}, ss grpc.ServerStream) error { return want })
+	if err != want {
+		t.Fatalf("interceptor changed the error: %v", err)
+	}
+	requests := family(t, m, "urbis_grpc_requests_total").GetMetric()
+	if len(requests) != 1 || labels(requests[0])["code"] != "Unknown" || requests[0].GetCounter().GetValue() != 1 {
+		t.Errorf("stream requests = %v, want one with code Unknown", requests)
+	}
+	if n := family(t, m, "urbis_grpc_request_duration_seconds").GetMetric()[0].GetHistogram().GetSampleCount(); n != 1 {
+		t.Errorf("stream latency samples = %d, want 1", n)
+	}
+}
+
+func TestHandlerServesGauges(t *testing.T) {
+	m := New(fakeSource{})
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	for _, line := range []string{
+		"urbis_indexes 3",
+		"urbis_objects 42",
+		`urbis_inflight_queries{index_id="roads"} 2`,
+	} {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("metrics output lacks %q", line)
+		}
+	}
+}
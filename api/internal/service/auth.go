@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/urbis/api/pkg/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyHeader is the metadata key clients send their API key in
+const APIKeyHeader = "x-api-key"
+
+// WithAPIKeys restricts UrbisService calls to clients presenting one of the
+// keys in the x-api-key metadata. Each key maps to the index-ID prefixes it
+// may use; the prefix "" allows every index and the calls that span all of
+// them, such as GetResourceStats. ListIndexes lists only the indexes a key
+// may use. Other services, such as health checks, need no key. With no
+// keys, every call is allowed.
+func WithAPIKeys(keys map[string][]string) Option {
+	return func(s *UrbisServer) {
+		if len(keys) > 0 {
+			s.apiKeys = keys
+		}
+	}
+}
+
+// LoadAPIKeys reads a WithAPIKeys mapping from a JSON file of the form
+// {"key": ["team-a/", "shared-"]}
+func LoadAPIKeys(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys map[string][]string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for key, prefixes := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("%s: empty API key", path)
+		}
+		if len(prefixes) == 0 {
+			return nil, fmt.Errorf("%s: an API key allows no indexes", path)
+		}
+	}
+	return keys, nil
+}
+
+// authorize returns the index-ID prefixes the caller's API key allows, or
+// nil with no error when auth is off or the method is not UrbisService's
+func (s *UrbisServer) authorize(ctx context.Context, fullMethod string) ([]string, error) {
+	if s.apiKeys == nil || !strings.HasPrefix(fullMethod, "/"+pb.UrbisService_ServiceDesc.ServiceName+"/") {
+		return nil, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(APIKeyHeader)
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing API key")
+	}
+	prefixes, ok := s.apiKeys[values[0]]
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unknown API key")
+	}
+	if len(prefixes) == 0 {
+		return nil, status.Error(codes.PermissionDenied, "API key allows no indexes")
+	}
+	return prefixes, nil
+}
+
+// allowsIndex reports whether an index ID has one of the prefixes
+func allowsIndex(prefixes []string, indexID string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(indexID, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAccess rejects a request for an index outside the prefixes. An empty
+// index_id is left for the handler to reject. A request with index_ids
+// needs every one of them allowed. Requests without an index_id
+// act on the whole server and need the "" prefix, except ListIndexes, whose
+// response is filtered instead, and GetServerInfo, which any key may call.
+func checkAccess(prefixes []string, req interface{}) error {
+	if r, ok := req.(interface{ GetIndexIds() []string }); ok {
+		for _, id := range r.GetIndexIds() {
+			if id != "" && !allowsIndex(prefixes, id) {
+				return status.Errorf(codes.PermissionDenied, "API key does not allow index %q", id)
+			}
+		}
+		return nil
+	}
+	r, ok := req.(interface{ GetIndexId() string })
+	if !ok {
+		switch req.(type) {
+		case *pb.ListIndexesRequest, *pb.ServerInfoRequest:
+			return nil
+		}
+		if slices.Contains(prefixes, "") {
+			return nil
+		}
+		return status.Error(codes.PermissionDenied, "API key is limited to some indexes")
+	}
+	if id := r.GetIndexId(); id != "" && !allowsIndex(prefixes, id) {
+		return status.Errorf(codes.PermissionDenied, "API key does not allow index %q", id)
+	}
+	return nil
+}
+
+// UnaryAuthInterceptor enforces WithAPIKeys on unary calls
+func (s *UrbisServer) UnaryAuthInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		prefixes, err := s.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		if prefixes == nil {
+			return handler(ctx, req)
+		}
+		if err := checkAccess(prefixes, req); err != nil {
+			return nil, err
+		}
+
+		resp, err := handler(ctx, req)
+		if list, ok := resp.(*pb.ListIndexesResponse); ok && err == nil {
+			allowed := list.IndexIds[:0]
+			for _, id := range list.IndexIds {
+				if allowsIndex(prefixes, id) {
+					allowed = append(allowed, id)
+				}
+			}
+			list.IndexIds = allowed
+		}
+		return resp, err
+	}
+}
+
+// StreamAuthInterceptor enforces WithAPIKeys on streaming calls, checking
+// every message the client sends
+func (s *UrbisServer) StreamAuthInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		prefixes, err := s.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		if prefixes == nil {
+			return handler(srv, ss)
+		}
+		return handler(srv, &authStream{ServerStream: ss, prefixes: prefixes})
+	}
+}
+
+// authStream checks the index_id of each received message
+type authStream struct {
+	grpc.ServerStream
+	prefixes []string
+}
+
+func (a *authStream) RecvMsg(m interface{}) error {
+	if err := a.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkAccess(a.prefixes, m)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/urbis/api/pkg/urbis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// dataFileExt is the extension of the files Save writes into a data_path
+const dataFileExt = ".urbis"
+
+// dataFile returns the file Save writes a persistent index to when no path
+// is given: one named after the escaped index ID inside data_path, so a file
+// left by an earlier index in the same directory is never taken for this
+// one's. It is empty when the config does not persist to a data_path.
+func dataFile(indexID string, config *urbis.Config) string {
+	if config == nil || !config.Persist || config.DataPath == "" {
+		return ""
+	}
+	return filepath.Join(config.DataPath, url.PathEscape(indexID)+dataFileExt)
+}
+
+// checkDataPath makes sure a persistent config's data_path is a directory,
+// or can become one, and that no other index persists to it
+func (s *UrbisServer) checkDataPath(indexID string, config *urbis.Config) error {
+	if config == nil || !config.Persist || config.DataPath == "" {
+		return nil
+	}
+	dir, err := filepath.Abs(config.DataPath)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "data_path %q: %v", config.DataPath, err)
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		return status.Errorf(codes.InvalidArgument, "data_path %q is not a directory", config.DataPath)
+	}
+
+	var owner string
+	s.configs.Range(func(key, value interface{}) bool {
+		other := value.(*urbis.Config)
+		if key.(string) == indexID || !other.Persist || other.DataPath == "" {
+			return true
+		}
+		if otherDir, err := filepath.Abs(other.DataPath); err == nil && otherDir == dir {
+			owner = key.(string)
+			return false
+		}
+		return true
+	})
+	if owner != "" {
+		return status.Errorf(codes.AlreadyExists, "data_path %q is already used by index %q", config.DataPath, owner)
+	}
+	return nil
+}
+
+// savePath resolves where Save writes an index: the requested path, or the
+// index's data file when none is given. A path that is another index's data
+// file is refused, since saving there would overwrite that index.
+func (s *UrbisServer) savePath(indexID, path string) (string, error) {
+	if path == "" {
+		if v, ok := s.configs.Load(indexID); ok {
+			path = dataFile(indexID, v.(*urbis.Config))
+		}
+		if path == "" {
+			return "", status.Error(codes.InvalidArgument, "path is required unless the index persists to a data_path")
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return "", status.Errorf(codes.Internal, "failed to create data_path: %v", err)
+		}
+		return path, nil
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "path %q: %v", path, err)
+	}
+	var owner string
+	s.configs.Range(func(key, value interface{}) bool {
+		if key.(string) == indexID {
+			return true
+		}
+		if file := dataFile(key.(string), value.(*urbis.Config)); file != "" {
+			if other, err := filepath.Abs(file); err == nil && other == abs {
+				owner = key.(string)
+				return false
+			}
+		}
+		return true
+	})
+	if owner != "" {
+		return "", status.Errorf(codes.InvalidArgument, "path %q is the data file of index %q", path, owner)
+	}
+	return path, nil
+}
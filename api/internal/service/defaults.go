@@ -0,0 +1,38 @@
+package service
+
+import (
+	"github.com/urbis/api/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// WithDefaultConfig sets the config CreateIndex and ReloadIndex use when a
+// request has none. A request that has one takes block_size, page_capacity
+// and cache_size from it when they are zero; its other fields, including
+// enable_quadtree, are used as sent. A nil c leaves requests as they are.
+func WithDefaultConfig(c *pb.Config) Option {
+	return func(s *UrbisServer) {
+		s.defaults = c
+	}
+}
+
+// withDefaults merges the server's default config into a request's
+func (s *UrbisServer) withDefaults(c *pb.Config) *pb.Config {
+	if s.defaults == nil {
+		return c
+	}
+	if c == nil {
+		return proto.Clone(s.defaults).(*pb.Config)
+	}
+
+	c = proto.Clone(c).(*pb.Config)
+	if c.BlockSize == 0 {
+		c.BlockSize = s.defaults.BlockSize
+	}
+	if c.PageCapacity == 0 {
+		c.PageCapacity = s.defaults.PageCapacity
+	}
+	if c.CacheSize == 0 {
+		c.CacheSize = s.defaults.CacheSize
+	}
+	return c
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+)
+
+// storeConfig remembers the config an index was created with. A nil config
+// is recorded as the library defaults it stands for.
+func (s *UrbisServer) storeConfig(indexID string, config *urbis.Config) {
+	if config == nil {
+		defaults := urbis.DefaultConfig()
+		config = &defaults
+	}
+	s.configs.Store(indexID, config)
+}
+
+// configMatches reports whether an index was created with config, reading
+// a nil config as the defaults as storeConfig does. Indexes loaded from a
+// data file have no recorded config and never match.
+func (s *UrbisServer) configMatches(indexID string, config *urbis.Config) bool {
+	v, ok := s.configs.Load(indexID)
+	if !ok {
+		return false
+	}
+	if config == nil {
+		defaults := urbis.DefaultConfig()
+		config = &defaults
+	}
+	return reflect.DeepEqual(v.(*urbis.Config), config)
+}
+
+// DescribeIndex gathers an index's creation config, build state, count,
+// bounds and statistics in one response
+func (s *UrbisServer) DescribeIndex(ctx context.Context, req *pb.DescribeIndexRequest) (*pb.DescribeIndexResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	stats := idx.GetStats()
+	resp := &pb.DescribeIndexResponse{
+		IndexId: req.IndexId,
+		Built:   idx.IsBuilt(),
+		Count:   stats.TotalObjects,
+		Stats:   convertToPbStats(stats),
+
+		ReadOnly:       idx.ReadOnly(),
+		PendingChanges: idx.PendingChanges(),
+	}
+	if stats.TotalObjects > 0 {
+		resp.Bounds = convertToPbMBR(stats.Bounds)
+	}
+	if v, ok := s.configs.Load(req.IndexId); ok {
+		resp.Config = convertToPbConfig(v.(*urbis.Config))
+	}
+	return resp, nil
+}
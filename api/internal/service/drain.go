@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/urbis/api/pkg/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Drain marks the server as shutting down. New UrbisService calls are
+// rejected with codes.Unavailable; calls already in flight are unaffected.
+func (s *UrbisServer) Drain() {
+	s.draining.Store(true)
+}
+
+// Draining reports whether Drain has been called
+func (s *UrbisServer) Draining() bool {
+	return s.draining.Load()
+}
+
+// rejectWhileDraining returns an Unavailable error for UrbisService methods
+// once the server is draining. Other services, such as health checks, keep
+// answering so load balancers can observe the shutdown.
+func (s *UrbisServer) rejectWhileDraining(fullMethod string) error {
+	if !s.Draining() || !strings.HasPrefix(fullMethod, "/"+pb.UrbisService_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+	return status.Error(codes.Unavailable, "server is shutting down")
+}
+
+// UnaryDrainInterceptor rejects new unary calls while the server is draining
+func (s *UrbisServer) UnaryDrainInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := s.rejectWhileDraining(info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamDrainInterceptor rejects new streaming calls while the server is draining
+func (s *UrbisServer) StreamDrainInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := s.rejectWhileDraining(info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
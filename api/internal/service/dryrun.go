@@ -0,0 +1,28 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+	"google.golang.org/grpc/status"
+)
+
+// dryRun answers a GeoJSON load with dry_run set. objects_loaded is what a
+// real load would add; count and bounds describe the untouched index.
+func dryRun(idx *urbis.Index, check func() (urbis.LoadResult, []string, error)) (*pb.LoadResponse, error) {
+	result, errs, err := check()
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to check GeoJSON: %v", err)
+	}
+
+	return &pb.LoadResponse{
+		ObjectsLoaded: result.Loaded,
+		Skipped:       result.Skipped,
+		OutOfBounds:   result.OutOfBounds,
+		Errors:        errs,
+		Message:       fmt.Sprintf("Dry run: %d objects would be loaded, %d features have errors", result.Loaded, len(errs)),
+		Count:         idx.Count(),
+		Bounds:        indexBounds(idx),
+	}, nil
+}
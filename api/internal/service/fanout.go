@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// QueryRangeMulti runs one range query against several indexes at once,
+// at most GOMAXPROCS at a time, and returns each index's results with its
+// own timing so a lagging shard stands out. Each index's query takes a slot
+// of that index as QueryRange does. If any index fails, the call fails.
+// Deduplicate compares objects by featureKey, since object IDs are only
+// unique within one index.
+func (s *UrbisServer) QueryRangeMulti(ctx context.Context, req *pb.QueryRangeMultiRequest) (*pb.QueryRangeMultiResponse, error) {
+	if len(req.IndexIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "index_ids is required")
+	}
+	if req.Range == nil {
+		return nil, status.Error(codes.InvalidArgument, "range is required")
+	}
+
+	indexes := make([]*urbis.Index, len(req.IndexIds))
+	seen := make(map[string]bool, len(req.IndexIds))
+	for i, id := range req.IndexIds {
+		if id == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "index_ids[%d] is empty", i)
+		}
+		if seen[id] {
+			return nil, status.Errorf(codes.InvalidArgument, "index %q is listed twice", id)
+		}
+		seen[id] = true
+
+		idx, release, err := s.getIndex(id)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		indexes[i] = idx
+	}
+
+	region := urbis.MBR{MinX: req.Range.MinX, MinY: req.Range.MinY, MaxX: req.Range.MaxX, MaxY: req.Range.MaxY}
+	structure, err := convertStructure(req.Structure)
+	if err != nil {
+		return nil, err
+	}
+	types, err := parseGeomTypes(req.GeomTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	type shard struct {
+		list    *urbis.ObjectList
+		elapsed time.Duration
+		err     error
+	}
+	shards := make([]shard, len(indexes))
+	slots := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	for i, idx := range indexes {
+		wg.Add(1)
+		go func(i int, idx *urbis.Index) {
+			defer wg.Done()
+			// Shards still waiting for a turn when the caller gives up
+			// are not queried
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+			}
+			if err := ctx.Err(); err != nil {
+				shards[i].err = status.FromContextError(err).Err()
+				return
+			}
+
+			began := time.Now()
+			shards[i].list, shards[i].err = runQuery(ctx, s, req.IndexIds[i], func() (*urbis.ObjectList, error) {
+				return idx.QueryRangeUsing(region, structure)
+			})
+			shards[i].elapsed = time.Since(began)
+		}(i, idx)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	resp := &pb.QueryRangeMultiResponse{
+		Results:     make(map[string]*pb.RangeResult, len(shards)),
+		QueryTimeMs: float64(elapsed.Microseconds()) / 1000.0,
+	}
+	found := make(map[string]bool)
+	for i, sh := range shards {
+		id := req.IndexIds[i]
+		if sh.err != nil {
+			st := status.Convert(sh.err)
+			return nil, status.Errorf(st.Code(), "index %q: %s", id, st.Message())
+		}
+
+		objs := types.filter(sh.list.Objects)
+		if req.Deduplicate {
+			all := objs
+			objs = objs[:0]
+			for _, obj := range all {
+				if key := featureKey(obj); !found[key] {
+					found[key] = true
+					objs = append(objs, obj)
+				}
+			}
+		}
+
+		resp.Results[id] = &pb.RangeResult{
+			Objects:     convertToPbResults(objs, req.IncludeVersion),
+			Count:       uint64(len(objs)),
+			QueryStats:  convertToPbQueryStats(sh.list.Stats),
+			Stale:       sh.list.Stale,
+			QueryTimeMs: float64(sh.elapsed.Microseconds()) / 1000.0,
+		}
+		resp.Count += uint64(len(objs))
+	}
+	return resp, nil
+}
+
+// featureKey identifies an object by what it holds rather than by its ID,
+// which two indexes may give to unrelated objects: the geometry type, every
+// coordinate, the Z and M values and the properties. Objects whose
+// geometry was reduced to a centroid and box by a save and load compare by
+// those.
+func featureKey(obj *urbis.SpatialObject) string {
+	return string(appendFeature(nil, obj))
+}
+
+func appendFeature(b []byte, obj *urbis.SpatialObject) []byte {
+	appendFloats := func(vs ...float64) {
+		b = binary.AppendUvarint(b, uint64(len(vs)))
+		for _, v := range vs {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+		}
+	}
+	appendPoints := func(ps []urbis.Point) {
+		b = binary.AppendUvarint(b, uint64(len(ps)))
+		for _, p := range ps {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(p.X))
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(p.Y))
+		}
+	}
+	appendParts := func(parts [][]urbis.Point) {
+		b = binary.AppendUvarint(b, uint64(len(parts)))
+		for _, part := range parts {
+			appendPoints(part)
+		}
+	}
+
+	b = binary.AppendUvarint(b, uint64(obj.Type))
+	b = binary.AppendUvarint(b, uint64(obj.Dims))
+	appendFloats(obj.Centroid.X, obj.Centroid.Y, obj.MBR.MinX, obj.MBR.MinY, obj.MBR.MaxX, obj.MBR.MaxY)
+	if obj.Point != nil {
+		appendPoints([]urbis.Point{*obj.Point})
+	} else {
+		appendPoints(nil)
+	}
+	appendPoints(obj.Line)
+	appendPoints(obj.Polygon)
+	appendPoints(obj.MultiPoint)
+	appendParts(obj.MultiLine)
+	appendParts(obj.MultiPolygon)
+	appendFloats(obj.Z...)
+	appendFloats(obj.M...)
+	b = binary.AppendUvarint(b, uint64(len(obj.Geometries)))
+	for _, member := range obj.Geometries {
+		b = appendFeature(b, member)
+	}
+	b = binary.AppendUvarint(b, uint64(len(obj.Properties)))
+	return append(b, obj.Properties...)
+}
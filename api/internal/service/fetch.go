@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxFetchBytes is the download cap used by WithFetchHosts when none is given
+const DefaultMaxFetchBytes = 256 << 20
+
+// WithFetchHosts allows LoadGeoJSONURL to download from the given hosts.
+// Entries are host names or IP addresses, matched case-insensitively
+// against the URL host on any port. Redirects are followed only to allowed
+// hosts. Downloads larger than maxBytes fail; zero uses DefaultMaxFetchBytes.
+// With no hosts, LoadGeoJSONURL is disabled.
+func WithFetchHosts(hosts []string, maxBytes int64) Option {
+	return func(s *UrbisServer) {
+		s.fetchHosts = make(map[string]bool, len(hosts))
+		for _, h := range hosts {
+			if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+				s.fetchHosts[h] = true
+			}
+		}
+		s.maxFetchBytes = maxBytes
+		if s.maxFetchBytes <= 0 {
+			s.maxFetchBytes = DefaultMaxFetchBytes
+		}
+	}
+}
+
+// LoadGeoJSONURL downloads a GeoJSON document from an allowed host and
+// loads it like LoadGeoJSONString
+func (s *UrbisServer) LoadGeoJSONURL(ctx context.Context, req *pb.LoadGeoJSONURLRequest) (*pb.LoadResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	body, err := s.fetch(ctx, req.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DryRun {
+		return dryRun(idx, func() (urbis.LoadResult, []string, error) {
+			return idx.DryRunGeoJSONStringFrom(body, int(req.SourceCrs))
+		})
+	}
+
+	result, err := idx.LoadGeoJSONStringFrom(body, int(req.SourceCrs))
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to load GeoJSON: %v", err)
+	}
+
+	return &pb.LoadResponse{
+		ObjectsLoaded: result.Loaded,
+		Skipped:       result.Skipped,
+		OutOfBounds:   result.OutOfBounds,
+		Message:       "GeoJSON loaded successfully",
+		Count:         idx.Count(),
+		Bounds:        indexBounds(idx),
+	}, nil
+}
+
+// fetch downloads rawURL into memory, enforcing the host allowlist and size cap
+func (s *UrbisServer) fetch(ctx context.Context, rawURL string) (string, error) {
+	u, err := s.checkFetchURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(r *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			_, err := s.checkFetchURL(r.URL.String())
+			return err
+		},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid url: %v", err)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", status.FromContextError(ctxErr).Err()
+		}
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			if st, ok := status.FromError(urlErr.Err); ok {
+				return "", st.Err()
+			}
+		}
+		return "", status.Errorf(codes.Unavailable, "fetch %s: %v", u.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", status.Errorf(codes.FailedPrecondition, "fetch %s: %s", u.Redacted(), resp.Status)
+	}
+	if resp.ContentLength > s.maxFetchBytes {
+		return "", s.fetchTooLarge(u)
+	}
+
+	var body strings.Builder
+	n, err := io.Copy(&body, io.LimitReader(resp.Body, s.maxFetchBytes+1))
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", status.FromContextError(ctxErr).Err()
+		}
+		return "", status.Errorf(codes.Unavailable, "fetch %s: %v", u.Redacted(), err)
+	}
+	if n > s.maxFetchBytes {
+		return "", s.fetchTooLarge(u)
+	}
+
+	return body.String(), nil
+}
+
+// checkFetchURL parses rawURL and checks it is http(s) on an allowed host
+func (s *UrbisServer) checkFetchURL(rawURL string) (*url.URL, error) {
+	if len(s.fetchHosts) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "URL loading is disabled; start the server with --allowed-fetch-hosts")
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid url: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported url scheme %q (want http or https)", u.Scheme)
+	}
+	if !s.fetchHosts[strings.ToLower(u.Hostname())] {
+		return nil, status.Errorf(codes.PermissionDenied, "host %q is not in the fetch allowlist", u.Hostname())
+	}
+	return u, nil
+}
+
+func (s *UrbisServer) fetchTooLarge(u *url.URL) error {
+	return status.Errorf(codes.ResourceExhausted, "fetch %s: body exceeds %d bytes", u.Redacted(), s.maxFetchBytes)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"github.com/urbis/api/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// objectFields is a set of pb.ObjectField values, one bit per field
+type objectFields uint8
+
+// allObjectFields is the set an empty field mask stands for
+const allObjectFields objectFields = 1<<pb.ObjectField_OBJECT_FIELD_ID |
+	1<<pb.ObjectField_OBJECT_FIELD_GEOMETRY |
+	1<<pb.ObjectField_OBJECT_FIELD_MBR |
+	1<<pb.ObjectField_OBJECT_FIELD_CENTROID |
+	1<<pb.ObjectField_OBJECT_FIELD_PROPERTIES
+
+// parseFieldMask turns a request's field mask into a set. An empty mask
+// keeps every field.
+func parseFieldMask(mask []pb.ObjectField) (objectFields, error) {
+	if len(mask) == 0 {
+		return allObjectFields, nil
+	}
+	var fields objectFields
+	for _, f := range mask {
+		if f <= pb.ObjectField_OBJECT_FIELD_UNSPECIFIED || f > pb.ObjectField_OBJECT_FIELD_PROPERTIES {
+			return 0, status.Errorf(codes.InvalidArgument, "field_mask: unknown field %d", int32(f))
+		}
+		fields |= 1 << f
+	}
+	return fields, nil
+}
+
+// has reports whether f is in the set
+func (fields objectFields) has(f pb.ObjectField) bool {
+	return fields&(1<<f) != 0
+}
+
+// apply clears the fields outside the set from each object. The type and
+// the version fields are never cleared.
+func (fields objectFields) apply(objs []*pb.SpatialObject) {
+	if fields == allObjectFields {
+		return
+	}
+	for _, obj := range objs {
+		if !fields.has(pb.ObjectField_OBJECT_FIELD_ID) {
+			obj.Id = 0
+		}
+		if !fields.has(pb.ObjectField_OBJECT_FIELD_GEOMETRY) {
+			obj.Geometry = nil
+			obj.EncodedGeometry = nil
+		}
+		if !fields.has(pb.ObjectField_OBJECT_FIELD_MBR) {
+			obj.Mbr = nil
+		}
+		if !fields.has(pb.ObjectField_OBJECT_FIELD_CENTROID) {
+			obj.Centroid = nil
+		}
+		if !fields.has(pb.ObjectField_OBJECT_FIELD_PROPERTIES) {
+			obj.Properties = nil
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// geomTypes is a set of pb.GeomType values, one bit per type
+type geomTypes uint8
+
+// allGeomTypes is the set an empty geom_types filter stands for
+const allGeomTypes geomTypes = 1<<(pb.GeomType_GEOM_GEOMETRYCOLLECTION+1) - 1
+
+// parseGeomTypes turns a request's geom_types filter into a set. An empty
+// filter keeps every type.
+func parseGeomTypes(types []pb.GeomType) (geomTypes, error) {
+	if len(types) == 0 {
+		return allGeomTypes, nil
+	}
+	var set geomTypes
+	for _, t := range types {
+		if t < pb.GeomType_GEOM_POINT || t > pb.GeomType_GEOM_GEOMETRYCOLLECTION {
+			return 0, status.Errorf(codes.InvalidArgument, "geom_types: unknown type %d", int32(t))
+		}
+		set |= 1 << t
+	}
+	return set, nil
+}
+
+// filter returns the objects whose type is in the set, in their order
+func (types geomTypes) filter(objs []*urbis.SpatialObject) []*urbis.SpatialObject {
+	if types == allGeomTypes {
+		return objs
+	}
+	kept := make([]*urbis.SpatialObject, 0, len(objs))
+	for _, obj := range objs {
+		if types&(1<<obj.Type) != 0 {
+			kept = append(kept, obj)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"github.com/urbis/api/pkg/pb"
+	"google.golang.org/grpc/status"
+)
+
+// LoadGeoPackage loads the features of one layer of a server-local
+// GeoPackage file, with its attribute columns as properties
+func (s *UrbisServer) LoadGeoPackage(ctx context.Context, req *pb.LoadGeoPackageRequest) (*pb.LoadResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, err := idx.LoadGeoPackageCounting(req.Path, req.Layer)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to load GeoPackage: %v", err)
+	}
+
+	return &pb.LoadResponse{
+		ObjectsLoaded: result.Loaded,
+		Skipped:       result.Skipped,
+		OutOfBounds:   result.OutOfBounds,
+		Message:       "GeoPackage loaded successfully",
+		Count:         idx.Count(),
+		Bounds:        indexBounds(idx),
+	}, nil
+}
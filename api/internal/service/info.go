@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+)
+
+// WithServerInfo sets the build version and reflection status that
+// GetServerInfo reports. Without it the version is "dev".
+func WithServerInfo(version string, reflection bool) Option {
+	return func(s *UrbisServer) {
+		s.version = version
+		s.reflection = reflection
+	}
+}
+
+// GetServerInfo reports the library, server and file format versions, how
+// long the server has run and how many indexes it holds
+func (s *UrbisServer) GetServerInfo(ctx context.Context, req *pb.ServerInfoRequest) (*pb.ServerInfoResponse, error) {
+	var count uint64
+	s.indexes.Range(func(key, value interface{}) bool {
+		count++
+		return true
+	})
+
+	version := s.version
+	if version == "" {
+		version = "dev"
+	}
+	return &pb.ServerInfoResponse{
+		LibraryVersion:    urbis.Version(),
+		ServerVersion:     version,
+		UptimeMs:          uint64(time.Since(s.started).Milliseconds()),
+		IndexCount:        count,
+		ReflectionEnabled: s.reflection,
+		FileFormatVersion: urbis.FormatVersion(),
+	}, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/urbis/api/pkg/urbis"
+)
+
+// leases counts the requests holding each index, so an index that
+// ReloadIndex replaces or DestroyIndex drops is closed only once the last of
+// them is done with it
+type leases struct {
+	mu      sync.Mutex
+	held    map[*urbis.Index]int
+	retired map[*urbis.Index]bool // Closed by the last release
+}
+
+func (l *leases) acquire(idx *urbis.Index) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held == nil {
+		l.held = make(map[*urbis.Index]int)
+	}
+	l.held[idx]++
+}
+
+func (l *leases) release(idx *urbis.Index) {
+	l.mu.Lock()
+	l.held[idx]--
+	last := l.held[idx] == 0
+	retired := last && l.retired[idx]
+	if last {
+		delete(l.held, idx)
+		delete(l.retired, idx)
+	}
+	l.mu.Unlock()
+
+	if retired {
+		idx.Close()
+	}
+}
+
+// retire closes an index no longer reachable through s.indexes, at once if
+// no request holds it and otherwise when the last one releases it
+func (l *leases) retire(idx *urbis.Index) {
+	l.mu.Lock()
+	if l.held[idx] > 0 {
+		if l.retired == nil {
+			l.retired = make(map[*urbis.Index]bool)
+		}
+		l.retired[idx] = true
+		l.mu.Unlock()
+		return
+	}
+	l.mu.Unlock()
+	idx.Close()
+}
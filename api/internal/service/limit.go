@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// querySlots tracks the queries running against a single index
+type querySlots struct {
+	sem      chan struct{} // nil when concurrency is unlimited
+	inFlight atomic.Int64
+}
+
+// WithQueryLimit caps the number of queries running concurrently against
+// each index. Queries beyond the cap wait up to wait for a free slot and
+// then fail with codes.ResourceExhausted; a zero wait rejects them at once.
+// A max of zero leaves concurrency unlimited.
+func WithQueryLimit(max int, wait time.Duration) Option {
+	return func(s *UrbisServer) {
+		s.maxQueries = max
+		s.queryWait = wait
+	}
+}
+
+// acquireQuery reserves a query slot on an index. The returned function
+// releases it and must be called once the query finishes.
+func (s *UrbisServer) acquireQuery(ctx context.Context, indexID string) (func(), error) {
+	v, ok := s.querySlots.Load(indexID)
+	if !ok {
+		slots := &querySlots{}
+		if s.maxQueries > 0 {
+			slots.sem = make(chan struct{}, s.maxQueries)
+		}
+		v, _ = s.querySlots.LoadOrStore(indexID, slots)
+	}
+	slots := v.(*querySlots)
+
+	if slots.sem != nil {
+		if err := s.waitForSlot(ctx, indexID, slots.sem); err != nil {
+			return nil, err
+		}
+	}
+	slots.inFlight.Add(1)
+
+	return func() {
+		slots.inFlight.Add(-1)
+		if slots.sem != nil {
+			<-slots.sem
+		}
+	}, nil
+}
+
+func (s *UrbisServer) waitForSlot(ctx context.Context, indexID string, sem chan struct{}) error {
+	select {
+	case sem <- struct{}{}:
+		return nil
+	default:
+	}
+
+	exhausted := status.Errorf(codes.ResourceExhausted, "index %q is at its limit of %d concurrent queries", indexID, s.maxQueries)
+	if s.queryWait <= 0 {
+		return exhausted
+	}
+
+	timer := time.NewTimer(s.queryWait)
+	defer timer.Stop()
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return exhausted
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// InFlightQueries returns the number of queries currently running against
+// each index that has served at least one query
+func (s *UrbisServer) InFlightQueries() map[string]int64 {
+	counts := make(map[string]int64)
+	s.querySlots.Range(func(key, value interface{}) bool {
+		counts[key.(string)] = value.(*querySlots).inFlight.Load()
+		return true
+	})
+	return counts
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// queryCRS reprojects the coordinates of a query request into the index CRS
+// and its results back out. The zero value leaves both alone.
+type queryCRS struct {
+	query, index int
+}
+
+// parseQueryCRS checks a request's query_crs against the index. Zero, or
+// the index's own CRS, needs no reprojection. WKB results are exported from
+// the stored geometry, so they cannot be reprojected.
+func parseQueryCRS(idx *urbis.Index, code int32, enc pb.GeometryEncoding) (queryCRS, error) {
+	if code == 0 || int(code) == idx.CRS() {
+		return queryCRS{}, nil
+	}
+	if !urbis.IsSupportedCRS(int(code)) {
+		return queryCRS{}, status.Errorf(codes.InvalidArgument, "unsupported query_crs EPSG:%d (want 4326 or 3857)", code)
+	}
+	if idx.CRS() == urbis.CRSUnspecified {
+		return queryCRS{}, status.Errorf(codes.FailedPrecondition, "index has no crs to reproject query_crs EPSG:%d into", code)
+	}
+	if enc == pb.GeometryEncoding_GEOMETRY_ENCODING_WKB {
+		return queryCRS{}, status.Error(codes.InvalidArgument, "query_crs cannot be combined with WKB encoding")
+	}
+	return queryCRS{query: int(code), index: idx.CRS()}, nil
+}
+
+// region reprojects a query box into the index CRS
+func (q queryCRS) region(m urbis.MBR) (urbis.MBR, error) {
+	out, err := urbis.TransformMBR(m, q.query, q.index)
+	if err != nil {
+		return urbis.MBR{}, status.Errorf(codes.InvalidArgument, "range: %v", err)
+	}
+	return out, nil
+}
+
+// point reprojects a query point into the index CRS
+func (q queryCRS) point(x, y float64) (float64, float64, error) {
+	p, err := urbis.Transform(urbis.Point{X: x, Y: y}, q.query, q.index)
+	if err != nil {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "point: %v", err)
+	}
+	return p.X, p.Y, nil
+}
+
+// results reprojects the geometries of query results into the query CRS
+func (q queryCRS) results(objs []*urbis.SpatialObject) ([]*urbis.SpatialObject, error) {
+	if q.query == q.index {
+		return objs, nil
+	}
+	out := make([]*urbis.SpatialObject, len(objs))
+	for i, obj := range objs {
+		moved, err := urbis.TransformObject(obj, q.index, q.query)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to reproject object %d: %v", obj.ID, err)
+		}
+		out[i] = moved
+	}
+	return out, nil
+}
+
+// geojson reprojects a GeoJSON-encoded result into the query CRS
+func (q queryCRS) geojson(fc *string) error {
+	if q.query == q.index || *fc == "" {
+		return nil
+	}
+	out, err := urbis.ReprojectGeoJSON([]byte(*fc), q.index, q.query)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to reproject GeoJSON: %v", err)
+	}
+	*fc = string(out)
+	return nil
+}
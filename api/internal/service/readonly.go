@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+)
+
+// MarkReadOnly makes an index reject loads, inserts, removals, property
+// updates and builds with FailedPrecondition from now on. Queries and
+// saves still work, and ReloadIndex keeps the replacement read-only.
+func (s *UrbisServer) MarkReadOnly(ctx context.Context, req *pb.MarkReadOnlyRequest) (*pb.MarkReadOnlyResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	wasReadOnly := idx.ReadOnly()
+	idx.MarkReadOnly()
+	s.recordState(func(m *manifest) error {
+		return m.setReadOnly(req.IndexId)
+	})
+	return &pb.MarkReadOnlyResponse{WasReadOnly: wasReadOnly}, nil
+}
+
+// writable returns config with ReadOnly cleared, so an index that will be
+// read-only can still be loaded and built before MarkReadOnly
+func writable(config *urbis.Config) *urbis.Config {
+	if config == nil || !config.ReadOnly {
+		return config
+	}
+	c := *config
+	c.ReadOnly = false
+	return &c
+}
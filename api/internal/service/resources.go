@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+)
+
+// CloseAll closes and forgets every index the server holds, returning how
+// many were closed. Call it once the server has stopped so native memory
+// is freed without waiting for the garbage collector. The manifest is left
+// untouched, so the indexes are restored on the next start.
+func (s *UrbisServer) CloseAll() int {
+	closed := 0
+	s.indexes.Range(func(key, value interface{}) bool {
+		s.indexes.Delete(key)
+		s.querySlots.Delete(key)
+		s.configs.Delete(key)
+		value.(*urbis.Index).Close()
+		closed++
+		return true
+	})
+	return closed
+}
+
+// GetResourceStats reports the native indexes open in the process. Open
+// indexes the server no longer holds are leaks; their origins are listed.
+func (s *UrbisServer) GetResourceStats(ctx context.Context, req *pb.ResourceStatsRequest) (*pb.ResourceStatsResponse, error) {
+	var held []*urbis.Index
+	s.indexes.Range(func(key, value interface{}) bool {
+		held = append(held, value.(*urbis.Index))
+		return true
+	})
+
+	res := urbis.Resources()
+	resp := &pb.ResourceStatsResponse{
+		OpenIndexes:      uint64(res.OpenIndexes),
+		NativeBytes:      res.NativeBytes,
+		FinalizedIndexes: res.Finalized,
+	}
+	for _, leaked := range urbis.OpenIndexes(held...) {
+		resp.LeakedOrigins = append(resp.LeakedOrigins, leaked.Origin)
+	}
+	return resp, nil
+}
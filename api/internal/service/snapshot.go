@@ -0,0 +1,49 @@
+package service
+
+import (
+	"github.com/urbis/api/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultScanBatch is the number of objects per SnapshotScan message when
+// the request does not say
+const defaultScanBatch = 1000
+
+// SnapshotScan streams every object in an index as of the call. The copy is
+// taken up front, so writes made while the stream is open are not seen; it
+// is released when the stream ends.
+func (s *UrbisServer) SnapshotScan(req *pb.SnapshotScanRequest, stream pb.UrbisService_SnapshotScanServer) error {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	snap, err := idx.Snapshot()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to take snapshot: %v", err)
+	}
+
+	batch := int(req.BatchSize)
+	if batch == 0 {
+		batch = defaultScanBatch
+	}
+	total := uint64(len(snap.Objects))
+	takenAt := snap.TakenAt.UnixMilli()
+
+	// An empty index still gets one message carrying the snapshot time
+	for start := 0; start == 0 || start < len(snap.Objects); start += batch {
+		objs := snap.Objects[start:min(start+batch, len(snap.Objects))]
+		if err := stream.Send(&pb.SnapshotScanResponse{
+			Objects:      convertToPbResults(objs, true),
+			Total:        total,
+			SnapshotAtMs: takenAt,
+		}); err != nil {
+			return err
+		}
+		// Let the copies already sent be collected
+		clear(snap.Objects[start : start+len(objs)])
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/urbis/api/pkg/urbis"
+)
+
+// manifestFile is the name of the manifest inside the state directory
+const manifestFile = "manifest.json"
+
+// manifestEntry records what is needed to recover a single index
+type manifestEntry struct {
+	IndexID  string        `json:"index_id"`
+	Config   *urbis.Config `json:"config,omitempty"`
+	DataFile string        `json:"data_file,omitempty"`
+	ReadOnly bool          `json:"read_only,omitempty"` // Set by MarkReadOnly or a read-only Load
+}
+
+// manifest tracks index IDs, configs and saved data files on disk so
+// that indexes survive server restarts
+type manifest struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]*manifestEntry
+}
+
+// openManifest reads the manifest in dir, creating the directory if needed
+func openManifest(dir string) (*manifest, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create state dir: %w", err)
+	}
+
+	m := &manifest{
+		path:    filepath.Join(dir, manifestFile),
+		entries: make(map[string]*manifestEntry),
+	}
+
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	var entries []*manifestEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	for _, e := range entries {
+		m.entries[e.IndexID] = e
+	}
+
+	return m, nil
+}
+
+// list returns a snapshot of all entries ordered by index ID
+func (m *manifest) list() []manifestEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := make([]manifestEntry, 0, len(m.entries))
+	for _, e := range m.entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].IndexID < entries[j].IndexID })
+	return entries
+}
+
+// put records an index, replacing any existing entry
+func (m *manifest) put(entry manifestEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[entry.IndexID] = &entry
+	return m.writeLocked()
+}
+
+// setDataFile records the data file an index was last saved to
+func (m *manifest) setDataFile(indexID, path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[indexID]
+	if !ok {
+		e = &manifestEntry{IndexID: indexID}
+		m.entries[indexID] = e
+	}
+	e.DataFile = abs
+	return m.writeLocked()
+}
+
+// setPageCapacity records a page capacity chosen after creation, so a
+// recreated index uses it too
+func (m *manifest) setPageCapacity(indexID string, capacity uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[indexID]
+	if !ok {
+		return nil
+	}
+	if e.Config == nil {
+		config := urbis.DefaultConfig()
+		e.Config = &config
+	}
+	e.Config.PageCapacity = capacity
+	return m.writeLocked()
+}
+
+// setReadOnly records that an index was made read-only, so it is restored
+// read-only too
+func (m *manifest) setReadOnly(indexID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[indexID]
+	if !ok {
+		return nil
+	}
+	e.ReadOnly = true
+	return m.writeLocked()
+}
+
+// remove drops an index from the manifest
+func (m *manifest) remove(indexID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.entries[indexID]; !ok {
+		return nil
+	}
+	delete(m.entries, indexID)
+	return m.writeLocked()
+}
+
+// writeLocked atomically rewrites the manifest file. Caller holds m.mu.
+func (m *manifest) writeLocked() error {
+	entries := make([]*manifestEntry, 0, len(m.entries))
+	for _, e := range m.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].IndexID < entries[j].IndexID })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.path)
+}
+
+// =============================================================================
+// Server State
+// =============================================================================
+
+// WithStateDir persists index IDs, configs and saved data files to a
+// manifest in dir so they can be recovered with RestoreState
+func WithStateDir(dir string) Option {
+	return func(s *UrbisServer) {
+		s.stateDir = dir
+	}
+}
+
+// RestoreState opens the state directory and reloads every index recorded
+// in its manifest. An index with a data file is loaded from it with its
+// recorded config, so settings the file does not hold, such as indexed
+// properties and the quadtree, carry over; the CRS, polygon validation,
+// property schema and valid bounds come from the file. One without a data
+// file is recreated from its config. Entries
+// whose data file is missing or corrupt are logged and skipped. It is a
+// no-op when no state directory is configured.
+func (s *UrbisServer) RestoreState() error {
+	if s.stateDir == "" {
+		return nil
+	}
+
+	m, err := openManifest(s.stateDir)
+	if err != nil {
+		return err
+	}
+	s.manifest = m
+
+	for _, entry := range m.list() {
+		var idx *urbis.Index
+		if entry.DataFile != "" {
+			if _, err := os.Stat(entry.DataFile); err != nil {
+				slog.Warn("Skipping index: data file unavailable", "index_id", entry.IndexID, "data_file", entry.DataFile, "error", err)
+				continue
+			}
+			idx, err = urbis.LoadWithConfig(entry.DataFile, entry.Config)
+		} else {
+			idx, err = urbis.NewIndex(entry.Config)
+		}
+		if err != nil {
+			slog.Warn("Skipping index", "index_id", entry.IndexID, "error", err)
+			continue
+		}
+
+		if entry.ReadOnly {
+			idx.MarkReadOnly()
+		}
+		s.indexes.Store(entry.IndexID, idx)
+		// A created index keeps its config after a save; one that was
+		// loaded from a file never had one
+		if entry.DataFile == "" || entry.Config != nil {
+			s.storeConfig(entry.IndexID, entry.Config)
+		}
+		slog.Info("Restored index", "index_id", entry.IndexID)
+	}
+
+	return nil
+}
+
+// recordState applies fn to the manifest, logging rather than failing the
+// request when the manifest cannot be written
+func (s *UrbisServer) recordState(fn func(m *manifest) error) {
+	if s.manifest == nil {
+		return
+	}
+	if err := fn(s.manifest); err != nil {
+		slog.Error("Failed to update state manifest", "error", err)
+	}
+}
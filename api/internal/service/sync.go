@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sync saves a persistent index to its data file now, whether or not it
+// changed since the last save
+func (s *UrbisServer) Sync(ctx context.Context, req *pb.SyncRequest) (*pb.SyncResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	path := s.dataFileOf(req.IndexId)
+	if path == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "index %q does not persist to a data_path", req.IndexId)
+	}
+
+	changes, err := s.flush(req.IndexId, idx, path, true)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to sync index: %v", err)
+	}
+	return &pb.SyncResponse{Path: path, ChangeCount: changes}, nil
+}
+
+// RunSyncer saves every persistent index that changed to its data file once
+// per interval until ctx is done, so writes return without waiting for the
+// disk. Changes made since the last save are lost if the process dies. A
+// zero interval returns at once, leaving saves to Save and Sync calls.
+func (s *UrbisServer) RunSyncer(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.FlushAll()
+		}
+	}
+}
+
+// FlushAll saves every persistent index that changed since its last save
+// to its data file and returns how many it saved. Failures are logged.
+// The server calls it on graceful shutdown after the last request.
+func (s *UrbisServer) FlushAll() int {
+	saved := 0
+	s.indexes.Range(func(key, value interface{}) bool {
+		id := key.(string)
+		path := s.dataFileOf(id)
+		if path == "" {
+			return true
+		}
+		idx, release, err := s.getIndex(id)
+		if err != nil {
+			return true
+		}
+		defer release()
+
+		before, _ := s.synced.Load(id)
+		changes, err := s.flush(id, idx, path, false)
+		switch {
+		case err != nil:
+			slog.Warn("Failed to sync index", "index_id", id, "path", path, "error", err)
+		case before == nil || before.(uint64) != changes:
+			saved++
+			slog.Debug("Synced index", "index_id", id, "path", path, "change_count", changes)
+		}
+		return true
+	})
+	return saved
+}
+
+// flush saves idx to its data file path unless it is unchanged since the
+// last save and force is false, and returns the change count the file
+// holds. Each flush writes a full snapshot, under the index's write lock,
+// to a temporary file that Save moves over path only once complete, so a
+// crash during the save leaves the previous file. Writes made while it
+// runs wait for it; those made since the count was taken are saved by the
+// next flush.
+func (s *UrbisServer) flush(id string, idx *urbis.Index, path string, force bool) (uint64, error) {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	changes := idx.ChangeCount()
+	if last, ok := s.synced.Load(id); ok && last.(uint64) == changes && !force {
+		return changes, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, fmt.Errorf("%w: %v", urbis.ErrIO, err)
+	}
+	if err := idx.Save(path); err != nil {
+		return 0, err
+	}
+
+	s.synced.Store(id, changes)
+	s.recordState(func(m *manifest) error {
+		return m.setDataFile(id, path)
+	})
+	return changes, nil
+}
+
+// dataFileOf returns the data file of a persistent index, or "" if the
+// index does not persist to a data_path
+func (s *UrbisServer) dataFileOf(indexID string) string {
+	v, ok := s.configs.Load(indexID)
+	if !ok {
+		return ""
+	}
+	return dataFile(indexID, v.(*urbis.Config))
+}
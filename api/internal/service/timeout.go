@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+// WithQueryTimeout bounds how long a spatial query may run. A query still
+// running after d, or after the client's own deadline if that comes first,
+// fails with codes.DeadlineExceeded. Zero leaves only the client deadline.
+func WithQueryTimeout(d time.Duration) Option {
+	return func(s *UrbisServer) {
+		s.queryTimeout = d
+	}
+}
+
+// queryResult carries the outcome of a query run on another goroutine
+type queryResult[T any] struct {
+	value T
+	err   error
+}
+
+// runQuery reserves a query slot on an index and runs query on its own
+// goroutine, returning early with codes.DeadlineExceeded (or
+// codes.Canceled) if the query timeout or the caller's context ends first.
+//
+// The C library cannot be interrupted, so an abandoned query keeps running
+// and keeps its slot until it returns. The bindings free the native result
+// before handing back Go values, so nothing leaks: the result is dropped
+// into a buffered channel nobody reads and is garbage collected.
+func runQuery[T any](ctx context.Context, s *UrbisServer, indexID string, query func() (T, error)) (T, error) {
+	var zero T
+
+	release, err := s.acquireQuery(ctx, indexID)
+	if err != nil {
+		return zero, err
+	}
+
+	if s.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		cause := fmt.Errorf("query exceeded the server timeout of %v", s.queryTimeout)
+		ctx, cancel = context.WithTimeoutCause(ctx, s.queryTimeout, cause)
+		defer cancel()
+	}
+
+	done := make(chan queryResult[T], 1)
+	go func() {
+		value, err := query()
+		release()
+		done <- queryResult[T]{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return zero, status.Errorf(errorCode(r.err), "query failed: %v", r.err)
+		}
+		return r.value, nil
+	case <-ctx.Done():
+		st := status.FromContextError(ctx.Err())
+		if cause := context.Cause(ctx); cause != ctx.Err() {
+			return zero, status.Error(st.Code(), cause.Error())
+		}
+		return zero, st.Err()
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SweepExpired removes the objects of an index whose ttl_ms has passed
+func (s *UrbisServer) SweepExpired(ctx context.Context, req *pb.SweepExpiredRequest) (*pb.SweepExpiredResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	removed, err := idx.SweepExpired()
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to sweep expired objects: %v", err)
+	}
+	return &pb.SweepExpiredResponse{Removed: removed, Count: idx.Count()}, nil
+}
+
+// maxTTLMs is the longest ttl_ms a time.Duration holds
+const maxTTLMs = uint64(math.MaxInt64 / int64(time.Millisecond))
+
+// validateTTL checks an insert request's ttl_ms before the insert, so a
+// TTL that cannot be set never leaves an object behind
+func validateTTL(ttlMs uint64) error {
+	if ttlMs > maxTTLMs {
+		return status.Errorf(codes.InvalidArgument, "ttl_ms %d is longer than the maximum of %d", ttlMs, maxTTLMs)
+	}
+	return nil
+}
+
+// insertOptions carries an insert request's object_id, ttl_ms, once
+// validateTTL has passed, and point z and m values into the insert itself
+func insertOptions(objectID, ttlMs uint64, z, m []float64) urbis.InsertOptions {
+	return urbis.InsertOptions{ID: objectID, TTL: time.Duration(ttlMs) * time.Millisecond, Z: z, M: m}
+}
+
+// RunSweeper sweeps expired objects from every index once per interval
+// until ctx is done. A zero interval returns at once, leaving expired
+// objects until a SweepExpired call.
+func (s *UrbisServer) RunSweeper(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweepAll()
+		}
+	}
+}
+
+// sweepAll runs one background sweep over every index
+func (s *UrbisServer) sweepAll() {
+	s.indexes.Range(func(key, value interface{}) bool {
+		idx, release, err := s.getIndex(key.(string))
+		if err != nil {
+			return true
+		}
+		defer release()
+
+		removed, err := idx.SweepExpired()
+		switch {
+		case errors.Is(err, urbis.ErrReadOnly):
+		case err != nil:
+			slog.Warn("Failed to sweep expired objects", "index_id", key, "error", err)
+		case removed > 0:
+			slog.Info("Swept expired objects", "index_id", key, "removed", removed)
+		}
+		return true
+	})
+}
@@ -2,8 +2,18 @@
 package service
 
 import (
+	"bufio"
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/urbis/api/pkg/pb"
@@ -15,27 +25,88 @@ import (
 // UrbisServer implements the UrbisService gRPC server
 type UrbisServer struct {
 	pb.UnimplementedUrbisServiceServer
-	indexes sync.Map // map[string]*urbis.Index
-	mu      sync.RWMutex
+	indexes  sync.Map // map[string]*urbis.Index
+	leases   leases
+	mu       sync.RWMutex
+	stateDir string
+	manifest *manifest
+	draining atomic.Bool
+
+	maxQueries   int
+	queryWait    time.Duration
+	queryTimeout time.Duration
+	querySlots   sync.Map // map[string]*querySlots
+	configs      sync.Map // map[string]*urbis.Config, for DescribeIndex
+	synced       sync.Map // map[string]uint64, ChangeCount at the last save to the data file
+	flushMu      sync.Mutex
+	createMu     sync.Mutex // Held while CreateIndex publishes an index and its config
+
+	fetchHosts    map[string]bool
+	maxFetchBytes int64
+
+	apiKeys  map[string][]string // API key to allowed index-ID prefixes; nil disables auth
+	defaults *pb.Config          // Config for CreateIndex requests without one; nil for library defaults
+
+	started    time.Time
+	version    string
+	reflection bool
 }
 
+// Option configures an UrbisServer
+type Option func(*UrbisServer)
+
 // NewUrbisServer creates a new Urbis gRPC server
-func NewUrbisServer() *UrbisServer {
-	return &UrbisServer{}
+func NewUrbisServer(opts ...Option) *UrbisServer {
+	s := &UrbisServer{started: time.Now()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// getIndex retrieves an index by ID
-func (s *UrbisServer) getIndex(indexID string) (*urbis.Index, error) {
+// getIndex retrieves an index by ID and leases it. The returned function
+// releases the lease and must be called once the request is done with the
+// index; until then a reload or destroy leaves it open.
+func (s *UrbisServer) getIndex(indexID string) (*urbis.Index, func(), error) {
 	if indexID == "" {
-		return nil, status.Error(codes.InvalidArgument, "index_id is required")
+		return nil, nil, status.Error(codes.InvalidArgument, "index_id is required")
 	}
 	
-	val, ok := s.indexes.Load(indexID)
-	if !ok {
-		return nil, status.Errorf(codes.NotFound, "index %q not found", indexID)
+	for {
+		val, ok := s.indexes.Load(indexID)
+		if !ok {
+			return nil, nil, status.Errorf(codes.NotFound, "index %q not found", indexID)
+		}
+		idx := val.(*urbis.Index)
+
+		// Replaced between the lookup and the lease, so possibly already
+		// retired and closed; look again
+		s.leases.acquire(idx)
+		if cur, _ := s.indexes.Load(indexID); cur == val {
+			return idx, func() { s.leases.release(idx) }, nil
+		}
+		s.leases.release(idx)
 	}
-	
-	return val.(*urbis.Index), nil
+}
+
+// IndexCount returns the number of indexes currently held by the server
+func (s *UrbisServer) IndexCount() int {
+	count := 0
+	s.indexes.Range(func(key, value interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// TotalObjects returns the number of objects across all indexes
+func (s *UrbisServer) TotalObjects() uint64 {
+	var total uint64
+	s.indexes.Range(func(key, value interface{}) bool {
+		total += value.(*urbis.Index).Count()
+		return true
+	})
+	return total
 }
 
 // =============================================================================
@@ -48,22 +119,19 @@ func (s *UrbisServer) CreateIndex(ctx context.Context, req *pb.CreateIndexReques
 		return nil, status.Error(codes.InvalidArgument, "index_id is required")
 	}
 	
+	// Build configuration
+	config, err := convertConfig(s.withDefaults(req.Config))
+	if err != nil {
+		return nil, err
+	}
+	
 	// Check if index already exists
-	if _, ok := s.indexes.Load(req.IndexId); ok {
-		return nil, status.Errorf(codes.AlreadyExists, "index %q already exists", req.IndexId)
+	if val, ok := s.indexes.Load(req.IndexId); ok {
+		return s.existingIndex(req, config, val.(*urbis.Index))
 	}
 	
-	// Build configuration
-	var config *urbis.Config
-	if req.Config != nil {
-		config = &urbis.Config{
-			BlockSize:      req.Config.BlockSize,
-			PageCapacity:   req.Config.PageCapacity,
-			CacheSize:      req.Config.CacheSize,
-			EnableQuadtree: req.Config.EnableQuadtree,
-			Persist:        req.Config.Persist,
-			DataPath:       req.Config.DataPath,
-		}
+	if err := s.checkDataPath(req.IndexId, config); err != nil {
+		return nil, err
 	}
 	
 	// Create index
@@ -72,23 +140,67 @@ func (s *UrbisServer) CreateIndex(ctx context.Context, req *pb.CreateIndexReques
 		return nil, status.Errorf(codes.Internal, "failed to create index: %v", err)
 	}
 	
-	s.indexes.Store(req.IndexId, idx)
+	// Another request may have taken the ID meanwhile; the first to store
+	// its index wins and the others answer as if it already existed
+	s.createMu.Lock()
+	actual, loaded := s.indexes.LoadOrStore(req.IndexId, idx)
+	if !loaded {
+		s.storeConfig(req.IndexId, config)
+	}
+	s.createMu.Unlock()
+	if loaded {
+		idx.Close()
+		return s.existingIndex(req, config, actual.(*urbis.Index))
+	}
+	s.recordState(func(m *manifest) error {
+		return m.put(manifestEntry{IndexID: req.IndexId, Config: config})
+	})
 	
 	return &pb.CreateIndexResponse{
 		IndexId: req.IndexId,
 		Message: "Index created successfully",
+		Count:   idx.Count(),
+		Bounds:  indexBounds(idx),
+		Created: true,
+	}, nil
+}
+
+// existingIndex answers a CreateIndex for an ID already taken by idx
+func (s *UrbisServer) existingIndex(req *pb.CreateIndexRequest, config *urbis.Config, idx *urbis.Index) (*pb.CreateIndexResponse, error) {
+	if !req.IfNotExists {
+		return nil, status.Errorf(codes.AlreadyExists, "index %q already exists", req.IndexId)
+	}
+	// The lock ensures a concurrently created index has its config stored
+	s.createMu.Lock()
+	matches := s.configMatches(req.IndexId, config)
+	s.createMu.Unlock()
+	if !matches {
+		return nil, status.Errorf(codes.FailedPrecondition, "index %q already exists with a different config", req.IndexId)
+	}
+	return &pb.CreateIndexResponse{
+		IndexId: req.IndexId,
+		Message: "Index already exists",
+		Count:   idx.Count(),
+		Bounds:  indexBounds(idx),
 	}, nil
 }
 
 // DestroyIndex destroys an existing index
 func (s *UrbisServer) DestroyIndex(ctx context.Context, req *pb.DestroyIndexRequest) (*pb.DestroyIndexResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	
-	idx.Close()
 	s.indexes.Delete(req.IndexId)
+	s.leases.retire(idx)
+	s.querySlots.Delete(req.IndexId)
+	s.configs.Delete(req.IndexId)
+	s.synced.Delete(req.IndexId)
+	s.recordState(func(m *manifest) error {
+		return m.remove(req.IndexId)
+	})
 	
 	return &pb.DestroyIndexResponse{
 		Message: "Index destroyed successfully",
@@ -114,59 +226,75 @@ func (s *UrbisServer) ListIndexes(ctx context.Context, req *pb.ListIndexesReques
 
 // LoadGeoJSON loads data from a GeoJSON file
 func (s *UrbisServer) LoadGeoJSON(ctx context.Context, req *pb.LoadGeoJSONRequest) (*pb.LoadResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	
-	countBefore := idx.Count()
-	
-	if err := idx.LoadGeoJSON(req.Path); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to load GeoJSON: %v", err)
+	if req.DryRun {
+		return dryRun(idx, func() (urbis.LoadResult, []string, error) {
+			return idx.DryRunGeoJSONFrom(req.Path, int(req.SourceCrs))
+		})
+	}
+
+	result, err := idx.LoadGeoJSONFrom(req.Path, int(req.SourceCrs))
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to load GeoJSON: %v", err)
 	}
-	
-	countAfter := idx.Count()
-	loaded := countAfter - countBefore
 	
 	return &pb.LoadResponse{
-		ObjectsLoaded: loaded,
+		ObjectsLoaded: result.Loaded,
+		Skipped:       result.Skipped,
+		OutOfBounds:   result.OutOfBounds,
 		Message:       "GeoJSON loaded successfully",
+		Count:         idx.Count(),
+		Bounds:        indexBounds(idx),
 	}, nil
 }
 
 // LoadGeoJSONString loads data from a GeoJSON string
 func (s *UrbisServer) LoadGeoJSONString(ctx context.Context, req *pb.LoadGeoJSONStringRequest) (*pb.LoadResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	
-	countBefore := idx.Count()
-	
-	if err := idx.LoadGeoJSONString(req.Geojson); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to load GeoJSON: %v", err)
+	if req.DryRun {
+		return dryRun(idx, func() (urbis.LoadResult, []string, error) {
+			return idx.DryRunGeoJSONStringFrom(req.Geojson, int(req.SourceCrs))
+		})
+	}
+
+	result, err := idx.LoadGeoJSONStringFrom(req.Geojson, int(req.SourceCrs))
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to load GeoJSON: %v", err)
 	}
-	
-	countAfter := idx.Count()
-	loaded := countAfter - countBefore
 	
 	return &pb.LoadResponse{
-		ObjectsLoaded: loaded,
+		ObjectsLoaded: result.Loaded,
+		Skipped:       result.Skipped,
+		OutOfBounds:   result.OutOfBounds,
 		Message:       "GeoJSON loaded successfully",
+		Count:         idx.Count(),
+		Bounds:        indexBounds(idx),
 	}, nil
 }
 
 // LoadWKT loads data from a WKT string
 func (s *UrbisServer) LoadWKT(ctx context.Context, req *pb.LoadWKTRequest) (*pb.LoadResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	
 	countBefore := idx.Count()
 	
-	if err := idx.LoadWKT(req.Wkt); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to load WKT: %v", err)
+	srid, err := idx.LoadEWKT(req.Wkt)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to load WKT: %v", err)
 	}
 	
 	countAfter := idx.Count()
@@ -175,235 +303,607 @@ func (s *UrbisServer) LoadWKT(ctx context.Context, req *pb.LoadWKTRequest) (*pb.
 	return &pb.LoadResponse{
 		ObjectsLoaded: loaded,
 		Message:       "WKT loaded successfully",
+		Count:         countAfter,
+		Bounds:        indexBounds(idx),
+		Srid:          int32(srid),
 	}, nil
 }
 
+// LoadWKB loads data from WKB bytes
+func (s *UrbisServer) LoadWKB(ctx context.Context, req *pb.LoadWKBRequest) (*pb.LoadResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	countBefore := idx.Count()
+
+	if err := idx.LoadWKB(req.Wkb); err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to load WKB: %v", err)
+	}
+
+	countAfter := idx.Count()
+
+	return &pb.LoadResponse{
+		ObjectsLoaded: countAfter - countBefore,
+		Message:       "WKB loaded successfully",
+		Count:         countAfter,
+		Bounds:        indexBounds(idx),
+	}, nil
+}
+
+// StreamLoadGeoJSON loads newline-delimited GeoJSON features streamed in chunks
+func (s *UrbisServer) StreamLoadGeoJSON(stream pb.UrbisService_StreamLoadGeoJSONServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "index_id is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	idx, release, err := s.getIndex(first.IndexId)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	next := func() ([]byte, error) {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return msg.Chunk, nil
+	}
+	result, err := idx.LoadGeoJSONReader(&chunkReader{next: next, buf: first.Chunk})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(errorCode(err), "failed to load GeoJSON after %d objects: %v", result.Loaded, err)
+	}
+
+	return stream.SendAndClose(&pb.LoadResponse{
+		ObjectsLoaded: result.Loaded,
+		Skipped:       result.Skipped,
+		OutOfBounds:   result.OutOfBounds,
+		Message:       "GeoJSON stream loaded successfully",
+		Count:         idx.Count(),
+		Bounds:        indexBounds(idx),
+	})
+}
+
+// chunkReader adapts a client stream of byte chunks to an io.Reader
+type chunkReader struct {
+	next func() ([]byte, error)
+	buf  []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.next()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
 // =============================================================================
 // Object Operations
 // =============================================================================
 
 // InsertPoint inserts a point into the index
 func (s *UrbisServer) InsertPoint(ctx context.Context, req *pb.InsertPointRequest) (*pb.InsertResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
+	
+	if err := validateCoords(&pb.Point{X: req.X, Y: req.Y}); err != nil {
+		return nil, err
+	}
+	if err := validateTTL(req.TtlMs); err != nil {
+		return nil, err
+	}
 	
-	id, err := idx.InsertPoint(req.X, req.Y)
+	ins, err := idx.InsertPointWith(req.X, req.Y, insertOptions(req.ObjectId, req.TtlMs, optionalSlice(req.Z), optionalSlice(req.M)))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to insert point: %v", err)
+		return nil, status.Errorf(errorCode(err), "failed to insert point: %v", err)
 	}
 	
-	return &pb.InsertResponse{
-		ObjectId: id,
-	}, nil
+	return convertToPbInserted(ins), nil
 }
 
 // InsertLineString inserts a linestring into the index
 func (s *UrbisServer) InsertLineString(ctx context.Context, req *pb.InsertLineStringRequest) (*pb.InsertResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	
+	if err := validateCoords(req.Points...); err != nil {
+		return nil, err
+	}
+	z, m, err := zmFromPb(req.Points)
 	if err != nil {
 		return nil, err
 	}
+	if err := validateTTL(req.TtlMs); err != nil {
+		return nil, err
+	}
 	
 	points := make([]urbis.Point, len(req.Points))
 	for i, p := range req.Points {
 		points[i] = urbis.Point{X: p.X, Y: p.Y}
 	}
 	
-	id, err := idx.InsertLineString(points)
+	ins, err := idx.InsertLineStringWith(points, insertOptions(req.ObjectId, req.TtlMs, z, m))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to insert linestring: %v", err)
+		return nil, status.Errorf(errorCode(err), "failed to insert linestring: %v", err)
 	}
 	
-	return &pb.InsertResponse{
-		ObjectId: id,
-	}, nil
+	return convertToPbInserted(ins), nil
 }
 
 // InsertPolygon inserts a polygon into the index
 func (s *UrbisServer) InsertPolygon(ctx context.Context, req *pb.InsertPolygonRequest) (*pb.InsertResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	
+	if err := validateCoords(req.Exterior...); err != nil {
+		return nil, err
+	}
+	z, m, err := zmFromPb(req.Exterior)
 	if err != nil {
 		return nil, err
 	}
+	if err := validateTTL(req.TtlMs); err != nil {
+		return nil, err
+	}
 	
 	exterior := make([]urbis.Point, len(req.Exterior))
 	for i, p := range req.Exterior {
 		exterior[i] = urbis.Point{X: p.X, Y: p.Y}
 	}
 	
-	id, err := idx.InsertPolygon(exterior)
+	ins, err := idx.InsertPolygonWith(exterior, insertOptions(req.ObjectId, req.TtlMs, z, m))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to insert polygon: %v", err)
+		return nil, status.Errorf(errorCode(err), "failed to insert polygon: %v", err)
 	}
 	
-	return &pb.InsertResponse{
-		ObjectId: id,
-	}, nil
+	resp := convertToPbInserted(ins)
+	if idx.PolygonValidation() == urbis.ValidationReport {
+		valid, reason := urbis.ValidatePolygonWithin(exterior, idx.ValidationTolerance())
+		resp.IsValid = &valid
+		resp.Reason = reason
+	}
+	return resp, nil
+}
+
+// StreamInsert inserts geometries as they arrive, answering each with its
+// ID or error before reading the next. A failed item does not end the stream.
+// Every item goes to the index the first one names; a later item naming
+// another fails with InvalidArgument.
+func (s *UrbisServer) StreamInsert(stream pb.UrbisService_StreamInsertServer) error {
+	ctx := stream.Context()
+	var indexID string
+
+	for seq := uint64(0); ; seq++ {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if seq == 0 {
+			indexID = req.IndexId
+			_, release, err := s.getIndex(indexID)
+			if err != nil {
+				return err
+			}
+			release()
+		}
+
+		resp := &pb.StreamInsertResponse{Sequence: seq}
+		if req.IndexId != "" && req.IndexId != indexID {
+			err = status.Errorf(codes.InvalidArgument, "index_id %q differs from the stream's index %q", req.IndexId, indexID)
+		} else {
+			resp.Result, err = s.insertOne(ctx, indexID, req)
+		}
+		if err != nil {
+			st := status.Convert(err)
+			resp.Code = uint32(st.Code())
+			resp.Error = st.Message()
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// insertOne applies one StreamInsert item through the matching unary insert
+func (s *UrbisServer) insertOne(ctx context.Context, indexID string, req *pb.StreamInsertRequest) (*pb.InsertResponse, error) {
+	switch g := req.Geometry.(type) {
+	case *pb.StreamInsertRequest_Point:
+		return s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: indexID, X: g.Point.GetX(), Y: g.Point.GetY(), Z: g.Point.Z, M: g.Point.M, ObjectId: req.ObjectId, TtlMs: req.TtlMs})
+	case *pb.StreamInsertRequest_Line:
+		return s.InsertLineString(ctx, &pb.InsertLineStringRequest{IndexId: indexID, Points: g.Line.GetPoints(), ObjectId: req.ObjectId, TtlMs: req.TtlMs})
+	case *pb.StreamInsertRequest_Polygon:
+		if len(g.Polygon.GetHoles()) > 0 {
+			return nil, status.Error(codes.InvalidArgument, "polygon holes are not supported")
+		}
+		return s.InsertPolygon(ctx, &pb.InsertPolygonRequest{IndexId: indexID, Exterior: g.Polygon.GetExterior(), ObjectId: req.ObjectId, TtlMs: req.TtlMs})
+	}
+	return nil, status.Error(codes.InvalidArgument, "geometry is required")
 }
 
+
 // Remove removes an object from the index
 func (s *UrbisServer) Remove(ctx context.Context, req *pb.RemoveRequest) (*pb.RemoveResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	
 	if err := idx.Remove(req.ObjectId); err != nil {
+		if errors.Is(err, urbis.ErrReadOnly) {
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to remove object: %v", err)
+		}
 		return &pb.RemoveResponse{Success: false}, nil
 	}
 	
 	return &pb.RemoveResponse{Success: true}, nil
 }
 
+// RemoveRange removes every object in a region
+func (s *UrbisServer) RemoveRange(ctx context.Context, req *pb.RemoveRangeRequest) (*pb.RemoveRangeResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if req.Region == nil {
+		return nil, status.Error(codes.InvalidArgument, "region is required")
+	}
+	if _, ok := pb.RangeMatch_name[int32(req.Match)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown range match %d", req.Match)
+	}
+
+	region := urbis.MBR{
+		MinX: req.Region.MinX,
+		MinY: req.Region.MinY,
+		MaxX: req.Region.MaxX,
+		MaxY: req.Region.MaxY,
+	}
+
+	removed, err := idx.RemoveRange(region, urbis.Match(req.Match))
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to remove range: %v", err)
+	}
+
+	return &pb.RemoveRangeResponse{
+		Removed: removed,
+		Count:   idx.Count(),
+	}, nil
+}
+
 // GetObject retrieves an object by ID
 func (s *UrbisServer) GetObject(ctx context.Context, req *pb.GetObjectRequest) (*pb.GetObjectResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	
 	obj, err := idx.Get(req.ObjectId)
 	if err != nil {
 		return &pb.GetObjectResponse{Found: false}, nil
 	}
 	
+	pbObj := convertToPbObject(obj)
+	if req.IncludeVersion {
+		setPbVersion(pbObj, obj)
+	}
+	
 	return &pb.GetObjectResponse{
-		Object: convertToPbObject(obj),
+		Object: pbObj,
 		Found:  true,
 	}, nil
 }
 
-// =============================================================================
-// Index Building
-// =============================================================================
+// SetProperties replaces an object's properties without reinserting it
+func (s *UrbisServer) SetProperties(ctx context.Context, req *pb.SetPropertiesRequest) (*pb.SetPropertiesResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-// Build builds the spatial index
-func (s *UrbisServer) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	err = idx.SetProperties(req.ObjectId, req.Properties)
+	if errors.Is(err, urbis.ErrNotFound) {
+		return &pb.SetPropertiesResponse{Success: false}, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to set properties: %v", err)
+	}
+
+	return &pb.SetPropertiesResponse{Success: true}, nil
+}
+
+// GetProperties retrieves an object's properties
+func (s *UrbisServer) GetProperties(ctx context.Context, req *pb.GetPropertiesRequest) (*pb.GetPropertiesResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
-	
-	start := time.Now()
-	
-	if err := idx.Build(); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to build index: %v", err)
+	defer release()
+
+	props, err := idx.GetProperties(req.ObjectId)
+	if err != nil {
+		return &pb.GetPropertiesResponse{Found: false}, nil
 	}
-	
-	elapsed := time.Since(start)
-	
-	return &pb.BuildResponse{
-		Message:     "Index built successfully",
-		BuildTimeMs: float64(elapsed.Microseconds()) / 1000.0,
+
+	return &pb.GetPropertiesResponse{
+		Properties: props,
+		Found:      true,
 	}, nil
 }
 
-// Optimize optimizes the index
-func (s *UrbisServer) Optimize(ctx context.Context, req *pb.OptimizeRequest) (*pb.OptimizeResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+// BatchGetObjects retrieves several objects by ID; missing IDs are reported
+// per entry rather than failing the batch
+func (s *UrbisServer) BatchGetObjects(ctx context.Context, req *pb.BatchGetObjectsRequest) (*pb.BatchGetObjectsResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
-	
-	if err := idx.Optimize(); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to optimize index: %v", err)
+	defer release()
+
+	objs, err := idx.GetObjects(req.ObjectIds)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get objects: %v", err)
 	}
-	
-	return &pb.OptimizeResponse{
-		Message: "Index optimized successfully",
-	}, nil
+
+	resp := &pb.BatchGetObjectsResponse{
+		Objects: make([]*pb.SpatialObject, len(objs)),
+		Found:   make([]bool, len(objs)),
+	}
+	for i, obj := range objs {
+		if obj == nil {
+			resp.Objects[i] = &pb.SpatialObject{}
+			continue
+		}
+		resp.Objects[i] = convertToPbObject(obj)
+		if req.IncludeVersion {
+			setPbVersion(resp.Objects[i], obj)
+		}
+		resp.Found[i] = true
+	}
+
+	return resp, nil
 }
 
 // =============================================================================
-// Spatial Queries
+// Index Building
 // =============================================================================
 
-// QueryRange queries objects in a bounding box
-func (s *UrbisServer) QueryRange(ctx context.Context, req *pb.RangeQueryRequest) (*pb.QueryResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+// Build builds the spatial index. Cancelling the call, or passing its
+// deadline, stops the build and leaves the index unbuilt.
+func (s *UrbisServer) Build(ctx context.Context, req *pb.BuildRequest) (*pb.BuildResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	
-	if req.Range == nil {
-		return nil, status.Error(codes.InvalidArgument, "range is required")
-	}
+	start := time.Now()
 	
-	region := urbis.MBR{
-		MinX: req.Range.MinX,
-		MinY: req.Range.MinY,
-		MaxX: req.Range.MaxX,
-		MaxY: req.Range.MaxY,
+	if err := idx.BuildContext(ctx); err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to build index: %v", err)
 	}
 	
-	start := time.Now()
-	result, err := idx.QueryRange(region)
 	elapsed := time.Since(start)
 	
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "query failed: %v", err)
-	}
-	
-	return &pb.QueryResponse{
-		Objects:     convertToPbObjects(result.Objects),
-		Count:       result.Count,
-		QueryTimeMs: float64(elapsed.Microseconds()) / 1000.0,
+	return &pb.BuildResponse{
+		Message:      "Index built successfully",
+		BuildTimeMs:  float64(elapsed.Microseconds()) / 1000.0,
+		Count:        idx.Count(),
+		Bounds:       indexBounds(idx),
+		BuildThreads: idx.GetStats().BuildThreads,
 	}, nil
 }
 
-// QueryPoint queries objects at a point
-func (s *UrbisServer) QueryPoint(ctx context.Context, req *pb.PointQueryRequest) (*pb.QueryResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+// BuildWithProgress builds the spatial index, streaming progress updates
+// and finishing with the same information Build returns. Like Build, it
+// stops when the call is cancelled.
+func (s *UrbisServer) BuildWithProgress(req *pb.BuildRequest, stream pb.UrbisService_BuildWithProgressServer) error {
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	
+	defer release()
+
 	start := time.Now()
-	result, err := idx.QueryPoint(req.X, req.Y)
-	elapsed := time.Since(start)
-	
+	lastPercent := -1.0
+	var sendErr error
+
+	err = idx.BuildProgressContext(stream.Context(), func(done, total uint64) {
+		percent := 100.0
+		if total > 0 {
+			percent = math.Floor(float64(done) * 100 / float64(total))
+		}
+		// Only send whole-percent changes, and leave 100% for the final message
+		if sendErr != nil || percent == lastPercent || percent >= 100 {
+			return
+		}
+		lastPercent = percent
+		sendErr = stream.Send(&pb.BuildProgressResponse{Done: done, Total: total, Percent: percent})
+	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "query failed: %v", err)
+		return status.Errorf(errorCode(err), "failed to build index: %v", err)
 	}
-	
-	return &pb.QueryResponse{
-		Objects:     convertToPbObjects(result.Objects),
-		Count:       result.Count,
-		QueryTimeMs: float64(elapsed.Microseconds()) / 1000.0,
-	}, nil
+	if sendErr != nil {
+		return sendErr
+	}
+
+	elapsed := time.Since(start)
+	count := idx.Count()
+
+	return stream.Send(&pb.BuildProgressResponse{
+		Done:    count,
+		Total:   count,
+		Percent: 100,
+		Result: &pb.BuildResponse{
+			Message:      "Index built successfully",
+			BuildTimeMs:  float64(elapsed.Microseconds()) / 1000.0,
+			Count:        count,
+			Bounds:       indexBounds(idx),
+			BuildThreads: idx.GetStats().BuildThreads,
+		},
+	})
 }
 
-// QueryKNN queries k nearest neighbors
-func (s *UrbisServer) QueryKNN(ctx context.Context, req *pb.KNNQueryRequest) (*pb.QueryResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+// Optimize optimizes the index. Like Build, it stops when the call is
+// cancelled or its deadline passes, but a built index stays built.
+func (s *UrbisServer) Optimize(ctx context.Context, req *pb.OptimizeRequest) (*pb.OptimizeResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	
-	start := time.Now()
-	result, err := idx.QueryKNN(req.X, req.Y, req.K)
-	elapsed := time.Since(start)
-	
+	report, err := idx.OptimizeContext(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "query failed: %v", err)
+		return nil, status.Errorf(errorCode(err), "failed to optimize index: %v", err)
 	}
 	
-	return &pb.QueryResponse{
-		Objects:     convertToPbObjects(result.Objects),
-		Count:       result.Count,
-		QueryTimeMs: float64(elapsed.Microseconds()) / 1000.0,
+	return &pb.OptimizeResponse{
+		Message:              "Index optimized successfully",
+		Before:               convertToPbStats(report.Before),
+		After:                convertToPbStats(report.After),
+		EstimatedSeeksBefore: report.SeeksBefore,
+		EstimatedSeeksAfter:  report.SeeksAfter,
 	}, nil
 }
 
-// QueryAdjacent queries objects in adjacent pages
-func (s *UrbisServer) QueryAdjacent(ctx context.Context, req *pb.RangeQueryRequest) (*pb.QueryResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+// Compact repacks an index onto as few pages as possible. It stops when
+// the call is cancelled or its deadline passes, leaving the index as it was.
+func (s *UrbisServer) Compact(ctx context.Context, req *pb.CompactRequest) (*pb.CompactResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
+
+	report, err := idx.CompactContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to compact index: %v", err)
+	}
+
+	return &pb.CompactResponse{
+		Message: "Index compacted successfully",
+		Before:  convertToPbStats(report.Before),
+		After:   convertToPbStats(report.After),
+	}, nil
+}
+
+// AutoTune recommends a page capacity for an index, rebuilding the index
+// with it when requested. Like Compact, it stops when the call is
+// cancelled or its deadline passes, leaving the index as it was.
+func (s *UrbisServer) AutoTune(ctx context.Context, req *pb.AutoTuneRequest) (*pb.AutoTuneResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	
+	queries := make([]urbis.MBR, len(req.SampleQueries))
+	for i, q := range req.SampleQueries {
+		if q == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "sample_queries[%d] is empty", i)
+		}
+		queries[i] = urbis.MBR{MinX: q.MinX, MinY: q.MinY, MaxX: q.MaxX, MaxY: q.MaxY}
+	}
 	
+	report, err := idx.AutoTuneConfigContext(ctx, queries, req.Apply)
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to tune index: %v", err)
+	}
+	if report.Applied {
+		s.recordState(func(m *manifest) error {
+			return m.setPageCapacity(req.IndexId, report.PageCapacity)
+		})
+	}
+	
+	candidates := make([]*pb.TuneCandidate, len(report.Candidates))
+	for i, c := range report.Candidates {
+		candidates[i] = &pb.TuneCandidate{
+			PageCapacity:    c.PageCapacity,
+			TotalPages:      c.TotalPages,
+			PageUtilization: c.PageUtilization,
+			AvgSeeks:        c.AvgSeeks,
+			AvgCostMs:       c.AvgCostMs,
+			Cost:            c.Cost,
+		}
+	}
+	
+	return &pb.AutoTuneResponse{
+		PageCapacity: report.PageCapacity,
+		Candidates:   candidates,
+		Applied:      report.Applied,
+	}, nil
+}
+
+// =============================================================================
+// Spatial Queries
+// =============================================================================
+
+// EstimateCount returns an upper bound on the objects in a range, counted
+// from page headers without reading any object
+func (s *UrbisServer) EstimateCount(ctx context.Context, req *pb.EstimateCountRequest) (*pb.EstimateCountResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if req.Range == nil {
+		return nil, status.Error(codes.InvalidArgument, "range is required")
+	}
+
+	count, err := idx.EstimateCount(urbis.MBR{
+		MinX: req.Range.MinX,
+		MinY: req.Range.MinY,
+		MaxX: req.Range.MaxX,
+		MaxY: req.Range.MaxY,
+	})
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to estimate count: %v", err)
+	}
+	return &pb.EstimateCountResponse{EstimatedCount: count}, nil
+}
+
+// QueryRange queries objects in a bounding box
+func (s *UrbisServer) QueryRange(ctx context.Context, req *pb.RangeQueryRequest) (*pb.QueryResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	if req.Range == nil {
 		return nil, status.Error(codes.InvalidArgument, "range is required")
 	}
@@ -415,31 +915,610 @@ func (s *UrbisServer) QueryAdjacent(ctx context.Context, req *pb.RangeQueryReque
 		MaxY: req.Range.MaxY,
 	}
 	
+	crs, err := parseQueryCRS(idx, req.QueryCrs, req.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	if region, err = crs.region(region); err != nil {
+		return nil, err
+	}
+	
+	structure, err := convertStructure(req.Structure)
+	if err != nil {
+		return nil, err
+	}
+	types, err := parseGeomTypes(req.GeomTypes)
+	if err != nil {
+		return nil, err
+	}
+	
+	start := time.Now()
+	result, err := runQuery(ctx, s, req.IndexId, func() (*urbis.ObjectList, error) {
+		if req.BestEffort {
+			return idx.QueryRangeBestEffort(region, structure)
+		}
+		return idx.QueryRangeUsing(region, structure)
+	})
+	elapsed := time.Since(start)
+	
+	if err != nil {
+		return nil, err
+	}
+	
+	objs, next, err := orderResults(types.filter(result.Objects), region, idx.CRS(), req)
+	if err != nil {
+		return nil, err
+	}
+	if objs, err = crs.results(objs); err != nil {
+		return nil, err
+	}
+	
+	resp := &pb.QueryResponse{
+		Objects:      convertToPbResults(objs, req.IncludeVersion),
+		ResultBounds: resultBounds(objs),
+		Count:        uint64(len(objs)),
+		QueryTimeMs:  float64(elapsed.Microseconds()) / 1000.0,
+		QueryStats:   convertToPbQueryStats(result.Stats),
+		Stale:        result.Stale,
+		NextCursor:   next,
+	}
+	if req.IncludePages {
+		for _, page := range result.Pages {
+			resp.Pages = append(resp.Pages, &pb.PageInfo{PageId: page.PageID, TrackId: page.TrackID})
+		}
+	}
+	for _, page := range result.FailedPages {
+		resp.Warnings = append(resp.Warnings, fmt.Sprintf("page %d failed checksum verification; its objects were skipped", page))
+	}
+	if err := encodeResults(idx, &resp.Objects, &resp.Geojson, objs, req.Encoding, req.FieldMask); err != nil {
+		return nil, err
+	}
+	if err := crs.geojson(&resp.Geojson); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// MultiQueryRange queries several bounding boxes in one call
+func (s *UrbisServer) MultiQueryRange(ctx context.Context, req *pb.MultiRangeQueryRequest) (*pb.MultiQueryResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	regions := make([]urbis.MBR, len(req.Ranges))
+	for i, r := range req.Ranges {
+		if r == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "range %d is empty", i)
+		}
+		regions[i] = urbis.MBR{MinX: r.MinX, MinY: r.MinY, MaxX: r.MaxX, MaxY: r.MaxY}
+	}
+
+	structure, err := convertStructure(req.Structure)
+	if err != nil {
+		return nil, err
+	}
+	types, err := parseGeomTypes(req.GeomTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	lists, err := runQuery(ctx, s, req.IndexId, func() ([]*urbis.ObjectList, error) {
+		return idx.QueryRangesUsing(regions, structure)
+	})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.MultiQueryResponse{
+		Results:     make(map[uint32]*pb.RangeResult, len(lists)),
+		QueryTimeMs: float64(elapsed.Microseconds()) / 1000.0,
+	}
+	seen := make(map[uint64]bool)
+	for i, list := range lists {
+		objs := types.filter(list.Objects)
+		if req.Deduplicate {
+			found := objs
+			objs = objs[:0]
+			for _, obj := range found {
+				if !seen[obj.ID] {
+					seen[obj.ID] = true
+					objs = append(objs, obj)
+				}
+			}
+		}
+
+		result := &pb.RangeResult{
+			Objects:    convertToPbResults(objs, req.IncludeVersion),
+			Count:      uint64(len(objs)),
+			QueryStats: convertToPbQueryStats(list.Stats),
+			Stale:      list.Stale,
+		}
+		if err := encodeResults(idx, &result.Objects, &result.Geojson, objs, req.Encoding, req.FieldMask); err != nil {
+			return nil, err
+		}
+		resp.Results[uint32(i)] = result
+		resp.Count += result.Count
+	}
+	return resp, nil
+}
+
+// QueryPoint queries objects at a point
+func (s *UrbisServer) QueryPoint(ctx context.Context, req *pb.PointQueryRequest) (*pb.QueryResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	crs, err := parseQueryCRS(idx, req.QueryCrs, req.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	x, y, err := crs.point(req.X, req.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	structure, err := convertStructure(req.Structure)
+	if err != nil {
+		return nil, err
+	}
+	types, err := parseGeomTypes(req.GeomTypes)
+	if err != nil {
+		return nil, err
+	}
+	
+	start := time.Now()
+	result, err := runQuery(ctx, s, req.IndexId, func() (*urbis.ObjectList, error) {
+		return idx.QueryPointUsing(x, y, structure)
+	})
+	elapsed := time.Since(start)
+	
+	if err != nil {
+		return nil, err
+	}
+	
+	objs, err := crs.results(types.filter(result.Objects))
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.QueryResponse{
+		Objects:      convertToPbResults(objs, req.IncludeVersion),
+		ResultBounds: resultBounds(objs),
+		Count:        uint64(len(objs)),
+		QueryTimeMs:  float64(elapsed.Microseconds()) / 1000.0,
+		QueryStats:   convertToPbQueryStats(result.Stats),
+		Stale:        result.Stale,
+	}
+	if err := encodeResults(idx, &resp.Objects, &resp.Geojson, objs, req.Encoding, req.FieldMask); err != nil {
+		return nil, err
+	}
+	if err := crs.geojson(&resp.Geojson); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// QueryContaining queries polygons whose interior contains a point
+func (s *UrbisServer) QueryContaining(ctx context.Context, req *pb.PointQueryRequest) (*pb.QueryResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	crs, err := parseQueryCRS(idx, req.QueryCrs, req.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	x, y, err := crs.point(req.X, req.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	structure, err := convertStructure(req.Structure)
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := parseGeomTypes(req.GeomTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := runQuery(ctx, s, req.IndexId, func() (*urbis.ObjectList, error) {
+		return idx.QueryContainingUsing(x, y, structure)
+	})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		return nil, err
+	}
+
+	objs, err := crs.results(types.filter(result.Objects))
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.QueryResponse{
+		Objects:      convertToPbResults(objs, req.IncludeVersion),
+		ResultBounds: resultBounds(objs),
+		Count:        uint64(len(objs)),
+		QueryTimeMs:  float64(elapsed.Microseconds()) / 1000.0,
+		QueryStats:   convertToPbQueryStats(result.Stats),
+		Stale:        result.Stale,
+	}
+	if err := encodeResults(idx, &resp.Objects, &resp.Geojson, objs, req.Encoding, req.FieldMask); err != nil {
+		return nil, err
+	}
+	if err := crs.geojson(&resp.Geojson); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// QueryBuffered queries objects within a distance of a geometry
+func (s *UrbisServer) QueryBuffered(ctx context.Context, req *pb.BufferQueryRequest) (*pb.QueryResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	geometry := convertFromPbGeometry(req.Geometry)
+	if geometry == nil {
+		return nil, status.Error(codes.InvalidArgument, "geometry is required")
+	}
+
+	types, err := parseGeomTypes(req.GeomTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := runQuery(ctx, s, req.IndexId, func() (*urbis.ObjectList, error) {
+		return idx.QueryBuffered(geometry, req.Distance)
+	})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		return nil, err
+	}
+
+	objs := types.filter(result.Objects)
+	resp := &pb.QueryResponse{
+		Objects:      convertToPbResults(objs, req.IncludeVersion),
+		ResultBounds: resultBounds(objs),
+		Count:        uint64(len(objs)),
+		QueryTimeMs:  float64(elapsed.Microseconds()) / 1000.0,
+		QueryStats:   convertToPbQueryStats(result.Stats),
+		Stale:        result.Stale,
+	}
+	if err := encodeResults(idx, &resp.Objects, &resp.Geojson, objs, req.Encoding, req.FieldMask); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// QueryContainingPolygon queries polygons that contain a polygon
+func (s *UrbisServer) QueryContainingPolygon(ctx context.Context, req *pb.ContainingPolygonQueryRequest) (*pb.QueryResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	polygon := make([]urbis.Point, len(req.Polygon))
+	for i, p := range req.Polygon {
+		polygon[i] = urbis.Point{X: p.GetX(), Y: p.GetY()}
+	}
+
+	types, err := parseGeomTypes(req.GeomTypes)
+	if err != nil {
+		return nil, err
+	}
+
 	start := time.Now()
-	result, err := idx.QueryAdjacent(region)
+	result, err := runQuery(ctx, s, req.IndexId, func() (*urbis.ObjectList, error) {
+		return idx.QueryContainingPolygon(polygon)
+	})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		return nil, err
+	}
+
+	objs := types.filter(result.Objects)
+	resp := &pb.QueryResponse{
+		Objects:      convertToPbResults(objs, req.IncludeVersion),
+		ResultBounds: resultBounds(objs),
+		Count:        uint64(len(objs)),
+		QueryTimeMs:  float64(elapsed.Microseconds()) / 1000.0,
+		QueryStats:   convertToPbQueryStats(result.Stats),
+		Stale:        result.Stale,
+	}
+	if err := encodeResults(idx, &resp.Objects, &resp.Geojson, objs, req.Encoding, req.FieldMask); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// QueryKNN queries k nearest neighbors, ranked by the requested distance
+// metric
+func (s *UrbisServer) QueryKNN(ctx context.Context, req *pb.KNNQueryRequest) (*pb.QueryResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	crs, err := parseQueryCRS(idx, req.QueryCrs, req.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	x, y, err := crs.point(req.X, req.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := pb.DistanceMetric_name[int32(req.DistanceMetric)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown distance metric %d", req.DistanceMetric)
+	}
+
+	type neighbors struct {
+		list      *urbis.ObjectList
+		distances []float64
+	}
+	start := time.Now()
+	result, err := runQuery(ctx, s, req.IndexId, func() (neighbors, error) {
+		list, distances, err := idx.QueryKNNBy(x, y, req.K, urbis.DistanceMetric(req.DistanceMetric))
+		return neighbors{list, distances}, err
+	})
 	elapsed := time.Since(start)
 	
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "query failed: %v", err)
+		return nil, err
 	}
 	
-	return &pb.QueryResponse{
-		Objects:     convertToPbObjects(result.Objects),
-		Count:       result.Count,
+	objs, err := crs.results(result.list.Objects)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.QueryResponse{
+		Objects:      convertToPbResults(objs, req.IncludeVersion),
+		ResultBounds: resultBounds(objs),
+		Count:        result.list.Count,
+		QueryTimeMs:  float64(elapsed.Microseconds()) / 1000.0,
+		QueryStats:   convertToPbQueryStats(result.list.Stats),
+		Distances:    result.distances,
+	}
+	if err := encodeResults(idx, &resp.Objects, &resp.Geojson, objs, req.Encoding, req.FieldMask); err != nil {
+		return nil, err
+	}
+	if err := crs.geojson(&resp.Geojson); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Nearest finds the single object nearest to a point
+func (s *UrbisServer) Nearest(ctx context.Context, req *pb.NearestRequest) (*pb.NearestResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	type nearest struct {
+		obj      *urbis.SpatialObject
+		distance float64
+	}
+	start := time.Now()
+	result, err := runQuery(ctx, s, req.IndexId, func() (nearest, error) {
+		obj, distance, err := idx.Nearest(req.X, req.Y)
+		return nearest{obj, distance}, err
+	})
+	elapsed := time.Since(start)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.NearestResponse{
+		Object:      convertToPbResults([]*urbis.SpatialObject{result.obj}, req.IncludeVersion)[0],
+		Distance:    result.distance,
 		QueryTimeMs: float64(elapsed.Microseconds()) / 1000.0,
 	}, nil
 }
 
+// QueryAdjacent queries objects in adjacent pages
+func (s *UrbisServer) QueryAdjacent(ctx context.Context, req *pb.RangeQueryRequest) (*pb.QueryResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if req.Range == nil {
+		return nil, status.Error(codes.InvalidArgument, "range is required")
+	}
+	
+	region := urbis.MBR{
+		MinX: req.Range.MinX,
+		MinY: req.Range.MinY,
+		MaxX: req.Range.MaxX,
+		MaxY: req.Range.MaxY,
+	}
+	crs, err := parseQueryCRS(idx, req.QueryCrs, req.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	if region, err = crs.region(region); err != nil {
+		return nil, err
+	}
+	types, err := parseGeomTypes(req.GeomTypes)
+	if err != nil {
+		return nil, err
+	}
+	
+	start := time.Now()
+	result, err := runQuery(ctx, s, req.IndexId, func() (*urbis.ObjectList, error) {
+		return idx.QueryAdjacent(region)
+	})
+	elapsed := time.Since(start)
+	
+	if err != nil {
+		return nil, err
+	}
+	
+	objs, next, err := orderResults(types.filter(result.Objects), region, idx.CRS(), req)
+	if err != nil {
+		return nil, err
+	}
+	if objs, err = crs.results(objs); err != nil {
+		return nil, err
+	}
+	
+	resp := &pb.QueryResponse{
+		Objects:      convertToPbResults(objs, req.IncludeVersion),
+		ResultBounds: resultBounds(objs),
+		Count:        uint64(len(objs)),
+		QueryTimeMs:  float64(elapsed.Microseconds()) / 1000.0,
+		QueryStats:   convertToPbQueryStats(result.Stats),
+		NextCursor:   next,
+	}
+	if err := encodeResults(idx, &resp.Objects, &resp.Geojson, objs, req.Encoding, req.FieldMask); err != nil {
+		return nil, err
+	}
+	if err := crs.geojson(&resp.Geojson); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// QueryChangedSince returns objects inserted or modified at or after a time,
+// oldest change first, with their versions filled in
+func (s *UrbisServer) QueryChangedSince(ctx context.Context, req *pb.ChangedSinceRequest) (*pb.QueryResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	start := time.Now()
+	result, err := runQuery(ctx, s, req.IndexId, func() (*urbis.ObjectList, error) {
+		return idx.QueryChangedSince(time.UnixMilli(req.SinceMs))
+	})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.QueryResponse{
+		Objects:      convertToPbResults(result.Objects, true),
+		ResultBounds: resultBounds(result.Objects),
+		Count:        result.Count,
+		QueryTimeMs:  float64(elapsed.Microseconds()) / 1000.0,
+		QueryStats:   convertToPbQueryStats(result.Stats),
+	}
+	if err := encodeResults(idx, &resp.Objects, &resp.Geojson, result.Objects, req.Encoding, req.FieldMask); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// QueryByProperty queries objects by an indexed property value
+func (s *UrbisServer) QueryByProperty(ctx context.Context, req *pb.PropertyQueryRequest) (*pb.QueryResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if req.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	types, err := parseGeomTypes(req.GeomTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := runQuery(ctx, s, req.IndexId, func() (*urbis.ObjectList, error) {
+		return idx.QueryByProperty(req.Key, req.Value)
+	})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		return nil, err
+	}
+
+	objs := types.filter(result.Objects)
+	resp := &pb.QueryResponse{
+		Objects:      convertToPbResults(objs, req.IncludeVersion),
+		ResultBounds: resultBounds(objs),
+		Count:        uint64(len(objs)),
+		QueryTimeMs:  float64(elapsed.Microseconds()) / 1000.0,
+		QueryStats:   convertToPbQueryStats(result.Stats),
+	}
+	if err := encodeResults(idx, &resp.Objects, &resp.Geojson, objs, req.Encoding, req.FieldMask); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ConvexHull returns the convex hull of the objects in a region
+func (s *UrbisServer) ConvexHull(ctx context.Context, req *pb.ConvexHullRequest) (*pb.ConvexHullResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if req.Region == nil {
+		return nil, status.Error(codes.InvalidArgument, "region is required")
+	}
+
+	region := urbis.MBR{
+		MinX: req.Region.MinX,
+		MinY: req.Region.MinY,
+		MaxX: req.Region.MaxX,
+		MaxY: req.Region.MaxY,
+	}
+
+	hull, err := runQuery(ctx, s, req.IndexId, func() ([]urbis.Point, error) {
+		return idx.ConvexHull(region)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ConvexHullResponse{Hull: convertToPbPoints(hull)}, nil
+}
+
 // =============================================================================
 // Disk-Aware Operations
 // =============================================================================
 
 // FindAdjacentPages finds adjacent pages to a region
 func (s *UrbisServer) FindAdjacentPages(ctx context.Context, req *pb.AdjacentPagesRequest) (*pb.AdjacentPagesResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	done, err := s.acquireQuery(ctx, req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer done()
 	
 	if req.Region == nil {
 		return nil, status.Error(codes.InvalidArgument, "region is required")
@@ -454,7 +1533,7 @@ func (s *UrbisServer) FindAdjacentPages(ctx context.Context, req *pb.AdjacentPag
 	
 	result, err := idx.FindAdjacentPages(region)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to find adjacent pages: %v", err)
+		return nil, status.Errorf(errorCode(err), "failed to find adjacent pages: %v", err)
 	}
 	
 	pages := make([]*pb.PageInfo, len(result.Pages))
@@ -464,11 +1543,158 @@ func (s *UrbisServer) FindAdjacentPages(ctx context.Context, req *pb.AdjacentPag
 			TrackId: p.TrackID,
 		}
 	}
-	
-	return &pb.AdjacentPagesResponse{
-		Pages:          pages,
-		Count:          result.Count,
-		EstimatedSeeks: result.EstimatedSeeks,
+	
+	return &pb.AdjacentPagesResponse{
+		Pages:          pages,
+		Count:          result.Count,
+		EstimatedSeeks: result.EstimatedSeeks,
+
+		EstimatedCostMs: result.EstimatedCostMs,
+	}, nil
+}
+
+// PrefetchRegion loads the pages around a region into the page cache
+func (s *UrbisServer) PrefetchRegion(ctx context.Context, req *pb.PrefetchRegionRequest) (*pb.PrefetchRegionResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	
+	if req.Region == nil {
+		return nil, status.Error(codes.InvalidArgument, "region is required")
+	}
+	
+	region := urbis.MBR{
+		MinX: req.Region.MinX,
+		MinY: req.Region.MinY,
+		MaxX: req.Region.MaxX,
+		MaxY: req.Region.MaxY,
+	}
+	
+	if err := idx.PrefetchRegion(region); err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to prefetch region: %v", err)
+	}
+	
+	return &pb.PrefetchRegionResponse{
+		Message: "Region prefetched",
+	}, nil
+}
+
+// GetPageGraph returns the pages of an index and which of them touch,
+// optionally a batch of pages at a time
+func (s *UrbisServer) GetPageGraph(ctx context.Context, req *pb.PageGraphRequest) (*pb.PageGraphResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	graph, err := idx.GetPageGraph()
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to get page graph: %v", err)
+	}
+
+	nodes, edges := graph.Nodes, graph.Edges
+	if req.Cursor != "" {
+		_, after, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		nodes = nodes[sort.Search(len(nodes), func(i int) bool { return uint64(nodes[i].PageID) > after }):]
+	}
+
+	resp := &pb.PageGraphResponse{
+		TotalNodes: uint64(len(graph.Nodes)),
+		TotalEdges: uint64(len(graph.Edges)),
+	}
+	if req.Limit > 0 && len(nodes) > int(req.Limit) {
+		nodes = nodes[:req.Limit]
+		resp.NextCursor = encodeCursor(0, uint64(nodes[len(nodes)-1].PageID))
+	}
+
+	// Edges are ordered by From, so those starting in this batch are contiguous
+	if len(nodes) > 0 {
+		first, last := nodes[0].PageID, nodes[len(nodes)-1].PageID
+		lo := sort.Search(len(edges), func(i int) bool { return edges[i].From >= first })
+		hi := sort.Search(len(edges), func(i int) bool { return edges[i].From > last })
+		edges = edges[lo:hi]
+	} else {
+		edges = nil
+	}
+
+	resp.Nodes = make([]*pb.PageInfo, len(nodes))
+	for i, n := range nodes {
+		resp.Nodes[i] = &pb.PageInfo{
+			PageId:      n.PageID,
+			TrackId:     n.TrackID,
+			ObjectCount: n.ObjectCount,
+			Extent:      convertToPbMBR(n.Extent),
+		}
+	}
+	resp.Edges = make([]*pb.PageEdge, len(edges))
+	for i, e := range edges {
+		resp.Edges[i] = &pb.PageEdge{
+			FromPageId:     e.From,
+			ToPageId:       e.To,
+			EstimatedSeeks: e.Seeks,
+		}
+	}
+	return resp, nil
+}
+
+// GetTreeStructure returns the nodes of an index's KD-tree or quadtree, down
+// to the requested depth
+func (s *UrbisServer) GetTreeStructure(ctx context.Context, req *pb.TreeStructureRequest) (*pb.TreeStructureResponse, error) {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	structure, err := convertStructure(req.Structure)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := idx.GetTreeStructure(structure, int(req.MaxDepth))
+	if err != nil {
+		return nil, status.Errorf(errorCode(err), "failed to get tree structure: %v", err)
+	}
+
+	resp := &pb.TreeStructureResponse{Nodes: make([]*pb.TreeNode, len(nodes))}
+	for i, n := range nodes {
+		resp.Nodes[i] = &pb.TreeNode{
+			Depth:       uint32(n.Depth),
+			Parent:      int32(n.Parent),
+			Bounds:      convertToPbMBR(n.Bounds),
+			ObjectCount: n.ObjectCount,
+			ItemCount:   n.ItemCount,
+			SplitAxis:   int32(n.SplitAxis),
+			SplitValue:  n.SplitValue,
+			Leaf:        n.Leaf,
+		}
+	}
+	return resp, nil
+}
+
+// =============================================================================
+// Health
+// =============================================================================
+
+// IndexReady reports whether an index exists and has been built
+func (s *UrbisServer) IndexReady(ctx context.Context, req *pb.IndexReadyRequest) (*pb.IndexReadyResponse, error) {
+	if req.IndexId == "" {
+		return nil, status.Error(codes.InvalidArgument, "index_id is required")
+	}
+
+	val, ok := s.indexes.Load(req.IndexId)
+	if !ok {
+		return &pb.IndexReadyResponse{Exists: false}, nil
+	}
+
+	return &pb.IndexReadyResponse{
+		Exists: true,
+		Built:  val.(*urbis.Index).IsBuilt(),
 	}, nil
 }
 
@@ -478,39 +1704,24 @@ func (s *UrbisServer) FindAdjacentPages(ctx context.Context, req *pb.AdjacentPag
 
 // GetStats retrieves index statistics
 func (s *UrbisServer) GetStats(ctx context.Context, req *pb.StatsRequest) (*pb.StatsResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
-	
-	stats := idx.GetStats()
+	defer release()
 	
 	return &pb.StatsResponse{
-		Stats: &pb.Stats{
-			TotalObjects:       stats.TotalObjects,
-			TotalBlocks:        stats.TotalBlocks,
-			TotalPages:         stats.TotalPages,
-			TotalTracks:        stats.TotalTracks,
-			AvgObjectsPerPage:  stats.AvgObjectsPerPage,
-			PageUtilization:    stats.PageUtilization,
-			KdtreeDepth:        stats.KDTreeDepth,
-			QuadtreeDepth:      stats.QuadtreeDepth,
-			Bounds: &pb.MBR{
-				MinX: stats.Bounds.MinX,
-				MinY: stats.Bounds.MinY,
-				MaxX: stats.Bounds.MaxX,
-				MaxY: stats.Bounds.MaxY,
-			},
-		},
+		Stats: convertToPbStats(idx.GetStats()),
 	}, nil
 }
 
 // GetCount returns the object count
 func (s *UrbisServer) GetCount(ctx context.Context, req *pb.CountRequest) (*pb.CountResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	
 	return &pb.CountResponse{
 		Count: idx.Count(),
@@ -519,44 +1730,62 @@ func (s *UrbisServer) GetCount(ctx context.Context, req *pb.CountRequest) (*pb.C
 
 // GetBounds returns the spatial bounds
 func (s *UrbisServer) GetBounds(ctx context.Context, req *pb.BoundsRequest) (*pb.BoundsResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	
 	bounds := idx.Bounds()
-	
-	return &pb.BoundsResponse{
-		Bounds: &pb.MBR{
-			MinX: bounds.MinX,
-			MinY: bounds.MinY,
-			MaxX: bounds.MaxX,
-			MaxY: bounds.MaxY,
-		},
-	}, nil
+	resp := &pb.BoundsResponse{Bounds: convertToPbMBR(bounds)}
+	if width, height, ok := bounds.ExtentMeters(idx.CRS()); ok {
+		resp.WidthMeters, resp.HeightMeters = &width, &height
+	}
+	return resp, nil
 }
 
 // =============================================================================
 // Persistence
 // =============================================================================
 
-// Save saves the index to a file
+// Save saves the index to a file. A call whose deadline has already passed
+// is refused; once started, the save runs to completion.
 func (s *UrbisServer) Save(ctx context.Context, req *pb.SaveRequest) (*pb.SaveResponse, error) {
-	idx, err := s.getIndex(req.IndexId)
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	
+	path, err := s.savePath(req.IndexId, req.Path)
 	if err != nil {
 		return nil, err
 	}
 	
-	if err := idx.Save(req.Path); err != nil {
+	// A save cannot stop midway without leaving a partial file, so only a
+	// call whose deadline has already passed is refused
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	changes := idx.ChangeCount()
+	if err := idx.Save(path); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to save index: %v", err)
 	}
+	if path == s.dataFileOf(req.IndexId) {
+		s.synced.Store(req.IndexId, changes)
+	}
+	s.recordState(func(m *manifest) error {
+		return m.setDataFile(req.IndexId, path)
+	})
 	
 	return &pb.SaveResponse{
 		Message: "Index saved successfully",
+		Path:    path,
 	}, nil
 }
 
-// Load loads an index from a file
+// Load loads an index from a file. The load runs to completion, but the
+// index is discarded if the call's deadline passed meanwhile.
 func (s *UrbisServer) Load(ctx context.Context, req *pb.LoadIndexRequest) (*pb.LoadIndexResponse, error) {
 	if req.IndexId == "" {
 		return nil, status.Error(codes.InvalidArgument, "index_id is required")
@@ -567,15 +1796,201 @@ func (s *UrbisServer) Load(ctx context.Context, req *pb.LoadIndexRequest) (*pb.L
 		return nil, status.Errorf(codes.AlreadyExists, "index %q already exists", req.IndexId)
 	}
 	
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
 	idx, err := urbis.Load(req.Path)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to load index: %v", err)
+		return nil, status.Errorf(errorCode(err), "failed to load index: %v", err)
+	}
+	// The load itself runs to completion; drop it if the client gave up
+	// meanwhile, so a retry does not find the index already there
+	if err := ctx.Err(); err != nil {
+		idx.Close()
+		return nil, status.FromContextError(err).Err()
+	}
+	if req.ReadOnly {
+		idx.MarkReadOnly()
 	}
 	
-	s.indexes.Store(req.IndexId, idx)
+	if _, loaded := s.indexes.LoadOrStore(req.IndexId, idx); loaded {
+		idx.Close()
+		return nil, status.Errorf(codes.AlreadyExists, "index %q already exists", req.IndexId)
+	}
+	s.recordState(func(m *manifest) error {
+		if err := m.setDataFile(req.IndexId, req.Path); err != nil {
+			return err
+		}
+		if req.ReadOnly {
+			return m.setReadOnly(req.IndexId)
+		}
+		return nil
+	})
 	
 	return &pb.LoadIndexResponse{
 		Message: "Index loaded successfully",
+		Count:   idx.Count(),
+		Bounds:  indexBounds(idx),
+	}, nil
+}
+
+// streamChunkSize bounds each IndexChunk so snapshots stay well under the
+// message size limit
+const streamChunkSize = 1 << 20
+
+// StreamSave streams a serialized index to the client in chunks
+func (s *UrbisServer) StreamSave(req *pb.StreamSaveRequest, stream pb.UrbisService_StreamSaveServer) error {
+	idx, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	w := bufio.NewWriterSize(chunkWriter{stream: stream}, streamChunkSize)
+	if _, err := idx.WriteTo(w); err != nil {
+		return status.Errorf(codes.Internal, "failed to save index: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// chunkWriter sends each write as one IndexChunk
+type chunkWriter struct {
+	stream pb.UrbisService_StreamSaveServer
+}
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&pb.IndexChunk{Chunk: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// StreamLoad loads an index from a serialized snapshot streamed by the client
+func (s *UrbisServer) StreamLoad(stream pb.UrbisService_StreamLoadServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "index_id is required")
+	}
+	if err != nil {
+		return err
+	}
+	if first.IndexId == "" {
+		return status.Error(codes.InvalidArgument, "index_id is required")
+	}
+	if _, ok := s.indexes.Load(first.IndexId); ok {
+		return status.Errorf(codes.AlreadyExists, "index %q already exists", first.IndexId)
+	}
+
+	next := func() ([]byte, error) {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return msg.Chunk, nil
+	}
+	idx, err := urbis.LoadReader(&chunkReader{next: next, buf: first.Chunk})
+	if err != nil {
+		return status.Errorf(errorCode(err), "failed to load index: %v", err)
+	}
+
+	if _, loaded := s.indexes.LoadOrStore(first.IndexId, idx); loaded {
+		idx.Close()
+		return status.Errorf(codes.AlreadyExists, "index %q already exists", first.IndexId)
+	}
+	s.recordState(func(m *manifest) error {
+		return m.put(manifestEntry{IndexID: first.IndexId})
+	})
+
+	return stream.SendAndClose(&pb.LoadIndexResponse{
+		Message: "Index loaded successfully",
+		Count:   idx.Count(),
+		Bounds:  indexBounds(idx),
+	})
+}
+
+// ReloadIndex builds a fresh index from a saved data file or GeoJSON and
+// swaps it in under the same ID. Requests already holding the old index
+// finish against it; it is closed once they have released it.
+func (s *UrbisServer) ReloadIndex(ctx context.Context, req *pb.ReloadIndexRequest) (*pb.ReloadIndexResponse, error) {
+	old, release, err := s.getIndex(req.IndexId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var idx *urbis.Index
+	var config *urbis.Config
+	switch src := req.Source.(type) {
+	case *pb.ReloadIndexRequest_DataFile:
+		idx, err = urbis.Load(src.DataFile)
+		if err != nil {
+			return nil, status.Errorf(errorCode(err), "failed to load index: %v", err)
+		}
+	case *pb.ReloadIndexRequest_GeojsonPath, *pb.ReloadIndexRequest_Geojson:
+		if config, err = convertConfig(s.withDefaults(req.Config)); err != nil {
+			return nil, err
+		}
+		if err := s.checkDataPath(req.IndexId, config); err != nil {
+			return nil, err
+		}
+		if idx, err = urbis.NewIndex(writable(config)); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create index: %v", err)
+		}
+		if path, ok := src.(*pb.ReloadIndexRequest_GeojsonPath); ok {
+			err = idx.LoadGeoJSON(path.GeojsonPath)
+		} else {
+			err = idx.LoadGeoJSONString(src.(*pb.ReloadIndexRequest_Geojson).Geojson)
+		}
+		if err != nil {
+			idx.Close()
+			return nil, status.Errorf(errorCode(err), "failed to load GeoJSON: %v", err)
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "a data_file, geojson_path or geojson source is required")
+	}
+
+	if req.Build {
+		if err := idx.Build(); err != nil {
+			idx.Close()
+			return nil, status.Errorf(codes.Internal, "failed to build index: %v", err)
+		}
+	}
+	// A read-only replica stays read-only across reloads
+	readOnly := old.ReadOnly() || (config != nil && config.ReadOnly)
+	if readOnly {
+		idx.MarkReadOnly()
+	}
+
+	if !s.indexes.CompareAndSwap(req.IndexId, old, idx) {
+		idx.Close()
+		return nil, status.Errorf(codes.Aborted, "index %q was replaced or destroyed during reload", req.IndexId)
+	}
+	// Requests holding the old index, this one included, finish against it
+	s.leases.retire(old)
+	s.synced.Delete(req.IndexId)
+	if _, ok := req.Source.(*pb.ReloadIndexRequest_DataFile); ok {
+		s.configs.Delete(req.IndexId)
+	} else {
+		s.storeConfig(req.IndexId, config)
+	}
+
+	s.recordState(func(m *manifest) error {
+		if src, ok := req.Source.(*pb.ReloadIndexRequest_DataFile); ok {
+			if err := m.put(manifestEntry{IndexID: req.IndexId, ReadOnly: readOnly}); err != nil {
+				return err
+			}
+			return m.setDataFile(req.IndexId, src.DataFile)
+		}
+		return m.put(manifestEntry{IndexID: req.IndexId, Config: config, ReadOnly: readOnly})
+	})
+
+	return &pb.ReloadIndexResponse{
+		Message: "Index reloaded successfully",
+		Count:   idx.Count(),
+		Bounds:  indexBounds(idx),
 	}, nil
 }
 
@@ -583,6 +1998,148 @@ func (s *UrbisServer) Load(ctx context.Context, req *pb.LoadIndexRequest) (*pb.L
 // Helper Functions
 // =============================================================================
 
+// convertConfig converts an optional protobuf config, returning nil for
+// the library defaults
+func convertConfig(c *pb.Config) (*urbis.Config, error) {
+	if c == nil {
+		return nil, nil
+	}
+	if p := c.SnapPrecision; p < 0 || math.IsNaN(p) || math.IsInf(p, 0) {
+		return nil, status.Errorf(codes.InvalidArgument, "snap_precision must be a finite non-negative grid size, got %v", p)
+	}
+	if t := c.SimplifyTolerance; t < 0 || math.IsNaN(t) || math.IsInf(t, 0) {
+		return nil, status.Errorf(codes.InvalidArgument, "simplify_tolerance must be a finite non-negative distance, got %v", t)
+	}
+	if t := c.ValidationTolerance; t < 0 || math.IsNaN(t) || math.IsInf(t, 0) {
+		return nil, status.Errorf(codes.InvalidArgument, "validation_tolerance must be a finite non-negative distance, got %v", t)
+	}
+	seekCost := convertSeekCost(c.SeekCost)
+	if err := seekCost.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid seek_cost: %v", err)
+	}
+	if !urbis.IsValidBlockSize(c.BlockSize) {
+		return nil, status.Errorf(codes.InvalidArgument, "block_size must be 0 (default) or a power of two from %d to %d, got %d",
+			urbis.MinBlockSize, urbis.MaxBlockSize, c.BlockSize)
+	}
+	if !urbis.IsSupportedCRS(int(c.Crs)) {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported crs EPSG:%d (want 4326 or 3857)", c.Crs)
+	}
+	if _, ok := pb.PolygonValidation_name[int32(c.PolygonValidation)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown polygon_validation %d", c.PolygonValidation)
+	}
+	if err := validateMBR(c.ValidBounds, false); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "valid_bounds: %v", err)
+	}
+	var validBounds urbis.MBR
+	if b := c.ValidBounds; b != nil {
+		validBounds = urbis.MBR{MinX: b.MinX, MinY: b.MinY, MaxX: b.MaxX, MaxY: b.MaxY}
+	}
+	var schema []urbis.PropertyRule
+	for i, r := range c.PropertySchema {
+		if r.Key == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "property_schema[%d]: key is required", i)
+		}
+		if _, ok := pb.PropertyType_name[int32(r.Type)]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "property_schema[%d]: unknown type %d", i, r.Type)
+		}
+		schema = append(schema, urbis.PropertyRule{
+			Key:      r.Key,
+			Required: r.Required,
+			Type:     urbis.PropertyType(r.Type),
+			NonEmpty: r.NonEmpty,
+		})
+	}
+
+	return &urbis.Config{
+		BlockSize:      c.BlockSize,
+		PageCapacity:   c.PageCapacity,
+		CacheSize:      c.CacheSize,
+		EnableQuadtree: c.EnableQuadtree,
+		Persist:        c.Persist,
+		DataPath:       c.DataPath,
+		SnapPrecision:  c.SnapPrecision,
+		DedupPoints:    c.DedupPoints,
+		CRS:            int(c.Crs),
+
+		SimplifyTolerance: c.SimplifyTolerance,
+		PolygonValidation: urbis.ValidationMode(c.PolygonValidation),
+		IndexedProperties: c.IndexedProperties,
+		SeekCost:          seekCost,
+
+		ValidationTolerance: c.ValidationTolerance,
+		PropertySchema:      schema,
+		BuildThreads:        int(c.BuildThreads),
+		Seed:                c.Seed,
+		ReadOnly:            c.ReadOnly,
+		KeepDuplicates:      c.KeepDuplicates,
+
+		AutoRebuildThreshold: c.AutoRebuildThreshold,
+		ValidBounds:          validBounds,
+	}, nil
+}
+
+// convertSeekCost converts an optional protobuf cost model; unset means a
+// hard disk
+func convertSeekCost(m *pb.SeekCostModel) urbis.SeekCostModel {
+	if m == nil {
+		return urbis.SeekCostModel{}
+	}
+	return urbis.SeekCostModel{
+		Storage:      urbis.Storage(m.Storage),
+		SeekTime:     time.Duration(m.SeekMs * float64(time.Millisecond)),
+		TransferRate: m.TransferMbS,
+	}
+}
+
+// convertToPbConfig converts a Go index Config to protobuf
+func convertToPbConfig(c *urbis.Config) *pb.Config {
+	config := &pb.Config{
+		BlockSize:         c.BlockSize,
+		PageCapacity:      c.PageCapacity,
+		CacheSize:         c.CacheSize,
+		EnableQuadtree:    c.EnableQuadtree,
+		Persist:           c.Persist,
+		DataPath:          c.DataPath,
+		SnapPrecision:     c.SnapPrecision,
+		DedupPoints:       c.DedupPoints,
+		Crs:               int32(c.CRS),
+		PolygonValidation: pb.PolygonValidation(c.PolygonValidation),
+		IndexedProperties: c.IndexedProperties,
+		SimplifyTolerance: c.SimplifyTolerance,
+
+		ValidationTolerance: c.ValidationTolerance,
+		PropertySchema:      convertToPbSchema(c.PropertySchema),
+		BuildThreads:        uint32(c.BuildThreads),
+		Seed:                c.Seed,
+		ReadOnly:            c.ReadOnly,
+		KeepDuplicates:      c.KeepDuplicates,
+
+		AutoRebuildThreshold: c.AutoRebuildThreshold,
+		SeekCost: &pb.SeekCostModel{
+			Storage:     pb.StorageKind(c.SeekCost.Storage),
+			SeekMs:      float64(c.SeekCost.SeekTime) / float64(time.Millisecond),
+			TransferMbS: c.SeekCost.TransferRate,
+		},
+	}
+	if c.ValidBounds != (urbis.MBR{}) {
+		config.ValidBounds = convertToPbMBR(c.ValidBounds)
+	}
+	return config
+}
+
+func convertToPbSchema(rules []urbis.PropertyRule) []*pb.PropertyRule {
+	var result []*pb.PropertyRule
+	for _, r := range rules {
+		result = append(result, &pb.PropertyRule{
+			Key:      r.Key,
+			Required: r.Required,
+			Type:     pb.PropertyType(r.Type),
+			NonEmpty: r.NonEmpty,
+		})
+	}
+	return result
+}
+
 // convertToPbObject converts a Go SpatialObject to protobuf
 func convertToPbObject(obj *urbis.SpatialObject) *pb.SpatialObject {
 	if obj == nil {
@@ -632,11 +2189,422 @@ func convertToPbObject(obj *urbis.SpatialObject) *pb.SpatialObject {
 				Polygon: &pb.Polygon{Exterior: points},
 			}
 		}
+	case urbis.GeomMultiPoint:
+		pbObj.Geometry = &pb.SpatialObject_MultiPoint{
+			MultiPoint: &pb.MultiPoint{Points: convertToPbPoints(obj.MultiPoint)},
+		}
+	case urbis.GeomMultiLineString:
+		lines := make([]*pb.LineString, len(obj.MultiLine))
+		for i, line := range obj.MultiLine {
+			lines[i] = &pb.LineString{Points: convertToPbPoints(line)}
+		}
+		pbObj.Geometry = &pb.SpatialObject_MultiLine{
+			MultiLine: &pb.MultiLineString{Lines: lines},
+		}
+	case urbis.GeomMultiPolygon:
+		polygons := make([]*pb.Polygon, len(obj.MultiPolygon))
+		for i, exterior := range obj.MultiPolygon {
+			polygons[i] = &pb.Polygon{Exterior: convertToPbPoints(exterior)}
+		}
+		pbObj.Geometry = &pb.SpatialObject_MultiPolygon{
+			MultiPolygon: &pb.MultiPolygon{Polygons: polygons},
+		}
+	case urbis.GeomGeometryCollection:
+		pbObj.Geometry = &pb.SpatialObject_Collection{
+			Collection: &pb.GeometryCollection{Geometries: convertToPbObjects(obj.Geometries)},
+		}
 	}
+	setPbZM(pbObj, obj)
 	
 	return pbObj
 }
 
+// convertFromPbGeometry converts the geometry of a protobuf SpatialObject,
+// ignoring its other fields and polygon holes. It returns nil when no
+// geometry is set.
+func convertFromPbGeometry(obj *pb.SpatialObject) *urbis.SpatialObject {
+	points := func(pts []*pb.Point) []urbis.Point {
+		result := make([]urbis.Point, len(pts))
+		for i, p := range pts {
+			result[i] = urbis.Point{X: p.GetX(), Y: p.GetY()}
+		}
+		return result
+	}
+
+	switch g := obj.GetGeometry().(type) {
+	case *pb.SpatialObject_Point:
+		return &urbis.SpatialObject{Type: urbis.GeomPoint, Point: &urbis.Point{X: g.Point.GetX(), Y: g.Point.GetY()}}
+	case *pb.SpatialObject_Line:
+		return &urbis.SpatialObject{Type: urbis.GeomLineString, Line: points(g.Line.GetPoints())}
+	case *pb.SpatialObject_Polygon:
+		return &urbis.SpatialObject{Type: urbis.GeomPolygon, Polygon: points(g.Polygon.GetExterior())}
+	case *pb.SpatialObject_MultiPoint:
+		return &urbis.SpatialObject{Type: urbis.GeomMultiPoint, MultiPoint: points(g.MultiPoint.GetPoints())}
+	case *pb.SpatialObject_MultiLine:
+		obj := &urbis.SpatialObject{Type: urbis.GeomMultiLineString}
+		for _, line := range g.MultiLine.GetLines() {
+			obj.MultiLine = append(obj.MultiLine, points(line.GetPoints()))
+		}
+		return obj
+	case *pb.SpatialObject_MultiPolygon:
+		obj := &urbis.SpatialObject{Type: urbis.GeomMultiPolygon}
+		for _, polygon := range g.MultiPolygon.GetPolygons() {
+			obj.MultiPolygon = append(obj.MultiPolygon, points(polygon.GetExterior()))
+		}
+		return obj
+	case *pb.SpatialObject_Collection:
+		obj := &urbis.SpatialObject{Type: urbis.GeomGeometryCollection}
+		for _, member := range g.Collection.GetGeometries() {
+			if converted := convertFromPbGeometry(member); converted != nil {
+				obj.Geometries = append(obj.Geometries, converted)
+			}
+		}
+		return obj
+	}
+	return nil
+}
+
+// validateCoords rejects NaN and infinite coordinates before they reach the index
+func validateCoords(points ...*pb.Point) error {
+	for _, p := range points {
+		x, y := p.GetX(), p.GetY()
+		if math.IsNaN(x) || math.IsInf(x, 0) || math.IsNaN(y) || math.IsInf(y, 0) {
+			return status.Errorf(codes.InvalidArgument, "coordinates must be finite, got (%v, %v)", x, y)
+		}
+	}
+	return nil
+}
+
+// errorCode maps binding errors caused by bad input, or by an index that
+// is unbuilt or read-only, to InvalidArgument and FailedPrecondition
+func errorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, urbis.ErrIDInUse):
+		return codes.AlreadyExists
+	case errors.Is(err, urbis.ErrInvalid):
+		return codes.InvalidArgument
+	case errors.Is(err, urbis.ErrNotBuilt), errors.Is(err, urbis.ErrReadOnly), errors.Is(err, urbis.ErrVersionMismatch):
+		return codes.FailedPrecondition
+	case errors.Is(err, urbis.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+	return codes.Internal
+}
+
+func convertToPbMBR(mbr urbis.MBR) *pb.MBR {
+	return &pb.MBR{
+		MinX: mbr.MinX,
+		MinY: mbr.MinY,
+		MaxX: mbr.MaxX,
+		MaxY: mbr.MaxY,
+	}
+}
+
+// indexBounds returns the index's bounds, or nil if it is empty and so has
+// none
+func indexBounds(idx *urbis.Index) *pb.MBR {
+	if idx.Count() == 0 {
+		return nil
+	}
+	return convertToPbMBR(idx.Bounds())
+}
+
+// resultBounds returns the union of the objects' MBRs, or nil if there are none
+func resultBounds(objs []*urbis.SpatialObject) *pb.MBR {
+	if len(objs) == 0 {
+		return nil
+	}
+	bounds := objs[0].MBR
+	for _, obj := range objs[1:] {
+		bounds.MinX = math.Min(bounds.MinX, obj.MBR.MinX)
+		bounds.MinY = math.Min(bounds.MinY, obj.MBR.MinY)
+		bounds.MaxX = math.Max(bounds.MaxX, obj.MBR.MaxX)
+		bounds.MaxY = math.Max(bounds.MaxY, obj.MBR.MaxY)
+	}
+	return convertToPbMBR(bounds)
+}
+
+func convertToPbInserted(ins urbis.Inserted) *pb.InsertResponse {
+	return &pb.InsertResponse{
+		ObjectId: ins.ID,
+		Mbr:      convertToPbMBR(ins.MBR),
+		Centroid: &pb.Point{X: ins.Centroid.X, Y: ins.Centroid.Y},
+	}
+}
+
+func convertToPbPoints(points []urbis.Point) []*pb.Point {
+	result := make([]*pb.Point, len(points))
+	for i, p := range points {
+		result[i] = &pb.Point{X: p.X, Y: p.Y}
+	}
+	return result
+}
+
+// convertToPbStats converts Go index Stats to protobuf
+func convertToPbStats(stats urbis.Stats) *pb.Stats {
+	pbStats := &pb.Stats{
+		TotalObjects:      stats.TotalObjects,
+		TotalBlocks:       stats.TotalBlocks,
+		TotalPages:        stats.TotalPages,
+		TotalTracks:       stats.TotalTracks,
+		AvgObjectsPerPage: stats.AvgObjectsPerPage,
+		PageUtilization:   stats.PageUtilization,
+		KdtreeDepth:       stats.KDTreeDepth,
+		QuadtreeDepth:     stats.QuadtreeDepth,
+		PageCapacity:      stats.PageCapacity,
+		MemoryBytes:       stats.MemoryBytes,
+		DiskBytes:         stats.DiskBytes,
+		BuildThreads:      stats.BuildThreads,
+		Bounds: &pb.MBR{
+			MinX: stats.Bounds.MinX,
+			MinY: stats.Bounds.MinY,
+			MaxX: stats.Bounds.MaxX,
+			MaxY: stats.Bounds.MaxY,
+		},
+	}
+	if stats.HasMeters {
+		pbStats.WidthMeters, pbStats.HeightMeters = &stats.WidthMeters, &stats.HeightMeters
+	}
+	return pbStats
+}
+
+// convertToPbQueryStats converts Go QueryStats to protobuf
+func convertToPbQueryStats(stats urbis.QueryStats) *pb.QueryStats {
+	return &pb.QueryStats{
+		PagesVisited:   stats.PagesVisited,
+		TracksVisited:  stats.TracksVisited,
+		EstimatedSeeks: stats.EstimatedSeeks,
+		CacheHits:      stats.CacheHits,
+		CacheMisses:    stats.CacheMisses,
+
+		Structure:         pb.IndexStructure(stats.Structure),
+		StructureFallback: stats.StructureFallback,
+
+		DuplicatesSuppressed: stats.DuplicatesSuppressed,
+	}
+}
+
+// sortKey returns the ordering key for a range query sort, measured
+// against the query region. A nil key orders by ID alone. For a region
+// crossing the antimeridian, x distances are measured the short way round
+// the world of the index CRS.
+func sortKey(by pb.RangeSort, region urbis.MBR, crs int) (func(*urbis.SpatialObject) float64, error) {
+	switch by {
+	case pb.RangeSort_RANGE_SORT_NONE, pb.RangeSort_RANGE_SORT_ID:
+		return nil, nil
+	case pb.RangeSort_RANGE_SORT_DISTANCE_FROM_CENTER:
+		cx := (region.MinX + region.MaxX) / 2
+		cy := (region.MinY + region.MaxY) / 2
+		if region.CrossesAntimeridian() {
+			width := urbis.WorldWidth(crs)
+			cx += width / 2
+			return func(obj *urbis.SpatialObject) float64 {
+				dx := math.Mod(math.Abs(obj.Centroid.X-cx), width)
+				return math.Hypot(math.Min(dx, width-dx), obj.Centroid.Y-cy)
+			}, nil
+		}
+		return func(obj *urbis.SpatialObject) float64 {
+			return math.Hypot(obj.Centroid.X-cx, obj.Centroid.Y-cy)
+		}, nil
+	case pb.RangeSort_RANGE_SORT_MBR_AREA:
+		return func(obj *urbis.SpatialObject) float64 {
+			return (obj.MBR.MaxX - obj.MBR.MinX) * (obj.MBR.MaxY - obj.MBR.MinY)
+		}, nil
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "unknown sort_by %d", by)
+}
+
+// orderResults applies the sort order and pagination of a range query
+func orderResults(objs []*urbis.SpatialObject, region urbis.MBR, crs int, req *pb.RangeQueryRequest) ([]*urbis.SpatialObject, string, error) {
+	key, err := sortKey(req.SortBy, region, crs)
+	if err != nil {
+		return nil, "", err
+	}
+	if req.SortBy == pb.RangeSort_RANGE_SORT_NONE && req.Limit == 0 && req.Cursor == "" {
+		return objs, "", nil
+	}
+	return paginate(objs, key, req.Cursor, req.Limit)
+}
+
+// paginate orders objects by key, then ID, and returns up to limit of them
+// following the cursor, plus the cursor for the next page ("" on the last
+// page). The cursor records the position of the last object returned, so
+// pages are stable while the index is unchanged.
+func paginate(objs []*urbis.SpatialObject, key func(*urbis.SpatialObject) float64, cursor string, limit uint32) ([]*urbis.SpatialObject, string, error) {
+	if key == nil {
+		key = func(*urbis.SpatialObject) float64 { return 0 }
+	}
+	keys := make(map[*urbis.SpatialObject]float64, len(objs))
+	for _, obj := range objs {
+		keys[obj] = key(obj)
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		ki, kj := keys[objs[i]], keys[objs[j]]
+		if ki != kj {
+			return ki < kj
+		}
+		return objs[i].ID < objs[j].ID
+	})
+
+	if cursor != "" {
+		afterKey, afterID, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		start := sort.Search(len(objs), func(i int) bool {
+			k := keys[objs[i]]
+			return k > afterKey || (k == afterKey && objs[i].ID > afterID)
+		})
+		objs = objs[start:]
+	}
+
+	if limit == 0 || len(objs) <= int(limit) {
+		return objs, "", nil
+	}
+	page := objs[:limit]
+	last := page[len(page)-1]
+	return page, encodeCursor(keys[last], last.ID), nil
+}
+
+// encodeCursor packs a sort position into an opaque cursor. ID-only
+// positions keep the plain ID form.
+func encodeCursor(key float64, id uint64) string {
+	raw := strconv.FormatUint(id, 10)
+	if key != 0 {
+		raw = strconv.FormatFloat(key, 'g', -1, 64) + ":" + raw
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor reverses encodeCursor
+func decodeCursor(cursor string) (float64, uint64, error) {
+	invalid := status.Errorf(codes.InvalidArgument, "invalid cursor %q", cursor)
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, invalid
+	}
+	var key float64
+	keyPart, idPart, hasKey := strings.Cut(string(raw), ":")
+	if hasKey {
+		if key, err = strconv.ParseFloat(keyPart, 64); err != nil || math.IsNaN(key) {
+			return 0, 0, invalid
+		}
+	} else {
+		idPart = keyPart
+	}
+	id, err := strconv.ParseUint(idPart, 10, 64)
+	if err != nil {
+		return 0, 0, invalid
+	}
+	return key, id, nil
+}
+
+// convertStructure validates a protobuf structure hint
+func convertStructure(s pb.IndexStructure) (urbis.Structure, error) {
+	if _, ok := pb.IndexStructure_name[int32(s)]; !ok {
+		return 0, status.Errorf(codes.InvalidArgument, "unknown index structure %d", s)
+	}
+	return urbis.Structure(s), nil
+}
+
+// convertToPbResults converts query results, adding change stamps when asked
+func convertToPbResults(objs []*urbis.SpatialObject, includeVersion bool) []*pb.SpatialObject {
+	result := convertToPbObjects(objs)
+	if includeVersion {
+		for i, obj := range objs {
+			setPbVersion(result[i], obj)
+		}
+	}
+	return result
+}
+
+// encodeResults applies a query's encoding and field mask to its results.
+// GeoJSON replaces the objects with a single FeatureCollection, which the
+// mask does not apply to; the other encodings are applied object by object.
+func encodeResults(idx *urbis.Index, dst *[]*pb.SpatialObject, geojson *string, objs []*urbis.SpatialObject, enc pb.GeometryEncoding, mask []pb.ObjectField) error {
+	fields, err := parseFieldMask(mask)
+	if err != nil {
+		return err
+	}
+	if enc != pb.GeometryEncoding_GEOMETRY_ENCODING_GEOJSON {
+		// No need to encode geometry the mask drops
+		if fields.has(pb.ObjectField_OBJECT_FIELD_GEOMETRY) {
+			if err := encodeGeometries(idx, *dst, objs, enc); err != nil {
+				return err
+			}
+		}
+		fields.apply(*dst)
+		return nil
+	}
+
+	fc, err := idx.FeatureCollection(objs)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to export GeoJSON: %v", err)
+	}
+	*dst = nil
+	*geojson = string(fc)
+	return nil
+}
+
+// encodeGeometries replaces the structured geometry of query results with a
+// compact encoding. Objects the encoding cannot express, or that were
+// removed since the query ran, keep the structured form.
+func encodeGeometries(idx *urbis.Index, dst []*pb.SpatialObject, objs []*urbis.SpatialObject, enc pb.GeometryEncoding) error {
+	switch enc {
+	case pb.GeometryEncoding_GEOMETRY_ENCODING_STRUCTURED:
+		return nil
+
+	case pb.GeometryEncoding_GEOMETRY_ENCODING_WKB:
+		ids := make([]uint64, len(objs))
+		for i, obj := range objs {
+			ids[i] = obj.ID
+		}
+		wkbs, err := idx.ExportWKBs(ids)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to export WKB: %v", err)
+		}
+		for i, wkb := range wkbs {
+			if wkb != nil {
+				dst[i].Geometry = nil
+				dst[i].EncodedGeometry = wkb
+			}
+		}
+		return nil
+
+	case pb.GeometryEncoding_GEOMETRY_ENCODING_POLYLINE:
+		for i, obj := range objs {
+			var points []urbis.Point
+			switch obj.Type {
+			case urbis.GeomPoint:
+				points = []urbis.Point{*obj.Point}
+			case urbis.GeomLineString:
+				points = obj.Line
+			case urbis.GeomPolygon:
+				points = obj.Polygon
+			default:
+				continue
+			}
+			dst[i].Geometry = nil
+			dst[i].EncodedGeometry = urbis.EncodePolyline(points)
+		}
+		return nil
+	}
+	return status.Errorf(codes.InvalidArgument, "unknown geometry encoding %d", enc)
+}
+
+// setPbVersion copies an object's version and modification time
+func setPbVersion(dst *pb.SpatialObject, obj *urbis.SpatialObject) {
+	dst.Version = obj.Version
+	if !obj.ModifiedAt.IsZero() {
+		dst.ModifiedAtMs = obj.ModifiedAt.UnixMilli()
+	}
+}
+
 // convertToPbObjects converts a slice of SpatialObjects to protobuf
 func convertToPbObjects(objs []*urbis.SpatialObject) []*pb.SpatialObject {
 	result := make([]*pb.SpatialObject, len(objs))
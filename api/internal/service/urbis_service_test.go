@@ -0,0 +1,3128 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReloadIndexSwapsInPlace(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 1, Y: 1}); err != nil {
+		t.Fatal(err)
+	}
+	old, release, _ := s.getIndex("city")
+	release()
+
+	resp, err := s.ReloadIndex(ctx, &pb.ReloadIndexRequest{
+		IndexId: "city",
+		Source: &pb.ReloadIndexRequest_Geojson{Geojson: `{"type":"FeatureCollection","features":[
+			{"type":"Feature","geometry":{"type":"Point","coordinates":[5,5]},"properties":{}},
+			{"type":"Feature","geometry":{"type":"Point","coordinates":[6,6]},"properties":{}}]}`},
+		Build: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 2 {
+		t.Errorf("reloaded count = %d, want 2", resp.Count)
+	}
+
+	idx, release, _ := s.getIndex("city")
+	release()
+	if idx == old {
+		t.Fatal("index was not replaced")
+	}
+	if old.Count() != 0 {
+		t.Error("old index was not closed")
+	}
+	if s.IndexCount() != 1 {
+		t.Errorf("IndexCount() = %d, want 1", s.IndexCount())
+	}
+
+	if _, err := s.ReloadIndex(ctx, &pb.ReloadIndexRequest{IndexId: "missing",
+		Source: &pb.ReloadIndexRequest_Geojson{Geojson: "{}"}}); err == nil {
+		t.Error("reloading a missing index succeeded")
+	}
+}
+
+func TestReloadIndexDuringQueries(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	defer s.CloseAll()
+
+	var features strings.Builder
+	features.WriteString(`{"type":"FeatureCollection","features":[`)
+	for i := 0; i < 200; i++ {
+		if i > 0 {
+			features.WriteString(",")
+		}
+		fmt.Fprintf(&features, `{"type":"Feature","geometry":{"type":"Point","coordinates":[%d,%d]},"properties":{}}`, i%20, i/20)
+	}
+	features.WriteString("]}")
+	reload := &pb.ReloadIndexRequest{
+		IndexId: "city",
+		Source:  &pb.ReloadIndexRequest_Geojson{Geojson: features.String()},
+		Build:   true,
+	}
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ReloadIndex(ctx, reload); err != nil {
+		t.Fatal(err)
+	}
+	// A request holding the index keeps it open across a reload
+	held, release, _ := s.getIndex("city")
+	if _, err := s.ReloadIndex(ctx, reload); err != nil {
+		t.Fatal(err)
+	}
+	if list, err := held.QueryRange(urbis.MBR{MinX: 0, MinY: 0, MaxX: 100, MaxY: 100}); err != nil || list.Count != 200 {
+		t.Errorf("query on the replaced index = %v, %v; want all 200 objects", list, err)
+	}
+	release()
+	if held.Count() != 0 {
+		t.Error("replaced index still open after its last release")
+	}
+
+	var wg sync.WaitGroup
+	var stop atomic.Bool
+	errs := make(chan error, 4)
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				resp, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "city", Range: &pb.MBR{MinX: 0, MinY: 0, MaxX: 100, MaxY: 100}})
+				if err == nil && resp.Count != 200 {
+					err = fmt.Errorf("query found %d objects, want 200", resp.Count)
+				}
+				if err == nil {
+					_, err = s.QueryKNN(ctx, &pb.KNNQueryRequest{IndexId: "city", X: 5, Y: 5, K: 3})
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	var replaced []*urbis.Index
+	for i := 0; i < 50 && len(errs) == 0; i++ {
+		old, release, _ := s.getIndex("city")
+		release()
+		replaced = append(replaced, old)
+		if _, err := s.ReloadIndex(ctx, reload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stop.Store(true)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("query during reload: %v", err)
+	}
+
+	// Every replaced index was closed once its queries were done
+	for i, old := range replaced {
+		if old.Count() != 0 {
+			t.Errorf("index replaced by reload %d is still open", i)
+		}
+	}
+}
+
+func TestQueryUnbuiltIndexFailsPrecondition(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := s.QueryPoint(ctx, &pb.PointQueryRequest{IndexId: "city", X: 1, Y: 1})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("QueryPoint before Build: got %v, want FailedPrecondition", err)
+	}
+}
+
+func TestQueryRangePagination(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 25; i++ {
+		if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: float64(i), Y: float64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &pb.RangeQueryRequest{
+		IndexId: "city",
+		Range:   &pb.MBR{MinX: 0, MinY: 0, MaxX: 100, MaxY: 100},
+		Limit:   10,
+	}
+	seen := map[uint64]bool{}
+	var lastID uint64
+	pages := 0
+	for {
+		resp, err := s.QueryRange(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		for _, obj := range resp.Objects {
+			if seen[obj.Id] || obj.Id <= lastID {
+				t.Fatalf("object %d out of order or duplicated", obj.Id)
+			}
+			seen[obj.Id] = true
+			lastID = obj.Id
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		req.Cursor = resp.NextCursor
+	}
+	if pages != 3 || len(seen) != 25 {
+		t.Errorf("got %d objects over %d pages, want 25 over 3", len(seen), pages)
+	}
+
+	req.Cursor = "not a cursor"
+	if _, err := s.QueryRange(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("bad cursor: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestQueryRangeSortBy(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	// Squares of side 5-i centered on (i, 0), so distance from the origin
+	// grows with i while area shrinks
+	for i := 0; i < 5; i++ {
+		half := float64(5-i) / 2
+		x := float64(i)
+		ring := []*pb.Point{{X: x - half, Y: -half}, {X: x + half, Y: -half}, {X: x + half, Y: half}, {X: x - half, Y: half}, {X: x - half, Y: -half}}
+		if _, err := s.InsertPolygon(ctx, &pb.InsertPolygonRequest{IndexId: "city", Exterior: ring}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+
+	centroids := func(objs []*pb.SpatialObject) []float64 {
+		xs := make([]float64, len(objs))
+		for i, obj := range objs {
+			xs[i] = obj.Centroid.X
+		}
+		return xs
+	}
+	region := &pb.MBR{MinX: -10, MinY: -10, MaxX: 10, MaxY: 10}
+
+	resp, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "city", Range: region, SortBy: pb.RangeSort_RANGE_SORT_DISTANCE_FROM_CENTER})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := centroids(resp.Objects); !sort.Float64sAreSorted(got) || len(got) != 5 {
+		t.Errorf("distance order: centroid x = %v, want ascending", got)
+	}
+
+	// Paginate by area; each page continues where the last one ended
+	req := &pb.RangeQueryRequest{IndexId: "city", Range: region, SortBy: pb.RangeSort_RANGE_SORT_MBR_AREA, Limit: 2}
+	var xs []float64
+	for {
+		resp, err := s.QueryRange(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		xs = append(xs, centroids(resp.Objects)...)
+		if resp.NextCursor == "" {
+			break
+		}
+		req.Cursor = resp.NextCursor
+	}
+	if want := []float64{4, 3, 2, 1, 0}; !reflect.DeepEqual(xs, want) {
+		t.Errorf("area order: centroid x = %v, want %v", xs, want)
+	}
+
+	if _, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "city", Range: region, SortBy: 99}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown sort_by: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestStreamSaveLoadRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterUrbisServiceServer(server, NewUrbisServer())
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewUrbisServiceClient(conn)
+
+	if _, err := client.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "src"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		client.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "src", X: float64(i), Y: 1})
+	}
+
+	save, err := client.StreamSave(ctx, &pb.StreamSaveRequest{IndexId: "src"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	load, err := client.StreamLoad(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := true
+	for {
+		chunk, err := save.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := &pb.StreamLoadRequest{Chunk: chunk.Chunk}
+		if first {
+			req.IndexId = "copy"
+			first = false
+		}
+		if err := load.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err := load.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 50 {
+		t.Errorf("loaded copy has %d objects, want 50", resp.Count)
+	}
+}
+
+func TestDrainRejectsNewCalls(t *testing.T) {
+	s := NewUrbisServer()
+	intercept := s.UnaryDrainInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	create := &grpc.UnaryServerInfo{FullMethod: pb.UrbisService_CreateIndex_FullMethodName}
+	health := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+
+	if _, err := intercept(context.Background(), nil, create, handler); err != nil {
+		t.Fatalf("before drain: %v", err)
+	}
+
+	s.Drain()
+	if _, err := intercept(context.Background(), nil, create, handler); status.Code(err) != codes.Unavailable {
+		t.Errorf("CreateIndex while draining: got %v, want Unavailable", err)
+	}
+	if _, err := intercept(context.Background(), nil, health, handler); err != nil {
+		t.Errorf("health check while draining: %v", err)
+	}
+}
+
+func TestQueryConcurrencyLimit(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer(WithQueryLimit(1, 0))
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 1, Y: 1})
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	query := &pb.PointQueryRequest{IndexId: "city", X: 1, Y: 1}
+
+	// Hold the only slot as if a query were running
+	release, err := s.acquireQuery(ctx, "city")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := s.InFlightQueries()["city"]; n != 1 {
+		t.Errorf("in-flight queries = %d, want 1", n)
+	}
+	if _, err := s.QueryPoint(ctx, query); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("query over the limit: got %v, want ResourceExhausted", err)
+	}
+	release()
+	if _, err := s.QueryPoint(ctx, query); err != nil {
+		t.Errorf("query after release: %v", err)
+	}
+
+	// With a queue timeout, a query waits for the slot to free up
+	s.queryWait = time.Second
+	release, _ = s.acquireQuery(ctx, "city")
+	time.AfterFunc(20*time.Millisecond, release)
+	if _, err := s.QueryPoint(ctx, query); err != nil {
+		t.Errorf("queued query: %v", err)
+	}
+	if n := s.InFlightQueries()["city"]; n != 0 {
+		t.Errorf("in-flight queries after completion = %d, want 0", n)
+	}
+}
+
+func TestInsertPolygonReportsValidity(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	bowtie := []*pb.Point{{X: 0, Y: 0}, {X: 4, Y: 4}, {X: 4, Y: 0}, {X: 0, Y: 4}, {X: 0, Y: 0}}
+
+	s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "strict"})
+	if _, err := s.InsertPolygon(ctx, &pb.InsertPolygonRequest{IndexId: "strict", Exterior: bowtie}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("bowtie in strict index: got %v, want InvalidArgument", err)
+	}
+
+	s.CreateIndex(ctx, &pb.CreateIndexRequest{
+		IndexId: "report",
+		Config:  &pb.Config{PolygonValidation: pb.PolygonValidation_POLYGON_VALIDATION_REPORT},
+	})
+	resp, err := s.InsertPolygon(ctx, &pb.InsertPolygonRequest{IndexId: "report", Exterior: bowtie})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.IsValid == nil || *resp.IsValid || resp.Reason == "" {
+		t.Errorf("report mode response = %+v, want is_valid=false with a reason", resp)
+	}
+}
+
+func TestQueryChangedSince(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	first, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 1, Y: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.GetObject(ctx, &pb.GetObjectRequest{IndexId: "city", ObjectId: first.ObjectId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Object.Version != 0 || got.Object.ModifiedAtMs != 0 {
+		t.Errorf("version filled without include_version: %d at %d", got.Object.Version, got.Object.ModifiedAtMs)
+	}
+	got, err = s.GetObject(ctx, &pb.GetObjectRequest{IndexId: "city", ObjectId: first.ObjectId, IncludeVersion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Object.Version == 0 || got.Object.ModifiedAtMs == 0 {
+		t.Fatalf("include_version left the stamp empty: %v", got.Object)
+	}
+
+	second, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 2, Y: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.QueryChangedSince(ctx, &pb.ChangedSinceRequest{IndexId: "city", SinceMs: got.Object.ModifiedAtMs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 2 || resp.Objects[0].Id != first.ObjectId || resp.Objects[1].Id != second.ObjectId {
+		t.Fatalf("changed objects = %v, want both in insert order", resp.Objects)
+	}
+	if resp.Objects[1].Version <= resp.Objects[0].Version {
+		t.Errorf("versions not increasing: %d then %d", resp.Objects[0].Version, resp.Objects[1].Version)
+	}
+}
+
+func TestLoadGeoJSONURL(t *testing.T) {
+	const doc = `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{}}]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "http://elsewhere.invalid/city.geojson", http.StatusFound)
+			return
+		}
+		w.Write([]byte(doc))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	req := &pb.LoadGeoJSONURLRequest{IndexId: "city", Url: srv.URL + "/city.geojson"}
+
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadGeoJSONURL(ctx, req); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("fetch with no allowlist: got %v, want FailedPrecondition", err)
+	}
+
+	s = NewUrbisServer(WithFetchHosts([]string{"127.0.0.1"}, 0))
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.LoadGeoJSONURL(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ObjectsLoaded != 1 {
+		t.Errorf("loaded %d objects, want 1", resp.ObjectsLoaded)
+	}
+
+	denied := []struct {
+		url  string
+		code codes.Code
+	}{
+		{"http://localhost.invalid/city.geojson", codes.PermissionDenied},
+		{srv.URL + "/moved", codes.PermissionDenied},
+		{"file:///etc/passwd", codes.InvalidArgument},
+	}
+	for _, tc := range denied {
+		_, err := s.LoadGeoJSONURL(ctx, &pb.LoadGeoJSONURLRequest{IndexId: "city", Url: tc.url})
+		if status.Code(err) != tc.code {
+			t.Errorf("fetch %s: got %v, want %v", tc.url, err, tc.code)
+		}
+	}
+
+	s = NewUrbisServer(WithFetchHosts([]string{"127.0.0.1"}, int64(len(doc)-1)))
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadGeoJSONURL(ctx, req); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("oversized fetch: got %v, want ResourceExhausted", err)
+	}
+}
+
+func TestQueryGeometryEncoding(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	line := []*pb.Point{{X: 88.3, Y: 22.5}, {X: 88.35, Y: 22.55}, {X: 88.4, Y: 22.52}}
+	if _, err := s.InsertLineString(ctx, &pb.InsertLineStringRequest{IndexId: "city", Points: line}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	query := func(enc pb.GeometryEncoding) (*pb.SpatialObject, error) {
+		resp, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "city", Range: &pb.MBR{MinX: 88, MinY: 22, MaxX: 89, MaxY: 23}, Encoding: enc})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Objects) != 1 {
+			t.Fatalf("got %d objects, want 1", len(resp.Objects))
+		}
+		return resp.Objects[0], nil
+	}
+
+	obj, err := query(pb.GeometryEncoding_GEOMETRY_ENCODING_STRUCTURED)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetLine() == nil || obj.EncodedGeometry != nil {
+		t.Errorf("default encoding: line %v, encoded %q", obj.GetLine(), obj.EncodedGeometry)
+	}
+
+	obj, err = query(pb.GeometryEncoding_GEOMETRY_ENCODING_WKB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Byte order, type, count and three points
+	if obj.Geometry != nil || len(obj.EncodedGeometry) != 1+4+4+3*16 {
+		t.Errorf("WKB encoding: geometry %v, %d encoded bytes", obj.Geometry, len(obj.EncodedGeometry))
+	}
+
+	obj, err = query(pb.GeometryEncoding_GEOMETRY_ENCODING_POLYLINE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	points, err := urbis.DecodePolyline(obj.EncodedGeometry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != len(line) || points[1] != (urbis.Point{X: 88.35, Y: 22.55}) {
+		t.Errorf("polyline decoded to %v", points)
+	}
+
+	if _, err := query(pb.GeometryEncoding(9)); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown encoding: got %v, want InvalidArgument", err)
+	}
+
+	resp, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "city", Range: &pb.MBR{MinX: 88, MinY: 22, MaxX: 89, MaxY: 23},
+		Encoding: pb.GeometryEncoding_GEOMETRY_ENCODING_GEOJSON})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Type        string      `json:"type"`
+				Coordinates [][]float64 `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal([]byte(resp.Geojson), &fc); err != nil {
+		t.Fatalf("GeoJSON encoding: %v in %s", err, resp.Geojson)
+	}
+	if len(resp.Objects) != 0 || resp.Count != 1 || fc.Type != "FeatureCollection" || len(fc.Features) != 1 {
+		t.Fatalf("GeoJSON encoding: %d objects, count %d, collection %s", len(resp.Objects), resp.Count, resp.Geojson)
+	}
+	if g := fc.Features[0].Geometry; g.Type != "LineString" || len(g.Coordinates) != 3 || g.Coordinates[1][0] != 88.35 {
+		t.Errorf("GeoJSON geometry = %+v", g)
+	}
+}
+
+func TestCloseAllAndResourceStats(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	// Other tests' servers may have left indexes open
+	before, err := s.GetResourceStats(ctx, &pb.ResourceStatsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err := s.GetResourceStats(ctx, &pb.ResourceStatsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.OpenIndexes != before.OpenIndexes+2 || resp.NativeBytes <= before.NativeBytes {
+		t.Errorf("resource stats = %+v, want two more indexes than %+v", resp, before)
+	}
+	if len(resp.LeakedOrigins) != len(before.LeakedOrigins) {
+		t.Errorf("held indexes reported as leaked: %v", resp.LeakedOrigins)
+	}
+
+	// Lose track of an index without closing it
+	leaked, err := urbis.NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer leaked.Close()
+	resp, err = s.GetResourceStats(ctx, &pb.ResourceStatsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, origin := range resp.LeakedOrigins {
+		found = found || strings.Contains(origin, "urbis_service_test.go")
+	}
+	if !found {
+		t.Errorf("leaked index not reported: %v", resp.LeakedOrigins)
+	}
+
+	if n := s.CloseAll(); n != 2 {
+		t.Errorf("CloseAll closed %d indexes, want 2", n)
+	}
+	if s.IndexCount() != 0 {
+		t.Errorf("%d indexes left after CloseAll", s.IndexCount())
+	}
+}
+
+func TestMultiQueryRange(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "tiles"}); err != nil {
+		t.Fatal(err)
+	}
+	left, _ := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "tiles", X: 5, Y: 5})
+	edge, _ := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "tiles", X: 10, Y: 5})
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "tiles"}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &pb.MultiRangeQueryRequest{
+		IndexId: "tiles",
+		Ranges: []*pb.MBR{
+			{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10},
+			{MinX: 10, MinY: 0, MaxX: 20, MaxY: 10},
+			{MinX: 50, MinY: 50, MaxX: 60, MaxY: 60},
+		},
+	}
+	resp, err := s.MultiQueryRange(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 3 || resp.Results[2].Count != 0 {
+		t.Fatalf("results = %v, want an entry per range with the last empty", resp.Results)
+	}
+	if resp.Results[0].Count != 2 || resp.Results[1].Count != 1 || resp.Results[1].Objects[0].Id != edge.ObjectId || resp.Count != 3 {
+		t.Fatalf("without deduplicate: counts %d, %d of %d, want the edge point in both tiles",
+			resp.Results[0].Count, resp.Results[1].Count, resp.Count)
+	}
+
+	req.Deduplicate = true
+	resp, err = s.MultiQueryRange(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Results[0].Count != 2 || resp.Results[1].Count != 0 || resp.Count != 2 {
+		t.Errorf("with deduplicate: counts %d, %d of %d, want the edge point only in the first tile",
+			resp.Results[0].Count, resp.Results[1].Count, resp.Count)
+	}
+	for _, obj := range resp.Results[0].Objects {
+		if obj.Id != left.ObjectId && obj.Id != edge.ObjectId {
+			t.Errorf("unexpected object %d", obj.Id)
+		}
+	}
+}
+
+func TestInsertReturnsMBRAndCentroid(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.InsertLineString(ctx, &pb.InsertLineStringRequest{
+		IndexId: "city",
+		Points:  []*pb.Point{{X: 1, Y: 1}, {X: 3, Y: 5}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.GetObject(ctx, &pb.GetObjectRequest{IndexId: "city", ObjectId: resp.ObjectId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(resp.Mbr, got.Object.Mbr) || !proto.Equal(resp.Centroid, got.Object.Centroid) {
+		t.Errorf("insert returned %v / %v, GetObject returned %v / %v", resp.Mbr, resp.Centroid, got.Object.Mbr, got.Object.Centroid)
+	}
+	if resp.Mbr.MaxY != 5 {
+		t.Errorf("mbr = %v, want max_y 5", resp.Mbr)
+	}
+}
+
+func TestQueryTimeoutAbandonsQuery(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer(WithQueryTimeout(20 * time.Millisecond))
+
+	unblock := make(chan struct{})
+	finished := make(chan struct{})
+	_, err := runQuery(ctx, s, "city", func() (int, error) {
+		defer close(finished)
+		<-unblock
+		return 1, nil
+	})
+	if status.Code(err) != codes.DeadlineExceeded || !strings.Contains(err.Error(), "server timeout") {
+		t.Fatalf("slow query: got %v, want DeadlineExceeded naming the server timeout", err)
+	}
+
+	// The abandoned query keeps its slot until it returns
+	if n := s.InFlightQueries()["city"]; n != 1 {
+		t.Errorf("in-flight queries while abandoned = %d, want 1", n)
+	}
+	close(unblock)
+	<-finished
+	for i := 0; i < 100 && s.InFlightQueries()["city"] != 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if n := s.InFlightQueries()["city"]; n != 0 {
+		t.Errorf("in-flight queries after the query returned = %d, want 0", n)
+	}
+
+	// A shorter client deadline wins over the server timeout
+	s.queryTimeout = time.Minute
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	block := make(chan struct{})
+	defer close(block)
+	if _, err := runQuery(short, s, "city", func() (int, error) { <-block; return 0, nil }); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("client deadline: got %v, want DeadlineExceeded", err)
+	}
+
+	if v, err := runQuery(ctx, s, "city", func() (int, error) { return 7, nil }); err != nil || v != 7 {
+		t.Errorf("fast query = %d, %v; want 7, nil", v, err)
+	}
+}
+
+func TestInsertWithClientID(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "mirror"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.InsertPolygon(ctx, &pb.InsertPolygonRequest{
+		IndexId:  "mirror",
+		ObjectId: 9000,
+		Exterior: []*pb.Point{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 0}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ObjectId != 9000 || resp.Mbr.GetMaxX() != 2 {
+		t.Errorf("response = %v, want ID 9000 with its MBR", resp)
+	}
+
+	_, err = s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "mirror", ObjectId: 9000, X: 1, Y: 1})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("reused ID: got %v, want AlreadyExists", err)
+	}
+}
+
+func TestGetPageGraphPagination(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "grid", Config: &pb.Config{PageCapacity: 4}}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 64; i++ {
+		s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "grid", X: float64(i % 8), Y: float64(i / 8)})
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "grid"}); err != nil {
+		t.Fatal(err)
+	}
+
+	full, err := s.GetPageGraph(ctx, &pb.PageGraphRequest{IndexId: "grid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full.NextCursor != "" || uint64(len(full.Nodes)) != full.TotalNodes || uint64(len(full.Edges)) != full.TotalEdges {
+		t.Fatalf("unpaginated response has %d/%d nodes, %d/%d edges, cursor %q",
+			len(full.Nodes), full.TotalNodes, len(full.Edges), full.TotalEdges, full.NextCursor)
+	}
+
+	var nodes, edges int
+	req := &pb.PageGraphRequest{IndexId: "grid", Limit: 3}
+	for {
+		resp, err := s.GetPageGraph(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes += len(resp.Nodes)
+		edges += len(resp.Edges)
+		if resp.NextCursor == "" {
+			break
+		}
+		req.Cursor = resp.NextCursor
+	}
+	if nodes != len(full.Nodes) || edges != len(full.Edges) {
+		t.Errorf("pages of 3 returned %d nodes and %d edges, want %d and %d", nodes, edges, len(full.Nodes), len(full.Edges))
+	}
+}
+
+func TestStreamInsertAcksEachItem(t *testing.T) {
+	ctx := context.Background()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterUrbisServiceServer(server, NewUrbisServer())
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewUrbisServiceClient(conn)
+
+	for _, id := range []string{"bulk", "other"} {
+		if _, err := client.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream, err := client.StreamInsert(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	square := &pb.Polygon{Exterior: []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 0}}}
+	items := []*pb.StreamInsertRequest{
+		{IndexId: "bulk", Geometry: &pb.StreamInsertRequest_Point{Point: &pb.Point{X: 1, Y: 2}}},
+		{Geometry: &pb.StreamInsertRequest_Line{Line: &pb.LineString{Points: []*pb.Point{{X: 0, Y: 0}}}}},
+		{Geometry: &pb.StreamInsertRequest_Polygon{Polygon: square}, ObjectId: 77},
+		{},
+		{IndexId: "bulk", Geometry: &pb.StreamInsertRequest_Point{Point: &pb.Point{X: 3, Y: 4}}},
+		{IndexId: "other", Geometry: &pb.StreamInsertRequest_Point{Point: &pb.Point{X: 5, Y: 6}}},
+	}
+	want := []codes.Code{codes.OK, codes.InvalidArgument, codes.OK, codes.InvalidArgument, codes.OK, codes.InvalidArgument}
+
+	// Send one item at a time and wait for its acknowledgement
+	for i, item := range items {
+		if err := stream.Send(item); err != nil {
+			t.Fatal(err)
+		}
+		ack, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ack.Sequence != uint64(i) || codes.Code(ack.Code) != want[i] {
+			t.Errorf("ack %d = %v, want sequence %d with code %v", i, ack, i, want[i])
+		}
+		if want[i] == codes.OK && ack.Result.GetObjectId() == 0 {
+			t.Errorf("ack %d has no object ID", i)
+		}
+		if i == 2 && ack.Result.GetObjectId() != 77 {
+			t.Errorf("client ID not kept: got %d", ack.Result.GetObjectId())
+		}
+	}
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("end of stream: got %v, want EOF", err)
+	}
+
+	count, err := client.GetCount(ctx, &pb.CountRequest{IndexId: "bulk"})
+	if err != nil || count.Count != 3 {
+		t.Errorf("count = %v, %v; want 3", count, err)
+	}
+	// The item naming another index went nowhere
+	count, err = client.GetCount(ctx, &pb.CountRequest{IndexId: "other"})
+	if err != nil || count.Count != 0 {
+		t.Errorf("other index count = %v, %v; want 0", count, err)
+	}
+}
+
+func TestStreamLoadGeoJSON(t *testing.T) {
+	ctx := context.Background()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterUrbisServiceServer(server, NewUrbisServer())
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewUrbisServiceClient(conn)
+
+	if _, err := client.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "extract"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// load sends data in chunks of size bytes, the first naming indexID
+	load := func(indexID, data string, size int) (*pb.LoadResponse, error) {
+		stream, err := client.StreamLoadGeoJSON(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i == 0 || i < len(data); i += size {
+			msg := &pb.StreamLoadGeoJSONRequest{Chunk: []byte(data[i:min(i+size, len(data))])}
+			if i == 0 {
+				msg.IndexId = indexID
+			}
+			if err := stream.Send(msg); err != nil {
+				break
+			}
+		}
+		return stream.CloseAndRecv()
+	}
+
+	// Chunks split features, and numbers, anywhere
+	var features strings.Builder
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&features, `{"type":"Feature","geometry":{"type":"Point","coordinates":[%d.5,%d]},"properties":{"n":%d}}`+"\n", i, i%5, i)
+	}
+	features.WriteString(`{"type":"Feature","geometry":null,"properties":{}}` + "\n")
+	resp, err := load("extract", features.String(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ObjectsLoaded != 50 || resp.Skipped != 1 || resp.Count != 50 {
+		t.Errorf("stream load = %v, want 50 loaded, 1 skipped and a count of 50", resp)
+	}
+	if b := resp.Bounds; b.GetMinX() != 0.5 || b.GetMaxX() != 49.5 || b.GetMaxY() != 4 {
+		t.Errorf("bounds after the stream = %v", b)
+	}
+
+	// A second stream adds to the index
+	resp, err = load("extract", `{"type":"Feature","geometry":{"type":"Point","coordinates":[100,100]},"properties":{}}`, 1<<20)
+	if err != nil || resp.ObjectsLoaded != 1 || resp.Count != 51 {
+		t.Errorf("second stream = %v, %v; want 1 loaded and a count of 51", resp, err)
+	}
+
+	if _, err := load("missing", features.String(), 1<<20); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown index: got %v, want NotFound", err)
+	}
+	if _, err := load("extract", `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,`+"\n", 1<<20); status.Code(err) == codes.OK {
+		t.Error("truncated feature loaded without an error")
+	}
+	empty, err := client.StreamLoadGeoJSON(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := empty.CloseAndRecv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty stream: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestQueryByProperty(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	config := &pb.Config{IndexedProperties: []string{"category"}}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "poi", Config: config}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := s.LoadGeoJSONString(ctx, &pb.LoadGeoJSONStringRequest{IndexId: "poi", Geojson: `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[0,0]},"properties":{"category":"park"}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1,1]},"properties":{"category":"cafe"}}]}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "poi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.QueryByProperty(ctx, &pb.PropertyQueryRequest{IndexId: "poi", Key: "category", Value: "park"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 1 || string(resp.Objects[0].Properties) != `{"category":"park"}` {
+		t.Errorf("parks = %v, want the one park with its properties", resp.Objects)
+	}
+
+	_, err = s.QueryByProperty(ctx, &pb.PropertyQueryRequest{IndexId: "poi", Key: "name", Value: "x"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unindexed key: code = %v, want InvalidArgument", status.Code(err))
+	}
+}
+
+func TestDescribeIndex(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	config := &pb.Config{PageCapacity: 32, CacheSize: 16, Crs: urbis.CRSWGS84, IndexedProperties: []string{"kind"}}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city", Config: config}); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*pb.InsertPointRequest{{IndexId: "city", X: 1, Y: 2}, {IndexId: "city", X: 3, Y: 4}} {
+		if _, err := s.InsertPoint(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	desc, err := s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "city"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Built || desc.Count != 2 || desc.Stats.TotalObjects != 2 {
+		t.Errorf("before build: built %v, count %d", desc.Built, desc.Count)
+	}
+	if c := desc.Config; c.PageCapacity != 32 || c.CacheSize != 16 || c.Crs != urbis.CRSWGS84 || !reflect.DeepEqual(c.IndexedProperties, []string{"kind"}) {
+		t.Errorf("config = %v", c)
+	}
+	if b := desc.Bounds; b.MinX != 1 || b.MinY != 2 || b.MaxX != 3 || b.MaxY != 4 {
+		t.Errorf("bounds = %v", b)
+	}
+
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	if desc, _ = s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "city"}); !desc.Built {
+		t.Error("built index described as not built")
+	}
+
+	// Creating without a config records the defaults
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "plain"}); err != nil {
+		t.Fatal(err)
+	}
+	desc, err = s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Config.GetPageCapacity() != urbis.DefaultConfig().PageCapacity {
+		t.Errorf("default config = %v", desc.Config)
+	}
+
+	if desc.Config.SeekCost.GetStorage() != pb.StorageKind_STORAGE_ROTATIONAL {
+		t.Errorf("default seek cost = %v, want rotational", desc.Config.SeekCost)
+	}
+
+	ssd := &pb.Config{SeekCost: &pb.SeekCostModel{Storage: pb.StorageKind_STORAGE_SSD}}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "ssd", Config: ssd}); err != nil {
+		t.Fatal(err)
+	}
+	if desc, _ = s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "ssd"}); desc.Config.SeekCost.GetStorage() != pb.StorageKind_STORAGE_SSD {
+		t.Errorf("seek cost = %v, want SSD", desc.Config.SeekCost)
+	}
+	bad := &pb.Config{SeekCost: &pb.SeekCostModel{Storage: pb.StorageKind_STORAGE_CUSTOM, SeekMs: 1}}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "bad", Config: bad}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("custom seek cost without transfer rate: got %v, want InvalidArgument", err)
+	}
+
+	if _, err := s.DestroyIndex(ctx, &pb.DestroyIndexRequest{IndexId: "plain"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "plain"}); status.Code(err) != codes.NotFound {
+		t.Errorf("destroyed index: got %v, want NotFound", err)
+	}
+}
+
+func TestValidationInterceptor(t *testing.T) {
+	intercept := NewUrbisServer().UnaryValidationInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: pb.UrbisService_QueryRange_FullMethodName}
+
+	tests := []struct {
+		name    string
+		req     interface{}
+		wantErr string
+	}{
+		{"valid range", &pb.RangeQueryRequest{IndexId: "a", Range: &pb.MBR{MinX: 0, MinY: 0, MaxX: 1, MaxY: 1}}, ""},
+		{"antimeridian range", &pb.RangeQueryRequest{IndexId: "a", Range: &pb.MBR{MinX: 170, MinY: 0, MaxX: -170, MaxY: 1}}, ""},
+		{"unset range left to handler", &pb.RangeQueryRequest{IndexId: "a"}, ""},
+		{"no index_id", &pb.BuildRequest{}, "index_id is required"},
+		{"inverted y", &pb.RangeQueryRequest{IndexId: "a", Range: &pb.MBR{MinY: 5, MaxY: 1}}, "range: min_y 5 is greater than max_y 1"},
+		{"inverted x", &pb.RemoveRangeRequest{IndexId: "a", Region: &pb.MBR{MinX: 5, MaxX: 1}}, "region: min_x 5 is greater than max_x 1"},
+		{"not finite", &pb.MultiRangeQueryRequest{IndexId: "a", Ranges: []*pb.MBR{{}, {MaxX: math.Inf(1)}}}, "ranges[1]: max_x must be finite, got +Inf"},
+		{"no fields checked", &pb.ListIndexesRequest{}, ""},
+	}
+	for _, tt := range tests {
+		resp, err := intercept(context.Background(), tt.req, info, handler)
+		if tt.wantErr == "" {
+			if err != nil || resp != "ok" {
+				t.Errorf("%s: got %v, %v; want the handler to run", tt.name, resp, err)
+			}
+			continue
+		}
+		if status.Code(err) != codes.InvalidArgument || status.Convert(err).Message() != tt.wantErr {
+			t.Errorf("%s: got %v, want InvalidArgument %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEmptyIndexIDWithoutInterceptor(t *testing.T) {
+	// Handlers called directly, as by an embedding server without the
+	// interceptor, still refuse an empty index_id
+	ctx := context.Background()
+	s := NewUrbisServer()
+	_, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CreateIndex: got %v, want InvalidArgument", err)
+	}
+	if _, err := s.Load(ctx, &pb.LoadIndexRequest{Path: "index.dat"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Load: got %v, want InvalidArgument", err)
+	}
+	if _, err := s.IndexReady(ctx, &pb.IndexReadyRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("IndexReady: got %v, want InvalidArgument", err)
+	}
+	if _, ok := s.indexes.Load(""); ok {
+		t.Error("an index was stored under the empty ID")
+	}
+}
+
+func TestIndexReady(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+
+	ready := func() *pb.IndexReadyResponse {
+		t.Helper()
+		resp, err := s.IndexReady(ctx, &pb.IndexReadyRequest{IndexId: "city"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	if resp := ready(); resp.Exists || resp.Built {
+		t.Errorf("before CreateIndex = %v, want neither existing nor built", resp)
+	}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 1, Y: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if resp := ready(); !resp.Exists || resp.Built {
+		t.Errorf("before Build = %v, want existing but not built", resp)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	if resp := ready(); !resp.Exists || !resp.Built {
+		t.Errorf("after Build = %v, want existing and built", resp)
+	}
+
+	// A change leaves the index unbuilt until the next Build
+	if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 2, Y: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if resp := ready(); !resp.Exists || resp.Built {
+		t.Errorf("after an insert = %v, want existing but not built", resp)
+	}
+	if _, err := s.DestroyIndex(ctx, &pb.DestroyIndexRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	if resp := ready(); resp.Exists {
+		t.Errorf("after DestroyIndex = %v, want not existing", resp)
+	}
+}
+
+func TestSnapshotScan(t *testing.T) {
+	ctx := context.Background()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	s := NewUrbisServer()
+	pb.RegisterUrbisServiceServer(server, s)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewUrbisServiceClient(conn)
+
+	if _, err := client.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "scan"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := client.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "scan", X: float64(i), Y: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stream, err := client.SnapshotScan(ctx, &pb.SnapshotScanRequest{IndexId: "scan", BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []uint64
+	for batches := 0; ; batches++ {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			if batches != 3 {
+				t.Errorf("got %d batches, want 3", batches)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Total != 5 || msg.SnapshotAtMs == 0 {
+			t.Errorf("batch %d: total %d, snapshot_at_ms %d", batches, msg.Total, msg.SnapshotAtMs)
+		}
+		for _, obj := range msg.Objects {
+			ids = append(ids, obj.Id)
+		}
+
+		// Writes after the scan started must not show up in later batches
+		if batches == 0 {
+			if _, err := client.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "scan", X: 9, Y: 9}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.Remove(ctx, &pb.RemoveRequest{IndexId: "scan", ObjectId: ids[len(ids)-1] + 1}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if len(ids) != 5 || !sort.SliceIsSorted(ids, func(i, j int) bool { return ids[i] < ids[j] }) {
+		t.Errorf("scanned ids %v, want 5 in ascending order", ids)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "near"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "near"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Nearest(ctx, &pb.NearestRequest{IndexId: "near"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("empty index: got %v, want NotFound", err)
+	}
+
+	for _, p := range []*pb.InsertPointRequest{{X: 0, Y: 0}, {X: 10, Y: 10}} {
+		p.IndexId = "near"
+		if _, err := s.InsertPoint(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "near"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.Nearest(ctx, &pb.NearestRequest{IndexId: "near", X: 7, Y: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := resp.Object.GetPoint(); p.GetX() != 10 || resp.Distance != 5 {
+		t.Errorf("got %v at distance %v, want (10, 10) at 5", resp.Object, resp.Distance)
+	}
+}
+
+func TestQueryFieldMask(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "masked"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "masked", X: 1, Y: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "masked"}); err != nil {
+		t.Fatal(err)
+	}
+	query := func(mask ...pb.ObjectField) (*pb.QueryResponse, error) {
+		return s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "masked", Range: &pb.MBR{MaxX: 5, MaxY: 5}, FieldMask: mask})
+	}
+
+	resp, err := query()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj := resp.Objects[0]; obj.GetPoint() == nil || obj.Mbr == nil || obj.Centroid == nil {
+		t.Errorf("empty mask dropped fields: %v", obj)
+	}
+
+	resp, err = query(pb.ObjectField_OBJECT_FIELD_ID, pb.ObjectField_OBJECT_FIELD_GEOMETRY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := resp.Objects[0]
+	if obj.Id == 0 || obj.GetPoint().GetY() != 2 || obj.Type != pb.GeomType_GEOM_POINT {
+		t.Errorf("kept fields missing: %v", obj)
+	}
+	if obj.Mbr != nil || obj.Centroid != nil || obj.Properties != nil {
+		t.Errorf("masked fields present: %v", obj)
+	}
+
+	resp, err = query(pb.ObjectField_OBJECT_FIELD_MBR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj := resp.Objects[0]; obj.Id != 0 || obj.Geometry != nil || obj.Mbr == nil {
+		t.Errorf("MBR-only mask: %v", obj)
+	}
+
+	if _, err := query(pb.ObjectField(42)); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown field: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestGetTreeStructure(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "tree"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 16; i++ {
+		if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "tree", X: float64(i % 4), Y: float64(i / 4)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "tree"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.GetTreeStructure(ctx, &pb.TreeStructureRequest{IndexId: "tree", Structure: pb.IndexStructure_INDEX_STRUCTURE_KDTREE, MaxDepth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Nodes) != 3 || resp.Nodes[0].Parent != -1 || resp.Nodes[0].ObjectCount != 16 || resp.Nodes[2].Parent != 0 {
+		t.Errorf("got nodes %v", resp.Nodes)
+	}
+
+	if _, err := s.GetTreeStructure(ctx, &pb.TreeStructureRequest{IndexId: "tree", Structure: pb.IndexStructure_INDEX_STRUCTURE_SCAN}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("scan structure: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestCreateIndexIfNotExists(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	req := &pb.CreateIndexRequest{IndexId: "roads", Config: &pb.Config{PageCapacity: 16}, IfNotExists: true}
+
+	resp, err := s.CreateIndex(ctx, req)
+	if err != nil || !resp.Created {
+		t.Fatalf("first create: %v, %v", resp, err)
+	}
+	if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "roads", X: 1, Y: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A retry leaves the existing index and its objects alone
+	resp, err = s.CreateIndex(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Created || resp.Count != 1 {
+		t.Errorf("retry: created %v, count %d, want false and 1", resp.Created, resp.Count)
+	}
+
+	_, err = s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "roads", Config: &pb.Config{PageCapacity: 32}, IfNotExists: true})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("different config: got %v, want FailedPrecondition", err)
+	}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "roads", Config: req.Config}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("without if_not_exists: got %v, want AlreadyExists", err)
+	}
+}
+
+func TestCreateIndexConcurrent(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	req := &pb.CreateIndexRequest{IndexId: "shared", Config: &pb.Config{PageCapacity: 16}, IfNotExists: true}
+	open := urbis.Resources().OpenIndexes
+
+	const callers = 16
+	var wg sync.WaitGroup
+	var created atomic.Int32
+	start := make(chan struct{})
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			resp, err := s.CreateIndex(ctx, req)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if resp.Created {
+				created.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent create: %v", err)
+	}
+	if n := created.Load(); n != 1 {
+		t.Errorf("%d callers created the index, want 1", n)
+	}
+	// The indexes of the callers that lost were closed, not leaked
+	if n := urbis.Resources().OpenIndexes - open; n > 1 {
+		t.Errorf("%d indexes left open, want 1", n)
+	}
+
+	idx, release, err := s.getIndex("shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	idx.Close()
+}
+
+func TestDataPathIsolation(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	dir := filepath.Join(t.TempDir(), "data")
+	persist := &pb.Config{Persist: true, DataPath: dir}
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "a/b", Config: persist}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "other", Config: &pb.Config{Persist: true, DataPath: dir + "/"}}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("shared data_path: got %v, want AlreadyExists", err)
+	}
+
+	// Without a path, Save writes the index's own file in its data_path
+	resp, err := s.Save(ctx, &pb.SaveRequest{IndexId: "a/b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "a%2Fb.urbis"); resp.Path != want {
+		t.Errorf("saved to %q, want %q", resp.Path, want)
+	}
+	if _, err := os.Stat(resp.Path); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "other", Config: &pb.Config{Persist: true, DataPath: resp.Path}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("data_path on a file: got %v, want InvalidArgument", err)
+	}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Save(ctx, &pb.SaveRequest{IndexId: "other", Path: resp.Path}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("save over another index's file: got %v, want InvalidArgument", err)
+	}
+	if _, err := s.Save(ctx, &pb.SaveRequest{IndexId: "other"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("save without path or data_path: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestLoadReturnsCountAndBounds(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	dir := t.TempDir()
+
+	created, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Count != 0 || created.Bounds != nil {
+		t.Errorf("new index: count %d, bounds %v; want 0 and no bounds", created.Count, created.Bounds)
+	}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "blank"}); err != nil {
+		t.Fatal(err)
+	}
+	empty, err := s.Save(ctx, &pb.SaveRequest{IndexId: "blank", Path: filepath.Join(dir, "empty.urbis")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range [][2]float64{{-3, 2}, {10, -4}, {5, 7.5}} {
+		if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: p[0], Y: p[1]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	built, err := s.Build(ctx, &pb.BuildRequest{IndexId: "city"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.MBR{MinX: -3, MinY: -4, MaxX: 10, MaxY: 7.5}
+	if !proto.Equal(built.Bounds, want) {
+		t.Errorf("Build bounds = %v, want %v", built.Bounds, want)
+	}
+	full, err := s.Save(ctx, &pb.SaveRequest{IndexId: "city", Path: filepath.Join(dir, "full.urbis")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := s.Load(ctx, &pb.LoadIndexRequest{IndexId: "copy", Path: full.Path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Count != 3 || !proto.Equal(loaded.Bounds, want) {
+		t.Errorf("Load: count %d, bounds %v; want 3 and %v", loaded.Count, loaded.Bounds, want)
+	}
+	described, err := s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "copy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(described.Bounds, want) {
+		t.Errorf("DescribeIndex bounds after Load = %v, want %v", described.Bounds, want)
+	}
+
+	loaded, err = s.Load(ctx, &pb.LoadIndexRequest{IndexId: "none", Path: empty.Path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Count != 0 || loaded.Bounds != nil {
+		t.Errorf("Load of an empty index: count %d, bounds %v; want 0 and no bounds", loaded.Count, loaded.Bounds)
+	}
+	described, err = s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if described.Bounds != nil {
+		t.Errorf("DescribeIndex bounds of an empty index = %v, want none", described.Bounds)
+	}
+}
+
+func TestPropertySchema(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	schema := []*pb.PropertyRule{{Key: "name", Required: true, Type: pb.PropertyType_PROPERTY_TYPE_STRING, NonEmpty: true}}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "named", Config: &pb.Config{PropertySchema: schema}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := s.LoadGeoJSONString(ctx, &pb.LoadGeoJSONStringRequest{IndexId: "named",
+		Geojson: `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 1]}, "properties": {"name": 5}}`})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), `"name"`) {
+		t.Errorf("bad feature: got %v, want InvalidArgument naming the property", err)
+	}
+	if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "named", X: 1, Y: 1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("bare insert: got %v, want InvalidArgument", err)
+	}
+
+	desc, err := s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "named"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := desc.Config.PropertySchema; len(got) != 1 || !proto.Equal(got[0], schema[0]) {
+		t.Errorf("described schema = %v", got)
+	}
+
+	bad := &pb.Config{PropertySchema: []*pb.PropertyRule{{Key: "x", Type: pb.PropertyType(9)}}}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "bad", Config: bad}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown type: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestRestoreStateSettings(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	config := &pb.Config{
+		Crs:               4326,
+		PolygonValidation: pb.PolygonValidation_POLYGON_VALIDATION_REPORT,
+		ValidBounds:       &pb.MBR{MinX: 88.2, MinY: 22.4, MaxX: 88.5, MaxY: 22.7},
+		PropertySchema:    []*pb.PropertyRule{{Key: "name", Required: true, Type: pb.PropertyType_PROPERTY_TYPE_STRING}},
+	}
+
+	s := NewUrbisServer(WithStateDir(dir))
+	if err := s.RestoreState(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city", Config: config}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadGeoJSONString(ctx, &pb.LoadGeoJSONStringRequest{IndexId: "city",
+		Geojson: `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [88.36, 22.57]}, "properties": {"name": "a"}}`}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Save(ctx, &pb.SaveRequest{IndexId: "city", Path: filepath.Join(dir, "city.dat")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restarted server restores the index from its data file with the
+	// same checks on inserts
+	restarted := NewUrbisServer(WithStateDir(dir))
+	if err := restarted.RestoreState(); err != nil {
+		t.Fatal(err)
+	}
+	idx, release, err := restarted.getIndex("city")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	defer idx.Close()
+	if idx.Count() != 1 || idx.CRS() != 4326 {
+		t.Errorf("restored count %d, CRS %d; want 1, 4326", idx.Count(), idx.CRS())
+	}
+	if _, err := restarted.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 88.3, Y: 22.5}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("insert without a name: got %v, want InvalidArgument", err)
+	}
+	resp, err := restarted.LoadGeoJSONString(ctx, &pb.LoadGeoJSONStringRequest{IndexId: "city",
+		Geojson: `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [22.57, 88.36]}, "properties": {"name": "swapped"}}`})
+	if err != nil || resp.OutOfBounds != 1 || resp.Count != 1 {
+		t.Errorf("load outside the valid bounds: got %v, %v; want it left out", resp, err)
+	}
+	if idx.PolygonValidation() != urbis.ValidationReport {
+		t.Errorf("restored polygon validation %d, want report", idx.PolygonValidation())
+	}
+
+	desc, err := restarted.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "city"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := desc.Config.GetPropertySchema(); len(got) != 1 || got[0].Key != "name" {
+		t.Errorf("restored config schema = %v", got)
+	}
+	s.indexes.Range(func(_, v any) bool { v.(*urbis.Index).Close(); return true })
+}
+
+func TestRestoreStateConfig(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewUrbisServer(WithStateDir(dir))
+	if err := s.RestoreState(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Settings only the recorded config holds
+	tuned := &pb.Config{
+		PageCapacity:      4,
+		EnableQuadtree:    true,
+		SnapPrecision:     1,
+		SimplifyTolerance: 0.5,
+		DedupPoints:       true,
+		IndexedProperties: []string{"k"},
+	}
+	wide := &pb.Config{Crs: 4326, KeepDuplicates: true, AutoRebuildThreshold: 100}
+	create := func(id string, config *pb.Config) {
+		t.Helper()
+		if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: id, Config: config}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	create("tuned", tuned)
+	create("wide", wide)
+	create("gone", nil)
+	create("broken", nil)
+
+	var features []string
+	for i := 0; i < 40; i++ {
+		features = append(features, fmt.Sprintf(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [%d, %d]}, "properties": {"k": "v%d"}}`, i%8*10, i/8*10, i%2))
+	}
+	collection := `{"type": "FeatureCollection", "features": [` + strings.Join(features, ",") + `]}`
+	if _, err := s.LoadGeoJSONString(ctx, &pb.LoadGeoJSONStringRequest{IndexId: "tuned", Geojson: collection}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.InsertLineString(ctx, &pb.InsertLineStringRequest{IndexId: "wide", Points: []*pb.Point{{X: -180, Y: 0}, {X: 180, Y: 0}}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"tuned", "wide", "gone", "broken"} {
+		if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: id}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Save(ctx, &pb.SaveRequest{IndexId: id, Path: filepath.Join(dir, id+".dat")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before, err := s.GetStats(ctx, &pb.StatsRequest{IndexId: "tuned"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Stats.QuadtreeDepth == 0 {
+		t.Fatalf("tuned index has no quadtree before the restart: %v", before.Stats)
+	}
+	s.CloseAll()
+	if err := os.Remove(filepath.Join(dir, "gone.dat")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.dat"), []byte("not an index"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries whose data file is missing or corrupt are skipped, not fatal
+	restarted := NewUrbisServer(WithStateDir(dir))
+	if err := restarted.RestoreState(); err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.CloseAll()
+	list, err := restarted.ListIndexes(ctx, &pb.ListIndexesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(list.IndexIds)
+	if got := strings.Join(list.IndexIds, ","); got != "tuned,wide" {
+		t.Errorf("restored indexes = %s, want tuned,wide", got)
+	}
+
+	// Indexed properties and the quadtree
+	if _, err := restarted.LoadGeoJSONString(ctx, &pb.LoadGeoJSONStringRequest{IndexId: "tuned",
+		Geojson: `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [100, 100]}, "properties": {"k": "new"}}`}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restarted.Build(ctx, &pb.BuildRequest{IndexId: "tuned"}); err != nil {
+		t.Fatal(err)
+	}
+	found, err := restarted.QueryByProperty(ctx, &pb.PropertyQueryRequest{IndexId: "tuned", Key: "k", Value: "new"})
+	if err != nil || found.Count != 1 {
+		t.Errorf("QueryByProperty after restart: %v, %v; want the new object", found.GetCount(), err)
+	}
+	after, err := restarted.GetStats(ctx, &pb.StatsRequest{IndexId: "tuned"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Stats.QuadtreeDepth != before.Stats.QuadtreeDepth {
+		t.Errorf("quadtree depth after restart = %d, want %d", after.Stats.QuadtreeDepth, before.Stats.QuadtreeDepth)
+	}
+
+	// Snapping, deduplication and simplification of new inserts
+	first, err := restarted.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "tuned", X: 2.4, Y: 2.4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := restarted.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "tuned", X: 1.6, Y: 2.2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ObjectId != first.ObjectId {
+		t.Errorf("point snapped onto another: IDs %d and %d, want one deduplicated object", first.ObjectId, again.ObjectId)
+	}
+	if p, _ := restarted.GetObject(ctx, &pb.GetObjectRequest{IndexId: "tuned", ObjectId: first.ObjectId}); p.GetObject().GetPoint().GetX() != 2 {
+		t.Errorf("point after restart = %v, want it snapped to (2, 2)", p.GetObject().GetPoint())
+	}
+	line, err := restarted.InsertLineString(ctx, &pb.InsertLineStringRequest{IndexId: "tuned", Points: []*pb.Point{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 10, Y: 0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, _ := restarted.GetObject(ctx, &pb.GetObjectRequest{IndexId: "tuned", ObjectId: line.ObjectId}); len(l.GetObject().GetLine().GetPoints()) != 2 {
+		t.Errorf("line after restart = %v, want it simplified to 2 points", l.GetObject().GetLine().GetPoints())
+	}
+
+	// Duplicates kept across the antimeridian, and a stale scan instead of
+	// ErrNotBuilt after a change
+	if _, err := restarted.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "wide", X: 175, Y: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	query, err := restarted.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "wide", Range: &pb.MBR{MinX: 170, MinY: -1, MaxX: -170, MaxY: 1}})
+	if err != nil {
+		t.Fatalf("query after a change: %v, want a stale scan", err)
+	}
+	if query.Count != 3 {
+		t.Errorf("query across the antimeridian found %d, want the line twice and the point", query.Count)
+	}
+}
+
+func TestEstimateCount(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "est", Config: &pb.Config{PageCapacity: 4}}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "est", X: float64(i % 10), Y: float64(i / 10)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	region := &pb.MBR{MinX: 2, MinY: 2, MaxX: 5, MaxY: 5}
+	if _, err := s.EstimateCount(ctx, &pb.EstimateCountRequest{IndexId: "est", Range: region}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("unbuilt index: got %v, want FailedPrecondition", err)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "est"}); err != nil {
+		t.Fatal(err)
+	}
+
+	est, err := s.EstimateCount(ctx, &pb.EstimateCountRequest{IndexId: "est", Range: region})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "est", Range: region})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if est.EstimatedCount < resp.Count || est.EstimatedCount > 100 {
+		t.Errorf("estimate %d for %d matches", est.EstimatedCount, resp.Count)
+	}
+}
+
+func TestBuildThreads(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "par", Config: &pb.Config{BuildThreads: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	idx, release, _ := s.getIndex("par")
+	defer release()
+	for i := 0; i < 10000; i++ {
+		idx.InsertPoint(float64(i%100), float64(i/100))
+	}
+
+	resp, err := s.Build(ctx, &pb.BuildRequest{IndexId: "par"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.BuildThreads != 2 {
+		t.Errorf("build used %d threads, want 2", resp.BuildThreads)
+	}
+	desc, err := s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "par"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Config.BuildThreads != 2 || desc.Stats.BuildThreads != 2 {
+		t.Errorf("config build_threads %d, stats %d", desc.Config.BuildThreads, desc.Stats.BuildThreads)
+	}
+}
+
+func TestBuildSeed(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	var stats []*pb.Stats
+	for _, id := range []string{"a", "b"} {
+		if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: id, Config: &pb.Config{PageCapacity: 8, Seed: 42}}); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 200; i++ {
+			if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: id, X: float64(i % 4), Y: float64(i * 37 % 101)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: id}); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := s.GetStats(ctx, &pb.StatsRequest{IndexId: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats = append(stats, resp.Stats)
+	}
+	if !proto.Equal(stats[0], stats[1]) {
+		t.Errorf("stats differ with the same seed:\n%v\n%v", stats[0], stats[1])
+	}
+
+	desc, err := s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Config.Seed != 42 {
+		t.Errorf("config seed = %d, want 42", desc.Config.Seed)
+	}
+}
+
+func TestQueryBuffered(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "roads"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*pb.InsertPointRequest{{IndexId: "roads", X: 5, Y: 0.5}, {IndexId: "roads", X: 5, Y: 3}} {
+		if _, err := s.InsertPoint(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "roads"}); err != nil {
+		t.Fatal(err)
+	}
+
+	route := &pb.SpatialObject{Geometry: &pb.SpatialObject_Line{Line: &pb.LineString{
+		Points: []*pb.Point{{X: 0, Y: 0}, {X: 10, Y: 0}},
+	}}}
+	resp, err := s.QueryBuffered(ctx, &pb.BufferQueryRequest{IndexId: "roads", Geometry: route, Distance: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 1 || resp.Objects[0].GetPoint().GetY() != 0.5 {
+		t.Errorf("within 1 of the route: %v", resp.Objects)
+	}
+
+	if _, err := s.QueryBuffered(ctx, &pb.BufferQueryRequest{IndexId: "roads", Geometry: route}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("zero distance: got %v, want InvalidArgument", err)
+	}
+	if _, err := s.QueryBuffered(ctx, &pb.BufferQueryRequest{IndexId: "roads", Distance: 1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("no geometry: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestMarkReadOnly(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "replica"}); err != nil {
+		t.Fatal(err)
+	}
+	ins, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "replica", X: 1, Y: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "replica"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.MarkReadOnly(ctx, &pb.MarkReadOnlyRequest{IndexId: "replica"})
+	if err != nil || resp.WasReadOnly {
+		t.Fatalf("MarkReadOnly: %v, err %v", resp, err)
+	}
+	if resp, _ := s.MarkReadOnly(ctx, &pb.MarkReadOnlyRequest{IndexId: "replica"}); !resp.WasReadOnly {
+		t.Error("second MarkReadOnly: was_read_only not set")
+	}
+
+	if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "replica", X: 2, Y: 2}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("insert: got %v, want FailedPrecondition", err)
+	}
+	if _, err := s.Remove(ctx, &pb.RemoveRequest{IndexId: "replica", ObjectId: ins.ObjectId}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("remove: got %v, want FailedPrecondition", err)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "replica"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("build: got %v, want FailedPrecondition", err)
+	}
+
+	query, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "replica", Range: &pb.MBR{MaxX: 5, MaxY: 5}})
+	if err != nil || query.Count != 1 {
+		t.Errorf("query: %v, err %v", query, err)
+	}
+	desc, err := s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "replica"})
+	if err != nil || !desc.ReadOnly {
+		t.Errorf("describe: read_only %v, err %v", desc.GetReadOnly(), err)
+	}
+
+	// The replacement of a read-only index is read-only too
+	if _, err := s.ReloadIndex(ctx, &pb.ReloadIndexRequest{IndexId: "replica", Source: &pb.ReloadIndexRequest_Geojson{Geojson: `{"type":"Point","coordinates":[3,3]}`}, Build: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "replica", X: 2, Y: 2}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("insert after reload: got %v, want FailedPrecondition", err)
+	}
+}
+
+func TestInsertTTLOnDuplicatePoint(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "fleet", Config: &pb.Config{DedupPoints: true}}); err != nil {
+		t.Fatal(err)
+	}
+	kept, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "fleet", X: 1, Y: 1, TtlMs: 3600000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The duplicate is counted against the first point, whose TTL stands
+	dup, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "fleet", X: 1, Y: 1, TtlMs: 1})
+	if err != nil || dup.ObjectId != kept.ObjectId {
+		t.Fatalf("duplicate insert = %v, %v; want object %d", dup, err, kept.ObjectId)
+	}
+	time.Sleep(5 * time.Millisecond)
+	resp, err := s.SweepExpired(ctx, &pb.SweepExpiredRequest{IndexId: "fleet"})
+	if err != nil || resp.Removed != 0 || resp.Count != 1 {
+		t.Errorf("SweepExpired = %v, %v; want the point kept", resp, err)
+	}
+}
+
+func TestSweepExpired(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "fleet"}); err != nil {
+		t.Fatal(err)
+	}
+	short, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "fleet", X: 1, Y: 1, TtlMs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "fleet", X: 2, Y: 2, TtlMs: 3600000}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "fleet", X: 3, Y: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A TTL too long to schedule is refused before anything is inserted
+	_, err = s.InsertLineString(ctx, &pb.InsertLineStringRequest{IndexId: "fleet", Points: []*pb.Point{{X: 0, Y: 0}, {X: 4, Y: 4}}, TtlMs: math.MaxUint64})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ttl_ms overflowing a duration: err = %v, want InvalidArgument", err)
+	}
+	if stats, _ := s.GetStats(ctx, &pb.StatsRequest{IndexId: "fleet"}); stats.Stats.TotalObjects != 3 {
+		t.Errorf("objects after the refused insert = %d, want 3", stats.Stats.TotalObjects)
+	}
+
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "fleet"}); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	resp, err := s.SweepExpired(ctx, &pb.SweepExpiredRequest{IndexId: "fleet"})
+	if err != nil || resp.Removed != 1 || resp.Count != 2 {
+		t.Fatalf("SweepExpired: %v, err %v", resp, err)
+	}
+	if got, _ := s.GetObject(ctx, &pb.GetObjectRequest{IndexId: "fleet", ObjectId: short.ObjectId}); got.Found {
+		t.Error("expired object still present")
+	}
+	query, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "fleet", Range: &pb.MBR{MaxX: 5, MaxY: 5}})
+	if err != nil || query.Count != 2 {
+		t.Errorf("query after sweep: %v, err %v", query, err)
+	}
+
+	sweepCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		s.RunSweeper(sweepCtx, time.Millisecond)
+		close(done)
+	}()
+	cancel()
+	<-done
+
+	if _, err := s.SweepExpired(ctx, &pb.SweepExpiredRequest{IndexId: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("missing index: got %v, want NotFound", err)
+	}
+}
+
+func TestInsertZM(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "terrain"}); err != nil {
+		t.Fatal(err)
+	}
+	z := func(v float64) *float64 { return &v }
+
+	line, err := s.InsertLineString(ctx, &pb.InsertLineStringRequest{IndexId: "terrain", Points: []*pb.Point{
+		{X: 0, Y: 0, Z: z(100)}, {X: 5, Y: 5, Z: z(120)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.GetObject(ctx, &pb.GetObjectRequest{IndexId: "terrain", ObjectId: line.ObjectId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := got.Object.GetLine().GetPoints()
+	if len(points) != 2 || points[1].Z == nil || points[1].GetZ() != 120 || points[1].M != nil {
+		t.Errorf("line points = %v", points)
+	}
+
+	point, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "terrain", X: 1, Y: 1, M: z(7)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ = s.GetObject(ctx, &pb.GetObjectRequest{IndexId: "terrain", ObjectId: point.ObjectId})
+	if p := got.Object.GetPoint(); p.Z != nil || p.GetM() != 7 {
+		t.Errorf("point = %v", p)
+	}
+
+	_, err = s.InsertLineString(ctx, &pb.InsertLineStringRequest{IndexId: "terrain", Points: []*pb.Point{
+		{X: 0, Y: 0, Z: z(1)}, {X: 1, Y: 1},
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("z on some points only: err = %v, want InvalidArgument", err)
+	}
+
+	// Simplification would keep 2 of 3 vertices, so a z per point is
+	// refused before anything is inserted
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "simple", Config: &pb.Config{SimplifyTolerance: 0.1}}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.InsertLineString(ctx, &pb.InsertLineStringRequest{IndexId: "simple", Points: []*pb.Point{
+		{X: 0, Y: 0, Z: z(1)}, {X: 1, Y: 0.01, Z: z(2)}, {X: 2, Y: 0, Z: z(3)},
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("z for vertices simplification drops: err = %v, want InvalidArgument", err)
+	}
+	if stats, _ := s.GetStats(ctx, &pb.StatsRequest{IndexId: "simple"}); stats.Stats.TotalObjects != 0 {
+		t.Errorf("objects after the refused insert = %d, want 0", stats.Stats.TotalObjects)
+	}
+
+	// A point counted against an existing one keeps that object's z
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "dedup", Config: &pb.Config{DedupPoints: true}}); err != nil {
+		t.Fatal(err)
+	}
+	kept, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "dedup", X: 1, Y: 1, Z: z(10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dup, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "dedup", X: 1, Y: 1, Z: z(20), M: z(3)}); err != nil || dup.ObjectId != kept.ObjectId {
+		t.Fatalf("duplicate insert = %v, %v; want object %d", dup, err, kept.ObjectId)
+	}
+	got, _ = s.GetObject(ctx, &pb.GetObjectRequest{IndexId: "dedup", ObjectId: kept.ObjectId})
+	if p := got.Object.GetPoint(); p.GetZ() != 10 || p.M != nil {
+		t.Errorf("point after the duplicate insert = %v, want z 10 and no m", p)
+	}
+}
+
+func TestQueryRangeDedupe(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "equator", Config: &pb.Config{Crs: 4326}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.InsertLineString(ctx, &pb.InsertLineStringRequest{IndexId: "equator", Points: []*pb.Point{{X: -180, Y: 0}, {X: 180, Y: 0}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "equator"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "equator", Range: &pb.MBR{MinX: 170, MinY: -1, MaxX: -170, MaxY: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 1 || resp.QueryStats.GetDuplicatesSuppressed() != 1 {
+		t.Errorf("count %d, duplicates suppressed %d, want 1 and 1", resp.Count, resp.QueryStats.GetDuplicatesSuppressed())
+	}
+}
+
+func TestBoundsMeters(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	for id, crs := range map[string]int32{"geo": 4326, "plain": 0} {
+		if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: id, Config: &pb.Config{Crs: crs}}); err != nil {
+			t.Fatal(err)
+		}
+		s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: id, X: 0, Y: 0})
+		s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: id, X: 1, Y: 0})
+	}
+
+	bounds, err := s.GetBounds(ctx, &pb.BoundsRequest{IndexId: "geo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bounds.WidthMeters == nil || math.Abs(bounds.GetWidthMeters()-111319.49) > 0.01 || bounds.GetHeightMeters() != 0 {
+		t.Errorf("geo bounds extent = %v x %v", bounds.WidthMeters, bounds.HeightMeters)
+	}
+	stats, _ := s.GetStats(ctx, &pb.StatsRequest{IndexId: "geo"})
+	if stats.Stats.WidthMeters == nil {
+		t.Error("geo stats have no extent in meters")
+	}
+	if bounds, _ := s.GetBounds(ctx, &pb.BoundsRequest{IndexId: "plain"}); bounds.WidthMeters != nil {
+		t.Errorf("plain index bounds have an extent in meters: %v", bounds.GetWidthMeters())
+	}
+}
+
+func TestLoadGeoJSONSkipped(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "sparse"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.LoadGeoJSONString(ctx, &pb.LoadGeoJSONStringRequest{IndexId: "sparse", Geojson: `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":null,"properties":{}},
+		{"type":"Feature","geometry":{"type":"MultiPoint","coordinates":[]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[3,4]},"properties":{}}
+	]}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ObjectsLoaded != 1 || resp.Skipped != 2 || resp.Count != 1 {
+		t.Errorf("load response = %v, want 1 loaded and 2 skipped", resp)
+	}
+}
+
+func TestQueryGeomTypes(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "mixed"}); err != nil {
+		t.Fatal(err)
+	}
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "mixed", X: 5, Y: 5})
+	s.InsertLineString(ctx, &pb.InsertLineStringRequest{IndexId: "mixed", Points: []*pb.Point{{X: 1, Y: 1}, {X: 4, Y: 4}}})
+	if _, err := s.InsertPolygon(ctx, &pb.InsertPolygonRequest{IndexId: "mixed", Exterior: []*pb.Point{{X: 2, Y: 2}, {X: 6, Y: 2}, {X: 6, Y: 6}, {X: 2, Y: 6}, {X: 2, Y: 2}}}); err != nil {
+		t.Fatal(err)
+	}
+	s.Build(ctx, &pb.BuildRequest{IndexId: "mixed"})
+
+	region := &pb.MBR{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}
+	resp, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "mixed", Range: region,
+		GeomTypes: []pb.GeomType{pb.GeomType_GEOM_POLYGON, pb.GeomType_GEOM_POINT}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 2 {
+		t.Fatalf("count = %d, want 2", resp.Count)
+	}
+	for _, obj := range resp.Objects {
+		if obj.Type == pb.GeomType_GEOM_LINESTRING {
+			t.Errorf("object %d is a linestring", obj.Id)
+		}
+	}
+	if all, _ := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "mixed", Range: region}); all.Count != 3 {
+		t.Errorf("unfiltered count = %d, want 3", all.Count)
+	}
+
+	point, err := s.QueryPoint(ctx, &pb.PointQueryRequest{IndexId: "mixed", X: 5, Y: 5, GeomTypes: []pb.GeomType{pb.GeomType_GEOM_POLYGON}})
+	if err != nil || point.Count != 1 || point.Objects[0].Type != pb.GeomType_GEOM_POLYGON {
+		t.Errorf("QueryPoint polygons = %v, %v", point, err)
+	}
+
+	_, err = s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "mixed", Range: region, GeomTypes: []pb.GeomType{42}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown type: err = %v, want InvalidArgument", err)
+	}
+}
+
+func TestBuildCancelled(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "slow"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "slow", X: float64(i), Y: float64(i)})
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "slow"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := s.Build(cancelled, &pb.BuildRequest{IndexId: "slow"})
+	if status.Code(err) != codes.Canceled {
+		t.Fatalf("cancelled build: err = %v, want Canceled", err)
+	}
+	desc, err := s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "slow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Built {
+		t.Error("index reports built after a cancelled build")
+	}
+	_, err = s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "slow", Range: &pb.MBR{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("query after cancelled build: err = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestLoadGeoJSONDryRun(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "staging"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.LoadGeoJSONString(ctx, &pb.LoadGeoJSONStringRequest{IndexId: "staging", DryRun: true, Geojson: `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"Polygon","coordinates":"oops"},"properties":{}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[3,4]},"properties":{}}
+	]}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ObjectsLoaded != 2 || resp.Count != 0 || len(resp.Errors) != 1 || !strings.HasPrefix(resp.Errors[0], "feature 1: ") {
+		t.Errorf("dry run response = %v, want 2 would-be objects, 1 error and an empty index", resp)
+	}
+
+	_, err = s.LoadGeoJSONString(ctx, &pb.LoadGeoJSONStringRequest{IndexId: "staging", DryRun: true, Geojson: `{"type":`})
+	if err == nil {
+		t.Error("dry run of an unreadable document succeeded")
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	s := NewUrbisServer(WithAPIKeys(map[string][]string{"team-a-key": {"team-a/"}, "admin-key": {""}}))
+	intercept := s.UnaryAuthInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.ListIndexesResponse{IndexIds: []string{"team-a/roads", "team-b/roads"}}, nil
+	}
+	call := func(key, method string, req interface{}) (interface{}, error) {
+		ctx := context.Background()
+		if key != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(APIKeyHeader, key))
+		}
+		return intercept(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+
+	if _, err := call("", pb.UrbisService_GetCount_FullMethodName, &pb.CountRequest{IndexId: "team-a/roads"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("no key: err = %v, want Unauthenticated", err)
+	}
+	if _, err := call("guess", pb.UrbisService_GetCount_FullMethodName, &pb.CountRequest{IndexId: "team-a/roads"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("unknown key: err = %v, want Unauthenticated", err)
+	}
+	if _, err := call("team-a-key", pb.UrbisService_GetCount_FullMethodName, &pb.CountRequest{IndexId: "team-a/roads"}); err != nil {
+		t.Errorf("own index: %v", err)
+	}
+	if _, err := call("team-a-key", pb.UrbisService_GetCount_FullMethodName, &pb.CountRequest{IndexId: "team-b/roads"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("other team's index: err = %v, want PermissionDenied", err)
+	}
+	if _, err := call("team-a-key", pb.UrbisService_GetResourceStats_FullMethodName, &pb.ResourceStatsRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("server-wide call: err = %v, want PermissionDenied", err)
+	}
+	if _, err := call("admin-key", pb.UrbisService_GetResourceStats_FullMethodName, &pb.ResourceStatsRequest{}); err != nil {
+		t.Errorf("admin server-wide call: %v", err)
+	}
+	multi := &pb.QueryRangeMultiRequest{IndexIds: []string{"team-a/roads", "team-b/roads"}}
+	if _, err := call("team-a-key", pb.UrbisService_QueryRangeMulti_FullMethodName, multi); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("fan-out into another team's index: err = %v, want PermissionDenied", err)
+	}
+	multi.IndexIds = multi.IndexIds[:1]
+	if _, err := call("team-a-key", pb.UrbisService_QueryRangeMulti_FullMethodName, multi); err != nil {
+		t.Errorf("fan-out over own indexes: %v", err)
+	}
+	if _, err := call("team-a-key", pb.UrbisService_GetServerInfo_FullMethodName, &pb.ServerInfoRequest{}); err != nil {
+		t.Errorf("server info with a limited key: %v", err)
+	}
+	if _, err := call("", "/grpc.health.v1.Health/Check", nil); err != nil {
+		t.Errorf("health check without a key: %v", err)
+	}
+
+	resp, err := call("team-a-key", pb.UrbisService_ListIndexes_FullMethodName, &pb.ListIndexesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := resp.(*pb.ListIndexesResponse).IndexIds; !slices.Equal(ids, []string{"team-a/roads"}) {
+		t.Errorf("ListIndexes = %v, want only team-a/roads", ids)
+	}
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	os.WriteFile(path, []byte(`{"k1": ["a/"], "k2": []}`), 0o600)
+	if _, err := LoadAPIKeys(path); err == nil {
+		t.Error("LoadAPIKeys accepted a key without prefixes")
+	}
+}
+
+func TestQueryCRS(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	for id, crs := range map[string]int32{"mercator": 3857, "plain": 0} {
+		if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: id, Config: &pb.Config{Crs: crs}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A square from (10, 50) to (11, 51) in longitude and latitude
+	var square []*pb.Point
+	for _, p := range []urbis.Point{{X: 10, Y: 50}, {X: 11, Y: 50}, {X: 11, Y: 51}, {X: 10, Y: 51}, {X: 10, Y: 50}} {
+		m, _ := urbis.Transform(p, urbis.CRSWGS84, urbis.CRSWebMercator)
+		square = append(square, &pb.Point{X: m.X, Y: m.Y})
+	}
+	if _, err := s.InsertPolygon(ctx, &pb.InsertPolygonRequest{IndexId: "mercator", Exterior: square}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "mercator"}); err != nil {
+		t.Fatal(err)
+	}
+
+	near := func(got *pb.Point, x, y float64) bool {
+		return math.Abs(got.X-x) < 1e-6 && math.Abs(got.Y-y) < 1e-6
+	}
+	resp, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "mercator", QueryCrs: 4326, Range: &pb.MBR{MinX: 10.2, MinY: 50.2, MaxX: 10.4, MaxY: 50.4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 1 {
+		t.Fatalf("range in EPSG:4326 found %d objects, want 1", resp.Count)
+	}
+	if exterior := resp.Objects[0].GetPolygon().GetExterior(); len(exterior) != 5 || !near(exterior[2], 11, 51) {
+		t.Errorf("polygon came back as %v, want it in longitude and latitude", exterior)
+	}
+	if mbr := resp.Objects[0].Mbr; math.Abs(mbr.MinX-10) > 1e-6 || math.Abs(mbr.MaxY-51) > 1e-6 {
+		t.Errorf("mbr = %v, want it in longitude and latitude", mbr)
+	}
+
+	point, err := s.QueryContaining(ctx, &pb.PointQueryRequest{IndexId: "mercator", QueryCrs: 4326, X: 10.5, Y: 50.5})
+	if err != nil || point.Count != 1 {
+		t.Fatalf("containing query = %v, %v", point, err)
+	}
+	knn, err := s.QueryKNN(ctx, &pb.KNNQueryRequest{IndexId: "mercator", QueryCrs: 4326, X: 12, Y: 52, K: 1,
+		Encoding: pb.GeometryEncoding_GEOMETRY_ENCODING_GEOJSON})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fc struct {
+		Features []struct {
+			Geometry struct{ Coordinates [][][2]float64 }
+		}
+	}
+	if err := json.Unmarshal([]byte(knn.Geojson), &fc); err != nil || len(fc.Features) != 1 {
+		t.Fatalf("KNN GeoJSON %s: %v", knn.Geojson, err)
+	}
+	if c := fc.Features[0].Geometry.Coordinates[0][2]; !near(&pb.Point{X: c[0], Y: c[1]}, 11, 51) {
+		t.Errorf("KNN GeoJSON is not in longitude and latitude: %s", knn.Geojson)
+	}
+
+	// Without query_crs the same box is in meters and misses the polygon
+	if resp, _ := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "mercator", Range: &pb.MBR{MinX: 10.2, MinY: 50.2, MaxX: 10.4, MaxY: 50.4}}); resp.Count != 0 {
+		t.Errorf("range in index units found %d objects, want 0", resp.Count)
+	}
+
+	for _, tc := range []struct {
+		req  *pb.RangeQueryRequest
+		want codes.Code
+	}{
+		{&pb.RangeQueryRequest{IndexId: "mercator", QueryCrs: 27700, Range: &pb.MBR{}}, codes.InvalidArgument},
+		{&pb.RangeQueryRequest{IndexId: "mercator", QueryCrs: 4326, Range: &pb.MBR{MaxX: 1, MaxY: 91}}, codes.InvalidArgument},
+		{&pb.RangeQueryRequest{IndexId: "mercator", QueryCrs: 4326, Range: &pb.MBR{MaxX: 1, MaxY: 1},
+			Encoding: pb.GeometryEncoding_GEOMETRY_ENCODING_WKB}, codes.InvalidArgument},
+		{&pb.RangeQueryRequest{IndexId: "plain", QueryCrs: 4326, Range: &pb.MBR{MaxX: 1, MaxY: 1}}, codes.FailedPrecondition},
+	} {
+		if _, err := s.QueryRange(ctx, tc.req); status.Code(err) != tc.want {
+			t.Errorf("query_crs %d on %s: err = %v, want %v", tc.req.QueryCrs, tc.req.IndexId, err, tc.want)
+		}
+	}
+}
+
+func TestQueryKNNDistanceMetric(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "river"}); err != nil {
+		t.Fatal(err)
+	}
+	// The river's bank is 1 from the origin but its centroid is 200 away
+	river, err := s.InsertPolygon(ctx, &pb.InsertPolygonRequest{IndexId: "river", Exterior: []*pb.Point{
+		{X: 0, Y: 1}, {X: 400, Y: 1}, {X: 400, Y: 3}, {X: 0, Y: 3}, {X: 0, Y: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	well, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "river", X: 5, Y: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "river"}); err != nil {
+		t.Fatal(err)
+	}
+
+	centroid, err := s.QueryKNN(ctx, &pb.KNNQueryRequest{IndexId: "river", K: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if centroid.Objects[0].Id != well.ObjectId || !slices.Equal(centroid.Distances, []float64{5}) {
+		t.Errorf("centroid KNN = object %d at %v, want %d at 5", centroid.Objects[0].Id, centroid.Distances, well.ObjectId)
+	}
+	edge, err := s.QueryKNN(ctx, &pb.KNNQueryRequest{IndexId: "river", K: 2, DistanceMetric: pb.DistanceMetric_DISTANCE_METRIC_NEAREST_EDGE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edge.Count != 2 || edge.Objects[0].Id != river.ObjectId || !slices.Equal(edge.Distances, []float64{1, 5}) {
+		t.Errorf("nearest-edge KNN = %d objects, first %d, distances %v", edge.Count, edge.Objects[0].Id, edge.Distances)
+	}
+
+	if _, err := s.QueryKNN(ctx, &pb.KNNQueryRequest{IndexId: "river", K: 1, DistanceMetric: 9}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown metric: err = %v, want InvalidArgument", err)
+	}
+}
+
+func TestAutoRebuildStale(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "live", Config: &pb.Config{AutoRebuildThreshold: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "live", X: 1, Y: 1})
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "live"}); err != nil {
+		t.Fatal(err)
+	}
+
+	region := &pb.MBR{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "live", X: 2, Y: 2})
+	resp, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "live", Range: region})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Stale || resp.Count != 2 {
+		t.Errorf("one change pending: stale %v, count %d, want a stale result of 2", resp.Stale, resp.Count)
+	}
+	multi, err := s.MultiQueryRange(ctx, &pb.MultiRangeQueryRequest{IndexId: "live", Ranges: []*pb.MBR{region}})
+	if err != nil || !multi.Results[0].Stale {
+		t.Errorf("MultiQueryRange with one change pending = %v, %v", multi, err)
+	}
+
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "live", X: 3, Y: 3})
+	resp, err = s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "live", Range: region})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Stale || resp.Count != 3 {
+		t.Errorf("at the threshold: stale %v, count %d, want a fresh result of 3", resp.Stale, resp.Count)
+	}
+
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "live", X: 4, Y: 4})
+	desc, err := s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "live"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.PendingChanges != 1 || desc.Config.GetAutoRebuildThreshold() != 2 {
+		t.Errorf("DescribeIndex: %d pending changes, threshold %d", desc.PendingChanges, desc.Config.GetAutoRebuildThreshold())
+	}
+}
+
+func TestLoadGeoPackage(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "gpkg"}); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "wells.gpkg")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A little-endian WKB point (3, 4) behind an 8-byte GeoPackage header
+	point := []byte{'G', 'P', 0, 1, 0, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 8, 64, 0, 0, 0, 0, 0, 0, 16, 64}
+	for _, stmt := range []string{
+		`CREATE TABLE gpkg_spatial_ref_sys (srs_id INTEGER PRIMARY KEY, organization TEXT, organization_coordsys_id INTEGER)`,
+		`CREATE TABLE gpkg_contents (table_name TEXT PRIMARY KEY, data_type TEXT)`,
+		`CREATE TABLE gpkg_geometry_columns (table_name TEXT, column_name TEXT, srs_id INTEGER)`,
+		`CREATE TABLE wells (fid INTEGER PRIMARY KEY, shape BLOB, depth REAL)`,
+		`INSERT INTO gpkg_spatial_ref_sys VALUES (0, 'NONE', 0)`,
+		`INSERT INTO gpkg_contents VALUES ('wells', 'features')`,
+		`INSERT INTO gpkg_geometry_columns VALUES ('wells', 'shape', 0)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(`INSERT INTO wells (shape, depth) VALUES (?, 12.5), (NULL, 3)`, point); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	resp, err := s.LoadGeoPackage(ctx, &pb.LoadGeoPackageRequest{IndexId: "gpkg", Path: path, Layer: "wells"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ObjectsLoaded != 1 || resp.Skipped != 1 || resp.Bounds.MinX != 3 || resp.Bounds.MinY != 4 {
+		t.Errorf("load response = %v, want the well at (3, 4) loaded and one skipped", resp)
+	}
+	props, err := s.GetProperties(ctx, &pb.GetPropertiesRequest{IndexId: "gpkg", ObjectId: 1})
+	if err != nil || string(props.Properties) != `{"depth":12.5}` {
+		t.Errorf("properties = %v, %v", props, err)
+	}
+
+	_, err = s.LoadGeoPackage(ctx, &pb.LoadGeoPackageRequest{IndexId: "gpkg", Path: path, Layer: "roads"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("missing layer: code %v, want NotFound", status.Code(err))
+	}
+}
+
+func TestGetServerInfo(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer(WithServerInfo("v1.2.3", true))
+	s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "a"})
+	s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "b"})
+
+	info, err := s.GetServerInfo(ctx, &pb.ServerInfoRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.LibraryVersion != urbis.Version() || info.ServerVersion != "v1.2.3" || info.IndexCount != 2 || !info.ReflectionEnabled ||
+		info.FileFormatVersion != urbis.FormatVersion() {
+		t.Errorf("server info = %v", info)
+	}
+
+	if info, _ := NewUrbisServer().GetServerInfo(ctx, &pb.ServerInfoRequest{}); info.ServerVersion != "dev" || info.ReflectionEnabled {
+		t.Errorf("server info without WithServerInfo = %v", info)
+	}
+}
+
+func TestLoadNewerFormat(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"})
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 1, Y: 2})
+
+	path := filepath.Join(t.TempDir(), "city.dat")
+	if _, err := s.Save(ctx, &pb.SaveRequest{IndexId: "city", Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	// Stamp the file with the next format version, as a newer server would
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteAt(binary.LittleEndian.AppendUint32(nil, urbis.FormatVersion()+1), 4)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Load(ctx, &pb.LoadIndexRequest{IndexId: "restored", Path: path})
+	if status.Code(err) != codes.FailedPrecondition || !strings.Contains(err.Error(), "version") {
+		t.Errorf("Load of a newer format: err = %v, want FailedPrecondition", err)
+	}
+	if _, _, err := s.getIndex("restored"); err == nil {
+		t.Error("index registered after a failed load")
+	}
+}
+
+func TestValidBounds(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	kolkata := &pb.MBR{MinX: 88.2, MinY: 22.4, MaxX: 88.5, MaxY: 22.7}
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "bad", Config: &pb.Config{ValidBounds: &pb.MBR{MinX: 1, MaxX: 0}}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("inverted valid_bounds: err = %v, want InvalidArgument", err)
+	}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city", Config: &pb.Config{ValidBounds: kolkata}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 22.57, Y: 88.36}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("swapped point: err = %v, want InvalidArgument", err)
+	}
+	resp, err := s.LoadGeoJSONString(ctx, &pb.LoadGeoJSONStringRequest{IndexId: "city", Geojson: `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[88.36,22.57]}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[22.57,88.36]}}]}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ObjectsLoaded != 1 || resp.OutOfBounds != 1 {
+		t.Errorf("load = %d loaded, %d out of bounds, want 1 and 1", resp.ObjectsLoaded, resp.OutOfBounds)
+	}
+
+	desc, err := s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: "city"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(desc.Config.ValidBounds, kolkata) {
+		t.Errorf("described valid_bounds = %v, want %v", desc.Config.ValidBounds, kolkata)
+	}
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "city.urbis")
+	s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city", Config: &pb.Config{Persist: true, DataPath: dir}})
+	s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "scratch"})
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 1, Y: 1})
+
+	savedCount := func() uint64 {
+		t.Helper()
+		idx, err := urbis.Load(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer idx.Close()
+		return idx.Count()
+	}
+
+	if n := s.FlushAll(); n != 1 {
+		t.Fatalf("FlushAll saved %d indexes, want 1", n)
+	}
+	if got := savedCount(); got != 1 {
+		t.Errorf("data file holds %d objects, want 1", got)
+	}
+	if n := s.FlushAll(); n != 0 {
+		t.Errorf("FlushAll of an unchanged index saved %d, want 0", n)
+	}
+
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 2, Y: 2})
+	resp, err := s.Sync(ctx, &pb.SyncRequest{IndexId: "city"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Path != file || resp.ChangeCount != 2 {
+		t.Errorf("Sync = %v, want %s with 2 changes", resp, file)
+	}
+	if got := savedCount(); got != 2 {
+		t.Errorf("data file holds %d objects after Sync, want 2", got)
+	}
+	if _, err := s.Sync(ctx, &pb.SyncRequest{IndexId: "scratch"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Sync of an index without a data_path: err = %v, want FailedPrecondition", err)
+	}
+
+	// The background syncer picks up later writes
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		s.RunSyncer(runCtx, 10*time.Millisecond)
+		close(done)
+	}()
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 3, Y: 3})
+	for deadline := time.Now().Add(5 * time.Second); savedCount() != 3; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("background sync did not save the third point")
+		}
+	}
+	cancel()
+	<-done
+
+	if _, err := os.Stat(file + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
+
+func TestFlushDuringWrites(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "city.urbis")
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city", Config: &pb.Config{Persist: true, DataPath: dir}}); err != nil {
+		t.Fatal(err)
+	}
+
+	savedCount := func() uint64 {
+		t.Helper()
+		idx, err := urbis.Load(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer idx.Close()
+		return idx.Count()
+	}
+
+	const writers, perWriter = 4, 50
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: float64(w), Y: float64(i)}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(w)
+	}
+	stop := make(chan struct{})
+	flushed := make(chan struct{})
+	go func() {
+		defer close(flushed)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				s.FlushAll()
+			}
+		}
+	}()
+	wg.Wait()
+	close(stop)
+	<-flushed
+
+	// The last flush saves whatever the ones racing the writers missed
+	s.FlushAll()
+	if got := savedCount(); got != writers*perWriter {
+		t.Errorf("data file holds %d objects, want %d", got, writers*perWriter)
+	}
+
+	// Flushes leave the data file where it is, so a compaction replaces it
+	// rather than a temporary file
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 9, Y: 9})
+	if _, err := s.Compact(ctx, &pb.CompactRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := savedCount(); got != writers*perWriter+1 {
+		t.Errorf("data file holds %d objects after Compact, want %d", got, writers*perWriter+1)
+	}
+	if _, err := os.Stat(file + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
+
+func TestQueryResultBounds(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"})
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 2, Y: 3})
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 5, Y: 1})
+	s.InsertPolygon(ctx, &pb.InsertPolygonRequest{IndexId: "city", Exterior: []*pb.Point{{X: 4, Y: 4}, {X: 6, Y: 4}, {X: 6, Y: 7}, {X: 4, Y: 7}, {X: 4, Y: 4}}})
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: 90, Y: 90})
+	s.Build(ctx, &pb.BuildRequest{IndexId: "city"})
+
+	resp, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "city", Range: &pb.MBR{MinX: 0, MinY: 0, MaxX: 50, MaxY: 50}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&pb.MBR{MinX: 2, MinY: 1, MaxX: 6, MaxY: 7}); !proto.Equal(resp.ResultBounds, want) {
+		t.Errorf("result_bounds = %v, want %v", resp.ResultBounds, want)
+	}
+
+	resp, err = s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "city", Range: &pb.MBR{MinX: 0, MinY: 0, MaxX: 50, MaxY: 50}, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&pb.MBR{MinX: 2, MinY: 3, MaxX: 2, MaxY: 3}); !proto.Equal(resp.ResultBounds, want) {
+		t.Errorf("first page result_bounds = %v, want %v", resp.ResultBounds, want)
+	}
+
+	resp, err = s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "city", Range: &pb.MBR{MinX: 20, MinY: 20, MaxX: 30, MaxY: 30}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ResultBounds != nil {
+		t.Errorf("empty result has result_bounds %v", resp.ResultBounds)
+	}
+}
+
+func TestQueryContainingPolygon(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"})
+	ward, _ := s.InsertPolygon(ctx, &pb.InsertPolygonRequest{IndexId: "city", Exterior: []*pb.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0}}})
+	s.InsertPolygon(ctx, &pb.InsertPolygonRequest{IndexId: "city", Exterior: []*pb.Point{{X: 10, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 0}}})
+	s.Build(ctx, &pb.BuildRequest{IndexId: "city"})
+
+	// A parcel on the shared border belongs to the ward it lies in
+	parcel := []*pb.Point{{X: 8, Y: 2}, {X: 10, Y: 2}, {X: 10, Y: 4}, {X: 8, Y: 4}}
+	resp, err := s.QueryContainingPolygon(ctx, &pb.ContainingPolygonQueryRequest{IndexId: "city", Polygon: parcel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 1 || resp.Objects[0].Id != ward.ObjectId {
+		t.Errorf("containing = %v, want ward %d", resp.Objects, ward.ObjectId)
+	}
+
+	straddling := []*pb.Point{{X: 9, Y: 2}, {X: 11, Y: 2}, {X: 11, Y: 4}, {X: 9, Y: 4}}
+	if resp, err = s.QueryContainingPolygon(ctx, &pb.ContainingPolygonQueryRequest{IndexId: "city", Polygon: straddling}); err != nil || resp.Count != 0 {
+		t.Errorf("parcel across the border: count = %d, err = %v, want none", resp.GetCount(), err)
+	}
+
+	if _, err := s.QueryContainingPolygon(ctx, &pb.ContainingPolygonQueryRequest{IndexId: "city", Polygon: parcel[:2]}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("two-point polygon: err = %v, want InvalidArgument", err)
+	}
+}
+
+func TestMaintenanceDeadline(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "city"})
+	for i := 0; i < 100; i++ {
+		s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "city", X: float64(i), Y: float64(i)})
+	}
+
+	expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := s.Build(expired, &pb.BuildRequest{IndexId: "city"}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("build past the deadline: err = %v, want DeadlineExceeded", err)
+	}
+	if _, err := s.Build(ctx, &pb.BuildRequest{IndexId: "city"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Optimize(expired, &pb.OptimizeRequest{IndexId: "city"}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("optimize past the deadline: err = %v, want DeadlineExceeded", err)
+	}
+	if _, err := s.Compact(expired, &pb.CompactRequest{IndexId: "city"}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("compact past the deadline: err = %v, want DeadlineExceeded", err)
+	}
+	if _, err := s.AutoTune(expired, &pb.AutoTuneRequest{IndexId: "city", Apply: true}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("autotune past the deadline: err = %v, want DeadlineExceeded", err)
+	}
+
+	// A timed-out maintenance call leaves the index serving
+	resp, err := s.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "city", Range: &pb.MBR{MinX: 0, MinY: 0, MaxX: 9, MaxY: 9}})
+	if err != nil || resp.Count != 10 {
+		t.Fatalf("query after timed-out maintenance = %v, %v; want 10 objects", resp, err)
+	}
+	if _, err := s.Optimize(ctx, &pb.OptimizeRequest{IndexId: "city"}); err != nil {
+		t.Errorf("optimize after the deadline: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "city.urbis")
+	if _, err := s.Save(expired, &pb.SaveRequest{IndexId: "city", Path: path}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("save past the deadline: err = %v, want DeadlineExceeded", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("save past the deadline wrote %s: %v", path, err)
+	}
+	if _, err := s.Save(ctx, &pb.SaveRequest{IndexId: "city", Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load(expired, &pb.LoadIndexRequest{IndexId: "copy", Path: path}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("load past the deadline: err = %v, want DeadlineExceeded", err)
+	}
+	if _, err := s.Load(ctx, &pb.LoadIndexRequest{IndexId: "copy", Path: path}); err != nil {
+		t.Errorf("load after a timed-out load: %v", err)
+	}
+}
+
+func TestDefaultConfig(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer(WithDefaultConfig(&pb.Config{CacheSize: 512, PageCapacity: 32, EnableQuadtree: true}))
+
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "bare"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "tuned", Config: &pb.Config{CacheSize: 16}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string]*pb.Config{
+		"bare":  {CacheSize: 512, PageCapacity: 32, EnableQuadtree: true},
+		"tuned": {CacheSize: 16, PageCapacity: 32},
+	} {
+		desc, err := s.DescribeIndex(ctx, &pb.DescribeIndexRequest{IndexId: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := desc.Config
+		if c.CacheSize != want.CacheSize || c.PageCapacity != want.PageCapacity || c.EnableQuadtree != want.EnableQuadtree {
+			t.Errorf("%s: config = %v, want cache %d, page capacity %d, quadtree %v",
+				id, c, want.CacheSize, want.PageCapacity, want.EnableQuadtree)
+		}
+	}
+
+	// An identical bare request matches the merged config
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "bare", IfNotExists: true}); err != nil {
+		t.Errorf("repeated bare create: %v", err)
+	}
+}
+
+func TestQueryRangeIncludePages(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "pages", Config: &pb.Config{PageCapacity: 4}}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 40; i++ {
+		s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "pages", X: float64(i), Y: float64(i)})
+	}
+	s.Build(ctx, &pb.BuildRequest{IndexId: "pages"})
+
+	req := &pb.RangeQueryRequest{IndexId: "pages", Range: &pb.MBR{MinX: 0, MinY: 0, MaxX: 39, MaxY: 39}}
+	resp, err := s.QueryRange(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Pages) != 0 {
+		t.Errorf("pages returned without include_pages: %v", resp.Pages)
+	}
+
+	req.IncludePages = true
+	if resp, err = s.QueryRange(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Pages) < 10 || uint64(len(resp.Pages)) != resp.QueryStats.PagesVisited {
+		t.Errorf("%d pages returned, stats say %d visited", len(resp.Pages), resp.QueryStats.PagesVisited)
+	}
+}
+
+func TestStreamLoadWKT(t *testing.T) {
+	ctx := context.Background()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterUrbisServiceServer(server, NewUrbisServer())
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewUrbisServiceClient(conn)
+
+	if _, err := client.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "lines"}); err != nil {
+		t.Fatal(err)
+	}
+	stream, err := client.StreamLoadWKT(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Lines split across chunks are joined before parsing
+	for i, chunk := range []string{"POINT (1 2)\nLINESTR", "ING (0 0, 3 3)\n\nPOINT (", "bad)\nPOINT (5 5)"} {
+		req := &pb.StreamLoadWKTRequest{Chunk: []byte(chunk)}
+		if i == 0 {
+			req.IndexId = "lines"
+		}
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ObjectsLoaded != 3 || resp.Count != 3 || len(resp.Errors) != 1 || !strings.HasPrefix(resp.Errors[0], "line 4: ") {
+		t.Errorf("response = %v, want 3 loaded and line 4 rejected", resp)
+	}
+
+	stream, err = client.StreamLoadWKT(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.Send(&pb.StreamLoadWKTRequest{IndexId: "missing", Chunk: []byte("POINT (1 1)")})
+	if _, err := stream.CloseAndRecv(); status.Code(err) != codes.NotFound {
+		t.Errorf("missing index: err = %v, want NotFound", err)
+	}
+}
+
+func TestQueryRangeMulti(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	// Two shards split at x = 10, and an unbuilt one. Both repeat the point
+	// at x = 9 as object 1 and the one at x = 5 under different IDs, and
+	// give ID 2 to different points.
+	for _, id := range []string{"west", "east", "fresh"} {
+		if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, p := range []struct {
+		index string
+		id    uint64
+		x     float64
+	}{{"west", 1, 9}, {"west", 2, 5}, {"east", 1, 9}, {"east", 2, 15}, {"east", 7, 5}, {"east", 4, 50}} {
+		if _, err := s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: p.index, X: p.x, Y: 1, ObjectId: p.id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Build(ctx, &pb.BuildRequest{IndexId: "west"})
+	s.Build(ctx, &pb.BuildRequest{IndexId: "east"})
+
+	req := &pb.QueryRangeMultiRequest{IndexIds: []string{"west", "east"}, Range: &pb.MBR{MinX: 0, MinY: 0, MaxX: 20, MaxY: 2}}
+	resp, err := s.QueryRangeMulti(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 5 || resp.Results["west"].Count != 2 || resp.Results["east"].Count != 3 {
+		t.Errorf("counts: total %d, west %d, east %d; want 5, 2, 3", resp.Count, resp.Results["west"].GetCount(), resp.Results["east"].GetCount())
+	}
+	if resp.Results["east"].QueryStats == nil {
+		t.Error("east has no query stats")
+	}
+
+	req.Deduplicate = true
+	if resp, err = s.QueryRangeMulti(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	east := resp.Results["east"]
+	if resp.Count != 3 || east.Count != 1 || east.Objects[0].Id != 2 || east.Objects[0].Centroid.X != 15 {
+		t.Errorf("deduplicated: total %d, east %v; want only east's own point at x = 15", resp.Count, east.Objects)
+	}
+
+	// A caller that has given up gets no results
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := s.QueryRangeMulti(cancelled, req); status.Code(err) != codes.Canceled {
+		t.Errorf("cancelled call: err = %v, want Canceled", err)
+	}
+
+	for _, tc := range []struct {
+		ids  []string
+		code codes.Code
+	}{
+		{nil, codes.InvalidArgument},
+		{[]string{"west", "west"}, codes.InvalidArgument},
+		{[]string{"west", "nowhere"}, codes.NotFound},
+		{[]string{"west", "fresh"}, codes.FailedPrecondition},
+	} {
+		req.IndexIds = tc.ids
+		_, err := s.QueryRangeMulti(ctx, req)
+		if status.Code(err) != tc.code {
+			t.Errorf("%v: err = %v, want %v", tc.ids, err, tc.code)
+		}
+		if tc.code == codes.FailedPrecondition && !strings.Contains(status.Convert(err).Message(), `index "fresh"`) {
+			t.Errorf("shard error %q does not name the index", status.Convert(err).Message())
+		}
+	}
+}
+
+func TestQueryRangeZeroArea(t *testing.T) {
+	ctx := context.Background()
+	s := NewUrbisServer()
+	if _, err := s.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "probe"}); err != nil {
+		t.Fatal(err)
+	}
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "probe", X: 3, Y: 4})
+	s.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "probe", X: 3, Y: 9})
+	s.Build(ctx, &pb.BuildRequest{IndexId: "probe"})
+
+	for _, tc := range []struct {
+		region *pb.MBR
+		want   uint64
+	}{
+		{&pb.MBR{MinX: 3, MinY: 4, MaxX: 3, MaxY: 4}, 1},
+		{&pb.MBR{MinX: 3, MinY: 0, MaxX: 3, MaxY: 10}, 2},
+		{&pb.MBR{MinX: 0, MinY: 4, MaxX: 10, MaxY: 4}, 1},
+		{&pb.MBR{MinX: 4, MinY: 4, MaxX: 4, MaxY: 4}, 0},
+	} {
+		req := &pb.RangeQueryRequest{IndexId: "probe", Range: tc.region}
+		if err := validateRequest(req); err != nil {
+			t.Errorf("%v rejected: %v", tc.region, err)
+			continue
+		}
+		resp, err := s.QueryRange(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Count != tc.want {
+			t.Errorf("%v found %d, want %d", tc.region, resp.Count, tc.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/urbis/api/pkg/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// mbrName is the full protobuf name of pb.MBR
+var mbrName = (&pb.MBR{}).ProtoReflect().Descriptor().FullName()
+
+// wrappingMBRFields are the MBR fields where MinX greater than MaxX is
+// allowed, meaning the region crosses the antimeridian
+var wrappingMBRFields = map[protoreflect.FullName]bool{
+	"urbis.RangeQueryRequest.range":       true,
+	"urbis.MultiRangeQueryRequest.ranges": true,
+	"urbis.EstimateCountRequest.range":    true,
+	"urbis.QueryRangeMultiRequest.range":  true,
+}
+
+// UnaryValidationInterceptor rejects malformed unary requests before they
+// reach a handler: an empty index_id, or an MBR field with a non-finite
+// coordinate or a minimum greater than its maximum. Fields that are not
+// set are left for the handler, which knows whether they are required.
+func (s *UrbisServer) UnaryValidationInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := validateRequest(req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// validateRequest checks the index_id and the top-level MBR fields of req
+func validateRequest(req interface{}) error {
+	if r, ok := req.(interface{ GetIndexId() string }); ok && r.GetIndexId() == "" {
+		return status.Error(codes.InvalidArgument, "index_id is required")
+	}
+
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil
+	}
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Message() == nil || fd.Message().FullName() != mbrName || fd.IsMap() {
+			continue
+		}
+		wraps := wrappingMBRFields[fd.FullName()]
+
+		if fd.IsList() {
+			list := m.Get(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				mbr, _ := list.Get(j).Message().Interface().(*pb.MBR)
+				if err := validateMBR(mbr, wraps); err != nil {
+					return status.Errorf(codes.InvalidArgument, "%s[%d]: %v", fd.Name(), j, err)
+				}
+			}
+		} else if m.Has(fd) {
+			mbr, _ := m.Get(fd).Message().Interface().(*pb.MBR)
+			if err := validateMBR(mbr, wraps); err != nil {
+				return status.Errorf(codes.InvalidArgument, "%s: %v", fd.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateMBR checks that every coordinate of mbr is finite and that its
+// minimums do not exceed its maximums. With wraps set MinX may exceed MaxX.
+// A nil MBR is left for the handler to report.
+func validateMBR(mbr *pb.MBR, wraps bool) error {
+	if mbr == nil {
+		return nil
+	}
+	for _, c := range []struct {
+		name  string
+		value float64
+	}{{"min_x", mbr.MinX}, {"min_y", mbr.MinY}, {"max_x", mbr.MaxX}, {"max_y", mbr.MaxY}} {
+		if math.IsNaN(c.value) || math.IsInf(c.value, 0) {
+			return fmt.Errorf("%s must be finite, got %g", c.name, c.value)
+		}
+	}
+	if mbr.MinX > mbr.MaxX && !wraps {
+		return fmt.Errorf("min_x %g is greater than max_x %g", mbr.MinX, mbr.MaxX)
+	}
+	if mbr.MinY > mbr.MaxY {
+		return fmt.Errorf("min_y %g is greater than max_y %g", mbr.MinY, mbr.MaxY)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"io"
+
+	"github.com/urbis/api/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StreamLoadWKT loads newline-delimited WKT sent in chunks. Lines that fail
+// to parse are listed in the response's errors; the rest are loaded.
+func (s *UrbisServer) StreamLoadWKT(stream pb.UrbisService_StreamLoadWKTServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "index_id is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	idx, release, err := s.getIndex(first.IndexId)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	next := func() ([]byte, error) {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return msg.Chunk, nil
+	}
+	loaded, err := idx.LoadWKTReader(&chunkReader{next: next, buf: first.Chunk})
+
+	var lineErrs []string
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			lineErrs = append(lineErrs, e.Error())
+		}
+		err = nil
+	}
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(errorCode(err), "failed to load WKT after %d objects: %v", loaded, err)
+	}
+
+	return stream.SendAndClose(&pb.LoadResponse{
+		ObjectsLoaded: loaded,
+		Message:       "WKT stream loaded successfully",
+		Count:         idx.Count(),
+		Bounds:        indexBounds(idx),
+		Errors:        lineErrs,
+	})
+}
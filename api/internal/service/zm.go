@@ -0,0 +1,73 @@
+package service
+
+import (
+	"github.com/urbis/api/pkg/pb"
+	"github.com/urbis/api/pkg/urbis"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// zmFromPb collects the z and m values of an insert request's points. Each
+// must be set on every point or on none; a nil slice means none.
+func zmFromPb(points []*pb.Point) (z, m []float64, err error) {
+	for i, p := range points {
+		if (p.Z != nil) != (points[0].Z != nil) || (p.M != nil) != (points[0].M != nil) {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "point %d sets z or m but point 0 does not, or the reverse", i)
+		}
+		if p.Z != nil {
+			z = append(z, p.GetZ())
+		}
+		if p.M != nil {
+			m = append(m, p.GetM())
+		}
+	}
+	return z, m, nil
+}
+
+// setPbZM copies the Z and M values of obj onto the points of its
+// converted geometry, which lists them in the same order
+func setPbZM(pbObj *pb.SpatialObject, obj *urbis.SpatialObject) {
+	if obj.Dims == 0 {
+		return
+	}
+	i := 0
+	next := func(points []*pb.Point) {
+		for _, p := range points {
+			if obj.Dims.HasZ() && i < len(obj.Z) {
+				p.Z = &obj.Z[i]
+			}
+			if obj.Dims.HasM() && i < len(obj.M) {
+				p.M = &obj.M[i]
+			}
+			i++
+		}
+	}
+
+	switch g := pbObj.Geometry.(type) {
+	case *pb.SpatialObject_Point:
+		next([]*pb.Point{g.Point})
+	case *pb.SpatialObject_Line:
+		next(g.Line.Points)
+	case *pb.SpatialObject_Polygon:
+		next(g.Polygon.Exterior)
+	case *pb.SpatialObject_MultiPoint:
+		next(g.MultiPoint.Points)
+	case *pb.SpatialObject_MultiLine:
+		for _, line := range g.MultiLine.Lines {
+			next(line.Points)
+		}
+	case *pb.SpatialObject_MultiPolygon:
+		for _, polygon := range g.MultiPolygon.Polygons {
+			next(polygon.Exterior)
+		}
+	}
+}
+
+// optionalSlice turns an optional request field into a one-value slice,
+// or nil when it is unset
+func optionalSlice(v *float64) []float64 {
+	if v == nil {
+		return nil
+	}
+	return []float64{*v}
+}
@@ -0,0 +1,93 @@
+// Package tracing exports OpenTelemetry traces of the Urbis gRPC server
+// over OTLP, one span per RPC.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// Span attributes recorded for UrbisService calls
+const (
+	IndexIDKey     = attribute.Key("urbis.index_id")
+	ResultCountKey = attribute.Key("urbis.result_count")
+	QueryTimeKey   = attribute.Key("urbis.query_time_ms")
+)
+
+// Tracer creates spans for the RPCs of one server and exports them
+type Tracer struct {
+	provider    *sdktrace.TracerProvider
+	propagators propagation.TextMapPropagator
+}
+
+// New starts exporting spans to the OTLP/gRPC collector at endpoint, a URL
+// such as http://localhost:4317 (plaintext) or https://collector:4317. The
+// service is named "urbis"; OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES
+// override the resource.
+func New(ctx context.Context, endpoint, version string) (*Tracer, error) {
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("create OTLP exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName("urbis"), semconv.ServiceVersion(version)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("build resource: %w", err)
+	}
+	return newTracer(sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))), nil
+}
+
+// newTracer wraps a provider, propagating W3C trace context and baggage
+func newTracer(provider *sdktrace.TracerProvider) *Tracer {
+	return &Tracer{
+		provider:    provider,
+		propagators: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+}
+
+// ServerOption makes the server start a span for every RPC, continuing the
+// trace of the incoming call when it carries one
+func (t *Tracer) ServerOption() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler(
+		otelgrpc.WithTracerProvider(t.provider),
+		otelgrpc.WithPropagators(t.propagators),
+	))
+}
+
+// Shutdown flushes buffered spans and stops the exporter
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	return t.provider.Shutdown(ctx)
+}
+
+// UnaryServerInterceptor records the index ID of each unary call, and the
+// result count and query time of its response, on the RPC's span. It must
+// run inside the span started by ServerOption.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		span := trace.SpanFromContext(ctx)
+		if r, ok := req.(interface{ GetIndexId() string }); ok && r.GetIndexId() != "" {
+			span.SetAttributes(IndexIDKey.String(r.GetIndexId()))
+		}
+
+		resp, err := handler(ctx, req)
+		if r, ok := resp.(interface{ GetCount() uint64 }); ok && err == nil {
+			span.SetAttributes(ResultCountKey.Int64(int64(r.GetCount())))
+		}
+		if r, ok := resp.(interface{ GetQueryTimeMs() float64 }); ok && err == nil {
+			span.SetAttributes(QueryTimeKey.Float64(r.GetQueryTimeMs()))
+		}
+		return resp, err
+	}
+}
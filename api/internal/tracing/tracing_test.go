@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/urbis/api/internal/service"
+	"github.com/urbis/api/pkg/pb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServerSpans(t *testing.T) {
+	ctx := context.Background()
+	spans := tracetest.NewSpanRecorder()
+	tracer := newTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(tracer.ServerOption(), grpc.ChainUnaryInterceptor(UnaryServerInterceptor()))
+	pb.RegisterUrbisServiceServer(server, service.NewUrbisServer())
+	go server.Serve(lis)
+	defer server.Stop()
+
+	// The client traces too, so the server span must join its trace
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithTracerProvider(tracer.provider),
+			otelgrpc.WithPropagators(tracer.propagators),
+		)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewUrbisServiceClient(conn)
+
+	client.CreateIndex(ctx, &pb.CreateIndexRequest{IndexId: "traced"})
+	client.InsertPoint(ctx, &pb.InsertPointRequest{IndexId: "traced", X: 1, Y: 1})
+	client.Build(ctx, &pb.BuildRequest{IndexId: "traced"})
+	if _, err := client.QueryRange(ctx, &pb.RangeQueryRequest{IndexId: "traced", Range: &pb.MBR{MaxX: 2, MaxY: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var clientSpan, serverSpan sdktrace.ReadOnlySpan
+	for _, span := range spans.Ended() {
+		if span.Name() != "urbis.UrbisService/QueryRange" {
+			continue
+		}
+		if span.SpanKind() == trace.SpanKindServer {
+			serverSpan = span
+		} else {
+			clientSpan = span
+		}
+	}
+	if serverSpan == nil || clientSpan == nil {
+		t.Fatalf("missing QueryRange spans in %d recorded", len(spans.Ended()))
+	}
+	if serverSpan.Parent().SpanID() != clientSpan.SpanContext().SpanID() {
+		t.Errorf("server span does not continue the client's trace")
+	}
+
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range serverSpan.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs[IndexIDKey].AsString(); got != "traced" {
+		t.Errorf("%s = %q, want traced", IndexIDKey, got)
+	}
+	if got := attrs[ResultCountKey].AsInt64(); got != 1 {
+		t.Errorf("%s = %d, want 1", ResultCountKey, got)
+	}
+	if _, ok := attrs[QueryTimeKey]; !ok {
+		t.Errorf("%s not recorded", QueryTimeKey)
+	}
+}
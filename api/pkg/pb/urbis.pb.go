@@ -25,9 +25,13 @@ const (
 type GeomType int32
 
 const (
-	GeomType_GEOM_POINT      GeomType = 0
-	GeomType_GEOM_LINESTRING GeomType = 1
-	GeomType_GEOM_POLYGON    GeomType = 2
+	GeomType_GEOM_POINT              GeomType = 0
+	GeomType_GEOM_LINESTRING         GeomType = 1
+	GeomType_GEOM_POLYGON            GeomType = 2
+	GeomType_GEOM_MULTIPOINT         GeomType = 3
+	GeomType_GEOM_MULTILINESTRING    GeomType = 4
+	GeomType_GEOM_MULTIPOLYGON       GeomType = 5
+	GeomType_GEOM_GEOMETRYCOLLECTION GeomType = 6
 )
 
 // Enum value maps for GeomType.
@@ -36,11 +40,19 @@ var (
 		0: "GEOM_POINT",
 		1: "GEOM_LINESTRING",
 		2: "GEOM_POLYGON",
+		3: "GEOM_MULTIPOINT",
+		4: "GEOM_MULTILINESTRING",
+		5: "GEOM_MULTIPOLYGON",
+		6: "GEOM_GEOMETRYCOLLECTION",
 	}
 	GeomType_value = map[string]int32{
-		"GEOM_POINT":      0,
-		"GEOM_LINESTRING": 1,
-		"GEOM_POLYGON":    2,
+		"GEOM_POINT":              0,
+		"GEOM_LINESTRING":         1,
+		"GEOM_POLYGON":            2,
+		"GEOM_MULTIPOINT":         3,
+		"GEOM_MULTILINESTRING":    4,
+		"GEOM_MULTIPOLYGON":       5,
+		"GEOM_GEOMETRYCOLLECTION": 6,
 	}
 )
 
@@ -71,11 +83,492 @@ func (GeomType) EnumDescriptor() ([]byte, []int) {
 	return file_urbis_proto_rawDescGZIP(), []int{0}
 }
 
+// Rule deciding whether an object falls within a region (inclusive bounds)
+type RangeMatch int32
+
+const (
+	RangeMatch_RANGE_MATCH_CONTAINED  RangeMatch = 0 // Object MBR lies entirely inside the region
+	RangeMatch_RANGE_MATCH_CENTROID   RangeMatch = 1 // Object centroid lies inside the region
+	RangeMatch_RANGE_MATCH_INTERSECTS RangeMatch = 2 // Object MBR intersects the region
+)
+
+// Enum value maps for RangeMatch.
+var (
+	RangeMatch_name = map[int32]string{
+		0: "RANGE_MATCH_CONTAINED",
+		1: "RANGE_MATCH_CENTROID",
+		2: "RANGE_MATCH_INTERSECTS",
+	}
+	RangeMatch_value = map[string]int32{
+		"RANGE_MATCH_CONTAINED":  0,
+		"RANGE_MATCH_CENTROID":   1,
+		"RANGE_MATCH_INTERSECTS": 2,
+	}
+)
+
+func (x RangeMatch) Enum() *RangeMatch {
+	p := new(RangeMatch)
+	*p = x
+	return p
+}
+
+func (x RangeMatch) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RangeMatch) Descriptor() protoreflect.EnumDescriptor {
+	return file_urbis_proto_enumTypes[1].Descriptor()
+}
+
+func (RangeMatch) Type() protoreflect.EnumType {
+	return &file_urbis_proto_enumTypes[1]
+}
+
+func (x RangeMatch) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RangeMatch.Descriptor instead.
+func (RangeMatch) EnumDescriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{1}
+}
+
+// Index structure used to answer a query
+type IndexStructure int32
+
+const (
+	IndexStructure_INDEX_STRUCTURE_AUTO     IndexStructure = 0 // Let the index choose
+	IndexStructure_INDEX_STRUCTURE_KDTREE   IndexStructure = 1 // Object KD-tree
+	IndexStructure_INDEX_STRUCTURE_QUADTREE IndexStructure = 2 // Page quadtree
+	IndexStructure_INDEX_STRUCTURE_SCAN     IndexStructure = 3 // Linear scan of page extents
+)
+
+// Enum value maps for IndexStructure.
+var (
+	IndexStructure_name = map[int32]string{
+		0: "INDEX_STRUCTURE_AUTO",
+		1: "INDEX_STRUCTURE_KDTREE",
+		2: "INDEX_STRUCTURE_QUADTREE",
+		3: "INDEX_STRUCTURE_SCAN",
+	}
+	IndexStructure_value = map[string]int32{
+		"INDEX_STRUCTURE_AUTO":     0,
+		"INDEX_STRUCTURE_KDTREE":   1,
+		"INDEX_STRUCTURE_QUADTREE": 2,
+		"INDEX_STRUCTURE_SCAN":     3,
+	}
+)
+
+func (x IndexStructure) Enum() *IndexStructure {
+	p := new(IndexStructure)
+	*p = x
+	return p
+}
+
+func (x IndexStructure) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IndexStructure) Descriptor() protoreflect.EnumDescriptor {
+	return file_urbis_proto_enumTypes[2].Descriptor()
+}
+
+func (IndexStructure) Type() protoreflect.EnumType {
+	return &file_urbis_proto_enumTypes[2]
+}
+
+func (x IndexStructure) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IndexStructure.Descriptor instead.
+func (IndexStructure) EnumDescriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{2}
+}
+
+// How InsertPolygon treats rings that are unclosed, self-intersecting,
+// clockwise or have fewer than three vertices
+type PolygonValidation int32
+
+const (
+	PolygonValidation_POLYGON_VALIDATION_REJECT PolygonValidation = 0 // Fail with INVALID_ARGUMENT and the reason
+	PolygonValidation_POLYGON_VALIDATION_REPORT PolygonValidation = 1 // Insert anyway, reporting is_valid/reason
+	PolygonValidation_POLYGON_VALIDATION_OFF    PolygonValidation = 2 // Skip validation
+)
+
+// Enum value maps for PolygonValidation.
+var (
+	PolygonValidation_name = map[int32]string{
+		0: "POLYGON_VALIDATION_REJECT",
+		1: "POLYGON_VALIDATION_REPORT",
+		2: "POLYGON_VALIDATION_OFF",
+	}
+	PolygonValidation_value = map[string]int32{
+		"POLYGON_VALIDATION_REJECT": 0,
+		"POLYGON_VALIDATION_REPORT": 1,
+		"POLYGON_VALIDATION_OFF":    2,
+	}
+)
+
+func (x PolygonValidation) Enum() *PolygonValidation {
+	p := new(PolygonValidation)
+	*p = x
+	return p
+}
+
+func (x PolygonValidation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PolygonValidation) Descriptor() protoreflect.EnumDescriptor {
+	return file_urbis_proto_enumTypes[3].Descriptor()
+}
+
+func (PolygonValidation) Type() protoreflect.EnumType {
+	return &file_urbis_proto_enumTypes[3]
+}
+
+func (x PolygonValidation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PolygonValidation.Descriptor instead.
+func (PolygonValidation) EnumDescriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{3}
+}
+
+// JSON type a property value must have
+type PropertyType int32
+
+const (
+	PropertyType_PROPERTY_TYPE_ANY    PropertyType = 0
+	PropertyType_PROPERTY_TYPE_STRING PropertyType = 1
+	PropertyType_PROPERTY_TYPE_NUMBER PropertyType = 2
+	PropertyType_PROPERTY_TYPE_BOOL   PropertyType = 3
+	PropertyType_PROPERTY_TYPE_OBJECT PropertyType = 4
+	PropertyType_PROPERTY_TYPE_ARRAY  PropertyType = 5
+)
+
+// Enum value maps for PropertyType.
+var (
+	PropertyType_name = map[int32]string{
+		0: "PROPERTY_TYPE_ANY",
+		1: "PROPERTY_TYPE_STRING",
+		2: "PROPERTY_TYPE_NUMBER",
+		3: "PROPERTY_TYPE_BOOL",
+		4: "PROPERTY_TYPE_OBJECT",
+		5: "PROPERTY_TYPE_ARRAY",
+	}
+	PropertyType_value = map[string]int32{
+		"PROPERTY_TYPE_ANY":    0,
+		"PROPERTY_TYPE_STRING": 1,
+		"PROPERTY_TYPE_NUMBER": 2,
+		"PROPERTY_TYPE_BOOL":   3,
+		"PROPERTY_TYPE_OBJECT": 4,
+		"PROPERTY_TYPE_ARRAY":  5,
+	}
+)
+
+func (x PropertyType) Enum() *PropertyType {
+	p := new(PropertyType)
+	*p = x
+	return p
+}
+
+func (x PropertyType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PropertyType) Descriptor() protoreflect.EnumDescriptor {
+	return file_urbis_proto_enumTypes[4].Descriptor()
+}
+
+func (PropertyType) Type() protoreflect.EnumType {
+	return &file_urbis_proto_enumTypes[4]
+}
+
+func (x PropertyType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PropertyType.Descriptor instead.
+func (PropertyType) EnumDescriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{4}
+}
+
+// Storage medium the seek and transfer cost estimates assume
+type StorageKind int32
+
+const (
+	StorageKind_STORAGE_ROTATIONAL StorageKind = 0 // Hard disk: 8 ms per seek, 150 MB/s
+	StorageKind_STORAGE_SSD        StorageKind = 1 // Flash: 0.1 ms per seek, 2000 MB/s
+	StorageKind_STORAGE_CUSTOM     StorageKind = 2 // seek_ms and transfer_mb_s as given
+)
+
+// Enum value maps for StorageKind.
+var (
+	StorageKind_name = map[int32]string{
+		0: "STORAGE_ROTATIONAL",
+		1: "STORAGE_SSD",
+		2: "STORAGE_CUSTOM",
+	}
+	StorageKind_value = map[string]int32{
+		"STORAGE_ROTATIONAL": 0,
+		"STORAGE_SSD":        1,
+		"STORAGE_CUSTOM":     2,
+	}
+)
+
+func (x StorageKind) Enum() *StorageKind {
+	p := new(StorageKind)
+	*p = x
+	return p
+}
+
+func (x StorageKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StorageKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_urbis_proto_enumTypes[5].Descriptor()
+}
+
+func (StorageKind) Type() protoreflect.EnumType {
+	return &file_urbis_proto_enumTypes[5]
+}
+
+func (x StorageKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StorageKind.Descriptor instead.
+func (StorageKind) EnumDescriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{5}
+}
+
+// Result order for range queries
+type RangeSort int32
+
+const (
+	RangeSort_RANGE_SORT_NONE                 RangeSort = 0 // Index order (ID order when paginating)
+	RangeSort_RANGE_SORT_ID                   RangeSort = 1 // Ascending object ID
+	RangeSort_RANGE_SORT_DISTANCE_FROM_CENTER RangeSort = 2 // Centroid distance from the range center, nearest first
+	RangeSort_RANGE_SORT_MBR_AREA             RangeSort = 3 // Object MBR area, smallest first
+)
+
+// Enum value maps for RangeSort.
+var (
+	RangeSort_name = map[int32]string{
+		0: "RANGE_SORT_NONE",
+		1: "RANGE_SORT_ID",
+		2: "RANGE_SORT_DISTANCE_FROM_CENTER",
+		3: "RANGE_SORT_MBR_AREA",
+	}
+	RangeSort_value = map[string]int32{
+		"RANGE_SORT_NONE":                 0,
+		"RANGE_SORT_ID":                   1,
+		"RANGE_SORT_DISTANCE_FROM_CENTER": 2,
+		"RANGE_SORT_MBR_AREA":             3,
+	}
+)
+
+func (x RangeSort) Enum() *RangeSort {
+	p := new(RangeSort)
+	*p = x
+	return p
+}
+
+func (x RangeSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RangeSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_urbis_proto_enumTypes[6].Descriptor()
+}
+
+func (RangeSort) Type() protoreflect.EnumType {
+	return &file_urbis_proto_enumTypes[6]
+}
+
+func (x RangeSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RangeSort.Descriptor instead.
+func (RangeSort) EnumDescriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{6}
+}
+
+// How QueryKNN measures the distance from the query point to an object
+type DistanceMetric int32
+
+const (
+	DistanceMetric_DISTANCE_METRIC_CENTROID       DistanceMetric = 0 // To the object's centroid
+	DistanceMetric_DISTANCE_METRIC_NEAREST_EDGE   DistanceMetric = 1 // To the nearest point of the geometry; 0 inside a polygon
+	DistanceMetric_DISTANCE_METRIC_NEAREST_VERTEX DistanceMetric = 2 // To the nearest vertex of the geometry
+)
+
+// Enum value maps for DistanceMetric.
+var (
+	DistanceMetric_name = map[int32]string{
+		0: "DISTANCE_METRIC_CENTROID",
+		1: "DISTANCE_METRIC_NEAREST_EDGE",
+		2: "DISTANCE_METRIC_NEAREST_VERTEX",
+	}
+	DistanceMetric_value = map[string]int32{
+		"DISTANCE_METRIC_CENTROID":       0,
+		"DISTANCE_METRIC_NEAREST_EDGE":   1,
+		"DISTANCE_METRIC_NEAREST_VERTEX": 2,
+	}
+)
+
+func (x DistanceMetric) Enum() *DistanceMetric {
+	p := new(DistanceMetric)
+	*p = x
+	return p
+}
+
+func (x DistanceMetric) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DistanceMetric) Descriptor() protoreflect.EnumDescriptor {
+	return file_urbis_proto_enumTypes[7].Descriptor()
+}
+
+func (DistanceMetric) Type() protoreflect.EnumType {
+	return &file_urbis_proto_enumTypes[7]
+}
+
+func (x DistanceMetric) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DistanceMetric.Descriptor instead.
+func (DistanceMetric) EnumDescriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{7}
+}
+
+// Parts of a SpatialObject a query can return; type is always set
+type ObjectField int32
+
+const (
+	ObjectField_OBJECT_FIELD_UNSPECIFIED ObjectField = 0
+	ObjectField_OBJECT_FIELD_ID          ObjectField = 1
+	ObjectField_OBJECT_FIELD_GEOMETRY    ObjectField = 2 // The geometry oneof or encoded_geometry
+	ObjectField_OBJECT_FIELD_MBR         ObjectField = 3
+	ObjectField_OBJECT_FIELD_CENTROID    ObjectField = 4
+	ObjectField_OBJECT_FIELD_PROPERTIES  ObjectField = 5
+)
+
+// Enum value maps for ObjectField.
+var (
+	ObjectField_name = map[int32]string{
+		0: "OBJECT_FIELD_UNSPECIFIED",
+		1: "OBJECT_FIELD_ID",
+		2: "OBJECT_FIELD_GEOMETRY",
+		3: "OBJECT_FIELD_MBR",
+		4: "OBJECT_FIELD_CENTROID",
+		5: "OBJECT_FIELD_PROPERTIES",
+	}
+	ObjectField_value = map[string]int32{
+		"OBJECT_FIELD_UNSPECIFIED": 0,
+		"OBJECT_FIELD_ID":          1,
+		"OBJECT_FIELD_GEOMETRY":    2,
+		"OBJECT_FIELD_MBR":         3,
+		"OBJECT_FIELD_CENTROID":    4,
+		"OBJECT_FIELD_PROPERTIES":  5,
+	}
+)
+
+func (x ObjectField) Enum() *ObjectField {
+	p := new(ObjectField)
+	*p = x
+	return p
+}
+
+func (x ObjectField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ObjectField) Descriptor() protoreflect.EnumDescriptor {
+	return file_urbis_proto_enumTypes[8].Descriptor()
+}
+
+func (ObjectField) Type() protoreflect.EnumType {
+	return &file_urbis_proto_enumTypes[8]
+}
+
+func (x ObjectField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ObjectField.Descriptor instead.
+func (ObjectField) EnumDescriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{8}
+}
+
+// How query results carry geometry
+type GeometryEncoding int32
+
+const (
+	GeometryEncoding_GEOMETRY_ENCODING_STRUCTURED GeometryEncoding = 0 // The geometry oneof of Point messages
+	GeometryEncoding_GEOMETRY_ENCODING_WKB        GeometryEncoding = 1 // Little-endian WKB in encoded_geometry
+	GeometryEncoding_GEOMETRY_ENCODING_POLYLINE   GeometryEncoding = 2 // Encoded polyline (5 decimal places, lat/y first) in encoded_geometry;
+	// points, linestrings and polygon exterior rings only
+	GeometryEncoding_GEOMETRY_ENCODING_GEOJSON GeometryEncoding = 3 // One GeoJSON FeatureCollection in the response's geojson field,
+)
+
+// Enum value maps for GeometryEncoding.
+var (
+	GeometryEncoding_name = map[int32]string{
+		0: "GEOMETRY_ENCODING_STRUCTURED",
+		1: "GEOMETRY_ENCODING_WKB",
+		2: "GEOMETRY_ENCODING_POLYLINE",
+		3: "GEOMETRY_ENCODING_GEOJSON",
+	}
+	GeometryEncoding_value = map[string]int32{
+		"GEOMETRY_ENCODING_STRUCTURED": 0,
+		"GEOMETRY_ENCODING_WKB":        1,
+		"GEOMETRY_ENCODING_POLYLINE":   2,
+		"GEOMETRY_ENCODING_GEOJSON":    3,
+	}
+)
+
+func (x GeometryEncoding) Enum() *GeometryEncoding {
+	p := new(GeometryEncoding)
+	*p = x
+	return p
+}
+
+func (x GeometryEncoding) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GeometryEncoding) Descriptor() protoreflect.EnumDescriptor {
+	return file_urbis_proto_enumTypes[9].Descriptor()
+}
+
+func (GeometryEncoding) Type() protoreflect.EnumType {
+	return &file_urbis_proto_enumTypes[9]
+}
+
+func (x GeometryEncoding) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GeometryEncoding.Descriptor instead.
+func (GeometryEncoding) EnumDescriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{9}
+}
+
 // 2D Point
 type Point struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	X             float64                `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
 	Y             float64                `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+	Z             *float64               `protobuf:"fixed64,3,opt,name=z,proto3,oneof" json:"z,omitempty"` // Elevation; kept but not indexed
+	M             *float64               `protobuf:"fixed64,4,opt,name=m,proto3,oneof" json:"m,omitempty"` // Measure; kept but not indexed
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -124,6 +617,20 @@ func (x *Point) GetY() float64 {
 	return 0
 }
 
+func (x *Point) GetZ() float64 {
+	if x != nil && x.Z != nil {
+		return *x.Z
+	}
+	return 0
+}
+
+func (x *Point) GetM() float64 {
+	if x != nil && x.M != nil {
+		return *x.M
+	}
+	return 0
+}
+
 // Minimum Bounding Rectangle (axis-aligned bounding box)
 type MBR struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -336,38 +843,28 @@ func (x *Ring) GetPoints() []*Point {
 	return nil
 }
 
-// Spatial object containing geometry and metadata
-type SpatialObject struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Type  GeomType               `protobuf:"varint,2,opt,name=type,proto3,enum=urbis.GeomType" json:"type,omitempty"`
-	// Types that are valid to be assigned to Geometry:
-	//
-	//	*SpatialObject_Point
-	//	*SpatialObject_Line
-	//	*SpatialObject_Polygon
-	Geometry      isSpatialObject_Geometry `protobuf_oneof:"geometry"`
-	Centroid      *Point                   `protobuf:"bytes,6,opt,name=centroid,proto3" json:"centroid,omitempty"`
-	Mbr           *MBR                     `protobuf:"bytes,7,opt,name=mbr,proto3" json:"mbr,omitempty"`
-	Properties    []byte                   `protobuf:"bytes,8,opt,name=properties,proto3" json:"properties,omitempty"` // JSON encoded properties
+// MultiPoint geometry
+type MultiPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Points        []*Point               `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SpatialObject) Reset() {
-	*x = SpatialObject{}
+func (x *MultiPoint) Reset() {
+	*x = MultiPoint{}
 	mi := &file_urbis_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SpatialObject) String() string {
+func (x *MultiPoint) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpatialObject) ProtoMessage() {}
+func (*MultiPoint) ProtoMessage() {}
 
-func (x *SpatialObject) ProtoReflect() protoreflect.Message {
+func (x *MultiPoint) ProtoReflect() protoreflect.Message {
 	mi := &file_urbis_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -379,11 +876,208 @@ func (x *SpatialObject) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SpatialObject.ProtoReflect.Descriptor instead.
-func (*SpatialObject) Descriptor() ([]byte, []int) {
+// Deprecated: Use MultiPoint.ProtoReflect.Descriptor instead.
+func (*MultiPoint) Descriptor() ([]byte, []int) {
 	return file_urbis_proto_rawDescGZIP(), []int{5}
 }
 
+func (x *MultiPoint) GetPoints() []*Point {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+// MultiLineString geometry
+type MultiLineString struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lines         []*LineString          `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiLineString) Reset() {
+	*x = MultiLineString{}
+	mi := &file_urbis_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiLineString) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiLineString) ProtoMessage() {}
+
+func (x *MultiLineString) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiLineString.ProtoReflect.Descriptor instead.
+func (*MultiLineString) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MultiLineString) GetLines() []*LineString {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+// MultiPolygon geometry
+type MultiPolygon struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Polygons      []*Polygon             `protobuf:"bytes,1,rep,name=polygons,proto3" json:"polygons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiPolygon) Reset() {
+	*x = MultiPolygon{}
+	mi := &file_urbis_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiPolygon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiPolygon) ProtoMessage() {}
+
+func (x *MultiPolygon) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiPolygon.ProtoReflect.Descriptor instead.
+func (*MultiPolygon) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MultiPolygon) GetPolygons() []*Polygon {
+	if x != nil {
+		return x.Polygons
+	}
+	return nil
+}
+
+// Heterogeneous collection of geometries
+type GeometryCollection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Geometries    []*SpatialObject       `protobuf:"bytes,1,rep,name=geometries,proto3" json:"geometries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeometryCollection) Reset() {
+	*x = GeometryCollection{}
+	mi := &file_urbis_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeometryCollection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeometryCollection) ProtoMessage() {}
+
+func (x *GeometryCollection) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeometryCollection.ProtoReflect.Descriptor instead.
+func (*GeometryCollection) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GeometryCollection) GetGeometries() []*SpatialObject {
+	if x != nil {
+		return x.Geometries
+	}
+	return nil
+}
+
+// Spatial object containing geometry and metadata
+type SpatialObject struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type  GeomType               `protobuf:"varint,2,opt,name=type,proto3,enum=urbis.GeomType" json:"type,omitempty"`
+	// Types that are valid to be assigned to Geometry:
+	//
+	//	*SpatialObject_Point
+	//	*SpatialObject_Line
+	//	*SpatialObject_Polygon
+	//	*SpatialObject_MultiPoint
+	//	*SpatialObject_MultiLine
+	//	*SpatialObject_MultiPolygon
+	//	*SpatialObject_Collection
+	Geometry        isSpatialObject_Geometry `protobuf_oneof:"geometry"`
+	Centroid        *Point                   `protobuf:"bytes,6,opt,name=centroid,proto3" json:"centroid,omitempty"`
+	Mbr             *MBR                     `protobuf:"bytes,7,opt,name=mbr,proto3" json:"mbr,omitempty"`
+	Properties      []byte                   `protobuf:"bytes,8,opt,name=properties,proto3" json:"properties,omitempty"`                                   // JSON encoded properties
+	Version         uint64                   `protobuf:"varint,13,opt,name=version,proto3" json:"version,omitempty"`                                       // Index-wide change counter at last modification (with include_version)
+	ModifiedAtMs    int64                    `protobuf:"varint,14,opt,name=modified_at_ms,json=modifiedAtMs,proto3" json:"modified_at_ms,omitempty"`       // Last modification, Unix milliseconds (with include_version)
+	EncodedGeometry []byte                   `protobuf:"bytes,15,opt,name=encoded_geometry,json=encodedGeometry,proto3" json:"encoded_geometry,omitempty"` // Set instead of the geometry oneof when a query asks for an encoding
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SpatialObject) Reset() {
+	*x = SpatialObject{}
+	mi := &file_urbis_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpatialObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpatialObject) ProtoMessage() {}
+
+func (x *SpatialObject) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpatialObject.ProtoReflect.Descriptor instead.
+func (*SpatialObject) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{9}
+}
+
 func (x *SpatialObject) GetId() uint64 {
 	if x != nil {
 		return x.Id
@@ -432,6 +1126,42 @@ func (x *SpatialObject) GetPolygon() *Polygon {
 	return nil
 }
 
+func (x *SpatialObject) GetMultiPoint() *MultiPoint {
+	if x != nil {
+		if x, ok := x.Geometry.(*SpatialObject_MultiPoint); ok {
+			return x.MultiPoint
+		}
+	}
+	return nil
+}
+
+func (x *SpatialObject) GetMultiLine() *MultiLineString {
+	if x != nil {
+		if x, ok := x.Geometry.(*SpatialObject_MultiLine); ok {
+			return x.MultiLine
+		}
+	}
+	return nil
+}
+
+func (x *SpatialObject) GetMultiPolygon() *MultiPolygon {
+	if x != nil {
+		if x, ok := x.Geometry.(*SpatialObject_MultiPolygon); ok {
+			return x.MultiPolygon
+		}
+	}
+	return nil
+}
+
+func (x *SpatialObject) GetCollection() *GeometryCollection {
+	if x != nil {
+		if x, ok := x.Geometry.(*SpatialObject_Collection); ok {
+			return x.Collection
+		}
+	}
+	return nil
+}
+
 func (x *SpatialObject) GetCentroid() *Point {
 	if x != nil {
 		return x.Centroid
@@ -453,6 +1183,27 @@ func (x *SpatialObject) GetProperties() []byte {
 	return nil
 }
 
+func (x *SpatialObject) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SpatialObject) GetModifiedAtMs() int64 {
+	if x != nil {
+		return x.ModifiedAtMs
+	}
+	return 0
+}
+
+func (x *SpatialObject) GetEncodedGeometry() []byte {
+	if x != nil {
+		return x.EncodedGeometry
+	}
+	return nil
+}
+
 type isSpatialObject_Geometry interface {
 	isSpatialObject_Geometry()
 }
@@ -469,27 +1220,66 @@ type SpatialObject_Polygon struct {
 	Polygon *Polygon `protobuf:"bytes,5,opt,name=polygon,proto3,oneof"`
 }
 
+type SpatialObject_MultiPoint struct {
+	MultiPoint *MultiPoint `protobuf:"bytes,9,opt,name=multi_point,json=multiPoint,proto3,oneof"`
+}
+
+type SpatialObject_MultiLine struct {
+	MultiLine *MultiLineString `protobuf:"bytes,10,opt,name=multi_line,json=multiLine,proto3,oneof"`
+}
+
+type SpatialObject_MultiPolygon struct {
+	MultiPolygon *MultiPolygon `protobuf:"bytes,11,opt,name=multi_polygon,json=multiPolygon,proto3,oneof"`
+}
+
+type SpatialObject_Collection struct {
+	Collection *GeometryCollection `protobuf:"bytes,12,opt,name=collection,proto3,oneof"`
+}
+
 func (*SpatialObject_Point) isSpatialObject_Geometry() {}
 
 func (*SpatialObject_Line) isSpatialObject_Geometry() {}
 
 func (*SpatialObject_Polygon) isSpatialObject_Geometry() {}
 
+func (*SpatialObject_MultiPoint) isSpatialObject_Geometry() {}
+
+func (*SpatialObject_MultiLine) isSpatialObject_Geometry() {}
+
+func (*SpatialObject_MultiPolygon) isSpatialObject_Geometry() {}
+
+func (*SpatialObject_Collection) isSpatialObject_Geometry() {}
+
 type Config struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	BlockSize      uint64                 `protobuf:"varint,1,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`                // Max objects per block (default: 1024)
-	PageCapacity   uint64                 `protobuf:"varint,2,opt,name=page_capacity,json=pageCapacity,proto3" json:"page_capacity,omitempty"`       // Max objects per page (default: 64)
-	CacheSize      uint64                 `protobuf:"varint,3,opt,name=cache_size,json=cacheSize,proto3" json:"cache_size,omitempty"`                // Page cache size (default: 128)
-	EnableQuadtree bool                   `protobuf:"varint,4,opt,name=enable_quadtree,json=enableQuadtree,proto3" json:"enable_quadtree,omitempty"` // Enable quadtree for adjacency (default: true)
-	Persist        bool                   `protobuf:"varint,5,opt,name=persist,proto3" json:"persist,omitempty"`                                     // Enable persistence (default: false)
-	DataPath       string                 `protobuf:"bytes,6,opt,name=data_path,json=dataPath,proto3" json:"data_path,omitempty"`                    // Path for data file (if persist=true)
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	BlockSize            uint64                 `protobuf:"varint,1,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`                                                       // Max objects per block, a power of two from 64 to 2^20 (default: 1024)
+	PageCapacity         uint64                 `protobuf:"varint,2,opt,name=page_capacity,json=pageCapacity,proto3" json:"page_capacity,omitempty"`                                              // Max objects per page (default: 64)
+	CacheSize            uint64                 `protobuf:"varint,3,opt,name=cache_size,json=cacheSize,proto3" json:"cache_size,omitempty"`                                                       // Page cache size (default: 128)
+	EnableQuadtree       bool                   `protobuf:"varint,4,opt,name=enable_quadtree,json=enableQuadtree,proto3" json:"enable_quadtree,omitempty"`                                        // Enable quadtree for adjacency (default: true)
+	Persist              bool                   `protobuf:"varint,5,opt,name=persist,proto3" json:"persist,omitempty"`                                                                            // Enable persistence (default: false)
+	DataPath             string                 `protobuf:"bytes,6,opt,name=data_path,json=dataPath,proto3" json:"data_path,omitempty"`                                                           // Directory for the data file (if persist=true); one index per directory
+	SnapPrecision        float64                `protobuf:"fixed64,7,opt,name=snap_precision,json=snapPrecision,proto3" json:"snap_precision,omitempty"`                                          // Grid size coordinates snap to on insert (default: 0, off)
+	DedupPoints          bool                   `protobuf:"varint,8,opt,name=dedup_points,json=dedupPoints,proto3" json:"dedup_points,omitempty"`                                                 // Collapse identical points, counting them in properties
+	Crs                  int32                  `protobuf:"varint,9,opt,name=crs,proto3" json:"crs,omitempty"`                                                                                    // EPSG code of index coordinates: 4326, 3857 or 0 (unspecified)
+	PolygonValidation    PolygonValidation      `protobuf:"varint,10,opt,name=polygon_validation,json=polygonValidation,proto3,enum=urbis.PolygonValidation" json:"polygon_validation,omitempty"` // How InsertPolygon treats invalid rings
+	IndexedProperties    []string               `protobuf:"bytes,11,rep,name=indexed_properties,json=indexedProperties,proto3" json:"indexed_properties,omitempty"`                               // Property keys indexed for QueryByProperty
+	SimplifyTolerance    float64                `protobuf:"fixed64,12,opt,name=simplify_tolerance,json=simplifyTolerance,proto3" json:"simplify_tolerance,omitempty"`                             // Douglas-Peucker tolerance for lines and rings on insert (default: 0, off)
+	SeekCost             *SeekCostModel         `protobuf:"bytes,13,opt,name=seek_cost,json=seekCost,proto3" json:"seek_cost,omitempty"`                                                          // Storage behind FindAdjacentPages and AutoTune estimates (default: rotational)
+	ValidationTolerance  float64                `protobuf:"fixed64,14,opt,name=validation_tolerance,json=validationTolerance,proto3" json:"validation_tolerance,omitempty"`                       // Distance within which polygon validation treats points as coincident (default: 0, exact)
+	PropertySchema       []*PropertyRule        `protobuf:"bytes,15,rep,name=property_schema,json=propertySchema,proto3" json:"property_schema,omitempty"`                                        // Checked against the properties of every inserted object (default: none)
+	BuildThreads         uint32                 `protobuf:"varint,16,opt,name=build_threads,json=buildThreads,proto3" json:"build_threads,omitempty"`                                             // Threads Build may use for the KD-tree; the tree is the same for any count (default: 1)
+	Seed                 uint64                 `protobuf:"varint,17,opt,name=seed,proto3" json:"seed,omitempty"`                                                                                 // Orders objects with tied centroids in Build, for reproducible layouts (default: 0)
+	ReadOnly             bool                   `protobuf:"varint,18,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`                                                         // Reject writes and builds with FAILED_PRECONDITION, as after MarkReadOnly
+	KeepDuplicates       bool                   `protobuf:"varint,19,opt,name=keep_duplicates,json=keepDuplicates,proto3" json:"keep_duplicates,omitempty"`                                       // List an object in range results as often as it is found (default: false, each ID once)
+	AutoRebuildThreshold uint64                 `protobuf:"varint,20,opt,name=auto_rebuild_threshold,json=autoRebuildThreshold,proto3" json:"auto_rebuild_threshold,omitempty"`                   // Changes after which a query rebuilds; fewer are answered by a stale page scan (default: 0, off)
+	ValidBounds          *MBR                   `protobuf:"bytes,21,opt,name=valid_bounds,json=validBounds,proto3" json:"valid_bounds,omitempty"`                                                 // Box every object must lie in; inserts outside fail, loads skip them (default: unset, off)
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *Config) Reset() {
 	*x = Config{}
-	mi := &file_urbis_proto_msgTypes[6]
+	mi := &file_urbis_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -501,7 +1291,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[6]
+	mi := &file_urbis_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -514,7 +1304,7 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{6}
+	return file_urbis_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Config) GetBlockSize() uint64 {
@@ -559,61 +1349,303 @@ func (x *Config) GetDataPath() string {
 	return ""
 }
 
-type Stats struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	TotalObjects      uint64                 `protobuf:"varint,1,opt,name=total_objects,json=totalObjects,proto3" json:"total_objects,omitempty"`
-	TotalBlocks       uint64                 `protobuf:"varint,2,opt,name=total_blocks,json=totalBlocks,proto3" json:"total_blocks,omitempty"`
-	TotalPages        uint64                 `protobuf:"varint,3,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
-	TotalTracks       uint64                 `protobuf:"varint,4,opt,name=total_tracks,json=totalTracks,proto3" json:"total_tracks,omitempty"`
-	AvgObjectsPerPage float64                `protobuf:"fixed64,5,opt,name=avg_objects_per_page,json=avgObjectsPerPage,proto3" json:"avg_objects_per_page,omitempty"`
-	PageUtilization   float64                `protobuf:"fixed64,6,opt,name=page_utilization,json=pageUtilization,proto3" json:"page_utilization,omitempty"`
-	KdtreeDepth       uint64                 `protobuf:"varint,7,opt,name=kdtree_depth,json=kdtreeDepth,proto3" json:"kdtree_depth,omitempty"`
-	QuadtreeDepth     uint64                 `protobuf:"varint,8,opt,name=quadtree_depth,json=quadtreeDepth,proto3" json:"quadtree_depth,omitempty"`
-	Bounds            *MBR                   `protobuf:"bytes,9,opt,name=bounds,proto3" json:"bounds,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+func (x *Config) GetSnapPrecision() float64 {
+	if x != nil {
+		return x.SnapPrecision
+	}
+	return 0
 }
 
-func (x *Stats) Reset() {
-	*x = Stats{}
-	mi := &file_urbis_proto_msgTypes[7]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Config) GetDedupPoints() bool {
+	if x != nil {
+		return x.DedupPoints
+	}
+	return false
 }
 
-func (x *Stats) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Config) GetCrs() int32 {
+	if x != nil {
+		return x.Crs
+	}
+	return 0
 }
 
-func (*Stats) ProtoMessage() {}
-
-func (x *Stats) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[7]
+func (x *Config) GetPolygonValidation() PolygonValidation {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.PolygonValidation
 	}
-	return mi.MessageOf(x)
+	return PolygonValidation_POLYGON_VALIDATION_REJECT
 }
 
-// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
-func (*Stats) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{7}
+func (x *Config) GetIndexedProperties() []string {
+	if x != nil {
+		return x.IndexedProperties
+	}
+	return nil
 }
 
-func (x *Stats) GetTotalObjects() uint64 {
+func (x *Config) GetSimplifyTolerance() float64 {
 	if x != nil {
-		return x.TotalObjects
+		return x.SimplifyTolerance
 	}
 	return 0
 }
 
-func (x *Stats) GetTotalBlocks() uint64 {
+func (x *Config) GetSeekCost() *SeekCostModel {
 	if x != nil {
-		return x.TotalBlocks
+		return x.SeekCost
+	}
+	return nil
+}
+
+func (x *Config) GetValidationTolerance() float64 {
+	if x != nil {
+		return x.ValidationTolerance
+	}
+	return 0
+}
+
+func (x *Config) GetPropertySchema() []*PropertyRule {
+	if x != nil {
+		return x.PropertySchema
+	}
+	return nil
+}
+
+func (x *Config) GetBuildThreads() uint32 {
+	if x != nil {
+		return x.BuildThreads
+	}
+	return 0
+}
+
+func (x *Config) GetSeed() uint64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *Config) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *Config) GetKeepDuplicates() bool {
+	if x != nil {
+		return x.KeepDuplicates
+	}
+	return false
+}
+
+func (x *Config) GetAutoRebuildThreshold() uint64 {
+	if x != nil {
+		return x.AutoRebuildThreshold
+	}
+	return 0
+}
+
+func (x *Config) GetValidBounds() *MBR {
+	if x != nil {
+		return x.ValidBounds
+	}
+	return nil
+}
+
+// Constrains one key of an object's properties
+type PropertyRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Required      bool                   `protobuf:"varint,2,opt,name=required,proto3" json:"required,omitempty"`                 // Must be present and not null
+	Type          PropertyType           `protobuf:"varint,3,opt,name=type,proto3,enum=urbis.PropertyType" json:"type,omitempty"` // Checked when present and not null
+	NonEmpty      bool                   `protobuf:"varint,4,opt,name=non_empty,json=nonEmpty,proto3" json:"non_empty,omitempty"` // Rejects "", [] and {}
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PropertyRule) Reset() {
+	*x = PropertyRule{}
+	mi := &file_urbis_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PropertyRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PropertyRule) ProtoMessage() {}
+
+func (x *PropertyRule) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PropertyRule.ProtoReflect.Descriptor instead.
+func (*PropertyRule) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PropertyRule) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PropertyRule) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *PropertyRule) GetType() PropertyType {
+	if x != nil {
+		return x.Type
+	}
+	return PropertyType_PROPERTY_TYPE_ANY
+}
+
+func (x *PropertyRule) GetNonEmpty() bool {
+	if x != nil {
+		return x.NonEmpty
+	}
+	return false
+}
+
+// Turns the pages a query touches into read time: one seek to the first
+// track, one per track change, and the transfer of every page
+type SeekCostModel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Storage       StorageKind            `protobuf:"varint,1,opt,name=storage,proto3,enum=urbis.StorageKind" json:"storage,omitempty"`
+	SeekMs        float64                `protobuf:"fixed64,2,opt,name=seek_ms,json=seekMs,proto3" json:"seek_ms,omitempty"`                  // Time per seek (STORAGE_CUSTOM only, >= 0)
+	TransferMbS   float64                `protobuf:"fixed64,3,opt,name=transfer_mb_s,json=transferMbS,proto3" json:"transfer_mb_s,omitempty"` // Sequential read rate in MB/s (STORAGE_CUSTOM only, > 0)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeekCostModel) Reset() {
+	*x = SeekCostModel{}
+	mi := &file_urbis_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeekCostModel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeekCostModel) ProtoMessage() {}
+
+func (x *SeekCostModel) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeekCostModel.ProtoReflect.Descriptor instead.
+func (*SeekCostModel) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SeekCostModel) GetStorage() StorageKind {
+	if x != nil {
+		return x.Storage
+	}
+	return StorageKind_STORAGE_ROTATIONAL
+}
+
+func (x *SeekCostModel) GetSeekMs() float64 {
+	if x != nil {
+		return x.SeekMs
+	}
+	return 0
+}
+
+func (x *SeekCostModel) GetTransferMbS() float64 {
+	if x != nil {
+		return x.TransferMbS
+	}
+	return 0
+}
+
+type Stats struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TotalObjects      uint64                 `protobuf:"varint,1,opt,name=total_objects,json=totalObjects,proto3" json:"total_objects,omitempty"`
+	TotalBlocks       uint64                 `protobuf:"varint,2,opt,name=total_blocks,json=totalBlocks,proto3" json:"total_blocks,omitempty"`
+	TotalPages        uint64                 `protobuf:"varint,3,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	TotalTracks       uint64                 `protobuf:"varint,4,opt,name=total_tracks,json=totalTracks,proto3" json:"total_tracks,omitempty"`
+	AvgObjectsPerPage float64                `protobuf:"fixed64,5,opt,name=avg_objects_per_page,json=avgObjectsPerPage,proto3" json:"avg_objects_per_page,omitempty"`
+	PageUtilization   float64                `protobuf:"fixed64,6,opt,name=page_utilization,json=pageUtilization,proto3" json:"page_utilization,omitempty"`
+	KdtreeDepth       uint64                 `protobuf:"varint,7,opt,name=kdtree_depth,json=kdtreeDepth,proto3" json:"kdtree_depth,omitempty"`
+	QuadtreeDepth     uint64                 `protobuf:"varint,8,opt,name=quadtree_depth,json=quadtreeDepth,proto3" json:"quadtree_depth,omitempty"`
+	Bounds            *MBR                   `protobuf:"bytes,9,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	PageCapacity      uint64                 `protobuf:"varint,10,opt,name=page_capacity,json=pageCapacity,proto3" json:"page_capacity,omitempty"`     // Max objects per page
+	MemoryBytes       uint64                 `protobuf:"varint,11,opt,name=memory_bytes,json=memoryBytes,proto3" json:"memory_bytes,omitempty"`        // Heap held by the index, excluding allocator overhead
+	DiskBytes         uint64                 `protobuf:"varint,12,opt,name=disk_bytes,json=diskBytes,proto3" json:"disk_bytes,omitempty"`              // Size of the data file (0 if none)
+	BuildThreads      uint64                 `protobuf:"varint,13,opt,name=build_threads,json=buildThreads,proto3" json:"build_threads,omitempty"`     // Threads the last build used (0 before one)
+	WidthMeters       *float64               `protobuf:"fixed64,14,opt,name=width_meters,json=widthMeters,proto3,oneof" json:"width_meters,omitempty"` // Extent of bounds in meters, for a non-empty index with crs 4326
+	HeightMeters      *float64               `protobuf:"fixed64,15,opt,name=height_meters,json=heightMeters,proto3,oneof" json:"height_meters,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_urbis_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Stats) GetTotalObjects() uint64 {
+	if x != nil {
+		return x.TotalObjects
+	}
+	return 0
+}
+
+func (x *Stats) GetTotalBlocks() uint64 {
+	if x != nil {
+		return x.TotalBlocks
 	}
 	return 0
 }
@@ -655,42 +1687,4464 @@ func (x *Stats) GetKdtreeDepth() uint64 {
 
 func (x *Stats) GetQuadtreeDepth() uint64 {
 	if x != nil {
-		return x.QuadtreeDepth
+		return x.QuadtreeDepth
+	}
+	return 0
+}
+
+func (x *Stats) GetBounds() *MBR {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *Stats) GetPageCapacity() uint64 {
+	if x != nil {
+		return x.PageCapacity
+	}
+	return 0
+}
+
+func (x *Stats) GetMemoryBytes() uint64 {
+	if x != nil {
+		return x.MemoryBytes
+	}
+	return 0
+}
+
+func (x *Stats) GetDiskBytes() uint64 {
+	if x != nil {
+		return x.DiskBytes
+	}
+	return 0
+}
+
+func (x *Stats) GetBuildThreads() uint64 {
+	if x != nil {
+		return x.BuildThreads
+	}
+	return 0
+}
+
+func (x *Stats) GetWidthMeters() float64 {
+	if x != nil && x.WidthMeters != nil {
+		return *x.WidthMeters
+	}
+	return 0
+}
+
+func (x *Stats) GetHeightMeters() float64 {
+	if x != nil && x.HeightMeters != nil {
+		return *x.HeightMeters
+	}
+	return 0
+}
+
+// Page information for disk-aware queries
+type PageInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageId        uint32                 `protobuf:"varint,1,opt,name=page_id,json=pageId,proto3" json:"page_id,omitempty"`
+	TrackId       uint32                 `protobuf:"varint,2,opt,name=track_id,json=trackId,proto3" json:"track_id,omitempty"`
+	ObjectCount   uint32                 `protobuf:"varint,3,opt,name=object_count,json=objectCount,proto3" json:"object_count,omitempty"` // Set by GetPageGraph
+	Extent        *MBR                   `protobuf:"bytes,4,opt,name=extent,proto3" json:"extent,omitempty"`                               // Set by GetPageGraph
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageInfo) Reset() {
+	*x = PageInfo{}
+	mi := &file_urbis_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageInfo) ProtoMessage() {}
+
+func (x *PageInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageInfo.ProtoReflect.Descriptor instead.
+func (*PageInfo) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PageInfo) GetPageId() uint32 {
+	if x != nil {
+		return x.PageId
+	}
+	return 0
+}
+
+func (x *PageInfo) GetTrackId() uint32 {
+	if x != nil {
+		return x.TrackId
+	}
+	return 0
+}
+
+func (x *PageInfo) GetObjectCount() uint32 {
+	if x != nil {
+		return x.ObjectCount
+	}
+	return 0
+}
+
+func (x *PageInfo) GetExtent() *MBR {
+	if x != nil {
+		return x.Extent
+	}
+	return nil
+}
+
+type CreateIndexRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	IndexId string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"` // Client-provided index identifier
+	Config  *Config                `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`                  // Optional configuration
+	// Succeed if the index already exists with the same config; a different
+	// config fails with FAILED_PRECONDITION
+	IfNotExists   bool `protobuf:"varint,3,opt,name=if_not_exists,json=ifNotExists,proto3" json:"if_not_exists,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateIndexRequest) Reset() {
+	*x = CreateIndexRequest{}
+	mi := &file_urbis_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateIndexRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateIndexRequest) ProtoMessage() {}
+
+func (x *CreateIndexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateIndexRequest.ProtoReflect.Descriptor instead.
+func (*CreateIndexRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateIndexRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *CreateIndexRequest) GetConfig() *Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *CreateIndexRequest) GetIfNotExists() bool {
+	if x != nil {
+		return x.IfNotExists
+	}
+	return false
+}
+
+type CreateIndexResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Count         uint64                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Bounds        *MBR                   `protobuf:"bytes,4,opt,name=bounds,proto3" json:"bounds,omitempty"`    // Unset while the index is empty
+	Created       bool                   `protobuf:"varint,5,opt,name=created,proto3" json:"created,omitempty"` // False when if_not_exists found the index already there
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateIndexResponse) Reset() {
+	*x = CreateIndexResponse{}
+	mi := &file_urbis_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateIndexResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateIndexResponse) ProtoMessage() {}
+
+func (x *CreateIndexResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateIndexResponse.ProtoReflect.Descriptor instead.
+func (*CreateIndexResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CreateIndexResponse) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *CreateIndexResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CreateIndexResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *CreateIndexResponse) GetBounds() *MBR {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *CreateIndexResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+type DestroyIndexRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DestroyIndexRequest) Reset() {
+	*x = DestroyIndexRequest{}
+	mi := &file_urbis_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestroyIndexRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestroyIndexRequest) ProtoMessage() {}
+
+func (x *DestroyIndexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestroyIndexRequest.ProtoReflect.Descriptor instead.
+func (*DestroyIndexRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DestroyIndexRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+type DestroyIndexResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DestroyIndexResponse) Reset() {
+	*x = DestroyIndexResponse{}
+	mi := &file_urbis_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestroyIndexResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestroyIndexResponse) ProtoMessage() {}
+
+func (x *DestroyIndexResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestroyIndexResponse.ProtoReflect.Descriptor instead.
+func (*DestroyIndexResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DestroyIndexResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListIndexesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIndexesRequest) Reset() {
+	*x = ListIndexesRequest{}
+	mi := &file_urbis_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIndexesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIndexesRequest) ProtoMessage() {}
+
+func (x *ListIndexesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIndexesRequest.ProtoReflect.Descriptor instead.
+func (*ListIndexesRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{19}
+}
+
+type ListIndexesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexIds      []string               `protobuf:"bytes,1,rep,name=index_ids,json=indexIds,proto3" json:"index_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIndexesResponse) Reset() {
+	*x = ListIndexesResponse{}
+	mi := &file_urbis_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIndexesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIndexesResponse) ProtoMessage() {}
+
+func (x *ListIndexesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIndexesResponse.ProtoReflect.Descriptor instead.
+func (*ListIndexesResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListIndexesResponse) GetIndexIds() []string {
+	if x != nil {
+		return x.IndexIds
+	}
+	return nil
+}
+
+type DescribeIndexRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DescribeIndexRequest) Reset() {
+	*x = DescribeIndexRequest{}
+	mi := &file_urbis_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeIndexRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeIndexRequest) ProtoMessage() {}
+
+func (x *DescribeIndexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeIndexRequest.ProtoReflect.Descriptor instead.
+func (*DescribeIndexRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DescribeIndexRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+type DescribeIndexResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	IndexId string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	// Config the index was created with, defaults filled in. Unset for
+	// indexes loaded from a saved file, whose config was not recorded.
+	Config         *Config `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	Built          bool    `protobuf:"varint,3,opt,name=built,proto3" json:"built,omitempty"` // Built since the last change
+	Count          uint64  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	Bounds         *MBR    `protobuf:"bytes,5,opt,name=bounds,proto3" json:"bounds,omitempty"`                                        // Unset while the index is empty
+	Stats          *Stats  `protobuf:"bytes,6,opt,name=stats,proto3" json:"stats,omitempty"`                                          // Includes memory_bytes, disk_bytes and the current page_capacity
+	ReadOnly       bool    `protobuf:"varint,7,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`                   // Writes and builds fail with FAILED_PRECONDITION
+	PendingChanges uint64  `protobuf:"varint,8,opt,name=pending_changes,json=pendingChanges,proto3" json:"pending_changes,omitempty"` // Inserts and removals since the last build
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DescribeIndexResponse) Reset() {
+	*x = DescribeIndexResponse{}
+	mi := &file_urbis_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeIndexResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeIndexResponse) ProtoMessage() {}
+
+func (x *DescribeIndexResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeIndexResponse.ProtoReflect.Descriptor instead.
+func (*DescribeIndexResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DescribeIndexResponse) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *DescribeIndexResponse) GetConfig() *Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *DescribeIndexResponse) GetBuilt() bool {
+	if x != nil {
+		return x.Built
+	}
+	return false
+}
+
+func (x *DescribeIndexResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *DescribeIndexResponse) GetBounds() *MBR {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *DescribeIndexResponse) GetStats() *Stats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *DescribeIndexResponse) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *DescribeIndexResponse) GetPendingChanges() uint64 {
+	if x != nil {
+		return x.PendingChanges
+	}
+	return 0
+}
+
+type MarkReadOnlyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkReadOnlyRequest) Reset() {
+	*x = MarkReadOnlyRequest{}
+	mi := &file_urbis_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkReadOnlyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkReadOnlyRequest) ProtoMessage() {}
+
+func (x *MarkReadOnlyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkReadOnlyRequest.ProtoReflect.Descriptor instead.
+func (*MarkReadOnlyRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *MarkReadOnlyRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+type MarkReadOnlyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WasReadOnly   bool                   `protobuf:"varint,1,opt,name=was_read_only,json=wasReadOnly,proto3" json:"was_read_only,omitempty"` // The index was already read-only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkReadOnlyResponse) Reset() {
+	*x = MarkReadOnlyResponse{}
+	mi := &file_urbis_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkReadOnlyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkReadOnlyResponse) ProtoMessage() {}
+
+func (x *MarkReadOnlyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkReadOnlyResponse.ProtoReflect.Descriptor instead.
+func (*MarkReadOnlyResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *MarkReadOnlyResponse) GetWasReadOnly() bool {
+	if x != nil {
+		return x.WasReadOnly
+	}
+	return false
+}
+
+type LoadGeoJSONRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`                             // File path to GeoJSON
+	SourceCrs     int32                  `protobuf:"varint,3,opt,name=source_crs,json=sourceCrs,proto3" json:"source_crs,omitempty"` // EPSG code of the file; reprojected to the index CRS (0 = same)
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`          // Only check the file: report the would-be count and feature errors, insert nothing
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadGeoJSONRequest) Reset() {
+	*x = LoadGeoJSONRequest{}
+	mi := &file_urbis_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadGeoJSONRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadGeoJSONRequest) ProtoMessage() {}
+
+func (x *LoadGeoJSONRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadGeoJSONRequest.ProtoReflect.Descriptor instead.
+func (*LoadGeoJSONRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *LoadGeoJSONRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *LoadGeoJSONRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *LoadGeoJSONRequest) GetSourceCrs() int32 {
+	if x != nil {
+		return x.SourceCrs
+	}
+	return 0
+}
+
+func (x *LoadGeoJSONRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type LoadGeoJSONURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`                               // http(s) URL on a host allowed by --allowed-fetch-hosts
+	SourceCrs     int32                  `protobuf:"varint,3,opt,name=source_crs,json=sourceCrs,proto3" json:"source_crs,omitempty"` // EPSG code of the document; reprojected to the index CRS (0 = same)
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`          // As in LoadGeoJSONRequest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadGeoJSONURLRequest) Reset() {
+	*x = LoadGeoJSONURLRequest{}
+	mi := &file_urbis_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadGeoJSONURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadGeoJSONURLRequest) ProtoMessage() {}
+
+func (x *LoadGeoJSONURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadGeoJSONURLRequest.ProtoReflect.Descriptor instead.
+func (*LoadGeoJSONURLRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *LoadGeoJSONURLRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *LoadGeoJSONURLRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *LoadGeoJSONURLRequest) GetSourceCrs() int32 {
+	if x != nil {
+		return x.SourceCrs
+	}
+	return 0
+}
+
+func (x *LoadGeoJSONURLRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type LoadGeoJSONStringRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Geojson       string                 `protobuf:"bytes,2,opt,name=geojson,proto3" json:"geojson,omitempty"`                       // GeoJSON content as string
+	SourceCrs     int32                  `protobuf:"varint,3,opt,name=source_crs,json=sourceCrs,proto3" json:"source_crs,omitempty"` // EPSG code of the content; reprojected to the index CRS (0 = same)
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`          // As in LoadGeoJSONRequest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadGeoJSONStringRequest) Reset() {
+	*x = LoadGeoJSONStringRequest{}
+	mi := &file_urbis_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadGeoJSONStringRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadGeoJSONStringRequest) ProtoMessage() {}
+
+func (x *LoadGeoJSONStringRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadGeoJSONStringRequest.ProtoReflect.Descriptor instead.
+func (*LoadGeoJSONStringRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *LoadGeoJSONStringRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *LoadGeoJSONStringRequest) GetGeojson() string {
+	if x != nil {
+		return x.Geojson
+	}
+	return ""
+}
+
+func (x *LoadGeoJSONStringRequest) GetSourceCrs() int32 {
+	if x != nil {
+		return x.SourceCrs
+	}
+	return 0
+}
+
+func (x *LoadGeoJSONStringRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type LoadWKTRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Wkt           string                 `protobuf:"bytes,2,opt,name=wkt,proto3" json:"wkt,omitempty"` // WKT geometry string, or PostGIS EWKT with an SRID= prefix
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadWKTRequest) Reset() {
+	*x = LoadWKTRequest{}
+	mi := &file_urbis_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadWKTRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadWKTRequest) ProtoMessage() {}
+
+func (x *LoadWKTRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadWKTRequest.ProtoReflect.Descriptor instead.
+func (*LoadWKTRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *LoadWKTRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *LoadWKTRequest) GetWkt() string {
+	if x != nil {
+		return x.Wkt
+	}
+	return ""
+}
+
+type LoadWKBRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Wkb           []byte                 `protobuf:"bytes,2,opt,name=wkb,proto3" json:"wkb,omitempty"` // One or more concatenated WKB geometries
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadWKBRequest) Reset() {
+	*x = LoadWKBRequest{}
+	mi := &file_urbis_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadWKBRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadWKBRequest) ProtoMessage() {}
+
+func (x *LoadWKBRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadWKBRequest.ProtoReflect.Descriptor instead.
+func (*LoadWKBRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *LoadWKBRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *LoadWKBRequest) GetWkb() []byte {
+	if x != nil {
+		return x.Wkb
+	}
+	return nil
+}
+
+type LoadGeoPackageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`   // Server-local path to a .gpkg file
+	Layer         string                 `protobuf:"bytes,3,opt,name=layer,proto3" json:"layer,omitempty"` // Feature table to read; empty for the file's only one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadGeoPackageRequest) Reset() {
+	*x = LoadGeoPackageRequest{}
+	mi := &file_urbis_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadGeoPackageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadGeoPackageRequest) ProtoMessage() {}
+
+func (x *LoadGeoPackageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadGeoPackageRequest.ProtoReflect.Descriptor instead.
+func (*LoadGeoPackageRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *LoadGeoPackageRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *LoadGeoPackageRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *LoadGeoPackageRequest) GetLayer() string {
+	if x != nil {
+		return x.Layer
+	}
+	return ""
+}
+
+type StreamLoadGeoJSONRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"` // Required on the first message, ignored afterwards
+	Chunk         []byte                 `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"`                    // Next chunk of newline-delimited GeoJSON features
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamLoadGeoJSONRequest) Reset() {
+	*x = StreamLoadGeoJSONRequest{}
+	mi := &file_urbis_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamLoadGeoJSONRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamLoadGeoJSONRequest) ProtoMessage() {}
+
+func (x *StreamLoadGeoJSONRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamLoadGeoJSONRequest.ProtoReflect.Descriptor instead.
+func (*StreamLoadGeoJSONRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *StreamLoadGeoJSONRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *StreamLoadGeoJSONRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type StreamLoadWKTRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"` // Required on the first message, ignored afterwards
+	Chunk         []byte                 `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"`                    // Next chunk of newline-delimited WKT or EWKT, one geometry per line
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamLoadWKTRequest) Reset() {
+	*x = StreamLoadWKTRequest{}
+	mi := &file_urbis_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamLoadWKTRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamLoadWKTRequest) ProtoMessage() {}
+
+func (x *StreamLoadWKTRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamLoadWKTRequest.ProtoReflect.Descriptor instead.
+func (*StreamLoadWKTRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *StreamLoadWKTRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *StreamLoadWKTRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type LoadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ObjectsLoaded uint64                 `protobuf:"varint,1,opt,name=objects_loaded,json=objectsLoaded,proto3" json:"objects_loaded,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Count         uint64                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`     // Total objects in the index after loading
+	Bounds        *MBR                   `protobuf:"bytes,4,opt,name=bounds,proto3" json:"bounds,omitempty"`    // Unset while the index is empty
+	Srid          int32                  `protobuf:"varint,5,opt,name=srid,proto3" json:"srid,omitempty"`       // LoadWKT: SRID of an EWKT input, 0 for plain WKT
+	Skipped       uint64                 `protobuf:"varint,6,opt,name=skipped,proto3" json:"skipped,omitempty"` // GeoJSON and GeoPackage loads: features left out for a null or empty geometry
+	// Dry runs: one message per feature a load would leave out or reject,
+	// e.g. "feature 3: unsupported geometry type". StreamLoadWKT: one per
+	// line left out, e.g. "line 7: parse error"
+	Errors        []string `protobuf:"bytes,7,rep,name=errors,proto3" json:"errors,omitempty"`
+	OutOfBounds   uint64   `protobuf:"varint,8,opt,name=out_of_bounds,json=outOfBounds,proto3" json:"out_of_bounds,omitempty"` // GeoJSON and GeoPackage loads: features left out for leaving config.valid_bounds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadResponse) Reset() {
+	*x = LoadResponse{}
+	mi := &file_urbis_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadResponse) ProtoMessage() {}
+
+func (x *LoadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadResponse.ProtoReflect.Descriptor instead.
+func (*LoadResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *LoadResponse) GetObjectsLoaded() uint64 {
+	if x != nil {
+		return x.ObjectsLoaded
+	}
+	return 0
+}
+
+func (x *LoadResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LoadResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *LoadResponse) GetBounds() *MBR {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *LoadResponse) GetSrid() int32 {
+	if x != nil {
+		return x.Srid
+	}
+	return 0
+}
+
+func (x *LoadResponse) GetSkipped() uint64 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *LoadResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *LoadResponse) GetOutOfBounds() uint64 {
+	if x != nil {
+		return x.OutOfBounds
+	}
+	return 0
+}
+
+type InsertPointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	X             float64                `protobuf:"fixed64,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y             float64                `protobuf:"fixed64,3,opt,name=y,proto3" json:"y,omitempty"`
+	ObjectId      uint64                 `protobuf:"varint,4,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"` // Use this ID instead of assigning one (0 = assign)
+	TtlMs         uint64                 `protobuf:"varint,5,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`          // Remove the object on the first sweep this long after inserting (0 = never)
+	Z             *float64               `protobuf:"fixed64,6,opt,name=z,proto3,oneof" json:"z,omitempty"`                        // Elevation; kept but not indexed
+	M             *float64               `protobuf:"fixed64,7,opt,name=m,proto3,oneof" json:"m,omitempty"`                        // Measure; kept but not indexed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InsertPointRequest) Reset() {
+	*x = InsertPointRequest{}
+	mi := &file_urbis_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InsertPointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InsertPointRequest) ProtoMessage() {}
+
+func (x *InsertPointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InsertPointRequest.ProtoReflect.Descriptor instead.
+func (*InsertPointRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *InsertPointRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *InsertPointRequest) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *InsertPointRequest) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *InsertPointRequest) GetObjectId() uint64 {
+	if x != nil {
+		return x.ObjectId
+	}
+	return 0
+}
+
+func (x *InsertPointRequest) GetTtlMs() uint64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *InsertPointRequest) GetZ() float64 {
+	if x != nil && x.Z != nil {
+		return *x.Z
+	}
+	return 0
+}
+
+func (x *InsertPointRequest) GetM() float64 {
+	if x != nil && x.M != nil {
+		return *x.M
+	}
+	return 0
+}
+
+type InsertLineStringRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Points        []*Point               `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"`
+	ObjectId      uint64                 `protobuf:"varint,3,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"` // Use this ID instead of assigning one (0 = assign)
+	TtlMs         uint64                 `protobuf:"varint,4,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`          // Remove the object on the first sweep this long after inserting (0 = never)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InsertLineStringRequest) Reset() {
+	*x = InsertLineStringRequest{}
+	mi := &file_urbis_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InsertLineStringRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InsertLineStringRequest) ProtoMessage() {}
+
+func (x *InsertLineStringRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InsertLineStringRequest.ProtoReflect.Descriptor instead.
+func (*InsertLineStringRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *InsertLineStringRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *InsertLineStringRequest) GetPoints() []*Point {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+func (x *InsertLineStringRequest) GetObjectId() uint64 {
+	if x != nil {
+		return x.ObjectId
+	}
+	return 0
+}
+
+func (x *InsertLineStringRequest) GetTtlMs() uint64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+type InsertPolygonRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Exterior      []*Point               `protobuf:"bytes,2,rep,name=exterior,proto3" json:"exterior,omitempty"`
+	ObjectId      uint64                 `protobuf:"varint,3,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"` // Use this ID instead of assigning one (0 = assign)
+	TtlMs         uint64                 `protobuf:"varint,4,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`          // Remove the object on the first sweep this long after inserting (0 = never)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InsertPolygonRequest) Reset() {
+	*x = InsertPolygonRequest{}
+	mi := &file_urbis_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InsertPolygonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InsertPolygonRequest) ProtoMessage() {}
+
+func (x *InsertPolygonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InsertPolygonRequest.ProtoReflect.Descriptor instead.
+func (*InsertPolygonRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *InsertPolygonRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *InsertPolygonRequest) GetExterior() []*Point {
+	if x != nil {
+		return x.Exterior
+	}
+	return nil
+}
+
+func (x *InsertPolygonRequest) GetObjectId() uint64 {
+	if x != nil {
+		return x.ObjectId
+	}
+	return 0
+}
+
+func (x *InsertPolygonRequest) GetTtlMs() uint64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+type InsertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ObjectId      uint64                 `protobuf:"varint,1,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	IsValid       *bool                  `protobuf:"varint,2,opt,name=is_valid,json=isValid,proto3,oneof" json:"is_valid,omitempty"` // Polygon validity, set when polygon_validation is REPORT
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                         // Why the polygon is invalid
+	Mbr           *MBR                   `protobuf:"bytes,4,opt,name=mbr,proto3" json:"mbr,omitempty"`                               // Bounding box computed for the new object
+	Centroid      *Point                 `protobuf:"bytes,5,opt,name=centroid,proto3" json:"centroid,omitempty"`                     // Centroid computed for the new object
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InsertResponse) Reset() {
+	*x = InsertResponse{}
+	mi := &file_urbis_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InsertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InsertResponse) ProtoMessage() {}
+
+func (x *InsertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InsertResponse.ProtoReflect.Descriptor instead.
+func (*InsertResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *InsertResponse) GetObjectId() uint64 {
+	if x != nil {
+		return x.ObjectId
+	}
+	return 0
+}
+
+func (x *InsertResponse) GetIsValid() bool {
+	if x != nil && x.IsValid != nil {
+		return *x.IsValid
+	}
+	return false
+}
+
+func (x *InsertResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *InsertResponse) GetMbr() *MBR {
+	if x != nil {
+		return x.Mbr
+	}
+	return nil
+}
+
+func (x *InsertResponse) GetCentroid() *Point {
+	if x != nil {
+		return x.Centroid
+	}
+	return nil
+}
+
+type StreamInsertRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	IndexId string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"` // Required on the first message; later ones may omit it, and naming another index fails that item
+	// Types that are valid to be assigned to Geometry:
+	//
+	//	*StreamInsertRequest_Point
+	//	*StreamInsertRequest_Line
+	//	*StreamInsertRequest_Polygon
+	Geometry      isStreamInsertRequest_Geometry `protobuf_oneof:"geometry"`
+	ObjectId      uint64                         `protobuf:"varint,5,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"` // Use this ID instead of assigning one (0 = assign)
+	TtlMs         uint64                         `protobuf:"varint,6,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`          // As in InsertPointRequest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamInsertRequest) Reset() {
+	*x = StreamInsertRequest{}
+	mi := &file_urbis_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamInsertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamInsertRequest) ProtoMessage() {}
+
+func (x *StreamInsertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamInsertRequest.ProtoReflect.Descriptor instead.
+func (*StreamInsertRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *StreamInsertRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *StreamInsertRequest) GetGeometry() isStreamInsertRequest_Geometry {
+	if x != nil {
+		return x.Geometry
+	}
+	return nil
+}
+
+func (x *StreamInsertRequest) GetPoint() *Point {
+	if x != nil {
+		if x, ok := x.Geometry.(*StreamInsertRequest_Point); ok {
+			return x.Point
+		}
+	}
+	return nil
+}
+
+func (x *StreamInsertRequest) GetLine() *LineString {
+	if x != nil {
+		if x, ok := x.Geometry.(*StreamInsertRequest_Line); ok {
+			return x.Line
+		}
+	}
+	return nil
+}
+
+func (x *StreamInsertRequest) GetPolygon() *Polygon {
+	if x != nil {
+		if x, ok := x.Geometry.(*StreamInsertRequest_Polygon); ok {
+			return x.Polygon
+		}
+	}
+	return nil
+}
+
+func (x *StreamInsertRequest) GetObjectId() uint64 {
+	if x != nil {
+		return x.ObjectId
+	}
+	return 0
+}
+
+func (x *StreamInsertRequest) GetTtlMs() uint64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+type isStreamInsertRequest_Geometry interface {
+	isStreamInsertRequest_Geometry()
+}
+
+type StreamInsertRequest_Point struct {
+	Point *Point `protobuf:"bytes,2,opt,name=point,proto3,oneof"`
+}
+
+type StreamInsertRequest_Line struct {
+	Line *LineString `protobuf:"bytes,3,opt,name=line,proto3,oneof"`
+}
+
+type StreamInsertRequest_Polygon struct {
+	Polygon *Polygon `protobuf:"bytes,4,opt,name=polygon,proto3,oneof"` // Exterior ring only; holes are rejected
+}
+
+func (*StreamInsertRequest_Point) isStreamInsertRequest_Geometry() {}
+
+func (*StreamInsertRequest_Line) isStreamInsertRequest_Geometry() {}
+
+func (*StreamInsertRequest_Polygon) isStreamInsertRequest_Geometry() {}
+
+// Outcome of one StreamInsertRequest, sent in request order
+type StreamInsertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"` // Position of the request in the stream, from 0
+	Result        *InsertResponse        `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`      // Set when the insert succeeded
+	Code          uint32                 `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`         // gRPC status code of a failed insert (0 = OK)
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`        // Why the insert failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamInsertResponse) Reset() {
+	*x = StreamInsertResponse{}
+	mi := &file_urbis_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamInsertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamInsertResponse) ProtoMessage() {}
+
+func (x *StreamInsertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamInsertResponse.ProtoReflect.Descriptor instead.
+func (*StreamInsertResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *StreamInsertResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *StreamInsertResponse) GetResult() *InsertResponse {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *StreamInsertResponse) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *StreamInsertResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RemoveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	ObjectId      uint64                 `protobuf:"varint,2,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRequest) Reset() {
+	*x = RemoveRequest{}
+	mi := &file_urbis_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRequest) ProtoMessage() {}
+
+func (x *RemoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RemoveRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *RemoveRequest) GetObjectId() uint64 {
+	if x != nil {
+		return x.ObjectId
+	}
+	return 0
+}
+
+type RemoveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveResponse) Reset() {
+	*x = RemoveResponse{}
+	mi := &file_urbis_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveResponse) ProtoMessage() {}
+
+func (x *RemoveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveResponse.ProtoReflect.Descriptor instead.
+func (*RemoveResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RemoveResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RemoveRangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Region        *MBR                   `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	Match         RangeMatch             `protobuf:"varint,3,opt,name=match,proto3,enum=urbis.RangeMatch" json:"match,omitempty"` // Inclusion rule (default: fully contained)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRangeRequest) Reset() {
+	*x = RemoveRangeRequest{}
+	mi := &file_urbis_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRangeRequest) ProtoMessage() {}
+
+func (x *RemoveRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRangeRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRangeRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *RemoveRangeRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *RemoveRangeRequest) GetRegion() *MBR {
+	if x != nil {
+		return x.Region
+	}
+	return nil
+}
+
+func (x *RemoveRangeRequest) GetMatch() RangeMatch {
+	if x != nil {
+		return x.Match
+	}
+	return RangeMatch_RANGE_MATCH_CONTAINED
+}
+
+type RemoveRangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Removed       uint64                 `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"` // Objects deleted
+	Count         uint64                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`     // Objects remaining in the index
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRangeResponse) Reset() {
+	*x = RemoveRangeResponse{}
+	mi := &file_urbis_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRangeResponse) ProtoMessage() {}
+
+func (x *RemoveRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRangeResponse.ProtoReflect.Descriptor instead.
+func (*RemoveRangeResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RemoveRangeResponse) GetRemoved() uint64 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+func (x *RemoveRangeResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type SweepExpiredRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SweepExpiredRequest) Reset() {
+	*x = SweepExpiredRequest{}
+	mi := &file_urbis_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SweepExpiredRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepExpiredRequest) ProtoMessage() {}
+
+func (x *SweepExpiredRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepExpiredRequest.ProtoReflect.Descriptor instead.
+func (*SweepExpiredRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *SweepExpiredRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+type SweepExpiredResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Removed       uint64                 `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"` // Expired objects deleted
+	Count         uint64                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`     // Objects remaining in the index
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SweepExpiredResponse) Reset() {
+	*x = SweepExpiredResponse{}
+	mi := &file_urbis_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SweepExpiredResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepExpiredResponse) ProtoMessage() {}
+
+func (x *SweepExpiredResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepExpiredResponse.ProtoReflect.Descriptor instead.
+func (*SweepExpiredResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SweepExpiredResponse) GetRemoved() uint64 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+func (x *SweepExpiredResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetObjectRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IndexId        string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	ObjectId       uint64                 `protobuf:"varint,2,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	IncludeVersion bool                   `protobuf:"varint,3,opt,name=include_version,json=includeVersion,proto3" json:"include_version,omitempty"` // Fill version and modified_at_ms
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetObjectRequest) Reset() {
+	*x = GetObjectRequest{}
+	mi := &file_urbis_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetObjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetObjectRequest) ProtoMessage() {}
+
+func (x *GetObjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetObjectRequest.ProtoReflect.Descriptor instead.
+func (*GetObjectRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetObjectRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *GetObjectRequest) GetObjectId() uint64 {
+	if x != nil {
+		return x.ObjectId
+	}
+	return 0
+}
+
+func (x *GetObjectRequest) GetIncludeVersion() bool {
+	if x != nil {
+		return x.IncludeVersion
+	}
+	return false
+}
+
+type GetObjectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Object        *SpatialObject         `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetObjectResponse) Reset() {
+	*x = GetObjectResponse{}
+	mi := &file_urbis_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetObjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetObjectResponse) ProtoMessage() {}
+
+func (x *GetObjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetObjectResponse.ProtoReflect.Descriptor instead.
+func (*GetObjectResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetObjectResponse) GetObject() *SpatialObject {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *GetObjectResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type BatchGetObjectsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IndexId        string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	ObjectIds      []uint64               `protobuf:"varint,2,rep,packed,name=object_ids,json=objectIds,proto3" json:"object_ids,omitempty"`
+	IncludeVersion bool                   `protobuf:"varint,3,opt,name=include_version,json=includeVersion,proto3" json:"include_version,omitempty"` // Fill version and modified_at_ms
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BatchGetObjectsRequest) Reset() {
+	*x = BatchGetObjectsRequest{}
+	mi := &file_urbis_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetObjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetObjectsRequest) ProtoMessage() {}
+
+func (x *BatchGetObjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetObjectsRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetObjectsRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *BatchGetObjectsRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *BatchGetObjectsRequest) GetObjectIds() []uint64 {
+	if x != nil {
+		return x.ObjectIds
+	}
+	return nil
+}
+
+func (x *BatchGetObjectsRequest) GetIncludeVersion() bool {
+	if x != nil {
+		return x.IncludeVersion
+	}
+	return false
+}
+
+type BatchGetObjectsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Parallel to object_ids; missing IDs have an empty object and found = false
+	Objects       []*SpatialObject `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
+	Found         []bool           `protobuf:"varint,2,rep,packed,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetObjectsResponse) Reset() {
+	*x = BatchGetObjectsResponse{}
+	mi := &file_urbis_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetObjectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetObjectsResponse) ProtoMessage() {}
+
+func (x *BatchGetObjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetObjectsResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetObjectsResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *BatchGetObjectsResponse) GetObjects() []*SpatialObject {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *BatchGetObjectsResponse) GetFound() []bool {
+	if x != nil {
+		return x.Found
+	}
+	return nil
+}
+
+type SetPropertiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	ObjectId      uint64                 `protobuf:"varint,2,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	Properties    []byte                 `protobuf:"bytes,3,opt,name=properties,proto3" json:"properties,omitempty"` // JSON encoded properties; empty clears them
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPropertiesRequest) Reset() {
+	*x = SetPropertiesRequest{}
+	mi := &file_urbis_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPropertiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPropertiesRequest) ProtoMessage() {}
+
+func (x *SetPropertiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPropertiesRequest.ProtoReflect.Descriptor instead.
+func (*SetPropertiesRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *SetPropertiesRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *SetPropertiesRequest) GetObjectId() uint64 {
+	if x != nil {
+		return x.ObjectId
+	}
+	return 0
+}
+
+func (x *SetPropertiesRequest) GetProperties() []byte {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type SetPropertiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPropertiesResponse) Reset() {
+	*x = SetPropertiesResponse{}
+	mi := &file_urbis_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPropertiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPropertiesResponse) ProtoMessage() {}
+
+func (x *SetPropertiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPropertiesResponse.ProtoReflect.Descriptor instead.
+func (*SetPropertiesResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *SetPropertiesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type GetPropertiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	ObjectId      uint64                 `protobuf:"varint,2,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPropertiesRequest) Reset() {
+	*x = GetPropertiesRequest{}
+	mi := &file_urbis_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPropertiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPropertiesRequest) ProtoMessage() {}
+
+func (x *GetPropertiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPropertiesRequest.ProtoReflect.Descriptor instead.
+func (*GetPropertiesRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetPropertiesRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *GetPropertiesRequest) GetObjectId() uint64 {
+	if x != nil {
+		return x.ObjectId
+	}
+	return 0
+}
+
+type GetPropertiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Properties    []byte                 `protobuf:"bytes,1,opt,name=properties,proto3" json:"properties,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPropertiesResponse) Reset() {
+	*x = GetPropertiesResponse{}
+	mi := &file_urbis_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPropertiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPropertiesResponse) ProtoMessage() {}
+
+func (x *GetPropertiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPropertiesResponse.ProtoReflect.Descriptor instead.
+func (*GetPropertiesResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *GetPropertiesResponse) GetProperties() []byte {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *GetPropertiesResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type BuildRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildRequest) Reset() {
+	*x = BuildRequest{}
+	mi := &file_urbis_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildRequest) ProtoMessage() {}
+
+func (x *BuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildRequest.ProtoReflect.Descriptor instead.
+func (*BuildRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *BuildRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+type BuildResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	BuildTimeMs   float64                `protobuf:"fixed64,2,opt,name=build_time_ms,json=buildTimeMs,proto3" json:"build_time_ms,omitempty"`
+	Count         uint64                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Bounds        *MBR                   `protobuf:"bytes,4,opt,name=bounds,proto3" json:"bounds,omitempty"`                                  // Unset while the index is empty
+	BuildThreads  uint64                 `protobuf:"varint,5,opt,name=build_threads,json=buildThreads,proto3" json:"build_threads,omitempty"` // Threads the build used, at most config.build_threads
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildResponse) Reset() {
+	*x = BuildResponse{}
+	mi := &file_urbis_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildResponse) ProtoMessage() {}
+
+func (x *BuildResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildResponse.ProtoReflect.Descriptor instead.
+func (*BuildResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *BuildResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *BuildResponse) GetBuildTimeMs() float64 {
+	if x != nil {
+		return x.BuildTimeMs
+	}
+	return 0
+}
+
+func (x *BuildResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *BuildResponse) GetBounds() *MBR {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *BuildResponse) GetBuildThreads() uint64 {
+	if x != nil {
+		return x.BuildThreads
+	}
+	return 0
+}
+
+type BuildProgressResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Done          uint64                 `protobuf:"varint,1,opt,name=done,proto3" json:"done,omitempty"` // Objects processed so far
+	Total         uint64                 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Percent       float64                `protobuf:"fixed64,3,opt,name=percent,proto3" json:"percent,omitempty"`
+	Result        *BuildResponse         `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"` // Set on the final message once the build completes
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildProgressResponse) Reset() {
+	*x = BuildProgressResponse{}
+	mi := &file_urbis_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildProgressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildProgressResponse) ProtoMessage() {}
+
+func (x *BuildProgressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildProgressResponse.ProtoReflect.Descriptor instead.
+func (*BuildProgressResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *BuildProgressResponse) GetDone() uint64 {
+	if x != nil {
+		return x.Done
+	}
+	return 0
+}
+
+func (x *BuildProgressResponse) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *BuildProgressResponse) GetPercent() float64 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *BuildProgressResponse) GetResult() *BuildResponse {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type OptimizeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OptimizeRequest) Reset() {
+	*x = OptimizeRequest{}
+	mi := &file_urbis_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OptimizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OptimizeRequest) ProtoMessage() {}
+
+func (x *OptimizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OptimizeRequest.ProtoReflect.Descriptor instead.
+func (*OptimizeRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *OptimizeRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+type OptimizeResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Message              string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Before               *Stats                 `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"`                                                            // Stats before optimizing
+	After                *Stats                 `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`                                                              // Stats after optimizing
+	EstimatedSeeksBefore uint64                 `protobuf:"varint,4,opt,name=estimated_seeks_before,json=estimatedSeeksBefore,proto3" json:"estimated_seeks_before,omitempty"` // Seeks for a query over the central quarter of the bounds
+	EstimatedSeeksAfter  uint64                 `protobuf:"varint,5,opt,name=estimated_seeks_after,json=estimatedSeeksAfter,proto3" json:"estimated_seeks_after,omitempty"`    // Seeks for the same query after optimizing
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *OptimizeResponse) Reset() {
+	*x = OptimizeResponse{}
+	mi := &file_urbis_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OptimizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OptimizeResponse) ProtoMessage() {}
+
+func (x *OptimizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OptimizeResponse.ProtoReflect.Descriptor instead.
+func (*OptimizeResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *OptimizeResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *OptimizeResponse) GetBefore() *Stats {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *OptimizeResponse) GetAfter() *Stats {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *OptimizeResponse) GetEstimatedSeeksBefore() uint64 {
+	if x != nil {
+		return x.EstimatedSeeksBefore
+	}
+	return 0
+}
+
+func (x *OptimizeResponse) GetEstimatedSeeksAfter() uint64 {
+	if x != nil {
+		return x.EstimatedSeeksAfter
+	}
+	return 0
+}
+
+type CompactRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompactRequest) Reset() {
+	*x = CompactRequest{}
+	mi := &file_urbis_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactRequest) ProtoMessage() {}
+
+func (x *CompactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompactRequest.ProtoReflect.Descriptor instead.
+func (*CompactRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *CompactRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+type CompactResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Before        *Stats                 `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"` // Stats before compacting
+	After         *Stats                 `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`   // Stats after compacting
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompactResponse) Reset() {
+	*x = CompactResponse{}
+	mi := &file_urbis_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompactResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactResponse) ProtoMessage() {}
+
+func (x *CompactResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompactResponse.ProtoReflect.Descriptor instead.
+func (*CompactResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *CompactResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CompactResponse) GetBefore() *Stats {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *CompactResponse) GetAfter() *Stats {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+type AutoTuneRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	SampleQueries []*MBR                 `protobuf:"bytes,2,rep,name=sample_queries,json=sampleQueries,proto3" json:"sample_queries,omitempty"` // Representative query boxes (default: central quarter and quadrants)
+	Apply         bool                   `protobuf:"varint,3,opt,name=apply,proto3" json:"apply,omitempty"`                                     // Rebuild the index with the recommended capacity
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AutoTuneRequest) Reset() {
+	*x = AutoTuneRequest{}
+	mi := &file_urbis_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AutoTuneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutoTuneRequest) ProtoMessage() {}
+
+func (x *AutoTuneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutoTuneRequest.ProtoReflect.Descriptor instead.
+func (*AutoTuneRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *AutoTuneRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *AutoTuneRequest) GetSampleQueries() []*MBR {
+	if x != nil {
+		return x.SampleQueries
+	}
+	return nil
+}
+
+func (x *AutoTuneRequest) GetApply() bool {
+	if x != nil {
+		return x.Apply
+	}
+	return false
+}
+
+// Layout one page capacity would produce
+type TuneCandidate struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PageCapacity    uint64                 `protobuf:"varint,1,opt,name=page_capacity,json=pageCapacity,proto3" json:"page_capacity,omitempty"`
+	TotalPages      uint64                 `protobuf:"varint,2,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	PageUtilization float64                `protobuf:"fixed64,3,opt,name=page_utilization,json=pageUtilization,proto3" json:"page_utilization,omitempty"`
+	AvgSeeks        float64                `protobuf:"fixed64,4,opt,name=avg_seeks,json=avgSeeks,proto3" json:"avg_seeks,omitempty"`      // Mean estimated seeks per sample query
+	Cost            float64                `protobuf:"fixed64,5,opt,name=cost,proto3" json:"cost,omitempty"`                              // avg_cost_ms / page_utilization; lower is better
+	AvgCostMs       float64                `protobuf:"fixed64,6,opt,name=avg_cost_ms,json=avgCostMs,proto3" json:"avg_cost_ms,omitempty"` // Mean estimated read time per sample query under the index's seek_cost
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TuneCandidate) Reset() {
+	*x = TuneCandidate{}
+	mi := &file_urbis_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TuneCandidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TuneCandidate) ProtoMessage() {}
+
+func (x *TuneCandidate) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TuneCandidate.ProtoReflect.Descriptor instead.
+func (*TuneCandidate) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *TuneCandidate) GetPageCapacity() uint64 {
+	if x != nil {
+		return x.PageCapacity
+	}
+	return 0
+}
+
+func (x *TuneCandidate) GetTotalPages() uint64 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *TuneCandidate) GetPageUtilization() float64 {
+	if x != nil {
+		return x.PageUtilization
+	}
+	return 0
+}
+
+func (x *TuneCandidate) GetAvgSeeks() float64 {
+	if x != nil {
+		return x.AvgSeeks
+	}
+	return 0
+}
+
+func (x *TuneCandidate) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *TuneCandidate) GetAvgCostMs() float64 {
+	if x != nil {
+		return x.AvgCostMs
+	}
+	return 0
+}
+
+type AutoTuneResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageCapacity  uint64                 `protobuf:"varint,1,opt,name=page_capacity,json=pageCapacity,proto3" json:"page_capacity,omitempty"` // Recommended capacity
+	Candidates    []*TuneCandidate       `protobuf:"bytes,2,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	Applied       bool                   `protobuf:"varint,3,opt,name=applied,proto3" json:"applied,omitempty"` // The index now uses page_capacity
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AutoTuneResponse) Reset() {
+	*x = AutoTuneResponse{}
+	mi := &file_urbis_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AutoTuneResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutoTuneResponse) ProtoMessage() {}
+
+func (x *AutoTuneResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutoTuneResponse.ProtoReflect.Descriptor instead.
+func (*AutoTuneResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *AutoTuneResponse) GetPageCapacity() uint64 {
+	if x != nil {
+		return x.PageCapacity
+	}
+	return 0
+}
+
+func (x *AutoTuneResponse) GetCandidates() []*TuneCandidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *AutoTuneResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+type RangeQueryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IndexId        string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Range          *MBR                   `protobuf:"bytes,2,opt,name=range,proto3" json:"range,omitempty"`
+	Structure      IndexStructure         `protobuf:"varint,3,opt,name=structure,proto3,enum=urbis.IndexStructure" json:"structure,omitempty"`                       // Preferred structure (ignored by QueryAdjacent)
+	Limit          uint32                 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                                                         // Max objects per page, in sort_by order (0 = all)
+	Cursor         string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`                                                        // next_cursor from the previous page
+	SortBy         RangeSort              `protobuf:"varint,6,opt,name=sort_by,json=sortBy,proto3,enum=urbis.RangeSort" json:"sort_by,omitempty"`                    // Result order; ties are broken by ID
+	IncludeVersion bool                   `protobuf:"varint,7,opt,name=include_version,json=includeVersion,proto3" json:"include_version,omitempty"`                 // Fill version and modified_at_ms
+	Encoding       GeometryEncoding       `protobuf:"varint,8,opt,name=encoding,proto3,enum=urbis.GeometryEncoding" json:"encoding,omitempty"`                       // Geometry format of the results
+	BestEffort     bool                   `protobuf:"varint,9,opt,name=best_effort,json=bestEffort,proto3" json:"best_effort,omitempty"`                             // Skip corrupt pages with a warning instead of failing (QueryRange only)
+	FieldMask      []ObjectField          `protobuf:"varint,10,rep,packed,name=field_mask,json=fieldMask,proto3,enum=urbis.ObjectField" json:"field_mask,omitempty"` // Object fields to return (empty = all)
+	GeomTypes      []GeomType             `protobuf:"varint,11,rep,packed,name=geom_types,json=geomTypes,proto3,enum=urbis.GeomType" json:"geom_types,omitempty"`    // Geometry types to return (empty = all)
+	QueryCrs       int32                  `protobuf:"varint,12,opt,name=query_crs,json=queryCrs,proto3" json:"query_crs,omitempty"`                                  // EPSG code of range and the returned geometries (0 = index CRS)
+	IncludePages   bool                   `protobuf:"varint,13,opt,name=include_pages,json=includePages,proto3" json:"include_pages,omitempty"`                      // Return the pages the query touched in pages (QueryRange only)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RangeQueryRequest) Reset() {
+	*x = RangeQueryRequest{}
+	mi := &file_urbis_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RangeQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RangeQueryRequest) ProtoMessage() {}
+
+func (x *RangeQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RangeQueryRequest.ProtoReflect.Descriptor instead.
+func (*RangeQueryRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *RangeQueryRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *RangeQueryRequest) GetRange() *MBR {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *RangeQueryRequest) GetStructure() IndexStructure {
+	if x != nil {
+		return x.Structure
+	}
+	return IndexStructure_INDEX_STRUCTURE_AUTO
+}
+
+func (x *RangeQueryRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *RangeQueryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *RangeQueryRequest) GetSortBy() RangeSort {
+	if x != nil {
+		return x.SortBy
+	}
+	return RangeSort_RANGE_SORT_NONE
+}
+
+func (x *RangeQueryRequest) GetIncludeVersion() bool {
+	if x != nil {
+		return x.IncludeVersion
+	}
+	return false
+}
+
+func (x *RangeQueryRequest) GetEncoding() GeometryEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return GeometryEncoding_GEOMETRY_ENCODING_STRUCTURED
+}
+
+func (x *RangeQueryRequest) GetBestEffort() bool {
+	if x != nil {
+		return x.BestEffort
+	}
+	return false
+}
+
+func (x *RangeQueryRequest) GetFieldMask() []ObjectField {
+	if x != nil {
+		return x.FieldMask
+	}
+	return nil
+}
+
+func (x *RangeQueryRequest) GetGeomTypes() []GeomType {
+	if x != nil {
+		return x.GeomTypes
+	}
+	return nil
+}
+
+func (x *RangeQueryRequest) GetQueryCrs() int32 {
+	if x != nil {
+		return x.QueryCrs
+	}
+	return 0
+}
+
+func (x *RangeQueryRequest) GetIncludePages() bool {
+	if x != nil {
+		return x.IncludePages
+	}
+	return false
+}
+
+type EstimateCountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Range         *MBR                   `protobuf:"bytes,2,opt,name=range,proto3" json:"range,omitempty"` // min_x > max_x crosses the antimeridian, as in QueryRange
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EstimateCountRequest) Reset() {
+	*x = EstimateCountRequest{}
+	mi := &file_urbis_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateCountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateCountRequest) ProtoMessage() {}
+
+func (x *EstimateCountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateCountRequest.ProtoReflect.Descriptor instead.
+func (*EstimateCountRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *EstimateCountRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *EstimateCountRequest) GetRange() *MBR {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+type EstimateCountResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Objects on the pages the range touches; never fewer than QueryRange
+	// returns, but may include objects just outside the range
+	EstimatedCount uint64 `protobuf:"varint,1,opt,name=estimated_count,json=estimatedCount,proto3" json:"estimated_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EstimateCountResponse) Reset() {
+	*x = EstimateCountResponse{}
+	mi := &file_urbis_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateCountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateCountResponse) ProtoMessage() {}
+
+func (x *EstimateCountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateCountResponse.ProtoReflect.Descriptor instead.
+func (*EstimateCountResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *EstimateCountResponse) GetEstimatedCount() uint64 {
+	if x != nil {
+		return x.EstimatedCount
+	}
+	return 0
+}
+
+type MultiRangeQueryRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	IndexId   string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Ranges    []*MBR                 `protobuf:"bytes,2,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	Structure IndexStructure         `protobuf:"varint,3,opt,name=structure,proto3,enum=urbis.IndexStructure" json:"structure,omitempty"` // Preferred structure
+	// List each object only under the first range that contains it; otherwise
+	// an object in several ranges is listed under each of them
+	Deduplicate    bool             `protobuf:"varint,4,opt,name=deduplicate,proto3" json:"deduplicate,omitempty"`
+	IncludeVersion bool             `protobuf:"varint,5,opt,name=include_version,json=includeVersion,proto3" json:"include_version,omitempty"`                // Fill version and modified_at_ms
+	Encoding       GeometryEncoding `protobuf:"varint,6,opt,name=encoding,proto3,enum=urbis.GeometryEncoding" json:"encoding,omitempty"`                      // Geometry format of the results
+	FieldMask      []ObjectField    `protobuf:"varint,7,rep,packed,name=field_mask,json=fieldMask,proto3,enum=urbis.ObjectField" json:"field_mask,omitempty"` // Object fields to return (empty = all)
+	GeomTypes      []GeomType       `protobuf:"varint,8,rep,packed,name=geom_types,json=geomTypes,proto3,enum=urbis.GeomType" json:"geom_types,omitempty"`    // Geometry types to return (empty = all)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MultiRangeQueryRequest) Reset() {
+	*x = MultiRangeQueryRequest{}
+	mi := &file_urbis_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiRangeQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiRangeQueryRequest) ProtoMessage() {}
+
+func (x *MultiRangeQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiRangeQueryRequest.ProtoReflect.Descriptor instead.
+func (*MultiRangeQueryRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *MultiRangeQueryRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *MultiRangeQueryRequest) GetRanges() []*MBR {
+	if x != nil {
+		return x.Ranges
+	}
+	return nil
+}
+
+func (x *MultiRangeQueryRequest) GetStructure() IndexStructure {
+	if x != nil {
+		return x.Structure
+	}
+	return IndexStructure_INDEX_STRUCTURE_AUTO
+}
+
+func (x *MultiRangeQueryRequest) GetDeduplicate() bool {
+	if x != nil {
+		return x.Deduplicate
+	}
+	return false
+}
+
+func (x *MultiRangeQueryRequest) GetIncludeVersion() bool {
+	if x != nil {
+		return x.IncludeVersion
+	}
+	return false
+}
+
+func (x *MultiRangeQueryRequest) GetEncoding() GeometryEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return GeometryEncoding_GEOMETRY_ENCODING_STRUCTURED
+}
+
+func (x *MultiRangeQueryRequest) GetFieldMask() []ObjectField {
+	if x != nil {
+		return x.FieldMask
+	}
+	return nil
+}
+
+func (x *MultiRangeQueryRequest) GetGeomTypes() []GeomType {
+	if x != nil {
+		return x.GeomTypes
+	}
+	return nil
+}
+
+type RangeResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Objects       []*SpatialObject       `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
+	Count         uint64                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	QueryStats    *QueryStats            `protobuf:"bytes,3,opt,name=query_stats,json=queryStats,proto3" json:"query_stats,omitempty"`
+	Geojson       string                 `protobuf:"bytes,4,opt,name=geojson,proto3" json:"geojson,omitempty"`                                // FeatureCollection of the results (GEOMETRY_ENCODING_GEOJSON)
+	Stale         bool                   `protobuf:"varint,5,opt,name=stale,proto3" json:"stale,omitempty"`                                   // The index changed since its last build; answered by a page scan
+	QueryTimeMs   float64                `protobuf:"fixed64,6,opt,name=query_time_ms,json=queryTimeMs,proto3" json:"query_time_ms,omitempty"` // QueryRangeMulti: time this index took, including any wait for a query slot
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RangeResult) Reset() {
+	*x = RangeResult{}
+	mi := &file_urbis_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RangeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RangeResult) ProtoMessage() {}
+
+func (x *RangeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RangeResult.ProtoReflect.Descriptor instead.
+func (*RangeResult) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *RangeResult) GetObjects() []*SpatialObject {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *RangeResult) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *RangeResult) GetQueryStats() *QueryStats {
+	if x != nil {
+		return x.QueryStats
+	}
+	return nil
+}
+
+func (x *RangeResult) GetGeojson() string {
+	if x != nil {
+		return x.Geojson
+	}
+	return ""
+}
+
+func (x *RangeResult) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *RangeResult) GetQueryTimeMs() float64 {
+	if x != nil {
+		return x.QueryTimeMs
+	}
+	return 0
+}
+
+type MultiQueryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Keyed by position in ranges; every range has an entry, possibly empty
+	Results       map[uint32]*RangeResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Count         uint64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"` // Objects across all results
+	QueryTimeMs   float64                 `protobuf:"fixed64,3,opt,name=query_time_ms,json=queryTimeMs,proto3" json:"query_time_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MultiQueryResponse) Reset() {
+	*x = MultiQueryResponse{}
+	mi := &file_urbis_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MultiQueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiQueryResponse) ProtoMessage() {}
+
+func (x *MultiQueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiQueryResponse.ProtoReflect.Descriptor instead.
+func (*MultiQueryResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *MultiQueryResponse) GetResults() map[uint32]*RangeResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *MultiQueryResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *MultiQueryResponse) GetQueryTimeMs() float64 {
+	if x != nil {
+		return x.QueryTimeMs
+	}
+	return 0
+}
+
+type QueryRangeMultiRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	IndexIds  []string               `protobuf:"bytes,1,rep,name=index_ids,json=indexIds,proto3" json:"index_ids,omitempty"` // Indexes to query, e.g. the shards of a region
+	Range     *MBR                   `protobuf:"bytes,2,opt,name=range,proto3" json:"range,omitempty"`
+	Structure IndexStructure         `protobuf:"varint,3,opt,name=structure,proto3,enum=urbis.IndexStructure" json:"structure,omitempty"` // Preferred structure
+	// List each feature only under the first index in index_ids that has
+	// it; otherwise a feature in several indexes is listed under each.
+	// Objects are the same feature when their geometry type, coordinates,
+	// Z/M values and properties match; their IDs, which are per index, are
+	// not compared.
+	Deduplicate    bool       `protobuf:"varint,4,opt,name=deduplicate,proto3" json:"deduplicate,omitempty"`
+	IncludeVersion bool       `protobuf:"varint,5,opt,name=include_version,json=includeVersion,proto3" json:"include_version,omitempty"`             // Fill version and modified_at_ms
+	GeomTypes      []GeomType `protobuf:"varint,6,rep,packed,name=geom_types,json=geomTypes,proto3,enum=urbis.GeomType" json:"geom_types,omitempty"` // Geometry types to return (empty = all)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *QueryRangeMultiRequest) Reset() {
+	*x = QueryRangeMultiRequest{}
+	mi := &file_urbis_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryRangeMultiRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRangeMultiRequest) ProtoMessage() {}
+
+func (x *QueryRangeMultiRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRangeMultiRequest.ProtoReflect.Descriptor instead.
+func (*QueryRangeMultiRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *QueryRangeMultiRequest) GetIndexIds() []string {
+	if x != nil {
+		return x.IndexIds
+	}
+	return nil
+}
+
+func (x *QueryRangeMultiRequest) GetRange() *MBR {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *QueryRangeMultiRequest) GetStructure() IndexStructure {
+	if x != nil {
+		return x.Structure
+	}
+	return IndexStructure_INDEX_STRUCTURE_AUTO
+}
+
+func (x *QueryRangeMultiRequest) GetDeduplicate() bool {
+	if x != nil {
+		return x.Deduplicate
+	}
+	return false
+}
+
+func (x *QueryRangeMultiRequest) GetIncludeVersion() bool {
+	if x != nil {
+		return x.IncludeVersion
+	}
+	return false
+}
+
+func (x *QueryRangeMultiRequest) GetGeomTypes() []GeomType {
+	if x != nil {
+		return x.GeomTypes
+	}
+	return nil
+}
+
+type QueryRangeMultiResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Keyed by index ID; every index has an entry, possibly empty
+	Results       map[string]*RangeResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Count         uint64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"` // Objects across all results
+	QueryTimeMs   float64                 `protobuf:"fixed64,3,opt,name=query_time_ms,json=queryTimeMs,proto3" json:"query_time_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryRangeMultiResponse) Reset() {
+	*x = QueryRangeMultiResponse{}
+	mi := &file_urbis_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryRangeMultiResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRangeMultiResponse) ProtoMessage() {}
+
+func (x *QueryRangeMultiResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRangeMultiResponse.ProtoReflect.Descriptor instead.
+func (*QueryRangeMultiResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *QueryRangeMultiResponse) GetResults() map[string]*RangeResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *QueryRangeMultiResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *QueryRangeMultiResponse) GetQueryTimeMs() float64 {
+	if x != nil {
+		return x.QueryTimeMs
+	}
+	return 0
+}
+
+type PropertyQueryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IndexId        string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Key            string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`                                                             // Must be in the index config's indexed_properties
+	Value          string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`                                                         // Strings match as is; numbers and booleans as JSON text
+	IncludeVersion bool                   `protobuf:"varint,4,opt,name=include_version,json=includeVersion,proto3" json:"include_version,omitempty"`                // Fill version and modified_at_ms
+	Encoding       GeometryEncoding       `protobuf:"varint,5,opt,name=encoding,proto3,enum=urbis.GeometryEncoding" json:"encoding,omitempty"`                      // Geometry format of the results
+	FieldMask      []ObjectField          `protobuf:"varint,6,rep,packed,name=field_mask,json=fieldMask,proto3,enum=urbis.ObjectField" json:"field_mask,omitempty"` // Object fields to return (empty = all)
+	GeomTypes      []GeomType             `protobuf:"varint,7,rep,packed,name=geom_types,json=geomTypes,proto3,enum=urbis.GeomType" json:"geom_types,omitempty"`    // Geometry types to return (empty = all)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PropertyQueryRequest) Reset() {
+	*x = PropertyQueryRequest{}
+	mi := &file_urbis_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PropertyQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PropertyQueryRequest) ProtoMessage() {}
+
+func (x *PropertyQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PropertyQueryRequest.ProtoReflect.Descriptor instead.
+func (*PropertyQueryRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *PropertyQueryRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *PropertyQueryRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PropertyQueryRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *PropertyQueryRequest) GetIncludeVersion() bool {
+	if x != nil {
+		return x.IncludeVersion
+	}
+	return false
+}
+
+func (x *PropertyQueryRequest) GetEncoding() GeometryEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return GeometryEncoding_GEOMETRY_ENCODING_STRUCTURED
+}
+
+func (x *PropertyQueryRequest) GetFieldMask() []ObjectField {
+	if x != nil {
+		return x.FieldMask
+	}
+	return nil
+}
+
+func (x *PropertyQueryRequest) GetGeomTypes() []GeomType {
+	if x != nil {
+		return x.GeomTypes
+	}
+	return nil
+}
+
+type ConvexHullRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Region        *MBR                   `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvexHullRequest) Reset() {
+	*x = ConvexHullRequest{}
+	mi := &file_urbis_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvexHullRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvexHullRequest) ProtoMessage() {}
+
+func (x *ConvexHullRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvexHullRequest.ProtoReflect.Descriptor instead.
+func (*ConvexHullRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *ConvexHullRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *ConvexHullRequest) GetRegion() *MBR {
+	if x != nil {
+		return x.Region
+	}
+	return nil
+}
+
+type ConvexHullResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Closed counter-clockwise ring around every vertex of the objects in
+	// region. With fewer than three non-collinear vertices, the distinct
+	// vertices themselves (at most two).
+	Hull          []*Point `protobuf:"bytes,1,rep,name=hull,proto3" json:"hull,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvexHullResponse) Reset() {
+	*x = ConvexHullResponse{}
+	mi := &file_urbis_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvexHullResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvexHullResponse) ProtoMessage() {}
+
+func (x *ConvexHullResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvexHullResponse.ProtoReflect.Descriptor instead.
+func (*ConvexHullResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *ConvexHullResponse) GetHull() []*Point {
+	if x != nil {
+		return x.Hull
+	}
+	return nil
+}
+
+type PointQueryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IndexId        string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	X              float64                `protobuf:"fixed64,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y              float64                `protobuf:"fixed64,3,opt,name=y,proto3" json:"y,omitempty"`
+	Structure      IndexStructure         `protobuf:"varint,4,opt,name=structure,proto3,enum=urbis.IndexStructure" json:"structure,omitempty"`                      // Preferred structure
+	IncludeVersion bool                   `protobuf:"varint,5,opt,name=include_version,json=includeVersion,proto3" json:"include_version,omitempty"`                // Fill version and modified_at_ms
+	Encoding       GeometryEncoding       `protobuf:"varint,6,opt,name=encoding,proto3,enum=urbis.GeometryEncoding" json:"encoding,omitempty"`                      // Geometry format of the results
+	FieldMask      []ObjectField          `protobuf:"varint,7,rep,packed,name=field_mask,json=fieldMask,proto3,enum=urbis.ObjectField" json:"field_mask,omitempty"` // Object fields to return (empty = all)
+	GeomTypes      []GeomType             `protobuf:"varint,8,rep,packed,name=geom_types,json=geomTypes,proto3,enum=urbis.GeomType" json:"geom_types,omitempty"`    // Geometry types to return (empty = all)
+	QueryCrs       int32                  `protobuf:"varint,9,opt,name=query_crs,json=queryCrs,proto3" json:"query_crs,omitempty"`                                  // EPSG code of x, y and the returned geometries (0 = index CRS)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PointQueryRequest) Reset() {
+	*x = PointQueryRequest{}
+	mi := &file_urbis_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PointQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointQueryRequest) ProtoMessage() {}
+
+func (x *PointQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointQueryRequest.ProtoReflect.Descriptor instead.
+func (*PointQueryRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *PointQueryRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *PointQueryRequest) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *PointQueryRequest) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *PointQueryRequest) GetStructure() IndexStructure {
+	if x != nil {
+		return x.Structure
+	}
+	return IndexStructure_INDEX_STRUCTURE_AUTO
+}
+
+func (x *PointQueryRequest) GetIncludeVersion() bool {
+	if x != nil {
+		return x.IncludeVersion
+	}
+	return false
+}
+
+func (x *PointQueryRequest) GetEncoding() GeometryEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return GeometryEncoding_GEOMETRY_ENCODING_STRUCTURED
+}
+
+func (x *PointQueryRequest) GetFieldMask() []ObjectField {
+	if x != nil {
+		return x.FieldMask
+	}
+	return nil
+}
+
+func (x *PointQueryRequest) GetGeomTypes() []GeomType {
+	if x != nil {
+		return x.GeomTypes
+	}
+	return nil
+}
+
+func (x *PointQueryRequest) GetQueryCrs() int32 {
+	if x != nil {
+		return x.QueryCrs
+	}
+	return 0
+}
+
+type BufferQueryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IndexId        string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Geometry       *SpatialObject         `protobuf:"bytes,2,opt,name=geometry,proto3" json:"geometry,omitempty"`                                                   // Only the geometry is read; polygon holes are ignored
+	Distance       float64                `protobuf:"fixed64,3,opt,name=distance,proto3" json:"distance,omitempty"`                                                 // Buffer width in index coordinates (> 0)
+	IncludeVersion bool                   `protobuf:"varint,4,opt,name=include_version,json=includeVersion,proto3" json:"include_version,omitempty"`                // Fill version and modified_at_ms
+	Encoding       GeometryEncoding       `protobuf:"varint,5,opt,name=encoding,proto3,enum=urbis.GeometryEncoding" json:"encoding,omitempty"`                      // Geometry format of the results
+	FieldMask      []ObjectField          `protobuf:"varint,6,rep,packed,name=field_mask,json=fieldMask,proto3,enum=urbis.ObjectField" json:"field_mask,omitempty"` // Object fields to return (empty = all)
+	GeomTypes      []GeomType             `protobuf:"varint,7,rep,packed,name=geom_types,json=geomTypes,proto3,enum=urbis.GeomType" json:"geom_types,omitempty"`    // Geometry types to return (empty = all)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BufferQueryRequest) Reset() {
+	*x = BufferQueryRequest{}
+	mi := &file_urbis_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BufferQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BufferQueryRequest) ProtoMessage() {}
+
+func (x *BufferQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BufferQueryRequest.ProtoReflect.Descriptor instead.
+func (*BufferQueryRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *BufferQueryRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *BufferQueryRequest) GetGeometry() *SpatialObject {
+	if x != nil {
+		return x.Geometry
+	}
+	return nil
+}
+
+func (x *BufferQueryRequest) GetDistance() float64 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *BufferQueryRequest) GetIncludeVersion() bool {
+	if x != nil {
+		return x.IncludeVersion
+	}
+	return false
+}
+
+func (x *BufferQueryRequest) GetEncoding() GeometryEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return GeometryEncoding_GEOMETRY_ENCODING_STRUCTURED
+}
+
+func (x *BufferQueryRequest) GetFieldMask() []ObjectField {
+	if x != nil {
+		return x.FieldMask
+	}
+	return nil
+}
+
+func (x *BufferQueryRequest) GetGeomTypes() []GeomType {
+	if x != nil {
+		return x.GeomTypes
+	}
+	return nil
+}
+
+type ContainingPolygonQueryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IndexId        string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Polygon        []*Point               `protobuf:"bytes,2,rep,name=polygon,proto3" json:"polygon,omitempty"`                                                     // Ring to look up: 3+ distinct vertices, closed or not, either orientation
+	IncludeVersion bool                   `protobuf:"varint,3,opt,name=include_version,json=includeVersion,proto3" json:"include_version,omitempty"`                // Fill version and modified_at_ms
+	Encoding       GeometryEncoding       `protobuf:"varint,4,opt,name=encoding,proto3,enum=urbis.GeometryEncoding" json:"encoding,omitempty"`                      // Geometry format of the results
+	FieldMask      []ObjectField          `protobuf:"varint,5,rep,packed,name=field_mask,json=fieldMask,proto3,enum=urbis.ObjectField" json:"field_mask,omitempty"` // Object fields to return (empty = all)
+	GeomTypes      []GeomType             `protobuf:"varint,6,rep,packed,name=geom_types,json=geomTypes,proto3,enum=urbis.GeomType" json:"geom_types,omitempty"`    // Geometry types to return (empty = all)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ContainingPolygonQueryRequest) Reset() {
+	*x = ContainingPolygonQueryRequest{}
+	mi := &file_urbis_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainingPolygonQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainingPolygonQueryRequest) ProtoMessage() {}
+
+func (x *ContainingPolygonQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainingPolygonQueryRequest.ProtoReflect.Descriptor instead.
+func (*ContainingPolygonQueryRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *ContainingPolygonQueryRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *ContainingPolygonQueryRequest) GetPolygon() []*Point {
+	if x != nil {
+		return x.Polygon
+	}
+	return nil
+}
+
+func (x *ContainingPolygonQueryRequest) GetIncludeVersion() bool {
+	if x != nil {
+		return x.IncludeVersion
+	}
+	return false
+}
+
+func (x *ContainingPolygonQueryRequest) GetEncoding() GeometryEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return GeometryEncoding_GEOMETRY_ENCODING_STRUCTURED
+}
+
+func (x *ContainingPolygonQueryRequest) GetFieldMask() []ObjectField {
+	if x != nil {
+		return x.FieldMask
+	}
+	return nil
+}
+
+func (x *ContainingPolygonQueryRequest) GetGeomTypes() []GeomType {
+	if x != nil {
+		return x.GeomTypes
+	}
+	return nil
+}
+
+type KNNQueryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IndexId        string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	X              float64                `protobuf:"fixed64,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y              float64                `protobuf:"fixed64,3,opt,name=y,proto3" json:"y,omitempty"`
+	K              uint32                 `protobuf:"varint,4,opt,name=k,proto3" json:"k,omitempty"`
+	IncludeVersion bool                   `protobuf:"varint,5,opt,name=include_version,json=includeVersion,proto3" json:"include_version,omitempty"`                           // Fill version and modified_at_ms
+	Encoding       GeometryEncoding       `protobuf:"varint,6,opt,name=encoding,proto3,enum=urbis.GeometryEncoding" json:"encoding,omitempty"`                                 // Geometry format of the results
+	FieldMask      []ObjectField          `protobuf:"varint,7,rep,packed,name=field_mask,json=fieldMask,proto3,enum=urbis.ObjectField" json:"field_mask,omitempty"`            // Object fields to return (empty = all)
+	QueryCrs       int32                  `protobuf:"varint,8,opt,name=query_crs,json=queryCrs,proto3" json:"query_crs,omitempty"`                                             // EPSG code of x, y and the returned geometries (0 = index CRS)
+	DistanceMetric DistanceMetric         `protobuf:"varint,9,opt,name=distance_metric,json=distanceMetric,proto3,enum=urbis.DistanceMetric" json:"distance_metric,omitempty"` // Ranking and reported distances (default centroid)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *KNNQueryRequest) Reset() {
+	*x = KNNQueryRequest{}
+	mi := &file_urbis_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KNNQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KNNQueryRequest) ProtoMessage() {}
+
+func (x *KNNQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KNNQueryRequest.ProtoReflect.Descriptor instead.
+func (*KNNQueryRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *KNNQueryRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *KNNQueryRequest) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *KNNQueryRequest) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *KNNQueryRequest) GetK() uint32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+func (x *KNNQueryRequest) GetIncludeVersion() bool {
+	if x != nil {
+		return x.IncludeVersion
+	}
+	return false
+}
+
+func (x *KNNQueryRequest) GetEncoding() GeometryEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return GeometryEncoding_GEOMETRY_ENCODING_STRUCTURED
+}
+
+func (x *KNNQueryRequest) GetFieldMask() []ObjectField {
+	if x != nil {
+		return x.FieldMask
+	}
+	return nil
+}
+
+func (x *KNNQueryRequest) GetQueryCrs() int32 {
+	if x != nil {
+		return x.QueryCrs
+	}
+	return 0
+}
+
+func (x *KNNQueryRequest) GetDistanceMetric() DistanceMetric {
+	if x != nil {
+		return x.DistanceMetric
+	}
+	return DistanceMetric_DISTANCE_METRIC_CENTROID
+}
+
+type NearestRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IndexId        string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	X              float64                `protobuf:"fixed64,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y              float64                `protobuf:"fixed64,3,opt,name=y,proto3" json:"y,omitempty"`
+	IncludeVersion bool                   `protobuf:"varint,4,opt,name=include_version,json=includeVersion,proto3" json:"include_version,omitempty"` // Fill version and modified_at_ms
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *NearestRequest) Reset() {
+	*x = NearestRequest{}
+	mi := &file_urbis_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NearestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearestRequest) ProtoMessage() {}
+
+func (x *NearestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearestRequest.ProtoReflect.Descriptor instead.
+func (*NearestRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *NearestRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *NearestRequest) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *NearestRequest) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *NearestRequest) GetIncludeVersion() bool {
+	if x != nil {
+		return x.IncludeVersion
+	}
+	return false
+}
+
+type NearestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Object        *SpatialObject         `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	Distance      float64                `protobuf:"fixed64,2,opt,name=distance,proto3" json:"distance,omitempty"` // From the query point to the object's centroid, in index coordinates
+	QueryTimeMs   float64                `protobuf:"fixed64,3,opt,name=query_time_ms,json=queryTimeMs,proto3" json:"query_time_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NearestResponse) Reset() {
+	*x = NearestResponse{}
+	mi := &file_urbis_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NearestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearestResponse) ProtoMessage() {}
+
+func (x *NearestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearestResponse.ProtoReflect.Descriptor instead.
+func (*NearestResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *NearestResponse) GetObject() *SpatialObject {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *NearestResponse) GetDistance() float64 {
+	if x != nil {
+		return x.Distance
 	}
 	return 0
 }
 
-func (x *Stats) GetBounds() *MBR {
+func (x *NearestResponse) GetQueryTimeMs() float64 {
 	if x != nil {
-		return x.Bounds
+		return x.QueryTimeMs
 	}
-	return nil
+	return 0
 }
 
-// Page information for disk-aware queries
-type PageInfo struct {
+type ChangedSinceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PageId        uint32                 `protobuf:"varint,1,opt,name=page_id,json=pageId,proto3" json:"page_id,omitempty"`
-	TrackId       uint32                 `protobuf:"varint,2,opt,name=track_id,json=trackId,proto3" json:"track_id,omitempty"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	SinceMs       int64                  `protobuf:"varint,2,opt,name=since_ms,json=sinceMs,proto3" json:"since_ms,omitempty"`                                     // Unix milliseconds; objects modified at or after it are returned
+	Encoding      GeometryEncoding       `protobuf:"varint,3,opt,name=encoding,proto3,enum=urbis.GeometryEncoding" json:"encoding,omitempty"`                      // Geometry format of the results
+	FieldMask     []ObjectField          `protobuf:"varint,4,rep,packed,name=field_mask,json=fieldMask,proto3,enum=urbis.ObjectField" json:"field_mask,omitempty"` // Object fields to return (empty = all)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PageInfo) Reset() {
-	*x = PageInfo{}
-	mi := &file_urbis_proto_msgTypes[8]
+func (x *ChangedSinceRequest) Reset() {
+	*x = ChangedSinceRequest{}
+	mi := &file_urbis_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PageInfo) String() string {
+func (x *ChangedSinceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PageInfo) ProtoMessage() {}
+func (*ChangedSinceRequest) ProtoMessage() {}
 
-func (x *PageInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[8]
+func (x *ChangedSinceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -701,48 +6155,62 @@ func (x *PageInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PageInfo.ProtoReflect.Descriptor instead.
-func (*PageInfo) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ChangedSinceRequest.ProtoReflect.Descriptor instead.
+func (*ChangedSinceRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{81}
 }
 
-func (x *PageInfo) GetPageId() uint32 {
+func (x *ChangedSinceRequest) GetIndexId() string {
 	if x != nil {
-		return x.PageId
+		return x.IndexId
 	}
-	return 0
+	return ""
 }
 
-func (x *PageInfo) GetTrackId() uint32 {
+func (x *ChangedSinceRequest) GetSinceMs() int64 {
 	if x != nil {
-		return x.TrackId
+		return x.SinceMs
 	}
 	return 0
 }
 
-type CreateIndexRequest struct {
+func (x *ChangedSinceRequest) GetEncoding() GeometryEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return GeometryEncoding_GEOMETRY_ENCODING_STRUCTURED
+}
+
+func (x *ChangedSinceRequest) GetFieldMask() []ObjectField {
+	if x != nil {
+		return x.FieldMask
+	}
+	return nil
+}
+
+type SnapshotScanRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"` // Client-provided index identifier
-	Config        *Config                `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`                  // Optional configuration
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	BatchSize     uint32                 `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"` // Objects per response message (default: 1000)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateIndexRequest) Reset() {
-	*x = CreateIndexRequest{}
-	mi := &file_urbis_proto_msgTypes[9]
+func (x *SnapshotScanRequest) Reset() {
+	*x = SnapshotScanRequest{}
+	mi := &file_urbis_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateIndexRequest) String() string {
+func (x *SnapshotScanRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateIndexRequest) ProtoMessage() {}
+func (*SnapshotScanRequest) ProtoMessage() {}
 
-func (x *CreateIndexRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[9]
+func (x *SnapshotScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -753,48 +6221,50 @@ func (x *CreateIndexRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateIndexRequest.ProtoReflect.Descriptor instead.
-func (*CreateIndexRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use SnapshotScanRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotScanRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *CreateIndexRequest) GetIndexId() string {
+func (x *SnapshotScanRequest) GetIndexId() string {
 	if x != nil {
 		return x.IndexId
 	}
 	return ""
 }
 
-func (x *CreateIndexRequest) GetConfig() *Config {
+func (x *SnapshotScanRequest) GetBatchSize() uint32 {
 	if x != nil {
-		return x.Config
+		return x.BatchSize
 	}
-	return nil
+	return 0
 }
 
-type CreateIndexResponse struct {
+// One batch of a snapshot scan
+type SnapshotScanResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Objects       []*SpatialObject       `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`                                  // Next objects in ascending ID order, with change stamps
+	Total         uint64                 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                                     // Objects in the whole snapshot
+	SnapshotAtMs  int64                  `protobuf:"varint,3,opt,name=snapshot_at_ms,json=snapshotAtMs,proto3" json:"snapshot_at_ms,omitempty"` // When the snapshot was taken, Unix milliseconds
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateIndexResponse) Reset() {
-	*x = CreateIndexResponse{}
-	mi := &file_urbis_proto_msgTypes[10]
+func (x *SnapshotScanResponse) Reset() {
+	*x = SnapshotScanResponse{}
+	mi := &file_urbis_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateIndexResponse) String() string {
+func (x *SnapshotScanResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateIndexResponse) ProtoMessage() {}
+func (*SnapshotScanResponse) ProtoMessage() {}
 
-func (x *CreateIndexResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[10]
+func (x *SnapshotScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -805,47 +6275,62 @@ func (x *CreateIndexResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateIndexResponse.ProtoReflect.Descriptor instead.
-func (*CreateIndexResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use SnapshotScanResponse.ProtoReflect.Descriptor instead.
+func (*SnapshotScanResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{83}
 }
 
-func (x *CreateIndexResponse) GetIndexId() string {
+func (x *SnapshotScanResponse) GetObjects() []*SpatialObject {
 	if x != nil {
-		return x.IndexId
+		return x.Objects
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateIndexResponse) GetMessage() string {
+func (x *SnapshotScanResponse) GetTotal() uint64 {
 	if x != nil {
-		return x.Message
+		return x.Total
 	}
-	return ""
+	return 0
 }
 
-type DestroyIndexRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SnapshotScanResponse) GetSnapshotAtMs() int64 {
+	if x != nil {
+		return x.SnapshotAtMs
+	}
+	return 0
 }
 
-func (x *DestroyIndexRequest) Reset() {
-	*x = DestroyIndexRequest{}
-	mi := &file_urbis_proto_msgTypes[11]
+// Page and seek statistics for a single query
+type QueryStats struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	PagesVisited         uint64                 `protobuf:"varint,1,opt,name=pages_visited,json=pagesVisited,proto3" json:"pages_visited,omitempty"`                         // Distinct pages touched by the query
+	TracksVisited        uint64                 `protobuf:"varint,2,opt,name=tracks_visited,json=tracksVisited,proto3" json:"tracks_visited,omitempty"`                      // Distinct tracks touched by the query
+	EstimatedSeeks       uint64                 `protobuf:"varint,3,opt,name=estimated_seeks,json=estimatedSeeks,proto3" json:"estimated_seeks,omitempty"`                   // Track transitions across visited pages
+	CacheHits            uint64                 `protobuf:"varint,4,opt,name=cache_hits,json=cacheHits,proto3" json:"cache_hits,omitempty"`                                  // Visited pages already resident in memory
+	CacheMisses          uint64                 `protobuf:"varint,5,opt,name=cache_misses,json=cacheMisses,proto3" json:"cache_misses,omitempty"`                            // Visited pages that had to be read from disk
+	Structure            IndexStructure         `protobuf:"varint,6,opt,name=structure,proto3,enum=urbis.IndexStructure" json:"structure,omitempty"`                         // Structure that answered the query
+	StructureFallback    bool                   `protobuf:"varint,7,opt,name=structure_fallback,json=structureFallback,proto3" json:"structure_fallback,omitempty"`          // Requested structure was not built, another was used
+	DuplicatesSuppressed uint64                 `protobuf:"varint,8,opt,name=duplicates_suppressed,json=duplicatesSuppressed,proto3" json:"duplicates_suppressed,omitempty"` // Repeat finds of objects already in the result, left out
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *QueryStats) Reset() {
+	*x = QueryStats{}
+	mi := &file_urbis_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DestroyIndexRequest) String() string {
+func (x *QueryStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DestroyIndexRequest) ProtoMessage() {}
+func (*QueryStats) ProtoMessage() {}
 
-func (x *DestroyIndexRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[11]
+func (x *QueryStats) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -856,40 +6341,99 @@ func (x *DestroyIndexRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DestroyIndexRequest.ProtoReflect.Descriptor instead.
-func (*DestroyIndexRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use QueryStats.ProtoReflect.Descriptor instead.
+func (*QueryStats) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{84}
 }
 
-func (x *DestroyIndexRequest) GetIndexId() string {
+func (x *QueryStats) GetPagesVisited() uint64 {
 	if x != nil {
-		return x.IndexId
+		return x.PagesVisited
 	}
-	return ""
+	return 0
 }
 
-type DestroyIndexResponse struct {
+func (x *QueryStats) GetTracksVisited() uint64 {
+	if x != nil {
+		return x.TracksVisited
+	}
+	return 0
+}
+
+func (x *QueryStats) GetEstimatedSeeks() uint64 {
+	if x != nil {
+		return x.EstimatedSeeks
+	}
+	return 0
+}
+
+func (x *QueryStats) GetCacheHits() uint64 {
+	if x != nil {
+		return x.CacheHits
+	}
+	return 0
+}
+
+func (x *QueryStats) GetCacheMisses() uint64 {
+	if x != nil {
+		return x.CacheMisses
+	}
+	return 0
+}
+
+func (x *QueryStats) GetStructure() IndexStructure {
+	if x != nil {
+		return x.Structure
+	}
+	return IndexStructure_INDEX_STRUCTURE_AUTO
+}
+
+func (x *QueryStats) GetStructureFallback() bool {
+	if x != nil {
+		return x.StructureFallback
+	}
+	return false
+}
+
+func (x *QueryStats) GetDuplicatesSuppressed() uint64 {
+	if x != nil {
+		return x.DuplicatesSuppressed
+	}
+	return 0
+}
+
+type QueryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Objects       []*SpatialObject       `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
+	Count         uint64                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	QueryTimeMs   float64                `protobuf:"fixed64,3,opt,name=query_time_ms,json=queryTimeMs,proto3" json:"query_time_ms,omitempty"`
+	QueryStats    *QueryStats            `protobuf:"bytes,4,opt,name=query_stats,json=queryStats,proto3" json:"query_stats,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,5,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`        // Set when a paginated query has more results
+	Warnings      []string               `protobuf:"bytes,6,rep,name=warnings,proto3" json:"warnings,omitempty"`                              // Pages skipped by a best_effort query
+	Geojson       string                 `protobuf:"bytes,7,opt,name=geojson,proto3" json:"geojson,omitempty"`                                // FeatureCollection of the results (GEOMETRY_ENCODING_GEOJSON)
+	Distances     []float64              `protobuf:"fixed64,8,rep,packed,name=distances,proto3" json:"distances,omitempty"`                   // QueryKNN: distance to each object, in order, in index coordinates
+	Stale         bool                   `protobuf:"varint,9,opt,name=stale,proto3" json:"stale,omitempty"`                                   // The index changed since its last build; answered by a page scan
+	Pages         []*PageInfo            `protobuf:"bytes,10,rep,name=pages,proto3" json:"pages,omitempty"`                                   // QueryRange with include_pages: pages touched, in visit order
+	ResultBounds  *MBR                   `protobuf:"bytes,11,opt,name=result_bounds,json=resultBounds,proto3" json:"result_bounds,omitempty"` // Union of the returned objects' MBRs, in the query CRS; unset when none are returned
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DestroyIndexResponse) Reset() {
-	*x = DestroyIndexResponse{}
-	mi := &file_urbis_proto_msgTypes[12]
+func (x *QueryResponse) Reset() {
+	*x = QueryResponse{}
+	mi := &file_urbis_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DestroyIndexResponse) String() string {
+func (x *QueryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DestroyIndexResponse) ProtoMessage() {}
+func (*QueryResponse) ProtoMessage() {}
 
-func (x *DestroyIndexResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[12]
+func (x *QueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -900,39 +6444,111 @@ func (x *DestroyIndexResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DestroyIndexResponse.ProtoReflect.Descriptor instead.
-func (*DestroyIndexResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
+func (*QueryResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{85}
 }
 
-func (x *DestroyIndexResponse) GetMessage() string {
+func (x *QueryResponse) GetObjects() []*SpatialObject {
 	if x != nil {
-		return x.Message
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *QueryResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *QueryResponse) GetQueryTimeMs() float64 {
+	if x != nil {
+		return x.QueryTimeMs
+	}
+	return 0
+}
+
+func (x *QueryResponse) GetQueryStats() *QueryStats {
+	if x != nil {
+		return x.QueryStats
+	}
+	return nil
+}
+
+func (x *QueryResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
 	}
 	return ""
 }
 
-type ListIndexesRequest struct {
+func (x *QueryResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *QueryResponse) GetGeojson() string {
+	if x != nil {
+		return x.Geojson
+	}
+	return ""
+}
+
+func (x *QueryResponse) GetDistances() []float64 {
+	if x != nil {
+		return x.Distances
+	}
+	return nil
+}
+
+func (x *QueryResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *QueryResponse) GetPages() []*PageInfo {
+	if x != nil {
+		return x.Pages
+	}
+	return nil
+}
+
+func (x *QueryResponse) GetResultBounds() *MBR {
+	if x != nil {
+		return x.ResultBounds
+	}
+	return nil
+}
+
+type AdjacentPagesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Region        *MBR                   `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListIndexesRequest) Reset() {
-	*x = ListIndexesRequest{}
-	mi := &file_urbis_proto_msgTypes[13]
+func (x *AdjacentPagesRequest) Reset() {
+	*x = AdjacentPagesRequest{}
+	mi := &file_urbis_proto_msgTypes[86]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListIndexesRequest) String() string {
+func (x *AdjacentPagesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListIndexesRequest) ProtoMessage() {}
+func (*AdjacentPagesRequest) ProtoMessage() {}
 
-func (x *ListIndexesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[13]
+func (x *AdjacentPagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[86]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -943,33 +6559,50 @@ func (x *ListIndexesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListIndexesRequest.ProtoReflect.Descriptor instead.
-func (*ListIndexesRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use AdjacentPagesRequest.ProtoReflect.Descriptor instead.
+func (*AdjacentPagesRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{86}
 }
 
-type ListIndexesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexIds      []string               `protobuf:"bytes,1,rep,name=index_ids,json=indexIds,proto3" json:"index_ids,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *AdjacentPagesRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *AdjacentPagesRequest) GetRegion() *MBR {
+	if x != nil {
+		return x.Region
+	}
+	return nil
+}
+
+type AdjacentPagesResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Pages           []*PageInfo            `protobuf:"bytes,1,rep,name=pages,proto3" json:"pages,omitempty"`
+	Count           uint64                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	EstimatedSeeks  uint64                 `protobuf:"varint,3,opt,name=estimated_seeks,json=estimatedSeeks,proto3" json:"estimated_seeks,omitempty"`
+	EstimatedCostMs float64                `protobuf:"fixed64,4,opt,name=estimated_cost_ms,json=estimatedCostMs,proto3" json:"estimated_cost_ms,omitempty"` // Read time of the pages under the index's seek_cost
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *ListIndexesResponse) Reset() {
-	*x = ListIndexesResponse{}
-	mi := &file_urbis_proto_msgTypes[14]
+func (x *AdjacentPagesResponse) Reset() {
+	*x = AdjacentPagesResponse{}
+	mi := &file_urbis_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListIndexesResponse) String() string {
+func (x *AdjacentPagesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListIndexesResponse) ProtoMessage() {}
+func (*AdjacentPagesResponse) ProtoMessage() {}
 
-func (x *ListIndexesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[14]
+func (x *AdjacentPagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -980,41 +6613,63 @@ func (x *ListIndexesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListIndexesResponse.ProtoReflect.Descriptor instead.
-func (*ListIndexesResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use AdjacentPagesResponse.ProtoReflect.Descriptor instead.
+func (*AdjacentPagesResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{87}
 }
 
-func (x *ListIndexesResponse) GetIndexIds() []string {
+func (x *AdjacentPagesResponse) GetPages() []*PageInfo {
 	if x != nil {
-		return x.IndexIds
+		return x.Pages
 	}
 	return nil
 }
 
-type LoadGeoJSONRequest struct {
+func (x *AdjacentPagesResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *AdjacentPagesResponse) GetEstimatedSeeks() uint64 {
+	if x != nil {
+		return x.EstimatedSeeks
+	}
+	return 0
+}
+
+func (x *AdjacentPagesResponse) GetEstimatedCostMs() float64 {
+	if x != nil {
+		return x.EstimatedCostMs
+	}
+	return 0
+}
+
+type PageGraphRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"` // File path to GeoJSON
+	Limit         uint32                 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`  // Max pages per response, by page ID (0 = all)
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor from the previous response
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LoadGeoJSONRequest) Reset() {
-	*x = LoadGeoJSONRequest{}
-	mi := &file_urbis_proto_msgTypes[15]
+func (x *PageGraphRequest) Reset() {
+	*x = PageGraphRequest{}
+	mi := &file_urbis_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoadGeoJSONRequest) String() string {
+func (x *PageGraphRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoadGeoJSONRequest) ProtoMessage() {}
+func (*PageGraphRequest) ProtoMessage() {}
 
-func (x *LoadGeoJSONRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[15]
+func (x *PageGraphRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1025,48 +6680,57 @@ func (x *LoadGeoJSONRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoadGeoJSONRequest.ProtoReflect.Descriptor instead.
-func (*LoadGeoJSONRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use PageGraphRequest.ProtoReflect.Descriptor instead.
+func (*PageGraphRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{88}
 }
 
-func (x *LoadGeoJSONRequest) GetIndexId() string {
+func (x *PageGraphRequest) GetIndexId() string {
 	if x != nil {
 		return x.IndexId
 	}
 	return ""
 }
 
-func (x *LoadGeoJSONRequest) GetPath() string {
+func (x *PageGraphRequest) GetLimit() uint32 {
 	if x != nil {
-		return x.Path
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *PageGraphRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
 	}
 	return ""
 }
 
-type LoadGeoJSONStringRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	Geojson       string                 `protobuf:"bytes,2,opt,name=geojson,proto3" json:"geojson,omitempty"` // GeoJSON content as string
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Pages whose extents touch or overlap
+type PageEdge struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	FromPageId     uint32                 `protobuf:"varint,1,opt,name=from_page_id,json=fromPageId,proto3" json:"from_page_id,omitempty"` // The lower page ID
+	ToPageId       uint32                 `protobuf:"varint,2,opt,name=to_page_id,json=toPageId,proto3" json:"to_page_id,omitempty"`
+	EstimatedSeeks uint64                 `protobuf:"varint,3,opt,name=estimated_seeks,json=estimatedSeeks,proto3" json:"estimated_seeks,omitempty"` // 0 on the same track, 1 otherwise
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *LoadGeoJSONStringRequest) Reset() {
-	*x = LoadGeoJSONStringRequest{}
-	mi := &file_urbis_proto_msgTypes[16]
+func (x *PageEdge) Reset() {
+	*x = PageEdge{}
+	mi := &file_urbis_proto_msgTypes[89]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoadGeoJSONStringRequest) String() string {
+func (x *PageEdge) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoadGeoJSONStringRequest) ProtoMessage() {}
+func (*PageEdge) ProtoMessage() {}
 
-func (x *LoadGeoJSONStringRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[16]
+func (x *PageEdge) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[89]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1077,48 +6741,59 @@ func (x *LoadGeoJSONStringRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoadGeoJSONStringRequest.ProtoReflect.Descriptor instead.
-func (*LoadGeoJSONStringRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use PageEdge.ProtoReflect.Descriptor instead.
+func (*PageEdge) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{89}
 }
 
-func (x *LoadGeoJSONStringRequest) GetIndexId() string {
+func (x *PageEdge) GetFromPageId() uint32 {
 	if x != nil {
-		return x.IndexId
+		return x.FromPageId
 	}
-	return ""
+	return 0
 }
 
-func (x *LoadGeoJSONStringRequest) GetGeojson() string {
+func (x *PageEdge) GetToPageId() uint32 {
 	if x != nil {
-		return x.Geojson
+		return x.ToPageId
 	}
-	return ""
+	return 0
 }
 
-type LoadWKTRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	Wkt           string                 `protobuf:"bytes,2,opt,name=wkt,proto3" json:"wkt,omitempty"` // WKT geometry string
+func (x *PageEdge) GetEstimatedSeeks() uint64 {
+	if x != nil {
+		return x.EstimatedSeeks
+	}
+	return 0
+}
+
+type PageGraphResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Nodes []*PageInfo            `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	// Edges whose from_page_id is in nodes; to_page_id may be on another page
+	Edges         []*PageEdge `protobuf:"bytes,2,rep,name=edges,proto3" json:"edges,omitempty"`
+	NextCursor    string      `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // Set when more pages follow
+	TotalNodes    uint64      `protobuf:"varint,4,opt,name=total_nodes,json=totalNodes,proto3" json:"total_nodes,omitempty"`
+	TotalEdges    uint64      `protobuf:"varint,5,opt,name=total_edges,json=totalEdges,proto3" json:"total_edges,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LoadWKTRequest) Reset() {
-	*x = LoadWKTRequest{}
-	mi := &file_urbis_proto_msgTypes[17]
+func (x *PageGraphResponse) Reset() {
+	*x = PageGraphResponse{}
+	mi := &file_urbis_proto_msgTypes[90]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoadWKTRequest) String() string {
+func (x *PageGraphResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoadWKTRequest) ProtoMessage() {}
+func (*PageGraphResponse) ProtoMessage() {}
 
-func (x *LoadWKTRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[17]
+func (x *PageGraphResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[90]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1129,48 +6804,70 @@ func (x *LoadWKTRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoadWKTRequest.ProtoReflect.Descriptor instead.
-func (*LoadWKTRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use PageGraphResponse.ProtoReflect.Descriptor instead.
+func (*PageGraphResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{90}
 }
 
-func (x *LoadWKTRequest) GetIndexId() string {
+func (x *PageGraphResponse) GetNodes() []*PageInfo {
 	if x != nil {
-		return x.IndexId
+		return x.Nodes
 	}
-	return ""
+	return nil
 }
 
-func (x *LoadWKTRequest) GetWkt() string {
+func (x *PageGraphResponse) GetEdges() []*PageEdge {
 	if x != nil {
-		return x.Wkt
+		return x.Edges
+	}
+	return nil
+}
+
+func (x *PageGraphResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
 	}
 	return ""
 }
 
-type LoadResponse struct {
+func (x *PageGraphResponse) GetTotalNodes() uint64 {
+	if x != nil {
+		return x.TotalNodes
+	}
+	return 0
+}
+
+func (x *PageGraphResponse) GetTotalEdges() uint64 {
+	if x != nil {
+		return x.TotalEdges
+	}
+	return 0
+}
+
+type TreeStructureRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ObjectsLoaded uint64                 `protobuf:"varint,1,opt,name=objects_loaded,json=objectsLoaded,proto3" json:"objects_loaded,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Structure     IndexStructure         `protobuf:"varint,2,opt,name=structure,proto3,enum=urbis.IndexStructure" json:"structure,omitempty"` // INDEX_STRUCTURE_KDTREE or INDEX_STRUCTURE_QUADTREE
+	MaxDepth      uint32                 `protobuf:"varint,3,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`             // Levels to return, from the root (0 = all)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LoadResponse) Reset() {
-	*x = LoadResponse{}
-	mi := &file_urbis_proto_msgTypes[18]
+func (x *TreeStructureRequest) Reset() {
+	*x = TreeStructureRequest{}
+	mi := &file_urbis_proto_msgTypes[91]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoadResponse) String() string {
+func (x *TreeStructureRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoadResponse) ProtoMessage() {}
+func (*TreeStructureRequest) ProtoMessage() {}
 
-func (x *LoadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[18]
+func (x *TreeStructureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[91]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1181,49 +6878,62 @@ func (x *LoadResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoadResponse.ProtoReflect.Descriptor instead.
-func (*LoadResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use TreeStructureRequest.ProtoReflect.Descriptor instead.
+func (*TreeStructureRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{91}
 }
 
-func (x *LoadResponse) GetObjectsLoaded() uint64 {
+func (x *TreeStructureRequest) GetIndexId() string {
 	if x != nil {
-		return x.ObjectsLoaded
+		return x.IndexId
 	}
-	return 0
+	return ""
 }
 
-func (x *LoadResponse) GetMessage() string {
+func (x *TreeStructureRequest) GetStructure() IndexStructure {
 	if x != nil {
-		return x.Message
+		return x.Structure
 	}
-	return ""
+	return IndexStructure_INDEX_STRUCTURE_AUTO
 }
 
-type InsertPointRequest struct {
+func (x *TreeStructureRequest) GetMaxDepth() uint32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+// A KD-tree node (one object's centroid splitting the plane) or a quadtree cell
+type TreeNode struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	X             float64                `protobuf:"fixed64,2,opt,name=x,proto3" json:"x,omitempty"`
-	Y             float64                `protobuf:"fixed64,3,opt,name=y,proto3" json:"y,omitempty"`
+	Depth         uint32                 `protobuf:"varint,1,opt,name=depth,proto3" json:"depth,omitempty"`                                // 0 for the root
+	Parent        int32                  `protobuf:"varint,2,opt,name=parent,proto3" json:"parent,omitempty"`                              // Index of the parent in nodes, -1 for the root
+	Bounds        *MBR                   `protobuf:"bytes,3,opt,name=bounds,proto3" json:"bounds,omitempty"`                               // KD-tree: extent of the subtree's centroids; quadtree: the cell
+	ObjectCount   uint64                 `protobuf:"varint,4,opt,name=object_count,json=objectCount,proto3" json:"object_count,omitempty"` // Objects in the subtree
+	ItemCount     uint64                 `protobuf:"varint,5,opt,name=item_count,json=itemCount,proto3" json:"item_count,omitempty"`       // Objects (KD-tree) or pages (quadtree) in the subtree
+	SplitAxis     int32                  `protobuf:"varint,6,opt,name=split_axis,json=splitAxis,proto3" json:"split_axis,omitempty"`       // 0 = x, 1 = y; -1 for KD-tree leaves and quadtree cells
+	SplitValue    float64                `protobuf:"fixed64,7,opt,name=split_value,json=splitValue,proto3" json:"split_value,omitempty"`   // Coordinate of the split line
+	Leaf          bool                   `protobuf:"varint,8,opt,name=leaf,proto3" json:"leaf,omitempty"`                                  // No children (unlike nodes cut off by max_depth)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *InsertPointRequest) Reset() {
-	*x = InsertPointRequest{}
-	mi := &file_urbis_proto_msgTypes[19]
+func (x *TreeNode) Reset() {
+	*x = TreeNode{}
+	mi := &file_urbis_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InsertPointRequest) String() string {
+func (x *TreeNode) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InsertPointRequest) ProtoMessage() {}
+func (*TreeNode) ProtoMessage() {}
 
-func (x *InsertPointRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[19]
+func (x *TreeNode) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1234,55 +6944,89 @@ func (x *InsertPointRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InsertPointRequest.ProtoReflect.Descriptor instead.
-func (*InsertPointRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use TreeNode.ProtoReflect.Descriptor instead.
+func (*TreeNode) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{92}
 }
 
-func (x *InsertPointRequest) GetIndexId() string {
+func (x *TreeNode) GetDepth() uint32 {
 	if x != nil {
-		return x.IndexId
+		return x.Depth
 	}
-	return ""
+	return 0
 }
 
-func (x *InsertPointRequest) GetX() float64 {
+func (x *TreeNode) GetParent() int32 {
 	if x != nil {
-		return x.X
+		return x.Parent
 	}
 	return 0
 }
 
-func (x *InsertPointRequest) GetY() float64 {
+func (x *TreeNode) GetBounds() *MBR {
 	if x != nil {
-		return x.Y
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *TreeNode) GetObjectCount() uint64 {
+	if x != nil {
+		return x.ObjectCount
 	}
 	return 0
 }
 
-type InsertLineStringRequest struct {
+func (x *TreeNode) GetItemCount() uint64 {
+	if x != nil {
+		return x.ItemCount
+	}
+	return 0
+}
+
+func (x *TreeNode) GetSplitAxis() int32 {
+	if x != nil {
+		return x.SplitAxis
+	}
+	return 0
+}
+
+func (x *TreeNode) GetSplitValue() float64 {
+	if x != nil {
+		return x.SplitValue
+	}
+	return 0
+}
+
+func (x *TreeNode) GetLeaf() bool {
+	if x != nil {
+		return x.Leaf
+	}
+	return false
+}
+
+type TreeStructureResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	Points        []*Point               `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"`
+	Nodes         []*TreeNode            `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"` // Depth-first, each parent before its children
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *InsertLineStringRequest) Reset() {
-	*x = InsertLineStringRequest{}
-	mi := &file_urbis_proto_msgTypes[20]
+func (x *TreeStructureResponse) Reset() {
+	*x = TreeStructureResponse{}
+	mi := &file_urbis_proto_msgTypes[93]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InsertLineStringRequest) String() string {
+func (x *TreeStructureResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InsertLineStringRequest) ProtoMessage() {}
+func (*TreeStructureResponse) ProtoMessage() {}
 
-func (x *InsertLineStringRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[20]
+func (x *TreeStructureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[93]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1293,48 +7037,41 @@ func (x *InsertLineStringRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InsertLineStringRequest.ProtoReflect.Descriptor instead.
-func (*InsertLineStringRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{20}
-}
-
-func (x *InsertLineStringRequest) GetIndexId() string {
-	if x != nil {
-		return x.IndexId
-	}
-	return ""
+// Deprecated: Use TreeStructureResponse.ProtoReflect.Descriptor instead.
+func (*TreeStructureResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{93}
 }
 
-func (x *InsertLineStringRequest) GetPoints() []*Point {
+func (x *TreeStructureResponse) GetNodes() []*TreeNode {
 	if x != nil {
-		return x.Points
+		return x.Nodes
 	}
 	return nil
 }
 
-type InsertPolygonRequest struct {
+type PrefetchRegionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	Exterior      []*Point               `protobuf:"bytes,2,rep,name=exterior,proto3" json:"exterior,omitempty"`
+	Region        *MBR                   `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *InsertPolygonRequest) Reset() {
-	*x = InsertPolygonRequest{}
-	mi := &file_urbis_proto_msgTypes[21]
+func (x *PrefetchRegionRequest) Reset() {
+	*x = PrefetchRegionRequest{}
+	mi := &file_urbis_proto_msgTypes[94]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InsertPolygonRequest) String() string {
+func (x *PrefetchRegionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InsertPolygonRequest) ProtoMessage() {}
+func (*PrefetchRegionRequest) ProtoMessage() {}
 
-func (x *InsertPolygonRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[21]
+func (x *PrefetchRegionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1345,47 +7082,47 @@ func (x *InsertPolygonRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InsertPolygonRequest.ProtoReflect.Descriptor instead.
-func (*InsertPolygonRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use PrefetchRegionRequest.ProtoReflect.Descriptor instead.
+func (*PrefetchRegionRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{94}
 }
 
-func (x *InsertPolygonRequest) GetIndexId() string {
+func (x *PrefetchRegionRequest) GetIndexId() string {
 	if x != nil {
 		return x.IndexId
 	}
 	return ""
 }
 
-func (x *InsertPolygonRequest) GetExterior() []*Point {
+func (x *PrefetchRegionRequest) GetRegion() *MBR {
 	if x != nil {
-		return x.Exterior
+		return x.Region
 	}
 	return nil
 }
 
-type InsertResponse struct {
+type PrefetchRegionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ObjectId      uint64                 `protobuf:"varint,1,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *InsertResponse) Reset() {
-	*x = InsertResponse{}
-	mi := &file_urbis_proto_msgTypes[22]
+func (x *PrefetchRegionResponse) Reset() {
+	*x = PrefetchRegionResponse{}
+	mi := &file_urbis_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InsertResponse) String() string {
+func (x *PrefetchRegionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InsertResponse) ProtoMessage() {}
+func (*PrefetchRegionResponse) ProtoMessage() {}
 
-func (x *InsertResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[22]
+func (x *PrefetchRegionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1396,41 +7133,40 @@ func (x *InsertResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InsertResponse.ProtoReflect.Descriptor instead.
-func (*InsertResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{22}
-}
-
-func (x *InsertResponse) GetObjectId() uint64 {
+// Deprecated: Use PrefetchRegionResponse.ProtoReflect.Descriptor instead.
+func (*PrefetchRegionResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *PrefetchRegionResponse) GetMessage() string {
 	if x != nil {
-		return x.ObjectId
+		return x.Message
 	}
-	return 0
+	return ""
 }
 
-type RemoveRequest struct {
+type IndexReadyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	ObjectId      uint64                 `protobuf:"varint,2,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemoveRequest) Reset() {
-	*x = RemoveRequest{}
-	mi := &file_urbis_proto_msgTypes[23]
+func (x *IndexReadyRequest) Reset() {
+	*x = IndexReadyRequest{}
+	mi := &file_urbis_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemoveRequest) String() string {
+func (x *IndexReadyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveRequest) ProtoMessage() {}
+func (*IndexReadyRequest) ProtoMessage() {}
 
-func (x *RemoveRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[23]
+func (x *IndexReadyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1441,47 +7177,41 @@ func (x *RemoveRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveRequest.ProtoReflect.Descriptor instead.
-func (*RemoveRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use IndexReadyRequest.ProtoReflect.Descriptor instead.
+func (*IndexReadyRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{96}
 }
 
-func (x *RemoveRequest) GetIndexId() string {
+func (x *IndexReadyRequest) GetIndexId() string {
 	if x != nil {
 		return x.IndexId
 	}
 	return ""
 }
 
-func (x *RemoveRequest) GetObjectId() uint64 {
-	if x != nil {
-		return x.ObjectId
-	}
-	return 0
-}
-
-type RemoveResponse struct {
+type IndexReadyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Exists        bool                   `protobuf:"varint,1,opt,name=exists,proto3" json:"exists,omitempty"`
+	Built         bool                   `protobuf:"varint,2,opt,name=built,proto3" json:"built,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RemoveResponse) Reset() {
-	*x = RemoveResponse{}
-	mi := &file_urbis_proto_msgTypes[24]
+func (x *IndexReadyResponse) Reset() {
+	*x = IndexReadyResponse{}
+	mi := &file_urbis_proto_msgTypes[97]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RemoveResponse) String() string {
+func (x *IndexReadyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveResponse) ProtoMessage() {}
+func (*IndexReadyResponse) ProtoMessage() {}
 
-func (x *RemoveResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[24]
+func (x *IndexReadyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[97]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1492,41 +7222,46 @@ func (x *RemoveResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveResponse.ProtoReflect.Descriptor instead.
-func (*RemoveResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use IndexReadyResponse.ProtoReflect.Descriptor instead.
+func (*IndexReadyResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{97}
 }
 
-func (x *RemoveResponse) GetSuccess() bool {
+func (x *IndexReadyResponse) GetExists() bool {
 	if x != nil {
-		return x.Success
+		return x.Exists
 	}
 	return false
 }
 
-type GetObjectRequest struct {
+func (x *IndexReadyResponse) GetBuilt() bool {
+	if x != nil {
+		return x.Built
+	}
+	return false
+}
+
+type ServerInfoRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	ObjectId      uint64                 `protobuf:"varint,2,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetObjectRequest) Reset() {
-	*x = GetObjectRequest{}
-	mi := &file_urbis_proto_msgTypes[25]
+func (x *ServerInfoRequest) Reset() {
+	*x = ServerInfoRequest{}
+	mi := &file_urbis_proto_msgTypes[98]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetObjectRequest) String() string {
+func (x *ServerInfoRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetObjectRequest) ProtoMessage() {}
+func (*ServerInfoRequest) ProtoMessage() {}
 
-func (x *GetObjectRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[25]
+func (x *ServerInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[98]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1537,48 +7272,38 @@ func (x *GetObjectRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetObjectRequest.ProtoReflect.Descriptor instead.
-func (*GetObjectRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{25}
-}
-
-func (x *GetObjectRequest) GetIndexId() string {
-	if x != nil {
-		return x.IndexId
-	}
-	return ""
-}
-
-func (x *GetObjectRequest) GetObjectId() uint64 {
-	if x != nil {
-		return x.ObjectId
-	}
-	return 0
+// Deprecated: Use ServerInfoRequest.ProtoReflect.Descriptor instead.
+func (*ServerInfoRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{98}
 }
 
-type GetObjectResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Object        *SpatialObject         `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
-	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type ServerInfoResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	LibraryVersion    string                 `protobuf:"bytes,1,opt,name=library_version,json=libraryVersion,proto3" json:"library_version,omitempty"`             // Version of the C urbis library
+	ServerVersion     string                 `protobuf:"bytes,2,opt,name=server_version,json=serverVersion,proto3" json:"server_version,omitempty"`                // Build version of the server, "dev" if unset
+	UptimeMs          uint64                 `protobuf:"varint,3,opt,name=uptime_ms,json=uptimeMs,proto3" json:"uptime_ms,omitempty"`                              // Time since the server started
+	IndexCount        uint64                 `protobuf:"varint,4,opt,name=index_count,json=indexCount,proto3" json:"index_count,omitempty"`                        // Indexes currently loaded
+	ReflectionEnabled bool                   `protobuf:"varint,5,opt,name=reflection_enabled,json=reflectionEnabled,proto3" json:"reflection_enabled,omitempty"`   // Whether gRPC reflection is served
+	FileFormatVersion uint32                 `protobuf:"varint,6,opt,name=file_format_version,json=fileFormatVersion,proto3" json:"file_format_version,omitempty"` // Save format written; older ones also load
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
-func (x *GetObjectResponse) Reset() {
-	*x = GetObjectResponse{}
-	mi := &file_urbis_proto_msgTypes[26]
+func (x *ServerInfoResponse) Reset() {
+	*x = ServerInfoResponse{}
+	mi := &file_urbis_proto_msgTypes[99]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetObjectResponse) String() string {
+func (x *ServerInfoResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetObjectResponse) ProtoMessage() {}
+func (*ServerInfoResponse) ProtoMessage() {}
 
-func (x *GetObjectResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[26]
+func (x *ServerInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[99]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1589,47 +7314,75 @@ func (x *GetObjectResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetObjectResponse.ProtoReflect.Descriptor instead.
-func (*GetObjectResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use ServerInfoResponse.ProtoReflect.Descriptor instead.
+func (*ServerInfoResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{99}
 }
 
-func (x *GetObjectResponse) GetObject() *SpatialObject {
+func (x *ServerInfoResponse) GetLibraryVersion() string {
 	if x != nil {
-		return x.Object
+		return x.LibraryVersion
 	}
-	return nil
+	return ""
 }
 
-func (x *GetObjectResponse) GetFound() bool {
+func (x *ServerInfoResponse) GetServerVersion() string {
 	if x != nil {
-		return x.Found
+		return x.ServerVersion
+	}
+	return ""
+}
+
+func (x *ServerInfoResponse) GetUptimeMs() uint64 {
+	if x != nil {
+		return x.UptimeMs
+	}
+	return 0
+}
+
+func (x *ServerInfoResponse) GetIndexCount() uint64 {
+	if x != nil {
+		return x.IndexCount
+	}
+	return 0
+}
+
+func (x *ServerInfoResponse) GetReflectionEnabled() bool {
+	if x != nil {
+		return x.ReflectionEnabled
 	}
 	return false
 }
 
-type BuildRequest struct {
+func (x *ServerInfoResponse) GetFileFormatVersion() uint32 {
+	if x != nil {
+		return x.FileFormatVersion
+	}
+	return 0
+}
+
+type StatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BuildRequest) Reset() {
-	*x = BuildRequest{}
-	mi := &file_urbis_proto_msgTypes[27]
+func (x *StatsRequest) Reset() {
+	*x = StatsRequest{}
+	mi := &file_urbis_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BuildRequest) String() string {
+func (x *StatsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BuildRequest) ProtoMessage() {}
+func (*StatsRequest) ProtoMessage() {}
 
-func (x *BuildRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[27]
+func (x *StatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1640,41 +7393,40 @@ func (x *BuildRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BuildRequest.ProtoReflect.Descriptor instead.
-func (*BuildRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use StatsRequest.ProtoReflect.Descriptor instead.
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{100}
 }
 
-func (x *BuildRequest) GetIndexId() string {
+func (x *StatsRequest) GetIndexId() string {
 	if x != nil {
 		return x.IndexId
 	}
 	return ""
 }
 
-type BuildResponse struct {
+type StatsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	BuildTimeMs   float64                `protobuf:"fixed64,2,opt,name=build_time_ms,json=buildTimeMs,proto3" json:"build_time_ms,omitempty"`
+	Stats         *Stats                 `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BuildResponse) Reset() {
-	*x = BuildResponse{}
-	mi := &file_urbis_proto_msgTypes[28]
+func (x *StatsResponse) Reset() {
+	*x = StatsResponse{}
+	mi := &file_urbis_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BuildResponse) String() string {
+func (x *StatsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BuildResponse) ProtoMessage() {}
+func (*StatsResponse) ProtoMessage() {}
 
-func (x *BuildResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[28]
+func (x *StatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1685,47 +7437,39 @@ func (x *BuildResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BuildResponse.ProtoReflect.Descriptor instead.
-func (*BuildResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{28}
-}
-
-func (x *BuildResponse) GetMessage() string {
-	if x != nil {
-		return x.Message
-	}
-	return ""
+// Deprecated: Use StatsResponse.ProtoReflect.Descriptor instead.
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{101}
 }
 
-func (x *BuildResponse) GetBuildTimeMs() float64 {
+func (x *StatsResponse) GetStats() *Stats {
 	if x != nil {
-		return x.BuildTimeMs
+		return x.Stats
 	}
-	return 0
+	return nil
 }
 
-type OptimizeRequest struct {
+type ResourceStatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OptimizeRequest) Reset() {
-	*x = OptimizeRequest{}
-	mi := &file_urbis_proto_msgTypes[29]
+func (x *ResourceStatsRequest) Reset() {
+	*x = ResourceStatsRequest{}
+	mi := &file_urbis_proto_msgTypes[102]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OptimizeRequest) String() string {
+func (x *ResourceStatsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OptimizeRequest) ProtoMessage() {}
+func (*ResourceStatsRequest) ProtoMessage() {}
 
-func (x *OptimizeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[29]
+func (x *ResourceStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[102]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1736,40 +7480,36 @@ func (x *OptimizeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OptimizeRequest.ProtoReflect.Descriptor instead.
-func (*OptimizeRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{29}
-}
-
-func (x *OptimizeRequest) GetIndexId() string {
-	if x != nil {
-		return x.IndexId
-	}
-	return ""
+// Deprecated: Use ResourceStatsRequest.ProtoReflect.Descriptor instead.
+func (*ResourceStatsRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{102}
 }
 
-type OptimizeResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type ResourceStatsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	OpenIndexes      uint64                 `protobuf:"varint,1,opt,name=open_indexes,json=openIndexes,proto3" json:"open_indexes,omitempty"`                // Native indexes open in the process
+	NativeBytes      uint64                 `protobuf:"varint,2,opt,name=native_bytes,json=nativeBytes,proto3" json:"native_bytes,omitempty"`                // Heap held by the open indexes
+	FinalizedIndexes uint64                 `protobuf:"varint,3,opt,name=finalized_indexes,json=finalizedIndexes,proto3" json:"finalized_indexes,omitempty"` // Indexes closed by the garbage collector, never by Close
+	LeakedOrigins    []string               `protobuf:"bytes,4,rep,name=leaked_origins,json=leakedOrigins,proto3" json:"leaked_origins,omitempty"`           // Where open indexes the server no longer holds were opened
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *OptimizeResponse) Reset() {
-	*x = OptimizeResponse{}
-	mi := &file_urbis_proto_msgTypes[30]
+func (x *ResourceStatsResponse) Reset() {
+	*x = ResourceStatsResponse{}
+	mi := &file_urbis_proto_msgTypes[103]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OptimizeResponse) String() string {
+func (x *ResourceStatsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OptimizeResponse) ProtoMessage() {}
+func (*ResourceStatsResponse) ProtoMessage() {}
 
-func (x *OptimizeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[30]
+func (x *ResourceStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[103]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1780,41 +7520,61 @@ func (x *OptimizeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OptimizeResponse.ProtoReflect.Descriptor instead.
-func (*OptimizeResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use ResourceStatsResponse.ProtoReflect.Descriptor instead.
+func (*ResourceStatsResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{103}
 }
 
-func (x *OptimizeResponse) GetMessage() string {
+func (x *ResourceStatsResponse) GetOpenIndexes() uint64 {
 	if x != nil {
-		return x.Message
+		return x.OpenIndexes
 	}
-	return ""
+	return 0
 }
 
-type RangeQueryRequest struct {
+func (x *ResourceStatsResponse) GetNativeBytes() uint64 {
+	if x != nil {
+		return x.NativeBytes
+	}
+	return 0
+}
+
+func (x *ResourceStatsResponse) GetFinalizedIndexes() uint64 {
+	if x != nil {
+		return x.FinalizedIndexes
+	}
+	return 0
+}
+
+func (x *ResourceStatsResponse) GetLeakedOrigins() []string {
+	if x != nil {
+		return x.LeakedOrigins
+	}
+	return nil
+}
+
+type CountRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	Range         *MBR                   `protobuf:"bytes,2,opt,name=range,proto3" json:"range,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RangeQueryRequest) Reset() {
-	*x = RangeQueryRequest{}
-	mi := &file_urbis_proto_msgTypes[31]
+func (x *CountRequest) Reset() {
+	*x = CountRequest{}
+	mi := &file_urbis_proto_msgTypes[104]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RangeQueryRequest) String() string {
+func (x *CountRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RangeQueryRequest) ProtoMessage() {}
+func (*CountRequest) ProtoMessage() {}
 
-func (x *RangeQueryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[31]
+func (x *CountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[104]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1825,49 +7585,40 @@ func (x *RangeQueryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RangeQueryRequest.ProtoReflect.Descriptor instead.
-func (*RangeQueryRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use CountRequest.ProtoReflect.Descriptor instead.
+func (*CountRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{104}
 }
 
-func (x *RangeQueryRequest) GetIndexId() string {
+func (x *CountRequest) GetIndexId() string {
 	if x != nil {
 		return x.IndexId
 	}
 	return ""
 }
 
-func (x *RangeQueryRequest) GetRange() *MBR {
-	if x != nil {
-		return x.Range
-	}
-	return nil
-}
-
-type PointQueryRequest struct {
+type CountResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	X             float64                `protobuf:"fixed64,2,opt,name=x,proto3" json:"x,omitempty"`
-	Y             float64                `protobuf:"fixed64,3,opt,name=y,proto3" json:"y,omitempty"`
+	Count         uint64                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PointQueryRequest) Reset() {
-	*x = PointQueryRequest{}
-	mi := &file_urbis_proto_msgTypes[32]
+func (x *CountResponse) Reset() {
+	*x = CountResponse{}
+	mi := &file_urbis_proto_msgTypes[105]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PointQueryRequest) String() string {
+func (x *CountResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PointQueryRequest) ProtoMessage() {}
+func (*CountResponse) ProtoMessage() {}
 
-func (x *PointQueryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[32]
+func (x *CountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[105]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1878,57 +7629,40 @@ func (x *PointQueryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PointQueryRequest.ProtoReflect.Descriptor instead.
-func (*PointQueryRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{32}
-}
-
-func (x *PointQueryRequest) GetIndexId() string {
-	if x != nil {
-		return x.IndexId
-	}
-	return ""
-}
-
-func (x *PointQueryRequest) GetX() float64 {
-	if x != nil {
-		return x.X
-	}
-	return 0
+// Deprecated: Use CountResponse.ProtoReflect.Descriptor instead.
+func (*CountResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{105}
 }
 
-func (x *PointQueryRequest) GetY() float64 {
+func (x *CountResponse) GetCount() uint64 {
 	if x != nil {
-		return x.Y
+		return x.Count
 	}
 	return 0
 }
 
-type KNNQueryRequest struct {
+type BoundsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	X             float64                `protobuf:"fixed64,2,opt,name=x,proto3" json:"x,omitempty"`
-	Y             float64                `protobuf:"fixed64,3,opt,name=y,proto3" json:"y,omitempty"`
-	K             uint32                 `protobuf:"varint,4,opt,name=k,proto3" json:"k,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *KNNQueryRequest) Reset() {
-	*x = KNNQueryRequest{}
-	mi := &file_urbis_proto_msgTypes[33]
+func (x *BoundsRequest) Reset() {
+	*x = BoundsRequest{}
+	mi := &file_urbis_proto_msgTypes[106]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *KNNQueryRequest) String() string {
+func (x *BoundsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KNNQueryRequest) ProtoMessage() {}
+func (*BoundsRequest) ProtoMessage() {}
 
-func (x *KNNQueryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[33]
+func (x *BoundsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[106]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1939,63 +7673,42 @@ func (x *KNNQueryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KNNQueryRequest.ProtoReflect.Descriptor instead.
-func (*KNNQueryRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{33}
+// Deprecated: Use BoundsRequest.ProtoReflect.Descriptor instead.
+func (*BoundsRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{106}
 }
 
-func (x *KNNQueryRequest) GetIndexId() string {
+func (x *BoundsRequest) GetIndexId() string {
 	if x != nil {
 		return x.IndexId
 	}
 	return ""
 }
 
-func (x *KNNQueryRequest) GetX() float64 {
-	if x != nil {
-		return x.X
-	}
-	return 0
-}
-
-func (x *KNNQueryRequest) GetY() float64 {
-	if x != nil {
-		return x.Y
-	}
-	return 0
-}
-
-func (x *KNNQueryRequest) GetK() uint32 {
-	if x != nil {
-		return x.K
-	}
-	return 0
-}
-
-type QueryResponse struct {
+type BoundsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Objects       []*SpatialObject       `protobuf:"bytes,1,rep,name=objects,proto3" json:"objects,omitempty"`
-	Count         uint64                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
-	QueryTimeMs   float64                `protobuf:"fixed64,3,opt,name=query_time_ms,json=queryTimeMs,proto3" json:"query_time_ms,omitempty"`
+	Bounds        *MBR                   `protobuf:"bytes,1,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	WidthMeters   *float64               `protobuf:"fixed64,2,opt,name=width_meters,json=widthMeters,proto3,oneof" json:"width_meters,omitempty"` // Extent of bounds in meters, for a non-empty index with crs 4326
+	HeightMeters  *float64               `protobuf:"fixed64,3,opt,name=height_meters,json=heightMeters,proto3,oneof" json:"height_meters,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *QueryResponse) Reset() {
-	*x = QueryResponse{}
-	mi := &file_urbis_proto_msgTypes[34]
+func (x *BoundsResponse) Reset() {
+	*x = BoundsResponse{}
+	mi := &file_urbis_proto_msgTypes[107]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *QueryResponse) String() string {
+func (x *BoundsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryResponse) ProtoMessage() {}
+func (*BoundsResponse) ProtoMessage() {}
 
-func (x *QueryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[34]
+func (x *BoundsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[107]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2006,55 +7719,55 @@ func (x *QueryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
-func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{34}
+// Deprecated: Use BoundsResponse.ProtoReflect.Descriptor instead.
+func (*BoundsResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{107}
 }
 
-func (x *QueryResponse) GetObjects() []*SpatialObject {
+func (x *BoundsResponse) GetBounds() *MBR {
 	if x != nil {
-		return x.Objects
+		return x.Bounds
 	}
 	return nil
 }
 
-func (x *QueryResponse) GetCount() uint64 {
-	if x != nil {
-		return x.Count
+func (x *BoundsResponse) GetWidthMeters() float64 {
+	if x != nil && x.WidthMeters != nil {
+		return *x.WidthMeters
 	}
 	return 0
 }
 
-func (x *QueryResponse) GetQueryTimeMs() float64 {
-	if x != nil {
-		return x.QueryTimeMs
+func (x *BoundsResponse) GetHeightMeters() float64 {
+	if x != nil && x.HeightMeters != nil {
+		return *x.HeightMeters
 	}
 	return 0
 }
 
-type AdjacentPagesRequest struct {
+type SaveRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	Region        *MBR                   `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"` // Empty saves to the index's file in config.data_path
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AdjacentPagesRequest) Reset() {
-	*x = AdjacentPagesRequest{}
-	mi := &file_urbis_proto_msgTypes[35]
+func (x *SaveRequest) Reset() {
+	*x = SaveRequest{}
+	mi := &file_urbis_proto_msgTypes[108]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AdjacentPagesRequest) String() string {
+func (x *SaveRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AdjacentPagesRequest) ProtoMessage() {}
+func (*SaveRequest) ProtoMessage() {}
 
-func (x *AdjacentPagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[35]
+func (x *SaveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[108]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2065,49 +7778,48 @@ func (x *AdjacentPagesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AdjacentPagesRequest.ProtoReflect.Descriptor instead.
-func (*AdjacentPagesRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{35}
+// Deprecated: Use SaveRequest.ProtoReflect.Descriptor instead.
+func (*SaveRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{108}
 }
 
-func (x *AdjacentPagesRequest) GetIndexId() string {
+func (x *SaveRequest) GetIndexId() string {
 	if x != nil {
 		return x.IndexId
 	}
 	return ""
 }
 
-func (x *AdjacentPagesRequest) GetRegion() *MBR {
+func (x *SaveRequest) GetPath() string {
 	if x != nil {
-		return x.Region
+		return x.Path
 	}
-	return nil
+	return ""
 }
 
-type AdjacentPagesResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Pages          []*PageInfo            `protobuf:"bytes,1,rep,name=pages,proto3" json:"pages,omitempty"`
-	Count          uint64                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
-	EstimatedSeeks uint64                 `protobuf:"varint,3,opt,name=estimated_seeks,json=estimatedSeeks,proto3" json:"estimated_seeks,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type SaveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"` // The file written
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AdjacentPagesResponse) Reset() {
-	*x = AdjacentPagesResponse{}
-	mi := &file_urbis_proto_msgTypes[36]
+func (x *SaveResponse) Reset() {
+	*x = SaveResponse{}
+	mi := &file_urbis_proto_msgTypes[109]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AdjacentPagesResponse) String() string {
+func (x *SaveResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AdjacentPagesResponse) ProtoMessage() {}
+func (*SaveResponse) ProtoMessage() {}
 
-func (x *AdjacentPagesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[36]
+func (x *SaveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[109]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2118,54 +7830,47 @@ func (x *AdjacentPagesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AdjacentPagesResponse.ProtoReflect.Descriptor instead.
-func (*AdjacentPagesResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{36}
-}
-
-func (x *AdjacentPagesResponse) GetPages() []*PageInfo {
-	if x != nil {
-		return x.Pages
-	}
-	return nil
+// Deprecated: Use SaveResponse.ProtoReflect.Descriptor instead.
+func (*SaveResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{109}
 }
 
-func (x *AdjacentPagesResponse) GetCount() uint64 {
+func (x *SaveResponse) GetMessage() string {
 	if x != nil {
-		return x.Count
+		return x.Message
 	}
-	return 0
+	return ""
 }
 
-func (x *AdjacentPagesResponse) GetEstimatedSeeks() uint64 {
+func (x *SaveResponse) GetPath() string {
 	if x != nil {
-		return x.EstimatedSeeks
+		return x.Path
 	}
-	return 0
+	return ""
 }
 
-type StatsRequest struct {
+type SyncRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"` // Must persist to a data_path
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StatsRequest) Reset() {
-	*x = StatsRequest{}
-	mi := &file_urbis_proto_msgTypes[37]
+func (x *SyncRequest) Reset() {
+	*x = SyncRequest{}
+	mi := &file_urbis_proto_msgTypes[110]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StatsRequest) String() string {
+func (x *SyncRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatsRequest) ProtoMessage() {}
+func (*SyncRequest) ProtoMessage() {}
 
-func (x *StatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[37]
+func (x *SyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[110]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2176,40 +7881,41 @@ func (x *StatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatsRequest.ProtoReflect.Descriptor instead.
-func (*StatsRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use SyncRequest.ProtoReflect.Descriptor instead.
+func (*SyncRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{110}
 }
 
-func (x *StatsRequest) GetIndexId() string {
+func (x *SyncRequest) GetIndexId() string {
 	if x != nil {
 		return x.IndexId
 	}
 	return ""
 }
 
-type StatsResponse struct {
+type SyncResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Stats         *Stats                 `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`                                   // The data file written
+	ChangeCount   uint64                 `protobuf:"varint,2,opt,name=change_count,json=changeCount,proto3" json:"change_count,omitempty"` // Inserts, removals and updates the file includes
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StatsResponse) Reset() {
-	*x = StatsResponse{}
-	mi := &file_urbis_proto_msgTypes[38]
+func (x *SyncResponse) Reset() {
+	*x = SyncResponse{}
+	mi := &file_urbis_proto_msgTypes[111]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StatsResponse) String() string {
+func (x *SyncResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatsResponse) ProtoMessage() {}
+func (*SyncResponse) ProtoMessage() {}
 
-func (x *StatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[38]
+func (x *SyncResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[111]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2220,40 +7926,49 @@ func (x *StatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatsResponse.ProtoReflect.Descriptor instead.
-func (*StatsResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{38}
+// Deprecated: Use SyncResponse.ProtoReflect.Descriptor instead.
+func (*SyncResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{111}
 }
 
-func (x *StatsResponse) GetStats() *Stats {
+func (x *SyncResponse) GetPath() string {
 	if x != nil {
-		return x.Stats
+		return x.Path
 	}
-	return nil
+	return ""
 }
 
-type CountRequest struct {
+func (x *SyncResponse) GetChangeCount() uint64 {
+	if x != nil {
+		return x.ChangeCount
+	}
+	return 0
+}
+
+type LoadIndexRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	ReadOnly      bool                   `protobuf:"varint,3,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"` // Open the index read-only, e.g. on a serving replica
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CountRequest) Reset() {
-	*x = CountRequest{}
-	mi := &file_urbis_proto_msgTypes[39]
+func (x *LoadIndexRequest) Reset() {
+	*x = LoadIndexRequest{}
+	mi := &file_urbis_proto_msgTypes[112]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CountRequest) String() string {
+func (x *LoadIndexRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CountRequest) ProtoMessage() {}
+func (*LoadIndexRequest) ProtoMessage() {}
 
-func (x *CountRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[39]
+func (x *LoadIndexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[112]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2264,40 +7979,56 @@ func (x *CountRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CountRequest.ProtoReflect.Descriptor instead.
-func (*CountRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use LoadIndexRequest.ProtoReflect.Descriptor instead.
+func (*LoadIndexRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{112}
+}
+
+func (x *LoadIndexRequest) GetIndexId() string {
+	if x != nil {
+		return x.IndexId
+	}
+	return ""
+}
+
+func (x *LoadIndexRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
 }
 
-func (x *CountRequest) GetIndexId() string {
+func (x *LoadIndexRequest) GetReadOnly() bool {
 	if x != nil {
-		return x.IndexId
+		return x.ReadOnly
 	}
-	return ""
+	return false
 }
 
-type CountResponse struct {
+type LoadIndexResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Count         uint64                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Count         uint64                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Bounds        *MBR                   `protobuf:"bytes,3,opt,name=bounds,proto3" json:"bounds,omitempty"` // Unset when the saved index is empty
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CountResponse) Reset() {
-	*x = CountResponse{}
-	mi := &file_urbis_proto_msgTypes[40]
+func (x *LoadIndexResponse) Reset() {
+	*x = LoadIndexResponse{}
+	mi := &file_urbis_proto_msgTypes[113]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CountResponse) String() string {
+func (x *LoadIndexResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CountResponse) ProtoMessage() {}
+func (*LoadIndexResponse) ProtoMessage() {}
 
-func (x *CountResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[40]
+func (x *LoadIndexResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[113]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2308,40 +8039,54 @@ func (x *CountResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CountResponse.ProtoReflect.Descriptor instead.
-func (*CountResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{40}
+// Deprecated: Use LoadIndexResponse.ProtoReflect.Descriptor instead.
+func (*LoadIndexResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{113}
 }
 
-func (x *CountResponse) GetCount() uint64 {
+func (x *LoadIndexResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LoadIndexResponse) GetCount() uint64 {
 	if x != nil {
 		return x.Count
 	}
 	return 0
 }
 
-type BoundsRequest struct {
+func (x *LoadIndexResponse) GetBounds() *MBR {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+type StreamSaveRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BoundsRequest) Reset() {
-	*x = BoundsRequest{}
-	mi := &file_urbis_proto_msgTypes[41]
+func (x *StreamSaveRequest) Reset() {
+	*x = StreamSaveRequest{}
+	mi := &file_urbis_proto_msgTypes[114]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BoundsRequest) String() string {
+func (x *StreamSaveRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BoundsRequest) ProtoMessage() {}
+func (*StreamSaveRequest) ProtoMessage() {}
 
-func (x *BoundsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[41]
+func (x *StreamSaveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[114]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2352,40 +8097,41 @@ func (x *BoundsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BoundsRequest.ProtoReflect.Descriptor instead.
-func (*BoundsRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{41}
+// Deprecated: Use StreamSaveRequest.ProtoReflect.Descriptor instead.
+func (*StreamSaveRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{114}
 }
 
-func (x *BoundsRequest) GetIndexId() string {
+func (x *StreamSaveRequest) GetIndexId() string {
 	if x != nil {
 		return x.IndexId
 	}
 	return ""
 }
 
-type BoundsResponse struct {
+// A chunk of a serialized index, in the same format Save writes to disk
+type IndexChunk struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Bounds        *MBR                   `protobuf:"bytes,1,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	Chunk         []byte                 `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BoundsResponse) Reset() {
-	*x = BoundsResponse{}
-	mi := &file_urbis_proto_msgTypes[42]
+func (x *IndexChunk) Reset() {
+	*x = IndexChunk{}
+	mi := &file_urbis_proto_msgTypes[115]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BoundsResponse) String() string {
+func (x *IndexChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BoundsResponse) ProtoMessage() {}
+func (*IndexChunk) ProtoMessage() {}
 
-func (x *BoundsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[42]
+func (x *IndexChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[115]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2396,41 +8142,41 @@ func (x *BoundsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BoundsResponse.ProtoReflect.Descriptor instead.
-func (*BoundsResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{42}
+// Deprecated: Use IndexChunk.ProtoReflect.Descriptor instead.
+func (*IndexChunk) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{115}
 }
 
-func (x *BoundsResponse) GetBounds() *MBR {
+func (x *IndexChunk) GetChunk() []byte {
 	if x != nil {
-		return x.Bounds
+		return x.Chunk
 	}
 	return nil
 }
 
-type SaveRequest struct {
+type StreamLoadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"` // Required on the first message, ignored afterwards
+	Chunk         []byte                 `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"`                    // Next chunk of a serialized index
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SaveRequest) Reset() {
-	*x = SaveRequest{}
-	mi := &file_urbis_proto_msgTypes[43]
+func (x *StreamLoadRequest) Reset() {
+	*x = StreamLoadRequest{}
+	mi := &file_urbis_proto_msgTypes[116]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveRequest) String() string {
+func (x *StreamLoadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveRequest) ProtoMessage() {}
+func (*StreamLoadRequest) ProtoMessage() {}
 
-func (x *SaveRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[43]
+func (x *StreamLoadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[116]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2441,47 +8187,55 @@ func (x *SaveRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveRequest.ProtoReflect.Descriptor instead.
-func (*SaveRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{43}
+// Deprecated: Use StreamLoadRequest.ProtoReflect.Descriptor instead.
+func (*StreamLoadRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{116}
 }
 
-func (x *SaveRequest) GetIndexId() string {
+func (x *StreamLoadRequest) GetIndexId() string {
 	if x != nil {
 		return x.IndexId
 	}
 	return ""
 }
 
-func (x *SaveRequest) GetPath() string {
+func (x *StreamLoadRequest) GetChunk() []byte {
 	if x != nil {
-		return x.Path
+		return x.Chunk
 	}
-	return ""
+	return nil
 }
 
-type SaveResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+type ReloadIndexRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	IndexId string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
+	// Types that are valid to be assigned to Source:
+	//
+	//	*ReloadIndexRequest_DataFile
+	//	*ReloadIndexRequest_GeojsonPath
+	//	*ReloadIndexRequest_Geojson
+	Source        isReloadIndexRequest_Source `protobuf_oneof:"source"`
+	Config        *Config                     `protobuf:"bytes,5,opt,name=config,proto3" json:"config,omitempty"` // Configuration for GeoJSON sources (default config if unset)
+	Build         bool                        `protobuf:"varint,6,opt,name=build,proto3" json:"build,omitempty"`  // Build the fresh index before swapping it in
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SaveResponse) Reset() {
-	*x = SaveResponse{}
-	mi := &file_urbis_proto_msgTypes[44]
+func (x *ReloadIndexRequest) Reset() {
+	*x = ReloadIndexRequest{}
+	mi := &file_urbis_proto_msgTypes[117]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveResponse) String() string {
+func (x *ReloadIndexRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveResponse) ProtoMessage() {}
+func (*ReloadIndexRequest) ProtoMessage() {}
 
-func (x *SaveResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[44]
+func (x *ReloadIndexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[117]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2492,92 +8246,112 @@ func (x *SaveResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveResponse.ProtoReflect.Descriptor instead.
-func (*SaveResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{44}
+// Deprecated: Use ReloadIndexRequest.ProtoReflect.Descriptor instead.
+func (*ReloadIndexRequest) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{117}
 }
 
-func (x *SaveResponse) GetMessage() string {
+func (x *ReloadIndexRequest) GetIndexId() string {
 	if x != nil {
-		return x.Message
+		return x.IndexId
 	}
 	return ""
 }
 
-type LoadIndexRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IndexId       string                 `protobuf:"bytes,1,opt,name=index_id,json=indexId,proto3" json:"index_id,omitempty"`
-	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *LoadIndexRequest) Reset() {
-	*x = LoadIndexRequest{}
-	mi := &file_urbis_proto_msgTypes[45]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ReloadIndexRequest) GetSource() isReloadIndexRequest_Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
 }
 
-func (x *LoadIndexRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ReloadIndexRequest) GetDataFile() string {
+	if x != nil {
+		if x, ok := x.Source.(*ReloadIndexRequest_DataFile); ok {
+			return x.DataFile
+		}
+	}
+	return ""
 }
 
-func (*LoadIndexRequest) ProtoMessage() {}
-
-func (x *LoadIndexRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[45]
+func (x *ReloadIndexRequest) GetGeojsonPath() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
+		if x, ok := x.Source.(*ReloadIndexRequest_GeojsonPath); ok {
+			return x.GeojsonPath
 		}
-		return ms
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use LoadIndexRequest.ProtoReflect.Descriptor instead.
-func (*LoadIndexRequest) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{45}
+func (x *ReloadIndexRequest) GetGeojson() string {
+	if x != nil {
+		if x, ok := x.Source.(*ReloadIndexRequest_Geojson); ok {
+			return x.Geojson
+		}
+	}
+	return ""
 }
 
-func (x *LoadIndexRequest) GetIndexId() string {
+func (x *ReloadIndexRequest) GetConfig() *Config {
 	if x != nil {
-		return x.IndexId
+		return x.Config
 	}
-	return ""
+	return nil
 }
 
-func (x *LoadIndexRequest) GetPath() string {
+func (x *ReloadIndexRequest) GetBuild() bool {
 	if x != nil {
-		return x.Path
+		return x.Build
 	}
-	return ""
+	return false
 }
 
-type LoadIndexResponse struct {
+type isReloadIndexRequest_Source interface {
+	isReloadIndexRequest_Source()
+}
+
+type ReloadIndexRequest_DataFile struct {
+	DataFile string `protobuf:"bytes,2,opt,name=data_file,json=dataFile,proto3,oneof"` // Saved index file written by Save
+}
+
+type ReloadIndexRequest_GeojsonPath struct {
+	GeojsonPath string `protobuf:"bytes,3,opt,name=geojson_path,json=geojsonPath,proto3,oneof"` // GeoJSON file
+}
+
+type ReloadIndexRequest_Geojson struct {
+	Geojson string `protobuf:"bytes,4,opt,name=geojson,proto3,oneof"` // GeoJSON string
+}
+
+func (*ReloadIndexRequest_DataFile) isReloadIndexRequest_Source() {}
+
+func (*ReloadIndexRequest_GeojsonPath) isReloadIndexRequest_Source() {}
+
+func (*ReloadIndexRequest_Geojson) isReloadIndexRequest_Source() {}
+
+type ReloadIndexResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Count         uint64                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Bounds        *MBR                   `protobuf:"bytes,3,opt,name=bounds,proto3" json:"bounds,omitempty"` // Unset when the saved index is empty
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LoadIndexResponse) Reset() {
-	*x = LoadIndexResponse{}
-	mi := &file_urbis_proto_msgTypes[46]
+func (x *ReloadIndexResponse) Reset() {
+	*x = ReloadIndexResponse{}
+	mi := &file_urbis_proto_msgTypes[118]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoadIndexResponse) String() string {
+func (x *ReloadIndexResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoadIndexResponse) ProtoMessage() {}
+func (*ReloadIndexResponse) ProtoMessage() {}
 
-func (x *LoadIndexResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_urbis_proto_msgTypes[46]
+func (x *ReloadIndexResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_urbis_proto_msgTypes[118]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2588,26 +8362,44 @@ func (x *LoadIndexResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoadIndexResponse.ProtoReflect.Descriptor instead.
-func (*LoadIndexResponse) Descriptor() ([]byte, []int) {
-	return file_urbis_proto_rawDescGZIP(), []int{46}
+// Deprecated: Use ReloadIndexResponse.ProtoReflect.Descriptor instead.
+func (*ReloadIndexResponse) Descriptor() ([]byte, []int) {
+	return file_urbis_proto_rawDescGZIP(), []int{118}
 }
 
-func (x *LoadIndexResponse) GetMessage() string {
+func (x *ReloadIndexResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
+func (x *ReloadIndexResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ReloadIndexResponse) GetBounds() *MBR {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
 var File_urbis_proto protoreflect.FileDescriptor
 
 const file_urbis_proto_rawDesc = "" +
 	"\n" +
-	"\vurbis.proto\x12\x05urbis\"#\n" +
+	"\vurbis.proto\x12\x05urbis\"U\n" +
 	"\x05Point\x12\f\n" +
 	"\x01x\x18\x01 \x01(\x01R\x01x\x12\f\n" +
-	"\x01y\x18\x02 \x01(\x01R\x01y\"Y\n" +
+	"\x01y\x18\x02 \x01(\x01R\x01y\x12\x11\n" +
+	"\x01z\x18\x03 \x01(\x01H\x00R\x01z\x88\x01\x01\x12\x11\n" +
+	"\x01m\x18\x04 \x01(\x01H\x01R\x01m\x88\x01\x01B\x04\n" +
+	"\x02_zB\x04\n" +
+	"\x02_m\"Y\n" +
 	"\x03MBR\x12\x13\n" +
 	"\x05min_x\x18\x01 \x01(\x01R\x04minX\x12\x13\n" +
 	"\x05min_y\x18\x02 \x01(\x01R\x04minY\x12\x13\n" +
@@ -2620,21 +8412,44 @@ const file_urbis_proto_rawDesc = "" +
 	"\bexterior\x18\x01 \x03(\v2\f.urbis.PointR\bexterior\x12!\n" +
 	"\x05holes\x18\x02 \x03(\v2\v.urbis.RingR\x05holes\",\n" +
 	"\x04Ring\x12$\n" +
-	"\x06points\x18\x01 \x03(\v2\f.urbis.PointR\x06points\"\xb3\x02\n" +
+	"\x06points\x18\x01 \x03(\v2\f.urbis.PointR\x06points\"2\n" +
+	"\n" +
+	"MultiPoint\x12$\n" +
+	"\x06points\x18\x01 \x03(\v2\f.urbis.PointR\x06points\":\n" +
+	"\x0fMultiLineString\x12'\n" +
+	"\x05lines\x18\x01 \x03(\v2\x11.urbis.LineStringR\x05lines\":\n" +
+	"\fMultiPolygon\x12*\n" +
+	"\bpolygons\x18\x01 \x03(\v2\x0e.urbis.PolygonR\bpolygons\"J\n" +
+	"\x12GeometryCollection\x124\n" +
+	"\n" +
+	"geometries\x18\x01 \x03(\v2\x14.urbis.SpatialObjectR\n" +
+	"geometries\"\x86\x05\n" +
 	"\rSpatialObject\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12#\n" +
 	"\x04type\x18\x02 \x01(\x0e2\x0f.urbis.GeomTypeR\x04type\x12$\n" +
 	"\x05point\x18\x03 \x01(\v2\f.urbis.PointH\x00R\x05point\x12'\n" +
 	"\x04line\x18\x04 \x01(\v2\x11.urbis.LineStringH\x00R\x04line\x12*\n" +
-	"\apolygon\x18\x05 \x01(\v2\x0e.urbis.PolygonH\x00R\apolygon\x12(\n" +
+	"\apolygon\x18\x05 \x01(\v2\x0e.urbis.PolygonH\x00R\apolygon\x124\n" +
+	"\vmulti_point\x18\t \x01(\v2\x11.urbis.MultiPointH\x00R\n" +
+	"multiPoint\x127\n" +
+	"\n" +
+	"multi_line\x18\n" +
+	" \x01(\v2\x16.urbis.MultiLineStringH\x00R\tmultiLine\x12:\n" +
+	"\rmulti_polygon\x18\v \x01(\v2\x13.urbis.MultiPolygonH\x00R\fmultiPolygon\x12;\n" +
+	"\n" +
+	"collection\x18\f \x01(\v2\x19.urbis.GeometryCollectionH\x00R\n" +
+	"collection\x12(\n" +
 	"\bcentroid\x18\x06 \x01(\v2\f.urbis.PointR\bcentroid\x12\x1c\n" +
 	"\x03mbr\x18\a \x01(\v2\n" +
 	".urbis.MBRR\x03mbr\x12\x1e\n" +
 	"\n" +
 	"properties\x18\b \x01(\fR\n" +
-	"propertiesB\n" +
+	"properties\x12\x18\n" +
+	"\aversion\x18\r \x01(\x04R\aversion\x12$\n" +
+	"\x0emodified_at_ms\x18\x0e \x01(\x03R\fmodifiedAtMs\x12)\n" +
+	"\x10encoded_geometry\x18\x0f \x01(\fR\x0fencodedGeometryB\n" +
 	"\n" +
-	"\bgeometry\"\xcb\x01\n" +
+	"\bgeometry\"\xd6\x06\n" +
 	"\x06Config\x12\x1d\n" +
 	"\n" +
 	"block_size\x18\x01 \x01(\x04R\tblockSize\x12#\n" +
@@ -2643,7 +8458,33 @@ const file_urbis_proto_rawDesc = "" +
 	"cache_size\x18\x03 \x01(\x04R\tcacheSize\x12'\n" +
 	"\x0fenable_quadtree\x18\x04 \x01(\bR\x0eenableQuadtree\x12\x18\n" +
 	"\apersist\x18\x05 \x01(\bR\apersist\x12\x1b\n" +
-	"\tdata_path\x18\x06 \x01(\tR\bdataPath\"\xdd\x02\n" +
+	"\tdata_path\x18\x06 \x01(\tR\bdataPath\x12%\n" +
+	"\x0esnap_precision\x18\a \x01(\x01R\rsnapPrecision\x12!\n" +
+	"\fdedup_points\x18\b \x01(\bR\vdedupPoints\x12\x10\n" +
+	"\x03crs\x18\t \x01(\x05R\x03crs\x12G\n" +
+	"\x12polygon_validation\x18\n" +
+	" \x01(\x0e2\x18.urbis.PolygonValidationR\x11polygonValidation\x12-\n" +
+	"\x12indexed_properties\x18\v \x03(\tR\x11indexedProperties\x12-\n" +
+	"\x12simplify_tolerance\x18\f \x01(\x01R\x11simplifyTolerance\x121\n" +
+	"\tseek_cost\x18\r \x01(\v2\x14.urbis.SeekCostModelR\bseekCost\x121\n" +
+	"\x14validation_tolerance\x18\x0e \x01(\x01R\x13validationTolerance\x12<\n" +
+	"\x0fproperty_schema\x18\x0f \x03(\v2\x13.urbis.PropertyRuleR\x0epropertySchema\x12#\n" +
+	"\rbuild_threads\x18\x10 \x01(\rR\fbuildThreads\x12\x12\n" +
+	"\x04seed\x18\x11 \x01(\x04R\x04seed\x12\x1b\n" +
+	"\tread_only\x18\x12 \x01(\bR\breadOnly\x12'\n" +
+	"\x0fkeep_duplicates\x18\x13 \x01(\bR\x0ekeepDuplicates\x124\n" +
+	"\x16auto_rebuild_threshold\x18\x14 \x01(\x04R\x14autoRebuildThreshold\x12-\n" +
+	"\fvalid_bounds\x18\x15 \x01(\v2\n" +
+	".urbis.MBRR\vvalidBounds\"\x82\x01\n" +
+	"\fPropertyRule\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1a\n" +
+	"\brequired\x18\x02 \x01(\bR\brequired\x12'\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x13.urbis.PropertyTypeR\x04type\x12\x1b\n" +
+	"\tnon_empty\x18\x04 \x01(\bR\bnonEmpty\"z\n" +
+	"\rSeekCostModel\x12,\n" +
+	"\astorage\x18\x01 \x01(\x0e2\x12.urbis.StorageKindR\astorage\x12\x17\n" +
+	"\aseek_ms\x18\x02 \x01(\x01R\x06seekMs\x12\"\n" +
+	"\rtransfer_mb_s\x18\x03 \x01(\x01R\vtransferMbS\"\xde\x04\n" +
 	"\x05Stats\x12#\n" +
 	"\rtotal_objects\x18\x01 \x01(\x04R\ftotalObjects\x12!\n" +
 	"\ftotal_blocks\x18\x02 \x01(\x04R\vtotalBlocks\x12\x1f\n" +
@@ -2655,146 +8496,668 @@ const file_urbis_proto_rawDesc = "" +
 	"\fkdtree_depth\x18\a \x01(\x04R\vkdtreeDepth\x12%\n" +
 	"\x0equadtree_depth\x18\b \x01(\x04R\rquadtreeDepth\x12\"\n" +
 	"\x06bounds\x18\t \x01(\v2\n" +
-	".urbis.MBRR\x06bounds\">\n" +
+	".urbis.MBRR\x06bounds\x12#\n" +
+	"\rpage_capacity\x18\n" +
+	" \x01(\x04R\fpageCapacity\x12!\n" +
+	"\fmemory_bytes\x18\v \x01(\x04R\vmemoryBytes\x12\x1d\n" +
+	"\n" +
+	"disk_bytes\x18\f \x01(\x04R\tdiskBytes\x12#\n" +
+	"\rbuild_threads\x18\r \x01(\x04R\fbuildThreads\x12&\n" +
+	"\fwidth_meters\x18\x0e \x01(\x01H\x00R\vwidthMeters\x88\x01\x01\x12(\n" +
+	"\rheight_meters\x18\x0f \x01(\x01H\x01R\fheightMeters\x88\x01\x01B\x0f\n" +
+	"\r_width_metersB\x10\n" +
+	"\x0e_height_meters\"\x85\x01\n" +
 	"\bPageInfo\x12\x17\n" +
 	"\apage_id\x18\x01 \x01(\rR\x06pageId\x12\x19\n" +
-	"\btrack_id\x18\x02 \x01(\rR\atrackId\"V\n" +
+	"\btrack_id\x18\x02 \x01(\rR\atrackId\x12!\n" +
+	"\fobject_count\x18\x03 \x01(\rR\vobjectCount\x12\"\n" +
+	"\x06extent\x18\x04 \x01(\v2\n" +
+	".urbis.MBRR\x06extent\"z\n" +
 	"\x12CreateIndexRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12%\n" +
-	"\x06config\x18\x02 \x01(\v2\r.urbis.ConfigR\x06config\"J\n" +
+	"\x06config\x18\x02 \x01(\v2\r.urbis.ConfigR\x06config\x12\"\n" +
+	"\rif_not_exists\x18\x03 \x01(\bR\vifNotExists\"\x9e\x01\n" +
 	"\x13CreateIndexResponse\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"0\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x04R\x05count\x12\"\n" +
+	"\x06bounds\x18\x04 \x01(\v2\n" +
+	".urbis.MBRR\x06bounds\x12\x18\n" +
+	"\acreated\x18\x05 \x01(\bR\acreated\"0\n" +
 	"\x13DestroyIndexRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\"0\n" +
 	"\x14DestroyIndexResponse\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\"\x14\n" +
 	"\x12ListIndexesRequest\"2\n" +
 	"\x13ListIndexesResponse\x12\x1b\n" +
-	"\tindex_ids\x18\x01 \x03(\tR\bindexIds\"C\n" +
+	"\tindex_ids\x18\x01 \x03(\tR\bindexIds\"1\n" +
+	"\x14DescribeIndexRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\"\x93\x02\n" +
+	"\x15DescribeIndexResponse\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12%\n" +
+	"\x06config\x18\x02 \x01(\v2\r.urbis.ConfigR\x06config\x12\x14\n" +
+	"\x05built\x18\x03 \x01(\bR\x05built\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x04R\x05count\x12\"\n" +
+	"\x06bounds\x18\x05 \x01(\v2\n" +
+	".urbis.MBRR\x06bounds\x12\"\n" +
+	"\x05stats\x18\x06 \x01(\v2\f.urbis.StatsR\x05stats\x12\x1b\n" +
+	"\tread_only\x18\a \x01(\bR\breadOnly\x12'\n" +
+	"\x0fpending_changes\x18\b \x01(\x04R\x0ependingChanges\"0\n" +
+	"\x13MarkReadOnlyRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\":\n" +
+	"\x14MarkReadOnlyResponse\x12\"\n" +
+	"\rwas_read_only\x18\x01 \x01(\bR\vwasReadOnly\"{\n" +
 	"\x12LoadGeoJSONRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x12\n" +
-	"\x04path\x18\x02 \x01(\tR\x04path\"O\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1d\n" +
+	"\n" +
+	"source_crs\x18\x03 \x01(\x05R\tsourceCrs\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"|\n" +
+	"\x15LoadGeoJSONURLRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1d\n" +
+	"\n" +
+	"source_crs\x18\x03 \x01(\x05R\tsourceCrs\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"\x87\x01\n" +
 	"\x18LoadGeoJSONStringRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x18\n" +
-	"\ageojson\x18\x02 \x01(\tR\ageojson\"=\n" +
+	"\ageojson\x18\x02 \x01(\tR\ageojson\x12\x1d\n" +
+	"\n" +
+	"source_crs\x18\x03 \x01(\x05R\tsourceCrs\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"=\n" +
 	"\x0eLoadWKTRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x10\n" +
-	"\x03wkt\x18\x02 \x01(\tR\x03wkt\"O\n" +
+	"\x03wkt\x18\x02 \x01(\tR\x03wkt\"=\n" +
+	"\x0eLoadWKBRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x10\n" +
+	"\x03wkb\x18\x02 \x01(\fR\x03wkb\"\\\n" +
+	"\x15LoadGeoPackageRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x14\n" +
+	"\x05layer\x18\x03 \x01(\tR\x05layer\"K\n" +
+	"\x18StreamLoadGeoJSONRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x14\n" +
+	"\x05chunk\x18\x02 \x01(\fR\x05chunk\"G\n" +
+	"\x14StreamLoadWKTRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x14\n" +
+	"\x05chunk\x18\x02 \x01(\fR\x05chunk\"\xf3\x01\n" +
 	"\fLoadResponse\x12%\n" +
 	"\x0eobjects_loaded\x18\x01 \x01(\x04R\robjectsLoaded\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"K\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x04R\x05count\x12\"\n" +
+	"\x06bounds\x18\x04 \x01(\v2\n" +
+	".urbis.MBRR\x06bounds\x12\x12\n" +
+	"\x04srid\x18\x05 \x01(\x05R\x04srid\x12\x18\n" +
+	"\askipped\x18\x06 \x01(\x04R\askipped\x12\x16\n" +
+	"\x06errors\x18\a \x03(\tR\x06errors\x12\"\n" +
+	"\rout_of_bounds\x18\b \x01(\x04R\voutOfBounds\"\xb1\x01\n" +
 	"\x12InsertPointRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\f\n" +
 	"\x01x\x18\x02 \x01(\x01R\x01x\x12\f\n" +
-	"\x01y\x18\x03 \x01(\x01R\x01y\"Z\n" +
+	"\x01y\x18\x03 \x01(\x01R\x01y\x12\x1b\n" +
+	"\tobject_id\x18\x04 \x01(\x04R\bobjectId\x12\x15\n" +
+	"\x06ttl_ms\x18\x05 \x01(\x04R\x05ttlMs\x12\x11\n" +
+	"\x01z\x18\x06 \x01(\x01H\x00R\x01z\x88\x01\x01\x12\x11\n" +
+	"\x01m\x18\a \x01(\x01H\x01R\x01m\x88\x01\x01B\x04\n" +
+	"\x02_zB\x04\n" +
+	"\x02_m\"\x8e\x01\n" +
 	"\x17InsertLineStringRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12$\n" +
-	"\x06points\x18\x02 \x03(\v2\f.urbis.PointR\x06points\"[\n" +
+	"\x06points\x18\x02 \x03(\v2\f.urbis.PointR\x06points\x12\x1b\n" +
+	"\tobject_id\x18\x03 \x01(\x04R\bobjectId\x12\x15\n" +
+	"\x06ttl_ms\x18\x04 \x01(\x04R\x05ttlMs\"\x8f\x01\n" +
 	"\x14InsertPolygonRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12(\n" +
-	"\bexterior\x18\x02 \x03(\v2\f.urbis.PointR\bexterior\"-\n" +
+	"\bexterior\x18\x02 \x03(\v2\f.urbis.PointR\bexterior\x12\x1b\n" +
+	"\tobject_id\x18\x03 \x01(\x04R\bobjectId\x12\x15\n" +
+	"\x06ttl_ms\x18\x04 \x01(\x04R\x05ttlMs\"\xba\x01\n" +
 	"\x0eInsertResponse\x12\x1b\n" +
-	"\tobject_id\x18\x01 \x01(\x04R\bobjectId\"G\n" +
+	"\tobject_id\x18\x01 \x01(\x04R\bobjectId\x12\x1e\n" +
+	"\bis_valid\x18\x02 \x01(\bH\x00R\aisValid\x88\x01\x01\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1c\n" +
+	"\x03mbr\x18\x04 \x01(\v2\n" +
+	".urbis.MBRR\x03mbr\x12(\n" +
+	"\bcentroid\x18\x05 \x01(\v2\f.urbis.PointR\bcentroidB\v\n" +
+	"\t_is_valid\"\xeb\x01\n" +
+	"\x13StreamInsertRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12$\n" +
+	"\x05point\x18\x02 \x01(\v2\f.urbis.PointH\x00R\x05point\x12'\n" +
+	"\x04line\x18\x03 \x01(\v2\x11.urbis.LineStringH\x00R\x04line\x12*\n" +
+	"\apolygon\x18\x04 \x01(\v2\x0e.urbis.PolygonH\x00R\apolygon\x12\x1b\n" +
+	"\tobject_id\x18\x05 \x01(\x04R\bobjectId\x12\x15\n" +
+	"\x06ttl_ms\x18\x06 \x01(\x04R\x05ttlMsB\n" +
+	"\n" +
+	"\bgeometry\"\x8b\x01\n" +
+	"\x14StreamInsertResponse\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12-\n" +
+	"\x06result\x18\x02 \x01(\v2\x15.urbis.InsertResponseR\x06result\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\rR\x04code\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"G\n" +
 	"\rRemoveRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x1b\n" +
 	"\tobject_id\x18\x02 \x01(\x04R\bobjectId\"*\n" +
 	"\x0eRemoveResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"J\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"|\n" +
+	"\x12RemoveRangeRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\"\n" +
+	"\x06region\x18\x02 \x01(\v2\n" +
+	".urbis.MBRR\x06region\x12'\n" +
+	"\x05match\x18\x03 \x01(\x0e2\x11.urbis.RangeMatchR\x05match\"E\n" +
+	"\x13RemoveRangeResponse\x12\x18\n" +
+	"\aremoved\x18\x01 \x01(\x04R\aremoved\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\"0\n" +
+	"\x13SweepExpiredRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\"F\n" +
+	"\x14SweepExpiredResponse\x12\x18\n" +
+	"\aremoved\x18\x01 \x01(\x04R\aremoved\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\"s\n" +
 	"\x10GetObjectRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x1b\n" +
-	"\tobject_id\x18\x02 \x01(\x04R\bobjectId\"W\n" +
+	"\tobject_id\x18\x02 \x01(\x04R\bobjectId\x12'\n" +
+	"\x0finclude_version\x18\x03 \x01(\bR\x0eincludeVersion\"W\n" +
 	"\x11GetObjectResponse\x12,\n" +
 	"\x06object\x18\x01 \x01(\v2\x14.urbis.SpatialObjectR\x06object\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"{\n" +
+	"\x16BatchGetObjectsRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x1d\n" +
+	"\n" +
+	"object_ids\x18\x02 \x03(\x04R\tobjectIds\x12'\n" +
+	"\x0finclude_version\x18\x03 \x01(\bR\x0eincludeVersion\"_\n" +
+	"\x17BatchGetObjectsResponse\x12.\n" +
+	"\aobjects\x18\x01 \x03(\v2\x14.urbis.SpatialObjectR\aobjects\x12\x14\n" +
+	"\x05found\x18\x02 \x03(\bR\x05found\"n\n" +
+	"\x14SetPropertiesRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x1b\n" +
+	"\tobject_id\x18\x02 \x01(\x04R\bobjectId\x12\x1e\n" +
+	"\n" +
+	"properties\x18\x03 \x01(\fR\n" +
+	"properties\"1\n" +
+	"\x15SetPropertiesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"N\n" +
+	"\x14GetPropertiesRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x1b\n" +
+	"\tobject_id\x18\x02 \x01(\x04R\bobjectId\"M\n" +
+	"\x15GetPropertiesResponse\x12\x1e\n" +
+	"\n" +
+	"properties\x18\x01 \x01(\fR\n" +
+	"properties\x12\x14\n" +
 	"\x05found\x18\x02 \x01(\bR\x05found\")\n" +
 	"\fBuildRequest\x12\x19\n" +
-	"\bindex_id\x18\x01 \x01(\tR\aindexId\"M\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\"\xac\x01\n" +
 	"\rBuildResponse\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\"\n" +
-	"\rbuild_time_ms\x18\x02 \x01(\x01R\vbuildTimeMs\",\n" +
+	"\rbuild_time_ms\x18\x02 \x01(\x01R\vbuildTimeMs\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x04R\x05count\x12\"\n" +
+	"\x06bounds\x18\x04 \x01(\v2\n" +
+	".urbis.MBRR\x06bounds\x12#\n" +
+	"\rbuild_threads\x18\x05 \x01(\x04R\fbuildThreads\"\x89\x01\n" +
+	"\x15BuildProgressResponse\x12\x12\n" +
+	"\x04done\x18\x01 \x01(\x04R\x04done\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x04R\x05total\x12\x18\n" +
+	"\apercent\x18\x03 \x01(\x01R\apercent\x12,\n" +
+	"\x06result\x18\x04 \x01(\v2\x14.urbis.BuildResponseR\x06result\",\n" +
 	"\x0fOptimizeRequest\x12\x19\n" +
-	"\bindex_id\x18\x01 \x01(\tR\aindexId\",\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\"\xe0\x01\n" +
 	"\x10OptimizeResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"P\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12$\n" +
+	"\x06before\x18\x02 \x01(\v2\f.urbis.StatsR\x06before\x12\"\n" +
+	"\x05after\x18\x03 \x01(\v2\f.urbis.StatsR\x05after\x124\n" +
+	"\x16estimated_seeks_before\x18\x04 \x01(\x04R\x14estimatedSeeksBefore\x122\n" +
+	"\x15estimated_seeks_after\x18\x05 \x01(\x04R\x13estimatedSeeksAfter\"+\n" +
+	"\x0eCompactRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\"u\n" +
+	"\x0fCompactResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12$\n" +
+	"\x06before\x18\x02 \x01(\v2\f.urbis.StatsR\x06before\x12\"\n" +
+	"\x05after\x18\x03 \x01(\v2\f.urbis.StatsR\x05after\"u\n" +
+	"\x0fAutoTuneRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x121\n" +
+	"\x0esample_queries\x18\x02 \x03(\v2\n" +
+	".urbis.MBRR\rsampleQueries\x12\x14\n" +
+	"\x05apply\x18\x03 \x01(\bR\x05apply\"\xd1\x01\n" +
+	"\rTuneCandidate\x12#\n" +
+	"\rpage_capacity\x18\x01 \x01(\x04R\fpageCapacity\x12\x1f\n" +
+	"\vtotal_pages\x18\x02 \x01(\x04R\n" +
+	"totalPages\x12)\n" +
+	"\x10page_utilization\x18\x03 \x01(\x01R\x0fpageUtilization\x12\x1b\n" +
+	"\tavg_seeks\x18\x04 \x01(\x01R\bavgSeeks\x12\x12\n" +
+	"\x04cost\x18\x05 \x01(\x01R\x04cost\x12\x1e\n" +
+	"\vavg_cost_ms\x18\x06 \x01(\x01R\tavgCostMs\"\x87\x01\n" +
+	"\x10AutoTuneResponse\x12#\n" +
+	"\rpage_capacity\x18\x01 \x01(\x04R\fpageCapacity\x124\n" +
+	"\n" +
+	"candidates\x18\x02 \x03(\v2\x14.urbis.TuneCandidateR\n" +
+	"candidates\x12\x18\n" +
+	"\aapplied\x18\x03 \x01(\bR\aapplied\"\x82\x04\n" +
 	"\x11RangeQueryRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12 \n" +
 	"\x05range\x18\x02 \x01(\v2\n" +
-	".urbis.MBRR\x05range\"J\n" +
+	".urbis.MBRR\x05range\x123\n" +
+	"\tstructure\x18\x03 \x01(\x0e2\x15.urbis.IndexStructureR\tstructure\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\rR\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\x12)\n" +
+	"\asort_by\x18\x06 \x01(\x0e2\x10.urbis.RangeSortR\x06sortBy\x12'\n" +
+	"\x0finclude_version\x18\a \x01(\bR\x0eincludeVersion\x123\n" +
+	"\bencoding\x18\b \x01(\x0e2\x17.urbis.GeometryEncodingR\bencoding\x12\x1f\n" +
+	"\vbest_effort\x18\t \x01(\bR\n" +
+	"bestEffort\x121\n" +
+	"\n" +
+	"field_mask\x18\n" +
+	" \x03(\x0e2\x12.urbis.ObjectFieldR\tfieldMask\x12.\n" +
+	"\n" +
+	"geom_types\x18\v \x03(\x0e2\x0f.urbis.GeomTypeR\tgeomTypes\x12\x1b\n" +
+	"\tquery_crs\x18\f \x01(\x05R\bqueryCrs\x12#\n" +
+	"\rinclude_pages\x18\r \x01(\bR\fincludePages\"S\n" +
+	"\x14EstimateCountRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12 \n" +
+	"\x05range\x18\x02 \x01(\v2\n" +
+	".urbis.MBRR\x05range\"@\n" +
+	"\x15EstimateCountResponse\x12'\n" +
+	"\x0festimated_count\x18\x01 \x01(\x04R\x0eestimatedCount\"\xef\x02\n" +
+	"\x16MultiRangeQueryRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\"\n" +
+	"\x06ranges\x18\x02 \x03(\v2\n" +
+	".urbis.MBRR\x06ranges\x123\n" +
+	"\tstructure\x18\x03 \x01(\x0e2\x15.urbis.IndexStructureR\tstructure\x12 \n" +
+	"\vdeduplicate\x18\x04 \x01(\bR\vdeduplicate\x12'\n" +
+	"\x0finclude_version\x18\x05 \x01(\bR\x0eincludeVersion\x123\n" +
+	"\bencoding\x18\x06 \x01(\x0e2\x17.urbis.GeometryEncodingR\bencoding\x121\n" +
+	"\n" +
+	"field_mask\x18\a \x03(\x0e2\x12.urbis.ObjectFieldR\tfieldMask\x12.\n" +
+	"\n" +
+	"geom_types\x18\b \x03(\x0e2\x0f.urbis.GeomTypeR\tgeomTypes\"\xdb\x01\n" +
+	"\vRangeResult\x12.\n" +
+	"\aobjects\x18\x01 \x03(\v2\x14.urbis.SpatialObjectR\aobjects\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\x122\n" +
+	"\vquery_stats\x18\x03 \x01(\v2\x11.urbis.QueryStatsR\n" +
+	"queryStats\x12\x18\n" +
+	"\ageojson\x18\x04 \x01(\tR\ageojson\x12\x14\n" +
+	"\x05stale\x18\x05 \x01(\bR\x05stale\x12\"\n" +
+	"\rquery_time_ms\x18\x06 \x01(\x01R\vqueryTimeMs\"\xe0\x01\n" +
+	"\x12MultiQueryResponse\x12@\n" +
+	"\aresults\x18\x01 \x03(\v2&.urbis.MultiQueryResponse.ResultsEntryR\aresults\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\x12\"\n" +
+	"\rquery_time_ms\x18\x03 \x01(\x01R\vqueryTimeMs\x1aN\n" +
+	"\fResultsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\rR\x03key\x12(\n" +
+	"\x05value\x18\x02 \x01(\v2\x12.urbis.RangeResultR\x05value:\x028\x01\"\x87\x02\n" +
+	"\x16QueryRangeMultiRequest\x12\x1b\n" +
+	"\tindex_ids\x18\x01 \x03(\tR\bindexIds\x12 \n" +
+	"\x05range\x18\x02 \x01(\v2\n" +
+	".urbis.MBRR\x05range\x123\n" +
+	"\tstructure\x18\x03 \x01(\x0e2\x15.urbis.IndexStructureR\tstructure\x12 \n" +
+	"\vdeduplicate\x18\x04 \x01(\bR\vdeduplicate\x12'\n" +
+	"\x0finclude_version\x18\x05 \x01(\bR\x0eincludeVersion\x12.\n" +
+	"\n" +
+	"geom_types\x18\x06 \x03(\x0e2\x0f.urbis.GeomTypeR\tgeomTypes\"\xea\x01\n" +
+	"\x17QueryRangeMultiResponse\x12E\n" +
+	"\aresults\x18\x01 \x03(\v2+.urbis.QueryRangeMultiResponse.ResultsEntryR\aresults\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\x12\"\n" +
+	"\rquery_time_ms\x18\x03 \x01(\x01R\vqueryTimeMs\x1aN\n" +
+	"\fResultsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
+	"\x05value\x18\x02 \x01(\v2\x12.urbis.RangeResultR\x05value:\x028\x01\"\x9a\x02\n" +
+	"\x14PropertyQueryRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12'\n" +
+	"\x0finclude_version\x18\x04 \x01(\bR\x0eincludeVersion\x123\n" +
+	"\bencoding\x18\x05 \x01(\x0e2\x17.urbis.GeometryEncodingR\bencoding\x121\n" +
+	"\n" +
+	"field_mask\x18\x06 \x03(\x0e2\x12.urbis.ObjectFieldR\tfieldMask\x12.\n" +
+	"\n" +
+	"geom_types\x18\a \x03(\x0e2\x0f.urbis.GeomTypeR\tgeomTypes\"R\n" +
+	"\x11ConvexHullRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\"\n" +
+	"\x06region\x18\x02 \x01(\v2\n" +
+	".urbis.MBRR\x06region\"6\n" +
+	"\x12ConvexHullResponse\x12 \n" +
+	"\x04hull\x18\x01 \x03(\v2\f.urbis.PointR\x04hull\"\xdd\x02\n" +
 	"\x11PointQueryRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\f\n" +
 	"\x01x\x18\x02 \x01(\x01R\x01x\x12\f\n" +
-	"\x01y\x18\x03 \x01(\x01R\x01y\"V\n" +
+	"\x01y\x18\x03 \x01(\x01R\x01y\x123\n" +
+	"\tstructure\x18\x04 \x01(\x0e2\x15.urbis.IndexStructureR\tstructure\x12'\n" +
+	"\x0finclude_version\x18\x05 \x01(\bR\x0eincludeVersion\x123\n" +
+	"\bencoding\x18\x06 \x01(\x0e2\x17.urbis.GeometryEncodingR\bencoding\x121\n" +
+	"\n" +
+	"field_mask\x18\a \x03(\x0e2\x12.urbis.ObjectFieldR\tfieldMask\x12.\n" +
+	"\n" +
+	"geom_types\x18\b \x03(\x0e2\x0f.urbis.GeomTypeR\tgeomTypes\x12\x1b\n" +
+	"\tquery_crs\x18\t \x01(\x05R\bqueryCrs\"\xbe\x02\n" +
+	"\x12BufferQueryRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x120\n" +
+	"\bgeometry\x18\x02 \x01(\v2\x14.urbis.SpatialObjectR\bgeometry\x12\x1a\n" +
+	"\bdistance\x18\x03 \x01(\x01R\bdistance\x12'\n" +
+	"\x0finclude_version\x18\x04 \x01(\bR\x0eincludeVersion\x123\n" +
+	"\bencoding\x18\x05 \x01(\x0e2\x17.urbis.GeometryEncodingR\bencoding\x121\n" +
+	"\n" +
+	"field_mask\x18\x06 \x03(\x0e2\x12.urbis.ObjectFieldR\tfieldMask\x12.\n" +
+	"\n" +
+	"geom_types\x18\a \x03(\x0e2\x0f.urbis.GeomTypeR\tgeomTypes\"\xa3\x02\n" +
+	"\x1dContainingPolygonQueryRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12&\n" +
+	"\apolygon\x18\x02 \x03(\v2\f.urbis.PointR\apolygon\x12'\n" +
+	"\x0finclude_version\x18\x03 \x01(\bR\x0eincludeVersion\x123\n" +
+	"\bencoding\x18\x04 \x01(\x0e2\x17.urbis.GeometryEncodingR\bencoding\x121\n" +
+	"\n" +
+	"field_mask\x18\x05 \x03(\x0e2\x12.urbis.ObjectFieldR\tfieldMask\x12.\n" +
+	"\n" +
+	"geom_types\x18\x06 \x03(\x0e2\x0f.urbis.GeomTypeR\tgeomTypes\"\xc4\x02\n" +
 	"\x0fKNNQueryRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\f\n" +
 	"\x01x\x18\x02 \x01(\x01R\x01x\x12\f\n" +
 	"\x01y\x18\x03 \x01(\x01R\x01y\x12\f\n" +
-	"\x01k\x18\x04 \x01(\rR\x01k\"y\n" +
+	"\x01k\x18\x04 \x01(\rR\x01k\x12'\n" +
+	"\x0finclude_version\x18\x05 \x01(\bR\x0eincludeVersion\x123\n" +
+	"\bencoding\x18\x06 \x01(\x0e2\x17.urbis.GeometryEncodingR\bencoding\x121\n" +
+	"\n" +
+	"field_mask\x18\a \x03(\x0e2\x12.urbis.ObjectFieldR\tfieldMask\x12\x1b\n" +
+	"\tquery_crs\x18\b \x01(\x05R\bqueryCrs\x12>\n" +
+	"\x0fdistance_metric\x18\t \x01(\x0e2\x15.urbis.DistanceMetricR\x0edistanceMetric\"p\n" +
+	"\x0eNearestRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\f\n" +
+	"\x01x\x18\x02 \x01(\x01R\x01x\x12\f\n" +
+	"\x01y\x18\x03 \x01(\x01R\x01y\x12'\n" +
+	"\x0finclude_version\x18\x04 \x01(\bR\x0eincludeVersion\"\x7f\n" +
+	"\x0fNearestResponse\x12,\n" +
+	"\x06object\x18\x01 \x01(\v2\x14.urbis.SpatialObjectR\x06object\x12\x1a\n" +
+	"\bdistance\x18\x02 \x01(\x01R\bdistance\x12\"\n" +
+	"\rquery_time_ms\x18\x03 \x01(\x01R\vqueryTimeMs\"\xb3\x01\n" +
+	"\x13ChangedSinceRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x19\n" +
+	"\bsince_ms\x18\x02 \x01(\x03R\asinceMs\x123\n" +
+	"\bencoding\x18\x03 \x01(\x0e2\x17.urbis.GeometryEncodingR\bencoding\x121\n" +
+	"\n" +
+	"field_mask\x18\x04 \x03(\x0e2\x12.urbis.ObjectFieldR\tfieldMask\"O\n" +
+	"\x13SnapshotScanRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x02 \x01(\rR\tbatchSize\"\x82\x01\n" +
+	"\x14SnapshotScanResponse\x12.\n" +
+	"\aobjects\x18\x01 \x03(\v2\x14.urbis.SpatialObjectR\aobjects\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x04R\x05total\x12$\n" +
+	"\x0esnapshot_at_ms\x18\x03 \x01(\x03R\fsnapshotAtMs\"\xdc\x02\n" +
+	"\n" +
+	"QueryStats\x12#\n" +
+	"\rpages_visited\x18\x01 \x01(\x04R\fpagesVisited\x12%\n" +
+	"\x0etracks_visited\x18\x02 \x01(\x04R\rtracksVisited\x12'\n" +
+	"\x0festimated_seeks\x18\x03 \x01(\x04R\x0eestimatedSeeks\x12\x1d\n" +
+	"\n" +
+	"cache_hits\x18\x04 \x01(\x04R\tcacheHits\x12!\n" +
+	"\fcache_misses\x18\x05 \x01(\x04R\vcacheMisses\x123\n" +
+	"\tstructure\x18\x06 \x01(\x0e2\x15.urbis.IndexStructureR\tstructure\x12-\n" +
+	"\x12structure_fallback\x18\a \x01(\bR\x11structureFallback\x123\n" +
+	"\x15duplicates_suppressed\x18\b \x01(\x04R\x14duplicatesSuppressed\"\x90\x03\n" +
 	"\rQueryResponse\x12.\n" +
 	"\aobjects\x18\x01 \x03(\v2\x14.urbis.SpatialObjectR\aobjects\x12\x14\n" +
 	"\x05count\x18\x02 \x01(\x04R\x05count\x12\"\n" +
-	"\rquery_time_ms\x18\x03 \x01(\x01R\vqueryTimeMs\"U\n" +
+	"\rquery_time_ms\x18\x03 \x01(\x01R\vqueryTimeMs\x122\n" +
+	"\vquery_stats\x18\x04 \x01(\v2\x11.urbis.QueryStatsR\n" +
+	"queryStats\x12\x1f\n" +
+	"\vnext_cursor\x18\x05 \x01(\tR\n" +
+	"nextCursor\x12\x1a\n" +
+	"\bwarnings\x18\x06 \x03(\tR\bwarnings\x12\x18\n" +
+	"\ageojson\x18\a \x01(\tR\ageojson\x12\x1c\n" +
+	"\tdistances\x18\b \x03(\x01R\tdistances\x12\x14\n" +
+	"\x05stale\x18\t \x01(\bR\x05stale\x12%\n" +
+	"\x05pages\x18\n" +
+	" \x03(\v2\x0f.urbis.PageInfoR\x05pages\x12/\n" +
+	"\rresult_bounds\x18\v \x01(\v2\n" +
+	".urbis.MBRR\fresultBounds\"U\n" +
 	"\x14AdjacentPagesRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\"\n" +
 	"\x06region\x18\x02 \x01(\v2\n" +
-	".urbis.MBRR\x06region\"}\n" +
+	".urbis.MBRR\x06region\"\xa9\x01\n" +
 	"\x15AdjacentPagesResponse\x12%\n" +
 	"\x05pages\x18\x01 \x03(\v2\x0f.urbis.PageInfoR\x05pages\x12\x14\n" +
 	"\x05count\x18\x02 \x01(\x04R\x05count\x12'\n" +
-	"\x0festimated_seeks\x18\x03 \x01(\x04R\x0eestimatedSeeks\")\n" +
+	"\x0festimated_seeks\x18\x03 \x01(\x04R\x0eestimatedSeeks\x12*\n" +
+	"\x11estimated_cost_ms\x18\x04 \x01(\x01R\x0festimatedCostMs\"[\n" +
+	"\x10PageGraphRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\rR\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"s\n" +
+	"\bPageEdge\x12 \n" +
+	"\ffrom_page_id\x18\x01 \x01(\rR\n" +
+	"fromPageId\x12\x1c\n" +
+	"\n" +
+	"to_page_id\x18\x02 \x01(\rR\btoPageId\x12'\n" +
+	"\x0festimated_seeks\x18\x03 \x01(\x04R\x0eestimatedSeeks\"\xc4\x01\n" +
+	"\x11PageGraphResponse\x12%\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x0f.urbis.PageInfoR\x05nodes\x12%\n" +
+	"\x05edges\x18\x02 \x03(\v2\x0f.urbis.PageEdgeR\x05edges\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\x12\x1f\n" +
+	"\vtotal_nodes\x18\x04 \x01(\x04R\n" +
+	"totalNodes\x12\x1f\n" +
+	"\vtotal_edges\x18\x05 \x01(\x04R\n" +
+	"totalEdges\"\x83\x01\n" +
+	"\x14TreeStructureRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x123\n" +
+	"\tstructure\x18\x02 \x01(\x0e2\x15.urbis.IndexStructureR\tstructure\x12\x1b\n" +
+	"\tmax_depth\x18\x03 \x01(\rR\bmaxDepth\"\xf2\x01\n" +
+	"\bTreeNode\x12\x14\n" +
+	"\x05depth\x18\x01 \x01(\rR\x05depth\x12\x16\n" +
+	"\x06parent\x18\x02 \x01(\x05R\x06parent\x12\"\n" +
+	"\x06bounds\x18\x03 \x01(\v2\n" +
+	".urbis.MBRR\x06bounds\x12!\n" +
+	"\fobject_count\x18\x04 \x01(\x04R\vobjectCount\x12\x1d\n" +
+	"\n" +
+	"item_count\x18\x05 \x01(\x04R\titemCount\x12\x1d\n" +
+	"\n" +
+	"split_axis\x18\x06 \x01(\x05R\tsplitAxis\x12\x1f\n" +
+	"\vsplit_value\x18\a \x01(\x01R\n" +
+	"splitValue\x12\x12\n" +
+	"\x04leaf\x18\b \x01(\bR\x04leaf\">\n" +
+	"\x15TreeStructureResponse\x12%\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x0f.urbis.TreeNodeR\x05nodes\"V\n" +
+	"\x15PrefetchRegionRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\"\n" +
+	"\x06region\x18\x02 \x01(\v2\n" +
+	".urbis.MBRR\x06region\"2\n" +
+	"\x16PrefetchRegionResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\".\n" +
+	"\x11IndexReadyRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\"B\n" +
+	"\x12IndexReadyResponse\x12\x16\n" +
+	"\x06exists\x18\x01 \x01(\bR\x06exists\x12\x14\n" +
+	"\x05built\x18\x02 \x01(\bR\x05built\"\x13\n" +
+	"\x11ServerInfoRequest\"\x81\x02\n" +
+	"\x12ServerInfoResponse\x12'\n" +
+	"\x0flibrary_version\x18\x01 \x01(\tR\x0elibraryVersion\x12%\n" +
+	"\x0eserver_version\x18\x02 \x01(\tR\rserverVersion\x12\x1b\n" +
+	"\tuptime_ms\x18\x03 \x01(\x04R\buptimeMs\x12\x1f\n" +
+	"\vindex_count\x18\x04 \x01(\x04R\n" +
+	"indexCount\x12-\n" +
+	"\x12reflection_enabled\x18\x05 \x01(\bR\x11reflectionEnabled\x12.\n" +
+	"\x13file_format_version\x18\x06 \x01(\rR\x11fileFormatVersion\")\n" +
 	"\fStatsRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\"3\n" +
 	"\rStatsResponse\x12\"\n" +
-	"\x05stats\x18\x01 \x01(\v2\f.urbis.StatsR\x05stats\")\n" +
+	"\x05stats\x18\x01 \x01(\v2\f.urbis.StatsR\x05stats\"\x16\n" +
+	"\x14ResourceStatsRequest\"\xb1\x01\n" +
+	"\x15ResourceStatsResponse\x12!\n" +
+	"\fopen_indexes\x18\x01 \x01(\x04R\vopenIndexes\x12!\n" +
+	"\fnative_bytes\x18\x02 \x01(\x04R\vnativeBytes\x12+\n" +
+	"\x11finalized_indexes\x18\x03 \x01(\x04R\x10finalizedIndexes\x12%\n" +
+	"\x0eleaked_origins\x18\x04 \x03(\tR\rleakedOrigins\")\n" +
 	"\fCountRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\"%\n" +
 	"\rCountResponse\x12\x14\n" +
 	"\x05count\x18\x01 \x01(\x04R\x05count\"*\n" +
 	"\rBoundsRequest\x12\x19\n" +
-	"\bindex_id\x18\x01 \x01(\tR\aindexId\"4\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\"\xa9\x01\n" +
 	"\x0eBoundsResponse\x12\"\n" +
 	"\x06bounds\x18\x01 \x01(\v2\n" +
-	".urbis.MBRR\x06bounds\"<\n" +
+	".urbis.MBRR\x06bounds\x12&\n" +
+	"\fwidth_meters\x18\x02 \x01(\x01H\x00R\vwidthMeters\x88\x01\x01\x12(\n" +
+	"\rheight_meters\x18\x03 \x01(\x01H\x01R\fheightMeters\x88\x01\x01B\x0f\n" +
+	"\r_width_metersB\x10\n" +
+	"\x0e_height_meters\"<\n" +
 	"\vSaveRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x12\n" +
-	"\x04path\x18\x02 \x01(\tR\x04path\"(\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\"<\n" +
 	"\fSaveResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"A\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\"(\n" +
+	"\vSyncRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\"E\n" +
+	"\fSyncResponse\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12!\n" +
+	"\fchange_count\x18\x02 \x01(\x04R\vchangeCount\"^\n" +
 	"\x10LoadIndexRequest\x12\x19\n" +
 	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x12\n" +
-	"\x04path\x18\x02 \x01(\tR\x04path\"-\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1b\n" +
+	"\tread_only\x18\x03 \x01(\bR\breadOnly\"g\n" +
 	"\x11LoadIndexResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage*A\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\x12\"\n" +
+	"\x06bounds\x18\x03 \x01(\v2\n" +
+	".urbis.MBRR\x06bounds\".\n" +
+	"\x11StreamSaveRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\"\"\n" +
+	"\n" +
+	"IndexChunk\x12\x14\n" +
+	"\x05chunk\x18\x01 \x01(\fR\x05chunk\"D\n" +
+	"\x11StreamLoadRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x14\n" +
+	"\x05chunk\x18\x02 \x01(\fR\x05chunk\"\xd6\x01\n" +
+	"\x12ReloadIndexRequest\x12\x19\n" +
+	"\bindex_id\x18\x01 \x01(\tR\aindexId\x12\x1d\n" +
+	"\tdata_file\x18\x02 \x01(\tH\x00R\bdataFile\x12#\n" +
+	"\fgeojson_path\x18\x03 \x01(\tH\x00R\vgeojsonPath\x12\x1a\n" +
+	"\ageojson\x18\x04 \x01(\tH\x00R\ageojson\x12%\n" +
+	"\x06config\x18\x05 \x01(\v2\r.urbis.ConfigR\x06config\x12\x14\n" +
+	"\x05build\x18\x06 \x01(\bR\x05buildB\b\n" +
+	"\x06source\"i\n" +
+	"\x13ReloadIndexResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\x12\"\n" +
+	"\x06bounds\x18\x03 \x01(\v2\n" +
+	".urbis.MBRR\x06bounds*\xa4\x01\n" +
 	"\bGeomType\x12\x0e\n" +
 	"\n" +
 	"GEOM_POINT\x10\x00\x12\x13\n" +
 	"\x0fGEOM_LINESTRING\x10\x01\x12\x10\n" +
-	"\fGEOM_POLYGON\x10\x022\xb8\v\n" +
+	"\fGEOM_POLYGON\x10\x02\x12\x13\n" +
+	"\x0fGEOM_MULTIPOINT\x10\x03\x12\x18\n" +
+	"\x14GEOM_MULTILINESTRING\x10\x04\x12\x15\n" +
+	"\x11GEOM_MULTIPOLYGON\x10\x05\x12\x1b\n" +
+	"\x17GEOM_GEOMETRYCOLLECTION\x10\x06*]\n" +
+	"\n" +
+	"RangeMatch\x12\x19\n" +
+	"\x15RANGE_MATCH_CONTAINED\x10\x00\x12\x18\n" +
+	"\x14RANGE_MATCH_CENTROID\x10\x01\x12\x1a\n" +
+	"\x16RANGE_MATCH_INTERSECTS\x10\x02*~\n" +
+	"\x0eIndexStructure\x12\x18\n" +
+	"\x14INDEX_STRUCTURE_AUTO\x10\x00\x12\x1a\n" +
+	"\x16INDEX_STRUCTURE_KDTREE\x10\x01\x12\x1c\n" +
+	"\x18INDEX_STRUCTURE_QUADTREE\x10\x02\x12\x18\n" +
+	"\x14INDEX_STRUCTURE_SCAN\x10\x03*m\n" +
+	"\x11PolygonValidation\x12\x1d\n" +
+	"\x19POLYGON_VALIDATION_REJECT\x10\x00\x12\x1d\n" +
+	"\x19POLYGON_VALIDATION_REPORT\x10\x01\x12\x1a\n" +
+	"\x16POLYGON_VALIDATION_OFF\x10\x02*\xa4\x01\n" +
+	"\fPropertyType\x12\x15\n" +
+	"\x11PROPERTY_TYPE_ANY\x10\x00\x12\x18\n" +
+	"\x14PROPERTY_TYPE_STRING\x10\x01\x12\x18\n" +
+	"\x14PROPERTY_TYPE_NUMBER\x10\x02\x12\x16\n" +
+	"\x12PROPERTY_TYPE_BOOL\x10\x03\x12\x18\n" +
+	"\x14PROPERTY_TYPE_OBJECT\x10\x04\x12\x17\n" +
+	"\x13PROPERTY_TYPE_ARRAY\x10\x05*J\n" +
+	"\vStorageKind\x12\x16\n" +
+	"\x12STORAGE_ROTATIONAL\x10\x00\x12\x0f\n" +
+	"\vSTORAGE_SSD\x10\x01\x12\x12\n" +
+	"\x0eSTORAGE_CUSTOM\x10\x02*q\n" +
+	"\tRangeSort\x12\x13\n" +
+	"\x0fRANGE_SORT_NONE\x10\x00\x12\x11\n" +
+	"\rRANGE_SORT_ID\x10\x01\x12#\n" +
+	"\x1fRANGE_SORT_DISTANCE_FROM_CENTER\x10\x02\x12\x17\n" +
+	"\x13RANGE_SORT_MBR_AREA\x10\x03*t\n" +
+	"\x0eDistanceMetric\x12\x1c\n" +
+	"\x18DISTANCE_METRIC_CENTROID\x10\x00\x12 \n" +
+	"\x1cDISTANCE_METRIC_NEAREST_EDGE\x10\x01\x12\"\n" +
+	"\x1eDISTANCE_METRIC_NEAREST_VERTEX\x10\x02*\xa9\x01\n" +
+	"\vObjectField\x12\x1c\n" +
+	"\x18OBJECT_FIELD_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fOBJECT_FIELD_ID\x10\x01\x12\x19\n" +
+	"\x15OBJECT_FIELD_GEOMETRY\x10\x02\x12\x14\n" +
+	"\x10OBJECT_FIELD_MBR\x10\x03\x12\x19\n" +
+	"\x15OBJECT_FIELD_CENTROID\x10\x04\x12\x1b\n" +
+	"\x17OBJECT_FIELD_PROPERTIES\x10\x05*\x8e\x01\n" +
+	"\x10GeometryEncoding\x12 \n" +
+	"\x1cGEOMETRY_ENCODING_STRUCTURED\x10\x00\x12\x19\n" +
+	"\x15GEOMETRY_ENCODING_WKB\x10\x01\x12\x1e\n" +
+	"\x1aGEOMETRY_ENCODING_POLYLINE\x10\x02\x12\x1d\n" +
+	"\x19GEOMETRY_ENCODING_GEOJSON\x10\x032\xf3\x1f\n" +
 	"\fUrbisService\x12D\n" +
 	"\vCreateIndex\x12\x19.urbis.CreateIndexRequest\x1a\x1a.urbis.CreateIndexResponse\x12G\n" +
 	"\fDestroyIndex\x12\x1a.urbis.DestroyIndexRequest\x1a\x1b.urbis.DestroyIndexResponse\x12D\n" +
-	"\vListIndexes\x12\x19.urbis.ListIndexesRequest\x1a\x1a.urbis.ListIndexesResponse\x12=\n" +
+	"\vListIndexes\x12\x19.urbis.ListIndexesRequest\x1a\x1a.urbis.ListIndexesResponse\x12J\n" +
+	"\rDescribeIndex\x12\x1b.urbis.DescribeIndexRequest\x1a\x1c.urbis.DescribeIndexResponse\x12G\n" +
+	"\fMarkReadOnly\x12\x1a.urbis.MarkReadOnlyRequest\x1a\x1b.urbis.MarkReadOnlyResponse\x12=\n" +
 	"\vLoadGeoJSON\x12\x19.urbis.LoadGeoJSONRequest\x1a\x13.urbis.LoadResponse\x12I\n" +
-	"\x11LoadGeoJSONString\x12\x1f.urbis.LoadGeoJSONStringRequest\x1a\x13.urbis.LoadResponse\x125\n" +
-	"\aLoadWKT\x12\x15.urbis.LoadWKTRequest\x1a\x13.urbis.LoadResponse\x12?\n" +
+	"\x11LoadGeoJSONString\x12\x1f.urbis.LoadGeoJSONStringRequest\x1a\x13.urbis.LoadResponse\x12C\n" +
+	"\x0eLoadGeoJSONURL\x12\x1c.urbis.LoadGeoJSONURLRequest\x1a\x13.urbis.LoadResponse\x125\n" +
+	"\aLoadWKT\x12\x15.urbis.LoadWKTRequest\x1a\x13.urbis.LoadResponse\x125\n" +
+	"\aLoadWKB\x12\x15.urbis.LoadWKBRequest\x1a\x13.urbis.LoadResponse\x12C\n" +
+	"\x0eLoadGeoPackage\x12\x1c.urbis.LoadGeoPackageRequest\x1a\x13.urbis.LoadResponse\x12K\n" +
+	"\x11StreamLoadGeoJSON\x12\x1f.urbis.StreamLoadGeoJSONRequest\x1a\x13.urbis.LoadResponse(\x01\x12C\n" +
+	"\rStreamLoadWKT\x12\x1b.urbis.StreamLoadWKTRequest\x1a\x13.urbis.LoadResponse(\x01\x12?\n" +
 	"\vInsertPoint\x12\x19.urbis.InsertPointRequest\x1a\x15.urbis.InsertResponse\x12I\n" +
 	"\x10InsertLineString\x12\x1e.urbis.InsertLineStringRequest\x1a\x15.urbis.InsertResponse\x12C\n" +
-	"\rInsertPolygon\x12\x1b.urbis.InsertPolygonRequest\x1a\x15.urbis.InsertResponse\x125\n" +
-	"\x06Remove\x12\x14.urbis.RemoveRequest\x1a\x15.urbis.RemoveResponse\x12>\n" +
-	"\tGetObject\x12\x17.urbis.GetObjectRequest\x1a\x18.urbis.GetObjectResponse\x122\n" +
-	"\x05Build\x12\x13.urbis.BuildRequest\x1a\x14.urbis.BuildResponse\x12;\n" +
-	"\bOptimize\x12\x16.urbis.OptimizeRequest\x1a\x17.urbis.OptimizeResponse\x12<\n" +
+	"\rInsertPolygon\x12\x1b.urbis.InsertPolygonRequest\x1a\x15.urbis.InsertResponse\x12K\n" +
+	"\fStreamInsert\x12\x1a.urbis.StreamInsertRequest\x1a\x1b.urbis.StreamInsertResponse(\x010\x01\x125\n" +
+	"\x06Remove\x12\x14.urbis.RemoveRequest\x1a\x15.urbis.RemoveResponse\x12D\n" +
+	"\vRemoveRange\x12\x19.urbis.RemoveRangeRequest\x1a\x1a.urbis.RemoveRangeResponse\x12G\n" +
+	"\fSweepExpired\x12\x1a.urbis.SweepExpiredRequest\x1a\x1b.urbis.SweepExpiredResponse\x12>\n" +
+	"\tGetObject\x12\x17.urbis.GetObjectRequest\x1a\x18.urbis.GetObjectResponse\x12P\n" +
+	"\x0fBatchGetObjects\x12\x1d.urbis.BatchGetObjectsRequest\x1a\x1e.urbis.BatchGetObjectsResponse\x12J\n" +
+	"\rSetProperties\x12\x1b.urbis.SetPropertiesRequest\x1a\x1c.urbis.SetPropertiesResponse\x12J\n" +
+	"\rGetProperties\x12\x1b.urbis.GetPropertiesRequest\x1a\x1c.urbis.GetPropertiesResponse\x122\n" +
+	"\x05Build\x12\x13.urbis.BuildRequest\x1a\x14.urbis.BuildResponse\x12H\n" +
+	"\x11BuildWithProgress\x12\x13.urbis.BuildRequest\x1a\x1c.urbis.BuildProgressResponse0\x01\x12;\n" +
+	"\bOptimize\x12\x16.urbis.OptimizeRequest\x1a\x17.urbis.OptimizeResponse\x128\n" +
+	"\aCompact\x12\x15.urbis.CompactRequest\x1a\x16.urbis.CompactResponse\x12;\n" +
+	"\bAutoTune\x12\x16.urbis.AutoTuneRequest\x1a\x17.urbis.AutoTuneResponse\x12<\n" +
 	"\n" +
-	"QueryRange\x12\x18.urbis.RangeQueryRequest\x1a\x14.urbis.QueryResponse\x12<\n" +
+	"QueryRange\x12\x18.urbis.RangeQueryRequest\x1a\x14.urbis.QueryResponse\x12J\n" +
+	"\rEstimateCount\x12\x1b.urbis.EstimateCountRequest\x1a\x1c.urbis.EstimateCountResponse\x12K\n" +
+	"\x0fMultiQueryRange\x12\x1d.urbis.MultiRangeQueryRequest\x1a\x19.urbis.MultiQueryResponse\x12P\n" +
+	"\x0fQueryRangeMulti\x12\x1d.urbis.QueryRangeMultiRequest\x1a\x1e.urbis.QueryRangeMultiResponse\x12<\n" +
 	"\n" +
-	"QueryPoint\x12\x18.urbis.PointQueryRequest\x1a\x14.urbis.QueryResponse\x128\n" +
-	"\bQueryKNN\x12\x16.urbis.KNNQueryRequest\x1a\x14.urbis.QueryResponse\x12?\n" +
-	"\rQueryAdjacent\x12\x18.urbis.RangeQueryRequest\x1a\x14.urbis.QueryResponse\x12N\n" +
-	"\x11FindAdjacentPages\x12\x1b.urbis.AdjacentPagesRequest\x1a\x1c.urbis.AdjacentPagesResponse\x125\n" +
+	"QueryPoint\x12\x18.urbis.PointQueryRequest\x1a\x14.urbis.QueryResponse\x12A\n" +
+	"\x0fQueryContaining\x12\x18.urbis.PointQueryRequest\x1a\x14.urbis.QueryResponse\x12@\n" +
+	"\rQueryBuffered\x12\x19.urbis.BufferQueryRequest\x1a\x14.urbis.QueryResponse\x12T\n" +
+	"\x16QueryContainingPolygon\x12$.urbis.ContainingPolygonQueryRequest\x1a\x14.urbis.QueryResponse\x128\n" +
+	"\bQueryKNN\x12\x16.urbis.KNNQueryRequest\x1a\x14.urbis.QueryResponse\x128\n" +
+	"\aNearest\x12\x15.urbis.NearestRequest\x1a\x16.urbis.NearestResponse\x12?\n" +
+	"\rQueryAdjacent\x12\x18.urbis.RangeQueryRequest\x1a\x14.urbis.QueryResponse\x12E\n" +
+	"\x11QueryChangedSince\x12\x1a.urbis.ChangedSinceRequest\x1a\x14.urbis.QueryResponse\x12I\n" +
+	"\fSnapshotScan\x12\x1a.urbis.SnapshotScanRequest\x1a\x1b.urbis.SnapshotScanResponse0\x01\x12D\n" +
+	"\x0fQueryByProperty\x12\x1b.urbis.PropertyQueryRequest\x1a\x14.urbis.QueryResponse\x12A\n" +
+	"\n" +
+	"ConvexHull\x12\x18.urbis.ConvexHullRequest\x1a\x19.urbis.ConvexHullResponse\x12N\n" +
+	"\x11FindAdjacentPages\x12\x1b.urbis.AdjacentPagesRequest\x1a\x1c.urbis.AdjacentPagesResponse\x12M\n" +
+	"\x0ePrefetchRegion\x12\x1c.urbis.PrefetchRegionRequest\x1a\x1d.urbis.PrefetchRegionResponse\x12A\n" +
+	"\fGetPageGraph\x12\x17.urbis.PageGraphRequest\x1a\x18.urbis.PageGraphResponse\x12M\n" +
+	"\x10GetTreeStructure\x12\x1b.urbis.TreeStructureRequest\x1a\x1c.urbis.TreeStructureResponse\x12A\n" +
+	"\n" +
+	"IndexReady\x12\x18.urbis.IndexReadyRequest\x1a\x19.urbis.IndexReadyResponse\x12D\n" +
+	"\rGetServerInfo\x12\x18.urbis.ServerInfoRequest\x1a\x19.urbis.ServerInfoResponse\x125\n" +
 	"\bGetStats\x12\x13.urbis.StatsRequest\x1a\x14.urbis.StatsResponse\x125\n" +
 	"\bGetCount\x12\x13.urbis.CountRequest\x1a\x14.urbis.CountResponse\x128\n" +
-	"\tGetBounds\x12\x14.urbis.BoundsRequest\x1a\x15.urbis.BoundsResponse\x12/\n" +
+	"\tGetBounds\x12\x14.urbis.BoundsRequest\x1a\x15.urbis.BoundsResponse\x12M\n" +
+	"\x10GetResourceStats\x12\x1b.urbis.ResourceStatsRequest\x1a\x1c.urbis.ResourceStatsResponse\x12/\n" +
 	"\x04Save\x12\x12.urbis.SaveRequest\x1a\x13.urbis.SaveResponse\x129\n" +
-	"\x04Load\x12\x17.urbis.LoadIndexRequest\x1a\x18.urbis.LoadIndexResponseB\x1dZ\x1bgithub.com/urbis/api/pkg/pbb\x06proto3"
+	"\x04Load\x12\x17.urbis.LoadIndexRequest\x1a\x18.urbis.LoadIndexResponse\x12;\n" +
+	"\n" +
+	"StreamSave\x12\x18.urbis.StreamSaveRequest\x1a\x11.urbis.IndexChunk0\x01\x12B\n" +
+	"\n" +
+	"StreamLoad\x12\x18.urbis.StreamLoadRequest\x1a\x18.urbis.LoadIndexResponse(\x01\x12D\n" +
+	"\vReloadIndex\x12\x19.urbis.ReloadIndexRequest\x1a\x1a.urbis.ReloadIndexResponse\x12/\n" +
+	"\x04Sync\x12\x12.urbis.SyncRequest\x1a\x13.urbis.SyncResponseB\x1dZ\x1bgithub.com/urbis/api/pkg/pbb\x06proto3"
 
 var (
 	file_urbis_proto_rawDescOnce sync.Once
@@ -2808,131 +9171,382 @@ func file_urbis_proto_rawDescGZIP() []byte {
 	return file_urbis_proto_rawDescData
 }
 
-var file_urbis_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_urbis_proto_msgTypes = make([]protoimpl.MessageInfo, 47)
+var file_urbis_proto_enumTypes = make([]protoimpl.EnumInfo, 10)
+var file_urbis_proto_msgTypes = make([]protoimpl.MessageInfo, 121)
 var file_urbis_proto_goTypes = []any{
-	(GeomType)(0),                    // 0: urbis.GeomType
-	(*Point)(nil),                    // 1: urbis.Point
-	(*MBR)(nil),                      // 2: urbis.MBR
-	(*LineString)(nil),               // 3: urbis.LineString
-	(*Polygon)(nil),                  // 4: urbis.Polygon
-	(*Ring)(nil),                     // 5: urbis.Ring
-	(*SpatialObject)(nil),            // 6: urbis.SpatialObject
-	(*Config)(nil),                   // 7: urbis.Config
-	(*Stats)(nil),                    // 8: urbis.Stats
-	(*PageInfo)(nil),                 // 9: urbis.PageInfo
-	(*CreateIndexRequest)(nil),       // 10: urbis.CreateIndexRequest
-	(*CreateIndexResponse)(nil),      // 11: urbis.CreateIndexResponse
-	(*DestroyIndexRequest)(nil),      // 12: urbis.DestroyIndexRequest
-	(*DestroyIndexResponse)(nil),     // 13: urbis.DestroyIndexResponse
-	(*ListIndexesRequest)(nil),       // 14: urbis.ListIndexesRequest
-	(*ListIndexesResponse)(nil),      // 15: urbis.ListIndexesResponse
-	(*LoadGeoJSONRequest)(nil),       // 16: urbis.LoadGeoJSONRequest
-	(*LoadGeoJSONStringRequest)(nil), // 17: urbis.LoadGeoJSONStringRequest
-	(*LoadWKTRequest)(nil),           // 18: urbis.LoadWKTRequest
-	(*LoadResponse)(nil),             // 19: urbis.LoadResponse
-	(*InsertPointRequest)(nil),       // 20: urbis.InsertPointRequest
-	(*InsertLineStringRequest)(nil),  // 21: urbis.InsertLineStringRequest
-	(*InsertPolygonRequest)(nil),     // 22: urbis.InsertPolygonRequest
-	(*InsertResponse)(nil),           // 23: urbis.InsertResponse
-	(*RemoveRequest)(nil),            // 24: urbis.RemoveRequest
-	(*RemoveResponse)(nil),           // 25: urbis.RemoveResponse
-	(*GetObjectRequest)(nil),         // 26: urbis.GetObjectRequest
-	(*GetObjectResponse)(nil),        // 27: urbis.GetObjectResponse
-	(*BuildRequest)(nil),             // 28: urbis.BuildRequest
-	(*BuildResponse)(nil),            // 29: urbis.BuildResponse
-	(*OptimizeRequest)(nil),          // 30: urbis.OptimizeRequest
-	(*OptimizeResponse)(nil),         // 31: urbis.OptimizeResponse
-	(*RangeQueryRequest)(nil),        // 32: urbis.RangeQueryRequest
-	(*PointQueryRequest)(nil),        // 33: urbis.PointQueryRequest
-	(*KNNQueryRequest)(nil),          // 34: urbis.KNNQueryRequest
-	(*QueryResponse)(nil),            // 35: urbis.QueryResponse
-	(*AdjacentPagesRequest)(nil),     // 36: urbis.AdjacentPagesRequest
-	(*AdjacentPagesResponse)(nil),    // 37: urbis.AdjacentPagesResponse
-	(*StatsRequest)(nil),             // 38: urbis.StatsRequest
-	(*StatsResponse)(nil),            // 39: urbis.StatsResponse
-	(*CountRequest)(nil),             // 40: urbis.CountRequest
-	(*CountResponse)(nil),            // 41: urbis.CountResponse
-	(*BoundsRequest)(nil),            // 42: urbis.BoundsRequest
-	(*BoundsResponse)(nil),           // 43: urbis.BoundsResponse
-	(*SaveRequest)(nil),              // 44: urbis.SaveRequest
-	(*SaveResponse)(nil),             // 45: urbis.SaveResponse
-	(*LoadIndexRequest)(nil),         // 46: urbis.LoadIndexRequest
-	(*LoadIndexResponse)(nil),        // 47: urbis.LoadIndexResponse
+	(GeomType)(0),                         // 0: urbis.GeomType
+	(RangeMatch)(0),                       // 1: urbis.RangeMatch
+	(IndexStructure)(0),                   // 2: urbis.IndexStructure
+	(PolygonValidation)(0),                // 3: urbis.PolygonValidation
+	(PropertyType)(0),                     // 4: urbis.PropertyType
+	(StorageKind)(0),                      // 5: urbis.StorageKind
+	(RangeSort)(0),                        // 6: urbis.RangeSort
+	(DistanceMetric)(0),                   // 7: urbis.DistanceMetric
+	(ObjectField)(0),                      // 8: urbis.ObjectField
+	(GeometryEncoding)(0),                 // 9: urbis.GeometryEncoding
+	(*Point)(nil),                         // 10: urbis.Point
+	(*MBR)(nil),                           // 11: urbis.MBR
+	(*LineString)(nil),                    // 12: urbis.LineString
+	(*Polygon)(nil),                       // 13: urbis.Polygon
+	(*Ring)(nil),                          // 14: urbis.Ring
+	(*MultiPoint)(nil),                    // 15: urbis.MultiPoint
+	(*MultiLineString)(nil),               // 16: urbis.MultiLineString
+	(*MultiPolygon)(nil),                  // 17: urbis.MultiPolygon
+	(*GeometryCollection)(nil),            // 18: urbis.GeometryCollection
+	(*SpatialObject)(nil),                 // 19: urbis.SpatialObject
+	(*Config)(nil),                        // 20: urbis.Config
+	(*PropertyRule)(nil),                  // 21: urbis.PropertyRule
+	(*SeekCostModel)(nil),                 // 22: urbis.SeekCostModel
+	(*Stats)(nil),                         // 23: urbis.Stats
+	(*PageInfo)(nil),                      // 24: urbis.PageInfo
+	(*CreateIndexRequest)(nil),            // 25: urbis.CreateIndexRequest
+	(*CreateIndexResponse)(nil),           // 26: urbis.CreateIndexResponse
+	(*DestroyIndexRequest)(nil),           // 27: urbis.DestroyIndexRequest
+	(*DestroyIndexResponse)(nil),          // 28: urbis.DestroyIndexResponse
+	(*ListIndexesRequest)(nil),            // 29: urbis.ListIndexesRequest
+	(*ListIndexesResponse)(nil),           // 30: urbis.ListIndexesResponse
+	(*DescribeIndexRequest)(nil),          // 31: urbis.DescribeIndexRequest
+	(*DescribeIndexResponse)(nil),         // 32: urbis.DescribeIndexResponse
+	(*MarkReadOnlyRequest)(nil),           // 33: urbis.MarkReadOnlyRequest
+	(*MarkReadOnlyResponse)(nil),          // 34: urbis.MarkReadOnlyResponse
+	(*LoadGeoJSONRequest)(nil),            // 35: urbis.LoadGeoJSONRequest
+	(*LoadGeoJSONURLRequest)(nil),         // 36: urbis.LoadGeoJSONURLRequest
+	(*LoadGeoJSONStringRequest)(nil),      // 37: urbis.LoadGeoJSONStringRequest
+	(*LoadWKTRequest)(nil),                // 38: urbis.LoadWKTRequest
+	(*LoadWKBRequest)(nil),                // 39: urbis.LoadWKBRequest
+	(*LoadGeoPackageRequest)(nil),         // 40: urbis.LoadGeoPackageRequest
+	(*StreamLoadGeoJSONRequest)(nil),      // 41: urbis.StreamLoadGeoJSONRequest
+	(*StreamLoadWKTRequest)(nil),          // 42: urbis.StreamLoadWKTRequest
+	(*LoadResponse)(nil),                  // 43: urbis.LoadResponse
+	(*InsertPointRequest)(nil),            // 44: urbis.InsertPointRequest
+	(*InsertLineStringRequest)(nil),       // 45: urbis.InsertLineStringRequest
+	(*InsertPolygonRequest)(nil),          // 46: urbis.InsertPolygonRequest
+	(*InsertResponse)(nil),                // 47: urbis.InsertResponse
+	(*StreamInsertRequest)(nil),           // 48: urbis.StreamInsertRequest
+	(*StreamInsertResponse)(nil),          // 49: urbis.StreamInsertResponse
+	(*RemoveRequest)(nil),                 // 50: urbis.RemoveRequest
+	(*RemoveResponse)(nil),                // 51: urbis.RemoveResponse
+	(*RemoveRangeRequest)(nil),            // 52: urbis.RemoveRangeRequest
+	(*RemoveRangeResponse)(nil),           // 53: urbis.RemoveRangeResponse
+	(*SweepExpiredRequest)(nil),           // 54: urbis.SweepExpiredRequest
+	(*SweepExpiredResponse)(nil),          // 55: urbis.SweepExpiredResponse
+	(*GetObjectRequest)(nil),              // 56: urbis.GetObjectRequest
+	(*GetObjectResponse)(nil),             // 57: urbis.GetObjectResponse
+	(*BatchGetObjectsRequest)(nil),        // 58: urbis.BatchGetObjectsRequest
+	(*BatchGetObjectsResponse)(nil),       // 59: urbis.BatchGetObjectsResponse
+	(*SetPropertiesRequest)(nil),          // 60: urbis.SetPropertiesRequest
+	(*SetPropertiesResponse)(nil),         // 61: urbis.SetPropertiesResponse
+	(*GetPropertiesRequest)(nil),          // 62: urbis.GetPropertiesRequest
+	(*GetPropertiesResponse)(nil),         // 63: urbis.GetPropertiesResponse
+	(*BuildRequest)(nil),                  // 64: urbis.BuildRequest
+	(*BuildResponse)(nil),                 // 65: urbis.BuildResponse
+	(*BuildProgressResponse)(nil),         // 66: urbis.BuildProgressResponse
+	(*OptimizeRequest)(nil),               // 67: urbis.OptimizeRequest
+	(*OptimizeResponse)(nil),              // 68: urbis.OptimizeResponse
+	(*CompactRequest)(nil),                // 69: urbis.CompactRequest
+	(*CompactResponse)(nil),               // 70: urbis.CompactResponse
+	(*AutoTuneRequest)(nil),               // 71: urbis.AutoTuneRequest
+	(*TuneCandidate)(nil),                 // 72: urbis.TuneCandidate
+	(*AutoTuneResponse)(nil),              // 73: urbis.AutoTuneResponse
+	(*RangeQueryRequest)(nil),             // 74: urbis.RangeQueryRequest
+	(*EstimateCountRequest)(nil),          // 75: urbis.EstimateCountRequest
+	(*EstimateCountResponse)(nil),         // 76: urbis.EstimateCountResponse
+	(*MultiRangeQueryRequest)(nil),        // 77: urbis.MultiRangeQueryRequest
+	(*RangeResult)(nil),                   // 78: urbis.RangeResult
+	(*MultiQueryResponse)(nil),            // 79: urbis.MultiQueryResponse
+	(*QueryRangeMultiRequest)(nil),        // 80: urbis.QueryRangeMultiRequest
+	(*QueryRangeMultiResponse)(nil),       // 81: urbis.QueryRangeMultiResponse
+	(*PropertyQueryRequest)(nil),          // 82: urbis.PropertyQueryRequest
+	(*ConvexHullRequest)(nil),             // 83: urbis.ConvexHullRequest
+	(*ConvexHullResponse)(nil),            // 84: urbis.ConvexHullResponse
+	(*PointQueryRequest)(nil),             // 85: urbis.PointQueryRequest
+	(*BufferQueryRequest)(nil),            // 86: urbis.BufferQueryRequest
+	(*ContainingPolygonQueryRequest)(nil), // 87: urbis.ContainingPolygonQueryRequest
+	(*KNNQueryRequest)(nil),               // 88: urbis.KNNQueryRequest
+	(*NearestRequest)(nil),                // 89: urbis.NearestRequest
+	(*NearestResponse)(nil),               // 90: urbis.NearestResponse
+	(*ChangedSinceRequest)(nil),           // 91: urbis.ChangedSinceRequest
+	(*SnapshotScanRequest)(nil),           // 92: urbis.SnapshotScanRequest
+	(*SnapshotScanResponse)(nil),          // 93: urbis.SnapshotScanResponse
+	(*QueryStats)(nil),                    // 94: urbis.QueryStats
+	(*QueryResponse)(nil),                 // 95: urbis.QueryResponse
+	(*AdjacentPagesRequest)(nil),          // 96: urbis.AdjacentPagesRequest
+	(*AdjacentPagesResponse)(nil),         // 97: urbis.AdjacentPagesResponse
+	(*PageGraphRequest)(nil),              // 98: urbis.PageGraphRequest
+	(*PageEdge)(nil),                      // 99: urbis.PageEdge
+	(*PageGraphResponse)(nil),             // 100: urbis.PageGraphResponse
+	(*TreeStructureRequest)(nil),          // 101: urbis.TreeStructureRequest
+	(*TreeNode)(nil),                      // 102: urbis.TreeNode
+	(*TreeStructureResponse)(nil),         // 103: urbis.TreeStructureResponse
+	(*PrefetchRegionRequest)(nil),         // 104: urbis.PrefetchRegionRequest
+	(*PrefetchRegionResponse)(nil),        // 105: urbis.PrefetchRegionResponse
+	(*IndexReadyRequest)(nil),             // 106: urbis.IndexReadyRequest
+	(*IndexReadyResponse)(nil),            // 107: urbis.IndexReadyResponse
+	(*ServerInfoRequest)(nil),             // 108: urbis.ServerInfoRequest
+	(*ServerInfoResponse)(nil),            // 109: urbis.ServerInfoResponse
+	(*StatsRequest)(nil),                  // 110: urbis.StatsRequest
+	(*StatsResponse)(nil),                 // 111: urbis.StatsResponse
+	(*ResourceStatsRequest)(nil),          // 112: urbis.ResourceStatsRequest
+	(*ResourceStatsResponse)(nil),         // 113: urbis.ResourceStatsResponse
+	(*CountRequest)(nil),                  // 114: urbis.CountRequest
+	(*CountResponse)(nil),                 // 115: urbis.CountResponse
+	(*BoundsRequest)(nil),                 // 116: urbis.BoundsRequest
+	(*BoundsResponse)(nil),                // 117: urbis.BoundsResponse
+	(*SaveRequest)(nil),                   // 118: urbis.SaveRequest
+	(*SaveResponse)(nil),                  // 119: urbis.SaveResponse
+	(*SyncRequest)(nil),                   // 120: urbis.SyncRequest
+	(*SyncResponse)(nil),                  // 121: urbis.SyncResponse
+	(*LoadIndexRequest)(nil),              // 122: urbis.LoadIndexRequest
+	(*LoadIndexResponse)(nil),             // 123: urbis.LoadIndexResponse
+	(*StreamSaveRequest)(nil),             // 124: urbis.StreamSaveRequest
+	(*IndexChunk)(nil),                    // 125: urbis.IndexChunk
+	(*StreamLoadRequest)(nil),             // 126: urbis.StreamLoadRequest
+	(*ReloadIndexRequest)(nil),            // 127: urbis.ReloadIndexRequest
+	(*ReloadIndexResponse)(nil),           // 128: urbis.ReloadIndexResponse
+	nil,                                   // 129: urbis.MultiQueryResponse.ResultsEntry
+	nil,                                   // 130: urbis.QueryRangeMultiResponse.ResultsEntry
 }
 var file_urbis_proto_depIdxs = []int32{
-	1,  // 0: urbis.LineString.points:type_name -> urbis.Point
-	1,  // 1: urbis.Polygon.exterior:type_name -> urbis.Point
-	5,  // 2: urbis.Polygon.holes:type_name -> urbis.Ring
-	1,  // 3: urbis.Ring.points:type_name -> urbis.Point
-	0,  // 4: urbis.SpatialObject.type:type_name -> urbis.GeomType
-	1,  // 5: urbis.SpatialObject.point:type_name -> urbis.Point
-	3,  // 6: urbis.SpatialObject.line:type_name -> urbis.LineString
-	4,  // 7: urbis.SpatialObject.polygon:type_name -> urbis.Polygon
-	1,  // 8: urbis.SpatialObject.centroid:type_name -> urbis.Point
-	2,  // 9: urbis.SpatialObject.mbr:type_name -> urbis.MBR
-	2,  // 10: urbis.Stats.bounds:type_name -> urbis.MBR
-	7,  // 11: urbis.CreateIndexRequest.config:type_name -> urbis.Config
-	1,  // 12: urbis.InsertLineStringRequest.points:type_name -> urbis.Point
-	1,  // 13: urbis.InsertPolygonRequest.exterior:type_name -> urbis.Point
-	6,  // 14: urbis.GetObjectResponse.object:type_name -> urbis.SpatialObject
-	2,  // 15: urbis.RangeQueryRequest.range:type_name -> urbis.MBR
-	6,  // 16: urbis.QueryResponse.objects:type_name -> urbis.SpatialObject
-	2,  // 17: urbis.AdjacentPagesRequest.region:type_name -> urbis.MBR
-	9,  // 18: urbis.AdjacentPagesResponse.pages:type_name -> urbis.PageInfo
-	8,  // 19: urbis.StatsResponse.stats:type_name -> urbis.Stats
-	2,  // 20: urbis.BoundsResponse.bounds:type_name -> urbis.MBR
-	10, // 21: urbis.UrbisService.CreateIndex:input_type -> urbis.CreateIndexRequest
-	12, // 22: urbis.UrbisService.DestroyIndex:input_type -> urbis.DestroyIndexRequest
-	14, // 23: urbis.UrbisService.ListIndexes:input_type -> urbis.ListIndexesRequest
-	16, // 24: urbis.UrbisService.LoadGeoJSON:input_type -> urbis.LoadGeoJSONRequest
-	17, // 25: urbis.UrbisService.LoadGeoJSONString:input_type -> urbis.LoadGeoJSONStringRequest
-	18, // 26: urbis.UrbisService.LoadWKT:input_type -> urbis.LoadWKTRequest
-	20, // 27: urbis.UrbisService.InsertPoint:input_type -> urbis.InsertPointRequest
-	21, // 28: urbis.UrbisService.InsertLineString:input_type -> urbis.InsertLineStringRequest
-	22, // 29: urbis.UrbisService.InsertPolygon:input_type -> urbis.InsertPolygonRequest
-	24, // 30: urbis.UrbisService.Remove:input_type -> urbis.RemoveRequest
-	26, // 31: urbis.UrbisService.GetObject:input_type -> urbis.GetObjectRequest
-	28, // 32: urbis.UrbisService.Build:input_type -> urbis.BuildRequest
-	30, // 33: urbis.UrbisService.Optimize:input_type -> urbis.OptimizeRequest
-	32, // 34: urbis.UrbisService.QueryRange:input_type -> urbis.RangeQueryRequest
-	33, // 35: urbis.UrbisService.QueryPoint:input_type -> urbis.PointQueryRequest
-	34, // 36: urbis.UrbisService.QueryKNN:input_type -> urbis.KNNQueryRequest
-	32, // 37: urbis.UrbisService.QueryAdjacent:input_type -> urbis.RangeQueryRequest
-	36, // 38: urbis.UrbisService.FindAdjacentPages:input_type -> urbis.AdjacentPagesRequest
-	38, // 39: urbis.UrbisService.GetStats:input_type -> urbis.StatsRequest
-	40, // 40: urbis.UrbisService.GetCount:input_type -> urbis.CountRequest
-	42, // 41: urbis.UrbisService.GetBounds:input_type -> urbis.BoundsRequest
-	44, // 42: urbis.UrbisService.Save:input_type -> urbis.SaveRequest
-	46, // 43: urbis.UrbisService.Load:input_type -> urbis.LoadIndexRequest
-	11, // 44: urbis.UrbisService.CreateIndex:output_type -> urbis.CreateIndexResponse
-	13, // 45: urbis.UrbisService.DestroyIndex:output_type -> urbis.DestroyIndexResponse
-	15, // 46: urbis.UrbisService.ListIndexes:output_type -> urbis.ListIndexesResponse
-	19, // 47: urbis.UrbisService.LoadGeoJSON:output_type -> urbis.LoadResponse
-	19, // 48: urbis.UrbisService.LoadGeoJSONString:output_type -> urbis.LoadResponse
-	19, // 49: urbis.UrbisService.LoadWKT:output_type -> urbis.LoadResponse
-	23, // 50: urbis.UrbisService.InsertPoint:output_type -> urbis.InsertResponse
-	23, // 51: urbis.UrbisService.InsertLineString:output_type -> urbis.InsertResponse
-	23, // 52: urbis.UrbisService.InsertPolygon:output_type -> urbis.InsertResponse
-	25, // 53: urbis.UrbisService.Remove:output_type -> urbis.RemoveResponse
-	27, // 54: urbis.UrbisService.GetObject:output_type -> urbis.GetObjectResponse
-	29, // 55: urbis.UrbisService.Build:output_type -> urbis.BuildResponse
-	31, // 56: urbis.UrbisService.Optimize:output_type -> urbis.OptimizeResponse
-	35, // 57: urbis.UrbisService.QueryRange:output_type -> urbis.QueryResponse
-	35, // 58: urbis.UrbisService.QueryPoint:output_type -> urbis.QueryResponse
-	35, // 59: urbis.UrbisService.QueryKNN:output_type -> urbis.QueryResponse
-	35, // 60: urbis.UrbisService.QueryAdjacent:output_type -> urbis.QueryResponse
-	37, // 61: urbis.UrbisService.FindAdjacentPages:output_type -> urbis.AdjacentPagesResponse
-	39, // 62: urbis.UrbisService.GetStats:output_type -> urbis.StatsResponse
-	41, // 63: urbis.UrbisService.GetCount:output_type -> urbis.CountResponse
-	43, // 64: urbis.UrbisService.GetBounds:output_type -> urbis.BoundsResponse
-	45, // 65: urbis.UrbisService.Save:output_type -> urbis.SaveResponse
-	47, // 66: urbis.UrbisService.Load:output_type -> urbis.LoadIndexResponse
-	44, // [44:67] is the sub-list for method output_type
-	21, // [21:44] is the sub-list for method input_type
-	21, // [21:21] is the sub-list for extension type_name
-	21, // [21:21] is the sub-list for extension extendee
-	0,  // [0:21] is the sub-list for field type_name
+	10,  // 0: urbis.LineString.points:type_name -> urbis.Point
+	10,  // 1: urbis.Polygon.exterior:type_name -> urbis.Point
+	14,  // 2: urbis.Polygon.holes:type_name -> urbis.Ring
+	10,  // 3: urbis.Ring.points:type_name -> urbis.Point
+	10,  // 4: urbis.MultiPoint.points:type_name -> urbis.Point
+	12,  // 5: urbis.MultiLineString.lines:type_name -> urbis.LineString
+	13,  // 6: urbis.MultiPolygon.polygons:type_name -> urbis.Polygon
+	19,  // 7: urbis.GeometryCollection.geometries:type_name -> urbis.SpatialObject
+	0,   // 8: urbis.SpatialObject.type:type_name -> urbis.GeomType
+	10,  // 9: urbis.SpatialObject.point:type_name -> urbis.Point
+	12,  // 10: urbis.SpatialObject.line:type_name -> urbis.LineString
+	13,  // 11: urbis.SpatialObject.polygon:type_name -> urbis.Polygon
+	15,  // 12: urbis.SpatialObject.multi_point:type_name -> urbis.MultiPoint
+	16,  // 13: urbis.SpatialObject.multi_line:type_name -> urbis.MultiLineString
+	17,  // 14: urbis.SpatialObject.multi_polygon:type_name -> urbis.MultiPolygon
+	18,  // 15: urbis.SpatialObject.collection:type_name -> urbis.GeometryCollection
+	10,  // 16: urbis.SpatialObject.centroid:type_name -> urbis.Point
+	11,  // 17: urbis.SpatialObject.mbr:type_name -> urbis.MBR
+	3,   // 18: urbis.Config.polygon_validation:type_name -> urbis.PolygonValidation
+	22,  // 19: urbis.Config.seek_cost:type_name -> urbis.SeekCostModel
+	21,  // 20: urbis.Config.property_schema:type_name -> urbis.PropertyRule
+	11,  // 21: urbis.Config.valid_bounds:type_name -> urbis.MBR
+	4,   // 22: urbis.PropertyRule.type:type_name -> urbis.PropertyType
+	5,   // 23: urbis.SeekCostModel.storage:type_name -> urbis.StorageKind
+	11,  // 24: urbis.Stats.bounds:type_name -> urbis.MBR
+	11,  // 25: urbis.PageInfo.extent:type_name -> urbis.MBR
+	20,  // 26: urbis.CreateIndexRequest.config:type_name -> urbis.Config
+	11,  // 27: urbis.CreateIndexResponse.bounds:type_name -> urbis.MBR
+	20,  // 28: urbis.DescribeIndexResponse.config:type_name -> urbis.Config
+	11,  // 29: urbis.DescribeIndexResponse.bounds:type_name -> urbis.MBR
+	23,  // 30: urbis.DescribeIndexResponse.stats:type_name -> urbis.Stats
+	11,  // 31: urbis.LoadResponse.bounds:type_name -> urbis.MBR
+	10,  // 32: urbis.InsertLineStringRequest.points:type_name -> urbis.Point
+	10,  // 33: urbis.InsertPolygonRequest.exterior:type_name -> urbis.Point
+	11,  // 34: urbis.InsertResponse.mbr:type_name -> urbis.MBR
+	10,  // 35: urbis.InsertResponse.centroid:type_name -> urbis.Point
+	10,  // 36: urbis.StreamInsertRequest.point:type_name -> urbis.Point
+	12,  // 37: urbis.StreamInsertRequest.line:type_name -> urbis.LineString
+	13,  // 38: urbis.StreamInsertRequest.polygon:type_name -> urbis.Polygon
+	47,  // 39: urbis.StreamInsertResponse.result:type_name -> urbis.InsertResponse
+	11,  // 40: urbis.RemoveRangeRequest.region:type_name -> urbis.MBR
+	1,   // 41: urbis.RemoveRangeRequest.match:type_name -> urbis.RangeMatch
+	19,  // 42: urbis.GetObjectResponse.object:type_name -> urbis.SpatialObject
+	19,  // 43: urbis.BatchGetObjectsResponse.objects:type_name -> urbis.SpatialObject
+	11,  // 44: urbis.BuildResponse.bounds:type_name -> urbis.MBR
+	65,  // 45: urbis.BuildProgressResponse.result:type_name -> urbis.BuildResponse
+	23,  // 46: urbis.OptimizeResponse.before:type_name -> urbis.Stats
+	23,  // 47: urbis.OptimizeResponse.after:type_name -> urbis.Stats
+	23,  // 48: urbis.CompactResponse.before:type_name -> urbis.Stats
+	23,  // 49: urbis.CompactResponse.after:type_name -> urbis.Stats
+	11,  // 50: urbis.AutoTuneRequest.sample_queries:type_name -> urbis.MBR
+	72,  // 51: urbis.AutoTuneResponse.candidates:type_name -> urbis.TuneCandidate
+	11,  // 52: urbis.RangeQueryRequest.range:type_name -> urbis.MBR
+	2,   // 53: urbis.RangeQueryRequest.structure:type_name -> urbis.IndexStructure
+	6,   // 54: urbis.RangeQueryRequest.sort_by:type_name -> urbis.RangeSort
+	9,   // 55: urbis.RangeQueryRequest.encoding:type_name -> urbis.GeometryEncoding
+	8,   // 56: urbis.RangeQueryRequest.field_mask:type_name -> urbis.ObjectField
+	0,   // 57: urbis.RangeQueryRequest.geom_types:type_name -> urbis.GeomType
+	11,  // 58: urbis.EstimateCountRequest.range:type_name -> urbis.MBR
+	11,  // 59: urbis.MultiRangeQueryRequest.ranges:type_name -> urbis.MBR
+	2,   // 60: urbis.MultiRangeQueryRequest.structure:type_name -> urbis.IndexStructure
+	9,   // 61: urbis.MultiRangeQueryRequest.encoding:type_name -> urbis.GeometryEncoding
+	8,   // 62: urbis.MultiRangeQueryRequest.field_mask:type_name -> urbis.ObjectField
+	0,   // 63: urbis.MultiRangeQueryRequest.geom_types:type_name -> urbis.GeomType
+	19,  // 64: urbis.RangeResult.objects:type_name -> urbis.SpatialObject
+	94,  // 65: urbis.RangeResult.query_stats:type_name -> urbis.QueryStats
+	129, // 66: urbis.MultiQueryResponse.results:type_name -> urbis.MultiQueryResponse.ResultsEntry
+	11,  // 67: urbis.QueryRangeMultiRequest.range:type_name -> urbis.MBR
+	2,   // 68: urbis.QueryRangeMultiRequest.structure:type_name -> urbis.IndexStructure
+	0,   // 69: urbis.QueryRangeMultiRequest.geom_types:type_name -> urbis.GeomType
+	130, // 70: urbis.QueryRangeMultiResponse.results:type_name -> urbis.QueryRangeMultiResponse.ResultsEntry
+	9,   // 71: urbis.PropertyQueryRequest.encoding:type_name -> urbis.GeometryEncoding
+	8,   // 72: urbis.PropertyQueryRequest.field_mask:type_name -> urbis.ObjectField
+	0,   // 73: urbis.PropertyQueryRequest.geom_types:type_name -> urbis.GeomType
+	11,  // 74: urbis.ConvexHullRequest.region:type_name -> urbis.MBR
+	10,  // 75: urbis.ConvexHullResponse.hull:type_name -> urbis.Point
+	2,   // 76: urbis.PointQueryRequest.structure:type_name -> urbis.IndexStructure
+	9,   // 77: urbis.PointQueryRequest.encoding:type_name -> urbis.GeometryEncoding
+	8,   // 78: urbis.PointQueryRequest.field_mask:type_name -> urbis.ObjectField
+	0,   // 79: urbis.PointQueryRequest.geom_types:type_name -> urbis.GeomType
+	19,  // 80: urbis.BufferQueryRequest.geometry:type_name -> urbis.SpatialObject
+	9,   // 81: urbis.BufferQueryRequest.encoding:type_name -> urbis.GeometryEncoding
+	8,   // 82: urbis.BufferQueryRequest.field_mask:type_name -> urbis.ObjectField
+	0,   // 83: urbis.BufferQueryRequest.geom_types:type_name -> urbis.GeomType
+	10,  // 84: urbis.ContainingPolygonQueryRequest.polygon:type_name -> urbis.Point
+	9,   // 85: urbis.ContainingPolygonQueryRequest.encoding:type_name -> urbis.GeometryEncoding
+	8,   // 86: urbis.ContainingPolygonQueryRequest.field_mask:type_name -> urbis.ObjectField
+	0,   // 87: urbis.ContainingPolygonQueryRequest.geom_types:type_name -> urbis.GeomType
+	9,   // 88: urbis.KNNQueryRequest.encoding:type_name -> urbis.GeometryEncoding
+	8,   // 89: urbis.KNNQueryRequest.field_mask:type_name -> urbis.ObjectField
+	7,   // 90: urbis.KNNQueryRequest.distance_metric:type_name -> urbis.DistanceMetric
+	19,  // 91: urbis.NearestResponse.object:type_name -> urbis.SpatialObject
+	9,   // 92: urbis.ChangedSinceRequest.encoding:type_name -> urbis.GeometryEncoding
+	8,   // 93: urbis.ChangedSinceRequest.field_mask:type_name -> urbis.ObjectField
+	19,  // 94: urbis.SnapshotScanResponse.objects:type_name -> urbis.SpatialObject
+	2,   // 95: urbis.QueryStats.structure:type_name -> urbis.IndexStructure
+	19,  // 96: urbis.QueryResponse.objects:type_name -> urbis.SpatialObject
+	94,  // 97: urbis.QueryResponse.query_stats:type_name -> urbis.QueryStats
+	24,  // 98: urbis.QueryResponse.pages:type_name -> urbis.PageInfo
+	11,  // 99: urbis.QueryResponse.result_bounds:type_name -> urbis.MBR
+	11,  // 100: urbis.AdjacentPagesRequest.region:type_name -> urbis.MBR
+	24,  // 101: urbis.AdjacentPagesResponse.pages:type_name -> urbis.PageInfo
+	24,  // 102: urbis.PageGraphResponse.nodes:type_name -> urbis.PageInfo
+	99,  // 103: urbis.PageGraphResponse.edges:type_name -> urbis.PageEdge
+	2,   // 104: urbis.TreeStructureRequest.structure:type_name -> urbis.IndexStructure
+	11,  // 105: urbis.TreeNode.bounds:type_name -> urbis.MBR
+	102, // 106: urbis.TreeStructureResponse.nodes:type_name -> urbis.TreeNode
+	11,  // 107: urbis.PrefetchRegionRequest.region:type_name -> urbis.MBR
+	23,  // 108: urbis.StatsResponse.stats:type_name -> urbis.Stats
+	11,  // 109: urbis.BoundsResponse.bounds:type_name -> urbis.MBR
+	11,  // 110: urbis.LoadIndexResponse.bounds:type_name -> urbis.MBR
+	20,  // 111: urbis.ReloadIndexRequest.config:type_name -> urbis.Config
+	11,  // 112: urbis.ReloadIndexResponse.bounds:type_name -> urbis.MBR
+	78,  // 113: urbis.MultiQueryResponse.ResultsEntry.value:type_name -> urbis.RangeResult
+	78,  // 114: urbis.QueryRangeMultiResponse.ResultsEntry.value:type_name -> urbis.RangeResult
+	25,  // 115: urbis.UrbisService.CreateIndex:input_type -> urbis.CreateIndexRequest
+	27,  // 116: urbis.UrbisService.DestroyIndex:input_type -> urbis.DestroyIndexRequest
+	29,  // 117: urbis.UrbisService.ListIndexes:input_type -> urbis.ListIndexesRequest
+	31,  // 118: urbis.UrbisService.DescribeIndex:input_type -> urbis.DescribeIndexRequest
+	33,  // 119: urbis.UrbisService.MarkReadOnly:input_type -> urbis.MarkReadOnlyRequest
+	35,  // 120: urbis.UrbisService.LoadGeoJSON:input_type -> urbis.LoadGeoJSONRequest
+	37,  // 121: urbis.UrbisService.LoadGeoJSONString:input_type -> urbis.LoadGeoJSONStringRequest
+	36,  // 122: urbis.UrbisService.LoadGeoJSONURL:input_type -> urbis.LoadGeoJSONURLRequest
+	38,  // 123: urbis.UrbisService.LoadWKT:input_type -> urbis.LoadWKTRequest
+	39,  // 124: urbis.UrbisService.LoadWKB:input_type -> urbis.LoadWKBRequest
+	40,  // 125: urbis.UrbisService.LoadGeoPackage:input_type -> urbis.LoadGeoPackageRequest
+	41,  // 126: urbis.UrbisService.StreamLoadGeoJSON:input_type -> urbis.StreamLoadGeoJSONRequest
+	42,  // 127: urbis.UrbisService.StreamLoadWKT:input_type -> urbis.StreamLoadWKTRequest
+	44,  // 128: urbis.UrbisService.InsertPoint:input_type -> urbis.InsertPointRequest
+	45,  // 129: urbis.UrbisService.InsertLineString:input_type -> urbis.InsertLineStringRequest
+	46,  // 130: urbis.UrbisService.InsertPolygon:input_type -> urbis.InsertPolygonRequest
+	48,  // 131: urbis.UrbisService.StreamInsert:input_type -> urbis.StreamInsertRequest
+	50,  // 132: urbis.UrbisService.Remove:input_type -> urbis.RemoveRequest
+	52,  // 133: urbis.UrbisService.RemoveRange:input_type -> urbis.RemoveRangeRequest
+	54,  // 134: urbis.UrbisService.SweepExpired:input_type -> urbis.SweepExpiredRequest
+	56,  // 135: urbis.UrbisService.GetObject:input_type -> urbis.GetObjectRequest
+	58,  // 136: urbis.UrbisService.BatchGetObjects:input_type -> urbis.BatchGetObjectsRequest
+	60,  // 137: urbis.UrbisService.SetProperties:input_type -> urbis.SetPropertiesRequest
+	62,  // 138: urbis.UrbisService.GetProperties:input_type -> urbis.GetPropertiesRequest
+	64,  // 139: urbis.UrbisService.Build:input_type -> urbis.BuildRequest
+	64,  // 140: urbis.UrbisService.BuildWithProgress:input_type -> urbis.BuildRequest
+	67,  // 141: urbis.UrbisService.Optimize:input_type -> urbis.OptimizeRequest
+	69,  // 142: urbis.UrbisService.Compact:input_type -> urbis.CompactRequest
+	71,  // 143: urbis.UrbisService.AutoTune:input_type -> urbis.AutoTuneRequest
+	74,  // 144: urbis.UrbisService.QueryRange:input_type -> urbis.RangeQueryRequest
+	75,  // 145: urbis.UrbisService.EstimateCount:input_type -> urbis.EstimateCountRequest
+	77,  // 146: urbis.UrbisService.MultiQueryRange:input_type -> urbis.MultiRangeQueryRequest
+	80,  // 147: urbis.UrbisService.QueryRangeMulti:input_type -> urbis.QueryRangeMultiRequest
+	85,  // 148: urbis.UrbisService.QueryPoint:input_type -> urbis.PointQueryRequest
+	85,  // 149: urbis.UrbisService.QueryContaining:input_type -> urbis.PointQueryRequest
+	86,  // 150: urbis.UrbisService.QueryBuffered:input_type -> urbis.BufferQueryRequest
+	87,  // 151: urbis.UrbisService.QueryContainingPolygon:input_type -> urbis.ContainingPolygonQueryRequest
+	88,  // 152: urbis.UrbisService.QueryKNN:input_type -> urbis.KNNQueryRequest
+	89,  // 153: urbis.UrbisService.Nearest:input_type -> urbis.NearestRequest
+	74,  // 154: urbis.UrbisService.QueryAdjacent:input_type -> urbis.RangeQueryRequest
+	91,  // 155: urbis.UrbisService.QueryChangedSince:input_type -> urbis.ChangedSinceRequest
+	92,  // 156: urbis.UrbisService.SnapshotScan:input_type -> urbis.SnapshotScanRequest
+	82,  // 157: urbis.UrbisService.QueryByProperty:input_type -> urbis.PropertyQueryRequest
+	83,  // 158: urbis.UrbisService.ConvexHull:input_type -> urbis.ConvexHullRequest
+	96,  // 159: urbis.UrbisService.FindAdjacentPages:input_type -> urbis.AdjacentPagesRequest
+	104, // 160: urbis.UrbisService.PrefetchRegion:input_type -> urbis.PrefetchRegionRequest
+	98,  // 161: urbis.UrbisService.GetPageGraph:input_type -> urbis.PageGraphRequest
+	101, // 162: urbis.UrbisService.GetTreeStructure:input_type -> urbis.TreeStructureRequest
+	106, // 163: urbis.UrbisService.IndexReady:input_type -> urbis.IndexReadyRequest
+	108, // 164: urbis.UrbisService.GetServerInfo:input_type -> urbis.ServerInfoRequest
+	110, // 165: urbis.UrbisService.GetStats:input_type -> urbis.StatsRequest
+	114, // 166: urbis.UrbisService.GetCount:input_type -> urbis.CountRequest
+	116, // 167: urbis.UrbisService.GetBounds:input_type -> urbis.BoundsRequest
+	112, // 168: urbis.UrbisService.GetResourceStats:input_type -> urbis.ResourceStatsRequest
+	118, // 169: urbis.UrbisService.Save:input_type -> urbis.SaveRequest
+	122, // 170: urbis.UrbisService.Load:input_type -> urbis.LoadIndexRequest
+	124, // 171: urbis.UrbisService.StreamSave:input_type -> urbis.StreamSaveRequest
+	126, // 172: urbis.UrbisService.StreamLoad:input_type -> urbis.StreamLoadRequest
+	127, // 173: urbis.UrbisService.ReloadIndex:input_type -> urbis.ReloadIndexRequest
+	120, // 174: urbis.UrbisService.Sync:input_type -> urbis.SyncRequest
+	26,  // 175: urbis.UrbisService.CreateIndex:output_type -> urbis.CreateIndexResponse
+	28,  // 176: urbis.UrbisService.DestroyIndex:output_type -> urbis.DestroyIndexResponse
+	30,  // 177: urbis.UrbisService.ListIndexes:output_type -> urbis.ListIndexesResponse
+	32,  // 178: urbis.UrbisService.DescribeIndex:output_type -> urbis.DescribeIndexResponse
+	34,  // 179: urbis.UrbisService.MarkReadOnly:output_type -> urbis.MarkReadOnlyResponse
+	43,  // 180: urbis.UrbisService.LoadGeoJSON:output_type -> urbis.LoadResponse
+	43,  // 181: urbis.UrbisService.LoadGeoJSONString:output_type -> urbis.LoadResponse
+	43,  // 182: urbis.UrbisService.LoadGeoJSONURL:output_type -> urbis.LoadResponse
+	43,  // 183: urbis.UrbisService.LoadWKT:output_type -> urbis.LoadResponse
+	43,  // 184: urbis.UrbisService.LoadWKB:output_type -> urbis.LoadResponse
+	43,  // 185: urbis.UrbisService.LoadGeoPackage:output_type -> urbis.LoadResponse
+	43,  // 186: urbis.UrbisService.StreamLoadGeoJSON:output_type -> urbis.LoadResponse
+	43,  // 187: urbis.UrbisService.StreamLoadWKT:output_type -> urbis.LoadResponse
+	47,  // 188: urbis.UrbisService.InsertPoint:output_type -> urbis.InsertResponse
+	47,  // 189: urbis.UrbisService.InsertLineString:output_type -> urbis.InsertResponse
+	47,  // 190: urbis.UrbisService.InsertPolygon:output_type -> urbis.InsertResponse
+	49,  // 191: urbis.UrbisService.StreamInsert:output_type -> urbis.StreamInsertResponse
+	51,  // 192: urbis.UrbisService.Remove:output_type -> urbis.RemoveResponse
+	53,  // 193: urbis.UrbisService.RemoveRange:output_type -> urbis.RemoveRangeResponse
+	55,  // 194: urbis.UrbisService.SweepExpired:output_type -> urbis.SweepExpiredResponse
+	57,  // 195: urbis.UrbisService.GetObject:output_type -> urbis.GetObjectResponse
+	59,  // 196: urbis.UrbisService.BatchGetObjects:output_type -> urbis.BatchGetObjectsResponse
+	61,  // 197: urbis.UrbisService.SetProperties:output_type -> urbis.SetPropertiesResponse
+	63,  // 198: urbis.UrbisService.GetProperties:output_type -> urbis.GetPropertiesResponse
+	65,  // 199: urbis.UrbisService.Build:output_type -> urbis.BuildResponse
+	66,  // 200: urbis.UrbisService.BuildWithProgress:output_type -> urbis.BuildProgressResponse
+	68,  // 201: urbis.UrbisService.Optimize:output_type -> urbis.OptimizeResponse
+	70,  // 202: urbis.UrbisService.Compact:output_type -> urbis.CompactResponse
+	73,  // 203: urbis.UrbisService.AutoTune:output_type -> urbis.AutoTuneResponse
+	95,  // 204: urbis.UrbisService.QueryRange:output_type -> urbis.QueryResponse
+	76,  // 205: urbis.UrbisService.EstimateCount:output_type -> urbis.EstimateCountResponse
+	79,  // 206: urbis.UrbisService.MultiQueryRange:output_type -> urbis.MultiQueryResponse
+	81,  // 207: urbis.UrbisService.QueryRangeMulti:output_type -> urbis.QueryRangeMultiResponse
+	95,  // 208: urbis.UrbisService.QueryPoint:output_type -> urbis.QueryResponse
+	95,  // 209: urbis.UrbisService.QueryContaining:output_type -> urbis.QueryResponse
+	95,  // 210: urbis.UrbisService.QueryBuffered:output_type -> urbis.QueryResponse
+	95,  // 211: urbis.UrbisService.QueryContainingPolygon:output_type -> urbis.QueryResponse
+	95,  // 212: urbis.UrbisService.QueryKNN:output_type -> urbis.QueryResponse
+	90,  // 213: urbis.UrbisService.Nearest:output_type -> urbis.NearestResponse
+	95,  // 214: urbis.UrbisService.QueryAdjacent:output_type -> urbis.QueryResponse
+	95,  // 215: urbis.UrbisService.QueryChangedSince:output_type -> urbis.QueryResponse
+	93,  // 216: urbis.UrbisService.SnapshotScan:output_type -> urbis.SnapshotScanResponse
+	95,  // 217: urbis.UrbisService.QueryByProperty:output_type -> urbis.QueryResponse
+	84,  // 218: urbis.UrbisService.ConvexHull:output_type -> urbis.ConvexHullResponse
+	97,  // 219: urbis.UrbisService.FindAdjacentPages:output_type -> urbis.AdjacentPagesResponse
+	105, // 220: urbis.UrbisService.PrefetchRegion:output_type -> urbis.PrefetchRegionResponse
+	100, // 221: urbis.UrbisService.GetPageGraph:output_type -> urbis.PageGraphResponse
+	103, // 222: urbis.UrbisService.GetTreeStructure:output_type -> urbis.TreeStructureResponse
+	107, // 223: urbis.UrbisService.IndexReady:output_type -> urbis.IndexReadyResponse
+	109, // 224: urbis.UrbisService.GetServerInfo:output_type -> urbis.ServerInfoResponse
+	111, // 225: urbis.UrbisService.GetStats:output_type -> urbis.StatsResponse
+	115, // 226: urbis.UrbisService.GetCount:output_type -> urbis.CountResponse
+	117, // 227: urbis.UrbisService.GetBounds:output_type -> urbis.BoundsResponse
+	113, // 228: urbis.UrbisService.GetResourceStats:output_type -> urbis.ResourceStatsResponse
+	119, // 229: urbis.UrbisService.Save:output_type -> urbis.SaveResponse
+	123, // 230: urbis.UrbisService.Load:output_type -> urbis.LoadIndexResponse
+	125, // 231: urbis.UrbisService.StreamSave:output_type -> urbis.IndexChunk
+	123, // 232: urbis.UrbisService.StreamLoad:output_type -> urbis.LoadIndexResponse
+	128, // 233: urbis.UrbisService.ReloadIndex:output_type -> urbis.ReloadIndexResponse
+	121, // 234: urbis.UrbisService.Sync:output_type -> urbis.SyncResponse
+	175, // [175:235] is the sub-list for method output_type
+	115, // [115:175] is the sub-list for method input_type
+	115, // [115:115] is the sub-list for extension type_name
+	115, // [115:115] is the sub-list for extension extendee
+	0,   // [0:115] is the sub-list for field type_name
 }
 
 func init() { file_urbis_proto_init() }
@@ -2940,18 +9554,37 @@ func file_urbis_proto_init() {
 	if File_urbis_proto != nil {
 		return
 	}
-	file_urbis_proto_msgTypes[5].OneofWrappers = []any{
+	file_urbis_proto_msgTypes[0].OneofWrappers = []any{}
+	file_urbis_proto_msgTypes[9].OneofWrappers = []any{
 		(*SpatialObject_Point)(nil),
 		(*SpatialObject_Line)(nil),
 		(*SpatialObject_Polygon)(nil),
+		(*SpatialObject_MultiPoint)(nil),
+		(*SpatialObject_MultiLine)(nil),
+		(*SpatialObject_MultiPolygon)(nil),
+		(*SpatialObject_Collection)(nil),
+	}
+	file_urbis_proto_msgTypes[13].OneofWrappers = []any{}
+	file_urbis_proto_msgTypes[34].OneofWrappers = []any{}
+	file_urbis_proto_msgTypes[37].OneofWrappers = []any{}
+	file_urbis_proto_msgTypes[38].OneofWrappers = []any{
+		(*StreamInsertRequest_Point)(nil),
+		(*StreamInsertRequest_Line)(nil),
+		(*StreamInsertRequest_Polygon)(nil),
+	}
+	file_urbis_proto_msgTypes[107].OneofWrappers = []any{}
+	file_urbis_proto_msgTypes[117].OneofWrappers = []any{
+		(*ReloadIndexRequest_DataFile)(nil),
+		(*ReloadIndexRequest_GeojsonPath)(nil),
+		(*ReloadIndexRequest_Geojson)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_urbis_proto_rawDesc), len(file_urbis_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   47,
+			NumEnums:      10,
+			NumMessages:   121,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
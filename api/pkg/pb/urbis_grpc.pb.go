@@ -19,29 +19,66 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UrbisService_CreateIndex_FullMethodName       = "/urbis.UrbisService/CreateIndex"
-	UrbisService_DestroyIndex_FullMethodName      = "/urbis.UrbisService/DestroyIndex"
-	UrbisService_ListIndexes_FullMethodName       = "/urbis.UrbisService/ListIndexes"
-	UrbisService_LoadGeoJSON_FullMethodName       = "/urbis.UrbisService/LoadGeoJSON"
-	UrbisService_LoadGeoJSONString_FullMethodName = "/urbis.UrbisService/LoadGeoJSONString"
-	UrbisService_LoadWKT_FullMethodName           = "/urbis.UrbisService/LoadWKT"
-	UrbisService_InsertPoint_FullMethodName       = "/urbis.UrbisService/InsertPoint"
-	UrbisService_InsertLineString_FullMethodName  = "/urbis.UrbisService/InsertLineString"
-	UrbisService_InsertPolygon_FullMethodName     = "/urbis.UrbisService/InsertPolygon"
-	UrbisService_Remove_FullMethodName            = "/urbis.UrbisService/Remove"
-	UrbisService_GetObject_FullMethodName         = "/urbis.UrbisService/GetObject"
-	UrbisService_Build_FullMethodName             = "/urbis.UrbisService/Build"
-	UrbisService_Optimize_FullMethodName          = "/urbis.UrbisService/Optimize"
-	UrbisService_QueryRange_FullMethodName        = "/urbis.UrbisService/QueryRange"
-	UrbisService_QueryPoint_FullMethodName        = "/urbis.UrbisService/QueryPoint"
-	UrbisService_QueryKNN_FullMethodName          = "/urbis.UrbisService/QueryKNN"
-	UrbisService_QueryAdjacent_FullMethodName     = "/urbis.UrbisService/QueryAdjacent"
-	UrbisService_FindAdjacentPages_FullMethodName = "/urbis.UrbisService/FindAdjacentPages"
-	UrbisService_GetStats_FullMethodName          = "/urbis.UrbisService/GetStats"
-	UrbisService_GetCount_FullMethodName          = "/urbis.UrbisService/GetCount"
-	UrbisService_GetBounds_FullMethodName         = "/urbis.UrbisService/GetBounds"
-	UrbisService_Save_FullMethodName              = "/urbis.UrbisService/Save"
-	UrbisService_Load_FullMethodName              = "/urbis.UrbisService/Load"
+	UrbisService_CreateIndex_FullMethodName            = "/urbis.UrbisService/CreateIndex"
+	UrbisService_DestroyIndex_FullMethodName           = "/urbis.UrbisService/DestroyIndex"
+	UrbisService_ListIndexes_FullMethodName            = "/urbis.UrbisService/ListIndexes"
+	UrbisService_DescribeIndex_FullMethodName          = "/urbis.UrbisService/DescribeIndex"
+	UrbisService_MarkReadOnly_FullMethodName           = "/urbis.UrbisService/MarkReadOnly"
+	UrbisService_LoadGeoJSON_FullMethodName            = "/urbis.UrbisService/LoadGeoJSON"
+	UrbisService_LoadGeoJSONString_FullMethodName      = "/urbis.UrbisService/LoadGeoJSONString"
+	UrbisService_LoadGeoJSONURL_FullMethodName         = "/urbis.UrbisService/LoadGeoJSONURL"
+	UrbisService_LoadWKT_FullMethodName                = "/urbis.UrbisService/LoadWKT"
+	UrbisService_LoadWKB_FullMethodName                = "/urbis.UrbisService/LoadWKB"
+	UrbisService_LoadGeoPackage_FullMethodName         = "/urbis.UrbisService/LoadGeoPackage"
+	UrbisService_StreamLoadGeoJSON_FullMethodName      = "/urbis.UrbisService/StreamLoadGeoJSON"
+	UrbisService_StreamLoadWKT_FullMethodName          = "/urbis.UrbisService/StreamLoadWKT"
+	UrbisService_InsertPoint_FullMethodName            = "/urbis.UrbisService/InsertPoint"
+	UrbisService_InsertLineString_FullMethodName       = "/urbis.UrbisService/InsertLineString"
+	UrbisService_InsertPolygon_FullMethodName          = "/urbis.UrbisService/InsertPolygon"
+	UrbisService_StreamInsert_FullMethodName           = "/urbis.UrbisService/StreamInsert"
+	UrbisService_Remove_FullMethodName                 = "/urbis.UrbisService/Remove"
+	UrbisService_RemoveRange_FullMethodName            = "/urbis.UrbisService/RemoveRange"
+	UrbisService_SweepExpired_FullMethodName           = "/urbis.UrbisService/SweepExpired"
+	UrbisService_GetObject_FullMethodName              = "/urbis.UrbisService/GetObject"
+	UrbisService_BatchGetObjects_FullMethodName        = "/urbis.UrbisService/BatchGetObjects"
+	UrbisService_SetProperties_FullMethodName          = "/urbis.UrbisService/SetProperties"
+	UrbisService_GetProperties_FullMethodName          = "/urbis.UrbisService/GetProperties"
+	UrbisService_Build_FullMethodName                  = "/urbis.UrbisService/Build"
+	UrbisService_BuildWithProgress_FullMethodName      = "/urbis.UrbisService/BuildWithProgress"
+	UrbisService_Optimize_FullMethodName               = "/urbis.UrbisService/Optimize"
+	UrbisService_Compact_FullMethodName                = "/urbis.UrbisService/Compact"
+	UrbisService_AutoTune_FullMethodName               = "/urbis.UrbisService/AutoTune"
+	UrbisService_QueryRange_FullMethodName             = "/urbis.UrbisService/QueryRange"
+	UrbisService_EstimateCount_FullMethodName          = "/urbis.UrbisService/EstimateCount"
+	UrbisService_MultiQueryRange_FullMethodName        = "/urbis.UrbisService/MultiQueryRange"
+	UrbisService_QueryRangeMulti_FullMethodName        = "/urbis.UrbisService/QueryRangeMulti"
+	UrbisService_QueryPoint_FullMethodName             = "/urbis.UrbisService/QueryPoint"
+	UrbisService_QueryContaining_FullMethodName        = "/urbis.UrbisService/QueryContaining"
+	UrbisService_QueryBuffered_FullMethodName          = "/urbis.UrbisService/QueryBuffered"
+	UrbisService_QueryContainingPolygon_FullMethodName = "/urbis.UrbisService/QueryContainingPolygon"
+	UrbisService_QueryKNN_FullMethodName               = "/urbis.UrbisService/QueryKNN"
+	UrbisService_Nearest_FullMethodName                = "/urbis.UrbisService/Nearest"
+	UrbisService_QueryAdjacent_FullMethodName          = "/urbis.UrbisService/QueryAdjacent"
+	UrbisService_QueryChangedSince_FullMethodName      = "/urbis.UrbisService/QueryChangedSince"
+	UrbisService_SnapshotScan_FullMethodName           = "/urbis.UrbisService/SnapshotScan"
+	UrbisService_QueryByProperty_FullMethodName        = "/urbis.UrbisService/QueryByProperty"
+	UrbisService_ConvexHull_FullMethodName             = "/urbis.UrbisService/ConvexHull"
+	UrbisService_FindAdjacentPages_FullMethodName      = "/urbis.UrbisService/FindAdjacentPages"
+	UrbisService_PrefetchRegion_FullMethodName         = "/urbis.UrbisService/PrefetchRegion"
+	UrbisService_GetPageGraph_FullMethodName           = "/urbis.UrbisService/GetPageGraph"
+	UrbisService_GetTreeStructure_FullMethodName       = "/urbis.UrbisService/GetTreeStructure"
+	UrbisService_IndexReady_FullMethodName             = "/urbis.UrbisService/IndexReady"
+	UrbisService_GetServerInfo_FullMethodName          = "/urbis.UrbisService/GetServerInfo"
+	UrbisService_GetStats_FullMethodName               = "/urbis.UrbisService/GetStats"
+	UrbisService_GetCount_FullMethodName               = "/urbis.UrbisService/GetCount"
+	UrbisService_GetBounds_FullMethodName              = "/urbis.UrbisService/GetBounds"
+	UrbisService_GetResourceStats_FullMethodName       = "/urbis.UrbisService/GetResourceStats"
+	UrbisService_Save_FullMethodName                   = "/urbis.UrbisService/Save"
+	UrbisService_Load_FullMethodName                   = "/urbis.UrbisService/Load"
+	UrbisService_StreamSave_FullMethodName             = "/urbis.UrbisService/StreamSave"
+	UrbisService_StreamLoad_FullMethodName             = "/urbis.UrbisService/StreamLoad"
+	UrbisService_ReloadIndex_FullMethodName            = "/urbis.UrbisService/ReloadIndex"
+	UrbisService_Sync_FullMethodName                   = "/urbis.UrbisService/Sync"
 )
 
 // UrbisServiceClient is the client API for UrbisService service.
@@ -52,33 +89,97 @@ type UrbisServiceClient interface {
 	CreateIndex(ctx context.Context, in *CreateIndexRequest, opts ...grpc.CallOption) (*CreateIndexResponse, error)
 	DestroyIndex(ctx context.Context, in *DestroyIndexRequest, opts ...grpc.CallOption) (*DestroyIndexResponse, error)
 	ListIndexes(ctx context.Context, in *ListIndexesRequest, opts ...grpc.CallOption) (*ListIndexesResponse, error)
+	DescribeIndex(ctx context.Context, in *DescribeIndexRequest, opts ...grpc.CallOption) (*DescribeIndexResponse, error)
+	// Make an index reject writes and builds for good, e.g. on a serving replica
+	MarkReadOnly(ctx context.Context, in *MarkReadOnlyRequest, opts ...grpc.CallOption) (*MarkReadOnlyResponse, error)
 	// Data Loading
 	LoadGeoJSON(ctx context.Context, in *LoadGeoJSONRequest, opts ...grpc.CallOption) (*LoadResponse, error)
 	LoadGeoJSONString(ctx context.Context, in *LoadGeoJSONStringRequest, opts ...grpc.CallOption) (*LoadResponse, error)
+	LoadGeoJSONURL(ctx context.Context, in *LoadGeoJSONURLRequest, opts ...grpc.CallOption) (*LoadResponse, error)
 	LoadWKT(ctx context.Context, in *LoadWKTRequest, opts ...grpc.CallOption) (*LoadResponse, error)
+	LoadWKB(ctx context.Context, in *LoadWKBRequest, opts ...grpc.CallOption) (*LoadResponse, error)
+	LoadGeoPackage(ctx context.Context, in *LoadGeoPackageRequest, opts ...grpc.CallOption) (*LoadResponse, error)
+	StreamLoadGeoJSON(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StreamLoadGeoJSONRequest, LoadResponse], error)
+	StreamLoadWKT(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StreamLoadWKTRequest, LoadResponse], error)
 	// Object Operations
 	InsertPoint(ctx context.Context, in *InsertPointRequest, opts ...grpc.CallOption) (*InsertResponse, error)
 	InsertLineString(ctx context.Context, in *InsertLineStringRequest, opts ...grpc.CallOption) (*InsertResponse, error)
 	InsertPolygon(ctx context.Context, in *InsertPolygonRequest, opts ...grpc.CallOption) (*InsertResponse, error)
+	// Insert a stream of geometries, acknowledging each one as it is applied
+	StreamInsert(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamInsertRequest, StreamInsertResponse], error)
 	Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*RemoveResponse, error)
+	RemoveRange(ctx context.Context, in *RemoveRangeRequest, opts ...grpc.CallOption) (*RemoveRangeResponse, error)
+	// Remove objects whose ttl_ms has passed now rather than on the next background sweep
+	SweepExpired(ctx context.Context, in *SweepExpiredRequest, opts ...grpc.CallOption) (*SweepExpiredResponse, error)
 	GetObject(ctx context.Context, in *GetObjectRequest, opts ...grpc.CallOption) (*GetObjectResponse, error)
+	BatchGetObjects(ctx context.Context, in *BatchGetObjectsRequest, opts ...grpc.CallOption) (*BatchGetObjectsResponse, error)
+	SetProperties(ctx context.Context, in *SetPropertiesRequest, opts ...grpc.CallOption) (*SetPropertiesResponse, error)
+	GetProperties(ctx context.Context, in *GetPropertiesRequest, opts ...grpc.CallOption) (*GetPropertiesResponse, error)
 	// Index Building
 	Build(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*BuildResponse, error)
+	BuildWithProgress(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BuildProgressResponse], error)
 	Optimize(ctx context.Context, in *OptimizeRequest, opts ...grpc.CallOption) (*OptimizeResponse, error)
+	// Repack objects onto as few pages as possible after heavy removal
+	Compact(ctx context.Context, in *CompactRequest, opts ...grpc.CallOption) (*CompactResponse, error)
+	// Recommend (and optionally apply) a page capacity for the current data
+	AutoTune(ctx context.Context, in *AutoTuneRequest, opts ...grpc.CallOption) (*AutoTuneResponse, error)
 	// Spatial Queries
 	QueryRange(ctx context.Context, in *RangeQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Upper bound on the objects QueryRange would return, without reading them
+	EstimateCount(ctx context.Context, in *EstimateCountRequest, opts ...grpc.CallOption) (*EstimateCountResponse, error)
+	// Several ranges in one call, e.g. adjacent map tiles
+	MultiQueryRange(ctx context.Context, in *MultiRangeQueryRequest, opts ...grpc.CallOption) (*MultiQueryResponse, error)
+	// One range across several indexes at once, e.g. the shards of a region
+	QueryRangeMulti(ctx context.Context, in *QueryRangeMultiRequest, opts ...grpc.CallOption) (*QueryRangeMultiResponse, error)
 	QueryPoint(ctx context.Context, in *PointQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Polygons whose interior contains the point; boundary points are not contained
+	QueryContaining(ctx context.Context, in *PointQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Objects within a distance of a geometry, e.g. along a route
+	QueryBuffered(ctx context.Context, in *BufferQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Polygons that contain a polygon, e.g. the regions a parcel lies in; shared edges count as contained
+	QueryContainingPolygon(ctx context.Context, in *ContainingPolygonQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
 	QueryKNN(ctx context.Context, in *KNNQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// The single nearest object and its distance; NOT_FOUND on an empty index
+	Nearest(ctx context.Context, in *NearestRequest, opts ...grpc.CallOption) (*NearestResponse, error)
 	QueryAdjacent(ctx context.Context, in *RangeQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Objects inserted or modified at or after a time, in change order
+	QueryChangedSince(ctx context.Context, in *ChangedSinceRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Every object as of the call, streamed in batches; writes made while the
+	// stream is open are not seen
+	SnapshotScan(ctx context.Context, in *SnapshotScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SnapshotScanResponse], error)
+	// Objects whose property key has a value, from the attribute index
+	QueryByProperty(ctx context.Context, in *PropertyQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Footprint of the objects in a region
+	ConvexHull(ctx context.Context, in *ConvexHullRequest, opts ...grpc.CallOption) (*ConvexHullResponse, error)
 	// Disk-Aware Operations
 	FindAdjacentPages(ctx context.Context, in *AdjacentPagesRequest, opts ...grpc.CallOption) (*AdjacentPagesResponse, error)
+	// Warm the page cache with the pages FindAdjacentPages would return
+	PrefetchRegion(ctx context.Context, in *PrefetchRegionRequest, opts ...grpc.CallOption) (*PrefetchRegionResponse, error)
+	// Every page with its track, and which pages touch
+	GetPageGraph(ctx context.Context, in *PageGraphRequest, opts ...grpc.CallOption) (*PageGraphResponse, error)
+	// Node rectangles and split lines of the KD-tree or quadtree
+	GetTreeStructure(ctx context.Context, in *TreeStructureRequest, opts ...grpc.CallOption) (*TreeStructureResponse, error)
+	// Health
+	IndexReady(ctx context.Context, in *IndexReadyRequest, opts ...grpc.CallOption) (*IndexReadyResponse, error)
+	// Library and server versions, uptime and index count
+	GetServerInfo(ctx context.Context, in *ServerInfoRequest, opts ...grpc.CallOption) (*ServerInfoResponse, error)
 	// Statistics
 	GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 	GetCount(ctx context.Context, in *CountRequest, opts ...grpc.CallOption) (*CountResponse, error)
 	GetBounds(ctx context.Context, in *BoundsRequest, opts ...grpc.CallOption) (*BoundsResponse, error)
+	// Debug: native indexes open in the process, including leaked ones
+	GetResourceStats(ctx context.Context, in *ResourceStatsRequest, opts ...grpc.CallOption) (*ResourceStatsResponse, error)
 	// Persistence
 	Save(ctx context.Context, in *SaveRequest, opts ...grpc.CallOption) (*SaveResponse, error)
 	Load(ctx context.Context, in *LoadIndexRequest, opts ...grpc.CallOption) (*LoadIndexResponse, error)
+	// Stream a serialized index to or from the client instead of a server path
+	StreamSave(ctx context.Context, in *StreamSaveRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IndexChunk], error)
+	StreamLoad(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StreamLoadRequest, LoadIndexResponse], error)
+	// Atomically replace an index with one built from fresh data
+	ReloadIndex(ctx context.Context, in *ReloadIndexRequest, opts ...grpc.CallOption) (*ReloadIndexResponse, error)
+	// Save a persistent index to its data file now, as --sync-interval does
+	// in the background
+	Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error)
 }
 
 type urbisServiceClient struct {
@@ -119,6 +220,26 @@ func (c *urbisServiceClient) ListIndexes(ctx context.Context, in *ListIndexesReq
 	return out, nil
 }
 
+func (c *urbisServiceClient) DescribeIndex(ctx context.Context, in *DescribeIndexRequest, opts ...grpc.CallOption) (*DescribeIndexResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DescribeIndexResponse)
+	err := c.cc.Invoke(ctx, UrbisService_DescribeIndex_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) MarkReadOnly(ctx context.Context, in *MarkReadOnlyRequest, opts ...grpc.CallOption) (*MarkReadOnlyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarkReadOnlyResponse)
+	err := c.cc.Invoke(ctx, UrbisService_MarkReadOnly_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *urbisServiceClient) LoadGeoJSON(ctx context.Context, in *LoadGeoJSONRequest, opts ...grpc.CallOption) (*LoadResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(LoadResponse)
@@ -139,6 +260,16 @@ func (c *urbisServiceClient) LoadGeoJSONString(ctx context.Context, in *LoadGeoJ
 	return out, nil
 }
 
+func (c *urbisServiceClient) LoadGeoJSONURL(ctx context.Context, in *LoadGeoJSONURLRequest, opts ...grpc.CallOption) (*LoadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoadResponse)
+	err := c.cc.Invoke(ctx, UrbisService_LoadGeoJSONURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *urbisServiceClient) LoadWKT(ctx context.Context, in *LoadWKTRequest, opts ...grpc.CallOption) (*LoadResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(LoadResponse)
@@ -149,6 +280,52 @@ func (c *urbisServiceClient) LoadWKT(ctx context.Context, in *LoadWKTRequest, op
 	return out, nil
 }
 
+func (c *urbisServiceClient) LoadWKB(ctx context.Context, in *LoadWKBRequest, opts ...grpc.CallOption) (*LoadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoadResponse)
+	err := c.cc.Invoke(ctx, UrbisService_LoadWKB_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) LoadGeoPackage(ctx context.Context, in *LoadGeoPackageRequest, opts ...grpc.CallOption) (*LoadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoadResponse)
+	err := c.cc.Invoke(ctx, UrbisService_LoadGeoPackage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) StreamLoadGeoJSON(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StreamLoadGeoJSONRequest, LoadResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UrbisService_ServiceDesc.Streams[0], UrbisService_StreamLoadGeoJSON_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamLoadGeoJSONRequest, LoadResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_StreamLoadGeoJSONClient = grpc.ClientStreamingClient[StreamLoadGeoJSONRequest, LoadResponse]
+
+func (c *urbisServiceClient) StreamLoadWKT(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StreamLoadWKTRequest, LoadResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UrbisService_ServiceDesc.Streams[1], UrbisService_StreamLoadWKT_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamLoadWKTRequest, LoadResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_StreamLoadWKTClient = grpc.ClientStreamingClient[StreamLoadWKTRequest, LoadResponse]
+
 func (c *urbisServiceClient) InsertPoint(ctx context.Context, in *InsertPointRequest, opts ...grpc.CallOption) (*InsertResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(InsertResponse)
@@ -179,6 +356,19 @@ func (c *urbisServiceClient) InsertPolygon(ctx context.Context, in *InsertPolygo
 	return out, nil
 }
 
+func (c *urbisServiceClient) StreamInsert(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamInsertRequest, StreamInsertResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UrbisService_ServiceDesc.Streams[2], UrbisService_StreamInsert_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamInsertRequest, StreamInsertResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_StreamInsertClient = grpc.BidiStreamingClient[StreamInsertRequest, StreamInsertResponse]
+
 func (c *urbisServiceClient) Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*RemoveResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RemoveResponse)
@@ -189,6 +379,26 @@ func (c *urbisServiceClient) Remove(ctx context.Context, in *RemoveRequest, opts
 	return out, nil
 }
 
+func (c *urbisServiceClient) RemoveRange(ctx context.Context, in *RemoveRangeRequest, opts ...grpc.CallOption) (*RemoveRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveRangeResponse)
+	err := c.cc.Invoke(ctx, UrbisService_RemoveRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) SweepExpired(ctx context.Context, in *SweepExpiredRequest, opts ...grpc.CallOption) (*SweepExpiredResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SweepExpiredResponse)
+	err := c.cc.Invoke(ctx, UrbisService_SweepExpired_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *urbisServiceClient) GetObject(ctx context.Context, in *GetObjectRequest, opts ...grpc.CallOption) (*GetObjectResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetObjectResponse)
@@ -199,6 +409,36 @@ func (c *urbisServiceClient) GetObject(ctx context.Context, in *GetObjectRequest
 	return out, nil
 }
 
+func (c *urbisServiceClient) BatchGetObjects(ctx context.Context, in *BatchGetObjectsRequest, opts ...grpc.CallOption) (*BatchGetObjectsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetObjectsResponse)
+	err := c.cc.Invoke(ctx, UrbisService_BatchGetObjects_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) SetProperties(ctx context.Context, in *SetPropertiesRequest, opts ...grpc.CallOption) (*SetPropertiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetPropertiesResponse)
+	err := c.cc.Invoke(ctx, UrbisService_SetProperties_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) GetProperties(ctx context.Context, in *GetPropertiesRequest, opts ...grpc.CallOption) (*GetPropertiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPropertiesResponse)
+	err := c.cc.Invoke(ctx, UrbisService_GetProperties_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *urbisServiceClient) Build(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*BuildResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(BuildResponse)
@@ -209,6 +449,25 @@ func (c *urbisServiceClient) Build(ctx context.Context, in *BuildRequest, opts .
 	return out, nil
 }
 
+func (c *urbisServiceClient) BuildWithProgress(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BuildProgressResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UrbisService_ServiceDesc.Streams[3], UrbisService_BuildWithProgress_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BuildRequest, BuildProgressResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_BuildWithProgressClient = grpc.ServerStreamingClient[BuildProgressResponse]
+
 func (c *urbisServiceClient) Optimize(ctx context.Context, in *OptimizeRequest, opts ...grpc.CallOption) (*OptimizeResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(OptimizeResponse)
@@ -219,6 +478,26 @@ func (c *urbisServiceClient) Optimize(ctx context.Context, in *OptimizeRequest,
 	return out, nil
 }
 
+func (c *urbisServiceClient) Compact(ctx context.Context, in *CompactRequest, opts ...grpc.CallOption) (*CompactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompactResponse)
+	err := c.cc.Invoke(ctx, UrbisService_Compact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) AutoTune(ctx context.Context, in *AutoTuneRequest, opts ...grpc.CallOption) (*AutoTuneResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AutoTuneResponse)
+	err := c.cc.Invoke(ctx, UrbisService_AutoTune_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *urbisServiceClient) QueryRange(ctx context.Context, in *RangeQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(QueryResponse)
@@ -229,6 +508,36 @@ func (c *urbisServiceClient) QueryRange(ctx context.Context, in *RangeQueryReque
 	return out, nil
 }
 
+func (c *urbisServiceClient) EstimateCount(ctx context.Context, in *EstimateCountRequest, opts ...grpc.CallOption) (*EstimateCountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EstimateCountResponse)
+	err := c.cc.Invoke(ctx, UrbisService_EstimateCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) MultiQueryRange(ctx context.Context, in *MultiRangeQueryRequest, opts ...grpc.CallOption) (*MultiQueryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MultiQueryResponse)
+	err := c.cc.Invoke(ctx, UrbisService_MultiQueryRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) QueryRangeMulti(ctx context.Context, in *QueryRangeMultiRequest, opts ...grpc.CallOption) (*QueryRangeMultiResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryRangeMultiResponse)
+	err := c.cc.Invoke(ctx, UrbisService_QueryRangeMulti_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *urbisServiceClient) QueryPoint(ctx context.Context, in *PointQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(QueryResponse)
@@ -239,6 +548,36 @@ func (c *urbisServiceClient) QueryPoint(ctx context.Context, in *PointQueryReque
 	return out, nil
 }
 
+func (c *urbisServiceClient) QueryContaining(ctx context.Context, in *PointQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, UrbisService_QueryContaining_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) QueryBuffered(ctx context.Context, in *BufferQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, UrbisService_QueryBuffered_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) QueryContainingPolygon(ctx context.Context, in *ContainingPolygonQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, UrbisService_QueryContainingPolygon_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *urbisServiceClient) QueryKNN(ctx context.Context, in *KNNQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(QueryResponse)
@@ -249,6 +588,16 @@ func (c *urbisServiceClient) QueryKNN(ctx context.Context, in *KNNQueryRequest,
 	return out, nil
 }
 
+func (c *urbisServiceClient) Nearest(ctx context.Context, in *NearestRequest, opts ...grpc.CallOption) (*NearestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NearestResponse)
+	err := c.cc.Invoke(ctx, UrbisService_Nearest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *urbisServiceClient) QueryAdjacent(ctx context.Context, in *RangeQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(QueryResponse)
@@ -259,6 +608,55 @@ func (c *urbisServiceClient) QueryAdjacent(ctx context.Context, in *RangeQueryRe
 	return out, nil
 }
 
+func (c *urbisServiceClient) QueryChangedSince(ctx context.Context, in *ChangedSinceRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, UrbisService_QueryChangedSince_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) SnapshotScan(ctx context.Context, in *SnapshotScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SnapshotScanResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UrbisService_ServiceDesc.Streams[4], UrbisService_SnapshotScan_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SnapshotScanRequest, SnapshotScanResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_SnapshotScanClient = grpc.ServerStreamingClient[SnapshotScanResponse]
+
+func (c *urbisServiceClient) QueryByProperty(ctx context.Context, in *PropertyQueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, UrbisService_QueryByProperty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) ConvexHull(ctx context.Context, in *ConvexHullRequest, opts ...grpc.CallOption) (*ConvexHullResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConvexHullResponse)
+	err := c.cc.Invoke(ctx, UrbisService_ConvexHull_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *urbisServiceClient) FindAdjacentPages(ctx context.Context, in *AdjacentPagesRequest, opts ...grpc.CallOption) (*AdjacentPagesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(AdjacentPagesResponse)
@@ -269,6 +667,56 @@ func (c *urbisServiceClient) FindAdjacentPages(ctx context.Context, in *Adjacent
 	return out, nil
 }
 
+func (c *urbisServiceClient) PrefetchRegion(ctx context.Context, in *PrefetchRegionRequest, opts ...grpc.CallOption) (*PrefetchRegionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PrefetchRegionResponse)
+	err := c.cc.Invoke(ctx, UrbisService_PrefetchRegion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) GetPageGraph(ctx context.Context, in *PageGraphRequest, opts ...grpc.CallOption) (*PageGraphResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PageGraphResponse)
+	err := c.cc.Invoke(ctx, UrbisService_GetPageGraph_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) GetTreeStructure(ctx context.Context, in *TreeStructureRequest, opts ...grpc.CallOption) (*TreeStructureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TreeStructureResponse)
+	err := c.cc.Invoke(ctx, UrbisService_GetTreeStructure_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) IndexReady(ctx context.Context, in *IndexReadyRequest, opts ...grpc.CallOption) (*IndexReadyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IndexReadyResponse)
+	err := c.cc.Invoke(ctx, UrbisService_IndexReady_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) GetServerInfo(ctx context.Context, in *ServerInfoRequest, opts ...grpc.CallOption) (*ServerInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerInfoResponse)
+	err := c.cc.Invoke(ctx, UrbisService_GetServerInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *urbisServiceClient) GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(StatsResponse)
@@ -299,6 +747,16 @@ func (c *urbisServiceClient) GetBounds(ctx context.Context, in *BoundsRequest, o
 	return out, nil
 }
 
+func (c *urbisServiceClient) GetResourceStats(ctx context.Context, in *ResourceStatsRequest, opts ...grpc.CallOption) (*ResourceStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResourceStatsResponse)
+	err := c.cc.Invoke(ctx, UrbisService_GetResourceStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *urbisServiceClient) Save(ctx context.Context, in *SaveRequest, opts ...grpc.CallOption) (*SaveResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SaveResponse)
@@ -319,41 +777,157 @@ func (c *urbisServiceClient) Load(ctx context.Context, in *LoadIndexRequest, opt
 	return out, nil
 }
 
-// UrbisServiceServer is the server API for UrbisService service.
-// All implementations must embed UnimplementedUrbisServiceServer
-// for forward compatibility.
-type UrbisServiceServer interface {
-	// Index Management
-	CreateIndex(context.Context, *CreateIndexRequest) (*CreateIndexResponse, error)
-	DestroyIndex(context.Context, *DestroyIndexRequest) (*DestroyIndexResponse, error)
-	ListIndexes(context.Context, *ListIndexesRequest) (*ListIndexesResponse, error)
-	// Data Loading
+func (c *urbisServiceClient) StreamSave(ctx context.Context, in *StreamSaveRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IndexChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UrbisService_ServiceDesc.Streams[5], UrbisService_StreamSave_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamSaveRequest, IndexChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_StreamSaveClient = grpc.ServerStreamingClient[IndexChunk]
+
+func (c *urbisServiceClient) StreamLoad(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StreamLoadRequest, LoadIndexResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UrbisService_ServiceDesc.Streams[6], UrbisService_StreamLoad_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamLoadRequest, LoadIndexResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_StreamLoadClient = grpc.ClientStreamingClient[StreamLoadRequest, LoadIndexResponse]
+
+func (c *urbisServiceClient) ReloadIndex(ctx context.Context, in *ReloadIndexRequest, opts ...grpc.CallOption) (*ReloadIndexResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReloadIndexResponse)
+	err := c.cc.Invoke(ctx, UrbisService_ReloadIndex_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *urbisServiceClient) Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncResponse)
+	err := c.cc.Invoke(ctx, UrbisService_Sync_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UrbisServiceServer is the server API for UrbisService service.
+// All implementations must embed UnimplementedUrbisServiceServer
+// for forward compatibility.
+type UrbisServiceServer interface {
+	// Index Management
+	CreateIndex(context.Context, *CreateIndexRequest) (*CreateIndexResponse, error)
+	DestroyIndex(context.Context, *DestroyIndexRequest) (*DestroyIndexResponse, error)
+	ListIndexes(context.Context, *ListIndexesRequest) (*ListIndexesResponse, error)
+	DescribeIndex(context.Context, *DescribeIndexRequest) (*DescribeIndexResponse, error)
+	// Make an index reject writes and builds for good, e.g. on a serving replica
+	MarkReadOnly(context.Context, *MarkReadOnlyRequest) (*MarkReadOnlyResponse, error)
+	// Data Loading
 	LoadGeoJSON(context.Context, *LoadGeoJSONRequest) (*LoadResponse, error)
 	LoadGeoJSONString(context.Context, *LoadGeoJSONStringRequest) (*LoadResponse, error)
+	LoadGeoJSONURL(context.Context, *LoadGeoJSONURLRequest) (*LoadResponse, error)
 	LoadWKT(context.Context, *LoadWKTRequest) (*LoadResponse, error)
+	LoadWKB(context.Context, *LoadWKBRequest) (*LoadResponse, error)
+	LoadGeoPackage(context.Context, *LoadGeoPackageRequest) (*LoadResponse, error)
+	StreamLoadGeoJSON(grpc.ClientStreamingServer[StreamLoadGeoJSONRequest, LoadResponse]) error
+	StreamLoadWKT(grpc.ClientStreamingServer[StreamLoadWKTRequest, LoadResponse]) error
 	// Object Operations
 	InsertPoint(context.Context, *InsertPointRequest) (*InsertResponse, error)
 	InsertLineString(context.Context, *InsertLineStringRequest) (*InsertResponse, error)
 	InsertPolygon(context.Context, *InsertPolygonRequest) (*InsertResponse, error)
+	// Insert a stream of geometries, acknowledging each one as it is applied
+	StreamInsert(grpc.BidiStreamingServer[StreamInsertRequest, StreamInsertResponse]) error
 	Remove(context.Context, *RemoveRequest) (*RemoveResponse, error)
+	RemoveRange(context.Context, *RemoveRangeRequest) (*RemoveRangeResponse, error)
+	// Remove objects whose ttl_ms has passed now rather than on the next background sweep
+	SweepExpired(context.Context, *SweepExpiredRequest) (*SweepExpiredResponse, error)
 	GetObject(context.Context, *GetObjectRequest) (*GetObjectResponse, error)
+	BatchGetObjects(context.Context, *BatchGetObjectsRequest) (*BatchGetObjectsResponse, error)
+	SetProperties(context.Context, *SetPropertiesRequest) (*SetPropertiesResponse, error)
+	GetProperties(context.Context, *GetPropertiesRequest) (*GetPropertiesResponse, error)
 	// Index Building
 	Build(context.Context, *BuildRequest) (*BuildResponse, error)
+	BuildWithProgress(*BuildRequest, grpc.ServerStreamingServer[BuildProgressResponse]) error
 	Optimize(context.Context, *OptimizeRequest) (*OptimizeResponse, error)
+	// Repack objects onto as few pages as possible after heavy removal
+	Compact(context.Context, *CompactRequest) (*CompactResponse, error)
+	// Recommend (and optionally apply) a page capacity for the current data
+	AutoTune(context.Context, *AutoTuneRequest) (*AutoTuneResponse, error)
 	// Spatial Queries
 	QueryRange(context.Context, *RangeQueryRequest) (*QueryResponse, error)
+	// Upper bound on the objects QueryRange would return, without reading them
+	EstimateCount(context.Context, *EstimateCountRequest) (*EstimateCountResponse, error)
+	// Several ranges in one call, e.g. adjacent map tiles
+	MultiQueryRange(context.Context, *MultiRangeQueryRequest) (*MultiQueryResponse, error)
+	// One range across several indexes at once, e.g. the shards of a region
+	QueryRangeMulti(context.Context, *QueryRangeMultiRequest) (*QueryRangeMultiResponse, error)
 	QueryPoint(context.Context, *PointQueryRequest) (*QueryResponse, error)
+	// Polygons whose interior contains the point; boundary points are not contained
+	QueryContaining(context.Context, *PointQueryRequest) (*QueryResponse, error)
+	// Objects within a distance of a geometry, e.g. along a route
+	QueryBuffered(context.Context, *BufferQueryRequest) (*QueryResponse, error)
+	// Polygons that contain a polygon, e.g. the regions a parcel lies in; shared edges count as contained
+	QueryContainingPolygon(context.Context, *ContainingPolygonQueryRequest) (*QueryResponse, error)
 	QueryKNN(context.Context, *KNNQueryRequest) (*QueryResponse, error)
+	// The single nearest object and its distance; NOT_FOUND on an empty index
+	Nearest(context.Context, *NearestRequest) (*NearestResponse, error)
 	QueryAdjacent(context.Context, *RangeQueryRequest) (*QueryResponse, error)
+	// Objects inserted or modified at or after a time, in change order
+	QueryChangedSince(context.Context, *ChangedSinceRequest) (*QueryResponse, error)
+	// Every object as of the call, streamed in batches; writes made while the
+	// stream is open are not seen
+	SnapshotScan(*SnapshotScanRequest, grpc.ServerStreamingServer[SnapshotScanResponse]) error
+	// Objects whose property key has a value, from the attribute index
+	QueryByProperty(context.Context, *PropertyQueryRequest) (*QueryResponse, error)
+	// Footprint of the objects in a region
+	ConvexHull(context.Context, *ConvexHullRequest) (*ConvexHullResponse, error)
 	// Disk-Aware Operations
 	FindAdjacentPages(context.Context, *AdjacentPagesRequest) (*AdjacentPagesResponse, error)
+	// Warm the page cache with the pages FindAdjacentPages would return
+	PrefetchRegion(context.Context, *PrefetchRegionRequest) (*PrefetchRegionResponse, error)
+	// Every page with its track, and which pages touch
+	GetPageGraph(context.Context, *PageGraphRequest) (*PageGraphResponse, error)
+	// Node rectangles and split lines of the KD-tree or quadtree
+	GetTreeStructure(context.Context, *TreeStructureRequest) (*TreeStructureResponse, error)
+	// Health
+	IndexReady(context.Context, *IndexReadyRequest) (*IndexReadyResponse, error)
+	// Library and server versions, uptime and index count
+	GetServerInfo(context.Context, *ServerInfoRequest) (*ServerInfoResponse, error)
 	// Statistics
 	GetStats(context.Context, *StatsRequest) (*StatsResponse, error)
 	GetCount(context.Context, *CountRequest) (*CountResponse, error)
 	GetBounds(context.Context, *BoundsRequest) (*BoundsResponse, error)
+	// Debug: native indexes open in the process, including leaked ones
+	GetResourceStats(context.Context, *ResourceStatsRequest) (*ResourceStatsResponse, error)
 	// Persistence
 	Save(context.Context, *SaveRequest) (*SaveResponse, error)
 	Load(context.Context, *LoadIndexRequest) (*LoadIndexResponse, error)
+	// Stream a serialized index to or from the client instead of a server path
+	StreamSave(*StreamSaveRequest, grpc.ServerStreamingServer[IndexChunk]) error
+	StreamLoad(grpc.ClientStreamingServer[StreamLoadRequest, LoadIndexResponse]) error
+	// Atomically replace an index with one built from fresh data
+	ReloadIndex(context.Context, *ReloadIndexRequest) (*ReloadIndexResponse, error)
+	// Save a persistent index to its data file now, as --sync-interval does
+	// in the background
+	Sync(context.Context, *SyncRequest) (*SyncResponse, error)
 	mustEmbedUnimplementedUrbisServiceServer()
 }
 
@@ -373,15 +947,36 @@ func (UnimplementedUrbisServiceServer) DestroyIndex(context.Context, *DestroyInd
 func (UnimplementedUrbisServiceServer) ListIndexes(context.Context, *ListIndexesRequest) (*ListIndexesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListIndexes not implemented")
 }
+func (UnimplementedUrbisServiceServer) DescribeIndex(context.Context, *DescribeIndexRequest) (*DescribeIndexResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DescribeIndex not implemented")
+}
+func (UnimplementedUrbisServiceServer) MarkReadOnly(context.Context, *MarkReadOnlyRequest) (*MarkReadOnlyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MarkReadOnly not implemented")
+}
 func (UnimplementedUrbisServiceServer) LoadGeoJSON(context.Context, *LoadGeoJSONRequest) (*LoadResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method LoadGeoJSON not implemented")
 }
 func (UnimplementedUrbisServiceServer) LoadGeoJSONString(context.Context, *LoadGeoJSONStringRequest) (*LoadResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method LoadGeoJSONString not implemented")
 }
+func (UnimplementedUrbisServiceServer) LoadGeoJSONURL(context.Context, *LoadGeoJSONURLRequest) (*LoadResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LoadGeoJSONURL not implemented")
+}
 func (UnimplementedUrbisServiceServer) LoadWKT(context.Context, *LoadWKTRequest) (*LoadResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method LoadWKT not implemented")
 }
+func (UnimplementedUrbisServiceServer) LoadWKB(context.Context, *LoadWKBRequest) (*LoadResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LoadWKB not implemented")
+}
+func (UnimplementedUrbisServiceServer) LoadGeoPackage(context.Context, *LoadGeoPackageRequest) (*LoadResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LoadGeoPackage not implemented")
+}
+func (UnimplementedUrbisServiceServer) StreamLoadGeoJSON(grpc.ClientStreamingServer[StreamLoadGeoJSONRequest, LoadResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamLoadGeoJSON not implemented")
+}
+func (UnimplementedUrbisServiceServer) StreamLoadWKT(grpc.ClientStreamingServer[StreamLoadWKTRequest, LoadResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamLoadWKT not implemented")
+}
 func (UnimplementedUrbisServiceServer) InsertPoint(context.Context, *InsertPointRequest) (*InsertResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method InsertPoint not implemented")
 }
@@ -391,33 +986,108 @@ func (UnimplementedUrbisServiceServer) InsertLineString(context.Context, *Insert
 func (UnimplementedUrbisServiceServer) InsertPolygon(context.Context, *InsertPolygonRequest) (*InsertResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method InsertPolygon not implemented")
 }
+func (UnimplementedUrbisServiceServer) StreamInsert(grpc.BidiStreamingServer[StreamInsertRequest, StreamInsertResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamInsert not implemented")
+}
 func (UnimplementedUrbisServiceServer) Remove(context.Context, *RemoveRequest) (*RemoveResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Remove not implemented")
 }
+func (UnimplementedUrbisServiceServer) RemoveRange(context.Context, *RemoveRangeRequest) (*RemoveRangeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveRange not implemented")
+}
+func (UnimplementedUrbisServiceServer) SweepExpired(context.Context, *SweepExpiredRequest) (*SweepExpiredResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SweepExpired not implemented")
+}
 func (UnimplementedUrbisServiceServer) GetObject(context.Context, *GetObjectRequest) (*GetObjectResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetObject not implemented")
 }
+func (UnimplementedUrbisServiceServer) BatchGetObjects(context.Context, *BatchGetObjectsRequest) (*BatchGetObjectsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchGetObjects not implemented")
+}
+func (UnimplementedUrbisServiceServer) SetProperties(context.Context, *SetPropertiesRequest) (*SetPropertiesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetProperties not implemented")
+}
+func (UnimplementedUrbisServiceServer) GetProperties(context.Context, *GetPropertiesRequest) (*GetPropertiesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetProperties not implemented")
+}
 func (UnimplementedUrbisServiceServer) Build(context.Context, *BuildRequest) (*BuildResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Build not implemented")
 }
+func (UnimplementedUrbisServiceServer) BuildWithProgress(*BuildRequest, grpc.ServerStreamingServer[BuildProgressResponse]) error {
+	return status.Error(codes.Unimplemented, "method BuildWithProgress not implemented")
+}
 func (UnimplementedUrbisServiceServer) Optimize(context.Context, *OptimizeRequest) (*OptimizeResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Optimize not implemented")
 }
+func (UnimplementedUrbisServiceServer) Compact(context.Context, *CompactRequest) (*CompactResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Compact not implemented")
+}
+func (UnimplementedUrbisServiceServer) AutoTune(context.Context, *AutoTuneRequest) (*AutoTuneResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AutoTune not implemented")
+}
 func (UnimplementedUrbisServiceServer) QueryRange(context.Context, *RangeQueryRequest) (*QueryResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method QueryRange not implemented")
 }
+func (UnimplementedUrbisServiceServer) EstimateCount(context.Context, *EstimateCountRequest) (*EstimateCountResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EstimateCount not implemented")
+}
+func (UnimplementedUrbisServiceServer) MultiQueryRange(context.Context, *MultiRangeQueryRequest) (*MultiQueryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MultiQueryRange not implemented")
+}
+func (UnimplementedUrbisServiceServer) QueryRangeMulti(context.Context, *QueryRangeMultiRequest) (*QueryRangeMultiResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method QueryRangeMulti not implemented")
+}
 func (UnimplementedUrbisServiceServer) QueryPoint(context.Context, *PointQueryRequest) (*QueryResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method QueryPoint not implemented")
 }
+func (UnimplementedUrbisServiceServer) QueryContaining(context.Context, *PointQueryRequest) (*QueryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method QueryContaining not implemented")
+}
+func (UnimplementedUrbisServiceServer) QueryBuffered(context.Context, *BufferQueryRequest) (*QueryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method QueryBuffered not implemented")
+}
+func (UnimplementedUrbisServiceServer) QueryContainingPolygon(context.Context, *ContainingPolygonQueryRequest) (*QueryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method QueryContainingPolygon not implemented")
+}
 func (UnimplementedUrbisServiceServer) QueryKNN(context.Context, *KNNQueryRequest) (*QueryResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method QueryKNN not implemented")
 }
+func (UnimplementedUrbisServiceServer) Nearest(context.Context, *NearestRequest) (*NearestResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Nearest not implemented")
+}
 func (UnimplementedUrbisServiceServer) QueryAdjacent(context.Context, *RangeQueryRequest) (*QueryResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method QueryAdjacent not implemented")
 }
+func (UnimplementedUrbisServiceServer) QueryChangedSince(context.Context, *ChangedSinceRequest) (*QueryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method QueryChangedSince not implemented")
+}
+func (UnimplementedUrbisServiceServer) SnapshotScan(*SnapshotScanRequest, grpc.ServerStreamingServer[SnapshotScanResponse]) error {
+	return status.Error(codes.Unimplemented, "method SnapshotScan not implemented")
+}
+func (UnimplementedUrbisServiceServer) QueryByProperty(context.Context, *PropertyQueryRequest) (*QueryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method QueryByProperty not implemented")
+}
+func (UnimplementedUrbisServiceServer) ConvexHull(context.Context, *ConvexHullRequest) (*ConvexHullResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ConvexHull not implemented")
+}
 func (UnimplementedUrbisServiceServer) FindAdjacentPages(context.Context, *AdjacentPagesRequest) (*AdjacentPagesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method FindAdjacentPages not implemented")
 }
+func (UnimplementedUrbisServiceServer) PrefetchRegion(context.Context, *PrefetchRegionRequest) (*PrefetchRegionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PrefetchRegion not implemented")
+}
+func (UnimplementedUrbisServiceServer) GetPageGraph(context.Context, *PageGraphRequest) (*PageGraphResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPageGraph not implemented")
+}
+func (UnimplementedUrbisServiceServer) GetTreeStructure(context.Context, *TreeStructureRequest) (*TreeStructureResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTreeStructure not implemented")
+}
+func (UnimplementedUrbisServiceServer) IndexReady(context.Context, *IndexReadyRequest) (*IndexReadyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method IndexReady not implemented")
+}
+func (UnimplementedUrbisServiceServer) GetServerInfo(context.Context, *ServerInfoRequest) (*ServerInfoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetServerInfo not implemented")
+}
 func (UnimplementedUrbisServiceServer) GetStats(context.Context, *StatsRequest) (*StatsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetStats not implemented")
 }
@@ -427,12 +1097,27 @@ func (UnimplementedUrbisServiceServer) GetCount(context.Context, *CountRequest)
 func (UnimplementedUrbisServiceServer) GetBounds(context.Context, *BoundsRequest) (*BoundsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetBounds not implemented")
 }
+func (UnimplementedUrbisServiceServer) GetResourceStats(context.Context, *ResourceStatsRequest) (*ResourceStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetResourceStats not implemented")
+}
 func (UnimplementedUrbisServiceServer) Save(context.Context, *SaveRequest) (*SaveResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Save not implemented")
 }
 func (UnimplementedUrbisServiceServer) Load(context.Context, *LoadIndexRequest) (*LoadIndexResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Load not implemented")
 }
+func (UnimplementedUrbisServiceServer) StreamSave(*StreamSaveRequest, grpc.ServerStreamingServer[IndexChunk]) error {
+	return status.Error(codes.Unimplemented, "method StreamSave not implemented")
+}
+func (UnimplementedUrbisServiceServer) StreamLoad(grpc.ClientStreamingServer[StreamLoadRequest, LoadIndexResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamLoad not implemented")
+}
+func (UnimplementedUrbisServiceServer) ReloadIndex(context.Context, *ReloadIndexRequest) (*ReloadIndexResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReloadIndex not implemented")
+}
+func (UnimplementedUrbisServiceServer) Sync(context.Context, *SyncRequest) (*SyncResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Sync not implemented")
+}
 func (UnimplementedUrbisServiceServer) mustEmbedUnimplementedUrbisServiceServer() {}
 func (UnimplementedUrbisServiceServer) testEmbeddedByValue()                      {}
 
@@ -443,337 +1128,866 @@ type UnsafeUrbisServiceServer interface {
 	mustEmbedUnimplementedUrbisServiceServer()
 }
 
-func RegisterUrbisServiceServer(s grpc.ServiceRegistrar, srv UrbisServiceServer) {
-	// If the following call panics, it indicates UnimplementedUrbisServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func RegisterUrbisServiceServer(s grpc.ServiceRegistrar, srv UrbisServiceServer) {
+	// If the following call panics, it indicates UnimplementedUrbisServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UrbisService_ServiceDesc, srv)
+}
+
+func _UrbisService_CreateIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateIndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).CreateIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_CreateIndex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).CreateIndex(ctx, req.(*CreateIndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_DestroyIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DestroyIndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).DestroyIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_DestroyIndex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).DestroyIndex(ctx, req.(*DestroyIndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_ListIndexes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIndexesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).ListIndexes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_ListIndexes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).ListIndexes(ctx, req.(*ListIndexesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_DescribeIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeIndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).DescribeIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_DescribeIndex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).DescribeIndex(ctx, req.(*DescribeIndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_MarkReadOnly_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkReadOnlyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).MarkReadOnly(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_MarkReadOnly_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).MarkReadOnly(ctx, req.(*MarkReadOnlyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_LoadGeoJSON_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadGeoJSONRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).LoadGeoJSON(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_LoadGeoJSON_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).LoadGeoJSON(ctx, req.(*LoadGeoJSONRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_LoadGeoJSONString_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadGeoJSONStringRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).LoadGeoJSONString(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_LoadGeoJSONString_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).LoadGeoJSONString(ctx, req.(*LoadGeoJSONStringRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_LoadGeoJSONURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadGeoJSONURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).LoadGeoJSONURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_LoadGeoJSONURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).LoadGeoJSONURL(ctx, req.(*LoadGeoJSONURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_LoadWKT_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadWKTRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).LoadWKT(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_LoadWKT_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).LoadWKT(ctx, req.(*LoadWKTRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_LoadWKB_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadWKBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).LoadWKB(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_LoadWKB_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).LoadWKB(ctx, req.(*LoadWKBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_LoadGeoPackage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadGeoPackageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).LoadGeoPackage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_LoadGeoPackage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).LoadGeoPackage(ctx, req.(*LoadGeoPackageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_StreamLoadGeoJSON_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UrbisServiceServer).StreamLoadGeoJSON(&grpc.GenericServerStream[StreamLoadGeoJSONRequest, LoadResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_StreamLoadGeoJSONServer = grpc.ClientStreamingServer[StreamLoadGeoJSONRequest, LoadResponse]
+
+func _UrbisService_StreamLoadWKT_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UrbisServiceServer).StreamLoadWKT(&grpc.GenericServerStream[StreamLoadWKTRequest, LoadResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_StreamLoadWKTServer = grpc.ClientStreamingServer[StreamLoadWKTRequest, LoadResponse]
+
+func _UrbisService_InsertPoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertPointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).InsertPoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_InsertPoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).InsertPoint(ctx, req.(*InsertPointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_InsertLineString_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertLineStringRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).InsertLineString(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_InsertLineString_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).InsertLineString(ctx, req.(*InsertLineStringRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_InsertPolygon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertPolygonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).InsertPolygon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_InsertPolygon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).InsertPolygon(ctx, req.(*InsertPolygonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_StreamInsert_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UrbisServiceServer).StreamInsert(&grpc.GenericServerStream[StreamInsertRequest, StreamInsertResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_StreamInsertServer = grpc.BidiStreamingServer[StreamInsertRequest, StreamInsertResponse]
+
+func _UrbisService_Remove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).Remove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_Remove_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).Remove(ctx, req.(*RemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_RemoveRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).RemoveRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_RemoveRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).RemoveRange(ctx, req.(*RemoveRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_SweepExpired_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SweepExpiredRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).SweepExpired(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_SweepExpired_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).SweepExpired(ctx, req.(*SweepExpiredRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_GetObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetObjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).GetObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_GetObject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).GetObject(ctx, req.(*GetObjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_BatchGetObjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetObjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).BatchGetObjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_BatchGetObjects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).BatchGetObjects(ctx, req.(*BatchGetObjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_SetProperties_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPropertiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).SetProperties(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_SetProperties_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).SetProperties(ctx, req.(*SetPropertiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_GetProperties_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPropertiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).GetProperties(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_GetProperties_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).GetProperties(ctx, req.(*GetPropertiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_Build_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).Build(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_Build_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).Build(ctx, req.(*BuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_BuildWithProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BuildRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UrbisServiceServer).BuildWithProgress(m, &grpc.GenericServerStream[BuildRequest, BuildProgressResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_BuildWithProgressServer = grpc.ServerStreamingServer[BuildProgressResponse]
+
+func _UrbisService_Optimize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OptimizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).Optimize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_Optimize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).Optimize(ctx, req.(*OptimizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_Compact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).Compact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_Compact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).Compact(ctx, req.(*CompactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_AutoTune_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AutoTuneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).AutoTune(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_AutoTune_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).AutoTune(ctx, req.(*AutoTuneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_QueryRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RangeQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).QueryRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_QueryRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).QueryRange(ctx, req.(*RangeQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_EstimateCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&UrbisService_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).EstimateCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_EstimateCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).EstimateCount(ctx, req.(*EstimateCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_CreateIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateIndexRequest)
+func _UrbisService_MultiQueryRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiRangeQueryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).CreateIndex(ctx, in)
+		return srv.(UrbisServiceServer).MultiQueryRange(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_CreateIndex_FullMethodName,
+		FullMethod: UrbisService_MultiQueryRange_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).CreateIndex(ctx, req.(*CreateIndexRequest))
+		return srv.(UrbisServiceServer).MultiQueryRange(ctx, req.(*MultiRangeQueryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_DestroyIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DestroyIndexRequest)
+func _UrbisService_QueryRangeMulti_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRangeMultiRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).DestroyIndex(ctx, in)
+		return srv.(UrbisServiceServer).QueryRangeMulti(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_DestroyIndex_FullMethodName,
+		FullMethod: UrbisService_QueryRangeMulti_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).DestroyIndex(ctx, req.(*DestroyIndexRequest))
+		return srv.(UrbisServiceServer).QueryRangeMulti(ctx, req.(*QueryRangeMultiRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_ListIndexes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListIndexesRequest)
+func _UrbisService_QueryPoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PointQueryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).ListIndexes(ctx, in)
+		return srv.(UrbisServiceServer).QueryPoint(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_ListIndexes_FullMethodName,
+		FullMethod: UrbisService_QueryPoint_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).ListIndexes(ctx, req.(*ListIndexesRequest))
+		return srv.(UrbisServiceServer).QueryPoint(ctx, req.(*PointQueryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_LoadGeoJSON_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoadGeoJSONRequest)
+func _UrbisService_QueryContaining_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PointQueryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).LoadGeoJSON(ctx, in)
+		return srv.(UrbisServiceServer).QueryContaining(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_LoadGeoJSON_FullMethodName,
+		FullMethod: UrbisService_QueryContaining_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).LoadGeoJSON(ctx, req.(*LoadGeoJSONRequest))
+		return srv.(UrbisServiceServer).QueryContaining(ctx, req.(*PointQueryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_LoadGeoJSONString_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoadGeoJSONStringRequest)
+func _UrbisService_QueryBuffered_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BufferQueryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).LoadGeoJSONString(ctx, in)
+		return srv.(UrbisServiceServer).QueryBuffered(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_LoadGeoJSONString_FullMethodName,
+		FullMethod: UrbisService_QueryBuffered_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).LoadGeoJSONString(ctx, req.(*LoadGeoJSONStringRequest))
+		return srv.(UrbisServiceServer).QueryBuffered(ctx, req.(*BufferQueryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_LoadWKT_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoadWKTRequest)
+func _UrbisService_QueryContainingPolygon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContainingPolygonQueryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).LoadWKT(ctx, in)
+		return srv.(UrbisServiceServer).QueryContainingPolygon(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_LoadWKT_FullMethodName,
+		FullMethod: UrbisService_QueryContainingPolygon_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).LoadWKT(ctx, req.(*LoadWKTRequest))
+		return srv.(UrbisServiceServer).QueryContainingPolygon(ctx, req.(*ContainingPolygonQueryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_InsertPoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(InsertPointRequest)
+func _UrbisService_QueryKNN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KNNQueryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).InsertPoint(ctx, in)
+		return srv.(UrbisServiceServer).QueryKNN(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_InsertPoint_FullMethodName,
+		FullMethod: UrbisService_QueryKNN_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).InsertPoint(ctx, req.(*InsertPointRequest))
+		return srv.(UrbisServiceServer).QueryKNN(ctx, req.(*KNNQueryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_InsertLineString_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(InsertLineStringRequest)
+func _UrbisService_Nearest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NearestRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).InsertLineString(ctx, in)
+		return srv.(UrbisServiceServer).Nearest(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_InsertLineString_FullMethodName,
+		FullMethod: UrbisService_Nearest_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).InsertLineString(ctx, req.(*InsertLineStringRequest))
+		return srv.(UrbisServiceServer).Nearest(ctx, req.(*NearestRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_InsertPolygon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(InsertPolygonRequest)
+func _UrbisService_QueryAdjacent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RangeQueryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).InsertPolygon(ctx, in)
+		return srv.(UrbisServiceServer).QueryAdjacent(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_InsertPolygon_FullMethodName,
+		FullMethod: UrbisService_QueryAdjacent_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).InsertPolygon(ctx, req.(*InsertPolygonRequest))
+		return srv.(UrbisServiceServer).QueryAdjacent(ctx, req.(*RangeQueryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_Remove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RemoveRequest)
+func _UrbisService_QueryChangedSince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangedSinceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).Remove(ctx, in)
+		return srv.(UrbisServiceServer).QueryChangedSince(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_Remove_FullMethodName,
+		FullMethod: UrbisService_QueryChangedSince_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).Remove(ctx, req.(*RemoveRequest))
+		return srv.(UrbisServiceServer).QueryChangedSince(ctx, req.(*ChangedSinceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_GetObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetObjectRequest)
+func _UrbisService_SnapshotScan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SnapshotScanRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UrbisServiceServer).SnapshotScan(m, &grpc.GenericServerStream[SnapshotScanRequest, SnapshotScanResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_SnapshotScanServer = grpc.ServerStreamingServer[SnapshotScanResponse]
+
+func _UrbisService_QueryByProperty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PropertyQueryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).GetObject(ctx, in)
+		return srv.(UrbisServiceServer).QueryByProperty(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_GetObject_FullMethodName,
+		FullMethod: UrbisService_QueryByProperty_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).GetObject(ctx, req.(*GetObjectRequest))
+		return srv.(UrbisServiceServer).QueryByProperty(ctx, req.(*PropertyQueryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_Build_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BuildRequest)
+func _UrbisService_ConvexHull_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvexHullRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).Build(ctx, in)
+		return srv.(UrbisServiceServer).ConvexHull(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_Build_FullMethodName,
+		FullMethod: UrbisService_ConvexHull_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).Build(ctx, req.(*BuildRequest))
+		return srv.(UrbisServiceServer).ConvexHull(ctx, req.(*ConvexHullRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_Optimize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OptimizeRequest)
+func _UrbisService_FindAdjacentPages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjacentPagesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).Optimize(ctx, in)
+		return srv.(UrbisServiceServer).FindAdjacentPages(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_Optimize_FullMethodName,
+		FullMethod: UrbisService_FindAdjacentPages_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).Optimize(ctx, req.(*OptimizeRequest))
+		return srv.(UrbisServiceServer).FindAdjacentPages(ctx, req.(*AdjacentPagesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_QueryRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RangeQueryRequest)
+func _UrbisService_PrefetchRegion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrefetchRegionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).QueryRange(ctx, in)
+		return srv.(UrbisServiceServer).PrefetchRegion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_QueryRange_FullMethodName,
+		FullMethod: UrbisService_PrefetchRegion_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).QueryRange(ctx, req.(*RangeQueryRequest))
+		return srv.(UrbisServiceServer).PrefetchRegion(ctx, req.(*PrefetchRegionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_QueryPoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PointQueryRequest)
+func _UrbisService_GetPageGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PageGraphRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).QueryPoint(ctx, in)
+		return srv.(UrbisServiceServer).GetPageGraph(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_QueryPoint_FullMethodName,
+		FullMethod: UrbisService_GetPageGraph_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).QueryPoint(ctx, req.(*PointQueryRequest))
+		return srv.(UrbisServiceServer).GetPageGraph(ctx, req.(*PageGraphRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_QueryKNN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(KNNQueryRequest)
+func _UrbisService_GetTreeStructure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TreeStructureRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).QueryKNN(ctx, in)
+		return srv.(UrbisServiceServer).GetTreeStructure(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_QueryKNN_FullMethodName,
+		FullMethod: UrbisService_GetTreeStructure_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).QueryKNN(ctx, req.(*KNNQueryRequest))
+		return srv.(UrbisServiceServer).GetTreeStructure(ctx, req.(*TreeStructureRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_QueryAdjacent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RangeQueryRequest)
+func _UrbisService_IndexReady_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IndexReadyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).QueryAdjacent(ctx, in)
+		return srv.(UrbisServiceServer).IndexReady(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_QueryAdjacent_FullMethodName,
+		FullMethod: UrbisService_IndexReady_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).QueryAdjacent(ctx, req.(*RangeQueryRequest))
+		return srv.(UrbisServiceServer).IndexReady(ctx, req.(*IndexReadyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UrbisService_FindAdjacentPages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AdjacentPagesRequest)
+func _UrbisService_GetServerInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UrbisServiceServer).FindAdjacentPages(ctx, in)
+		return srv.(UrbisServiceServer).GetServerInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UrbisService_FindAdjacentPages_FullMethodName,
+		FullMethod: UrbisService_GetServerInfo_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UrbisServiceServer).FindAdjacentPages(ctx, req.(*AdjacentPagesRequest))
+		return srv.(UrbisServiceServer).GetServerInfo(ctx, req.(*ServerInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -832,6 +2046,24 @@ func _UrbisService_GetBounds_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UrbisService_GetResourceStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResourceStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).GetResourceStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_GetResourceStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).GetResourceStats(ctx, req.(*ResourceStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UrbisService_Save_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SaveRequest)
 	if err := dec(in); err != nil {
@@ -868,6 +2100,60 @@ func _UrbisService_Load_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UrbisService_StreamSave_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamSaveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UrbisServiceServer).StreamSave(m, &grpc.GenericServerStream[StreamSaveRequest, IndexChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_StreamSaveServer = grpc.ServerStreamingServer[IndexChunk]
+
+func _UrbisService_StreamLoad_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UrbisServiceServer).StreamLoad(&grpc.GenericServerStream[StreamLoadRequest, LoadIndexResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UrbisService_StreamLoadServer = grpc.ClientStreamingServer[StreamLoadRequest, LoadIndexResponse]
+
+func _UrbisService_ReloadIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadIndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).ReloadIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_ReloadIndex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).ReloadIndex(ctx, req.(*ReloadIndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UrbisService_Sync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UrbisServiceServer).Sync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UrbisService_Sync_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UrbisServiceServer).Sync(ctx, req.(*SyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UrbisService_ServiceDesc is the grpc.ServiceDesc for UrbisService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -887,6 +2173,14 @@ var UrbisService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListIndexes",
 			Handler:    _UrbisService_ListIndexes_Handler,
 		},
+		{
+			MethodName: "DescribeIndex",
+			Handler:    _UrbisService_DescribeIndex_Handler,
+		},
+		{
+			MethodName: "MarkReadOnly",
+			Handler:    _UrbisService_MarkReadOnly_Handler,
+		},
 		{
 			MethodName: "LoadGeoJSON",
 			Handler:    _UrbisService_LoadGeoJSON_Handler,
@@ -895,10 +2189,22 @@ var UrbisService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LoadGeoJSONString",
 			Handler:    _UrbisService_LoadGeoJSONString_Handler,
 		},
+		{
+			MethodName: "LoadGeoJSONURL",
+			Handler:    _UrbisService_LoadGeoJSONURL_Handler,
+		},
 		{
 			MethodName: "LoadWKT",
 			Handler:    _UrbisService_LoadWKT_Handler,
 		},
+		{
+			MethodName: "LoadWKB",
+			Handler:    _UrbisService_LoadWKB_Handler,
+		},
+		{
+			MethodName: "LoadGeoPackage",
+			Handler:    _UrbisService_LoadGeoPackage_Handler,
+		},
 		{
 			MethodName: "InsertPoint",
 			Handler:    _UrbisService_InsertPoint_Handler,
@@ -915,10 +2221,30 @@ var UrbisService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Remove",
 			Handler:    _UrbisService_Remove_Handler,
 		},
+		{
+			MethodName: "RemoveRange",
+			Handler:    _UrbisService_RemoveRange_Handler,
+		},
+		{
+			MethodName: "SweepExpired",
+			Handler:    _UrbisService_SweepExpired_Handler,
+		},
 		{
 			MethodName: "GetObject",
 			Handler:    _UrbisService_GetObject_Handler,
 		},
+		{
+			MethodName: "BatchGetObjects",
+			Handler:    _UrbisService_BatchGetObjects_Handler,
+		},
+		{
+			MethodName: "SetProperties",
+			Handler:    _UrbisService_SetProperties_Handler,
+		},
+		{
+			MethodName: "GetProperties",
+			Handler:    _UrbisService_GetProperties_Handler,
+		},
 		{
 			MethodName: "Build",
 			Handler:    _UrbisService_Build_Handler,
@@ -927,26 +2253,94 @@ var UrbisService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Optimize",
 			Handler:    _UrbisService_Optimize_Handler,
 		},
+		{
+			MethodName: "Compact",
+			Handler:    _UrbisService_Compact_Handler,
+		},
+		{
+			MethodName: "AutoTune",
+			Handler:    _UrbisService_AutoTune_Handler,
+		},
 		{
 			MethodName: "QueryRange",
 			Handler:    _UrbisService_QueryRange_Handler,
 		},
+		{
+			MethodName: "EstimateCount",
+			Handler:    _UrbisService_EstimateCount_Handler,
+		},
+		{
+			MethodName: "MultiQueryRange",
+			Handler:    _UrbisService_MultiQueryRange_Handler,
+		},
+		{
+			MethodName: "QueryRangeMulti",
+			Handler:    _UrbisService_QueryRangeMulti_Handler,
+		},
 		{
 			MethodName: "QueryPoint",
 			Handler:    _UrbisService_QueryPoint_Handler,
 		},
+		{
+			MethodName: "QueryContaining",
+			Handler:    _UrbisService_QueryContaining_Handler,
+		},
+		{
+			MethodName: "QueryBuffered",
+			Handler:    _UrbisService_QueryBuffered_Handler,
+		},
+		{
+			MethodName: "QueryContainingPolygon",
+			Handler:    _UrbisService_QueryContainingPolygon_Handler,
+		},
 		{
 			MethodName: "QueryKNN",
 			Handler:    _UrbisService_QueryKNN_Handler,
 		},
+		{
+			MethodName: "Nearest",
+			Handler:    _UrbisService_Nearest_Handler,
+		},
 		{
 			MethodName: "QueryAdjacent",
 			Handler:    _UrbisService_QueryAdjacent_Handler,
 		},
+		{
+			MethodName: "QueryChangedSince",
+			Handler:    _UrbisService_QueryChangedSince_Handler,
+		},
+		{
+			MethodName: "QueryByProperty",
+			Handler:    _UrbisService_QueryByProperty_Handler,
+		},
+		{
+			MethodName: "ConvexHull",
+			Handler:    _UrbisService_ConvexHull_Handler,
+		},
 		{
 			MethodName: "FindAdjacentPages",
 			Handler:    _UrbisService_FindAdjacentPages_Handler,
 		},
+		{
+			MethodName: "PrefetchRegion",
+			Handler:    _UrbisService_PrefetchRegion_Handler,
+		},
+		{
+			MethodName: "GetPageGraph",
+			Handler:    _UrbisService_GetPageGraph_Handler,
+		},
+		{
+			MethodName: "GetTreeStructure",
+			Handler:    _UrbisService_GetTreeStructure_Handler,
+		},
+		{
+			MethodName: "IndexReady",
+			Handler:    _UrbisService_IndexReady_Handler,
+		},
+		{
+			MethodName: "GetServerInfo",
+			Handler:    _UrbisService_GetServerInfo_Handler,
+		},
 		{
 			MethodName: "GetStats",
 			Handler:    _UrbisService_GetStats_Handler,
@@ -959,6 +2353,10 @@ var UrbisService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBounds",
 			Handler:    _UrbisService_GetBounds_Handler,
 		},
+		{
+			MethodName: "GetResourceStats",
+			Handler:    _UrbisService_GetResourceStats_Handler,
+		},
 		{
 			MethodName: "Save",
 			Handler:    _UrbisService_Save_Handler,
@@ -967,7 +2365,52 @@ var UrbisService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Load",
 			Handler:    _UrbisService_Load_Handler,
 		},
+		{
+			MethodName: "ReloadIndex",
+			Handler:    _UrbisService_ReloadIndex_Handler,
+		},
+		{
+			MethodName: "Sync",
+			Handler:    _UrbisService_Sync_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLoadGeoJSON",
+			Handler:       _UrbisService_StreamLoadGeoJSON_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "StreamLoadWKT",
+			Handler:       _UrbisService_StreamLoadWKT_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "StreamInsert",
+			Handler:       _UrbisService_StreamInsert_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "BuildWithProgress",
+			Handler:       _UrbisService_BuildWithProgress_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SnapshotScan",
+			Handler:       _UrbisService_SnapshotScan_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamSave",
+			Handler:       _UrbisService_StreamSave_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamLoad",
+			Handler:       _UrbisService_StreamLoad_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "urbis.proto",
 }
@@ -0,0 +1,73 @@
+package urbis
+
+import (
+	"math"
+	"slices"
+)
+
+// WorldWidth returns the x extent of the world in the given CRS: 360
+// degrees for EPSG:4326 and for indexes with no CRS recorded (taken to be
+// longitude/latitude), and the Web Mercator circumference for EPSG:3857.
+// Range queries whose MinX is greater than MaxX wrap around at ±WorldWidth/2.
+func WorldWidth(crs int) float64 {
+	if crs == CRSWebMercator {
+		return 2 * math.Pi * earthRadius
+	}
+	return 360
+}
+
+// CrossesAntimeridian reports whether region wraps around the antimeridian,
+// i.e. runs east from MinX past ±180 to MaxX
+func (m MBR) CrossesAntimeridian() bool {
+	return m.MinX > m.MaxX
+}
+
+// splitAntimeridian splits a region crossing the antimeridian into its
+// eastern and western halves. Other regions are returned unchanged.
+func splitAntimeridian(region MBR, crs int) []MBR {
+	if !region.CrossesAntimeridian() {
+		return []MBR{region}
+	}
+	edge := WorldWidth(crs) / 2
+	east := region
+	east.MaxX = edge
+	west := region
+	west.MinX = -edge
+	return []MBR{east, west}
+}
+
+// mergeObjectLists joins the results of the parts of a split query. An
+// object found in more than one part is listed once unless keepDups is set,
+// the stats are summed and the failed pages are combined.
+func mergeObjectLists(lists []*ObjectList, keepDups bool) *ObjectList {
+	merged := &ObjectList{Objects: []*SpatialObject{}}
+	seen := make(map[uint64]bool)
+	for i, list := range lists {
+		for _, obj := range list.Objects {
+			if seen[obj.ID] && !keepDups {
+				merged.Stats.DuplicatesSuppressed++
+				continue
+			}
+			seen[obj.ID] = true
+			merged.Objects = append(merged.Objects, obj)
+		}
+
+		merged.Stats.PagesVisited += list.Stats.PagesVisited
+		merged.Stats.TracksVisited += list.Stats.TracksVisited
+		merged.Stats.EstimatedSeeks += list.Stats.EstimatedSeeks
+		merged.Stats.CacheHits += list.Stats.CacheHits
+		merged.Stats.CacheMisses += list.Stats.CacheMisses
+		merged.Stats.DuplicatesSuppressed += list.Stats.DuplicatesSuppressed
+		merged.Stats.StructureFallback = merged.Stats.StructureFallback || list.Stats.StructureFallback
+		if i == 0 {
+			merged.Stats.Structure = list.Stats.Structure
+		}
+		for _, page := range list.FailedPages {
+			if !slices.Contains(merged.FailedPages, page) {
+				merged.FailedPages = append(merged.FailedPages, page)
+			}
+		}
+	}
+	merged.Count = uint64(len(merged.Objects))
+	return merged
+}
@@ -0,0 +1,74 @@
+package urbis
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestQueryRangeAcrossAntimeridian(t *testing.T) {
+	idx, err := NewIndex(&Config{CRS: CRSWGS84})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	fiji, _ := idx.InsertPoint(178, -18)
+	samoa, _ := idx.InsertPoint(-172, -14)
+	idx.InsertPoint(0, -15)
+	idx.InsertPoint(-100, -15)
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	pacific := MBR{MinX: 170, MinY: -20, MaxX: -170, MaxY: -10}
+	result, err := idx.QueryRange(pacific)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []uint64
+	for _, obj := range result.Objects {
+		ids = append(ids, obj.ID)
+	}
+	slices.Sort(ids)
+	if want := []uint64{fiji, samoa}; !slices.Equal(ids, want) || result.Count != 2 {
+		t.Errorf("pacific query = %v (count %d), want %v", ids, result.Count, want)
+	}
+
+	lists, err := idx.QueryRanges([]MBR{pacific, {MinX: -1, MinY: -20, MaxX: 1, MaxY: -10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lists[0].Count != 2 || lists[1].Count != 1 {
+		t.Errorf("QueryRanges counts = %d, %d, want 2, 1", lists[0].Count, lists[1].Count)
+	}
+}
+
+func TestQueryRangeDedupe(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		idx, err := NewIndex(&Config{CRS: CRSWGS84, KeepDuplicates: keep})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer idx.Close()
+
+		// A line across the whole map falls in both halves of a split query
+		idx.InsertLineString([]Point{{-180, 0}, {180, 0}})
+		idx.InsertPoint(175, 0)
+		if err := idx.Build(); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := idx.QueryRange(MBR{MinX: 170, MinY: -1, MaxX: -170, MaxY: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, suppressed := uint64(2), uint64(1)
+		if keep {
+			want, suppressed = 3, 0
+		}
+		if result.Count != want || result.Stats.DuplicatesSuppressed != suppressed {
+			t.Errorf("keep %v: count %d, suppressed %d, want %d and %d",
+				keep, result.Count, result.Stats.DuplicatesSuppressed, want, suppressed)
+		}
+	}
+}
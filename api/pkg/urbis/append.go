@@ -0,0 +1,75 @@
+package urbis
+
+// AppendToSaved adds objects to the index saved at path without rewriting
+// the file. The index is opened in place, the objects are inserted, and
+// only the pages they land on, any new pages and the file header are
+// written back. It returns the IDs assigned, in order; they continue after
+// the highest ID the file has ever held. Properties are not part of the file
+// format and are ignored.
+//
+// Points, linestrings, polygons and the multi-part types are supported;
+// other types fail with ErrInvalid before anything is written. Polygons are
+// validated as by InsertPolygon. If an insert fails, the objects before it
+// stay appended.
+//
+// The caller must be the only writer of the file while this runs. An Index
+// already loaded from path does not see the new objects, and its own Sync,
+// Save or Close rewrites the header from its older state, dropping pages
+// appended meanwhile. Close such indexes first and load them again after.
+func AppendToSaved(path string, objects ...*SpatialObject) ([]uint64, error) {
+	for _, obj := range objects {
+		if !appendable(obj) {
+			return nil, ErrInvalid
+		}
+	}
+
+	idx, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	defer idx.Close()
+
+	ids := make([]uint64, 0, len(objects))
+	for _, obj := range objects {
+		id, err := idx.insertObject(obj)
+		if err != nil {
+			// Close still writes back what was inserted
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, idx.Sync()
+}
+
+// appendable reports whether insertObject can insert obj
+func appendable(obj *SpatialObject) bool {
+	if obj == nil {
+		return false
+	}
+	switch obj.Type {
+	case GeomPoint:
+		return obj.Point != nil
+	case GeomLineString, GeomPolygon, GeomMultiPoint, GeomMultiLineString, GeomMultiPolygon:
+		return true
+	}
+	return false
+}
+
+// insertObject inserts the geometry of obj with the insert for its type
+func (idx *Index) insertObject(obj *SpatialObject) (uint64, error) {
+	switch obj.Type {
+	case GeomPoint:
+		return idx.InsertPoint(obj.Point.X, obj.Point.Y)
+	case GeomLineString:
+		return idx.InsertLineString(obj.Line)
+	case GeomPolygon:
+		return idx.InsertPolygon(obj.Polygon)
+	case GeomMultiPoint:
+		return idx.InsertMultiPoint(obj.MultiPoint)
+	case GeomMultiLineString:
+		return idx.InsertMultiLineString(obj.MultiLine)
+	case GeomMultiPolygon:
+		return idx.InsertMultiPolygon(obj.MultiPolygon)
+	}
+	return 0, ErrInvalid
+}
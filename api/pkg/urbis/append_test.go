@@ -0,0 +1,54 @@
+package urbis
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestAppendToSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "base.idx")
+
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		idx.InsertPoint(float64(i), 0)
+	}
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	idx.Close()
+
+	ids, err := AppendToSaved(path,
+		&SpatialObject{Type: GeomPoint, Point: &Point{X: 100, Y: 100}},
+		&SpatialObject{Type: GeomLineString, Line: []Point{{0, 1}, {5, 6}}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != 51 || ids[1] != 52 {
+		t.Fatalf("appended ids %v, want [51 52]", ids)
+	}
+
+	if _, err := AppendToSaved(path, &SpatialObject{Type: GeomGeometryCollection}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("collection: got %v, want ErrInvalid", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer loaded.Close()
+	if n := loaded.Count(); n != 52 {
+		t.Errorf("reloaded %d objects, want 52", n)
+	}
+	obj, err := loaded.Get(ids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.MBR.MinX != 100 || obj.MBR.MinY != 100 {
+		t.Errorf("appended point has MBR %+v, want (100, 100)", obj.MBR)
+	}
+}
@@ -0,0 +1,64 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+
+// PendingChanges returns how many inserts and removals were made since the
+// last successful Build; an update counts as one of each
+func (idx *Index) PendingChanges() uint64 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return uint64(C.urbis_pending_changes(idx.ptr))
+}
+
+// rebuildIfDue builds a changed index ahead of a query when
+// Config.AutoRebuildThreshold is set: once that many changes are pending,
+// or at once for a query that needs the tree. A read-only index is left
+// as it is. It takes the write lock only when a build is due, so the
+// caller must not hold the index lock.
+func (idx *Index) rebuildIfDue(needTree bool) error {
+	if idx.rebuildAt == 0 {
+		return nil
+	}
+	idx.mu.RLock()
+	due := idx.rebuildDue(needTree)
+	idx.mu.RUnlock()
+	if !due {
+		return nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	// Another query may have rebuilt it while the lock was free
+	if !idx.rebuildDue(needTree) {
+		return nil
+	}
+	if err := toError(C.urbis_build(idx.ptr)); err != nil {
+		return err
+	}
+	return idx.buildPropertyIndex()
+}
+
+// rebuildDue reports whether rebuildIfDue should build the index. The
+// caller must hold the index lock.
+func (idx *Index) rebuildDue(needTree bool) bool {
+	if idx.ptr == nil || idx.readOnly || bool(C.urbis_is_built(idx.ptr)) {
+		return false
+	}
+	return needTree || uint64(C.urbis_pending_changes(idx.ptr)) >= idx.rebuildAt
+}
+
+// requireQueryable is requireBuilt for queries that can scan the pages
+// instead of a tree. With Config.AutoRebuildThreshold set, an index changed
+// since its last build passes and stale is true. The caller must hold the
+// index lock.
+func (idx *Index) requireQueryable() (stale bool, err error) {
+	err = idx.requireBuilt()
+	if err == ErrNotBuilt && idx.rebuildAt > 0 {
+		return true, nil
+	}
+	return false, err
+}
@@ -0,0 +1,80 @@
+package urbis
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAutoRebuildThreshold(t *testing.T) {
+	idx, err := NewIndex(&Config{AutoRebuildThreshold: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 5; i++ {
+		if _, err := idx.InsertPoint(float64(i), float64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if idx.PendingChanges() != 5 {
+		t.Errorf("PendingChanges before the first build = %d, want 5", idx.PendingChanges())
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if idx.PendingChanges() != 0 {
+		t.Errorf("PendingChanges after Build = %d, want 0", idx.PendingChanges())
+	}
+
+	square, err := idx.InsertPolygon([]Point{{10, 10}, {12, 10}, {12, 12}, {10, 12}, {10, 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := MBR{MinX: -1, MinY: -1, MaxX: 20, MaxY: 20}
+	list, err := idx.QueryRange(all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !list.Stale || list.Count != 6 || idx.IsBuilt() {
+		t.Errorf("one change pending: stale %v, %d objects, built %v; want a stale scan of 6", list.Stale, list.Count, idx.IsBuilt())
+	}
+	inside, err := idx.QueryContaining(11, 11)
+	if err != nil || !inside.Stale || inside.Count != 1 || inside.Objects[0].ID != square {
+		t.Errorf("QueryContaining on a changed index = %v, %v", inside, err)
+	}
+
+	idx.Remove(square)
+	if point, err := idx.QueryPoint(2, 2); err != nil || !point.Stale || point.Count != 1 {
+		t.Errorf("QueryPoint with two changes pending = %v, %v", point, err)
+	}
+
+	// The third change reaches the threshold, so the next query rebuilds
+	if _, err := idx.InsertPoint(7, 7); err != nil {
+		t.Fatal(err)
+	}
+	list, err = idx.QueryRange(all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Stale || list.Count != 6 || !idx.IsBuilt() || idx.PendingChanges() != 0 {
+		t.Errorf("at the threshold: stale %v, %d objects, built %v, %d pending", list.Stale, list.Count, idx.IsBuilt(), idx.PendingChanges())
+	}
+
+	// KNN needs the tree, so it rebuilds after a single change
+	if _, err := idx.InsertPoint(8, 8); err != nil {
+		t.Fatal(err)
+	}
+	if knn, err := idx.QueryKNN(8, 8, 1); err != nil || knn.Count != 1 || !idx.IsBuilt() {
+		t.Errorf("QueryKNN on a changed index = %v, %v, built %v", knn, err, idx.IsBuilt())
+	}
+
+	plain, _ := NewIndex(nil)
+	defer plain.Close()
+	plain.InsertPoint(0, 0)
+	plain.Build()
+	plain.InsertPoint(1, 1)
+	if _, err := plain.QueryRange(all); !errors.Is(err, ErrNotBuilt) {
+		t.Errorf("without a threshold: err = %v, want ErrNotBuilt", err)
+	}
+}
@@ -0,0 +1,152 @@
+package urbis
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// benchPoints is the size of the index the query benchmarks run against
+const benchPoints = 10000
+
+// benchConfigs are the configurations every benchmark is run under
+var benchConfigs = []benchConfig{
+	{"quadtree/cache128", true, 128},
+	{"quadtree/cache16", true, 16},
+	{"noquadtree/cache128", false, 128},
+}
+
+type benchConfig struct {
+	name      string
+	quadtree  bool
+	cacheSize uint64
+}
+
+// config returns the default configuration with the benchmark's settings
+func (bc benchConfig) config() *Config {
+	config := DefaultConfig()
+	config.EnableQuadtree = bc.quadtree
+	config.CacheSize = bc.cacheSize
+	return &config
+}
+
+// syntheticPoints returns n points spread uniformly over a 1000x1000
+// square. The sequence is fixed so runs are comparable.
+func syntheticPoints(n int) []Point {
+	rng := rand.New(rand.NewPCG(1, 2))
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{X: rng.Float64() * 1000, Y: rng.Float64() * 1000}
+	}
+	return points
+}
+
+// benchIndex builds an index of benchPoints synthetic points
+func benchIndex(b *testing.B, bc benchConfig) *Index {
+	b.Helper()
+
+	idx, err := NewIndex(bc.config())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(idx.Close)
+
+	for _, p := range syntheticPoints(benchPoints) {
+		if _, err := idx.InsertPoint(p.X, p.Y); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := idx.Build(); err != nil {
+		b.Fatal(err)
+	}
+	return idx
+}
+
+func BenchmarkQueryRange(b *testing.B) {
+	for _, bc := range benchConfigs {
+		idx := benchIndex(b, bc)
+		// Windows covering about 0.01%, 1% and 10% of the points
+		for _, side := range []float64{10, 100, 316} {
+			b.Run(fmt.Sprintf("%s/side%.0f", bc.name, side), func(b *testing.B) {
+				rng := rand.New(rand.NewPCG(3, 4))
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					x, y := rng.Float64()*(1000-side), rng.Float64()*(1000-side)
+					if _, err := idx.QueryRange(MBR{MinX: x, MinY: y, MaxX: x + side, MaxY: y + side}); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkQueryKNN(b *testing.B) {
+	for _, bc := range benchConfigs {
+		idx := benchIndex(b, bc)
+		for _, k := range []uint32{1, 10, 100} {
+			b.Run(fmt.Sprintf("%s/k%d", bc.name, k), func(b *testing.B) {
+				rng := rand.New(rand.NewPCG(5, 6))
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := idx.QueryKNN(rng.Float64()*1000, rng.Float64()*1000, k); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkInsertPoint(b *testing.B) {
+	points := syntheticPoints(benchPoints)
+	for _, bc := range benchConfigs {
+		b.Run(bc.name, func(b *testing.B) {
+			idx, err := NewIndex(bc.config())
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer idx.Close()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				p := points[i%len(points)]
+				if _, err := idx.InsertPoint(p.X, p.Y); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkBulkLoad measures loading and building a whole index, the cost
+// paid when a server starts from GeoJSON
+func BenchmarkBulkLoad(b *testing.B) {
+	geojson := []byte(`{"type":"FeatureCollection","features":[`)
+	for i, p := range syntheticPoints(benchPoints) {
+		if i > 0 {
+			geojson = append(geojson, ',')
+		}
+		geojson = fmt.Appendf(geojson, `{"type":"Feature","geometry":{"type":"Point","coordinates":[%g,%g]},"properties":{}}`, p.X, p.Y)
+	}
+	geojson = append(geojson, "]}"...)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(geojson)))
+	for i := 0; i < b.N; i++ {
+		idx, err := NewIndex(nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := idx.LoadGeoJSONString(string(geojson)); err != nil {
+			b.Fatal(err)
+		}
+		if err := idx.Build(); err != nil {
+			b.Fatal(err)
+		}
+		idx.Close()
+	}
+}
@@ -3,15 +3,27 @@ package urbis
 
 /*
 #cgo CFLAGS: -I${SRCDIR}/../../../include
-#cgo LDFLAGS: -L${SRCDIR}/../../../lib -lurbis -lm
+#cgo LDFLAGS: -L${SRCDIR}/../../../lib -lurbis -lm -lpthread
 #include <stdlib.h>
 #include <string.h>
 #include "urbis.h"
 */
 import "C"
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
-	"runtime"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -24,6 +36,35 @@ var (
 	ErrNotFound = errors.New("not found")
 	ErrFull     = errors.New("index full")
 	ErrInvalid  = errors.New("invalid argument")
+
+	// ErrNotBuilt is returned by queries on an index that has not been
+	// built, or has been modified since the last Build
+	ErrNotBuilt = errors.New("index not built")
+
+	// ErrCorrupt is returned by queries that read a page whose checksum
+	// does not match its contents; see QueryRangeBestEffort
+	ErrCorrupt = errors.New("corrupt page")
+
+	// ErrIDInUse is returned by the WithID inserts when the ID is taken.
+	// It wraps ErrInvalid.
+	ErrIDInUse = fmt.Errorf("%w: object ID already in use", ErrInvalid)
+
+	// ErrReadOnly is returned by every load, insert, remove, property
+	// update and build on a read-only index; see MarkReadOnly
+	ErrReadOnly = errors.New("index is read-only")
+
+	// ErrCancelled is returned by a build whose context was cancelled or
+	// timed out, wrapped together with the context's error. The index is
+	// left unbuilt.
+	ErrCancelled = errors.New("build cancelled")
+
+	// ErrInMemory is returned by Save, WriteTo, SaveBytes and Sync on an
+	// index created with Config.InMemory
+	ErrInMemory = errors.New("index is in-memory only")
+
+	// ErrVersionMismatch is returned by Load and the other loads of a saved
+	// index when the file was saved in a newer format than FormatVersion
+	ErrVersionMismatch = errors.New("unsupported file format version")
 )
 
 // toError converts C error code to Go error
@@ -45,6 +86,12 @@ func toError(code C.int) error {
 		return ErrFull
 	case C.URBIS_ERR_INVALID:
 		return ErrInvalid
+	case C.URBIS_ERR_EXISTS:
+		return ErrIDInUse
+	case C.URBIS_ERR_CANCELLED:
+		return ErrCancelled
+	case C.URBIS_ERR_VERSION:
+		return ErrVersionMismatch
 	default:
 		return errors.New("unknown error")
 	}
@@ -52,12 +99,94 @@ func toError(code C.int) error {
 
 // Config represents index configuration
 type Config struct {
-	BlockSize     uint64
+	BlockSize     uint64 // Max objects per block, 0 for the default; see IsValidBlockSize
 	PageCapacity  uint64
 	CacheSize     uint64
 	EnableQuadtree bool
 	Persist       bool
 	DataPath      string
+	// SnapPrecision is the grid size coordinates are rounded to on insert
+	// (e.g. 1e-6 for six decimal places); 0 disables snapping
+	SnapPrecision float64
+	// SimplifyTolerance drops linestring and polygon ring vertices closer
+	// than this distance to the simplified shape (Douglas-Peucker) on
+	// insert; 0 disables simplification
+	SimplifyTolerance float64
+	// DedupPoints collapses identical (snapped) points into one object
+	// whose properties carry the duplicate count as {"count":N}
+	DedupPoints bool
+	// CRS is the EPSG code of the index coordinates (CRSWGS84 or
+	// CRSWebMercator); loads from another CRS are reprojected into it
+	CRS int
+	// PolygonValidation selects how InsertPolygon treats rings that fail
+	// ValidatePolygon; invalid polygons are rejected by default
+	PolygonValidation ValidationMode
+	// ValidationTolerance is the distance within which polygon validation
+	// treats points as coincident (see ValidatePolygonWithin). Rings that
+	// pass are stored snapped: near-duplicate vertices merged and closed
+	// exactly. 0 requires exact closure.
+	ValidationTolerance float64
+	// IndexedProperties lists the property keys Build indexes for
+	// QueryByProperty
+	IndexedProperties []string
+	// PropertySchema is checked against the properties of every object
+	// loaded, inserted or given new properties (see ValidateProperties).
+	// Objects inserted without properties fail any required rule. Nil
+	// leaves properties unchecked.
+	PropertySchema []PropertyRule
+	// SeekCost is the storage model behind the read time estimates of
+	// FindAdjacentPages and AutoTuneConfig; the zero value is a hard disk
+	SeekCost SeekCostModel
+	// BuildThreads is how many threads Build may use to sort and split
+	// the KD-tree; 0 means one. The tree is the same for any count.
+	BuildThreads int
+	// ReadOnly creates the index read-only, as MarkReadOnly does
+	ReadOnly bool
+	// Seed orders objects whose centroids tie on a KD-tree split axis.
+	// Builds of the same objects, inserted in the same order, with the
+	// same seed give the same tree and blocks on every run and platform;
+	// another seed only breaks the ties differently.
+	Seed uint64
+	// KeepDuplicates turns off result deduplication: a range query then
+	// lists an object as often as it finds it, e.g. once per half of a
+	// query split at the antimeridian. By default each object ID appears
+	// at most once and QueryStats.DuplicatesSuppressed counts the rest.
+	KeepDuplicates bool
+	// InMemory keeps the index off disk altogether: Save, WriteTo,
+	// SaveBytes and Sync fail with ErrInMemory instead of writing a file
+	// or staging one in the temp directory. It cannot be combined with
+	// Persist or DataPath. Without it, an index with Persist false still
+	// does no disk I/O until one of those is called; see NewInMemoryIndex.
+	InMemory bool
+	// AutoRebuildThreshold keeps an index queryable between builds. While
+	// fewer changes than this are pending (see PendingChanges), range,
+	// point, containing and buffered queries scan the pages and mark their
+	// results Stale; the first query after that many changes rebuilds the
+	// index, and so does any other query on a changed index. 0 leaves
+	// queries on a changed index failing with ErrNotBuilt.
+	AutoRebuildThreshold uint64
+	// ValidBounds, when not the zero MBR, is the box in index coordinates
+	// every object must lie in, to catch swapped coordinates or unit
+	// errors at ingest. Inserts reaching outside it fail with ErrInvalid;
+	// GeoJSON loads leave such features out and count them in
+	// LoadResult.OutOfBounds. Objects are checked before snapping.
+	ValidBounds MBR
+}
+
+// Bounds on Config.BlockSize. A block should fill at least one page of the
+// largest capacity, and the KD-tree partitions on powers of two.
+const (
+	MinBlockSize = 64
+	MaxBlockSize = 1 << 20
+)
+
+// IsValidBlockSize reports whether n can be used as Config.BlockSize: zero
+// (the default) or a power of two from MinBlockSize to MaxBlockSize
+func IsValidBlockSize(n uint64) bool {
+	if n == 0 {
+		return true
+	}
+	return n >= MinBlockSize && n <= MaxBlockSize && n&(n-1) == 0
 }
 
 // DefaultConfig returns default configuration
@@ -69,50 +198,192 @@ func DefaultConfig() Config {
 		CacheSize:     uint64(cConfig.cache_size),
 		EnableQuadtree: bool(cConfig.enable_quadtree),
 		Persist:       bool(cConfig.persist),
+		SnapPrecision: float64(cConfig.snap_grid),
+		SimplifyTolerance: float64(cConfig.simplify_tolerance),
+		DedupPoints:   bool(cConfig.dedup_points),
+		BuildThreads:  int(cConfig.build_threads),
+		Seed:          uint64(cConfig.seed),
+
+		KeepDuplicates: bool(cConfig.keep_duplicates),
 	}
 }
 
-// Index represents a spatial index
+// Index represents a spatial index. It is safe for concurrent use:
+// mutating methods take an exclusive lock, queries share a read lock.
+// Call Close when done; see Resources for tracking indexes left open.
 type Index struct {
-	ptr *C.UrbisIndex
+	*handle
+}
+
+// handle holds the native index. It is kept apart from Index so the package
+// can track open indexes without keeping their Index reachable, which would
+// stop the finalizer from ever running.
+type handle struct {
+	mu         sync.RWMutex
+	ptr        *C.UrbisIndex
+	crs        int
+	validation ValidationMode
+	tolerance  float64              // Config.ValidationTolerance
+	origin     string               // file:line of the caller that opened the index
+	readOnly   bool                 // Set by MarkReadOnly, never cleared
+	keepDups   bool                 // Config.KeepDuplicates
+	inMemory   bool                 // Config.InMemory
+	rebuildAt  uint64               // Config.AutoRebuildThreshold
+	expires    map[uint64]time.Time // Set by SetTTL, emptied by SweepExpired
+
+	indexedProps []string
+	props        propertyIndex  // Built by Build when indexedProps is set
+	schema       []PropertyRule // Config.PropertySchema
+	validBounds  *MBR           // Config.ValidBounds, nil when unset
 }
 
 // NewIndex creates a new spatial index with optional configuration
 func NewIndex(config *Config) (*Index, error) {
+	return openIndex(config, "")
+}
+
+// openIndex creates an index with config, loading the file at path into
+// it when path is set
+func openIndex(config *Config, path string) (*Index, error) {
 	var cConfig *C.UrbisConfig
 	var cConfigVal C.UrbisConfig
 
 	if config != nil {
+		if !IsSupportedCRS(config.CRS) {
+			return nil, ErrInvalid
+		}
+		if !IsValidBlockSize(config.BlockSize) {
+			return nil, fmt.Errorf("%w: block size %d is not a power of two from %d to %d",
+				ErrInvalid, config.BlockSize, MinBlockSize, MaxBlockSize)
+		}
+		if t := config.SimplifyTolerance; t < 0 || math.IsNaN(t) || math.IsInf(t, 0) {
+			return nil, fmt.Errorf("%w: simplify tolerance %v is not a finite non-negative distance", ErrInvalid, t)
+		}
+		if err := config.SeekCost.Validate(); err != nil {
+			return nil, err
+		}
+		if config.PolygonValidation < ValidationReject || config.PolygonValidation > ValidationOff {
+			return nil, ErrInvalid
+		}
+		if !validRules(config.PropertySchema) {
+			return nil, fmt.Errorf("%w: property schema rules need a key and a known type", ErrInvalid)
+		}
+		if t := config.ValidationTolerance; t < 0 || math.IsNaN(t) || math.IsInf(t, 0) {
+			return nil, fmt.Errorf("%w: validation tolerance %v is not a finite non-negative distance", ErrInvalid, t)
+		}
+		if config.BuildThreads < 0 {
+			return nil, fmt.Errorf("%w: build threads %d is negative", ErrInvalid, config.BuildThreads)
+		}
+		if config.InMemory && (config.Persist || config.DataPath != "") {
+			return nil, fmt.Errorf("%w: an in-memory index cannot persist to a data path", ErrInvalid)
+		}
+		if b := config.ValidBounds; b != (MBR{}) && !(isFinite(b.MinX) && isFinite(b.MinY) && isFinite(b.MaxX) && isFinite(b.MaxY) &&
+			b.MinX <= b.MaxX && b.MinY <= b.MaxY) {
+			return nil, fmt.Errorf("%w: valid bounds %+v are not a finite box with min <= max", ErrInvalid, b)
+		}
+		if _, err := encodeSettings(savedSettings{config.CRS, config.PolygonValidation,
+			config.ValidationTolerance, config.PropertySchema}); err != nil {
+			return nil, err
+		}
+		blockSize := config.BlockSize
+		if blockSize == 0 {
+			blockSize = uint64(C.urbis_default_config().block_size)
+		}
 		cConfigVal = C.UrbisConfig{
-			block_size:      C.size_t(config.BlockSize),
+			block_size:      C.size_t(blockSize),
 			page_capacity:   C.size_t(config.PageCapacity),
 			cache_size:      C.size_t(config.CacheSize),
 			enable_quadtree: C.bool(config.EnableQuadtree),
 			persist:         C.bool(config.Persist),
+			snap_grid:       C.double(config.SnapPrecision),
+			simplify_tolerance: C.double(config.SimplifyTolerance),
+			dedup_points:    C.bool(config.DedupPoints),
+			seek_cost:       config.SeekCost.toC(),
+			build_threads:   C.size_t(config.BuildThreads),
+			seed:            C.uint64_t(config.Seed),
+
+			keep_duplicates: C.bool(config.KeepDuplicates),
 		}
 		if config.DataPath != "" {
 			cConfigVal.data_path = C.CString(config.DataPath)
 			defer C.free(unsafe.Pointer(cConfigVal.data_path))
 		}
+		if config.ValidBounds != (MBR{}) {
+			// C memory, since cConfigVal may not hold Go pointers
+			bounds := (*C.MBR)(C.malloc(C.sizeof_MBR))
+			defer C.free(unsafe.Pointer(bounds))
+			*bounds = C.MBR{
+				min_x: C.double(config.ValidBounds.MinX),
+				min_y: C.double(config.ValidBounds.MinY),
+				max_x: C.double(config.ValidBounds.MaxX),
+				max_y: C.double(config.ValidBounds.MaxY),
+			}
+			cConfigVal.valid_bounds = bounds
+		}
 		cConfig = &cConfigVal
 	}
 
-	ptr := C.urbis_create(cConfig)
-	if ptr == nil {
-		return nil, ErrAlloc
+	var ptr *C.UrbisIndex
+	if path == "" {
+		ptr = C.urbis_create(cConfig)
+		if ptr == nil {
+			return nil, ErrAlloc
+		}
+	} else if err := loadFile(path, cConfig, &ptr); err != nil {
+		return nil, err
 	}
 
-	idx := &Index{ptr: ptr}
-	runtime.SetFinalizer(idx, (*Index).Close)
+	idx := newIndex(ptr)
+	if config != nil {
+		idx.crs = config.CRS
+		idx.validation = config.PolygonValidation
+		idx.tolerance = config.ValidationTolerance
+		idx.indexedProps = slices.Clone(config.IndexedProperties)
+		idx.schema = slices.Clone(config.PropertySchema)
+		idx.readOnly = config.ReadOnly
+		idx.keepDups = config.KeepDuplicates
+		idx.inMemory = config.InMemory
+		idx.rebuildAt = config.AutoRebuildThreshold
+		if config.ValidBounds != (MBR{}) {
+			bounds := config.ValidBounds
+			idx.validBounds = &bounds
+		}
+	}
+	if path != "" {
+		// A loaded index is built, so its indexed properties are too
+		err := idx.restoreSettings()
+		if err == nil {
+			err = idx.buildPropertyIndex()
+		}
+		if err != nil {
+			idx.Close()
+			return nil, err
+		}
+	}
 	return idx, nil
 }
 
-// Close destroys the index and frees resources
+// NewInMemoryIndex creates an index with the default configuration that
+// never touches the disk: creating, loading data into, building and
+// querying it open no files, and Save, WriteTo, SaveBytes and Sync fail
+// with ErrInMemory
+func NewInMemoryIndex() (*Index, error) {
+	config := DefaultConfig()
+	config.Persist = false
+	config.DataPath = ""
+	config.InMemory = true
+	return NewIndex(&config)
+}
+
+// InMemory reports whether the index was created with Config.InMemory
+func (idx *Index) InMemory() bool {
+	return idx.inMemory
+}
+
+// Close destroys the index and frees resources. It is safe to call more
+// than once.
 func (idx *Index) Close() {
-	if idx.ptr != nil {
-		C.urbis_destroy(idx.ptr)
-		idx.ptr = nil
-	}
+	idx.handle.close()
 }
 
 // Version returns the library version string
@@ -124,25 +395,302 @@ func Version() string {
 // Data Loading
 // =============================================================================
 
-// LoadGeoJSON loads data from a GeoJSON file
+// LoadResult counts the features of a GeoJSON load
+type LoadResult struct {
+	Loaded  uint64 // Objects added to the index
+	Skipped uint64 // Features left out for a null or empty geometry
+	// OutOfBounds counts features left out for reaching outside
+	// Config.ValidBounds
+	OutOfBounds uint64
+}
+
+// LoadGeoJSON loads data from a GeoJSON file. Gzip-compressed files, named
+// *.gz or starting with the gzip header, are decompressed in memory first.
+// Features whose geometry is null or has no coordinates are skipped;
+// LoadGeoJSONFrom reports how many.
 func (idx *Index) LoadGeoJSON(path string) error {
+	_, err := idx.loadGeoJSON(path)
+	return err
+}
+
+func (idx *Index) loadGeoJSON(path string) (LoadResult, error) {
+	compressed, err := isGzipFile(path)
+	if err != nil {
+		return LoadResult{}, err
+	}
+	// The schema is checked in Go, so the document must be in memory
+	if compressed || len(idx.schema) > 0 {
+		data, err := readGeoJSONFile(path)
+		if err != nil {
+			return LoadResult{}, err
+		}
+		return idx.loadGeoJSONString(string(data))
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return LoadResult{}, err
+	}
+
 	cpath := C.CString(path)
 	defer C.free(unsafe.Pointer(cpath))
-	return toError(C.urbis_load_geojson(idx.ptr, cpath))
+	var skipped C.size_t
+	before, outside := C.urbis_count(idx.ptr), C.urbis_out_of_bounds(idx.ptr)
+	err = toError(C.urbis_load_geojson_counting(idx.ptr, cpath, &skipped))
+	return idx.loadResult(before, outside, skipped), err
 }
 
-// LoadGeoJSONString loads data from a GeoJSON string
+// LoadGeoJSONString loads data from a GeoJSON string, skipping features as
+// LoadGeoJSON does
 func (idx *Index) LoadGeoJSONString(json string) error {
+	_, err := idx.loadGeoJSONString(json)
+	return err
+}
+
+func (idx *Index) loadGeoJSONString(json string) (LoadResult, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return LoadResult{}, err
+	}
+
+	if err := idx.checkGeoJSON([]byte(json)); err != nil {
+		return LoadResult{}, err
+	}
+
 	cjson := C.CString(json)
 	defer C.free(unsafe.Pointer(cjson))
-	return toError(C.urbis_load_geojson_string(idx.ptr, cjson))
+	var skipped C.size_t
+	before, outside := C.urbis_count(idx.ptr), C.urbis_out_of_bounds(idx.ptr)
+	err := toError(C.urbis_load_geojson_string_counting(idx.ptr, cjson, &skipped))
+	return idx.loadResult(before, outside, skipped), err
+}
+
+// loadResult sums up a GeoJSON load from the object and out-of-bounds
+// counts taken before it. The caller must hold the write lock.
+func (idx *Index) loadResult(before, outside, skipped C.size_t) LoadResult {
+	return LoadResult{
+		Loaded:      uint64(C.urbis_count(idx.ptr) - before),
+		Skipped:     uint64(skipped),
+		OutOfBounds: uint64(C.urbis_out_of_bounds(idx.ptr) - outside),
+	}
 }
 
-// LoadWKT loads data from a WKT string
+// gzipMagic is the header every gzip stream starts with
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// isGzipFile reports whether the file at path is gzip-compressed, judged by
+// a .gz extension or the gzip header
+func isGzipFile(path string) (bool, error) {
+	if strings.EqualFold(filepath.Ext(path), ".gz") {
+		return true, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrIO, err)
+	}
+	defer f.Close()
+
+	header := make([]byte, len(gzipMagic))
+	if _, err := io.ReadFull(f, header); err != nil {
+		// Too short to be gzip; let the parser report what is wrong
+		return false, nil
+	}
+	return bytes.Equal(header, gzipMagic), nil
+}
+
+// readGeoJSONFile reads a GeoJSON file, decompressing it if it is gzipped
+func readGeoJSONFile(path string) ([]byte, error) {
+	compressed, err := isGzipFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIO, err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if compressed {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrParse, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+	return data, nil
+}
+
+// LoadWKT loads data from a WKT or PostGIS EWKT string. See LoadEWKT for
+// how an SRID is handled.
 func (idx *Index) LoadWKT(wkt string) error {
+	_, err := idx.LoadEWKT(wkt)
+	return err
+}
+
+// LoadEWKT loads a WKT string that may carry a PostGIS "SRID=<code>;"
+// prefix, returning the SRID (0 when there is none). An SRID other than the
+// index CRS fails with ErrInvalid, unless the index CRS is CRSUnspecified,
+// in which case the coordinates are used as given.
+func (idx *Index) LoadEWKT(wkt string) (int, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return 0, err
+	}
+
 	cwkt := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cwkt))
-	return toError(C.urbis_load_wkt(idx.ptr, cwkt))
+
+	if err := idx.checkBare(); err != nil {
+		return 0, err
+	}
+
+	srid := int(C.urbis_wkt_srid(cwkt))
+	if srid < 0 {
+		return 0, fmt.Errorf("%w: malformed SRID prefix", ErrParse)
+	}
+	if srid != CRSUnspecified && idx.crs != CRSUnspecified && srid != idx.crs {
+		return srid, fmt.Errorf("%w: SRID %d does not match the index CRS EPSG:%d", ErrInvalid, srid, idx.crs)
+	}
+	return srid, toError(C.urbis_load_wkt(idx.ptr, cwkt))
+}
+
+// LoadWKB loads one or more concatenated WKB geometries
+func (idx *Index) LoadWKB(data []byte) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return err
+	}
+
+	if len(data) == 0 {
+		return ErrParse
+	}
+	if err := idx.checkBare(); err != nil {
+		return err
+	}
+	return toError(C.urbis_load_wkb(idx.ptr, (*C.uint8_t)(unsafe.Pointer(&data[0])), C.size_t(len(data))))
+}
+
+// ExportWKB returns an object's geometry as little-endian WKB
+func (idx *Index) ExportWKB(objectID uint64) ([]byte, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return idx.exportWKB(objectID)
+}
+
+// ExportWKBs returns the WKB of several objects under one lock. The result
+// is parallel to ids, with nil entries for IDs that were not found.
+func (idx *Index) ExportWKBs(ids []uint64) ([][]byte, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if idx.ptr == nil {
+		return nil, ErrNull
+	}
+
+	out := make([][]byte, len(ids))
+	for i, id := range ids {
+		wkb, err := idx.exportWKB(id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out[i] = wkb
+	}
+	return out, nil
+}
+
+// exportWKB is ExportWKB for callers already holding the lock
+func (idx *Index) exportWKB(objectID uint64) ([]byte, error) {
+	size := C.urbis_export_wkb(idx.ptr, C.uint64_t(objectID), nil, 0)
+	if size < 0 {
+		return nil, toError(size)
+	}
+
+	buf := make([]byte, int(size))
+	if size > 0 {
+		C.urbis_export_wkb(idx.ptr, C.uint64_t(objectID), (*C.uint8_t)(unsafe.Pointer(&buf[0])), C.size_t(size))
+	}
+	return buf, nil
+}
+
+// streamBatchSize is the number of features handed to the C parser at once
+// by LoadGeoJSONReader
+const streamBatchSize = 1024
+
+// maxFeatureSize is the largest single feature line LoadGeoJSONReader accepts
+const maxFeatureSize = 64 * 1024 * 1024
+
+// LoadGeoJSONReader loads newline-delimited GeoJSON features from r,
+// feeding the C parser in batches so the whole input is never buffered.
+// It returns the number of objects loaded and features skipped, including
+// those of batches loaded before an error occurred.
+func (idx *Index) LoadGeoJSONReader(r io.Reader) (LoadResult, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFeatureSize)
+
+	var total LoadResult
+	var batch bytes.Buffer
+	pending := 0
+
+	flush := func() error {
+		if pending == 0 {
+			return nil
+		}
+		batch.WriteString("]}")
+		result, err := idx.loadGeoJSONString(batch.String())
+		total.Loaded += result.Loaded
+		total.Skipped += result.Skipped
+		total.OutOfBounds += result.OutOfBounds
+		batch.Reset()
+		pending = 0
+		return err
+	}
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if pending == 0 {
+			batch.WriteString(`{"type":"FeatureCollection","features":[`)
+		} else {
+			batch.WriteByte(',')
+		}
+		batch.Write(line)
+		pending++
+
+		if pending == streamBatchSize {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return total, err
+	}
+
+	err := flush()
+	return total, err
 }
 
 // =============================================================================
@@ -163,9 +711,13 @@ type MBR struct {
 type GeomType int
 
 const (
-	GeomPoint      GeomType = 0
-	GeomLineString GeomType = 1
-	GeomPolygon    GeomType = 2
+	GeomPoint              GeomType = 0
+	GeomLineString         GeomType = 1
+	GeomPolygon            GeomType = 2
+	GeomMultiPoint         GeomType = 3
+	GeomMultiLineString    GeomType = 4
+	GeomMultiPolygon       GeomType = 5
+	GeomGeometryCollection GeomType = 6
 )
 
 // SpatialObject represents a spatial object
@@ -175,69 +727,618 @@ type SpatialObject struct {
 	Centroid   Point
 	MBR        MBR
 	Properties []byte
+	// Version increases across the index with every insert, geometry update
+	// or properties change; ModifiedAt is when the object last changed.
+	// Both are zero for objects restored from a saved index.
+	Version    uint64
+	ModifiedAt time.Time
+	// Dims says whether the geometry carries Z and M values. Z and M hold
+	// one value per point when it does, in the order the geometry fields
+	// list points: each MultiLine line or MultiPolygon ring in turn. A
+	// collection's members carry their own. Indexing ignores both.
+	Dims Dims
+	Z    []float64
+	M    []float64
 	// Geometry data (type-specific)
-	Point   *Point
-	Line    []Point
-	Polygon []Point
+	Point        *Point
+	Line         []Point
+	Polygon      []Point
+	MultiPoint   []Point
+	MultiLine    [][]Point
+	MultiPolygon [][]Point // exterior ring of each part
+	Geometries   []*SpatialObject
+}
+
+// Inserted describes a newly inserted object
+type Inserted struct {
+	ID       uint64
+	MBR      MBR
+	Centroid Point
+	// Duplicate is set by the Insert*With methods when Config.DedupPoints
+	// counted the point against an existing one, whose ID, MBR and
+	// centroid these are. The options were not applied to it.
+	Duplicate bool
+}
+
+// InsertOptions are applied to an object by the Insert*With methods as
+// part of inserting it, under the same lock, so no reader sees the object
+// without them and an insert whose options fail stores nothing
+type InsertOptions struct {
+	ID  uint64        // Insert under this ID, as the WithID inserts do (0 = assign one)
+	TTL time.Duration // Expire the object TTL from now, as SetTTL does (0 = never)
+	// Z and M give the stored vertices Z and M values, as SetZM does. They
+	// are checked as by CheckZM before anything is inserted. Nil leaves
+	// the ordinate out.
+	Z, M []float64
 }
 
 // InsertPoint inserts a point and returns its ID
 func (idx *Index) InsertPoint(x, y float64) (uint64, error) {
-	id := C.urbis_insert_point(idx.ptr, C.double(x), C.double(y))
+	ins, err := idx.InsertPointInfo(x, y)
+	return ins.ID, err
+}
+
+// InsertPointInfo inserts a point and returns its ID, MBR and centroid
+func (idx *Index) InsertPointInfo(x, y float64) (Inserted, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	id, err := idx.insertPoint(0, x, y)
+	if err != nil {
+		return Inserted{}, err
+	}
+	return idx.inserted(C.uint64_t(id))
+}
+
+// insertPoint inserts a point under id, or a new ID when id is 0, and
+// returns the ID it got. The caller must hold the write lock.
+func (idx *Index) insertPoint(id uint64, x, y float64) (uint64, error) {
+	if err := idx.requireWritable(); err != nil {
+		return 0, err
+	}
+
+	if !isFinite(x) || !isFinite(y) {
+		return 0, ErrInvalid
+	}
+	if err := idx.checkBare(); err != nil {
+		return 0, err
+	}
+	if err := idx.checkBounds(Point{X: x, Y: y}); err != nil {
+		return 0, err
+	}
+
+	if id != 0 {
+		return id, toError(C.urbis_insert_point_id(idx.ptr, C.uint64_t(id), C.double(x), C.double(y)))
+	}
+	return newID(C.urbis_insert_point(idx.ptr, C.double(x), C.double(y)))
+}
+
+// InsertLineString inserts a linestring and returns its ID
+func (idx *Index) InsertLineString(points []Point) (uint64, error) {
+	ins, err := idx.InsertLineStringInfo(points)
+	return ins.ID, err
+}
+
+// InsertLineStringInfo inserts a linestring and returns its ID, MBR and centroid
+func (idx *Index) InsertLineStringInfo(points []Point) (Inserted, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	id, err := idx.insertLineString(0, points)
+	if err != nil {
+		return Inserted{}, err
+	}
+	return idx.inserted(C.uint64_t(id))
+}
+
+// insertLineString inserts a linestring as insertPoint inserts a point
+func (idx *Index) insertLineString(id uint64, points []Point) (uint64, error) {
+	if err := idx.requireWritable(); err != nil {
+		return 0, err
+	}
+
+	if len(points) < 2 || !pointsFinite(points) {
+		return 0, ErrInvalid
+	}
+	if err := idx.checkBare(); err != nil {
+		return 0, err
+	}
+	if err := idx.checkBounds(points...); err != nil {
+		return 0, err
+	}
+
+	cpoints := toCPoints(points)
+	if id != 0 {
+		return id, toError(C.urbis_insert_linestring_id(idx.ptr, C.uint64_t(id), &cpoints[0], C.size_t(len(points))))
+	}
+	return newID(C.urbis_insert_linestring(idx.ptr, &cpoints[0], C.size_t(len(points))))
+}
+
+// InsertPolygon inserts a polygon and returns its ID. Unless the index was
+// configured otherwise, the exterior ring must pass ValidatePolygon; the
+// returned ErrInvalid then carries the reason.
+func (idx *Index) InsertPolygon(exterior []Point) (uint64, error) {
+	ins, err := idx.InsertPolygonInfo(exterior)
+	return ins.ID, err
+}
+
+// InsertPolygonInfo inserts a polygon like InsertPolygon and returns its
+// ID, MBR and centroid
+func (idx *Index) InsertPolygonInfo(exterior []Point) (Inserted, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	id, err := idx.insertPolygon(0, exterior)
+	if err != nil {
+		return Inserted{}, err
+	}
+	return idx.inserted(C.uint64_t(id))
+}
+
+// insertPolygon inserts a polygon, validating its ring as InsertPolygon
+// does, as insertPoint inserts a point
+func (idx *Index) insertPolygon(id uint64, exterior []Point) (uint64, error) {
+	if err := idx.requireWritable(); err != nil {
+		return 0, err
+	}
+
+	if len(exterior) < 3 || !pointsFinite(exterior) {
+		return 0, ErrInvalid
+	}
+	if err := idx.checkBare(); err != nil {
+		return 0, err
+	}
+	exterior, err := idx.checkRing(exterior)
+	if err != nil {
+		return 0, err
+	}
+	if err := idx.checkBounds(exterior...); err != nil {
+		return 0, err
+	}
+
+	cpoints := toCPoints(exterior)
+	if id != 0 {
+		return id, toError(C.urbis_insert_polygon_id(idx.ptr, C.uint64_t(id), &cpoints[0], C.size_t(len(exterior))))
+	}
+	return newID(C.urbis_insert_polygon(idx.ptr, &cpoints[0], C.size_t(len(exterior))))
+}
+
+// newID returns the ID an automatically numbered insert returned, or
+// ErrAlloc for the 0 of a failed one
+func newID(id C.uint64_t) (uint64, error) {
+	if id == 0 {
+		return 0, ErrAlloc
+	}
+	return uint64(id), nil
+}
+
+// InsertPointWithID inserts a point under a caller-chosen ID, such as one
+// from an external system. ID 0 is reserved and fails with ErrInvalid; an
+// ID already in use fails with ErrIDInUse. With Config.DedupPoints, a point
+// at the location of an existing point also fails with ErrIDInUse, since it
+// could not keep its ID. Automatic IDs continue after the highest ID
+// inserted.
+func (idx *Index) InsertPointWithID(id uint64, x, y float64) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return err
+	}
+	if id == 0 {
+		return ErrInvalid
+	}
+	_, err := idx.insertPoint(id, x, y)
+	return err
+}
+
+// InsertLineStringWithID inserts a linestring under a caller-chosen ID, as
+// InsertPointWithID does
+func (idx *Index) InsertLineStringWithID(id uint64, points []Point) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return err
+	}
+	if id == 0 {
+		return ErrInvalid
+	}
+	_, err := idx.insertLineString(id, points)
+	return err
+}
+
+// InsertPolygonWithID inserts a polygon under a caller-chosen ID. The ring
+// is validated as by InsertPolygon and the ID handled as by InsertPointWithID.
+func (idx *Index) InsertPolygonWithID(id uint64, exterior []Point) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return err
+	}
+	if id == 0 {
+		return ErrInvalid
+	}
+	_, err := idx.insertPolygon(id, exterior)
+	return err
+}
+
+// InsertPointWith inserts a point with opts and returns its ID, MBR and
+// centroid. Failures are as for InsertPointInfo, or InsertPointWithID when
+// opts.ID is set.
+func (idx *Index) InsertPointWith(x, y float64, opts InsertOptions) (Inserted, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	return idx.insertWith(opts, GeomPoint, []Point{{X: x, Y: y}}, func(id uint64) (uint64, error) {
+		return idx.insertPoint(id, x, y)
+	})
+}
+
+// InsertLineStringWith inserts a linestring with opts as InsertPointWith
+// inserts a point
+func (idx *Index) InsertLineStringWith(points []Point, opts InsertOptions) (Inserted, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	return idx.insertWith(opts, GeomLineString, points, func(id uint64) (uint64, error) {
+		return idx.insertLineString(id, points)
+	})
+}
+
+// InsertPolygonWith inserts a polygon, validating its ring as
+// InsertPolygon does, with opts as InsertPointWith inserts a point
+func (idx *Index) InsertPolygonWith(exterior []Point, opts InsertOptions) (Inserted, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	return idx.insertWith(opts, GeomPolygon, exterior, func(id uint64) (uint64, error) {
+		return idx.insertPolygon(id, exterior)
+	})
+}
+
+// insertWith runs insert, of points as a typ, under opts.ID and applies
+// the rest of opts to the object it stores. The caller must hold the write
+// lock.
+func (idx *Index) insertWith(opts InsertOptions, typ GeomType, points []Point, insert func(id uint64) (uint64, error)) (Inserted, error) {
+	if err := idx.checkZM(typ, points, opts.Z, opts.M); err != nil {
+		return Inserted{}, err
+	}
+
+	next := uint64(C.urbis_next_id(idx.ptr))
+	id, err := insert(opts.ID)
+	if err != nil {
+		return Inserted{}, err
+	}
+	ins, err := idx.inserted(C.uint64_t(id))
+	if err != nil {
+		return Inserted{}, err
+	}
+	// An automatic ID below the next one belongs to an existing point the
+	// insert was counted against; leave that object as it is
+	if opts.ID == 0 && id < next {
+		ins.Duplicate = true
+		return ins, nil
+	}
+
+	if opts.Z != nil || opts.M != nil {
+		if err := idx.setZM(id, opts.Z, opts.M); err != nil {
+			// Nobody has seen the object yet; take it out again
+			if rmErr := toError(C.urbis_remove(idx.ptr, C.uint64_t(id))); rmErr != nil {
+				return Inserted{}, errors.Join(err, fmt.Errorf("removing object %d again: %w", id, rmErr))
+			}
+			return Inserted{}, err
+		}
+	}
+	if opts.TTL > 0 {
+		idx.setExpiry(id, opts.TTL)
+	}
+	return ins, nil
+}
+
+// checkRing applies the index's polygon validation to an exterior ring and
+// returns the ring to store. With a validation tolerance, a ring that passes
+// is snapped; others are stored as given when the mode lets them through.
+func (idx *Index) checkRing(exterior []Point) ([]Point, error) {
+	if idx.validation == ValidationOff {
+		return exterior, nil
+	}
+	ok, reason := ValidatePolygonWithin(exterior, idx.tolerance)
+	switch {
+	case !ok && idx.validation == ValidationReject:
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, reason)
+	case ok && idx.tolerance > 0:
+		return snapRing(exterior, idx.tolerance), nil
+	}
+	return exterior, nil
+}
+
+// checkBounds fails with ErrInvalid if any point lies outside
+// Config.ValidBounds
+func (idx *Index) checkBounds(points ...Point) error {
+	b := idx.validBounds
+	if b == nil {
+		return nil
+	}
+	for _, p := range points {
+		if p.X < b.MinX || p.X > b.MaxX || p.Y < b.MinY || p.Y > b.MaxY {
+			return fmt.Errorf("%w: (%v, %v) is outside the valid bounds [%v, %v] x [%v, %v]",
+				ErrInvalid, p.X, p.Y, b.MinX, b.MaxX, b.MinY, b.MaxY)
+		}
+	}
+	return nil
+}
+
+// inserted reads back the MBR and centroid the C library computed for a
+// new object. The caller must hold the write lock.
+func (idx *Index) inserted(id C.uint64_t) (Inserted, error) {
+	if id == 0 {
+		return Inserted{}, ErrAlloc
+	}
+	ins := Inserted{ID: uint64(id)}
+	if cobj := C.urbis_get(idx.ptr, id); cobj != nil {
+		ins.MBR = MBR{
+			MinX: float64(cobj.mbr.min_x),
+			MinY: float64(cobj.mbr.min_y),
+			MaxX: float64(cobj.mbr.max_x),
+			MaxY: float64(cobj.mbr.max_y),
+		}
+		ins.Centroid = Point{X: float64(cobj.centroid.x), Y: float64(cobj.centroid.y)}
+	}
+	return ins, nil
+}
+
+// InsertMultiPoint inserts a multipoint and returns its ID
+func (idx *Index) InsertMultiPoint(points []Point) (uint64, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return 0, err
+	}
+
+	if len(points) == 0 || !pointsFinite(points) {
+		return 0, ErrInvalid
+	}
+	if err := idx.checkBare(); err != nil {
+		return 0, err
+	}
+	if err := idx.checkBounds(points...); err != nil {
+		return 0, err
+	}
+
+	cpoints := toCPoints(points)
+	id := C.urbis_insert_multipoint(idx.ptr, &cpoints[0], C.size_t(len(points)))
 	if id == 0 {
 		return 0, ErrAlloc
 	}
 	return uint64(id), nil
 }
 
-// InsertLineString inserts a linestring and returns its ID
-func (idx *Index) InsertLineString(points []Point) (uint64, error) {
-	if len(points) < 2 {
-		return 0, ErrInvalid
-	}
+// InsertMultiLineString inserts a multilinestring and returns its ID
+func (idx *Index) InsertMultiLineString(lines [][]Point) (uint64, error) {
+	for _, line := range lines {
+		if len(line) < 2 || !pointsFinite(line) {
+			return 0, ErrInvalid
+		}
+	}
+	return idx.insertMulti(lines, func(points *C.Point, counts *C.size_t, n C.size_t) C.uint64_t {
+		return C.urbis_insert_multilinestring(idx.ptr, points, counts, n)
+	})
+}
+
+// InsertMultiPolygon inserts a multipolygon given the exterior ring of each part
+func (idx *Index) InsertMultiPolygon(polygons [][]Point) (uint64, error) {
+	for _, exterior := range polygons {
+		if len(exterior) < 3 || !pointsFinite(exterior) {
+			return 0, ErrInvalid
+		}
+	}
+	return idx.insertMulti(polygons, func(points *C.Point, counts *C.size_t, n C.size_t) C.uint64_t {
+		return C.urbis_insert_multipolygon(idx.ptr, points, counts, n)
+	})
+}
+
+// insertMulti flattens parts into the layout expected by the C multi-part inserts
+func (idx *Index) insertMulti(parts [][]Point, insert func(*C.Point, *C.size_t, C.size_t) C.uint64_t) (uint64, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return 0, err
+	}
+
+	if len(parts) == 0 {
+		return 0, ErrInvalid
+	}
+	if err := idx.checkBare(); err != nil {
+		return 0, err
+	}
+
+	var flat []Point
+	counts := make([]C.size_t, len(parts))
+	for i, part := range parts {
+		flat = append(flat, part...)
+		counts[i] = C.size_t(len(part))
+	}
+	if err := idx.checkBounds(flat...); err != nil {
+		return 0, err
+	}
+
+	cpoints := toCPoints(flat)
+	id := insert(&cpoints[0], &counts[0], C.size_t(len(parts)))
+	if id == 0 {
+		return 0, ErrAlloc
+	}
+	return uint64(id), nil
+}
+
+// isFinite reports whether v is neither NaN nor infinite; non-finite
+// coordinates would corrupt MBR comparisons in the C library
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+func pointsFinite(points []Point) bool {
+	for _, p := range points {
+		if !isFinite(p.X) || !isFinite(p.Y) {
+			return false
+		}
+	}
+	return true
+}
+
+func toCPoints(points []Point) []C.Point {
+	cpoints := make([]C.Point, len(points))
+	for i, p := range points {
+		cpoints[i] = C.Point{x: C.double(p.X), y: C.double(p.Y)}
+	}
+	return cpoints
+}
+
+func fromCPoints(points *C.Point, count C.size_t) []Point {
+	out := make([]Point, count)
+	if count == 0 {
+		return out
+	}
+	cpoints := unsafe.Slice(points, count)
+	for i := range out {
+		out[i] = Point{X: float64(cpoints[i].x), Y: float64(cpoints[i].y)}
+	}
+	return out
+}
+
+// Remove removes an object by ID
+func (idx *Index) Remove(objectID uint64) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return err
+	}
+	delete(idx.expires, objectID)
+	return toError(C.urbis_remove(idx.ptr, C.uint64_t(objectID)))
+}
+
+// Match is the rule RemoveRange uses to decide whether an object falls
+// within a region. Region boundaries are inclusive.
+type Match int
+
+const (
+	MatchContained  Match = 0 // Object MBR lies entirely inside the region
+	MatchCentroid   Match = 1 // Object centroid lies inside the region
+	MatchIntersects Match = 2 // Object MBR intersects the region
+)
+
+// RemoveRange deletes every object in region under the given match rule
+// and returns the number removed
+func (idx *Index) RemoveRange(region MBR, match Match) (uint64, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return 0, err
+	}
+
+	cmbr := C.MBR{
+		min_x: C.double(region.MinX),
+		min_y: C.double(region.MinY),
+		max_x: C.double(region.MaxX),
+		max_y: C.double(region.MaxY),
+	}
+
+	var removed C.size_t
+	if err := toError(C.urbis_remove_range(idx.ptr, &cmbr, C.SpatialMatch(match), &removed)); err != nil {
+		return 0, err
+	}
+	if removed > 0 {
+		idx.forgetRemoved()
+	}
+	return uint64(removed), nil
+}
+
+// Get retrieves an object by ID
+func (idx *Index) Get(objectID uint64) (*SpatialObject, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	cobj := C.urbis_get(idx.ptr, C.uint64_t(objectID))
+	if cobj == nil {
+		return nil, ErrNotFound
+	}
+	return convertSpatialObject(cobj), nil
+}
+
+// GetObjects retrieves several objects by ID in one call. The result is
+// parallel to ids, with nil entries for IDs that were not found.
+func (idx *Index) GetObjects(ids []uint64) ([]*SpatialObject, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
 
-	cpoints := make([]C.Point, len(points))
-	for i, p := range points {
-		cpoints[i] = C.Point{x: C.double(p.X), y: C.double(p.Y)}
+	if idx.ptr == nil {
+		return nil, ErrNull
 	}
 
-	id := C.urbis_insert_linestring(idx.ptr, &cpoints[0], C.size_t(len(points)))
-	if id == 0 {
-		return 0, ErrAlloc
+	objs := make([]*SpatialObject, len(ids))
+	for i, id := range ids {
+		if cobj := C.urbis_get(idx.ptr, C.uint64_t(id)); cobj != nil {
+			objs[i] = convertSpatialObject(cobj)
+		}
 	}
-	return uint64(id), nil
+	return objs, nil
 }
 
-// InsertPolygon inserts a polygon and returns its ID
-func (idx *Index) InsertPolygon(exterior []Point) (uint64, error) {
-	if len(exterior) < 3 {
-		return 0, ErrInvalid
+// SetProperties replaces an object's properties blob without touching its
+// geometry, so the index does not need rebuilding. Empty props clears them.
+// The new properties must pass the index's property schema.
+func (idx *Index) SetProperties(objectID uint64, props []byte) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return err
 	}
 
-	cpoints := make([]C.Point, len(exterior))
-	for i, p := range exterior {
-		cpoints[i] = C.Point{x: C.double(p.X), y: C.double(p.Y)}
+	if err := ValidateProperties(props, idx.schema); err != nil {
+		return err
 	}
 
-	id := C.urbis_insert_polygon(idx.ptr, &cpoints[0], C.size_t(len(exterior)))
-	if id == 0 {
-		return 0, ErrAlloc
+	var before []byte
+	if idx.props != nil {
+		var size C.size_t
+		if data := C.urbis_get_properties(idx.ptr, C.uint64_t(objectID), &size); data != nil {
+			before = C.GoBytes(data, C.int(size))
+		}
 	}
-	return uint64(id), nil
-}
 
-// Remove removes an object by ID
-func (idx *Index) Remove(objectID uint64) error {
-	return toError(C.urbis_remove(idx.ptr, C.uint64_t(objectID)))
+	var data unsafe.Pointer
+	if len(props) > 0 {
+		data = unsafe.Pointer(&props[0])
+	}
+	if err := toError(C.urbis_set_properties(idx.ptr, C.uint64_t(objectID), data, C.size_t(len(props)))); err != nil {
+		return err
+	}
+	idx.reindexProperties(objectID, before, props)
+	return nil
 }
 
-// Get retrieves an object by ID
-func (idx *Index) Get(objectID uint64) (*SpatialObject, error) {
-	cobj := C.urbis_get(idx.ptr, C.uint64_t(objectID))
-	if cobj == nil {
+// GetProperties returns a copy of an object's properties blob, or nil if
+// the object has none
+func (idx *Index) GetProperties(objectID uint64) ([]byte, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if C.urbis_get(idx.ptr, C.uint64_t(objectID)) == nil {
 		return nil, ErrNotFound
 	}
-	return convertSpatialObject(cobj), nil
+
+	var size C.size_t
+	data := C.urbis_get_properties(idx.ptr, C.uint64_t(objectID), &size)
+	if data == nil {
+		return nil, nil
+	}
+	return C.GoBytes(data, C.int(size)), nil
 }
 
 // convertSpatialObject converts C SpatialObject to Go
@@ -255,6 +1356,14 @@ func convertSpatialObject(cobj *C.SpatialObject) *SpatialObject {
 			MaxX: float64(cobj.mbr.max_x),
 			MaxY: float64(cobj.mbr.max_y),
 		},
+		Version: uint64(cobj.version),
+	}
+	if cobj.modified_at != 0 {
+		obj.ModifiedAt = time.UnixMilli(int64(cobj.modified_at))
+	}
+
+	if cobj.properties != nil && cobj.properties_size > 0 {
+		obj.Properties = C.GoBytes(cobj.properties, C.int(cobj.properties_size))
 	}
 
 	// Copy geometry based on type
@@ -279,7 +1388,38 @@ func convertSpatialObject(cobj *C.SpatialObject) *SpatialObject {
 		for i := range obj.Polygon {
 			obj.Polygon[i] = Point{X: float64(cpoints[i].x), Y: float64(cpoints[i].y)}
 		}
+	case GeomMultiPoint:
+		mpPtr := (*C.MultiPoint)(unsafe.Pointer(&cobj.geom[0]))
+		obj.MultiPoint = fromCPoints(mpPtr.points, mpPtr.count)
+	case GeomMultiLineString:
+		mlsPtr := (*C.MultiLineString)(unsafe.Pointer(&cobj.geom[0]))
+		obj.MultiLine = make([][]Point, mlsPtr.count)
+		if mlsPtr.count > 0 {
+			lines := unsafe.Slice(mlsPtr.lines, mlsPtr.count)
+			for i := range obj.MultiLine {
+				obj.MultiLine[i] = fromCPoints(lines[i].points, lines[i].count)
+			}
+		}
+	case GeomMultiPolygon:
+		mpPtr := (*C.MultiPolygon)(unsafe.Pointer(&cobj.geom[0]))
+		obj.MultiPolygon = make([][]Point, mpPtr.count)
+		if mpPtr.count > 0 {
+			polygons := unsafe.Slice(mpPtr.polygons, mpPtr.count)
+			for i := range obj.MultiPolygon {
+				obj.MultiPolygon[i] = fromCPoints(polygons[i].exterior, polygons[i].ext_count)
+			}
+		}
+	case GeomGeometryCollection:
+		gcPtr := (*C.GeometryCollection)(unsafe.Pointer(&cobj.geom[0]))
+		obj.Geometries = make([]*SpatialObject, gcPtr.count)
+		if gcPtr.count > 0 {
+			geoms := unsafe.Slice(gcPtr.geometries, gcPtr.count)
+			for i := range obj.Geometries {
+				obj.Geometries[i] = convertSpatialObject(&geoms[i])
+			}
+		}
 	}
+	readZM(obj, cobj)
 
 	return obj
 }
@@ -290,55 +1430,376 @@ func convertSpatialObject(cobj *C.SpatialObject) *SpatialObject {
 
 // Build builds the spatial index
 func (idx *Index) Build() error {
-	return toError(C.urbis_build(idx.ptr))
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return err
+	}
+
+	if err := toError(C.urbis_build(idx.ptr)); err != nil {
+		return err
+	}
+	return idx.buildPropertyIndex()
+}
+
+// OptimizeReport describes the index before and after Optimize. The seek
+// estimates are for a range query over the central quarter of the bounds
+// the index had before optimizing.
+type OptimizeReport struct {
+	Before      Stats
+	After       Stats
+	SeeksBefore uint64
+	SeeksAfter  uint64
+}
+
+// Optimize optimizes the index for better performance and reports how the
+// layout changed
+func (idx *Index) Optimize() (*OptimizeReport, error) {
+	return idx.OptimizeContext(context.Background())
+}
+
+// CompactReport describes the index before and after Compact
+type CompactReport struct {
+	Before Stats
+	After  Stats
+}
+
+// Compact rewrites the index densely: objects are packed onto as few pages
+// of the current capacity as their placement allows, and pages emptied by
+// removals are released. IDs, properties and change stamps are kept, and
+// the index is left built. A data file shrinks on the next Save.
+func (idx *Index) Compact() (*CompactReport, error) {
+	return idx.CompactContext(context.Background())
+}
+
+// TuneCandidate describes the layout one page capacity would produce
+type TuneCandidate struct {
+	PageCapacity    uint64
+	TotalPages      uint64
+	PageUtilization float64
+	AvgSeeks        float64 // Mean estimated seeks per sample query
+	AvgCostMs       float64 // Mean estimated read time per sample query
+	Cost            float64 // AvgCostMs / PageUtilization; lower is better
+}
+
+// TuneReport is the outcome of AutoTuneConfig
+type TuneReport struct {
+	PageCapacity uint64 // Recommended capacity
+	Candidates   []TuneCandidate
+	Applied      bool // The index was re-laid out with PageCapacity
+}
+
+// AutoTuneConfig recommends a page capacity for the current data by laying
+// it out on scratch copies with capacities of 8 to 64 objects, scoring each
+// on estimated read time (see SeekCostModel) over sampleQueries and page
+// utilization. With no
+// sample queries, the central quarter and quadrants of the bounds are used.
+// When apply is set and the recommendation differs from the current
+// capacity, the index is rebuilt onto pages of the recommended capacity.
+func (idx *Index) AutoTuneConfig(sampleQueries []MBR, apply bool) (*TuneReport, error) {
+	return idx.AutoTuneConfigContext(context.Background(), sampleQueries, apply)
+}
+
+// representativeRegion returns the central quarter of bounds, the probe
+// used to compare seek costs
+func representativeRegion(bounds MBR) MBR {
+	w := (bounds.MaxX - bounds.MinX) / 4
+	h := (bounds.MaxY - bounds.MinY) / 4
+	return MBR{MinX: bounds.MinX + w, MinY: bounds.MinY + h, MaxX: bounds.MaxX - w, MaxY: bounds.MaxY - h}
 }
 
-// Optimize optimizes the index for better performance
-func (idx *Index) Optimize() error {
-	return toError(C.urbis_optimize(idx.ptr))
+// estimateSeeks estimates disk seeks for a range query. The caller must
+// hold the index lock.
+func (idx *Index) estimateSeeks(region MBR) uint64 {
+	if region.MinX > region.MaxX || region.MinY > region.MaxY {
+		return 0
+	}
+	cmbr := C.MBR{
+		min_x: C.double(region.MinX),
+		min_y: C.double(region.MinY),
+		max_x: C.double(region.MaxX),
+		max_y: C.double(region.MaxY),
+	}
+	return uint64(C.urbis_estimate_seeks(idx.ptr, &cmbr, 1))
 }
 
 // =============================================================================
 // Spatial Queries
 // =============================================================================
 
+// Structure identifies the index structure used to answer a query
+type Structure int
+
+const (
+	StructureAuto     Structure = 0 // Let the index choose
+	StructureKDTree   Structure = 1 // Object KD-tree
+	StructureQuadtree Structure = 2 // Page quadtree
+	StructureScan     Structure = 3 // Linear scan of page extents
+)
+
+// QueryStats represents page and seek statistics for a single query
+type QueryStats struct {
+	PagesVisited   uint64
+	TracksVisited  uint64
+	EstimatedSeeks uint64
+	CacheHits      uint64
+	CacheMisses    uint64
+	// Structure is the structure that answered the query
+	Structure Structure
+	// StructureFallback is set when the requested structure was not built
+	StructureFallback bool
+	// DuplicatesSuppressed counts the repeat finds of objects already in
+	// the result that were left out (see Config.KeepDuplicates)
+	DuplicatesSuppressed uint64
+}
+
 // ObjectList represents a list of spatial objects from a query
 type ObjectList struct {
 	Objects []*SpatialObject
 	Count   uint64
+	Stats   QueryStats
+	// FailedPages lists pages that failed verification; their objects are
+	// missing from Objects. Only QueryRangeBestEffort returns such lists.
+	FailedPages []uint32
+	// Stale is set when the index had changed since its last build, so the
+	// query scanned the pages instead of a tree. The results are complete,
+	// but a rebuild is due; see Config.AutoRebuildThreshold.
+	Stale bool
+	// Pages lists the pages the query touched, in the order it visited
+	// them, for finding what makes a query slow. Range, point and
+	// containing queries fill it.
+	Pages []PageInfo
+}
+
+// checkPages fails with ErrCorrupt if any page of the query failed
+func (l *ObjectList) checkPages() error {
+	if len(l.FailedPages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: pages %v failed verification", ErrCorrupt, l.FailedPages)
 }
 
-// QueryRange queries objects in a bounding box
+// QueryRange queries objects in a bounding box. An object is found when
+// its MBR intersects the box, edges included, so a box of zero width or
+// height finds what its MBR crosses and a zero-area box finds what covers
+// that point, as QueryPoint does. A box with MinX greater than MaxX
+// crosses the antimeridian: it is queried as two boxes, from
+// MinX east to the edge of the world and from the opposite edge to MaxX,
+// and an object in both is listed once unless Config.KeepDuplicates is set
+// (see WorldWidth).
 func (idx *Index) QueryRange(region MBR) (*ObjectList, error) {
-	cmbr := C.MBR{
-		min_x: C.double(region.MinX),
-		min_y: C.double(region.MinY),
-		max_x: C.double(region.MaxX),
-		max_y: C.double(region.MaxY),
+	return idx.QueryRangeUsing(region, StructureAuto)
+}
+
+// QueryRangeUsing queries objects in a bounding box with the given
+// structure. If it has not been built (or is stale after a change), the
+// other tree or a page scan is used and Stats.StructureFallback is set.
+func (idx *Index) QueryRangeUsing(region MBR, s Structure) (*ObjectList, error) {
+	if err := idx.rebuildIfDue(false); err != nil {
+		return nil, err
 	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
 
-	result := C.urbis_query_range(idx.ptr, &cmbr)
-	if result == nil {
-		return &ObjectList{Objects: []*SpatialObject{}, Count: 0}, nil
+	stale, err := idx.requireQueryable()
+	if err != nil {
+		return nil, err
 	}
-	defer C.urbis_object_list_free(result)
 
-	return convertObjectList(result), nil
+	list := idx.queryRange(region, s)
+	if err := list.checkPages(); err != nil {
+		return nil, err
+	}
+	list.Stale = stale
+	return list, nil
+}
+
+// QueryRangeBestEffort is QueryRangeUsing for an index that may hold
+// corrupt pages. Where QueryRangeUsing fails with ErrCorrupt, it returns
+// the objects from the pages that verified and lists the others in
+// FailedPages.
+func (idx *Index) QueryRangeBestEffort(region MBR, s Structure) (*ObjectList, error) {
+	if err := idx.rebuildIfDue(false); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	stale, err := idx.requireQueryable()
+	if err != nil {
+		return nil, err
+	}
+
+	list := idx.queryRange(region, s)
+	list.Stale = stale
+	return list, nil
+}
+
+// QueryRanges queries several bounding boxes under one lock, returning one
+// list per region in the same order. An object in more than one region is
+// listed in each of them.
+func (idx *Index) QueryRanges(regions []MBR) ([]*ObjectList, error) {
+	return idx.QueryRangesUsing(regions, StructureAuto)
+}
+
+// QueryRangesUsing is QueryRanges with the given structure
+func (idx *Index) QueryRangesUsing(regions []MBR, s Structure) ([]*ObjectList, error) {
+	if err := idx.rebuildIfDue(false); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	stale, err := idx.requireQueryable()
+	if err != nil {
+		return nil, err
+	}
+
+	lists := make([]*ObjectList, len(regions))
+	for i, region := range regions {
+		lists[i] = idx.queryRange(region, s)
+		if err := lists[i].checkPages(); err != nil {
+			return nil, err
+		}
+		lists[i].Stale = stale
+	}
+	return lists, nil
+}
+
+// queryRange runs one range query, splitting it at the antimeridian if it
+// crosses it. The caller must hold the index lock.
+func (idx *Index) queryRange(region MBR, s Structure) *ObjectList {
+	parts := splitAntimeridian(region, idx.crs)
+	lists := make([]*ObjectList, len(parts))
+	for i, part := range parts {
+		cmbr := C.MBR{
+			min_x: C.double(part.MinX),
+			min_y: C.double(part.MinY),
+			max_x: C.double(part.MaxX),
+			max_y: C.double(part.MaxY),
+		}
+
+		result := C.urbis_query_range_using(idx.ptr, &cmbr, C.SpatialStructure(s))
+		lists[i] = convertObjectList(result)
+		if result != nil {
+			C.urbis_object_list_free(result)
+		}
+	}
+	if len(lists) == 1 {
+		return lists[0]
+	}
+	return mergeObjectLists(lists, idx.keepDups)
+}
+
+// EstimateCount estimates how many objects QueryRange would return for
+// region without reading any of them. It adds up the objects on every page
+// whose extent intersects region, so it is an upper bound: objects near the
+// region on those pages are counted too. The index must be built.
+func (idx *Index) EstimateCount(region MBR) (uint64, error) {
+	if err := idx.rebuildIfDue(true); err != nil {
+		return 0, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if err := idx.requireBuilt(); err != nil {
+		return 0, err
+	}
+
+	var total uint64
+	for _, part := range splitAntimeridian(region, idx.crs) {
+		cmbr := C.MBR{
+			min_x: C.double(part.MinX),
+			min_y: C.double(part.MinY),
+			max_x: C.double(part.MaxX),
+			max_y: C.double(part.MaxY),
+		}
+		total += uint64(C.urbis_estimate_count(idx.ptr, &cmbr))
+	}
+	// A page both halves of a split region touch is counted twice
+	return min(total, uint64(C.urbis_count(idx.ptr))), nil
 }
 
 // QueryPoint queries objects at a point
 func (idx *Index) QueryPoint(x, y float64) (*ObjectList, error) {
-	result := C.urbis_query_point(idx.ptr, C.double(x), C.double(y))
+	return idx.QueryPointUsing(x, y, StructureAuto)
+}
+
+// QueryPointUsing queries objects at a point with the given structure
+func (idx *Index) QueryPointUsing(x, y float64, s Structure) (*ObjectList, error) {
+	if err := idx.rebuildIfDue(false); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	stale, err := idx.requireQueryable()
+	if err != nil {
+		return nil, err
+	}
+
+	result := C.urbis_query_point_using(idx.ptr, C.double(x), C.double(y), C.SpatialStructure(s))
 	if result == nil {
-		return &ObjectList{Objects: []*SpatialObject{}, Count: 0}, nil
+		return &ObjectList{Objects: []*SpatialObject{}, Count: 0, Stale: stale}, nil
 	}
 	defer C.urbis_object_list_free(result)
 
-	return convertObjectList(result), nil
+	list := convertObjectList(result)
+	if err := list.checkPages(); err != nil {
+		return nil, err
+	}
+	list.Stale = stale
+	return list, nil
+}
+
+// QueryContaining queries polygons whose interior contains the point.
+// Unlike QueryPoint, which returns MBR hits, candidates are tested against
+// the exact geometry: points on a boundary or inside a hole are excluded.
+func (idx *Index) QueryContaining(x, y float64) (*ObjectList, error) {
+	return idx.QueryContainingUsing(x, y, StructureAuto)
+}
+
+// QueryContainingUsing queries polygons containing the point with the given
+// structure
+func (idx *Index) QueryContainingUsing(x, y float64, s Structure) (*ObjectList, error) {
+	if err := idx.rebuildIfDue(false); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	stale, err := idx.requireQueryable()
+	if err != nil {
+		return nil, err
+	}
+
+	result := C.urbis_query_containing_using(idx.ptr, C.double(x), C.double(y), C.SpatialStructure(s))
+	if result == nil {
+		return &ObjectList{Objects: []*SpatialObject{}, Count: 0, Stale: stale}, nil
+	}
+	defer C.urbis_object_list_free(result)
+
+	list := convertObjectList(result)
+	if err := list.checkPages(); err != nil {
+		return nil, err
+	}
+	list.Stale = stale
+	return list, nil
 }
 
 // QueryKNN queries k nearest neighbors
 func (idx *Index) QueryKNN(x, y float64, k uint32) (*ObjectList, error) {
+	if err := idx.rebuildIfDue(true); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if err := idx.requireBuilt(); err != nil {
+		return nil, err
+	}
+
 	result := C.urbis_query_knn(idx.ptr, C.double(x), C.double(y), C.size_t(k))
 	if result == nil {
 		return &ObjectList{Objects: []*SpatialObject{}, Count: 0}, nil
@@ -348,8 +1809,57 @@ func (idx *Index) QueryKNN(x, y float64, k uint32) (*ObjectList, error) {
 	return convertObjectList(result), nil
 }
 
+// Nearest returns the object nearest to (x, y) and its distance. Objects
+// are ranked by centroid as in QueryKNN, and the distance is measured to
+// the centroid in index coordinates, but no result list is built. Returns
+// ErrNotFound if the index is empty.
+func (idx *Index) Nearest(x, y float64) (*SpatialObject, float64, error) {
+	if err := idx.rebuildIfDue(true); err != nil {
+		return nil, 0, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if err := idx.requireBuilt(); err != nil {
+		return nil, 0, err
+	}
+
+	var nearest *C.SpatialObject
+	var distance C.double
+	if err := toError(C.urbis_nearest(idx.ptr, C.double(x), C.double(y), &nearest, &distance)); err != nil {
+		return nil, 0, err
+	}
+	return convertSpatialObject(nearest), float64(distance), nil
+}
+
+// QueryChangedSince returns the objects inserted or modified at or after
+// since, in ascending Version order. The index need not be built. Removed
+// objects are not reported.
+func (idx *Index) QueryChangedSince(since time.Time) (*ObjectList, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	result := C.urbis_query_changed_since(idx.ptr, C.int64_t(since.UnixMilli()))
+	if result == nil {
+		return nil, ErrAlloc
+	}
+	defer C.urbis_object_list_free(result)
+
+	return convertObjectList(result), nil
+}
+
 // QueryAdjacent queries objects in adjacent pages
 func (idx *Index) QueryAdjacent(region MBR) (*ObjectList, error) {
+	if err := idx.rebuildIfDue(true); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if err := idx.requireBuilt(); err != nil {
+		return nil, err
+	}
+
 	cmbr := C.MBR{
 		min_x: C.double(region.MinX),
 		min_y: C.double(region.MinY),
@@ -368,13 +1878,48 @@ func (idx *Index) QueryAdjacent(region MBR) (*ObjectList, error) {
 
 // convertObjectList converts C UrbisObjectList to Go
 func convertObjectList(clist *C.UrbisObjectList) *ObjectList {
-	if clist == nil || clist.count == 0 {
+	if clist == nil {
 		return &ObjectList{Objects: []*SpatialObject{}, Count: 0}
 	}
 
+	stats := QueryStats{
+		PagesVisited:   uint64(clist.stats.pages_visited),
+		TracksVisited:  uint64(clist.stats.tracks_visited),
+		EstimatedSeeks: uint64(clist.stats.estimated_seeks),
+		CacheHits:      uint64(clist.stats.cache_hits),
+		CacheMisses:    uint64(clist.stats.cache_misses),
+
+		Structure:         Structure(clist.stats.structure),
+		StructureFallback: bool(clist.stats.structure_fallback),
+
+		DuplicatesSuppressed: uint64(clist.stats.duplicates_suppressed),
+	}
+
+	var failed []uint32
+	if clist.failed_count > 0 {
+		for _, id := range unsafe.Slice(clist.failed_pages, clist.failed_count) {
+			failed = append(failed, uint32(id))
+		}
+	}
+
+	var pages []PageInfo
+	if clist.visited_count > 0 {
+		tracks := unsafe.Slice(clist.visited_tracks, clist.visited_count)
+		for i, id := range unsafe.Slice(clist.visited_pages, clist.visited_count) {
+			pages = append(pages, PageInfo{PageID: uint32(id), TrackID: uint32(tracks[i])})
+		}
+	}
+
+	if clist.count == 0 {
+		return &ObjectList{Objects: []*SpatialObject{}, Count: 0, Stats: stats, FailedPages: failed, Pages: pages}
+	}
+
 	list := &ObjectList{
-		Objects: make([]*SpatialObject, clist.count),
-		Count:   uint64(clist.count),
+		Objects:     make([]*SpatialObject, clist.count),
+		Count:       uint64(clist.count),
+		Stats:       stats,
+		FailedPages: failed,
+		Pages:       pages,
 	}
 
 	cobjects := unsafe.Slice(clist.objects, clist.count)
@@ -400,10 +1945,23 @@ type PageList struct {
 	Pages          []PageInfo
 	Count          uint64
 	EstimatedSeeks uint64
+	// EstimatedCostMs is the read time of the pages under the index's
+	// SeekCostModel
+	EstimatedCostMs float64
 }
 
 // FindAdjacentPages finds adjacent pages to a region
 func (idx *Index) FindAdjacentPages(region MBR) (*PageList, error) {
+	if err := idx.rebuildIfDue(true); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if err := idx.requireBuilt(); err != nil {
+		return nil, err
+	}
+
 	cmbr := C.MBR{
 		min_x: C.double(region.MinX),
 		min_y: C.double(region.MinY),
@@ -421,6 +1979,8 @@ func (idx *Index) FindAdjacentPages(region MBR) (*PageList, error) {
 		Pages:          make([]PageInfo, result.count),
 		Count:          uint64(result.count),
 		EstimatedSeeks: uint64(result.estimated_seeks),
+
+		EstimatedCostMs: float64(result.estimated_cost_ms),
 	}
 
 	if result.count > 0 {
@@ -437,6 +1997,30 @@ func (idx *Index) FindAdjacentPages(region MBR) (*PageList, error) {
 	return list, nil
 }
 
+// PrefetchRegion warms the page cache with the pages FindAdjacentPages
+// would return for region, so later queries there count as cache hits in
+// their QueryStats. The cache holds Config.CacheSize pages; prefetching
+// more evicts the least recently used.
+func (idx *Index) PrefetchRegion(region MBR) error {
+	if err := idx.rebuildIfDue(true); err != nil {
+		return err
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireBuilt(); err != nil {
+		return err
+	}
+
+	cmbr := C.MBR{
+		min_x: C.double(region.MinX),
+		min_y: C.double(region.MinY),
+		max_x: C.double(region.MaxX),
+		max_y: C.double(region.MaxY),
+	}
+	return toError(C.urbis_prefetch_region(idx.ptr, &cmbr, nil))
+}
+
 // =============================================================================
 // Statistics
 // =============================================================================
@@ -452,14 +2036,36 @@ type Stats struct {
 	KDTreeDepth        uint64
 	QuadtreeDepth      uint64
 	Bounds             MBR
+	PageCapacity       uint64
+	// MemoryBytes is the heap held by the C index, excluding allocator
+	// overhead; DiskBytes is the size of its data file, 0 if it has none
+	MemoryBytes uint64
+	DiskBytes   uint64
+	// BuildThreads is how many threads the last Build used, 0 before the
+	// first; fewer than Config.BuildThreads when the index was too small
+	// to split further
+	BuildThreads uint64
+	// WidthMeters and HeightMeters are the extent of Bounds in meters
+	// (see MBR.ExtentMeters), set when HasMeters is: for a CRSWGS84 index
+	// holding objects
+	WidthMeters  float64
+	HeightMeters float64
+	HasMeters    bool
 }
 
 // GetStats retrieves index statistics
 func (idx *Index) GetStats() Stats {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.stats()
+}
+
+// stats reads the index statistics. The caller must hold the index lock.
+func (idx *Index) stats() Stats {
 	var cstats C.UrbisStats
 	C.urbis_get_stats(idx.ptr, &cstats)
 
-	return Stats{
+	stats := Stats{
 		TotalObjects:       uint64(cstats.total_objects),
 		TotalBlocks:        uint64(cstats.total_blocks),
 		TotalPages:         uint64(cstats.total_pages),
@@ -474,16 +2080,75 @@ func (idx *Index) GetStats() Stats {
 			MaxX: float64(cstats.bounds.max_x),
 			MaxY: float64(cstats.bounds.max_y),
 		},
+		PageCapacity: uint64(cstats.page_capacity),
+		MemoryBytes:  uint64(cstats.memory_bytes),
+		DiskBytes:    uint64(cstats.disk_bytes),
+		BuildThreads: uint64(cstats.build_threads),
+	}
+	if stats.TotalObjects > 0 {
+		stats.WidthMeters, stats.HeightMeters, stats.HasMeters = stats.Bounds.ExtentMeters(idx.crs)
 	}
+	return stats
 }
 
 // Count returns the number of objects in the index
 func (idx *Index) Count() uint64 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
 	return uint64(C.urbis_count(idx.ptr))
 }
 
+// requireBuilt returns ErrNotBuilt unless the index is built. The flag lives
+// in the C index: Build and Load set it, inserts and removes clear it.
+// Caller holds idx.mu.
+func (idx *Index) requireBuilt() error {
+	if idx.ptr == nil {
+		return ErrNull
+	}
+	if !C.urbis_is_built(idx.ptr) {
+		return ErrNotBuilt
+	}
+	return nil
+}
+
+// requireWritable fails on a read-only index. The caller must hold the
+// index lock.
+func (idx *Index) requireWritable() error {
+	if idx.readOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// MarkReadOnly makes the index read-only for good: loads, inserts,
+// removals, SetProperties, Build, Optimize, Compact and an applied
+// AutoTuneConfig then fail with ErrReadOnly. Queries, Save and Sync still
+// work. It waits for writes in progress to finish.
+func (idx *Index) MarkReadOnly() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.readOnly = true
+}
+
+// ReadOnly reports whether the index is read-only
+func (idx *Index) ReadOnly() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.readOnly
+}
+
+// IsBuilt reports whether the index has been built since the last change
+func (idx *Index) IsBuilt() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return bool(C.urbis_is_built(idx.ptr))
+}
+
 // Bounds returns the spatial bounds of all data
 func (idx *Index) Bounds() MBR {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
 	cmbr := C.urbis_bounds(idx.ptr)
 	return MBR{
 		MinX: float64(cmbr.min_x),
@@ -497,30 +2162,145 @@ func (idx *Index) Bounds() MBR {
 // Persistence
 // =============================================================================
 
-// Save saves the index to a file
+// Save saves the index to a file. The file records the CRS, polygon
+// validation, property schema and valid bounds of the index, and Load
+// restores them. It is written to path with ".tmp" appended and renamed
+// to path once complete, so a failed save leaves any previous file there
+// whole. path then becomes the file Sync writes to.
 func (idx *Index) Save(path string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.inMemory {
+		return ErrInMemory
+	}
+	if err := idx.storeSettings(); err != nil {
+		return err
+	}
 	cpath := C.CString(path)
 	defer C.free(unsafe.Pointer(cpath))
 	return toError(C.urbis_save(idx.ptr, cpath))
 }
 
-// Load loads an index from a file
+// Load loads an index from a file, with the CRS, polygon validation,
+// property schema and valid bounds it was saved with. A file saved in an
+// older format is migrated as it loads; one saved in a newer format than
+// FormatVersion fails with ErrVersionMismatch, and an unreadable one with
+// ErrIO.
 func Load(path string) (*Index, error) {
+	return openIndex(nil, path)
+}
+
+// LoadWithConfig loads an index as Load does, into one created with
+// config, so that what Save does not write applies to it: the quadtree,
+// cache size, snapping, simplification, deduplication, indexed
+// properties, KeepDuplicates, InMemory and AutoRebuildThreshold among
+// others. The CRS, polygon validation, property schema and valid bounds
+// saved in the file replace those of config. Persist and DataPath are
+// ignored; the index uses path, as after Load.
+func LoadWithConfig(path string, config *Config) (*Index, error) {
+	if config != nil {
+		c := *config
+		c.Persist, c.DataPath = false, ""
+		config = &c
+	}
+	return openIndex(config, path)
+}
+
+// loadFile loads the index saved at path into a new C index created with
+// cConfig, nil for the defaults
+func loadFile(path string, cConfig *C.UrbisConfig, ptr **C.UrbisIndex) error {
 	cpath := C.CString(path)
 	defer C.free(unsafe.Pointer(cpath))
 
-	ptr := C.urbis_load(cpath)
-	if ptr == nil {
+	err := toError(C.urbis_load_config(cpath, cConfig, ptr))
+	if err == ErrVersionMismatch {
+		if version, verr := FileFormatVersion(path); verr == nil {
+			err = fmt.Errorf("%w: file is version %d, this build reads up to %d", err, version, FormatVersion())
+		}
+	}
+	return err
+}
+
+// WriteTo writes a snapshot of the index to w in the same format Save
+// writes to a file, so the two are interchangeable. The snapshot is staged
+// in a temporary file.
+func (idx *Index) WriteTo(w io.Writer) (int64, error) {
+	if idx.inMemory {
+		return 0, ErrInMemory
+	}
+	tmp, err := os.CreateTemp("", "urbis-*.idx")
+	if err != nil {
+		return 0, ErrIO
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := idx.Save(tmp.Name()); err != nil {
+		return 0, err
+	}
+
+	f, err := os.Open(tmp.Name())
+	if err != nil {
+		return 0, ErrIO
+	}
+	defer f.Close()
+	return io.Copy(w, f)
+}
+
+// SaveBytes returns a snapshot of the index in the Save file format
+func (idx *Index) SaveBytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := idx.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadReader loads an index from a snapshot in the Save file format
+func LoadReader(r io.Reader) (*Index, error) {
+	tmp, err := os.CreateTemp("", "urbis-*.idx")
+	if err != nil {
 		return nil, ErrIO
 	}
+	defer os.Remove(tmp.Name())
 
-	idx := &Index{ptr: ptr}
-	runtime.SetFinalizer(idx, (*Index).Close)
-	return idx, nil
+	_, err = io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, ErrIO
+	}
+	return Load(tmp.Name())
+}
+
+// LoadBytes loads an index from bytes produced by SaveBytes or Save
+func LoadBytes(data []byte) (*Index, error) {
+	return LoadReader(bytes.NewReader(data))
+}
+
+// ChangeCount returns how many inserts, removals, property and Z/M updates
+// were made since the index was created or loaded. It only grows, so a
+// caller that notes it at a Save can later tell whether there is anything
+// new to save.
+func (idx *Index) ChangeCount() uint64 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return uint64(C.urbis_change_count(idx.ptr))
 }
 
 // Sync syncs changes to disk
 func (idx *Index) Sync() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.inMemory {
+		return ErrInMemory
+	}
+	if err := idx.storeSettings(); err != nil {
+		return err
+	}
 	return toError(C.urbis_sync(idx.ptr))
 }
 
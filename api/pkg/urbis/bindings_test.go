@@ -0,0 +1,1521 @@
+package urbis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentAccess(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	const writers, readers, perWriter = 8, 8, 200
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				if _, err := idx.InsertPoint(float64(w*perWriter+i), float64(i)); err != nil {
+					t.Errorf("InsertPoint: %v", err)
+					return
+				}
+				if i%50 == 0 {
+					if err := idx.Build(); err != nil {
+						t.Errorf("Build: %v", err)
+						return
+					}
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				// Inserts invalidate the build, so readers may race ahead of it
+				if _, err := idx.QueryRange(MBR{MinX: 0, MinY: 0, MaxX: 500, MaxY: 500}); err != nil && err != ErrNotBuilt {
+					t.Errorf("QueryRange: %v", err)
+					return
+				}
+				idx.GetStats()
+				idx.Bounds()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := idx.Count(), uint64(writers*perWriter); got != want {
+		t.Fatalf("Count() = %d, want %d", got, want)
+	}
+}
+
+func TestGetObjectsReportsMissingIDs(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	a, _ := idx.InsertPoint(1, 2)
+	b, _ := idx.InsertPoint(3, 4)
+
+	objs, err := idx.GetObjects([]uint64{a, 999, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 3 {
+		t.Fatalf("got %d results, want 3", len(objs))
+	}
+	if objs[0] == nil || objs[0].ID != a {
+		t.Errorf("objs[0] = %+v, want ID %d", objs[0], a)
+	}
+	if objs[1] != nil {
+		t.Errorf("objs[1] = %+v, want nil for missing ID", objs[1])
+	}
+	if objs[2] == nil || objs[2].Point.X != 3 {
+		t.Errorf("objs[2] = %+v, want point (3, 4)", objs[2])
+	}
+}
+
+func TestBuildProgress(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 300; i++ {
+		idx.InsertPoint(float64(i), float64(i%17))
+	}
+
+	var calls int
+	var lastDone, lastTotal uint64
+	err = idx.BuildProgress(func(done, total uint64) {
+		if done < lastDone {
+			t.Errorf("progress went backwards: %d after %d", done, lastDone)
+		}
+		calls++
+		lastDone, lastTotal = done, total
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls < 2 || lastDone != 300 || lastTotal != 300 {
+		t.Fatalf("calls=%d last=%d/%d, want >=2 calls ending at 300/300", calls, lastDone, lastTotal)
+	}
+	if !idx.IsBuilt() {
+		t.Fatal("index not built after BuildProgress")
+	}
+}
+
+func TestBuildContextCancel(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 300; i++ {
+		idx.InsertPoint(float64(i), float64(i%17))
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancel once the rebuild has started
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = idx.BuildProgressContext(ctx, func(done, total uint64) { cancel() })
+	if !errors.Is(err, ErrCancelled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled build: err = %v, want ErrCancelled and context.Canceled", err)
+	}
+	if idx.IsBuilt() {
+		t.Fatal("index reports built after a cancelled build")
+	}
+	if _, err := idx.QueryRange(MBR{0, 0, 10, 10}); !errors.Is(err, ErrNotBuilt) {
+		t.Errorf("query after cancelled build: err = %v, want ErrNotBuilt", err)
+	}
+	if stats := idx.GetStats(); stats.TotalBlocks != 0 {
+		t.Errorf("cancelled build left %d blocks", stats.TotalBlocks)
+	}
+
+	if err := idx.BuildContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	list, err := idx.QueryRange(MBR{0, 0, 10, 10})
+	if err != nil || list.Count == 0 {
+		t.Fatalf("query after rebuild = %v, %v", list, err)
+	}
+}
+
+func TestOptimizeContextDeadline(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 300; i++ {
+		idx.InsertPoint(float64(i), float64(i%17))
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	before := idx.GetStats()
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	expired := func(op string, err error) {
+		t.Helper()
+		if !errors.Is(err, ErrCancelled) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s past its deadline: err = %v, want ErrCancelled and context.DeadlineExceeded", op, err)
+		}
+	}
+	_, err = idx.OptimizeContext(ctx)
+	expired("optimize", err)
+	_, err = idx.CompactContext(ctx)
+	expired("compact", err)
+	_, err = idx.AutoTuneConfigContext(ctx, nil, true)
+	expired("autotune", err)
+
+	// The index keeps serving exactly as before
+	if !idx.IsBuilt() {
+		t.Fatal("index reports unbuilt after a stopped optimize")
+	}
+	list, err := idx.QueryRange(MBR{0, 0, 10, 10})
+	if err != nil || list.Count != 11 {
+		t.Fatalf("query after a stopped optimize = %v, %v; want 11 objects", list, err)
+	}
+	if after := idx.GetStats(); after.TotalPages != before.TotalPages || after.PageCapacity != before.PageCapacity {
+		t.Errorf("stopped calls changed the layout: %d pages of %d before, %d of %d after",
+			before.TotalPages, before.PageCapacity, after.TotalPages, after.PageCapacity)
+	}
+
+	report, err := idx.OptimizeContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !idx.IsBuilt() || report.After.TotalObjects != 300 {
+		t.Errorf("optimize after the deadline: built = %v, %d objects", idx.IsBuilt(), report.After.TotalObjects)
+	}
+}
+
+func TestWKBRoundTrip(t *testing.T) {
+	src, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	id, err := src.InsertPolygon([]Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wkb, err := src.ExportWKB(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	if err := dst.LoadWKB(append(wkb, wkb...)); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Count() != 2 {
+		t.Fatalf("Count() = %d, want 2", dst.Count())
+	}
+	obj, err := dst.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Type != GeomPolygon || len(obj.Polygon) != 5 || obj.MBR.MaxX != 4 {
+		t.Fatalf("round-tripped object = %+v", obj)
+	}
+
+	if _, err := src.ExportWKB(999); err != ErrNotFound {
+		t.Fatalf("ExportWKB(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRejectNonFiniteCoordinates(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	idx.InsertPoint(1, 1)
+	idx.InsertPoint(5, 5)
+
+	if _, err := idx.InsertPoint(math.NaN(), 3); err != ErrInvalid {
+		t.Errorf("InsertPoint(NaN) error = %v, want ErrInvalid", err)
+	}
+	if _, err := idx.InsertLineString([]Point{{0, 0}, {math.Inf(1), 2}}); err != ErrInvalid {
+		t.Errorf("InsertLineString(+Inf) error = %v, want ErrInvalid", err)
+	}
+	if err := idx.LoadWKT("POINT (nan 2)"); err != ErrInvalid {
+		t.Errorf("LoadWKT(nan) error = %v, want ErrInvalid", err)
+	}
+
+	if got := idx.Count(); got != 2 {
+		t.Fatalf("Count() = %d, want 2", got)
+	}
+	if got, want := idx.Bounds(), (MBR{MinX: 1, MinY: 1, MaxX: 5, MaxY: 5}); got != want {
+		t.Fatalf("Bounds() = %+v, want %+v", got, want)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := idx.QueryRange(MBR{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}); res.Count != 2 {
+		t.Fatalf("QueryRange returned %d objects, want 2", res.Count)
+	}
+}
+
+func TestSnapAndDedupPoints(t *testing.T) {
+	config := DefaultConfig()
+	config.SnapPrecision = 0.01
+	config.DedupPoints = true
+	idx, err := NewIndex(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	a, _ := idx.InsertPoint(10.004, 20.001)
+	b, _ := idx.InsertPoint(9.999, 19.996)
+	if a == 0 || a != b {
+		t.Fatalf("duplicate snapped points got IDs %d and %d, want the same", a, b)
+	}
+	if idx.Count() != 1 {
+		t.Fatalf("Count() = %d, want 1", idx.Count())
+	}
+
+	obj, err := idx.Get(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Point.X != 10 || obj.Point.Y != 20 {
+		t.Errorf("snapped point = %+v, want (10, 20)", *obj.Point)
+	}
+	if string(obj.Properties) != `{"count":2}` {
+		t.Errorf("properties = %q, want {\"count\":2}", obj.Properties)
+	}
+}
+
+func TestSimplifyTolerance(t *testing.T) {
+	config := DefaultConfig()
+	config.SimplifyTolerance = 0.5
+	idx, err := NewIndex(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	// A wobbly line along y=0 that turns up at x=10
+	line := []Point{{0, 0}, {2, 0.1}, {4, -0.2}, {6, 0.1}, {8, 0}, {10, 0}, {10, 5}}
+	id, err := idx.InsertLineString(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := idx.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Point{{0, 0}, {10, 0}, {10, 5}}
+	if !slices.Equal(obj.Line, want) {
+		t.Errorf("simplified line = %v, want %v", obj.Line, want)
+	}
+
+	config.SimplifyTolerance = -1
+	if _, err := NewIndex(&config); !errors.Is(err, ErrInvalid) {
+		t.Errorf("NewIndex with negative tolerance: err = %v, want ErrInvalid", err)
+	}
+}
+
+func TestQueryStructureHint(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 200; i++ {
+		if _, err := idx.InsertPoint(float64(i%20), float64(i/20)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	region := MBR{MinX: 2, MinY: 2, MaxX: 6, MaxY: 6}
+
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	scan, err := idx.QueryRangeUsing(region, StructureScan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scan.Stats.Structure != StructureScan || scan.Count == 0 {
+		t.Fatalf("scan: structure %d, %d objects", scan.Stats.Structure, scan.Count)
+	}
+
+	for _, s := range []Structure{StructureKDTree, StructureQuadtree} {
+		list, err := idx.QueryRangeUsing(region, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if list.Stats.Structure != s || list.Stats.StructureFallback {
+			t.Errorf("structure %d: answered by %d (fallback %v)", s, list.Stats.Structure, list.Stats.StructureFallback)
+		}
+		if list.Count != scan.Count {
+			t.Errorf("structure %d: got %d objects, want %d", s, list.Count, scan.Count)
+		}
+	}
+}
+
+func TestSetProperties(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	id, _ := idx.InsertPoint(1, 2)
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := idx.SetProperties(id, []byte(`{"inspected":true}`)); err != nil {
+		t.Fatal(err)
+	}
+	props, err := idx.GetProperties(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(props) != `{"inspected":true}` {
+		t.Errorf("GetProperties = %q", props)
+	}
+	if !idx.IsBuilt() {
+		t.Error("SetProperties invalidated the build")
+	}
+
+	if err := idx.SetProperties(id, nil); err != nil {
+		t.Fatal(err)
+	}
+	if props, _ := idx.GetProperties(id); props != nil {
+		t.Errorf("cleared properties = %q, want nil", props)
+	}
+
+	if err := idx.SetProperties(id+1, []byte(`{}`)); err != ErrNotFound {
+		t.Errorf("SetProperties on missing ID: got %v, want ErrNotFound", err)
+	}
+	if _, err := idx.GetProperties(id + 1); err != ErrNotFound {
+		t.Errorf("GetProperties on missing ID: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestQueryUnbuiltIndex(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	region := MBR{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}
+	if _, err := idx.QueryRange(region); err != ErrNotBuilt {
+		t.Fatalf("QueryRange on new index: got %v, want ErrNotBuilt", err)
+	}
+
+	idx.InsertPoint(1, 1)
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.QueryRange(region); err != nil {
+		t.Fatalf("QueryRange after Build: %v", err)
+	}
+
+	// Inserting invalidates the build until the next Build
+	idx.InsertPoint(2, 2)
+	if _, err := idx.QueryRange(region); err != ErrNotBuilt {
+		t.Errorf("QueryRange after insert: got %v, want ErrNotBuilt", err)
+	}
+	if _, err := idx.QueryKNN(1, 1, 1); err != ErrNotBuilt {
+		t.Errorf("QueryKNN after insert: got %v, want ErrNotBuilt", err)
+	}
+	if _, err := idx.FindAdjacentPages(region); err != ErrNotBuilt {
+		t.Errorf("FindAdjacentPages after insert: got %v, want ErrNotBuilt", err)
+	}
+}
+
+func TestRemoveRange(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	idx.InsertPoint(1, 1)
+	idx.InsertPoint(20, 20)
+	line, _ := idx.InsertLineString([]Point{{5, 5}, {15, 5}})
+
+	region := MBR{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}
+	removed, err := idx.RemoveRange(region, MatchContained)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 || idx.Count() != 2 {
+		t.Fatalf("MatchContained removed %d, %d left; want 1 removed, 2 left", removed, idx.Count())
+	}
+
+	removed, err = idx.RemoveRange(region, MatchIntersects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Fatalf("MatchIntersects removed %d, want 1", removed)
+	}
+	if _, err := idx.Get(line); err != ErrNotFound {
+		t.Errorf("line straddling the region survived MatchIntersects")
+	}
+}
+
+func TestSaveBytesMatchesFileFormat(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 100; i++ {
+		idx.InsertPoint(float64(i), float64(i%10))
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := idx.SaveBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Bytes written to disk load like a Save file
+	path := filepath.Join(t.TempDir(), "snapshot.idx")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fromFile.Close()
+
+	fromBytes, err := LoadBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fromBytes.Close()
+
+	for _, loaded := range []*Index{fromFile, fromBytes} {
+		if loaded.Count() != 100 {
+			t.Errorf("loaded count = %d, want 100", loaded.Count())
+		}
+	}
+
+	if _, err := LoadBytes([]byte("not an index")); err == nil {
+		t.Error("LoadBytes accepted garbage")
+	}
+}
+
+func TestChangeCountAcrossSaves(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	id, err := idx.InsertPoint(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.SetProperties(id, []byte(`{"a":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.InsertPoint(2, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Remove(id); err != nil {
+		t.Fatal(err)
+	}
+	if got := idx.ChangeCount(); got != 4 {
+		t.Errorf("ChangeCount = %d after 2 inserts, an update and a removal, want 4", got)
+	}
+
+	// Every save writes the whole index, not just what changed since the last
+	dir := t.TempDir()
+	for i, name := range []string{"first", "second"} {
+		path := filepath.Join(dir, name)
+		if err := idx.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded.Count() != 1 || loaded.ChangeCount() != 0 {
+			t.Errorf("save %d: loaded %d objects with change count %d, want 1 and 0", i+1, loaded.Count(), loaded.ChangeCount())
+		}
+		loaded.Close()
+	}
+}
+
+func TestSaveLoadKeepsIDs(t *testing.T) {
+	idx, err := NewIndex(&Config{PageCapacity: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	points := make(map[uint64]Point)
+	for i := 0; i < 50; i++ {
+		p := Point{X: float64(i%7) * 3, Y: float64(i / 7)}
+		id, err := idx.InsertPoint(p.X, p.Y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		points[id] = p
+	}
+	explicit := uint64(1000)
+	if err := idx.InsertPointWithID(explicit, -5, -5); err != nil {
+		t.Fatal(err)
+	}
+	points[explicit] = Point{X: -5, Y: -5}
+	// The highest ID is gone before saving; it must not be handed out again
+	idx.Remove(explicit)
+	delete(points, explicit)
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := idx.SaveBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer loaded.Close()
+
+	for id, p := range points {
+		obj, err := loaded.Get(id)
+		if err != nil {
+			t.Fatalf("object %d after load: %v", id, err)
+		}
+		if obj.Point == nil || *obj.Point != p {
+			t.Errorf("object %d after load = %v, want %v", id, obj.Point, p)
+		}
+	}
+	if loaded.Count() != uint64(len(points)) {
+		t.Errorf("loaded %d objects, want %d", loaded.Count(), len(points))
+	}
+
+	id, err := loaded.InsertPoint(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id <= explicit {
+		t.Errorf("insert after load got ID %d, want one above %d", id, explicit)
+	}
+}
+
+func TestInMemoryIndexTouchesNoFiles(t *testing.T) {
+	// Anything staged in the temp directory or written relative to the
+	// working directory would land in dir
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	idx, err := NewInMemoryIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	if !idx.InMemory() {
+		t.Error("NewInMemoryIndex index does not report InMemory")
+	}
+
+	for i := 0; i < 500; i++ {
+		if _, err := idx.InsertPoint(float64(i%25), float64(i/25)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := idx.InsertPolygon([]Point{{0, 0}, {5, 0}, {5, 5}, {0, 5}, {0, 0}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.LoadGeoJSONString(`{"type":"Point","coordinates":[30,30]}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	res, err := idx.QueryRange(MBR{MinX: 0, MinY: 0, MaxX: 4, MaxY: 4})
+	if err != nil || len(res.Objects) == 0 {
+		t.Fatalf("QueryRange = %v, %v", res, err)
+	}
+	if res, err := idx.QueryKNN(30, 30, 3); err != nil || len(res.Objects) != 3 {
+		t.Fatalf("QueryKNN = %v, %v", res, err)
+	}
+
+	if err := idx.Save("index.urbis"); !errors.Is(err, ErrInMemory) {
+		t.Errorf("Save: err = %v, want ErrInMemory", err)
+	}
+	if _, err := idx.SaveBytes(); !errors.Is(err, ErrInMemory) {
+		t.Errorf("SaveBytes: err = %v, want ErrInMemory", err)
+	}
+	if err := idx.Sync(); !errors.Is(err, ErrInMemory) {
+		t.Errorf("Sync: err = %v, want ErrInMemory", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("in-memory index left %s in the directory", e.Name())
+	}
+
+	if _, err := NewIndex(&Config{InMemory: true, Persist: true, DataPath: dir}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("InMemory with a data path: err = %v, want ErrInvalid", err)
+	}
+}
+
+func TestOptimizeReport(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 1000; i++ {
+		idx.InsertPoint(float64(i%40), float64(i/40))
+	}
+
+	report, err := idx.Optimize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Before.TotalObjects != 1000 || report.After.TotalObjects != 1000 {
+		t.Errorf("object counts before/after = %d/%d, want 1000", report.Before.TotalObjects, report.After.TotalObjects)
+	}
+	if report.Before.KDTreeDepth != 0 || report.After.KDTreeDepth == 0 {
+		t.Errorf("KD-tree depth before/after = %d/%d, want 0 then built", report.Before.KDTreeDepth, report.After.KDTreeDepth)
+	}
+	if report.SeeksAfter == 0 {
+		t.Error("no seek estimate after optimizing")
+	}
+	if !idx.IsBuilt() {
+		t.Error("index not built after Optimize")
+	}
+}
+
+func TestLoadGzipGeoJSON(t *testing.T) {
+	geojson := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[3,4]},"properties":{}}]}`
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(geojson))
+	zw.Close()
+
+	dir := t.TempDir()
+	named := filepath.Join(dir, "points.geojson.gz")
+	sniffed := filepath.Join(dir, "points.geojson")
+	for _, path := range []string{named, sniffed} {
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		idx, err := NewIndex(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := idx.LoadGeoJSON(path); err != nil {
+			t.Errorf("LoadGeoJSON(%s): %v", filepath.Base(path), err)
+		} else if idx.Count() != 2 {
+			t.Errorf("LoadGeoJSON(%s) loaded %d objects, want 2", filepath.Base(path), idx.Count())
+		}
+		idx.Close()
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.geojson.gz")
+	os.WriteFile(corrupt, []byte("not gzip"), 0o644)
+	idx, _ := NewIndex(nil)
+	defer idx.Close()
+	if err := idx.LoadGeoJSON(corrupt); !errors.Is(err, ErrParse) {
+		t.Errorf("corrupt gzip: got %v, want ErrParse", err)
+	}
+}
+
+func TestPrefetchRegion(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	region := MBR{MinX: 0, MinY: 0, MaxX: 30, MaxY: 30}
+	if err := idx.PrefetchRegion(region); err != ErrNotBuilt {
+		t.Fatalf("PrefetchRegion on unbuilt index: got %v, want ErrNotBuilt", err)
+	}
+
+	for i := 0; i < 400; i++ {
+		idx.InsertPoint(float64(i%20*5), float64(i/20*5))
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	cold, err := idx.QueryRange(region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.PrefetchRegion(region); err != nil {
+		t.Fatal(err)
+	}
+	warm, err := idx.QueryRange(region)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cold.Stats.CacheHits != 0 {
+		t.Errorf("cold query: %d cache hits, want 0", cold.Stats.CacheHits)
+	}
+	if warm.Stats.CacheHits != warm.Stats.PagesVisited || warm.Stats.CacheMisses != 0 {
+		t.Errorf("after prefetch: %+v, want every visited page cached", warm.Stats)
+	}
+}
+
+func TestQueryRangePages(t *testing.T) {
+	config := DefaultConfig()
+	config.PageCapacity = 8
+	idx, err := NewIndex(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 200; i++ {
+		idx.InsertPoint(float64(i%20), float64(i/20))
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	graph, err := idx.GetPageGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracks := map[uint32]uint32{}
+	for _, node := range graph.Nodes {
+		tracks[node.PageID] = node.TrackID
+	}
+
+	result, err := idx.QueryRange(MBR{MinX: 2, MinY: 2, MaxX: 8, MaxY: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Pages) == 0 || uint64(len(result.Pages)) != result.Stats.PagesVisited {
+		t.Fatalf("%d pages listed, stats say %d visited", len(result.Pages), result.Stats.PagesVisited)
+	}
+	visitedTracks := map[uint32]bool{}
+	for _, page := range result.Pages {
+		if track, ok := tracks[page.PageID]; !ok || track != page.TrackID {
+			t.Errorf("page %d on track %d, page graph has it on track %d", page.PageID, page.TrackID, track)
+		}
+		visitedTracks[page.TrackID] = true
+	}
+	if uint64(len(visitedTracks)) != result.Stats.TracksVisited {
+		t.Errorf("pages span %d tracks, stats say %d", len(visitedTracks), result.Stats.TracksVisited)
+	}
+
+	if empty, _ := idx.QueryRange(MBR{MinX: 50, MinY: 50, MaxX: 60, MaxY: 60}); len(empty.Pages) != 0 {
+		t.Errorf("query outside the data touched pages %v", empty.Pages)
+	}
+}
+
+func TestAutoTuneConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.PageCapacity = 8
+	idx, err := NewIndex(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 900; i++ {
+		idx.InsertPoint(float64(i%30), float64(i/30))
+	}
+	if got := idx.GetStats().PageCapacity; got != 8 {
+		t.Fatalf("PageCapacity = %d, want 8", got)
+	}
+
+	queries := []MBR{{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}, {MinX: 15, MinY: 15, MaxX: 29, MaxY: 29}}
+	report, err := idx.AutoTuneConfig(queries, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Candidates) != 4 || report.Applied {
+		t.Fatalf("report = %+v, want 4 candidates and nothing applied", report)
+	}
+	best := report.Candidates[0]
+	for _, c := range report.Candidates {
+		if c.Cost < best.Cost {
+			best = c
+		}
+	}
+	if report.PageCapacity != best.PageCapacity {
+		t.Errorf("recommended %d, but the cheapest candidate is %d", report.PageCapacity, best.PageCapacity)
+	}
+	if idx.GetStats().PageCapacity != 8 {
+		t.Error("recommendation changed the index")
+	}
+
+	report, err = idx.AutoTuneConfig(nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := idx.GetStats()
+	if stats.PageCapacity != report.PageCapacity || stats.TotalObjects != 900 {
+		t.Errorf("after apply: stats = %+v, want capacity %d and 900 objects", stats, report.PageCapacity)
+	}
+	if report.Applied != (report.PageCapacity != 8) {
+		t.Errorf("Applied = %v for recommendation %d", report.Applied, report.PageCapacity)
+	}
+	if _, err := idx.Get(450); err != nil {
+		t.Errorf("object lost after repaging: %v", err)
+	}
+}
+
+func TestAutoTuneApplyKeepsDataFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.urbis")
+	config := DefaultConfig()
+	config.PageCapacity = 8
+	idx, err := NewIndex(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 900; i++ {
+		idx.InsertPoint(float64(i%30), float64(i/30))
+	}
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	report, err := idx.AutoTuneConfig(nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Applied {
+		t.Fatalf("report = %+v, want a new page capacity applied", report)
+	}
+
+	// Writes after the new layout is applied still sync to the same file
+	if _, err := idx.InsertPoint(50, 50); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Sync(); err != nil {
+		t.Fatalf("Sync after AutoTuneConfig: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer loaded.Close()
+	if n := loaded.Count(); n != 901 {
+		t.Errorf("file holds %d objects, want 901", n)
+	}
+	if got, want := loaded.GetStats().TotalPages, idx.GetStats().TotalPages; got != want {
+		t.Errorf("file holds %d pages, want the %d of the tuned layout", got, want)
+	}
+}
+
+func TestQueryChangedSince(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	start := time.Now().Add(-time.Second)
+	a, _ := idx.InsertPoint(1, 1)
+	b, _ := idx.InsertPoint(2, 2)
+
+	objA, err := idx.Get(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objA.Version == 0 || objA.ModifiedAt.Before(start) {
+		t.Fatalf("insert stamp = version %d at %v, want a version after %v", objA.Version, objA.ModifiedAt, start)
+	}
+
+	if err := idx.SetProperties(a, []byte(`{"name":"a"}`)); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := idx.QueryChangedSince(start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.Count != 2 || changed.Objects[0].ID != b || changed.Objects[1].ID != a {
+		t.Fatalf("changed objects not in version order: %+v", changed.Objects)
+	}
+	if changed.Objects[1].Version <= objA.Version {
+		t.Errorf("SetProperties left version at %d", changed.Objects[1].Version)
+	}
+
+	changed, err = idx.QueryChangedSince(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.Count != 0 {
+		t.Errorf("got %d objects changed in the future", changed.Count)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := idx.Nearest(0, 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("empty index: got %v, want ErrNotFound", err)
+	}
+
+	idx.InsertPoint(0, 0)
+	want, _ := idx.InsertPoint(10, 10)
+	idx.InsertPoint(20, 20)
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	obj, distance, err := idx.Nearest(13, 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.ID != want || distance != 5 {
+		t.Errorf("got object %d at %v, want %d at 5", obj.ID, distance, want)
+	}
+	knn, err := idx.QueryKNN(13, 14, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if knn.Objects[0].ID != obj.ID {
+		t.Errorf("Nearest found %d, QueryKNN found %d", obj.ID, knn.Objects[0].ID)
+	}
+}
+
+func TestStatsMemoryAndDiskBytes(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	before := idx.GetStats()
+	if before.MemoryBytes == 0 || before.DiskBytes != 0 {
+		t.Fatalf("empty index: memory %d, disk %d", before.MemoryBytes, before.DiskBytes)
+	}
+
+	line := make([]Point, 50)
+	for i := range line {
+		line[i] = Point{X: float64(i), Y: float64(i % 5)}
+	}
+	for i := 0; i < 1000; i++ {
+		if _, err := idx.InsertLineString(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	after := idx.GetStats()
+	if grown := after.MemoryBytes - before.MemoryBytes; grown < 1000*50*16 {
+		t.Errorf("memory grew by %d bytes for 50000 points", grown)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.dat")
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if disk := idx.GetStats().DiskBytes; disk == 0 {
+		t.Error("DiskBytes is 0 after Save")
+	}
+}
+
+func TestBuildThreads(t *testing.T) {
+	if _, err := NewIndex(&Config{BuildThreads: -1}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("negative build threads: err = %v, want ErrInvalid", err)
+	}
+
+	serial, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer serial.Close()
+	parallel, err := NewIndex(&Config{BuildThreads: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer parallel.Close()
+
+	for i := 0; i < 10000; i++ {
+		x, y := float64(i*7919%1000), float64(i*104729%997)
+		serial.InsertPoint(x, y)
+		parallel.InsertPoint(x, y)
+	}
+	if got := parallel.GetStats().BuildThreads; got != 0 {
+		t.Errorf("before Build: BuildThreads = %d, want 0", got)
+	}
+	if err := serial.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := parallel.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if got := serial.GetStats().BuildThreads; got != 1 {
+		t.Errorf("serial BuildThreads = %d, want 1", got)
+	}
+	if got := parallel.GetStats().BuildThreads; got != 4 {
+		t.Errorf("parallel BuildThreads = %d, want 4", got)
+	}
+
+	region := MBR{MinX: 100, MinY: 200, MaxX: 350, MaxY: 420}
+	want, err := serial.QueryRange(region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parallel.QueryRange(region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Count != want.Count || got.Count == 0 {
+		t.Errorf("parallel build found %d objects, serial %d", got.Count, want.Count)
+	}
+}
+
+func TestBuildSeedIsReproducible(t *testing.T) {
+	build := func(seed uint64) *Index {
+		idx, err := NewIndex(&Config{PageCapacity: 8, EnableQuadtree: true, Seed: seed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(idx.Close)
+		// Four distinct x values make most x splits ties
+		for i := 0; i < 400; i++ {
+			idx.InsertPoint(float64(i%4), float64(i*37%101))
+		}
+		if err := idx.Build(); err != nil {
+			t.Fatal(err)
+		}
+		return idx
+	}
+	tree := func(idx *Index) []TreeNode {
+		nodes, err := idx.GetTreeStructure(StructureKDTree, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nodes
+	}
+	adjacent := func(idx *Index) *PageList {
+		pages, err := idx.FindAdjacentPages(MBR{MinX: 0, MinY: 20, MaxX: 2, MaxY: 40})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pages
+	}
+
+	a, b, c := build(42), build(42), build(43)
+	if !reflect.DeepEqual(a.GetStats(), b.GetStats()) {
+		t.Errorf("stats differ with the same seed:\n%+v\n%+v", a.GetStats(), b.GetStats())
+	}
+	if !reflect.DeepEqual(adjacent(a), adjacent(b)) {
+		t.Error("adjacent pages differ with the same seed")
+	}
+	if !reflect.DeepEqual(tree(a), tree(b)) {
+		t.Error("KD-trees differ with the same seed")
+	}
+	if reflect.DeepEqual(tree(a), tree(c)) {
+		t.Error("KD-trees match with different seeds")
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	id, _ := idx.InsertPoint(1, 1)
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	idx.MarkReadOnly()
+	if !idx.ReadOnly() {
+		t.Fatal("ReadOnly() = false after MarkReadOnly")
+	}
+
+	writes := map[string]func() error{
+		"InsertPoint":       func() error { _, err := idx.InsertPoint(2, 2); return err },
+		"InsertPointWithID": func() error { return idx.InsertPointWithID(99, 2, 2) },
+		"InsertMultiPoint":  func() error { _, err := idx.InsertMultiPoint([]Point{{X: 2, Y: 2}}); return err },
+		"LoadGeoJSONString": func() error { return idx.LoadGeoJSONString(`{"type":"Point","coordinates":[2,2]}`) },
+		"Remove":            func() error { return idx.Remove(id) },
+		"SetProperties":     func() error { return idx.SetProperties(id, []byte(`{}`)) },
+		"Build":             idx.Build,
+		"Optimize":          func() error { _, err := idx.Optimize(); return err },
+		"Compact":           func() error { _, err := idx.Compact(); return err },
+		"AutoTuneConfig":    func() error { _, err := idx.AutoTuneConfig(nil, true); return err },
+	}
+	for name, write := range writes {
+		if err := write(); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s: err = %v, want ErrReadOnly", name, err)
+		}
+	}
+
+	if res, err := idx.QueryRange(MBR{MinX: 0, MinY: 0, MaxX: 5, MaxY: 5}); err != nil || res.Count != 1 {
+		t.Errorf("query on read-only index: %v, err %v", res, err)
+	}
+	if _, err := idx.AutoTuneConfig(nil, false); err != nil {
+		t.Errorf("AutoTuneConfig without apply: %v", err)
+	}
+
+	fresh, err := NewIndex(&Config{ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fresh.Close()
+	if _, err := fresh.InsertPoint(1, 1); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Config.ReadOnly: err = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestInsertInfoMatchesGet(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	square := []Point{{0, 0}, {4, 0}, {4, 2}, {0, 2}, {0, 0}}
+	ins, err := idx.InsertPolygonInfo(square)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (MBR{MinX: 0, MinY: 0, MaxX: 4, MaxY: 2}); ins.MBR != want {
+		t.Errorf("MBR = %+v, want %+v", ins.MBR, want)
+	}
+
+	obj, err := idx.Get(ins.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ins.MBR != obj.MBR || ins.Centroid != obj.Centroid {
+		t.Errorf("insert returned %+v / %+v, Get returned %+v / %+v", ins.MBR, ins.Centroid, obj.MBR, obj.Centroid)
+	}
+
+	if _, err := idx.InsertPolygonInfo(square[:2]); !errors.Is(err, ErrInvalid) {
+		t.Errorf("short ring: err = %v, want ErrInvalid", err)
+	}
+}
+
+func TestInsertWithID(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	if err := idx.InsertPointWithID(500, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if obj, err := idx.Get(500); err != nil || *obj.Point != (Point{1, 2}) {
+		t.Fatalf("Get(500) = %+v, %v", obj, err)
+	}
+
+	err = idx.InsertLineStringWithID(500, []Point{{0, 0}, {1, 1}})
+	if !errors.Is(err, ErrIDInUse) || !errors.Is(err, ErrInvalid) {
+		t.Errorf("reused ID: err = %v, want ErrIDInUse wrapping ErrInvalid", err)
+	}
+	if err := idx.InsertPolygonWithID(0, []Point{{0, 0}, {1, 0}, {1, 1}, {0, 0}}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("ID 0: err = %v, want ErrInvalid", err)
+	}
+
+	id, err := idx.InsertPoint(3, 3)
+	if err != nil || id != 501 {
+		t.Errorf("next automatic ID = %d, %v; want 501", id, err)
+	}
+}
+
+func TestCompactReleasesEmptiedPages(t *testing.T) {
+	idx, err := NewIndex(&Config{PageCapacity: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	var ids []uint64
+	for i := 0; i < 200; i++ {
+		id, _ := idx.InsertPoint(float64(i%20), float64(i/20))
+		ids = append(ids, id)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids[:180] {
+		idx.Remove(id)
+	}
+	kept, _ := idx.Get(ids[190])
+
+	report, err := idx.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Before.TotalObjects != 20 || report.After.TotalObjects != 20 {
+		t.Fatalf("objects %d -> %d, want 20 both times", report.Before.TotalObjects, report.After.TotalObjects)
+	}
+	if report.After.TotalPages >= report.Before.TotalPages || report.After.PageUtilization <= report.Before.PageUtilization {
+		t.Errorf("pages %d -> %d, utilization %.2f -> %.2f; want fewer, fuller pages",
+			report.Before.TotalPages, report.After.TotalPages, report.Before.PageUtilization, report.After.PageUtilization)
+	}
+
+	got, err := idx.Get(ids[190])
+	if err != nil || got.Version != kept.Version || *got.Point != *kept.Point {
+		t.Errorf("object after compaction = %+v, %v; want %+v", got, err, kept)
+	}
+	if _, err := idx.QueryRange(MBR{MaxX: 20, MaxY: 20}); err != nil {
+		t.Errorf("query after compaction: %v", err)
+	}
+	if id, _ := idx.InsertPoint(50, 50); id != ids[len(ids)-1]+1 {
+		t.Errorf("next ID = %d, want %d", id, ids[len(ids)-1]+1)
+	}
+}
+
+func TestCompactKeepsDataFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.urbis")
+	idx, err := NewIndex(&Config{PageCapacity: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	var ids []uint64
+	for i := 0; i < 200; i++ {
+		id, _ := idx.InsertPoint(float64(i%20), float64(i/20))
+		ids = append(ids, id)
+	}
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids[:180] {
+		idx.Remove(id)
+	}
+	report, err := idx.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Writes after the compaction still sync to the same file
+	if _, err := idx.InsertPoint(50, 50); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Sync(); err != nil {
+		t.Fatalf("Sync after Compact: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer loaded.Close()
+	if n := loaded.Count(); n != 21 {
+		t.Errorf("file holds %d objects, want 21", n)
+	}
+	if pages := loaded.GetStats().TotalPages; pages != report.After.TotalPages {
+		t.Errorf("file holds %d pages, want the %d compacted ones", pages, report.After.TotalPages)
+	}
+
+	// Closing writes to the file too, not the pages from before
+	idx.Remove(ids[199])
+	idx.Close()
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Close()
+	if n := reloaded.Count(); n != 20 {
+		t.Errorf("file holds %d objects after Close, want 20", n)
+	}
+}
+
+func TestBlockSizeValidation(t *testing.T) {
+	for _, size := range []uint64{1, 63, 100, 1000, 1 << 21, math.MaxUint64} {
+		if idx, err := NewIndex(&Config{BlockSize: size}); !errors.Is(err, ErrInvalid) {
+			if idx != nil {
+				idx.Close()
+			}
+			t.Errorf("block size %d: err = %v, want ErrInvalid", size, err)
+		}
+	}
+
+	// Zero takes the default, so the index still partitions into blocks
+	idx, err := NewIndex(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	for i := 0; i < 100; i++ {
+		idx.InsertPoint(float64(i), float64(i))
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if blocks := idx.GetStats().TotalBlocks; blocks != 1 {
+		t.Errorf("default block size: %d blocks for 100 points, want 1", blocks)
+	}
+
+	for _, size := range []uint64{MinBlockSize, 1024, MaxBlockSize} {
+		idx, err := NewIndex(&Config{BlockSize: size})
+		if err != nil {
+			t.Errorf("block size %d: %v", size, err)
+			continue
+		}
+		idx.Close()
+	}
+}
+
+func TestEstimateCount(t *testing.T) {
+	idx, err := NewIndex(&Config{PageCapacity: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 400; i++ {
+		idx.InsertPoint(float64(i%20), float64(i/20))
+	}
+	region := MBR{MinX: 3, MinY: 3, MaxX: 7.5, MaxY: 7.5}
+	if _, err := idx.EstimateCount(region); !errors.Is(err, ErrNotBuilt) {
+		t.Errorf("unbuilt index: err = %v, want ErrNotBuilt", err)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	estimate, err := idx.EstimateCount(region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := idx.QueryRange(region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate < result.Count || estimate >= 400 {
+		t.Errorf("estimate %d for %d matches of 400", estimate, result.Count)
+	}
+	if n, _ := idx.EstimateCount(MBR{MinX: 100, MinY: 100, MaxX: 200, MaxY: 200}); n != 0 {
+		t.Errorf("estimate outside the data = %d, want 0", n)
+	}
+}
+
+func TestQueryRangeDegenerateBoxes(t *testing.T) {
+	idx, err := NewIndex(&Config{PageCapacity: 8, EnableQuadtree: true, AutoRebuildThreshold: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	// A grid of points at whole coordinates, a road along y = 5 and a
+	// square over (2, 2)-(8, 8)
+	for i := 0; i < 100; i++ {
+		idx.InsertPoint(float64(i%10), float64(i/10))
+	}
+	road, _ := idx.InsertLineString([]Point{{0, 5}, {9, 5}})
+	square, _ := idx.InsertPolygon([]Point{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}})
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		region MBR
+		want   uint64 // Objects found
+	}{
+		{"zero-area box on a grid point", MBR{MinX: 4, MinY: 5, MaxX: 4, MaxY: 5}, 3},
+		{"zero-area box between points", MBR{MinX: 0.5, MinY: 0.5, MaxX: 0.5, MaxY: 0.5}, 0},
+		{"zero-area box on a square corner", MBR{MinX: 8, MinY: 8, MaxX: 8, MaxY: 8}, 2},
+		{"zero-width box along x = 3", MBR{MinX: 3, MinY: 0, MaxX: 3, MaxY: 9}, 12},
+		{"zero-height box along y = 0", MBR{MinX: 0, MinY: 0, MaxX: 9, MaxY: 0}, 10},
+		{"zero-height box between rows", MBR{MinX: 0, MinY: 0.5, MaxX: 9, MaxY: 0.5}, 0},
+	} {
+		for _, s := range []Structure{StructureKDTree, StructureQuadtree} {
+			list, err := idx.QueryRangeUsing(tc.region, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if list.Count != tc.want {
+				t.Errorf("%s with structure %d: found %d, want %d", tc.name, s, list.Count, tc.want)
+			}
+		}
+	}
+
+	// A zero-area box agrees with QueryPoint
+	box, _ := idx.QueryRange(MBR{MinX: 4, MinY: 5, MaxX: 4, MaxY: 5})
+	point, _ := idx.QueryPoint(4, 5)
+	if box.Count != point.Count {
+		t.Errorf("zero-area box found %d, QueryPoint %d", box.Count, point.Count)
+	}
+	ids := map[uint64]bool{}
+	for _, obj := range box.Objects {
+		ids[obj.ID] = true
+	}
+	if !ids[road] || !ids[square] {
+		t.Errorf("zero-area box on the road found %v, want the road and the square", ids)
+	}
+
+	// So does the page scan that answers a stale index
+	added, _ := idx.InsertPoint(0.5, 0.5)
+	list, err := idx.QueryRange(MBR{MinX: 0.5, MinY: 0.5, MaxX: 0.5, MaxY: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !list.Stale || list.Count != 1 || list.Objects[0].ID != added {
+		t.Errorf("stale zero-area query: stale %v, %d objects", list.Stale, list.Count)
+	}
+}
@@ -0,0 +1,84 @@
+package urbis
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// kolkata roughly covers the city in WGS84 longitude and latitude
+var kolkata = MBR{MinX: 88.2, MinY: 22.4, MaxX: 88.5, MaxY: 22.7}
+
+func TestValidBounds(t *testing.T) {
+	idx, err := NewIndex(&Config{ValidBounds: kolkata})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	if _, err := idx.InsertPoint(88.36, 22.57); err != nil {
+		t.Fatal(err)
+	}
+	// On the edge counts as inside
+	if _, err := idx.InsertLineString([]Point{{88.2, 22.4}, {88.5, 22.7}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, insert := range map[string]func() error{
+		"swapped point": func() error { _, err := idx.InsertPoint(22.57, 88.36); return err },
+		"line leaving": func() error {
+			_, err := idx.InsertLineString([]Point{{88.3, 22.5}, {88.3, 23.5}})
+			return err
+		},
+		"polygon with ID": func() error {
+			return idx.InsertPolygonWithID(100, []Point{{0, 0}, {1, 0}, {1, 1}, {0, 0}})
+		},
+		"multipoint": func() error { _, err := idx.InsertMultiPoint([]Point{{88.3, 22.5}, {8830, 2250}}); return err },
+		"multipolygon": func() error {
+			_, err := idx.InsertMultiPolygon([][]Point{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}})
+			return err
+		},
+		"wkt": func() error { return idx.LoadWKT("POINT (22.57 88.36)") },
+	} {
+		if err := insert(); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: err = %v, want ErrInvalid", name, err)
+		}
+	}
+	if idx.Count() != 2 {
+		t.Fatalf("count = %d after rejected inserts, want 2", idx.Count())
+	}
+
+	result, err := idx.LoadGeoJSONStringFrom(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[88.4,22.6]}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[22.6,88.4]}},
+		{"type":"Feature","geometry":{"type":"LineString","coordinates":[[88.4,22.6],[90,22.6]]}},
+		{"type":"Feature","geometry":null}]}`, CRSUnspecified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (LoadResult{Loaded: 1, Skipped: 1, OutOfBounds: 2}) {
+		t.Errorf("load result = %+v, want 1 loaded, 1 skipped and 2 out of bounds", result)
+	}
+}
+
+func TestValidBoundsConfig(t *testing.T) {
+	for _, bounds := range []MBR{
+		{MinX: 1, MinY: 0, MaxX: 0, MaxY: 1},
+		{MinX: 0, MinY: 0, MaxX: math.Inf(1), MaxY: 1},
+		{MinX: math.NaN(), MinY: 0, MaxX: 1, MaxY: 1},
+	} {
+		if _, err := NewIndex(&Config{ValidBounds: bounds}); !errors.Is(err, ErrInvalid) {
+			t.Errorf("ValidBounds %+v: err = %v, want ErrInvalid", bounds, err)
+		}
+	}
+
+	// The zero MBR leaves inserts unchecked
+	idx, err := NewIndex(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	if _, err := idx.InsertPoint(1e9, -1e9); err != nil {
+		t.Errorf("unbounded insert: %v", err)
+	}
+}
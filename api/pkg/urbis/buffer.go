@@ -0,0 +1,253 @@
+package urbis
+
+import (
+	"fmt"
+	"math"
+)
+
+// BufferQuarterSegments is how many edges approximate a quarter circle in a
+// buffer: the round caps around points and line ends, and so the round
+// joins where segments meet
+const BufferQuarterSegments = 8
+
+// Buffer returns polygons whose union covers every point within distance of
+// geometry: one around each point and each edge, and for polygons the
+// exterior ring itself. Each is a closed counter-clockwise ring. Only the
+// geometry fields of geometry are read. Distance is in index coordinates,
+// the same units Nearest reports (degrees for EPSG:4326).
+//
+// Round caps and joins are drawn with BufferQuarterSegments edges per
+// quarter circle, placed outside the true circle so nothing within distance
+// is missed. Points up to distance/cos(π/32), about 0.5% farther, may fall
+// inside too.
+func Buffer(geometry *SpatialObject, distance float64) ([][]Point, error) {
+	if geometry == nil {
+		return nil, fmt.Errorf("%w: no geometry to buffer", ErrInvalid)
+	}
+	if !(distance > 0) || math.IsInf(distance, 0) {
+		return nil, fmt.Errorf("%w: buffer distance %v is not a finite positive distance", ErrInvalid, distance)
+	}
+
+	s := shapeOf(geometry)
+	if len(s.points) == 0 && len(s.edges) == 0 {
+		return nil, fmt.Errorf("%w: geometry has no vertices", ErrInvalid)
+	}
+	if !s.finite() {
+		return nil, fmt.Errorf("%w: geometry has a non-finite coordinate", ErrInvalid)
+	}
+
+	var polygons [][]Point
+	for _, p := range s.points {
+		polygons = append(polygons, capsule(p, p, distance))
+	}
+	for _, e := range s.edges {
+		polygons = append(polygons, capsule(e[0], e[1], distance))
+	}
+	for _, ring := range s.rings {
+		closed := append([]Point(nil), ring...)
+		if closed[0] != closed[len(closed)-1] {
+			closed = append(closed, closed[0])
+		}
+		if signedArea(closed) < 0 {
+			for i, j := 0, len(closed)-1; i < j; i, j = i+1, j-1 {
+				closed[i], closed[j] = closed[j], closed[i]
+			}
+		}
+		polygons = append(polygons, closed)
+	}
+	return polygons, nil
+}
+
+// QueryBuffered queries objects within distance of geometry, such as the
+// roads within 100 units of a route. It queries the polygons of
+// Buffer(geometry, distance): objects whose exact geometry touches any of
+// them are returned, not just those whose MBR does. See Buffer for the
+// units and the approximation of round joins.
+func (idx *Index) QueryBuffered(geometry *SpatialObject, distance float64) (*ObjectList, error) {
+	polygons, err := Buffer(geometry, distance)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := idx.rebuildIfDue(false); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	stale, err := idx.requireQueryable()
+	if err != nil {
+		return nil, err
+	}
+	list, err := idx.queryPolygons(polygons)
+	if err != nil {
+		return nil, err
+	}
+	list.Stale = stale
+	return list, nil
+}
+
+// queryPolygons returns the objects whose geometry intersects any of the
+// closed rings: the candidates in their bounding box, tested exactly. The
+// caller must hold the index lock.
+func (idx *Index) queryPolygons(polygons [][]Point) (*ObjectList, error) {
+	bounds := MBR{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+	for _, ring := range polygons {
+		for _, p := range ring {
+			bounds.MinX, bounds.MaxX = min(bounds.MinX, p.X), max(bounds.MaxX, p.X)
+			bounds.MinY, bounds.MaxY = min(bounds.MinY, p.Y), max(bounds.MaxY, p.Y)
+		}
+	}
+
+	list := idx.queryRange(bounds, StructureAuto)
+	if err := list.checkPages(); err != nil {
+		return nil, err
+	}
+
+	matches := list.Objects[:0]
+	for _, obj := range list.Objects {
+		s := shapeOf(obj)
+		for _, ring := range polygons {
+			if s.intersects(ring) {
+				matches = append(matches, obj)
+				break
+			}
+		}
+	}
+	list.Objects = matches
+	list.Count = uint64(len(matches))
+	return list, nil
+}
+
+// capsule returns the closed counter-clockwise ring around segment a-b at
+// distance r: a half circle past each end joined by the two sides. With a
+// equal to b it is a circle.
+func capsule(a, b Point, r float64) []Point {
+	n := 4 * BufferQuarterSegments
+	step := 2 * math.Pi / float64(n)
+	// Vertices on the circumscribed polygon keep every edge outside the circle
+	r /= math.Cos(step / 2)
+	theta := math.Atan2(b.Y-a.Y, b.X-a.X)
+
+	ring := make([]Point, 0, n+3)
+	for _, end := range []struct {
+		center Point
+		start  float64
+	}{{b, theta - math.Pi/2}, {a, theta + math.Pi/2}} {
+		for i := 0; i <= n/2; i++ {
+			t := end.start + float64(i)*step
+			ring = append(ring, Point{X: end.center.X + r*math.Cos(t), Y: end.center.Y + r*math.Sin(t)})
+		}
+	}
+	return append(ring, ring[0])
+}
+
+// shape is a geometry broken into lone points, edges and the rings that
+// enclose area
+type shape struct {
+	points []Point
+	edges  [][2]Point
+	rings  [][]Point
+}
+
+func shapeOf(obj *SpatialObject) shape {
+	var s shape
+	s.add(obj)
+	return s
+}
+
+func (s *shape) add(obj *SpatialObject) {
+	if obj.Point != nil {
+		s.points = append(s.points, *obj.Point)
+	}
+	s.points = append(s.points, obj.MultiPoint...)
+	s.addPath(obj.Line, false)
+	for _, line := range obj.MultiLine {
+		s.addPath(line, false)
+	}
+	s.addPath(obj.Polygon, true)
+	for _, ring := range obj.MultiPolygon {
+		s.addPath(ring, true)
+	}
+	for _, g := range obj.Geometries {
+		s.add(g)
+	}
+}
+
+// addPath adds the edges of a line, or of a ring when closed is set. A
+// ring may repeat its first point at the end or not.
+func (s *shape) addPath(path []Point, closed bool) {
+	switch len(path) {
+	case 0:
+		return
+	case 1:
+		s.points = append(s.points, path[0])
+		return
+	}
+	for i := 1; i < len(path); i++ {
+		s.edges = append(s.edges, [2]Point{path[i-1], path[i]})
+	}
+	if closed {
+		if first, last := path[0], path[len(path)-1]; first != last {
+			s.edges = append(s.edges, [2]Point{last, first})
+		}
+		if len(path) >= 3 {
+			s.rings = append(s.rings, path)
+		}
+	}
+}
+
+func (s *shape) finite() bool {
+	if !pointsFinite(s.points) {
+		return false
+	}
+	for _, e := range s.edges {
+		if !pointsFinite(e[:]) {
+			return false
+		}
+	}
+	return true
+}
+
+// intersects reports whether the shape shares any point with the area
+// of a closed ring, boundary included
+func (s *shape) intersects(ring []Point) bool {
+	for _, p := range s.points {
+		if inRing(p, ring) {
+			return true
+		}
+	}
+	for _, e := range s.edges {
+		if inRing(e[0], ring) {
+			return true
+		}
+		for i := 1; i < len(ring); i++ {
+			if segmentsIntersect(e[0], e[1], ring[i-1], ring[i]) {
+				return true
+			}
+		}
+	}
+	// The ring may lie wholly inside one of the shape's own rings
+	for _, area := range s.rings {
+		if inRing(ring[0], area) {
+			return true
+		}
+	}
+	return false
+}
+
+// inRing reports whether p lies inside ring or on its boundary. The ring
+// may repeat its first point at the end or not.
+func inRing(p Point, ring []Point) bool {
+	inside := false
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+		if orientation(a, b, p) == 0 && onSegment(a, b, p) {
+			return true
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,78 @@
+package urbis
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestBuffer(t *testing.T) {
+	if _, err := Buffer(&SpatialObject{Point: &Point{X: 1, Y: 1}}, 0); !errors.Is(err, ErrInvalid) {
+		t.Errorf("zero distance: err = %v, want ErrInvalid", err)
+	}
+	if _, err := Buffer(&SpatialObject{}, 1); !errors.Is(err, ErrInvalid) {
+		t.Errorf("empty geometry: err = %v, want ErrInvalid", err)
+	}
+
+	polygons, err := Buffer(&SpatialObject{Point: &Point{X: 3, Y: 4}}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(polygons) != 1 {
+		t.Fatalf("point buffer has %d polygons", len(polygons))
+	}
+	circle := polygons[0]
+	if circle[0] != circle[len(circle)-1] || signedArea(circle[:len(circle)-1]) <= 0 {
+		t.Errorf("buffer ring is not closed and counter-clockwise: %v", circle)
+	}
+	// Every edge stays outside the circle, within the documented slack
+	for i := 1; i < len(circle); i++ {
+		mid := Point{X: (circle[i-1].X + circle[i].X) / 2, Y: (circle[i-1].Y + circle[i].Y) / 2}
+		if r := math.Hypot(mid.X-3, mid.Y-4); r < 2-1e-9 {
+			t.Fatalf("edge %d passes %v from the center", i, r)
+		}
+		if r := math.Hypot(circle[i].X-3, circle[i].Y-4); r > 2/math.Cos(math.Pi/32)+1e-9 {
+			t.Fatalf("vertex %d is %v from the center", i, r)
+		}
+	}
+}
+
+func TestQueryBuffered(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	near, _ := idx.InsertPoint(5, 0.9)
+	idx.InsertPoint(5, 1.1)
+	// Inside the route's bounding box but far from the route itself
+	idx.InsertPoint(8, 8)
+	crossing, _ := idx.InsertLineString([]Point{{X: 2, Y: -5}, {X: 2, Y: -3}, {X: 3, Y: 5}})
+	// The end cap reaches past the last vertex
+	end, _ := idx.InsertPoint(10.5, 10.5)
+	// A polygon enclosing the whole route; only its interior is near it
+	around, _ := idx.InsertPolygon([]Point{{X: -20, Y: -20}, {X: 30, Y: -20}, {X: 30, Y: 30}, {X: -20, Y: 30}, {X: -20, Y: -20}})
+
+	route := &SpatialObject{Line: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}}
+	if _, err := idx.QueryBuffered(route, 1); !errors.Is(err, ErrNotBuilt) {
+		t.Errorf("unbuilt index: err = %v, want ErrNotBuilt", err)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := idx.QueryBuffered(route, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []uint64
+	for _, obj := range result.Objects {
+		ids = append(ids, obj.ID)
+	}
+	slices.Sort(ids)
+	if want := []uint64{near, crossing, end, around}; !slices.Equal(ids, want) || result.Count != 4 {
+		t.Errorf("objects within 1 of the route = %v, want %v", ids, want)
+	}
+}
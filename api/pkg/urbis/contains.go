@@ -0,0 +1,62 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+
+import "fmt"
+
+// QueryContainingPolygon queries polygons that contain the given polygon,
+// such as the administrative regions a parcel lies in. Candidates whose
+// MBR holds the polygon's are tested against their exact geometry, holes
+// included. A stored polygon contains the input when no part of the input
+// lies outside it or in one of its holes: the input may touch or share
+// edges with its boundary, but not cross it. A multipolygon contains it
+// when one of its parts does. The input needs at least three distinct
+// vertices, must not self-intersect, and may be closed or not, in either
+// orientation.
+func (idx *Index) QueryContainingPolygon(polygon []Point) (*ObjectList, error) {
+	if !pointsFinite(polygon) {
+		return nil, fmt.Errorf("%w: polygon has a non-finite coordinate", ErrInvalid)
+	}
+	ring := distinctVertices(polygon, 0)
+	if len(ring) < 3 {
+		return nil, fmt.Errorf("%w: polygon has %d distinct vertices, need at least 3", ErrInvalid, len(ring))
+	}
+	if a, b, c, d, ok := findSelfIntersection(ring); ok {
+		return nil, fmt.Errorf("%w: polygon self-intersects: edge %s-%s meets edge %s-%s", ErrInvalid,
+			formatPoint(a), formatPoint(b), formatPoint(c), formatPoint(d))
+	}
+	if signedArea(ring) == 0 {
+		return nil, fmt.Errorf("%w: polygon has zero area", ErrInvalid)
+	}
+
+	if err := idx.rebuildIfDue(false); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	stale, err := idx.requireQueryable()
+	if err != nil {
+		return nil, err
+	}
+
+	cpoints := make([]C.Point, len(ring))
+	for i, p := range ring {
+		cpoints[i] = C.Point{x: C.double(p.X), y: C.double(p.Y)}
+	}
+	result := C.urbis_query_containing_polygon(idx.ptr, &cpoints[0], C.size_t(len(ring)))
+	if result == nil {
+		return &ObjectList{Objects: []*SpatialObject{}, Count: 0, Stale: stale}, nil
+	}
+	defer C.urbis_object_list_free(result)
+
+	list := convertObjectList(result)
+	if err := list.checkPages(); err != nil {
+		return nil, err
+	}
+	list.Stale = stale
+	return list, nil
+}
@@ -0,0 +1,72 @@
+package urbis
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestQueryContainingPolygon(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	// A district with a lake, and a multipolygon of two islands
+	if err := idx.LoadGeoJSONString(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Polygon","coordinates":[
+			[[0,0],[10,0],[10,10],[0,10],[0,0]],[[4,4],[6,4],[6,6],[4,6],[4,4]]]}},
+		{"type":"Feature","geometry":{"type":"MultiPolygon","coordinates":[
+			[[[20,0],[24,0],[24,4],[20,4],[20,0]]],[[[30,0],[34,0],[34,4],[30,4],[30,0]]]]}}]}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	district := containingIDs(t, idx, []Point{{1, 1}, {2, 1}, {2, 2}})[0]
+	islands := containingIDs(t, idx, []Point{{31, 1}, {32, 1}, {32, 2}})[0]
+
+	for _, tc := range []struct {
+		name    string
+		polygon []Point
+		want    []uint64
+	}{
+		{"inside", []Point{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}, []uint64{district}},
+		{"clockwise and open", []Point{{1, 1}, {1, 3}, {3, 3}, {3, 1}}, []uint64{district}},
+		{"sharing the district's edge", []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}}, []uint64{district}},
+		{"touching the lake", []Point{{6, 4}, {8, 4}, {8, 6}, {6, 6}}, []uint64{district}},
+		{"around the lake", []Point{{3, 3}, {7, 3}, {7, 7}, {3, 7}}, nil},
+		{"on the lake", []Point{{4.5, 4.5}, {5.5, 4.5}, {5.5, 5.5}}, nil},
+		{"across the border", []Point{{8, 8}, {12, 8}, {12, 9}, {8, 9}}, nil},
+		{"on one island", []Point{{21, 1}, {23, 1}, {23, 3}}, []uint64{islands}},
+		{"spanning both islands", []Point{{21, 1}, {33, 1}, {33, 3}, {21, 3}}, nil},
+	} {
+		if got := containingIDs(t, idx, tc.polygon); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	for _, bad := range [][]Point{
+		{{1, 1}, {2, 2}},
+		{{1, 1}, {2, 2}, {3, 3}},
+		{{1, 1}, {3, 3}, {3, 1}, {1, 3}},
+	} {
+		if _, err := idx.QueryContainingPolygon(bad); !errors.Is(err, ErrInvalid) {
+			t.Errorf("QueryContainingPolygon(%v): err = %v, want ErrInvalid", bad, err)
+		}
+	}
+}
+
+func containingIDs(t *testing.T, idx *Index, polygon []Point) []uint64 {
+	t.Helper()
+	list, err := idx.QueryContainingPolygon(polygon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []uint64
+	for _, obj := range list.Objects {
+		ids = append(ids, obj.ID)
+	}
+	return ids
+}
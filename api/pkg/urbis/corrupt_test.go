@@ -0,0 +1,66 @@
+package urbis
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestQueryRangeBestEffortSkipsCorruptPage(t *testing.T) {
+	idx, err := NewIndex(&Config{PageCapacity: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 16; i++ {
+		idx.InsertPoint(float64(i), float64(i))
+	}
+	idx.InsertPoint(12345.25, -6789.5)
+	data, err := idx.SaveBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Damage the stored centroid of the marker point, which the page
+	// checksum covers. For a point the record holds the same coordinates
+	// three times, centroid then both MBR corners. The header of its page
+	// (extent and centroid) repeats them too, so take the last match.
+	corner := make([]byte, 16)
+	binary.LittleEndian.PutUint64(corner, math.Float64bits(12345.25))
+	binary.LittleEndian.PutUint64(corner[8:], math.Float64bits(-6789.5))
+	at := bytes.LastIndex(data, bytes.Repeat(corner, 3))
+	if at < 0 {
+		t.Fatal("marker centroid not found in saved index")
+	}
+	data[at] ^= 1
+
+	damaged, err := LoadBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer damaged.Close()
+
+	everything := MBR{MinX: -1, MinY: -7000, MaxX: 13000, MaxY: 20}
+	if _, err := damaged.QueryRange(everything); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("strict query: err = %v, want ErrCorrupt", err)
+	}
+
+	partial, err := damaged.QueryRangeBestEffort(everything, StructureAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(partial.FailedPages) != 1 {
+		t.Fatalf("FailedPages = %v, want one page", partial.FailedPages)
+	}
+	if partial.Count == 0 || partial.Count >= 17 {
+		t.Errorf("best-effort count = %d, want the objects of the intact pages", partial.Count)
+	}
+	for _, obj := range partial.Objects {
+		if obj.Centroid.X > 1000 {
+			t.Errorf("object %d from the corrupt page was returned", obj.ID)
+		}
+	}
+}
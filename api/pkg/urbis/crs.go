@@ -0,0 +1,292 @@
+package urbis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// EPSG codes understood by the reprojection helpers
+const (
+	CRSUnspecified = 0    // No CRS recorded; coordinates are used as given
+	CRSWGS84       = 4326 // Longitude/latitude in degrees
+	CRSWebMercator = 3857 // Spherical Mercator in meters
+)
+
+const (
+	earthRadius = 6378137.0
+	// maxMercatorLat is where Web Mercator y reaches the extent of x; the
+	// poles themselves project to infinity
+	maxMercatorLat = 85.051128779806604
+)
+
+// IsSupportedCRS reports whether code is a CRS the index can be tagged with
+func IsSupportedCRS(code int) bool {
+	switch code {
+	case CRSUnspecified, CRSWGS84, CRSWebMercator:
+		return true
+	}
+	return false
+}
+
+// Transform reprojects a point between EPSG:4326 and EPSG:3857. Latitudes
+// beyond the Web Mercator limit are clamped to it.
+func Transform(p Point, from, to int) (Point, error) {
+	switch {
+	case from == to:
+		return p, nil
+	case from == CRSWGS84 && to == CRSWebMercator:
+		if p.Y < -90 || p.Y > 90 {
+			return Point{}, fmt.Errorf("%w: latitude %v out of range", ErrInvalid, p.Y)
+		}
+		lat := math.Max(-maxMercatorLat, math.Min(maxMercatorLat, p.Y))
+		return Point{
+			X: earthRadius * p.X * math.Pi / 180,
+			Y: earthRadius * math.Log(math.Tan(math.Pi/4+lat*math.Pi/360)),
+		}, nil
+	case from == CRSWebMercator && to == CRSWGS84:
+		return Point{
+			X: p.X / earthRadius * 180 / math.Pi,
+			Y: (2*math.Atan(math.Exp(p.Y/earthRadius)) - math.Pi/2) * 180 / math.Pi,
+		}, nil
+	}
+	return Point{}, fmt.Errorf("%w: no transform from EPSG:%d to EPSG:%d", ErrInvalid, from, to)
+}
+
+// TransformMBR reprojects a bounding box. Both supported projections are
+// monotonic per axis, so transforming the corners is exact.
+func TransformMBR(m MBR, from, to int) (MBR, error) {
+	lo, err := Transform(Point{X: m.MinX, Y: m.MinY}, from, to)
+	if err != nil {
+		return MBR{}, err
+	}
+	hi, err := Transform(Point{X: m.MaxX, Y: m.MaxY}, from, to)
+	if err != nil {
+		return MBR{}, err
+	}
+	return MBR{MinX: lo.X, MinY: lo.Y, MaxX: hi.X, MaxY: hi.Y}, nil
+}
+
+// haversine returns the great-circle distance in meters between two
+// longitude/latitude points
+func haversine(a, b Point) float64 {
+	lat1, lat2 := a.Y*math.Pi/180, b.Y*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.X - a.X) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// TransformObject returns a copy of obj with its centroid, bounding box and
+// geometry, including the members of a collection, reprojected from one
+// CRS to another. Properties, Z and M values are shared with obj.
+func TransformObject(obj *SpatialObject, from, to int) (*SpatialObject, error) {
+	if from == to {
+		return obj, nil
+	}
+	out := *obj
+	var err error
+	transform := func(points []Point) []Point {
+		if points == nil || err != nil {
+			return points
+		}
+		moved := make([]Point, len(points))
+		for i, p := range points {
+			if moved[i], err = Transform(p, from, to); err != nil {
+				return nil
+			}
+		}
+		return moved
+	}
+
+	if out.MBR, err = TransformMBR(obj.MBR, from, to); err != nil {
+		return nil, err
+	}
+	if out.Centroid, err = Transform(obj.Centroid, from, to); err != nil {
+		return nil, err
+	}
+	if obj.Point != nil {
+		if p := transform([]Point{*obj.Point}); p != nil {
+			out.Point = &p[0]
+		}
+	}
+	out.Line = transform(obj.Line)
+	out.Polygon = transform(obj.Polygon)
+	out.MultiPoint = transform(obj.MultiPoint)
+	if obj.MultiLine != nil {
+		out.MultiLine = make([][]Point, len(obj.MultiLine))
+		for i, line := range obj.MultiLine {
+			out.MultiLine[i] = transform(line)
+		}
+	}
+	if obj.MultiPolygon != nil {
+		out.MultiPolygon = make([][]Point, len(obj.MultiPolygon))
+		for i, ring := range obj.MultiPolygon {
+			out.MultiPolygon[i] = transform(ring)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.Geometries != nil {
+		out.Geometries = make([]*SpatialObject, len(obj.Geometries))
+		for i, member := range obj.Geometries {
+			if out.Geometries[i], err = TransformObject(member, from, to); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &out, nil
+}
+
+// ExtentMeters returns the width and height of m in meters, by haversine,
+// when crs is geographic (CRSWGS84). The width is taken along the parallel
+// in m nearest the equator, where it is widest, and the height along a
+// meridian. A box crossing the antimeridian is measured east from MinX to
+// MaxX. It returns false for other CRSs and for an empty box.
+func (m MBR) ExtentMeters(crs int) (width, height float64, ok bool) {
+	if crs != CRSWGS84 || m.MinY > m.MaxY {
+		return 0, 0, false
+	}
+	span := m.MaxX - m.MinX
+	if m.CrossesAntimeridian() {
+		span += WorldWidth(crs)
+	}
+	// Halves stay under 180 degrees, so the great circle does not cut
+	// across the other side of the world
+	lat := math.Max(m.MinY, math.Min(m.MaxY, 0))
+	width = 2 * haversine(Point{X: 0, Y: lat}, Point{X: span / 2, Y: lat})
+	height = haversine(Point{X: 0, Y: m.MinY}, Point{X: 0, Y: m.MaxY})
+	return width, height, true
+}
+
+// CRS returns the EPSG code of the index coordinates, as set by Config.CRS
+func (idx *Index) CRS() int {
+	return idx.crs
+}
+
+// LoadGeoJSONFrom loads a GeoJSON file whose coordinates are in srcCRS,
+// reprojecting them to the index CRS first. CRSUnspecified means the file
+// is already in the index CRS. It returns how many objects were loaded and
+// how many features were skipped for a null or empty geometry.
+func (idx *Index) LoadGeoJSONFrom(path string, srcCRS int) (LoadResult, error) {
+	if srcCRS == CRSUnspecified || srcCRS == idx.crs {
+		return idx.loadGeoJSON(path)
+	}
+
+	data, err := readGeoJSONFile(path)
+	if err != nil {
+		return LoadResult{}, err
+	}
+	return idx.loadReprojected(data, srcCRS)
+}
+
+// LoadGeoJSONStringFrom is LoadGeoJSONFrom for a GeoJSON string
+func (idx *Index) LoadGeoJSONStringFrom(geojson string, srcCRS int) (LoadResult, error) {
+	if srcCRS == CRSUnspecified || srcCRS == idx.crs {
+		return idx.loadGeoJSONString(geojson)
+	}
+	return idx.loadReprojected([]byte(geojson), srcCRS)
+}
+
+func (idx *Index) loadReprojected(data []byte, srcCRS int) (LoadResult, error) {
+	if idx.crs == CRSUnspecified {
+		return LoadResult{}, fmt.Errorf("%w: index has no CRS to reproject EPSG:%d into", ErrInvalid, srcCRS)
+	}
+
+	out, err := ReprojectGeoJSON(data, srcCRS, idx.crs)
+	if err != nil {
+		return LoadResult{}, err
+	}
+	return idx.loadGeoJSONString(string(out))
+}
+
+// ReprojectGeoJSON rewrites every "coordinates" member of a GeoJSON
+// document from one CRS to another. Properties are passed through
+// untouched.
+func ReprojectGeoJSON(data []byte, from, to int) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+	if err := reprojectMember(doc, from, to); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func reprojectMember(v any, from, to int) error {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, member := range v {
+			var err error
+			switch key {
+			case "properties":
+				continue
+			case "coordinates":
+				err = reprojectCoords(member, from, to)
+			default:
+				err = reprojectMember(member, from, to)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, member := range v {
+			if err := reprojectMember(member, from, to); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// reprojectCoords transforms nested coordinate arrays in place. A position
+// is an array of numbers; any values beyond x and y are kept as is.
+func reprojectCoords(v any, from, to int) error {
+	arr, ok := v.([]any)
+	if !ok {
+		return fmt.Errorf("%w: coordinates must be an array", ErrParse)
+	}
+	if len(arr) == 0 {
+		return nil
+	}
+	if _, isPosition := arr[0].(json.Number); !isPosition {
+		for _, child := range arr {
+			if err := reprojectCoords(child, from, to); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(arr) < 2 {
+		return fmt.Errorf("%w: position needs at least two numbers", ErrParse)
+	}
+	x, errX := numberValue(arr[0])
+	y, errY := numberValue(arr[1])
+	if errX != nil || errY != nil {
+		return fmt.Errorf("%w: invalid position %v", ErrParse, arr)
+	}
+
+	p, err := Transform(Point{X: x, Y: y}, from, to)
+	if err != nil {
+		return err
+	}
+	arr[0], arr[1] = p.X, p.Y
+	return nil
+}
+
+func numberValue(v any) (float64, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, ErrParse
+	}
+	return n.Float64()
+}
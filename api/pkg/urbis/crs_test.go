@@ -0,0 +1,177 @@
+package urbis
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestTransformRoundTrip(t *testing.T) {
+	p := Point{X: 180, Y: 45}
+
+	m, err := Transform(p, CRSWGS84, CRSWebMercator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(m.X-20037508.342789) > 1e-3 || math.Abs(m.Y-5621521.486192) > 1e-3 {
+		t.Errorf("Transform to 3857 = %+v", m)
+	}
+
+	back, err := Transform(m, CRSWebMercator, CRSWGS84)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(back.X-p.X) > 1e-9 || math.Abs(back.Y-p.Y) > 1e-9 {
+		t.Errorf("round trip = %+v, want %+v", back, p)
+	}
+
+	if _, err := Transform(Point{X: 0, Y: 91}, CRSWGS84, CRSWebMercator); !errors.Is(err, ErrInvalid) {
+		t.Errorf("latitude 91: got %v, want ErrInvalid", err)
+	}
+	if _, err := Transform(p, CRSWGS84, 27700); !errors.Is(err, ErrInvalid) {
+		t.Errorf("unsupported CRS: got %v, want ErrInvalid", err)
+	}
+}
+
+func TestLoadGeoJSONReprojects(t *testing.T) {
+	config := DefaultConfig()
+	config.CRS = CRSWebMercator
+	idx, err := NewIndex(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	geojson := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[180,0]},"properties":{"lon":180}},
+		{"type":"Feature","geometry":{"type":"LineString","coordinates":[[0,0],[-180,0]]},"properties":{}}]}`
+	if _, err := idx.LoadGeoJSONStringFrom(geojson, CRSWGS84); err != nil {
+		t.Fatal(err)
+	}
+
+	bounds := idx.Bounds()
+	edge := math.Pi * earthRadius
+	if math.Abs(bounds.MaxX-edge) > 1e-6 || math.Abs(bounds.MinX+edge) > 1e-6 {
+		t.Errorf("bounds = %+v, want x in [%v, %v]", bounds, -edge, edge)
+	}
+
+	plain, _ := NewIndex(nil)
+	defer plain.Close()
+	if _, err := plain.LoadGeoJSONStringFrom(geojson, CRSWGS84); !errors.Is(err, ErrInvalid) {
+		t.Errorf("reprojecting into an index without CRS: got %v, want ErrInvalid", err)
+	}
+
+	config.CRS = 27700
+	if _, err := NewIndex(&config); err != ErrInvalid {
+		t.Errorf("NewIndex with unsupported CRS: got %v, want ErrInvalid", err)
+	}
+}
+
+func TestLoadEWKT(t *testing.T) {
+	idx, err := NewIndex(&Config{CRS: CRSWGS84})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	srid, err := idx.LoadEWKT("SRID=4326;POINT(13.4 52.5)")
+	if err != nil || srid != CRSWGS84 {
+		t.Fatalf("LoadEWKT = %d, %v; want 4326, nil", srid, err)
+	}
+	if srid, err := idx.LoadEWKT("POINT(1 2)"); err != nil || srid != 0 {
+		t.Errorf("plain WKT: %d, %v; want 0, nil", srid, err)
+	}
+	if _, err := idx.LoadEWKT("SRID=3857;POINT(1 2)"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("mismatched SRID: err = %v, want ErrInvalid", err)
+	}
+	if err := idx.LoadWKT("SRID=;POINT(1 2)"); !errors.Is(err, ErrParse) {
+		t.Errorf("malformed prefix: err = %v, want ErrParse", err)
+	}
+	if n := idx.Count(); n != 2 {
+		t.Errorf("count = %d, want 2", n)
+	}
+
+	plain, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plain.Close()
+	if err := plain.LoadWKT("SRID=27700;POINT(530000 180000)"); err != nil {
+		t.Errorf("index without a CRS rejected EWKT: %v", err)
+	}
+}
+
+func TestExtentMeters(t *testing.T) {
+	// One degree of arc on the sphere
+	degree := earthRadius * math.Pi / 180
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-6*want }
+
+	width, height, ok := MBR{MinX: 10, MinY: -0.5, MaxX: 11, MaxY: 0.5}.ExtentMeters(CRSWGS84)
+	if !ok || !near(width, degree) || !near(height, degree) {
+		t.Errorf("equator box = %v x %v (%v), want %v square", width, height, ok, degree)
+	}
+	width, _, _ = MBR{MinX: 170, MinY: 60, MaxX: -170, MaxY: 70}.ExtentMeters(CRSWGS84)
+	if want := 20 * degree / 2; math.Abs(width-want) > 0.01*want {
+		t.Errorf("width across the antimeridian at 60N = %v, want about %v", width, want)
+	}
+	if _, _, ok := (MBR{MaxX: 1, MaxY: 1}).ExtentMeters(CRSWebMercator); ok {
+		t.Error("ExtentMeters measured a projected box")
+	}
+
+	idx, err := NewIndex(&Config{CRS: CRSWGS84})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	if idx.GetStats().HasMeters {
+		t.Error("empty index has an extent in meters")
+	}
+	idx.InsertPoint(0, 0)
+	idx.InsertPoint(1, 1)
+	if stats := idx.GetStats(); !stats.HasMeters || !near(stats.HeightMeters, degree) {
+		t.Errorf("stats extent = %v x %v (%v)", stats.WidthMeters, stats.HeightMeters, stats.HasMeters)
+	}
+}
+
+func TestTransformObject(t *testing.T) {
+	square := []Point{{10, 50}, {11, 50}, {11, 51}, {10, 51}, {10, 50}}
+	obj := &SpatialObject{
+		ID:       7,
+		Type:     GeomGeometryCollection,
+		Centroid: Point{X: 10.5, Y: 50.5},
+		MBR:      MBR{MinX: 10, MinY: 50, MaxX: 11, MaxY: 51},
+		Geometries: []*SpatialObject{
+			{Type: GeomPoint, Centroid: Point{X: 10, Y: 50}, MBR: MBR{MinX: 10, MinY: 50, MaxX: 10, MaxY: 50}, Point: &Point{X: 10, Y: 50}},
+			{Type: GeomPolygon, Centroid: Point{X: 10.5, Y: 50.5}, MBR: MBR{MinX: 10, MinY: 50, MaxX: 11, MaxY: 51}, Polygon: square},
+		},
+	}
+
+	moved, err := TransformObject(obj, CRSWGS84, CRSWebMercator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := Transform(Point{X: 10, Y: 50}, CRSWGS84, CRSWebMercator)
+	if p := moved.Geometries[0].Point; p == nil || *p != want {
+		t.Errorf("member point = %v, want %v", p, want)
+	}
+	if moved.MBR.MinX != want.X || moved.MBR.MinY != want.Y {
+		t.Errorf("MBR = %+v, want a corner at %v", moved.MBR, want)
+	}
+	if obj.Geometries[1].Polygon[0] != (Point{X: 10, Y: 50}) {
+		t.Error("TransformObject changed the original object")
+	}
+
+	back, err := TransformObject(moved, CRSWebMercator, CRSWGS84)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range back.Geometries[1].Polygon {
+		if math.Abs(p.X-square[i].X) > 1e-9 || math.Abs(p.Y-square[i].Y) > 1e-9 {
+			t.Errorf("round trip vertex %d = %v, want %v", i, p, square[i])
+		}
+	}
+
+	if _, err := TransformObject(obj, CRSWGS84, 27700); !errors.Is(err, ErrInvalid) {
+		t.Errorf("unsupported CRS: got %v, want ErrInvalid", err)
+	}
+}
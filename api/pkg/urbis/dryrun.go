@@ -0,0 +1,110 @@
+package urbis
+
+/*
+#include <stdlib.h>
+#include "urbis.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// ValidateGeoJSON parses a GeoJSON file, gzip-compressed or not, the way
+// LoadGeoJSON would, without loading it into any index. It returns how many
+// objects a load would insert and one message per feature that would be
+// left out because it fails to parse, such as "feature 3: unsupported
+// geometry type". Features with a null or empty geometry are skipped
+// rather than reported. err is set only when the file as a whole cannot be
+// read or parsed.
+func ValidateGeoJSON(path string) (count uint64, errs []string, err error) {
+	data, err := readGeoJSONFile(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	result, errs, err := validateGeoJSON(string(data))
+	return result.Loaded, errs, err
+}
+
+// ValidateGeoJSONString is ValidateGeoJSON for a GeoJSON string
+func ValidateGeoJSONString(json string) (count uint64, errs []string, err error) {
+	result, errs, err := validateGeoJSON(json)
+	return result.Loaded, errs, err
+}
+
+// DryRunGeoJSONFrom checks a GeoJSON file whose coordinates are in srcCRS
+// as LoadGeoJSONFrom would load it into the index: it reprojects the
+// document, checks feature properties against the index schema and parses
+// every geometry, but inserts nothing, so it also works on a read-only
+// index. Loaded is the number of objects a real load would add. Schema
+// violations are reported in errs alongside parse failures, though a real
+// load fails as a whole on the first of them.
+func (idx *Index) DryRunGeoJSONFrom(path string, srcCRS int) (LoadResult, []string, error) {
+	data, err := readGeoJSONFile(path)
+	if err != nil {
+		return LoadResult{}, nil, err
+	}
+	return idx.DryRunGeoJSONStringFrom(string(data), srcCRS)
+}
+
+// DryRunGeoJSONStringFrom is DryRunGeoJSONFrom for a GeoJSON string
+func (idx *Index) DryRunGeoJSONStringFrom(geojson string, srcCRS int) (LoadResult, []string, error) {
+	if srcCRS != CRSUnspecified && srcCRS != idx.crs {
+		if idx.crs == CRSUnspecified {
+			return LoadResult{}, nil, fmt.Errorf("%w: index has no CRS to reproject EPSG:%d into", ErrInvalid, srcCRS)
+		}
+		out, err := ReprojectGeoJSON([]byte(geojson), srcCRS, idx.crs)
+		if err != nil {
+			return LoadResult{}, nil, err
+		}
+		geojson = string(out)
+	}
+
+	idx.mu.RLock()
+	schemaErr := idx.checkGeoJSON([]byte(geojson))
+	idx.mu.RUnlock()
+	if errors.Is(schemaErr, ErrParse) {
+		return LoadResult{}, nil, schemaErr
+	}
+
+	result, errs, err := validateGeoJSON(geojson)
+	if schemaErr != nil {
+		errs = append(errs, schemaErr.Error())
+	}
+	return result, errs, err
+}
+
+func validateGeoJSON(json string) (LoadResult, []string, error) {
+	cjson := C.CString(json)
+	defer C.free(unsafe.Pointer(cjson))
+
+	var check C.UrbisGeoJSONCheck
+	if err := toError(C.urbis_check_geojson_string(cjson, &check)); err != nil {
+		return LoadResult{}, nil, err
+	}
+	defer C.urbis_geojson_check_free(&check)
+
+	var errs []string
+	if check.error_count > 0 {
+		for _, fe := range unsafe.Slice(check.errors, check.error_count) {
+			errs = append(errs, fmt.Sprintf("feature %d: %s", uint64(fe.index), parseErrorText(fe.code)))
+		}
+	}
+	return LoadResult{Loaded: uint64(check.valid), Skipped: uint64(check.skipped)}, errs, nil
+}
+
+// parseErrorText describes why the parser rejected a feature
+func parseErrorText(code C.int) string {
+	switch code {
+	case C.PARSE_ERR_INVALID_GEOM:
+		return "missing or invalid geometry"
+	case C.PARSE_ERR_UNSUPPORTED:
+		return "unsupported geometry type"
+	case C.PARSE_ERR_SYNTAX:
+		return "malformed geometry"
+	case C.PARSE_ERR_ALLOC:
+		return "out of memory"
+	}
+	return fmt.Sprintf("parse error %d", int(code))
+}
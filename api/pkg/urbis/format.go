@@ -0,0 +1,28 @@
+package urbis
+
+/*
+#include <stdlib.h>
+#include "urbis.h"
+*/
+import "C"
+import "unsafe"
+
+// FormatVersion returns the version of the file format Save writes. Load
+// reads files in this version and every earlier one.
+func FormatVersion() uint32 {
+	return uint32(C.urbis_format_version())
+}
+
+// FileFormatVersion reads the format version of a saved index file without
+// loading it, to check a file before a rolling upgrade. It fails with ErrIO
+// if the file cannot be read or is not a saved index.
+func FileFormatVersion(path string) (uint32, error) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+
+	var version C.uint32_t
+	if err := toError(C.urbis_file_format_version(cpath, &version)); err != nil {
+		return 0, err
+	}
+	return uint32(version), nil
+}
@@ -0,0 +1,81 @@
+package urbis
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setFileVersion overwrites the format version stored after the magic
+// number of a saved index
+func setFileVersion(t *testing.T, path string, version uint32) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteAt(binary.LittleEndian.AppendUint32(nil, version), 4); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadFormatVersion(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.InsertPoint(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "index.dat")
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	idx.Close()
+
+	if version, err := FileFormatVersion(path); err != nil || version != FormatVersion() {
+		t.Fatalf("FileFormatVersion = %d, %v, want %d", version, err, FormatVersion())
+	}
+
+	// A newer format is refused, and not as an I/O error
+	setFileVersion(t, path, FormatVersion()+1)
+	if _, err := Load(path); !errors.Is(err, ErrVersionMismatch) || errors.Is(err, ErrIO) {
+		t.Errorf("newer format: err = %v, want ErrVersionMismatch", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadReader(bytes.NewReader(data)); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("LoadReader of a newer format: err = %v, want ErrVersionMismatch", err)
+	}
+
+	// An older one loads and saves back as the current format
+	setFileVersion(t, path, 0)
+	idx, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	if idx.Count() != 1 {
+		t.Errorf("count = %d after migrating, want 1", idx.Count())
+	}
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if version, err := FileFormatVersion(path); err != nil || version != FormatVersion() {
+		t.Errorf("FileFormatVersion after resave = %d, %v, want %d", version, err, FormatVersion())
+	}
+
+	missing := filepath.Join(t.TempDir(), "none.dat")
+	if _, err := Load(missing); !errors.Is(err, ErrIO) {
+		t.Errorf("missing file: err = %v, want ErrIO", err)
+	}
+	if _, err := FileFormatVersion(missing); !errors.Is(err, ErrIO) {
+		t.Errorf("FileFormatVersion of a missing file: err = %v, want ErrIO", err)
+	}
+}
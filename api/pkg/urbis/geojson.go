@@ -0,0 +1,77 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"unsafe"
+)
+
+// ExportGeoJSON returns an object's geometry as a GeoJSON geometry object,
+// holes included
+func (idx *Index) ExportGeoJSON(objectID uint64) ([]byte, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return idx.exportGeoJSON(objectID)
+}
+
+// exportGeoJSON is ExportGeoJSON for callers already holding the lock
+func (idx *Index) exportGeoJSON(objectID uint64) ([]byte, error) {
+	size := C.urbis_export_geojson(idx.ptr, C.uint64_t(objectID), nil, 0)
+	if size < 0 {
+		return nil, toError(size)
+	}
+
+	// One more byte for the terminating NUL the C side always writes
+	buf := make([]byte, int(size)+1)
+	C.urbis_export_geojson(idx.ptr, C.uint64_t(objectID), (*C.char)(unsafe.Pointer(&buf[0])), C.size_t(len(buf)))
+	return buf[:size], nil
+}
+
+type geoJSONFeature struct {
+	Type       string          `json:"type"`
+	ID         uint64          `json:"id"`
+	Geometry   json.RawMessage `json:"geometry"`
+	Properties json.RawMessage `json:"properties"`
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// FeatureCollection renders query results as a GeoJSON FeatureCollection,
+// in the order given. Each feature carries the object's ID, its geometry
+// as stored in the index and its properties; properties that are not a
+// JSON object become null. Objects removed since the query ran are left out.
+func (idx *Index) FeatureCollection(objs []*SpatialObject) ([]byte, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if idx.ptr == nil {
+		return nil, ErrNull
+	}
+
+	fc := geoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]geoJSONFeature, 0, len(objs))}
+	for _, obj := range objs {
+		geometry, err := idx.exportGeoJSON(obj.ID)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		feature := geoJSONFeature{Type: "Feature", ID: obj.ID, Geometry: geometry}
+		if props := bytes.TrimSpace(obj.Properties); len(props) > 0 && props[0] == '{' && json.Valid(props) {
+			feature.Properties = props
+		}
+		fc.Features = append(fc.Features, feature)
+	}
+	return json.Marshal(fc)
+}
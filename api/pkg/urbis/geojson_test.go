@@ -0,0 +1,134 @@
+package urbis
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFeatureCollection(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	polygon := `{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]],[[2,2],[4,2],[4,4],[2,2]]]}`
+	err = idx.LoadGeoJSONString(`{"type":"FeatureCollection","features":[` +
+		`{"type":"Feature","geometry":` + polygon + `,"properties":{"name":"park"}},` +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[0.1,-33.25]},"properties":{}}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	geometry, err := idx.ExportGeoJSON(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(geometry) != polygon {
+		t.Errorf("ExportGeoJSON = %s, want %s", geometry, polygon)
+	}
+
+	result, err := idx.QueryRange(MBR{MinX: -1, MinY: -40, MaxX: 11, MaxY: 11})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A removed object is left out of the collection
+	gone := &SpatialObject{ID: 99}
+	data, err := idx.FeatureCollection(append(result.Objects, gone))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			ID         uint64          `json:"id"`
+			Geometry   json.RawMessage `json:"geometry"`
+			Properties map[string]any  `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &fc); err != nil {
+		t.Fatalf("%v in %s", err, data)
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 2 {
+		t.Fatalf("collection = %s", data)
+	}
+	for _, f := range fc.Features {
+		if f.ID == 1 && (string(f.Geometry) != polygon || f.Properties["name"] != "park") {
+			t.Errorf("feature 1 = %s %v", f.Geometry, f.Properties)
+		}
+	}
+}
+
+func TestLoadGeoJSONSkipsEmptyGeometries(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	result, err := idx.LoadGeoJSONStringFrom(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{}},
+		{"type":"Feature","geometry":null,"properties":{"name":"unplaced"}},
+		{"type":"Feature","geometry":{"type":"Polygon","coordinates":[]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"LineString","coordinates":[[0,0],[1,1]]},"properties":{}}
+	]}`, CRSUnspecified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (LoadResult{Loaded: 2, Skipped: 2}) || idx.Count() != 2 {
+		t.Errorf("load = %+v with %d objects, want 2 loaded and 2 skipped", result, idx.Count())
+	}
+
+	stream := `{"type":"Feature","geometry":null,"properties":{}}` + "\n" +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[5,5]},"properties":{}}` + "\n"
+	if result, err := idx.LoadGeoJSONReader(strings.NewReader(stream)); err != nil || result != (LoadResult{Loaded: 1, Skipped: 1}) {
+		t.Errorf("reader load = %+v, %v", result, err)
+	}
+}
+
+func TestValidateGeoJSON(t *testing.T) {
+	doc := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{}},
+		{"type":"Feature","geometry":{"type":"Blob","coordinates":[1,2]},"properties":{}},
+		{"type":"Feature","geometry":null,"properties":{}},
+		{"type":"Feature","geometry":{"type":"LineString","coordinates":[[0,0],[1,1]]},"properties":{}}]}`
+	path := filepath.Join(t.TempDir(), "features.geojson")
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	count, errs, err := ValidateGeoJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || len(errs) != 1 || errs[0] != "feature 1: unsupported geometry type" {
+		t.Errorf("ValidateGeoJSON = %d, %q", count, errs)
+	}
+	if _, _, err := ValidateGeoJSONString(`{"type":`); !errors.Is(err, ErrParse) {
+		t.Errorf("truncated document: err = %v, want ErrParse", err)
+	}
+
+	idx, err := NewIndex(&Config{PropertySchema: []PropertyRule{{Key: "name", Required: true}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	result, errs, err := idx.DryRunGeoJSONStringFrom(doc, CRSUnspecified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Loaded != 2 || result.Skipped != 1 || len(errs) != 2 {
+		t.Errorf("DryRunGeoJSONStringFrom = %+v, %q", result, errs)
+	}
+	if idx.Count() != 0 {
+		t.Errorf("dry run inserted %d objects", idx.Count())
+	}
+}
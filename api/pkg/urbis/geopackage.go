@@ -0,0 +1,418 @@
+package urbis
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite driver for GeoPackage files
+)
+
+// GeoPackageLayers lists the feature tables of a GeoPackage file in the
+// order gpkg_contents gives them
+func GeoPackageLayers(path string) ([]string, error) {
+	db, err := openGeoPackage(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return geoPackageLayers(db)
+}
+
+// LoadGeoPackage loads the features of one layer of a GeoPackage file. An
+// empty layer names the file's only feature table. See
+// LoadGeoPackageCounting.
+func (idx *Index) LoadGeoPackage(path, layer string) error {
+	_, err := idx.LoadGeoPackageCounting(path, layer)
+	return err
+}
+
+// LoadGeoPackageCounting is LoadGeoPackage, returning how many features were
+// loaded and how many were skipped for an empty or null geometry.
+//
+// Points, line strings, polygons with their holes, their multi forms and
+// geometry collections are read, with Z values kept and M values dropped.
+// Every attribute column except the integer primary key becomes a property
+// of the same name; BLOB columns are base64 strings. A layer in EPSG:4326
+// or EPSG:3857 is reprojected into the index CRS; another CRS fails with
+// ErrInvalid unless the index CRS is CRSUnspecified, in which case the
+// coordinates are used as given. Nothing is inserted if any feature fails
+// to decode.
+func (idx *Index) LoadGeoPackageCounting(path, layer string) (LoadResult, error) {
+	fc, srcCRS, err := readGeoPackage(path, layer)
+	if err != nil {
+		return LoadResult{}, err
+	}
+	switch {
+	case idx.crs == CRSUnspecified:
+		srcCRS = CRSUnspecified
+	case !IsSupportedCRS(srcCRS):
+		return LoadResult{}, fmt.Errorf("%w: layer %q is in EPSG:%d, which cannot be reprojected into EPSG:%d",
+			ErrInvalid, layer, srcCRS, idx.crs)
+	}
+	return idx.LoadGeoJSONStringFrom(string(fc), srcCRS)
+}
+
+// openGeoPackage opens a GeoPackage file read-only, failing with ErrIO if
+// it cannot be read and ErrParse if it is not a GeoPackage
+func openGeoPackage(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIO, err)
+	}
+	db, err := sql.Open("sqlite3", "file:"+(&url.URL{Path: path}).EscapedPath()+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIO, err)
+	}
+	var n int
+	if err := db.QueryRow(`SELECT count(*) FROM gpkg_contents`).Scan(&n); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%w: not a GeoPackage: %v", ErrParse, err)
+	}
+	return db, nil
+}
+
+func geoPackageLayers(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT table_name FROM gpkg_contents WHERE data_type = 'features' ORDER BY rowid`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+	defer rows.Close()
+
+	var layers []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrParse, err)
+		}
+		layers = append(layers, name)
+	}
+	return layers, rows.Err()
+}
+
+// readGeoPackage turns a layer into a GeoJSON FeatureCollection and returns
+// it with the EPSG code of the layer's CRS, 0 when undefined
+func readGeoPackage(path, layer string) ([]byte, int, error) {
+	db, err := openGeoPackage(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer db.Close()
+
+	if layer == "" {
+		layers, err := geoPackageLayers(db)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(layers) != 1 {
+			return nil, 0, fmt.Errorf("%w: the file has %d feature layers %q; name one", ErrInvalid, len(layers), layers)
+		}
+		layer = layers[0]
+	}
+
+	var column, org string
+	var code int
+	err = db.QueryRow(`SELECT g.column_name, coalesce(s.organization, ''), coalesce(s.organization_coordsys_id, 0)
+		FROM gpkg_geometry_columns g LEFT JOIN gpkg_spatial_ref_sys s ON s.srs_id = g.srs_id
+		WHERE g.table_name = ?`, layer).Scan(&column, &org, &code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, fmt.Errorf("%w: no feature layer %q", ErrNotFound, layer)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+	if !strings.EqualFold(org, "EPSG") || code < 0 {
+		code = CRSUnspecified
+	}
+
+	pk, err := geoPackagePrimaryKey(db, layer)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := db.Query(`SELECT * FROM ` + quoteIdent(layer))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+
+	type feature struct {
+		Type       string                     `json:"type"`
+		Geometry   any                        `json:"geometry"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	features := []feature{}
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for n := 0; rows.Next(); n++ {
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", ErrParse, err)
+		}
+		f := feature{Type: "Feature", Properties: map[string]json.RawMessage{}}
+		for i, name := range columns {
+			switch {
+			case name == column:
+				blob, _ := values[i].([]byte)
+				if f.Geometry, err = decodeGeoPackageGeometry(blob); err != nil {
+					return nil, 0, fmt.Errorf("feature %d: %w", n, err)
+				}
+			case name != pk:
+				if f.Properties[name], err = json.Marshal(values[i]); err != nil {
+					return nil, 0, fmt.Errorf("%w: feature %d column %q: %v", ErrParse, n, name, err)
+				}
+			}
+		}
+		features = append(features, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrParse, err)
+	}
+
+	fc, err := json.Marshal(struct {
+		Type     string    `json:"type"`
+		Features []feature `json:"features"`
+	}{"FeatureCollection", features})
+	return fc, code, err
+}
+
+// geoPackagePrimaryKey returns the name of the layer's integer primary key
+// column, or "" when it has none
+func geoPackagePrimaryKey(db *sql.DB, layer string) (string, error) {
+	rows, err := db.Query(`SELECT name, type, pk FROM pragma_table_info(?)`, layer)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrParse, err)
+	}
+	defer rows.Close()
+
+	var key string
+	for rows.Next() {
+		var name, typ string
+		var pk int
+		if err := rows.Scan(&name, &typ, &pk); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrParse, err)
+		}
+		if pk == 1 && strings.EqualFold(typ, "INTEGER") {
+			key = name
+		}
+	}
+	return key, rows.Err()
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// decodeGeoPackageGeometry converts a GeoPackage geometry blob, a short
+// header followed by WKB, into a GeoJSON geometry. A NULL blob or an empty
+// geometry gives nil, which the loader skips.
+func decodeGeoPackageGeometry(blob []byte) (any, error) {
+	if blob == nil {
+		return nil, nil
+	}
+	if len(blob) < 8 || blob[0] != 'G' || blob[1] != 'P' {
+		return nil, fmt.Errorf("%w: not a GeoPackage geometry", ErrParse)
+	}
+	flags := blob[3]
+	if flags&0x20 != 0 {
+		return nil, fmt.Errorf("%w: extended GeoPackage geometries are not supported", ErrParse)
+	}
+	if flags&0x10 != 0 {
+		return nil, nil
+	}
+	envelope := map[byte]int{0: 0, 1: 32, 2: 48, 3: 48, 4: 64}
+	size, ok := envelope[(flags>>1)&0x07]
+	if !ok || len(blob) < 8+size {
+		return nil, fmt.Errorf("%w: bad GeoPackage geometry header", ErrParse)
+	}
+
+	r := wkbReader{data: blob[8+size:]}
+	geometry := r.geometry(0)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return geometry, nil
+}
+
+// wkbReader decodes WKB, ISO or extended, into GeoJSON geometries. The
+// first error stops it; later reads return zero values.
+type wkbReader struct {
+	data []byte
+	err  error
+}
+
+type geoJSONGeometry struct {
+	Type        string            `json:"type"`
+	Coordinates any               `json:"coordinates,omitempty"`
+	Geometries  []geoJSONGeometry `json:"geometries,omitempty"`
+}
+
+var wkbTypeNames = map[uint32]string{
+	1: "Point", 2: "LineString", 3: "Polygon",
+	4: "MultiPoint", 5: "MultiLineString", 6: "MultiPolygon", 7: "GeometryCollection",
+}
+
+func (r *wkbReader) fail(format string, args ...any) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: "+format, append([]any{ErrParse}, args...)...)
+	}
+}
+
+func (r *wkbReader) uint32(order binary.ByteOrder) uint32 {
+	if r.err != nil || len(r.data) < 4 {
+		r.fail("truncated WKB")
+		return 0
+	}
+	v := order.Uint32(r.data)
+	r.data = r.data[4:]
+	return v
+}
+
+// count reads an element count, refusing one the remaining bytes cannot hold
+func (r *wkbReader) count(order binary.ByteOrder) int {
+	n := r.uint32(order)
+	if int64(n) > int64(len(r.data)) {
+		r.fail("WKB count %d exceeds the data", n)
+		return 0
+	}
+	return int(n)
+}
+
+// position reads one position of dims values, keeping x, y and z
+func (r *wkbReader) position(order binary.ByteOrder, dims int, hasZ bool) []float64 {
+	if r.err != nil || len(r.data) < 8*dims {
+		r.fail("truncated WKB")
+		return nil
+	}
+	p := make([]float64, 0, 3)
+	for i := 0; i < dims; i++ {
+		if i < 2 || (i == 2 && hasZ) {
+			p = append(p, math.Float64frombits(order.Uint64(r.data[8*i:])))
+		}
+	}
+	r.data = r.data[8*dims:]
+	return p
+}
+
+func (r *wkbReader) positions(order binary.ByteOrder, dims int, hasZ bool) [][]float64 {
+	n := r.count(order)
+	out := make([][]float64, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		out = append(out, r.position(order, dims, hasZ))
+	}
+	return out
+}
+
+// geometry reads one WKB geometry. An empty point (NaN coordinates) gives
+// nil, as does any geometry with no positions.
+func (r *wkbReader) geometry(depth int) *geoJSONGeometry {
+	if depth > 32 {
+		r.fail("WKB nested too deeply")
+		return nil
+	}
+	if r.err != nil || len(r.data) < 1 {
+		r.fail("truncated WKB")
+		return nil
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if r.data[0] == 1 {
+		order = binary.LittleEndian
+	}
+	r.data = r.data[1:]
+
+	code := r.uint32(order)
+	hasZ := code&0x80000000 != 0
+	hasM := code&0x40000000 != 0
+	code &^= 0xE0000000
+	switch code / 1000 {
+	case 1:
+		hasZ = true
+	case 2:
+		hasM = true
+	case 3:
+		hasZ, hasM = true, true
+	}
+	code %= 1000
+	dims := 2
+	if hasZ {
+		dims++
+	}
+	if hasM {
+		dims++
+	}
+
+	name, ok := wkbTypeNames[code]
+	if !ok {
+		r.fail("unsupported WKB geometry type %d", code)
+		return nil
+	}
+	g := &geoJSONGeometry{Type: name}
+	switch code {
+	case 1:
+		p := r.position(order, dims, hasZ)
+		if r.err != nil || math.IsNaN(p[0]) || math.IsNaN(p[1]) {
+			return nil
+		}
+		g.Coordinates = p
+	case 2:
+		g.Coordinates = r.positions(order, dims, hasZ)
+	case 3:
+		g.Coordinates = r.rings(order, dims, hasZ)
+	case 4, 5, 6:
+		var parts []any
+		for i, n := 0, r.count(order); i < n && r.err == nil; i++ {
+			if part := r.geometry(depth + 1); part != nil {
+				parts = append(parts, part.Coordinates)
+			}
+		}
+		if len(parts) == 0 {
+			return nil
+		}
+		g.Coordinates = parts
+		return g
+	case 7:
+		for i, n := 0, r.count(order); i < n && r.err == nil; i++ {
+			if member := r.geometry(depth + 1); member != nil {
+				g.Geometries = append(g.Geometries, *member)
+			}
+		}
+		if len(g.Geometries) == 0 {
+			return nil
+		}
+		return g
+	}
+	if r.err != nil || isEmptyCoordinates(g.Coordinates) {
+		return nil
+	}
+	return g
+}
+
+func (r *wkbReader) rings(order binary.ByteOrder, dims int, hasZ bool) [][][]float64 {
+	n := r.count(order)
+	out := make([][][]float64, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		out = append(out, r.positions(order, dims, hasZ))
+	}
+	return out
+}
+
+func isEmptyCoordinates(c any) bool {
+	switch c := c.(type) {
+	case [][]float64:
+		return len(c) == 0
+	case [][][]float64:
+		return len(c) == 0 || len(c[0]) == 0
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package urbis
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// gpkgGeometry wraps little-endian WKB in a GeoPackage header with no
+// envelope. Each uint32 part is a type code or count and each float64 a
+// coordinate.
+func gpkgGeometry(parts ...any) []byte {
+	blob := []byte{'G', 'P', 0, 0x01, 0, 0, 0, 0, 1}
+	for _, part := range parts {
+		switch v := part.(type) {
+		case uint32:
+			blob = binary.LittleEndian.AppendUint32(blob, v)
+		case float64:
+			blob = binary.LittleEndian.AppendUint64(blob, math.Float64bits(v))
+		}
+	}
+	return blob
+}
+
+// writeGeoPackage creates a GeoPackage with the layer "places" in the
+// given EPSG CRS and returns its path
+func writeGeoPackage(t *testing.T, epsg int, rows [][]any) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "places.gpkg")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, stmt := range []string{
+		`CREATE TABLE gpkg_spatial_ref_sys (srs_name TEXT, srs_id INTEGER PRIMARY KEY,
+			organization TEXT, organization_coordsys_id INTEGER, definition TEXT)`,
+		`CREATE TABLE gpkg_contents (table_name TEXT PRIMARY KEY, data_type TEXT, srs_id INTEGER)`,
+		`CREATE TABLE gpkg_geometry_columns (table_name TEXT, column_name TEXT,
+			geometry_type_name TEXT, srs_id INTEGER, z INTEGER, m INTEGER)`,
+		`CREATE TABLE places (fid INTEGER PRIMARY KEY, geom BLOB, name TEXT, visitors INTEGER)`,
+		`INSERT INTO gpkg_contents VALUES ('places', 'features', 1)`,
+		`INSERT INTO gpkg_geometry_columns VALUES ('places', 'geom', 'GEOMETRY', 1, 0, 0)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(`INSERT INTO gpkg_spatial_ref_sys VALUES ('crs', 1, 'EPSG', ?, '')`, epsg); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if _, err := db.Exec(`INSERT INTO places (geom, name, visitors) VALUES (?, ?, ?)`, row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestLoadGeoPackage(t *testing.T) {
+	path := writeGeoPackage(t, CRSWGS84, [][]any{
+		{gpkgGeometry(uint32(3), uint32(1), uint32(5), 0.0, 0.0, 10.0, 0.0, 10.0, 10.0, 0.0, 10.0, 0.0, 0.0), "park", 120},
+		{gpkgGeometry(uint32(1001), 5.0, 20.0, 7.0), "fountain", nil}, // PointZ
+		{gpkgGeometry(uint32(2), uint32(2), 2.0, 30.0, 4.0, 31.0), nil, 3},
+		{nil, "nowhere", 0},
+	})
+
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	result, err := idx.LoadGeoPackageCounting(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (LoadResult{Loaded: 3, Skipped: 1}) {
+		t.Fatalf("result = %+v, want 3 loaded and 1 skipped", result)
+	}
+
+	for _, tc := range []struct {
+		id         uint64
+		typ        GeomType
+		properties string
+	}{
+		{1, GeomPolygon, `{"name":"park","visitors":120}`},
+		{2, GeomPoint, `{"name":"fountain","visitors":null}`},
+		{3, GeomLineString, `{"name":null,"visitors":3}`},
+	} {
+		obj, err := idx.Get(tc.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if obj.Type != tc.typ {
+			t.Errorf("object %d type = %v, want %v", tc.id, obj.Type, tc.typ)
+		}
+		data, err := idx.GetProperties(tc.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got any
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("object %d properties %s: %v", tc.id, data, err)
+		}
+		if g, _ := json.Marshal(got); string(g) != tc.properties {
+			t.Errorf("object %d properties = %s, want %s", tc.id, g, tc.properties)
+		}
+	}
+	if geometry, err := idx.ExportGeoJSON(2); err != nil || string(geometry) != `{"type":"Point","coordinates":[5,20,7]}` {
+		t.Errorf("ExportGeoJSON(2) = %s, %v", geometry, err)
+	}
+
+	if layers, err := GeoPackageLayers(path); err != nil || len(layers) != 1 || layers[0] != "places" {
+		t.Errorf("GeoPackageLayers = %v, %v", layers, err)
+	}
+}
+
+func TestLoadGeoPackageErrors(t *testing.T) {
+	idx, err := NewIndex(&Config{CRS: CRSWebMercator})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	path := writeGeoPackage(t, CRSWGS84, [][]any{{gpkgGeometry(uint32(1), 0.0, 0.0), "origin", 1}})
+	if err := idx.LoadGeoPackage(path, "roads"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing layer: err = %v, want ErrNotFound", err)
+	}
+	if err := idx.LoadGeoPackage(filepath.Join(t.TempDir(), "none.gpkg"), ""); !errors.Is(err, ErrIO) {
+		t.Errorf("missing file: err = %v, want ErrIO", err)
+	}
+
+	text := filepath.Join(t.TempDir(), "text.gpkg")
+	if err := os.WriteFile(text, []byte("not a database"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.LoadGeoPackage(text, ""); !errors.Is(err, ErrParse) {
+		t.Errorf("not a GeoPackage: err = %v, want ErrParse", err)
+	}
+
+	other := writeGeoPackage(t, 27700, [][]any{{gpkgGeometry(uint32(1), 0.0, 0.0), "origin", 1}})
+	if err := idx.LoadGeoPackage(other, "places"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("unsupported CRS: err = %v, want ErrInvalid", err)
+	}
+	if idx.Count() != 0 {
+		t.Fatalf("count = %d after failed loads, want 0", idx.Count())
+	}
+
+	// A WGS84 layer is reprojected into the index CRS
+	if err := idx.LoadGeoPackage(path, "places"); err != nil {
+		t.Fatal(err)
+	}
+	if obj, err := idx.Get(1); err != nil || obj.Centroid.X != 0 || obj.Centroid.Y != 0 {
+		t.Errorf("reprojected origin = %+v, %v", obj, err)
+	}
+}
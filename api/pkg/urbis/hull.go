@@ -0,0 +1,85 @@
+package urbis
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ConvexHull returns the convex hull of the objects in region, computed
+// over all their vertices. The hull is a closed counter-clockwise ring
+// (the last point repeats the first), so it can be passed straight to
+// InsertPolygon. See HullOf for degenerate inputs.
+func (idx *Index) ConvexHull(region MBR) ([]Point, error) {
+	result, err := idx.QueryRange(region)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []Point
+	for _, obj := range result.Objects {
+		points = appendVertices(points, obj)
+	}
+	return HullOf(points), nil
+}
+
+// HullOf returns the convex hull of points using Andrew's monotone chain.
+// With three or more non-collinear points the hull is a closed
+// counter-clockwise ring. Otherwise there is no area to enclose and the
+// distinct points are returned as they are: none, one, or the two ends of
+// the line they all lie on.
+func HullOf(points []Point) []Point {
+	sorted := slices.Clone(points)
+	slices.SortFunc(sorted, func(a, b Point) int {
+		if c := cmp.Compare(a.X, b.X); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Y, b.Y)
+	})
+	sorted = slices.Compact(sorted)
+	if len(sorted) < 3 {
+		return sorted
+	}
+
+	hull := make([]Point, 0, 2*len(sorted))
+	// Lower hull left to right, then upper hull right to left, dropping
+	// points that do not make a counter-clockwise turn
+	for _, p := range sorted {
+		for len(hull) >= 2 && orientation(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	for i, lower := len(sorted)-2, len(hull)+1; i >= 0; i-- {
+		p := sorted[i]
+		for len(hull) >= lower && orientation(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	// All points collinear: the walk returns to the start after one edge
+	if len(hull) < 4 {
+		return []Point{sorted[0], sorted[len(sorted)-1]}
+	}
+	return hull
+}
+
+// appendVertices adds every vertex of obj's geometry to points
+func appendVertices(points []Point, obj *SpatialObject) []Point {
+	if obj.Point != nil {
+		points = append(points, *obj.Point)
+	}
+	points = append(points, obj.Line...)
+	points = append(points, obj.Polygon...)
+	points = append(points, obj.MultiPoint...)
+	for _, line := range obj.MultiLine {
+		points = append(points, line...)
+	}
+	for _, ring := range obj.MultiPolygon {
+		points = append(points, ring...)
+	}
+	for _, g := range obj.Geometries {
+		points = appendVertices(points, g)
+	}
+	return points
+}
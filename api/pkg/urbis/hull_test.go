@@ -0,0 +1,62 @@
+package urbis
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestHullOf(t *testing.T) {
+	square := []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {1, 1}, {1, 0}, {2, 2}}
+	want := []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}
+	if got := HullOf(square); !slices.Equal(got, want) {
+		t.Errorf("square hull = %v, want %v", got, want)
+	}
+	if ok, reason := ValidatePolygon(HullOf(square)); !ok {
+		t.Errorf("hull is not a valid polygon: %s", reason)
+	}
+
+	degenerate := []struct {
+		name   string
+		points []Point
+		want   []Point
+	}{
+		{"empty", nil, []Point{}},
+		{"single", []Point{{1, 1}, {1, 1}}, []Point{{1, 1}}},
+		{"two", []Point{{3, 0}, {1, 1}}, []Point{{1, 1}, {3, 0}}},
+		{"collinear", []Point{{2, 2}, {0, 0}, {1, 1}, {3, 3}}, []Point{{0, 0}, {3, 3}}},
+	}
+	for _, tc := range degenerate {
+		if got := HullOf(tc.points); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: hull = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestIndexConvexHull(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	idx.InsertPoint(1, 1)
+	idx.InsertLineString([]Point{{0, 0}, {4, 1}})
+	idx.InsertPolygon([]Point{{1, 2}, {3, 2}, {2, 5}, {1, 2}})
+	idx.InsertPoint(100, 100)
+	if _, err := idx.ConvexHull(MBR{MaxX: 10, MaxY: 10}); !errors.Is(err, ErrNotBuilt) {
+		t.Errorf("unbuilt index: err = %v, want ErrNotBuilt", err)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	hull, err := idx.ConvexHull(MBR{MaxX: 10, MaxY: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Point{{0, 0}, {4, 1}, {2, 5}, {0, 0}}
+	if !slices.Equal(hull, want) {
+		t.Errorf("hull = %v, want %v", hull, want)
+	}
+}
@@ -0,0 +1,150 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// DistanceMetric selects how QueryKNNBy measures the distance from the
+// query point to an object
+type DistanceMetric int
+
+const (
+	// DistanceCentroid measures to the object's centroid, as QueryKNN does
+	DistanceCentroid DistanceMetric = iota
+	// DistanceEdge measures to the nearest point of the geometry: a vertex,
+	// anywhere along an edge, or zero inside a polygon
+	DistanceEdge
+	// DistanceVertex measures to the nearest vertex of the geometry
+	DistanceVertex
+)
+
+// QueryKNNBy returns the k objects nearest (x, y) under metric, nearest
+// first, and the distance to each in index coordinates. DistanceCentroid
+// ranks as QueryKNN does; the other metrics break ties by ID.
+//
+// DistanceEdge and DistanceVertex read the exact geometry, so a long river
+// polygon ranks by its bank rather than its far-off centroid. Polygon holes
+// are ignored, as in QueryBuffered. Objects restored from a saved index
+// without their geometry are measured to their centroid.
+func (idx *Index) QueryKNNBy(x, y float64, k uint32, metric DistanceMetric) (*ObjectList, []float64, error) {
+	if metric < DistanceCentroid || metric > DistanceVertex {
+		return nil, nil, fmt.Errorf("%w: unknown distance metric %d", ErrInvalid, metric)
+	}
+
+	if err := idx.rebuildIfDue(true); err != nil {
+		return nil, nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if err := idx.requireBuilt(); err != nil {
+		return nil, nil, err
+	}
+
+	p := Point{X: x, Y: y}
+	centroids := &ObjectList{Objects: []*SpatialObject{}}
+	if result := C.urbis_query_knn(idx.ptr, C.double(x), C.double(y), C.size_t(k)); result != nil {
+		centroids = convertObjectList(result)
+		C.urbis_object_list_free(result)
+	}
+	if metric == DistanceCentroid || len(centroids.Objects) == 0 {
+		distances := make([]float64, len(centroids.Objects))
+		for i, obj := range centroids.Objects {
+			distances[i] = math.Hypot(obj.Centroid.X-x, obj.Centroid.Y-y)
+		}
+		return centroids, distances, nil
+	}
+
+	// Search a square around p that grows until it holds k objects within
+	// its half-width r: any object nearer than r has a point, and so its
+	// MBR, inside the square. The k-th centroid is a first guess at r.
+	cb := C.urbis_bounds(idx.ptr)
+	bounds := MBR{MinX: float64(cb.min_x), MinY: float64(cb.min_y), MaxX: float64(cb.max_x), MaxY: float64(cb.max_y)}
+	last := centroids.Objects[len(centroids.Objects)-1].Centroid
+	r := math.Hypot(last.X-x, last.Y-y)
+	if r == 0 {
+		r = math.Max(bounds.MaxX-bounds.MinX, bounds.MaxY-bounds.MinY) * 1e-6
+	}
+
+	type neighbor struct {
+		obj      *SpatialObject
+		distance float64
+	}
+	for {
+		box := MBR{MinX: x - r, MinY: y - r, MaxX: x + r, MaxY: y + r}
+		list := idx.queryRange(box, StructureAuto)
+		if err := list.checkPages(); err != nil {
+			return nil, nil, err
+		}
+
+		var found []neighbor
+		for _, obj := range list.Objects {
+			if d := objectDistance(p, obj, metric); d <= r {
+				found = append(found, neighbor{obj, d})
+			}
+		}
+		covered := box.MinX <= bounds.MinX && box.MinY <= bounds.MinY && box.MaxX >= bounds.MaxX && box.MaxY >= bounds.MaxY
+		if len(found) < int(k) && !covered && r > 0 {
+			r *= 2
+			continue
+		}
+
+		slices.SortFunc(found, func(a, b neighbor) int {
+			return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.obj.ID, b.obj.ID))
+		})
+		found = found[:min(len(found), int(k))]
+		list.Objects = make([]*SpatialObject, len(found))
+		distances := make([]float64, len(found))
+		for i, n := range found {
+			list.Objects[i], distances[i] = n.obj, n.distance
+		}
+		list.Count = uint64(len(found))
+		return list, distances, nil
+	}
+}
+
+// objectDistance measures from p to obj under metric
+func objectDistance(p Point, obj *SpatialObject, metric DistanceMetric) float64 {
+	s := shapeOf(obj)
+	if metric == DistanceCentroid || (len(s.points) == 0 && len(s.edges) == 0) {
+		return math.Hypot(obj.Centroid.X-p.X, obj.Centroid.Y-p.Y)
+	}
+
+	best := math.Inf(1)
+	for _, q := range s.points {
+		best = math.Min(best, math.Hypot(q.X-p.X, q.Y-p.Y))
+	}
+	for _, e := range s.edges {
+		if metric == DistanceVertex {
+			best = math.Min(best, math.Min(math.Hypot(e[0].X-p.X, e[0].Y-p.Y), math.Hypot(e[1].X-p.X, e[1].Y-p.Y)))
+		} else {
+			best = math.Min(best, segmentDistance(p, e[0], e[1]))
+		}
+	}
+	if metric == DistanceEdge {
+		for _, ring := range s.rings {
+			if inRing(p, ring) {
+				return 0
+			}
+		}
+	}
+	return best
+}
+
+// segmentDistance returns the distance from p to the nearest point of the
+// segment a-b
+func segmentDistance(p, a, b Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/l))
+	}
+	return math.Hypot(a.X+t*dx-p.X, a.Y+t*dy-p.Y)
+}
@@ -0,0 +1,75 @@
+package urbis
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestQueryKNNBy(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	// A river 400 long whose bank passes 1 from the query point but whose
+	// centroid is 200 away, a road 2 away with a far centroid too, and a
+	// lone point 5 away
+	river, err := idx.InsertPolygon([]Point{{0, 1}, {400, 1}, {400, 3}, {0, 3}, {0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	road, err := idx.InsertLineString([]Point{{-10, -2}, {390, -2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lone, err := idx.InsertPoint(5, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(list *ObjectList) []uint64 {
+		var out []uint64
+		for _, obj := range list.Objects {
+			out = append(out, obj.ID)
+		}
+		return out
+	}
+	for _, tc := range []struct {
+		metric    DistanceMetric
+		want      []uint64
+		distances []float64
+	}{
+		{DistanceCentroid, []uint64{lone, road, river}, []float64{5, math.Hypot(190, 2), math.Hypot(200, 2)}},
+		{DistanceEdge, []uint64{river, road, lone}, []float64{1, 2, 5}},
+		{DistanceVertex, []uint64{river, lone, road}, []float64{1, 5, math.Hypot(10, 2)}},
+	} {
+		list, distances, err := idx.QueryKNNBy(0, 0, 3, tc.metric)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(list); len(got) != 3 || got[0] != tc.want[0] || got[1] != tc.want[1] || got[2] != tc.want[2] {
+			t.Errorf("metric %d ranked %v, want %v", tc.metric, got, tc.want)
+			continue
+		}
+		for i, d := range distances {
+			if math.Abs(d-tc.distances[i]) > 1e-9 {
+				t.Errorf("metric %d distance %d = %v, want %v", tc.metric, i, d, tc.distances[i])
+			}
+		}
+	}
+
+	if _, distances, _ := idx.QueryKNNBy(10, 2, 1, DistanceEdge); len(distances) != 1 || distances[0] != 0 {
+		t.Errorf("point inside the river: distances %v, want [0]", distances)
+	}
+	if list, _, err := idx.QueryKNNBy(0, 0, 10, DistanceEdge); err != nil || list.Count != 3 {
+		t.Errorf("k beyond the object count: %v, %v", list, err)
+	}
+	if _, _, err := idx.QueryKNNBy(0, 0, 1, DistanceMetric(7)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("unknown metric: err = %v, want ErrInvalid", err)
+	}
+}
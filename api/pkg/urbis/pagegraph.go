@@ -0,0 +1,114 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+import (
+	"cmp"
+	"slices"
+)
+
+// adjacencyEpsilon is how far apart page extents may be and still count as
+// touching, matching the quadtree's adjacency test
+const adjacencyEpsilon = 1e-9
+
+// PageNode is a page in the page graph
+type PageNode struct {
+	PageID      uint32
+	TrackID     uint32
+	ObjectCount uint32
+	Extent      MBR
+}
+
+// PageEdge joins two pages whose extents touch or overlap. Seeks is the
+// estimated cost of reading one after the other: 0 on the same track, 1
+// otherwise, as in QueryStats.EstimatedSeeks.
+type PageEdge struct {
+	From, To uint32 // Page IDs, From < To
+	Seeks    uint64
+}
+
+// PageGraph is the adjacency graph of an index's pages
+type PageGraph struct {
+	Nodes []PageNode // Ordered by page ID
+	Edges []PageEdge // Ordered by From, then To
+}
+
+// GetPageGraph returns every non-empty page with its extent and track,
+// and an edge for every pair of pages whose extents touch or overlap.
+// Those are the pages FindAdjacentPages would return for each other's
+// extents. Track IDs are assigned by Build, so the index must be built.
+func (idx *Index) GetPageGraph() (*PageGraph, error) {
+	if err := idx.rebuildIfDue(true); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if err := idx.requireBuilt(); err != nil {
+		return nil, err
+	}
+
+	count := C.urbis_list_pages(idx.ptr, nil, 0)
+	graph := &PageGraph{Nodes: []PageNode{}, Edges: []PageEdge{}}
+	if count == 0 {
+		return graph, nil
+	}
+	pages := make([]C.UrbisPageInfo, count)
+	count = C.urbis_list_pages(idx.ptr, &pages[0], count)
+
+	for _, p := range pages[:count] {
+		if p.object_count == 0 {
+			continue
+		}
+		graph.Nodes = append(graph.Nodes, PageNode{
+			PageID:      uint32(p.page_id),
+			TrackID:     uint32(p.track_id),
+			ObjectCount: uint32(p.object_count),
+			Extent: MBR{
+				MinX: float64(p.extent.min_x),
+				MinY: float64(p.extent.min_y),
+				MaxX: float64(p.extent.max_x),
+				MaxY: float64(p.extent.max_y),
+			},
+		})
+	}
+	slices.SortFunc(graph.Nodes, func(a, b PageNode) int { return cmp.Compare(a.PageID, b.PageID) })
+
+	graph.Edges = pageEdges(graph.Nodes)
+	return graph, nil
+}
+
+// pageEdges finds the touching pairs with a sweep along x
+func pageEdges(nodes []PageNode) []PageEdge {
+	byMinX := slices.Clone(nodes)
+	slices.SortFunc(byMinX, func(a, b PageNode) int { return cmp.Compare(a.Extent.MinX, b.Extent.MinX) })
+
+	edges := []PageEdge{}
+	for i, a := range byMinX {
+		for _, b := range byMinX[i+1:] {
+			if b.Extent.MinX > a.Extent.MaxX+adjacencyEpsilon {
+				break
+			}
+			if b.Extent.MinY > a.Extent.MaxY+adjacencyEpsilon || b.Extent.MaxY < a.Extent.MinY-adjacencyEpsilon {
+				continue
+			}
+			edge := PageEdge{From: a.PageID, To: b.PageID}
+			if edge.From > edge.To {
+				edge.From, edge.To = edge.To, edge.From
+			}
+			if a.TrackID != b.TrackID {
+				edge.Seeks = 1
+			}
+			edges = append(edges, edge)
+		}
+	}
+	slices.SortFunc(edges, func(a, b PageEdge) int {
+		if c := cmp.Compare(a.From, b.From); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.To, b.To)
+	})
+	return edges
+}
@@ -0,0 +1,65 @@
+package urbis
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPageEdges(t *testing.T) {
+	nodes := []PageNode{
+		{PageID: 1, TrackID: 1, Extent: MBR{MinX: 0, MinY: 0, MaxX: 1, MaxY: 1}},
+		{PageID: 2, TrackID: 1, Extent: MBR{MinX: 1, MinY: 0, MaxX: 2, MaxY: 1}},     // touches 1
+		{PageID: 3, TrackID: 2, Extent: MBR{MinX: 0.5, MinY: 0.5, MaxX: 3, MaxY: 3}}, // overlaps 1 and 2
+		{PageID: 4, TrackID: 2, Extent: MBR{MinX: 5, MinY: 0, MaxX: 6, MaxY: 1}},     // isolated
+		{PageID: 5, TrackID: 3, Extent: MBR{MinX: 0, MinY: 4, MaxX: 1, MaxY: 5}},     // x overlaps 1, y does not
+	}
+	want := []PageEdge{
+		{From: 1, To: 2, Seeks: 0},
+		{From: 1, To: 3, Seeks: 1},
+		{From: 2, To: 3, Seeks: 1},
+	}
+	if got := pageEdges(nodes); !slices.Equal(got, want) {
+		t.Errorf("edges = %v, want %v", got, want)
+	}
+}
+
+func TestGetPageGraph(t *testing.T) {
+	idx, err := NewIndex(&Config{PageCapacity: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 64; i++ {
+		idx.InsertPoint(float64(i%8), float64(i/8))
+	}
+	if _, err := idx.GetPageGraph(); !errors.Is(err, ErrNotBuilt) {
+		t.Errorf("unbuilt index: err = %v, want ErrNotBuilt", err)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	graph, err := idx.GetPageGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var objects uint32
+	ids := make(map[uint32]bool)
+	for _, n := range graph.Nodes {
+		objects += n.ObjectCount
+		ids[n.PageID] = true
+	}
+	if objects != 64 || len(graph.Nodes) < 16 {
+		t.Fatalf("%d pages holding %d objects, want at least 16 pages holding 64", len(graph.Nodes), objects)
+	}
+	if len(graph.Edges) == 0 {
+		t.Fatal("no edges between pages of a grid")
+	}
+	for _, e := range graph.Edges {
+		if !ids[e.From] || !ids[e.To] || e.From >= e.To {
+			t.Errorf("edge %v does not join two listed pages in order", e)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package urbis
+
+import "math"
+
+// PolylinePrecision is the number of decimal places EncodePolyline keeps
+const PolylinePrecision = 5
+
+// EncodePolyline encodes points with the Encoded Polyline Algorithm used by
+// mapping services, at PolylinePrecision decimal places. Each point is
+// written latitude first, so Y precedes X.
+func EncodePolyline(points []Point) []byte {
+	scale := math.Pow10(PolylinePrecision)
+	buf := make([]byte, 0, len(points)*8)
+
+	var prevX, prevY int64
+	for _, p := range points {
+		x := int64(math.Round(p.X * scale))
+		y := int64(math.Round(p.Y * scale))
+		buf = appendPolylineValue(buf, y-prevY)
+		buf = appendPolylineValue(buf, x-prevX)
+		prevX, prevY = x, y
+	}
+	return buf
+}
+
+// DecodePolyline reverses EncodePolyline. It returns ErrParse if the input
+// is truncated or holds an odd number of values.
+func DecodePolyline(data []byte) ([]Point, error) {
+	scale := math.Pow10(PolylinePrecision)
+	var points []Point
+
+	var x, y int64
+	for i := 0; i < len(data); {
+		dy, n := readPolylineValue(data[i:])
+		if n == 0 {
+			return nil, ErrParse
+		}
+		i += n
+		dx, n := readPolylineValue(data[i:])
+		if n == 0 {
+			return nil, ErrParse
+		}
+		i += n
+
+		x, y = x+dx, y+dy
+		points = append(points, Point{X: float64(x) / scale, Y: float64(y) / scale})
+	}
+	return points, nil
+}
+
+func appendPolylineValue(buf []byte, v int64) []byte {
+	u := uint64(v) << 1
+	if v < 0 {
+		u = ^u
+	}
+	for u >= 0x20 {
+		buf = append(buf, byte(0x20|u&0x1f)+63)
+		u >>= 5
+	}
+	return append(buf, byte(u)+63)
+}
+
+// readPolylineValue decodes one value, returning the bytes consumed or 0
+// if data ends mid-value
+func readPolylineValue(data []byte) (int64, int) {
+	var u uint64
+	var shift uint
+	for i, c := range data {
+		if c < 63 || shift > 63 {
+			return 0, 0
+		}
+		chunk := uint64(c - 63)
+		u |= (chunk & 0x1f) << shift
+		shift += 5
+		if chunk < 0x20 {
+			v := int64(u >> 1)
+			if u&1 != 0 {
+				v = ^v
+			}
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
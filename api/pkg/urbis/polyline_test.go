@@ -0,0 +1,33 @@
+package urbis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEncodePolyline(t *testing.T) {
+	// Example from the Encoded Polyline Algorithm Format documentation
+	points := []Point{{X: -120.2, Y: 38.5}, {X: -120.95, Y: 40.7}, {X: -126.453, Y: 43.252}}
+	const want = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+
+	if got := string(EncodePolyline(points)); got != want {
+		t.Fatalf("EncodePolyline = %q, want %q", got, want)
+	}
+
+	decoded, err := DecodePolyline([]byte(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(points) {
+		t.Fatalf("decoded %d points, want %d", len(decoded), len(points))
+	}
+	for i, p := range decoded {
+		if math.Abs(p.X-points[i].X) > 1e-9 || math.Abs(p.Y-points[i].Y) > 1e-9 {
+			t.Errorf("point %d = %v, want %v", i, p, points[i])
+		}
+	}
+
+	if _, err := DecodePolyline([]byte(want[:len(want)-1])); err != ErrParse {
+		t.Errorf("truncated polyline: got %v, want ErrParse", err)
+	}
+}
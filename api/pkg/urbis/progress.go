@@ -0,0 +1,256 @@
+package urbis
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "urbis.h"
+
+extern void goBuildProgress(void *user_data, size_t done, size_t total);
+extern bool goBuildCancelled(void *user_data);
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// buildHooks is what the C build callbacks reach through their user data
+type buildHooks struct {
+	ctx      context.Context
+	progress func(done, total uint64)
+}
+
+// BuildProgress builds the spatial index like Build, calling fn with the
+// number of objects processed and the total as the build proceeds. fn runs
+// synchronously on the calling goroutine; its last call has done == total.
+func (idx *Index) BuildProgress(fn func(done, total uint64)) error {
+	return idx.BuildProgressContext(context.Background(), fn)
+}
+
+// BuildContext builds the spatial index like Build, stopping early once
+// ctx is done; see BuildProgressContext
+func (idx *Index) BuildContext(ctx context.Context) error {
+	return idx.BuildProgressContext(ctx, nil)
+}
+
+// BuildProgressContext builds the spatial index like BuildProgress, with
+// fn optional. ctx is checked between build phases and while objects are
+// gathered; once it is done the build stops and returns an error wrapping
+// both ErrCancelled and ctx.Err(). A cancelled build leaves the index
+// unbuilt, even if it was built before, so queries fail with ErrNotBuilt
+// until the next successful build.
+func (idx *Index) BuildProgressContext(ctx context.Context, fn func(done, total uint64)) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return err
+	}
+
+	userData, release := newBuildUserData(&buildHooks{ctx: ctx, progress: fn})
+	defer release()
+
+	err := toError(C.urbis_build_cancellable(idx.ptr, C.BuildProgressFn(C.goBuildProgress),
+		C.BuildCancelFn(C.goBuildCancelled), userData))
+	if err := cancelledError(ctx, err); err != nil {
+		return err
+	}
+	return idx.buildPropertyIndex()
+}
+
+// OptimizeContext optimizes the index like Optimize, stopping early once
+// ctx is done, for example when its deadline passes. The error then wraps
+// both ErrCancelled and ctx.Err(). Unlike a stopped build, a stopped
+// optimize leaves the index as it was: the new layout replaces the old one
+// only once it is complete, so a built index keeps answering queries.
+func (idx *Index) OptimizeContext(ctx context.Context) (*OptimizeReport, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return nil, err
+	}
+
+	report := &OptimizeReport{Before: idx.stats()}
+	probe := representativeRegion(report.Before.Bounds)
+	report.SeeksBefore = idx.estimateSeeks(probe)
+
+	userData, release := newBuildUserData(&buildHooks{ctx: ctx})
+	defer release()
+
+	err := toError(C.urbis_optimize_cancellable(idx.ptr, C.BuildCancelFn(C.goBuildCancelled), userData))
+	if err := cancelledError(ctx, err); err != nil {
+		return nil, err
+	}
+
+	report.After = idx.stats()
+	report.SeeksAfter = idx.estimateSeeks(probe)
+	return report, nil
+}
+
+// CompactContext compacts the index like Compact, stopping early once ctx
+// is done. The compacted copy replaces the index only once it is complete,
+// so a stopped compaction leaves the index as it was; the error wraps both
+// ErrCancelled and ctx.Err().
+func (idx *Index) CompactContext(ctx context.Context) (*CompactReport, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return nil, err
+	}
+
+	if idx.ptr == nil {
+		return nil, ErrNull
+	}
+
+	report := &CompactReport{Before: idx.stats()}
+	compacted, err := idx.repage(ctx, C.size_t(report.Before.PageCapacity))
+	if err != nil {
+		return nil, err
+	}
+	if err := idx.replace(compacted); err != nil {
+		return nil, err
+	}
+
+	report.After = idx.stats()
+	return report, nil
+}
+
+// AutoTuneConfigContext recommends a page capacity like AutoTuneConfig,
+// stopping early once ctx is done. The scratch copies are dropped and, with
+// apply, the index is only replaced once the chosen layout is complete, so
+// a stopped call leaves the index as it was; the error wraps both
+// ErrCancelled and ctx.Err().
+func (idx *Index) AutoTuneConfigContext(ctx context.Context, sampleQueries []MBR, apply bool) (*TuneReport, error) {
+	if apply {
+		idx.mu.Lock()
+		defer idx.mu.Unlock()
+		if err := idx.requireWritable(); err != nil {
+			return nil, err
+		}
+	} else {
+		idx.mu.RLock()
+		defer idx.mu.RUnlock()
+	}
+
+	var cqueries *C.MBR
+	if len(sampleQueries) > 0 {
+		buf := make([]C.MBR, len(sampleQueries))
+		for i, q := range sampleQueries {
+			buf[i] = C.MBR{
+				min_x: C.double(q.MinX),
+				min_y: C.double(q.MinY),
+				max_x: C.double(q.MaxX),
+				max_y: C.double(q.MaxY),
+			}
+		}
+		cqueries = &buf[0]
+	}
+
+	userData, release := newBuildUserData(&buildHooks{ctx: ctx})
+	defer release()
+
+	var cresult C.UrbisTuneResult
+	err := toError(C.urbis_autotune_cancellable(idx.ptr, cqueries, C.size_t(len(sampleQueries)),
+		C.BuildCancelFn(C.goBuildCancelled), userData, &cresult))
+	if err := cancelledError(ctx, err); err != nil {
+		return nil, err
+	}
+
+	report := &TuneReport{
+		PageCapacity: uint64(cresult.page_capacity),
+		Candidates:   make([]TuneCandidate, cresult.candidate_count),
+	}
+	for i := range report.Candidates {
+		c := cresult.candidates[i]
+		report.Candidates[i] = TuneCandidate{
+			PageCapacity:    uint64(c.page_capacity),
+			TotalPages:      uint64(c.total_pages),
+			PageUtilization: float64(c.page_utilization),
+			AvgSeeks:        float64(c.avg_seeks),
+			AvgCostMs:       float64(c.avg_cost_ms),
+			Cost:            float64(c.cost),
+		}
+	}
+
+	if apply && report.PageCapacity != idx.stats().PageCapacity {
+		repaged, err := idx.repage(ctx, cresult.page_capacity)
+		if err != nil {
+			return nil, err
+		}
+		if err := idx.replace(repaged); err != nil {
+			return nil, err
+		}
+		report.Applied = true
+	}
+	return report, nil
+}
+
+// replace swaps a copy from repage in for the native index. The data file
+// the index saves to, if any, is rewritten with the copy's pages and moves
+// to it, so Sync keeps writing there. On failure the copy is freed and the
+// index left as it was. The caller must hold the index lock.
+func (idx *Index) replace(copied *C.UrbisIndex) error {
+	if err := toError(C.urbis_adopt_data_file(copied, idx.ptr)); err != nil {
+		C.urbis_destroy(copied)
+		return err
+	}
+	C.urbis_destroy(idx.ptr)
+	idx.ptr = copied
+	return nil
+}
+
+// repage returns a built copy of the index on pages of the given capacity,
+// stopping early once ctx is done. The caller must hold the index lock.
+func (idx *Index) repage(ctx context.Context, pageCapacity C.size_t) (*C.UrbisIndex, error) {
+	userData, release := newBuildUserData(&buildHooks{ctx: ctx})
+	defer release()
+
+	var copied *C.UrbisIndex
+	err := toError(C.urbis_repage_cancellable(idx.ptr, pageCapacity,
+		C.BuildCancelFn(C.goBuildCancelled), userData, &copied))
+	if err := cancelledError(ctx, err); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+// newBuildUserData returns the user data for the C build callbacks and a
+// function that frees it. The handle to hooks goes through C memory so no
+// Go pointer crosses the boundary.
+func newBuildUserData(hooks *buildHooks) (unsafe.Pointer, func()) {
+	handle := cgo.NewHandle(hooks)
+	userData := C.malloc(C.size_t(unsafe.Sizeof(C.uintptr_t(0))))
+	*(*C.uintptr_t)(userData) = C.uintptr_t(handle)
+	return userData, func() {
+		C.free(userData)
+		handle.Delete()
+	}
+}
+
+// cancelledError adds the reason ctx ended to an ErrCancelled from a
+// cancellable build
+func cancelledError(ctx context.Context, err error) error {
+	if errors.Is(err, ErrCancelled) {
+		return fmt.Errorf("%w: %w", ErrCancelled, ctx.Err())
+	}
+	return err
+}
+
+//export goBuildProgress
+func goBuildProgress(userData unsafe.Pointer, done, total C.size_t) {
+	hooks := cgo.Handle(*(*C.uintptr_t)(userData)).Value().(*buildHooks)
+	if hooks.progress != nil {
+		hooks.progress(uint64(done), uint64(total))
+	}
+}
+
+//export goBuildCancelled
+func goBuildCancelled(userData unsafe.Pointer) C.bool {
+	hooks := cgo.Handle(*(*C.uintptr_t)(userData)).Value().(*buildHooks)
+	return C.bool(hooks.ctx.Err() != nil)
+}
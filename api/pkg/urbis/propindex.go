@@ -0,0 +1,151 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"unsafe"
+)
+
+// propertyIndex maps each indexed property key to its values, and each
+// value to the IDs of the objects that have it, in ID order
+type propertyIndex map[string]map[string][]uint64
+
+// QueryByProperty returns the objects whose properties have key set to
+// value, ordered by ID. The key must be listed in Config.IndexedProperties.
+//
+// Properties are matched as JSON objects. String values compare as the
+// string itself, and numbers and booleans as their JSON text (for example
+// "2.5" or "true"). Null, array and object values are not indexed. Like
+// the spatial queries, it needs a built index; the attribute index is
+// rebuilt by Build and kept current by SetProperties.
+func (idx *Index) QueryByProperty(key, value string) (*ObjectList, error) {
+	if err := idx.rebuildIfDue(true); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if err := idx.requireBuilt(); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(idx.indexedProps, key) {
+		return nil, fmt.Errorf("%w: property %q is not indexed", ErrInvalid, key)
+	}
+
+	ids := idx.props[key][value]
+	list := &ObjectList{Objects: make([]*SpatialObject, 0, len(ids))}
+	for _, id := range ids {
+		if cobj := C.urbis_get(idx.ptr, C.uint64_t(id)); cobj != nil {
+			list.Objects = append(list.Objects, convertSpatialObject(cobj))
+		}
+	}
+	list.Count = uint64(len(list.Objects))
+	return list, nil
+}
+
+// IndexedProperties returns the property keys QueryByProperty accepts
+func (idx *Index) IndexedProperties() []string {
+	return slices.Clone(idx.indexedProps)
+}
+
+// buildPropertyIndex indexes the configured keys of every object. The
+// caller must hold the write lock.
+func (idx *Index) buildPropertyIndex() error {
+	if len(idx.indexedProps) == 0 {
+		return nil
+	}
+
+	result := C.urbis_query_changed_since(idx.ptr, C.int64_t(math.MinInt64))
+	if result == nil {
+		return ErrAlloc
+	}
+	defer C.urbis_object_list_free(result)
+
+	props := make(propertyIndex, len(idx.indexedProps))
+	for _, key := range idx.indexedProps {
+		props[key] = make(map[string][]uint64)
+	}
+	if result.count > 0 {
+		for _, cobj := range unsafe.Slice(result.objects, result.count) {
+			if cobj.properties == nil || cobj.properties_size == 0 {
+				continue
+			}
+			blob := C.GoBytes(cobj.properties, C.int(cobj.properties_size))
+			props.add(uint64(cobj.id), propertyValues(blob, idx.indexedProps))
+		}
+	}
+	for _, values := range props {
+		for _, ids := range values {
+			slices.Sort(ids)
+		}
+	}
+
+	idx.props = props
+	return nil
+}
+
+// reindexProperties moves an object's entries from its old properties to
+// new ones. The caller must hold the write lock.
+func (idx *Index) reindexProperties(id uint64, before, after []byte) {
+	if idx.props == nil {
+		return
+	}
+	for key, value := range propertyValues(before, idx.indexedProps) {
+		ids := idx.props[key][value]
+		if i, found := slices.BinarySearch(ids, id); found {
+			idx.props[key][value] = slices.Delete(ids, i, i+1)
+		}
+	}
+	for key, value := range propertyValues(after, idx.indexedProps) {
+		ids := idx.props[key][value]
+		if i, found := slices.BinarySearch(ids, id); !found {
+			idx.props[key][value] = slices.Insert(ids, i, id)
+		}
+	}
+}
+
+func (p propertyIndex) add(id uint64, values map[string]string) {
+	for key, value := range values {
+		p[key][value] = append(p[key][value], id)
+	}
+}
+
+// propertyValues extracts the indexable values of keys from a properties
+// blob. Blobs that are not JSON objects have none.
+func propertyValues(blob []byte, keys []string) map[string]string {
+	if len(blob) == 0 || len(keys) == 0 {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(blob, &fields); err != nil {
+		return nil
+	}
+
+	values := make(map[string]string)
+	for _, key := range keys {
+		raw, ok := fields[key]
+		if !ok {
+			continue
+		}
+		raw = bytes.TrimSpace(raw)
+		switch raw[0] {
+		case '"':
+			var s string
+			if json.Unmarshal(raw, &s) == nil {
+				values[key] = s
+			}
+		case 'n', '[', '{':
+			// Null, arrays and objects have no single value to match
+		default:
+			values[key] = string(raw)
+		}
+	}
+	return values
+}
@@ -0,0 +1,54 @@
+package urbis
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQueryByProperty(t *testing.T) {
+	idx, err := NewIndex(&Config{IndexedProperties: []string{"category", "rank"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	err = idx.LoadGeoJSONString(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[0,0]},"properties":{"category":"park","rank":1}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[1,1]},"properties":{"category":"school","rank":2}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[2,2]},"properties":{"category":"park","rank":null}}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.QueryByProperty("category", "park"); !errors.Is(err, ErrNotBuilt) {
+		t.Errorf("unbuilt index: err = %v, want ErrNotBuilt", err)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	parks, err := idx.QueryByProperty("category", "park")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parks.Count != 2 || parks.Objects[0].ID >= parks.Objects[1].ID {
+		t.Fatalf("parks = %+v, want two objects in ID order", parks.Objects)
+	}
+	if ranked, _ := idx.QueryByProperty("rank", "2"); ranked.Count != 1 || ranked.Objects[0].Centroid.X != 1 {
+		t.Errorf("rank 2 = %+v, want the school", ranked.Objects)
+	}
+	if _, err := idx.QueryByProperty("name", "x"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("unindexed key: err = %v, want ErrInvalid", err)
+	}
+
+	// SetProperties keeps the attribute index current without a rebuild
+	first := parks.Objects[0].ID
+	if err := idx.SetProperties(first, []byte(`{"category":"lake"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if parks, _ := idx.QueryByProperty("category", "park"); parks.Count != 1 {
+		t.Errorf("parks after SetProperties = %d, want 1", parks.Count)
+	}
+	if lakes, _ := idx.QueryByProperty("category", "lake"); lakes.Count != 1 || lakes.Objects[0].ID != first {
+		t.Errorf("lakes = %+v, want object %d", lakes.Objects, first)
+	}
+}
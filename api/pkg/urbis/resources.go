@@ -0,0 +1,203 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// FinalizerPolicy selects what happens when the garbage collector finds an
+// index that was never closed
+type FinalizerPolicy int
+
+const (
+	// FinalizerClose frees the index and counts it in ResourceStats.Finalized (the default)
+	FinalizerClose FinalizerPolicy = iota
+	// FinalizerLog also logs a warning naming where the index was opened
+	FinalizerLog
+	// FinalizerKeep leaves the index open, so it stays in Resources and
+	// OpenIndexes until CloseAll. Use it in tests to make leaks deterministic.
+	FinalizerKeep
+)
+
+// ResourceStats describes the native resources held by open indexes
+type ResourceStats struct {
+	OpenIndexes int    // Indexes opened and not yet closed
+	NativeBytes uint64 // Heap held by the open indexes (Stats.MemoryBytes summed)
+	Finalized   uint64 // Indexes the garbage collector closed because Close was never called
+}
+
+// OpenIndex describes an index that has not been closed
+type OpenIndex struct {
+	Origin      string // file:line of the NewIndex or Load call that opened it
+	MemoryBytes uint64
+}
+
+var registry = struct {
+	sync.Mutex
+	open      map[*handle]struct{}
+	finalized uint64
+	policy    FinalizerPolicy
+}{open: make(map[*handle]struct{})}
+
+// SetFinalizerPolicy changes how indexes that are garbage collected without
+// Close are handled. It affects indexes already open.
+func SetFinalizerPolicy(p FinalizerPolicy) {
+	registry.Lock()
+	registry.policy = p
+	registry.Unlock()
+}
+
+// Resources reports the indexes currently open across the process
+func Resources() ResourceStats {
+	open := openHandles()
+	stats := ResourceStats{OpenIndexes: len(open)}
+	for _, h := range open {
+		stats.NativeBytes += h.memoryBytes()
+	}
+
+	registry.Lock()
+	stats.Finalized = registry.finalized
+	registry.Unlock()
+	return stats
+}
+
+// OpenIndexes lists the open indexes other than those in exclude. Passing
+// the indexes an owner still holds lists the ones it has lost track of.
+func OpenIndexes(exclude ...*Index) []OpenIndex {
+	skip := make(map[*handle]bool, len(exclude))
+	for _, idx := range exclude {
+		skip[idx.handle] = true
+	}
+
+	var list []OpenIndex
+	for _, h := range openHandles() {
+		if !skip[h] {
+			list = append(list, OpenIndex{Origin: h.origin, MemoryBytes: h.memoryBytes()})
+		}
+	}
+	return list
+}
+
+// CheckAllClosed returns an error naming where each still-open index was
+// opened, or nil if every index has been closed. Tests can call it at the
+// end to catch a missing Close.
+func CheckAllClosed() error {
+	open := OpenIndexes()
+	if len(open) == 0 {
+		return nil
+	}
+	origins := make([]string, len(open))
+	for i, o := range open {
+		origins[i] = o.Origin
+	}
+	return fmt.Errorf("%d indexes not closed, opened at: %s", len(open), strings.Join(origins, ", "))
+}
+
+// CloseAll closes every open index in the process and returns how many it
+// closed. Indexes still in use by other goroutines are closed as soon as
+// their current call returns; later calls on them fail with ErrNull or
+// return empty results.
+func CloseAll() int {
+	open := openHandles()
+	closed := 0
+	for _, h := range open {
+		if h.close() {
+			closed++
+		}
+	}
+	return closed
+}
+
+// newIndex wraps a native index, registering it until Close
+func newIndex(ptr *C.UrbisIndex) *Index {
+	h := &handle{ptr: ptr, origin: callerOrigin()}
+
+	registry.Lock()
+	registry.open[h] = struct{}{}
+	registry.Unlock()
+
+	idx := &Index{h}
+	runtime.SetFinalizer(idx, finalizeIndex)
+	return idx
+}
+
+func finalizeIndex(idx *Index) {
+	registry.Lock()
+	policy := registry.policy
+	_, open := registry.open[idx.handle]
+	if open && policy != FinalizerKeep {
+		registry.finalized++
+	}
+	registry.Unlock()
+
+	if !open {
+		return
+	}
+	switch policy {
+	case FinalizerKeep:
+		return
+	case FinalizerLog:
+		slog.Warn("urbis: index garbage collected without Close", "origin", idx.origin)
+	}
+	idx.Close()
+}
+
+// close frees the native index, reporting whether it was open
+func (h *handle) close() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ptr == nil {
+		return false
+	}
+	C.urbis_destroy(h.ptr)
+	h.ptr = nil
+
+	registry.Lock()
+	delete(registry.open, h)
+	registry.Unlock()
+	return true
+}
+
+func (h *handle) memoryBytes() uint64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.ptr == nil {
+		return 0
+	}
+	return uint64(C.urbis_memory_usage(h.ptr))
+}
+
+func openHandles() []*handle {
+	registry.Lock()
+	defer registry.Unlock()
+
+	open := make([]*handle, 0, len(registry.open))
+	for h := range registry.open {
+		open = append(open, h)
+	}
+	return open
+}
+
+// callerOrigin returns the file:line of the first caller outside this package
+func callerOrigin() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/urbis/api/pkg/urbis.") || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
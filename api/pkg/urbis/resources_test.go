@@ -0,0 +1,98 @@
+package urbis
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// openedHere lists the open indexes created by this file
+func openedHere() []OpenIndex {
+	var list []OpenIndex
+	for _, o := range OpenIndexes() {
+		if strings.Contains(o.Origin, "resources_test.go") {
+			list = append(list, o)
+		}
+	}
+	return list
+}
+
+func TestResourceAccounting(t *testing.T) {
+	before := Resources()
+
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		idx.InsertPoint(float64(i), float64(i))
+	}
+
+	during := Resources()
+	if during.OpenIndexes != before.OpenIndexes+1 {
+		t.Errorf("open indexes = %d, want %d", during.OpenIndexes, before.OpenIndexes+1)
+	}
+	if during.NativeBytes <= before.NativeBytes {
+		t.Errorf("native bytes did not grow: %d -> %d", before.NativeBytes, during.NativeBytes)
+	}
+	if len(OpenIndexes(idx)) != len(OpenIndexes())-1 {
+		t.Error("OpenIndexes did not exclude the given index")
+	}
+	if open := openedHere(); len(open) != 1 || open[0].MemoryBytes == 0 {
+		t.Fatalf("openedHere = %+v, want this index", open)
+	}
+
+	idx.Close()
+	idx.Close()
+	if after := Resources(); after.OpenIndexes != before.OpenIndexes {
+		t.Errorf("open indexes after Close = %d, want %d", after.OpenIndexes, before.OpenIndexes)
+	}
+	if len(openedHere()) != 0 {
+		t.Error("closed index still listed")
+	}
+}
+
+func TestFinalizerPolicy(t *testing.T) {
+	defer SetFinalizerPolicy(FinalizerClose)
+
+	leak := func() {
+		if _, err := NewIndex(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Kept: the garbage collector leaves the index open for CloseAll
+	SetFinalizerPolicy(FinalizerKeep)
+	leak()
+	runtime.GC()
+	runtime.GC()
+	if len(openedHere()) != 1 {
+		t.Fatalf("leaked index not reported: %+v", openedHere())
+	}
+	if err := CheckAllClosed(); err == nil || !strings.Contains(err.Error(), "resources_test.go") {
+		t.Errorf("CheckAllClosed = %v, want the leak's origin", err)
+	}
+	if n := CloseAll(); n < 1 {
+		t.Errorf("CloseAll closed %d indexes", n)
+	}
+	if err := CheckAllClosed(); err != nil {
+		t.Errorf("after CloseAll: %v", err)
+	}
+
+	// Closed: the finalizer frees the index and counts it
+	SetFinalizerPolicy(FinalizerClose)
+	finalized := Resources().Finalized
+	leak()
+	deadline := time.Now().Add(2 * time.Second)
+	for Resources().Finalized == finalized && time.Now().Before(deadline) {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if Resources().Finalized != finalized+1 {
+		t.Errorf("finalized = %d, want %d", Resources().Finalized, finalized+1)
+	}
+	if len(openedHere()) != 0 {
+		t.Error("finalized index still open")
+	}
+}
@@ -0,0 +1,152 @@
+package urbis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// PropertyType is the JSON type a property value must have
+type PropertyType int
+
+const (
+	PropertyAny    PropertyType = 0 // Any type
+	PropertyString PropertyType = 1
+	PropertyNumber PropertyType = 2
+	PropertyBool   PropertyType = 3
+	PropertyObject PropertyType = 4
+	PropertyArray  PropertyType = 5
+)
+
+var propertyTypeNames = [...]string{"any", "string", "number", "boolean", "object", "array"}
+
+func (t PropertyType) String() string {
+	if t < 0 || int(t) >= len(propertyTypeNames) {
+		return fmt.Sprintf("PropertyType(%d)", int(t))
+	}
+	return propertyTypeNames[t]
+}
+
+// PropertyRule constrains one key of an object's properties
+type PropertyRule struct {
+	Key string
+	// Required means the key must be present and not null
+	Required bool
+	// Type is checked when the key is present and not null
+	Type PropertyType
+	// NonEmpty rejects empty strings, arrays and objects
+	NonEmpty bool
+}
+
+// ValidateProperties checks a properties blob against rules. With any
+// rules, props must be a JSON object; empty props count as {}. The error
+// wraps ErrInvalid and names the failing key.
+func ValidateProperties(props []byte, rules []PropertyRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	values := map[string]json.RawMessage{}
+	if len(bytes.TrimSpace(props)) > 0 && !bytes.Equal(bytes.TrimSpace(props), []byte("null")) {
+		if err := json.Unmarshal(props, &values); err != nil {
+			return fmt.Errorf("%w: properties are not a JSON object", ErrInvalid)
+		}
+	}
+
+	for _, rule := range rules {
+		value, ok := values[rule.Key]
+		if !ok || bytes.Equal(value, []byte("null")) {
+			if rule.Required {
+				return fmt.Errorf("%w: property %q is required", ErrInvalid, rule.Key)
+			}
+			continue
+		}
+		if rule.Type != PropertyAny && jsonType(value) != rule.Type {
+			return fmt.Errorf("%w: property %q must be a %s", ErrInvalid, rule.Key, rule.Type)
+		}
+		if rule.NonEmpty && jsonEmpty(value) {
+			return fmt.Errorf("%w: property %q must not be empty", ErrInvalid, rule.Key)
+		}
+	}
+	return nil
+}
+
+// validRules reports whether every rule names a key and a known type
+func validRules(rules []PropertyRule) bool {
+	for _, rule := range rules {
+		if rule.Key == "" || rule.Type < PropertyAny || rule.Type > PropertyArray {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonType classifies a JSON value by its first byte
+func jsonType(value json.RawMessage) PropertyType {
+	switch value[0] {
+	case '"':
+		return PropertyString
+	case '{':
+		return PropertyObject
+	case '[':
+		return PropertyArray
+	case 't', 'f':
+		return PropertyBool
+	}
+	return PropertyNumber
+}
+
+// jsonEmpty reports whether a JSON value is "", [] or {}
+func jsonEmpty(value json.RawMessage) bool {
+	switch jsonType(value) {
+	case PropertyString:
+		var s string
+		return json.Unmarshal(value, &s) == nil && s == ""
+	case PropertyArray:
+		var a []json.RawMessage
+		return json.Unmarshal(value, &a) == nil && len(a) == 0
+	case PropertyObject:
+		var o map[string]json.RawMessage
+		return json.Unmarshal(value, &o) == nil && len(o) == 0
+	}
+	return false
+}
+
+// checkBare rejects an object inserted without properties when the schema
+// requires some
+func (idx *Index) checkBare() error {
+	return ValidateProperties(nil, idx.schema)
+}
+
+// checkGeoJSON validates the properties of every feature in a GeoJSON
+// document against the schema before any of it is loaded. Bare geometries
+// have no properties.
+func (idx *Index) checkGeoJSON(data []byte) error {
+	if len(idx.schema) == 0 {
+		return nil
+	}
+
+	var doc struct {
+		Type       string          `json:"type"`
+		Properties json.RawMessage `json:"properties"`
+		Features   []struct {
+			Properties json.RawMessage `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%w: %v", ErrParse, err)
+	}
+
+	switch doc.Type {
+	case "FeatureCollection":
+		for i, f := range doc.Features {
+			if err := ValidateProperties(f.Properties, idx.schema); err != nil {
+				return fmt.Errorf("feature %d: %w", i, err)
+			}
+		}
+		return nil
+	case "Feature":
+		return ValidateProperties(doc.Properties, idx.schema)
+	}
+	return idx.checkBare()
+}
@@ -0,0 +1,75 @@
+package urbis
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateProperties(t *testing.T) {
+	rules := []PropertyRule{
+		{Key: "name", Required: true, Type: PropertyString, NonEmpty: true},
+		{Key: "lanes", Type: PropertyNumber},
+	}
+	tests := []struct {
+		props string
+		fails string // Substring of the error, or "" for valid
+	}{
+		{`{"name": "Park St", "lanes": 2}`, ""},
+		{`{"name": "Park St", "lanes": null}`, ""},
+		{`{"lanes": 2}`, `"name" is required`},
+		{``, `"name" is required`},
+		{`{"name": null}`, `"name" is required`},
+		{`{"name": ""}`, `"name" must not be empty`},
+		{`{"name": 7}`, `"name" must be a string`},
+		{`{"name": "x", "lanes": "two"}`, `"lanes" must be a number`},
+		{`["name"]`, `not a JSON object`},
+	}
+	for _, tt := range tests {
+		err := ValidateProperties([]byte(tt.props), rules)
+		switch {
+		case tt.fails == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.props, err)
+		case tt.fails != "" && (!errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), tt.fails)):
+			t.Errorf("%s: err = %v, want ErrInvalid with %q", tt.props, err, tt.fails)
+		}
+	}
+}
+
+func TestPropertySchema(t *testing.T) {
+	idx, err := NewIndex(&Config{PropertySchema: []PropertyRule{{Key: "name", Required: true, NonEmpty: true}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	err = idx.LoadGeoJSONString(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 1]}, "properties": {"name": "a"}},
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [2, 2]}, "properties": {"name": ""}}]}`)
+	if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), "feature 1") {
+		t.Errorf("load with a bad feature: err = %v, want ErrInvalid naming feature 1", err)
+	}
+	if idx.Count() != 0 {
+		t.Errorf("a rejected load left %d objects", idx.Count())
+	}
+
+	if _, err := idx.InsertPoint(1, 1); !errors.Is(err, ErrInvalid) {
+		t.Errorf("insert without properties: err = %v, want ErrInvalid", err)
+	}
+
+	if err := idx.LoadGeoJSONString(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 1]}, "properties": {"name": "a"}}`); err != nil {
+		t.Fatal(err)
+	}
+	objs, err := idx.QueryChangedSince(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.SetProperties(objs.Objects[0].ID, []byte(`{"other": 1}`)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("set properties without name: err = %v, want ErrInvalid", err)
+	}
+
+	if _, err := NewIndex(&Config{PropertySchema: []PropertyRule{{Key: ""}}}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("rule without key: err = %v, want ErrInvalid", err)
+	}
+}
@@ -0,0 +1,59 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Storage is the medium the seek and transfer cost estimates assume
+type Storage int
+
+const (
+	StorageRotational Storage = 0 // Hard disk: 8 ms per seek, 150 MB/s (the default)
+	StorageSSD        Storage = 1 // Flash: 0.1 ms per seek, 2000 MB/s
+	StorageCustom     Storage = 2 // SeekCostModel.SeekTime and TransferRate
+)
+
+// SeekCostModel turns the pages a query touches into an estimated read
+// time: one seek to reach the first track, one per track change, and the
+// transfer time of every page. FindAdjacentPages reports the estimate and
+// AutoTuneConfig ranks page capacities by it.
+type SeekCostModel struct {
+	Storage Storage
+	// SeekTime and TransferRate (MB/s) are used only with StorageCustom
+	SeekTime     time.Duration
+	TransferRate float64
+}
+
+// Validate checks the storage kind and, for StorageCustom, that SeekTime is
+// not negative and TransferRate is positive and finite. NewIndex returns
+// its error, wrapping ErrInvalid.
+func (m SeekCostModel) Validate() error {
+	switch m.Storage {
+	case StorageRotational, StorageSSD:
+		return nil
+	case StorageCustom:
+		if m.SeekTime < 0 {
+			return fmt.Errorf("%w: seek time %v is negative", ErrInvalid, m.SeekTime)
+		}
+		if !(m.TransferRate > 0) || math.IsInf(m.TransferRate, 0) {
+			return fmt.Errorf("%w: transfer rate %v MB/s is not a positive number", ErrInvalid, m.TransferRate)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: unknown storage kind %d", ErrInvalid, m.Storage)
+}
+
+// toC converts the model for UrbisConfig
+func (m SeekCostModel) toC() C.UrbisSeekCostModel {
+	return C.UrbisSeekCostModel{
+		kind:          C.UrbisStorageKind(m.Storage),
+		seek_ms:       C.double(float64(m.SeekTime) / float64(time.Millisecond)),
+		transfer_mb_s: C.double(m.TransferRate),
+	}
+}
@@ -0,0 +1,60 @@
+package urbis
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSeekCostModel(t *testing.T) {
+	region := MBR{MinX: 0, MinY: 0, MaxX: 29, MaxY: 29}
+	find := func(model SeekCostModel) *PageList {
+		t.Helper()
+		config := DefaultConfig()
+		config.PageCapacity = 8
+		config.SeekCost = model
+		idx, err := NewIndex(&config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer idx.Close()
+
+		for i := 0; i < 900; i++ {
+			idx.InsertPoint(float64(i%30), float64(i/30))
+		}
+		if err := idx.Build(); err != nil {
+			t.Fatal(err)
+		}
+		pages, err := idx.FindAdjacentPages(region)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pages
+	}
+
+	hdd := find(SeekCostModel{})
+	ssd := find(SeekCostModel{Storage: StorageSSD})
+	if hdd.EstimatedSeeks != ssd.EstimatedSeeks || !(hdd.EstimatedCostMs > 10*ssd.EstimatedCostMs) {
+		t.Errorf("rotational %d seeks %.3f ms, SSD %d seeks %.3f ms", hdd.EstimatedSeeks, hdd.EstimatedCostMs, ssd.EstimatedSeeks, ssd.EstimatedCostMs)
+	}
+
+	// 4.096 MB/s reads one 4 KB page per millisecond
+	custom := find(SeekCostModel{Storage: StorageCustom, SeekTime: 2 * time.Millisecond, TransferRate: 4.096})
+	want := float64(2*(custom.EstimatedSeeks+1) + custom.Count)
+	if math.Abs(custom.EstimatedCostMs-want) > 1e-9 {
+		t.Errorf("custom cost = %v ms, want %v", custom.EstimatedCostMs, want)
+	}
+
+	for _, bad := range []SeekCostModel{
+		{Storage: StorageCustom, SeekTime: time.Millisecond},
+		{Storage: StorageCustom, SeekTime: -time.Millisecond, TransferRate: 100},
+		{Storage: 7},
+	} {
+		config := DefaultConfig()
+		config.SeekCost = bad
+		if _, err := NewIndex(&config); !errors.Is(err, ErrInvalid) {
+			t.Errorf("NewIndex with %+v: err = %v, want ErrInvalid", bad, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package urbis
+
+/*
+#include <stdlib.h>
+#include "urbis.h"
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+)
+
+// savedSettings is the part of Config that Save writes into the file, so
+// Load gives back an index that checks inserts as the saved one did.
+// Config.ValidBounds is saved by the library itself.
+type savedSettings struct {
+	CRS                 int            `json:"crs,omitempty"`
+	PolygonValidation   ValidationMode `json:"polygon_validation,omitempty"`
+	ValidationTolerance float64        `json:"validation_tolerance,omitempty"`
+	PropertySchema      []PropertyRule `json:"property_schema,omitempty"`
+}
+
+// encodeSettings returns s as Save stores it, nil when every setting is
+// the default. It fails with ErrInvalid if s does not fit in a file
+// header.
+func encodeSettings(s savedSettings) ([]byte, error) {
+	if s.CRS == CRSUnspecified && s.PolygonValidation == ValidationReject &&
+		s.ValidationTolerance == 0 && len(s.PropertySchema) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if len(data) > C.URBIS_MAX_SETTINGS {
+		return nil, fmt.Errorf("%w: saved settings take %d bytes, more than the %d a file holds",
+			ErrInvalid, len(data), C.URBIS_MAX_SETTINGS)
+	}
+	return data, nil
+}
+
+// settings returns the saved settings of the handle
+func (h *handle) settings() savedSettings {
+	return savedSettings{
+		CRS:                 h.crs,
+		PolygonValidation:   h.validation,
+		ValidationTolerance: h.tolerance,
+		PropertySchema:      h.schema,
+	}
+}
+
+// storeSettings hands the settings to the library for the next save or
+// sync. The caller holds the write lock.
+func (h *handle) storeSettings() error {
+	data, err := encodeSettings(h.settings())
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return toError(C.urbis_set_settings(h.ptr, nil, 0))
+	}
+	cdata := C.CBytes(data)
+	defer C.free(cdata)
+	return toError(C.urbis_set_settings(h.ptr, cdata, C.size_t(len(data))))
+}
+
+// restoreSettings applies the settings and valid bounds a loaded file was
+// saved with. Settings that do not parse or that NewIndex would refuse
+// fail with ErrIO, as any other damage to the file does.
+func (h *handle) restoreSettings() error {
+	var bounds C.MBR
+	if C.urbis_valid_bounds(h.ptr, &bounds) {
+		h.validBounds = &MBR{
+			MinX: float64(bounds.min_x),
+			MinY: float64(bounds.min_y),
+			MaxX: float64(bounds.max_x),
+			MaxY: float64(bounds.max_y),
+		}
+	}
+
+	var size C.size_t
+	data := C.urbis_get_settings(h.ptr, &size)
+	if data == nil || size == 0 {
+		return nil
+	}
+	var s savedSettings
+	if err := json.Unmarshal(C.GoBytes(unsafe.Pointer(data), C.int(size)), &s); err != nil {
+		return fmt.Errorf("%w: saved settings are not readable: %v", ErrIO, err)
+	}
+	if !IsSupportedCRS(s.CRS) || s.PolygonValidation < ValidationReject || s.PolygonValidation > ValidationOff ||
+		!validRules(s.PropertySchema) || s.ValidationTolerance < 0 || !isFinite(s.ValidationTolerance) {
+		return fmt.Errorf("%w: saved settings are not valid", ErrIO)
+	}
+	h.crs = s.CRS
+	h.validation = s.PolygonValidation
+	h.tolerance = s.ValidationTolerance
+	h.schema = s.PropertySchema
+	return nil
+}
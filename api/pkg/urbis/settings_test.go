@@ -0,0 +1,132 @@
+package urbis
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSavedSettings(t *testing.T) {
+	schema := []PropertyRule{{Key: "name", Required: true, Type: PropertyString}}
+	idx, err := NewIndex(&Config{
+		CRS:                 CRSWGS84,
+		PolygonValidation:   ValidationReport,
+		ValidationTolerance: 1e-6,
+		PropertySchema:      schema,
+		ValidBounds:         kolkata,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.LoadGeoJSONString(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [88.36, 22.57]}, "properties": {"name": "a"}}`); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "index.dat")
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	idx.Close()
+
+	idx, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	if idx.CRS() != CRSWGS84 || idx.PolygonValidation() != ValidationReport || idx.ValidationTolerance() != 1e-6 {
+		t.Errorf("loaded CRS %d, validation %d, tolerance %v; want %d, %d, 1e-6",
+			idx.CRS(), idx.PolygonValidation(), idx.ValidationTolerance(), CRSWGS84, ValidationReport)
+	}
+	if !slices.Equal(idx.schema, schema) {
+		t.Errorf("loaded schema %+v, want %+v", idx.schema, schema)
+	}
+
+	// The loaded index still refuses what the saved one refused
+	if _, err := idx.InsertPoint(88.3, 22.5); !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), "required") {
+		t.Errorf("insert without a name: err = %v, want the schema to refuse it", err)
+	}
+	res, err := idx.LoadGeoJSONReader(strings.NewReader(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [22.57, 88.36]}, "properties": {"name": "swapped"}}` + "\n"))
+	if err != nil || res.OutOfBounds != 1 || res.Loaded != 0 {
+		t.Errorf("load outside the valid bounds = %+v, %v; want it left out", res, err)
+	}
+	if idx.Count() != 1 {
+		t.Errorf("count = %d, want 1", idx.Count())
+	}
+
+	// Compacting and saving again keeps them
+	if _, err := idx.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := idx.SaveBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := LoadBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close()
+	if again.CRS() != CRSWGS84 || again.validBounds == nil || *again.validBounds != kolkata {
+		t.Errorf("second load: CRS %d, valid bounds %v", again.CRS(), again.validBounds)
+	}
+	if _, err := again.InsertPoint(88.3, 22.5); !errors.Is(err, ErrInvalid) {
+		t.Errorf("insert without a name after a second load: err = %v, want ErrInvalid", err)
+	}
+
+	// A schema too large for the file is refused up front
+	var big []PropertyRule
+	for i := 0; i < 200; i++ {
+		big = append(big, PropertyRule{Key: strings.Repeat("k", 40) + string(rune('a'+i%26)), Required: true})
+	}
+	if _, err := NewIndex(&Config{PropertySchema: big}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("oversized schema: err = %v, want ErrInvalid", err)
+	}
+}
+
+func TestLoadWithConfig(t *testing.T) {
+	config := &Config{SnapPrecision: 1, IndexedProperties: []string{"name"}, CRS: CRSWGS84}
+	idx, err := NewIndex(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.LoadGeoJSONString(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [88.36, 22.57]}, "properties": {"name": "a"}}`); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "index.dat")
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	idx.Close()
+
+	// The file's settings win over the config's
+	idx, err = LoadWithConfig(path, &Config{SnapPrecision: 1, IndexedProperties: []string{"name"}, DataPath: "/nonexistent/elsewhere.dat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	if idx.CRS() != CRSWGS84 || idx.Count() != 1 {
+		t.Errorf("loaded CRS %d, count %d; want %d, 1", idx.CRS(), idx.Count(), CRSWGS84)
+	}
+	if b := idx.Bounds(); b.MinX != 88 || b.MaxY != 23 {
+		t.Errorf("loaded bounds = %+v, want the snapped point", b)
+	}
+	if _, err := idx.QueryByProperty("name", "b"); err != nil {
+		t.Errorf("QueryByProperty on the loaded index: %v", err)
+	}
+	id, err := idx.InsertPoint(10.4, 10.6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj, _ := idx.Get(id); obj.Centroid != (Point{X: 10, Y: 11}) {
+		t.Errorf("inserted point = %+v, want it snapped to (10, 11)", obj.Centroid)
+	}
+	if err := idx.Sync(); err != nil {
+		t.Errorf("Sync to the loaded file: %v", err)
+	}
+
+	if _, err := LoadWithConfig(filepath.Join(t.TempDir(), "missing.dat"), config); !errors.Is(err, ErrIO) {
+		t.Errorf("missing file: err = %v, want ErrIO", err)
+	}
+}
@@ -0,0 +1,47 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+// Snapshot is a read-only view of every object in an index as it stood at
+// one instant. Inserts, updates and removals made on the index afterwards
+// do not show through.
+//
+// A snapshot is a deep copy held in Go memory: while it is referenced it
+// costs about as much as the index's geometry and properties together, on
+// top of the index itself. Drop it as soon as the scan is done.
+type Snapshot struct {
+	Objects []*SpatialObject // In ascending ID order
+	TakenAt time.Time
+}
+
+// Snapshot copies every object in the index. The index need not be built.
+// Writers are held off only while the copy is made, not while the snapshot
+// is read.
+func (idx *Index) Snapshot() (*Snapshot, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if idx.ptr == nil {
+		return nil, ErrNull
+	}
+
+	// Every object was modified at or after the earliest representable time
+	result := C.urbis_query_changed_since(idx.ptr, C.int64_t(math.MinInt64))
+	if result == nil {
+		return nil, ErrAlloc
+	}
+	defer C.urbis_object_list_free(result)
+
+	snap := &Snapshot{Objects: convertObjectList(result).Objects, TakenAt: time.Now()}
+	slices.SortFunc(snap.Objects, func(a, b *SpatialObject) int { return cmp.Compare(a.ID, b.ID) })
+	return snap, nil
+}
@@ -0,0 +1,40 @@
+package urbis
+
+import "testing"
+
+func TestSnapshotIgnoresLaterWrites(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 5; i++ {
+		if _, err := idx.InsertPoint(float64(i), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snap, err := idx.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := idx.InsertPoint(9, 9); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Remove(snap.Objects[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(snap.Objects) != 5 {
+		t.Fatalf("snapshot has %d objects, want 5", len(snap.Objects))
+	}
+	for i, obj := range snap.Objects {
+		if i > 0 && obj.ID <= snap.Objects[i-1].ID {
+			t.Errorf("objects not in ID order: %d after %d", obj.ID, snap.Objects[i-1].ID)
+		}
+		if obj.Point == nil || obj.Point.X != float64(i) {
+			t.Errorf("object %d: got %+v, want point at x=%d", obj.ID, obj.Point, i)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+
+// TreeNode is one node of the KD-tree or the page quadtree. KD-tree nodes
+// each hold one object's centroid and split the plane at it; quadtree
+// nodes are cells holding pages.
+type TreeNode struct {
+	Depth  int // 0 for the root
+	Parent int // Index of the parent in the returned slice, -1 for the root
+	// Bounds is the extent of the subtree's centroids for the KD-tree and
+	// the node's cell for the quadtree
+	Bounds      MBR
+	ObjectCount uint64 // Objects in the subtree
+	ItemCount   uint64 // Entries in the subtree: objects (KD-tree) or pages (quadtree)
+	// SplitAxis is 0 for a split along x and 1 along y. It is -1 for
+	// KD-tree leaves and every quadtree node.
+	SplitAxis  int
+	SplitValue float64 // Coordinate of the split line along SplitAxis
+	// Leaf is set for nodes without children. A node whose children were
+	// cut off by maxDepth is not a leaf.
+	Leaf bool
+}
+
+// GetTreeStructure returns the nodes of the KD-tree (StructureKDTree) or
+// the page quadtree (StructureQuadtree), each parent before its children
+// in depth-first order. Only the top maxDepth levels are returned, or
+// every level when maxDepth is 0; counts still cover whole subtrees. The
+// KD-tree has one node per object, so limit the depth on large indexes.
+//
+// The index must be built. The quadtree has no nodes when the index was
+// configured without one or holds no objects.
+func (idx *Index) GetTreeStructure(s Structure, maxDepth int) ([]TreeNode, error) {
+	if (s != StructureKDTree && s != StructureQuadtree) || maxDepth < 0 {
+		return nil, ErrInvalid
+	}
+
+	if err := idx.rebuildIfDue(true); err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if err := idx.requireBuilt(); err != nil {
+		return nil, err
+	}
+
+	count := C.urbis_list_tree_nodes(idx.ptr, C.SpatialStructure(s), C.size_t(maxDepth), nil, 0)
+	nodes := make([]TreeNode, 0, count)
+	if count == 0 {
+		return nodes, nil
+	}
+	cnodes := make([]C.UrbisTreeNode, count)
+	count = C.urbis_list_tree_nodes(idx.ptr, C.SpatialStructure(s), C.size_t(maxDepth), &cnodes[0], count)
+
+	for _, n := range cnodes[:count] {
+		nodes = append(nodes, TreeNode{
+			Depth:  int(n.depth),
+			Parent: int(n.parent),
+			Bounds: MBR{
+				MinX: float64(n.bounds.min_x),
+				MinY: float64(n.bounds.min_y),
+				MaxX: float64(n.bounds.max_x),
+				MaxY: float64(n.bounds.max_y),
+			},
+			ObjectCount: uint64(n.object_count),
+			ItemCount:   uint64(n.item_count),
+			SplitAxis:   int(n.split_dim),
+			SplitValue:  float64(n.split_value),
+			Leaf:        bool(n.is_leaf),
+		})
+	}
+	return nodes, nil
+}
@@ -0,0 +1,64 @@
+package urbis
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetTreeStructure(t *testing.T) {
+	idx, err := NewIndex(&Config{PageCapacity: 4, EnableQuadtree: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	for i := 0; i < 64; i++ {
+		idx.InsertPoint(float64(i%8), float64(i/8))
+	}
+	if _, err := idx.GetTreeStructure(StructureKDTree, 0); !errors.Is(err, ErrNotBuilt) {
+		t.Errorf("unbuilt index: err = %v, want ErrNotBuilt", err)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.GetTreeStructure(StructureScan, 0); !errors.Is(err, ErrInvalid) {
+		t.Errorf("scan structure: err = %v, want ErrInvalid", err)
+	}
+
+	kd, err := idx.GetTreeStructure(StructureKDTree, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kd) != 64 || kd[0].Parent != -1 || kd[0].ObjectCount != 64 {
+		t.Fatalf("KD-tree: %d nodes, root %+v", len(kd), kd[0])
+	}
+	for i, n := range kd[1:] {
+		parent := kd[n.Parent]
+		if n.Parent > i || n.Depth != parent.Depth+1 || n.ObjectCount >= parent.ObjectCount {
+			t.Fatalf("node %d %+v under %+v", i+1, n, parent)
+		}
+		if n.Leaf != (n.SplitAxis == -1) {
+			t.Errorf("node %d: leaf %v with split axis %d", i+1, n.Leaf, n.SplitAxis)
+		}
+	}
+
+	top, err := idx.GetTreeStructure(StructureKDTree, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 3 || top[1].Leaf || top[1].ObjectCount+top[2].ObjectCount != 63 {
+		t.Errorf("depth-limited KD-tree: %+v", top)
+	}
+
+	quad, err := idx.GetTreeStructure(StructureQuadtree, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph, err := idx.GetPageGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root := quad[0]; root.ObjectCount != 64 || root.ItemCount != uint64(len(graph.Nodes)) || root.SplitAxis != -1 {
+		t.Errorf("quadtree root %+v, want 64 objects in %d pages", root, len(graph.Nodes))
+	}
+}
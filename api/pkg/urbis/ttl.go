@@ -0,0 +1,96 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+import "time"
+
+// SetTTL makes an object expire ttl from now: the first SweepExpired after
+// that removes it. A ttl of zero or less clears the object's expiry.
+// Expiries are kept in memory only; Save does not write them, and an index
+// loaded from a file starts with none.
+func (idx *Index) SetTTL(objectID uint64, ttl time.Duration) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return err
+	}
+	if C.urbis_get(idx.ptr, C.uint64_t(objectID)) == nil {
+		return ErrNotFound
+	}
+
+	if ttl <= 0 {
+		delete(idx.expires, objectID)
+		return nil
+	}
+	idx.setExpiry(objectID, ttl)
+	return nil
+}
+
+// setExpiry makes an object expire ttl from now. The caller must hold the
+// write lock.
+func (idx *Index) setExpiry(objectID uint64, ttl time.Duration) {
+	if idx.expires == nil {
+		idx.expires = make(map[uint64]time.Time)
+	}
+	idx.expires[objectID] = time.Now().Add(ttl)
+}
+
+// Expiry returns when an object expires, or false when it has no TTL
+func (idx *Index) Expiry(objectID uint64) (time.Time, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	at, ok := idx.expires[objectID]
+	return at, ok
+}
+
+// SweepExpired removes every object whose TTL has passed and returns how
+// many it removed. It holds the write lock throughout, so queries never see
+// a half-swept index. An index that was built is rebuilt after the removals
+// and stays queryable.
+func (idx *Index) SweepExpired() (uint64, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return 0, err
+	}
+	if len(idx.expires) == 0 {
+		return 0, nil
+	}
+
+	built := bool(C.urbis_is_built(idx.ptr))
+	now := time.Now()
+	var removed uint64
+	for id, at := range idx.expires {
+		if at.After(now) {
+			continue
+		}
+		if toError(C.urbis_remove(idx.ptr, C.uint64_t(id))) == nil {
+			removed++
+		}
+		delete(idx.expires, id)
+	}
+
+	if removed == 0 || !built {
+		return removed, nil
+	}
+	if err := toError(C.urbis_build(idx.ptr)); err != nil {
+		return removed, err
+	}
+	return removed, idx.buildPropertyIndex()
+}
+
+// forgetRemoved drops the expiries of objects that are no longer in the
+// index, so an ID inserted again later does not inherit one. The caller
+// must hold the write lock.
+func (idx *Index) forgetRemoved() {
+	for id := range idx.expires {
+		if C.urbis_get(idx.ptr, C.uint64_t(id)) == nil {
+			delete(idx.expires, id)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package urbis
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSweepExpired(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	var ids []uint64
+	for i := 0; i < 4; i++ {
+		id, err := idx.InsertPoint(float64(i), float64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if err := idx.SetTTL(999, time.Second); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown object: err = %v, want ErrNotFound", err)
+	}
+	for _, id := range ids[:2] {
+		if err := idx.SetTTL(id, time.Nanosecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx.SetTTL(ids[2], time.Hour)
+	if _, ok := idx.Expiry(ids[3]); ok {
+		t.Errorf("object %d has an expiry without a TTL", ids[3])
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Millisecond)
+	removed, err := idx.SweepExpired()
+	if err != nil || removed != 2 {
+		t.Fatalf("SweepExpired = %d, %v, want 2", removed, err)
+	}
+	if idx.Count() != 2 || !idx.IsBuilt() {
+		t.Errorf("after sweep: %d objects, built %v", idx.Count(), idx.IsBuilt())
+	}
+	if _, err := idx.Get(ids[0]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expired object %d still present", ids[0])
+	}
+	if at, ok := idx.Expiry(ids[2]); !ok || !at.After(time.Now()) {
+		t.Errorf("object %d expiry = %v, %v", ids[2], at, ok)
+	}
+
+	if err := idx.SetTTL(ids[2], 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := idx.Expiry(ids[2]); ok {
+		t.Error("a zero TTL did not clear the expiry")
+	}
+	if removed, err := idx.SweepExpired(); err != nil || removed != 0 {
+		t.Errorf("second sweep = %d, %v", removed, err)
+	}
+
+	idx.MarkReadOnly()
+	if _, err := idx.SweepExpired(); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("read-only sweep: err = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestInsertWithTTL(t *testing.T) {
+	idx, err := NewIndex(&Config{DedupPoints: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	first, err := idx.InsertPointWith(1, 1, InsertOptions{TTL: time.Hour})
+	if err != nil || first.Duplicate {
+		t.Fatalf("InsertPointWith = %+v, %v", first, err)
+	}
+	at, ok := idx.Expiry(first.ID)
+	if !ok || at.Before(time.Now().Add(59*time.Minute)) {
+		t.Fatalf("expiry = %v, %v; want an hour from now", at, ok)
+	}
+
+	// A point counted against an existing one leaves that object's expiry
+	dup, err := idx.InsertPointWith(1, 1, InsertOptions{TTL: time.Nanosecond})
+	if err != nil || !dup.Duplicate || dup.ID != first.ID {
+		t.Fatalf("duplicate insert = %+v, %v; want a duplicate of %d", dup, err, first.ID)
+	}
+	if again, _ := idx.Expiry(first.ID); !again.Equal(at) {
+		t.Errorf("duplicate insert moved the expiry from %v to %v", at, again)
+	}
+	time.Sleep(time.Millisecond)
+	if removed, err := idx.SweepExpired(); err != nil || removed != 0 {
+		t.Errorf("SweepExpired = %d, %v; want nothing removed", removed, err)
+	}
+
+	line := []Point{{X: 0, Y: 0}, {X: 2, Y: 2}}
+	ins, err := idx.InsertLineStringWith(line, InsertOptions{ID: 42, TTL: time.Hour})
+	if err != nil || ins.ID != 42 || ins.MBR != (MBR{MaxX: 2, MaxY: 2}) {
+		t.Fatalf("InsertLineStringWith = %+v, %v; want ID 42 and its MBR", ins, err)
+	}
+	at, _ = idx.Expiry(42)
+
+	// A refused insert sets no expiry and leaves the object holding the ID alone
+	if _, err := idx.InsertPolygonWith([]Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 0}}, InsertOptions{ID: 42, TTL: time.Nanosecond}); !errors.Is(err, ErrIDInUse) {
+		t.Errorf("insert under a taken ID: err = %v, want ErrIDInUse", err)
+	}
+	if again, _ := idx.Expiry(42); !again.Equal(at) {
+		t.Errorf("refused insert moved the expiry from %v to %v", at, again)
+	}
+	if _, err := idx.InsertLineStringWith([]Point{{X: 0, Y: 0}}, InsertOptions{TTL: time.Hour}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("one-point line: err = %v, want ErrInvalid", err)
+	}
+	if idx.Count() != 2 || len(idx.expires) != 2 {
+		t.Errorf("%d objects and %d expiries, want 2 of each", idx.Count(), len(idx.expires))
+	}
+}
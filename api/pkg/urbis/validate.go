@@ -0,0 +1,189 @@
+package urbis
+
+import (
+	"fmt"
+	"math"
+)
+
+// ValidationMode selects how InsertPolygon treats invalid rings
+type ValidationMode int
+
+const (
+	ValidationReject ValidationMode = 0 // Fail with ErrInvalid (the default)
+	ValidationReport ValidationMode = 1 // Insert anyway; callers check ValidatePolygon
+	ValidationOff    ValidationMode = 2 // Skip validation
+)
+
+// PolygonValidation returns how the index validates inserted polygons, as
+// set by Config.PolygonValidation
+func (idx *Index) PolygonValidation() ValidationMode {
+	return idx.validation
+}
+
+// ValidationTolerance returns the distance within which the index treats
+// ring vertices as coincident, as set by Config.ValidationTolerance
+func (idx *Index) ValidationTolerance() float64 {
+	return idx.tolerance
+}
+
+// ValidatePolygon checks that points form a valid exterior ring: finite
+// coordinates, at least three distinct vertices, closed (the last point
+// repeats the first), no self-intersections and counter-clockwise
+// orientation. It returns false and a reason for the first problem found.
+// Repeated consecutive vertices are allowed. The self-intersection test
+// compares every pair of edges, so cost grows quadratically with the ring.
+func ValidatePolygon(points []Point) (bool, string) {
+	return ValidatePolygonWithin(points, 0)
+}
+
+// ValidatePolygonWithin checks a ring as ValidatePolygon does, but treats
+// points no more than tolerance apart as the same point: a ring whose last
+// point is that close to the first counts as closed, and near-coincident
+// consecutive vertices count as repeats. The remaining checks run on the
+// ring as snapRing would store it.
+func ValidatePolygonWithin(points []Point, tolerance float64) (bool, string) {
+	if !pointsFinite(points) {
+		return false, "ring has a non-finite coordinate"
+	}
+	n := len(points)
+	if n < 4 {
+		return false, fmt.Sprintf("ring needs at least 4 points (3 vertices plus the closing point), got %d", n)
+	}
+	if !near(points[0], points[n-1], tolerance) {
+		return false, fmt.Sprintf("ring is not closed: first point %s differs from last point %s",
+			formatPoint(points[0]), formatPoint(points[n-1]))
+	}
+
+	ring := distinctVertices(points[:n-1], tolerance)
+	if len(ring) < 3 {
+		return false, fmt.Sprintf("ring has %d distinct vertices, need at least 3", len(ring))
+	}
+	if a, b, c, d, ok := findSelfIntersection(ring); ok {
+		return false, fmt.Sprintf("ring self-intersects: edge %s-%s meets edge %s-%s",
+			formatPoint(a), formatPoint(b), formatPoint(c), formatPoint(d))
+	}
+
+	switch area := signedArea(ring); {
+	case area == 0:
+		return false, "ring has zero area"
+	case area < 0:
+		return false, "ring is clockwise; exterior rings must be counter-clockwise"
+	}
+	return true, ""
+}
+
+// distinctVertices drops consecutive repeats from an open ring, including
+// a last vertex equal to the first. Points within tolerance of the vertex
+// kept before them count as repeats.
+func distinctVertices(points []Point, tolerance float64) []Point {
+	ring := make([]Point, 0, len(points))
+	for _, p := range points {
+		if len(ring) == 0 || !near(ring[len(ring)-1], p, tolerance) {
+			ring = append(ring, p)
+		}
+	}
+	for len(ring) > 1 && near(ring[len(ring)-1], ring[0], tolerance) {
+		ring = ring[:len(ring)-1]
+	}
+	return ring
+}
+
+// snapRing returns a closed ring that passed ValidatePolygonWithin as it
+// is stored: near-coincident vertices merged into the first of them and the
+// last point set exactly to the first
+func snapRing(points []Point, tolerance float64) []Point {
+	ring := distinctVertices(points[:len(points)-1], tolerance)
+	return append(ring, ring[0])
+}
+
+// near reports whether p and q are no more than tolerance apart
+func near(p, q Point, tolerance float64) bool {
+	if tolerance == 0 {
+		return p == q
+	}
+	return math.Hypot(p.X-q.X, p.Y-q.Y) <= tolerance
+}
+
+// findSelfIntersection returns the first pair of edges of an open ring
+// that touch anywhere other than their shared vertex
+func findSelfIntersection(ring []Point) (Point, Point, Point, Point, bool) {
+	m := len(ring)
+	for i := 0; i < m; i++ {
+		a, b := ring[i], ring[(i+1)%m]
+		for j := i + 1; j < m; j++ {
+			c, d := ring[j], ring[(j+1)%m]
+
+			var hit bool
+			switch {
+			case j == i+1:
+				// Edges a-b and b-d: invalid only if d doubles back along a-b
+				hit = backtracks(a, b, d)
+			case i == 0 && j == m-1:
+				// Edges c-a and a-b
+				hit = backtracks(c, a, b)
+			default:
+				hit = segmentsIntersect(a, b, c, d)
+			}
+			if hit {
+				return a, b, c, d, true
+			}
+		}
+	}
+	return Point{}, Point{}, Point{}, Point{}, false
+}
+
+// backtracks reports whether the path p-q-r turns back on itself, so the
+// edges overlap along a line
+func backtracks(p, q, r Point) bool {
+	return orientation(p, q, r) == 0 && (p.X-q.X)*(r.X-q.X)+(p.Y-q.Y)*(r.Y-q.Y) > 0
+}
+
+// segmentsIntersect reports whether segments p1-p2 and p3-p4 share any point
+func segmentsIntersect(p1, p2, p3, p4 Point) bool {
+	o1 := orientation(p1, p2, p3)
+	o2 := orientation(p1, p2, p4)
+	o3 := orientation(p3, p4, p1)
+	o4 := orientation(p3, p4, p2)
+
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+	return (o1 == 0 && onSegment(p1, p2, p3)) ||
+		(o2 == 0 && onSegment(p1, p2, p4)) ||
+		(o3 == 0 && onSegment(p3, p4, p1)) ||
+		(o4 == 0 && onSegment(p3, p4, p2))
+}
+
+// orientation returns the sign of the turn p-q-r: 1 counter-clockwise,
+// -1 clockwise, 0 collinear
+func orientation(p, q, r Point) int {
+	cross := (q.X-p.X)*(r.Y-p.Y) - (q.Y-p.Y)*(r.X-p.X)
+	switch {
+	case cross > 0:
+		return 1
+	case cross < 0:
+		return -1
+	}
+	return 0
+}
+
+// onSegment reports whether r, collinear with p-q, lies within the segment
+func onSegment(p, q, r Point) bool {
+	return r.X >= min(p.X, q.X) && r.X <= max(p.X, q.X) &&
+		r.Y >= min(p.Y, q.Y) && r.Y <= max(p.Y, q.Y)
+}
+
+// signedArea returns the shoelace area of an open ring, positive when
+// counter-clockwise
+func signedArea(ring []Point) float64 {
+	var sum float64
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		sum += p.X*q.Y - q.X*p.Y
+	}
+	return sum / 2
+}
+
+func formatPoint(p Point) string {
+	return fmt.Sprintf("(%g %g)", p.X, p.Y)
+}
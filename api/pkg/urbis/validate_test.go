@@ -0,0 +1,113 @@
+package urbis
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidatePolygon(t *testing.T) {
+	tests := []struct {
+		name   string
+		ring   []Point
+		reason string // substring of the expected reason; "" means valid
+	}{
+		{"square", []Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}, ""},
+		{"repeated vertex", []Point{{0, 0}, {4, 0}, {4, 0}, {4, 4}, {0, 0}}, ""},
+		{"concave", []Point{{0, 0}, {4, 0}, {2, 1}, {4, 4}, {0, 4}, {0, 0}}, ""},
+		{"too few points", []Point{{0, 0}, {4, 0}, {0, 0}}, "at least 4 points"},
+		{"unclosed", []Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}}, "not closed"},
+		{"bowtie", []Point{{0, 0}, {4, 4}, {4, 0}, {0, 4}, {0, 0}}, "self-intersects"},
+		{"touching vertex", []Point{{0, 0}, {4, 0}, {2, 2}, {4, 4}, {0, 4}, {2, 2}, {0, 0}}, "self-intersects"},
+		{"spike", []Point{{0, 0}, {4, 0}, {6, 0}, {4, 0}, {4, 4}, {0, 0}}, "self-intersects"},
+		{"clockwise", []Point{{0, 0}, {0, 4}, {4, 4}, {4, 0}, {0, 0}}, "clockwise"},
+		{"collinear", []Point{{0, 0}, {1, 1}, {2, 2}, {0, 0}}, "self-intersects"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, reason := ValidatePolygon(tt.ring)
+			if tt.reason == "" {
+				if !ok {
+					t.Errorf("rejected valid ring: %s", reason)
+				}
+				return
+			}
+			if ok || !strings.Contains(reason, tt.reason) {
+				t.Errorf("got (%v, %q), want reason containing %q", ok, reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestInsertPolygonValidation(t *testing.T) {
+	bowtie := []Point{{0, 0}, {4, 4}, {4, 0}, {0, 4}, {0, 0}}
+
+	strict, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer strict.Close()
+	_, err = strict.InsertPolygon(bowtie)
+	if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), "self-intersects") {
+		t.Errorf("bowtie insert: got %v, want ErrInvalid with reason", err)
+	}
+	if strict.Count() != 0 {
+		t.Errorf("rejected polygon was stored")
+	}
+
+	config := DefaultConfig()
+	config.PolygonValidation = ValidationReport
+	lenient, err := NewIndex(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lenient.Close()
+	if _, err := lenient.InsertPolygon(bowtie); err != nil {
+		t.Errorf("bowtie insert in report mode: %v", err)
+	}
+
+	config.PolygonValidation = 7
+	if _, err := NewIndex(&config); err != ErrInvalid {
+		t.Errorf("unknown validation mode: got %v, want ErrInvalid", err)
+	}
+}
+
+func TestValidationTolerance(t *testing.T) {
+	// Closed only to 1e-9, with a vertex doubled up to floating-point noise
+	ring := []Point{{0, 0}, {4, 0}, {4, 4}, {4 + 1e-12, 4}, {0, 4}, {1e-9, 0}}
+
+	if ok, _ := ValidatePolygon(ring); ok {
+		t.Fatal("ring closed to 1e-9 passed exact validation")
+	}
+	if ok, reason := ValidatePolygonWithin(ring, 1e-8); !ok {
+		t.Fatalf("ring rejected within 1e-8: %s", reason)
+	}
+
+	config := DefaultConfig()
+	config.ValidationTolerance = 1e-8
+	idx, err := NewIndex(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	id, err := idx.InsertPolygon(ring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := idx.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}
+	if !slices.Equal(obj.Polygon, want) {
+		t.Errorf("stored ring %v, want snapped %v", obj.Polygon, want)
+	}
+
+	config.ValidationTolerance = -1
+	if _, err := NewIndex(&config); !errors.Is(err, ErrInvalid) {
+		t.Errorf("negative tolerance: got %v, want ErrInvalid", err)
+	}
+}
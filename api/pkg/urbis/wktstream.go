@@ -0,0 +1,71 @@
+package urbis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxLineErrors is how many bad lines LoadWKTReader reports one by one
+// before summing up the rest
+const maxLineErrors = 100
+
+// LoadWKTReader loads newline-delimited WKT or EWKT from r, one geometry per
+// line, without buffering the whole input. Blank lines are skipped. A line
+// that fails to parse, or whose SRID does not match the index CRS as in
+// LoadEWKT, is left out and loading goes on. It returns how many objects
+// were loaded and, if any lines failed, an error joining one "line N: ..."
+// error per line, which matches ErrParse or ErrInvalid with errors.Is and
+// lists the lines through Unwrap() []error. A read error, or the index
+// becoming read-only, stops the load and is returned alone.
+func (idx *Index) LoadWKTReader(r io.Reader) (uint64, error) {
+	if err := idx.checkWKTLoad(); err != nil {
+		return 0, err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFeatureSize)
+
+	var loaded uint64
+	var lineErrs []error
+	failed := 0
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		_, err := idx.LoadEWKT(line)
+		switch {
+		case err == nil:
+			loaded++
+		case errors.Is(err, ErrReadOnly):
+			return loaded, err
+		default:
+			if failed++; failed <= maxLineErrors {
+				lineErrs = append(lineErrs, fmt.Errorf("line %d: %w", n, err))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return loaded, err
+	}
+
+	if failed > maxLineErrors {
+		lineErrs = append(lineErrs, fmt.Errorf("%w: %d more lines failed", ErrParse, failed-maxLineErrors))
+	}
+	return loaded, errors.Join(lineErrs...)
+}
+
+// checkWKTLoad fails a WKT load that could not insert anything: into a
+// read-only index, or one whose property schema bare geometries fail
+func (idx *Index) checkWKTLoad() error {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return err
+	}
+	return idx.checkBare()
+}
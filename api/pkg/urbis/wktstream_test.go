@@ -0,0 +1,63 @@
+package urbis
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadWKTReader(t *testing.T) {
+	idx, err := NewIndex(&Config{CRS: CRSWGS84})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	input := strings.Join([]string{
+		"POINT (1 2)",
+		"",
+		"LINESTRING (0 0, 5 5)",
+		"POINT (oops)",
+		"   ",
+		"SRID=4326;POLYGON ((0 0, 4 0, 4 4, 0 0))",
+		"SRID=3857;POINT (3 3)",
+	}, "\n")
+	loaded, err := idx.LoadWKTReader(strings.NewReader(input))
+	if loaded != 3 || idx.Count() != 3 {
+		t.Errorf("loaded %d, count %d, want 3", loaded, idx.Count())
+	}
+	if !errors.Is(err, ErrParse) || !errors.Is(err, ErrInvalid) {
+		t.Fatalf("err = %v, want a parse and an SRID error", err)
+	}
+	lines := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(lines) != 2 || !strings.HasPrefix(lines[0].Error(), "line 4: ") || !strings.HasPrefix(lines[1].Error(), "line 7: ") {
+		t.Errorf("line errors = %v, want lines 4 and 7", lines)
+	}
+
+	if loaded, err := idx.LoadWKTReader(strings.NewReader("\n\nPOINT (9 9)\n")); loaded != 1 || err != nil {
+		t.Errorf("clean input: loaded %d, err %v", loaded, err)
+	}
+
+	idx.MarkReadOnly()
+	if _, err := idx.LoadWKTReader(strings.NewReader("POINT (1 1)")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("read-only index: err = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestLoadWKTReaderSummarizesErrors(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	input := strings.Repeat("NOT WKT\n", maxLineErrors+5)
+	loaded, err := idx.LoadWKTReader(strings.NewReader(input))
+	if loaded != 0 {
+		t.Errorf("loaded %d from garbage", loaded)
+	}
+	lines := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(lines) != maxLineErrors+1 || lines[maxLineErrors].Error() != "parse error: 5 more lines failed" {
+		t.Errorf("%d line errors, last %v", len(lines), lines[len(lines)-1])
+	}
+}
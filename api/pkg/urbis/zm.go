@@ -0,0 +1,189 @@
+package urbis
+
+/*
+#include "urbis.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// Dims says which ordinates beyond X and Y an object's points carry
+type Dims uint8
+
+const (
+	DimZ Dims = C.GEOM_DIM_Z // Points carry Z, e.g. elevation
+	DimM Dims = C.GEOM_DIM_M // Points carry M, a measure
+)
+
+// HasZ reports whether points carry Z
+func (d Dims) HasZ() bool { return d&DimZ != 0 }
+
+// HasM reports whether points carry M
+func (d Dims) HasM() bool { return d&DimM != 0 }
+
+// width is the number of extra ordinates per point
+func (d Dims) width() int {
+	n := 0
+	if d.HasZ() {
+		n++
+	}
+	if d.HasM() {
+		n++
+	}
+	return n
+}
+
+// readZM copies the Z and M values of cobj's vertices into obj.Z and obj.M.
+// The C object also stores the holes of polygons after each exterior; obj
+// leaves them out, so their values are skipped.
+func readZM(obj *SpatialObject, cobj *C.SpatialObject) {
+	dims := Dims(cobj.dims)
+	width := dims.width()
+	if width == 0 || cobj.zm == nil {
+		return
+	}
+	obj.Dims = dims
+
+	count := int(C.spatial_object_vertex_count(cobj)) * width
+	zm := unsafe.Slice((*float64)(unsafe.Pointer(cobj.zm)), count)
+	next := func(n int) {
+		for i := 0; i < n; i++ {
+			if dims.HasZ() {
+				obj.Z, zm = append(obj.Z, zm[0]), zm[1:]
+			}
+			if dims.HasM() {
+				obj.M, zm = append(obj.M, zm[0]), zm[1:]
+			}
+		}
+	}
+
+	switch obj.Type {
+	case GeomPoint:
+		next(1)
+	case GeomLineString:
+		next(len(obj.Line))
+	case GeomPolygon:
+		next(len(obj.Polygon))
+	case GeomMultiPoint:
+		next(len(obj.MultiPoint))
+	case GeomMultiLineString:
+		for _, line := range obj.MultiLine {
+			next(len(line))
+		}
+	case GeomMultiPolygon:
+		mpPtr := (*C.MultiPolygon)(unsafe.Pointer(&cobj.geom[0]))
+		polygons := unsafe.Slice(mpPtr.polygons, mpPtr.count)
+		for i, ring := range obj.MultiPolygon {
+			next(len(ring))
+			for _, n := range unsafe.Slice(polygons[i].hole_counts, polygons[i].num_holes) {
+				zm = zm[int(n)*width:]
+			}
+		}
+	}
+}
+
+// SetZM gives an object's points Z and/or M values. z and m hold one value
+// per vertex, in the order the object stores them: for objects inserted
+// through this package, the order of the points passed to the insert. A
+// polygon loaded with holes lists each hole after its exterior ring. A nil
+// slice leaves that ordinate out, so SetZM(id, nil, nil) makes the object 2D
+// again. Queries are not affected, so the index stays built.
+func (idx *Index) SetZM(objectID uint64, z, m []float64) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := idx.requireWritable(); err != nil {
+		return err
+	}
+	return idx.setZM(objectID, z, m)
+}
+
+// setZM does the work of SetZM. The caller must hold the write lock.
+func (idx *Index) setZM(objectID uint64, z, m []float64) error {
+	if z != nil && m != nil && len(z) != len(m) {
+		return fmt.Errorf("%w: %d Z values but %d M values", ErrInvalid, len(z), len(m))
+	}
+	var dims Dims
+	n := len(m)
+	if z != nil {
+		dims |= DimZ
+		n = len(z)
+	}
+	if m != nil {
+		dims |= DimM
+	}
+
+	values := make([]C.double, 0, n*dims.width())
+	for i := 0; i < n; i++ {
+		if z != nil {
+			values = append(values, C.double(z[i]))
+		}
+		if m != nil {
+			values = append(values, C.double(m[i]))
+		}
+	}
+	var cvalues *C.double
+	if len(values) > 0 {
+		cvalues = &values[0]
+	}
+	err := toError(C.urbis_set_zm(idx.ptr, C.uint64_t(objectID), C.uint8_t(dims), cvalues, C.size_t(len(values))))
+	if errors.Is(err, ErrInvalid) {
+		return fmt.Errorf("%w: %d values do not match the object's vertices", ErrInvalid, n)
+	}
+	return err
+}
+
+// CheckZM checks, before inserting points as a point, linestring or
+// polygon of type typ, that SetZM will accept z and m for the object the
+// insert stores. It fails with ErrInvalid when z and m differ in length or
+// do not hold one value per stored vertex: a polygon ring snapped by
+// Config.ValidationTolerance or a line or ring simplified by
+// Config.SimplifyTolerance can keep fewer vertices than points. Points the
+// insert itself would refuse pass, leaving the insert to report them.
+func (idx *Index) CheckZM(typ GeomType, points []Point, z, m []float64) error {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return idx.checkZM(typ, points, z, m)
+}
+
+// checkZM does the work of CheckZM. The caller must hold the lock.
+func (idx *Index) checkZM(typ GeomType, points []Point, z, m []float64) error {
+	if z == nil && m == nil {
+		return nil
+	}
+	if z != nil && m != nil && len(z) != len(m) {
+		return fmt.Errorf("%w: %d Z values but %d M values", ErrInvalid, len(z), len(m))
+	}
+	n := len(m)
+	if z != nil {
+		n = len(z)
+	}
+
+	stored := len(points)
+	switch typ {
+	case GeomLineString, GeomPolygon:
+		if len(points) < 2 || !pointsFinite(points) {
+			return nil
+		}
+		if typ == GeomPolygon {
+			ring, err := idx.checkRing(points)
+			if err != nil {
+				return nil
+			}
+			points = ring
+		}
+		cpoints := toCPoints(points)
+		// 0 means the count is unknown; the insert or SetZM reports why
+		if c := C.urbis_stored_vertex_count(idx.ptr, C.bool(typ == GeomPolygon), &cpoints[0], C.size_t(len(points))); c > 0 {
+			stored = int(c)
+		}
+	}
+	if n != stored {
+		return fmt.Errorf("%w: %d values for the %d vertices the insert stores", ErrInvalid, n, stored)
+	}
+	return nil
+}
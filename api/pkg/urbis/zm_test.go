@@ -0,0 +1,159 @@
+package urbis
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestZM(t *testing.T) {
+	idx, err := NewIndex(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	if err := idx.LoadWKT("LINESTRING Z (0 0 10, 5 5 20, 10 0 30)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.LoadGeoJSONString(`{"type":"Point","coordinates":[1,2,3,4]}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := idx.QueryRange(MBR{MinX: -1, MinY: -1, MaxX: 11, MaxY: 11})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Count != 2 {
+		t.Fatalf("query found %d objects, want 2", list.Count)
+	}
+	var line, point *SpatialObject
+	for _, obj := range list.Objects {
+		if obj.Type == GeomLineString {
+			line = obj
+		} else {
+			point = obj
+		}
+	}
+	if line.Dims != DimZ || !reflect.DeepEqual(line.Z, []float64{10, 20, 30}) || line.M != nil {
+		t.Errorf("line dims %v, Z %v, M %v", line.Dims, line.Z, line.M)
+	}
+	if point.Dims != DimZ|DimM || !reflect.DeepEqual(point.Z, []float64{3}) || !reflect.DeepEqual(point.M, []float64{4}) {
+		t.Errorf("point dims %v, Z %v, M %v", point.Dims, point.Z, point.M)
+	}
+
+	data, err := idx.ExportGeoJSON(line.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "[5,5,20]") {
+		t.Errorf("export dropped Z: %s", data)
+	}
+
+	id, err := idx.InsertPoint(7, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.SetZM(id, []float64{1, 2}, nil); !errors.Is(err, ErrInvalid) {
+		t.Errorf("two values for one vertex: err = %v, want ErrInvalid", err)
+	}
+	if err := idx.SetZM(id, nil, []float64{9}); err != nil {
+		t.Fatal(err)
+	}
+	if obj, _ := idx.Get(id); obj.Dims != DimM || obj.M[0] != 9 || obj.Z != nil {
+		t.Errorf("after SetZM: dims %v, Z %v, M %v", obj.Dims, obj.Z, obj.M)
+	}
+	if err := idx.SetZM(id, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if obj, _ := idx.Get(id); obj.Dims != 0 || obj.M != nil {
+		t.Errorf("SetZM(nil, nil) left dims %v, M %v", obj.Dims, obj.M)
+	}
+}
+
+func TestCheckZM(t *testing.T) {
+	idx, err := NewIndex(&Config{SimplifyTolerance: 0.1, ValidationTolerance: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	// Simplification keeps 2 of these 3 line vertices
+	line := []Point{{X: 0, Y: 0}, {X: 1, Y: 0.01}, {X: 2, Y: 0}}
+	if err := idx.CheckZM(GeomLineString, line, []float64{1, 2, 3}, nil); !errors.Is(err, ErrInvalid) {
+		t.Errorf("a Z for each point of a simplified line: err = %v, want ErrInvalid", err)
+	}
+	if err := idx.CheckZM(GeomLineString, line, []float64{1, 3}, nil); err != nil {
+		t.Errorf("a Z for each stored vertex: %v", err)
+	}
+
+	// The ring's near-repeat at (0.1, 0) is merged into (0, 0)
+	ring := []Point{{X: 0, Y: 0}, {X: 0.1, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0}}
+	if err := idx.CheckZM(GeomPolygon, ring, nil, make([]float64, 6)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("an M for each point of a snapped ring: err = %v, want ErrInvalid", err)
+	}
+	if err := idx.CheckZM(GeomPolygon, ring, nil, make([]float64, 5)); err != nil {
+		t.Errorf("an M for each stored vertex: %v", err)
+	}
+	id, err := idx.InsertPolygon(ring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.SetZM(id, nil, make([]float64, 5)); err != nil {
+		t.Errorf("SetZM with the count CheckZM accepted: %v", err)
+	}
+
+	if err := idx.CheckZM(GeomPoint, []Point{{X: 1, Y: 1}}, []float64{1}, []float64{2, 3}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("one Z but two M values: err = %v, want ErrInvalid", err)
+	}
+	if err := idx.CheckZM(GeomPoint, []Point{{X: 1, Y: 1}}, nil, nil); err != nil {
+		t.Errorf("no values: %v", err)
+	}
+}
+
+func TestInsertWithZM(t *testing.T) {
+	idx, err := NewIndex(&Config{DedupPoints: true, SimplifyTolerance: 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	first, err := idx.InsertPointWith(1, 1, InsertOptions{Z: []float64{5}, M: []float64{6}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj, _ := idx.Get(first.ID); obj.Dims != DimZ|DimM || obj.Z[0] != 5 || obj.M[0] != 6 {
+		t.Errorf("inserted point: dims %v, Z %v, M %v", obj.Dims, obj.Z, obj.M)
+	}
+
+	// A point counted against an existing one leaves that object's values
+	dup, err := idx.InsertPointWith(1, 1, InsertOptions{Z: []float64{7}})
+	if err != nil || !dup.Duplicate || dup.ID != first.ID {
+		t.Fatalf("duplicate insert = %+v, %v; want a duplicate of %d", dup, err, first.ID)
+	}
+	if obj, _ := idx.Get(first.ID); obj.Dims != DimZ|DimM || obj.Z[0] != 5 || obj.M[0] != 6 {
+		t.Errorf("duplicate insert changed the values to dims %v, Z %v, M %v", obj.Dims, obj.Z, obj.M)
+	}
+
+	// Values for the points rather than the stored vertices store nothing
+	line := []Point{{X: 0, Y: 0}, {X: 1, Y: 0.01}, {X: 2, Y: 0}}
+	if _, err := idx.InsertLineStringWith(line, InsertOptions{TTL: time.Hour, Z: []float64{1, 2, 3}}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("a Z for each point of a simplified line: err = %v, want ErrInvalid", err)
+	}
+	if idx.Count() != 1 || len(idx.expires) != 0 {
+		t.Errorf("%d objects and %d expiries after the refused insert, want 1 and 0", idx.Count(), len(idx.expires))
+	}
+
+	ins, err := idx.InsertLineStringWith(line, InsertOptions{ID: 42, Z: []float64{1, 3}})
+	if err != nil || ins.ID != 42 {
+		t.Fatalf("InsertLineStringWith = %+v, %v; want ID 42", ins, err)
+	}
+	if obj, _ := idx.Get(42); !reflect.DeepEqual(obj.Z, []float64{1, 3}) || obj.M != nil {
+		t.Errorf("inserted line: Z %v, M %v", obj.Z, obj.M)
+	}
+}
@@ -28,7 +28,8 @@ extern "C" {
 
 #define DM_DEFAULT_CACHE_SIZE 128     /**< Default page cache size */
 #define DM_MAGIC 0x55524249           /**< "URBI" magic number */
-#define DM_VERSION 1                  /**< File format version */
+#define DM_VERSION 2                  /**< File format version */
+#define DM_MAX_SETTINGS PAGE_SIZE     /**< Largest settings blob, stored at index_offset */
 
 /* ============================================================================
  * Types
@@ -50,7 +51,11 @@ typedef struct {
     uint32_t pages_per_track;         /**< Pages per track */
     uint64_t index_offset;            /**< Offset to index data */
     uint64_t data_offset;             /**< Offset to page data */
-    uint8_t reserved[64];             /**< Reserved for future use */
+    uint64_t next_object_id;          /**< Next automatic object ID, 0 if unrecorded */
+    uint32_t settings_size;           /**< Bytes of settings at index_offset (version 2) */
+    uint32_t bounded;                 /**< Nonzero if valid_bounds applies (version 2) */
+    MBR valid_bounds;                 /**< Box every object must lie in (version 2) */
+    uint8_t reserved[16];             /**< Reserved for future use */
 } DiskFileHeader;
 
 /**
@@ -70,6 +75,7 @@ typedef struct {
     size_t cache_size;                /**< Number of pages to cache */
     size_t page_size;                 /**< Page size in bytes */
     size_t pages_per_track;           /**< Pages per disk track */
+    size_t page_capacity;             /**< Max objects per new page */
     AllocationStrategy strategy;      /**< Page allocation strategy */
     bool use_mmap;                    /**< Use memory-mapped I/O */
     bool sync_on_write;               /**< Sync to disk on every write */
@@ -104,6 +110,8 @@ typedef struct {
     IOStats stats;                    /**< I/O statistics */
     bool is_open;                     /**< True if file is open */
     bool is_dirty;                    /**< True if uncommitted changes */
+    void *settings;                   /**< Opaque settings saved with the file */
+    size_t settings_size;             /**< Bytes of settings */
 } DiskManager;
 
 /* ============================================================================
@@ -148,14 +156,41 @@ int disk_manager_create(DiskManager *dm, const char *path);
 
 /**
  * @brief Open an existing data file
+ *
+ * Fails with DM_ERR_VERSION if the file was written by a newer format
+ * than DM_VERSION. Older versions are read as they are and written back
+ * as DM_VERSION.
  */
 int disk_manager_open(DiskManager *dm, const char *path);
 
+/**
+ * @brief Read the format version of a data file without opening it
+ * @return DM_OK, DM_ERR_IO if it cannot be read or DM_ERR_CORRUPT if it is
+ *         not a data file
+ */
+int disk_manager_read_version(const char *path, uint32_t *version);
+
+/**
+ * @brief Replace the settings blob written with the file header
+ * @return DM_OK, or DM_ERR_FULL if size exceeds DM_MAX_SETTINGS
+ */
+int disk_manager_set_settings(DiskManager *dm, const void *data, size_t size);
+
 /**
  * @brief Close the data file
  */
 int disk_manager_close(DiskManager *dm);
 
+/**
+ * @brief Close the data file without writing pending changes to it
+ */
+int disk_manager_detach(DiskManager *dm);
+
+/**
+ * @brief Move the open data file to path, replacing any file there
+ */
+int disk_manager_rename(DiskManager *dm, const char *path);
+
 /**
  * @brief Sync all changes to disk
  */
@@ -247,9 +282,17 @@ int disk_manager_query_tracks(DiskManager *dm, const MBR *region,
 
 /**
  * @brief Get data file size
+ *
+ * Uses the open data file, or the file at file_path when none is open.
+ * Returns 0 when there is no data file.
  */
 size_t disk_manager_file_size(const DiskManager *dm);
 
+/**
+ * @brief Heap bytes held by the page pool, cache and allocation tree
+ */
+size_t disk_manager_memory(const DiskManager *dm);
+
 /**
  * @brief Check if data file exists
  */
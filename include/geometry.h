@@ -52,6 +52,42 @@ typedef struct {
     size_t holes_capacity;    /**< Capacity of holes array */
 } Polygon;
 
+/**
+ * @brief MultiPoint - an unconnected set of points
+ */
+typedef struct {
+    Point *points;
+    size_t count;
+    size_t capacity;
+} MultiPoint;
+
+/**
+ * @brief MultiLineString - a set of linestrings
+ */
+typedef struct {
+    LineString *lines;
+    size_t count;
+    size_t capacity;
+} MultiLineString;
+
+/**
+ * @brief MultiPolygon - a set of polygons
+ */
+typedef struct {
+    Polygon *polygons;
+    size_t count;
+    size_t capacity;
+} MultiPolygon;
+
+/**
+ * @brief GeometryCollection - a heterogeneous set of geometries
+ */
+typedef struct {
+    struct SpatialObject *geometries;   /**< Member geometries */
+    size_t count;
+    size_t capacity;
+} GeometryCollection;
+
 /**
  * @brief Minimum Bounding Rectangle (axis-aligned bounding box)
  */
@@ -68,24 +104,42 @@ typedef struct {
 typedef enum {
     GEOM_POINT = 0,
     GEOM_LINESTRING = 1,
-    GEOM_POLYGON = 2
+    GEOM_POLYGON = 2,
+    GEOM_MULTIPOINT = 3,
+    GEOM_MULTILINESTRING = 4,
+    GEOM_MULTIPOLYGON = 5,
+    GEOM_GEOMETRYCOLLECTION = 6
 } GeomType;
 
+/**
+ * @brief Extra ordinates the vertices of an object carry (SpatialObject.dims)
+ */
+#define GEOM_DIM_Z 0x1u   /**< Z: elevation */
+#define GEOM_DIM_M 0x2u   /**< M: measure */
+
 /**
  * @brief Spatial object containing geometry and metadata
  */
-typedef struct {
+typedef struct SpatialObject {
     uint64_t id;              /**< Unique identifier */
     GeomType type;            /**< Geometry type */
     union {
         Point point;
         LineString line;
         Polygon polygon;
+        MultiPoint multi_point;
+        MultiLineString multi_line;
+        MultiPolygon multi_polygon;
+        GeometryCollection collection;
     } geom;
     Point centroid;           /**< Computed centroid for indexing */
     MBR mbr;                  /**< Bounding box */
     void *properties;         /**< User-defined properties */
     size_t properties_size;   /**< Size of properties data */
+    uint64_t version;         /**< Index-wide change counter at last modification (0 = never stamped) */
+    int64_t modified_at;      /**< Last modification, milliseconds since the Unix epoch */
+    uint8_t dims;             /**< GEOM_DIM_Z and/or GEOM_DIM_M, 0 for 2D; indexing is always 2D */
+    double *zm;               /**< Z then M of each own vertex, in coordinate order (NULL when 2D) */
 } SpatialObject;
 
 /* ============================================================================
@@ -218,6 +272,57 @@ bool polygon_is_clockwise(const Polygon *poly);
  */
 int polygon_copy(Polygon *dest, const Polygon *src);
 
+/**
+ * @brief Classify a point against a polygon
+ * @return 1 if strictly inside, 0 if on the boundary (exterior ring or a
+ *         hole edge), -1 if outside or inside a hole
+ */
+int polygon_locate_point(const Polygon *poly, const Point *p);
+
+/**
+ * @brief Check if a point lies in the polygon interior
+ *
+ * Boundary points, including points on a hole's edge, are not contained.
+ */
+bool polygon_contains_point(const Polygon *poly, const Point *p);
+
+/**
+ * @brief Check if a polygon covers the area of a ring
+ *
+ * Covered means no point of the ring or its interior lies outside the
+ * polygon or inside one of its holes. The ring may touch the polygon's
+ * boundary or share edges with it, including hole edges. The ring should
+ * not self-intersect; it may repeat its first point at the end or not.
+ * @return 1 if covered, 0 if not, or GEOM_ERR_ALLOC
+ */
+int polygon_covers_ring(const Polygon *poly, const Point *ring, size_t count);
+
+/* ============================================================================
+ * Multi-Geometry Operations
+ * ============================================================================ */
+
+/**
+ * @brief Add a point to a multipoint
+ */
+int multipoint_add_point(MultiPoint *mp, Point p);
+
+/**
+ * @brief Add an empty linestring to a multilinestring
+ * @return Index of the new linestring, or negative error code
+ */
+int multilinestring_add_line(MultiLineString *mls, size_t capacity);
+
+/**
+ * @brief Add an empty polygon to a multipolygon
+ * @return Index of the new polygon, or negative error code
+ */
+int multipolygon_add_polygon(MultiPolygon *mp, size_t ext_capacity);
+
+/**
+ * @brief Add a deep copy of a geometry to a collection
+ */
+int geometry_collection_add(GeometryCollection *gc, const SpatialObject *geom);
+
 /* ============================================================================
  * MBR Operations
  * ============================================================================ */
@@ -301,6 +406,26 @@ int spatial_object_init_linestring(SpatialObject *obj, uint64_t id, size_t capac
  */
 int spatial_object_init_polygon(SpatialObject *obj, uint64_t id, size_t ext_capacity);
 
+/**
+ * @brief Initialize a spatial object as a multipoint
+ */
+int spatial_object_init_multipoint(SpatialObject *obj, uint64_t id, size_t capacity);
+
+/**
+ * @brief Initialize a spatial object as a multilinestring
+ */
+int spatial_object_init_multilinestring(SpatialObject *obj, uint64_t id, size_t capacity);
+
+/**
+ * @brief Initialize a spatial object as a multipolygon
+ */
+int spatial_object_init_multipolygon(SpatialObject *obj, uint64_t id, size_t capacity);
+
+/**
+ * @brief Initialize a spatial object as a geometry collection
+ */
+int spatial_object_init_collection(SpatialObject *obj, uint64_t id, size_t capacity);
+
 /**
  * @brief Free spatial object resources
  */
@@ -321,6 +446,77 @@ int spatial_object_copy(SpatialObject *dest, const SpatialObject *src);
  */
 int spatial_object_set_properties(SpatialObject *obj, const void *data, size_t size);
 
+/**
+ * @brief Number of extra ordinates per vertex for a dims value (0, 1 or 2)
+ */
+size_t geom_dims_width(uint8_t dims);
+
+/**
+ * @brief Number of vertices in an object's own coordinate arrays
+ *
+ * A collection's members carry their own vertices and Z/M values, so a
+ * collection counts none.
+ */
+size_t spatial_object_vertex_count(const SpatialObject *obj);
+
+/**
+ * @brief Set the Z and/or M values of an object's vertices
+ *
+ * zm holds geom_dims_width(dims) values per vertex, Z before M, in the
+ * order the coordinate arrays list them: a polygon's exterior then its
+ * holes, the parts of a multi-geometry in order. It is copied. dims 0
+ * clears the values.
+ */
+int spatial_object_set_zm(SpatialObject *obj, uint8_t dims, const double *zm);
+
+/**
+ * @brief Heap bytes owned by a spatial object (coordinates and properties)
+ *
+ * The SpatialObject struct itself is not counted; it lives in its page.
+ */
+size_t spatial_object_memory(const SpatialObject *obj);
+
+/**
+ * @brief Check if a polygonal object's interior contains a point
+ *
+ * Only polygons and multipolygons can contain points; boundary points
+ * are not contained.
+ */
+bool spatial_object_contains_point(const SpatialObject *obj, const Point *p);
+
+/**
+ * @brief Check if a polygonal object covers the area of a ring
+ *
+ * A multipolygon covers the ring when one of its parts does. See
+ * polygon_covers_ring.
+ * @return 1 if covered, 0 if not, or GEOM_ERR_ALLOC
+ */
+int spatial_object_covers_ring(const SpatialObject *obj, const Point *ring, size_t count);
+
+/**
+ * @brief Check that every coordinate of an object is finite (no NaN/Inf)
+ */
+bool spatial_object_is_finite(const SpatialObject *obj);
+
+/**
+ * @brief Round every coordinate to the nearest multiple of grid
+ *
+ * Derived centroid and MBR are not updated; call
+ * spatial_object_update_derived afterwards.
+ */
+void spatial_object_snap(SpatialObject *obj, double grid);
+
+/**
+ * @brief Simplify linestrings and polygon rings with Douglas-Peucker
+ *
+ * Vertices closer than tolerance to the simplified shape are dropped in
+ * place. Lines keep at least their endpoints; a ring that would fall below
+ * four points is left as it was. Z/M values of dropped vertices are dropped
+ * with them. Derived centroid and MBR are not updated;
+ * call spatial_object_update_derived afterwards.
+ */
+void spatial_object_simplify(SpatialObject *obj, double tolerance);
+
 #ifdef __cplusplus
 }
 #endif
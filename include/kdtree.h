@@ -104,6 +104,22 @@ int kdtree_insert(KDTree *tree, Point p, uint64_t object_id, void *data);
  */
 int kdtree_bulk_load(KDTree *tree, KDPointData *points, size_t count);
 
+/**
+ * @brief Build a balanced KD-tree from points using up to threads threads
+ *
+ * The two halves of each split are built on separate threads until every
+ * thread has work or the halves get too small to be worth one. Points with
+ * the same coordinate on the split axis are ordered by a hash of their
+ * object ID and seed, so the tree depends only on the points and the seed:
+ * not on their order, the thread count or the platform's qsort.
+ * kdtree_bulk_load() uses seed 0.
+ * @param threads Maximum threads, including the caller's (0 = 1)
+ * @param seed Selects the order of tied points
+ * @param threads_used Output: threads that built part of the tree (may be NULL)
+ */
+int kdtree_bulk_load_parallel(KDTree *tree, KDPointData *points, size_t count,
+                              size_t threads, uint64_t seed, size_t *threads_used);
+
 /**
  * @brief Find the nearest neighbor to a query point
  * @param tree The KD-tree
@@ -155,6 +171,11 @@ int kdtree_partition(const KDTree *tree, size_t max_points_per_block,
  */
 size_t kdtree_depth(const KDTree *tree);
 
+/**
+ * @brief Heap bytes held by the tree's nodes
+ */
+size_t kdtree_memory(const KDTree *tree);
+
 /**
  * @brief Check if tree is balanced
  */
@@ -100,8 +100,9 @@ typedef struct PageRef {
     uint32_t page_id;
     uint32_t access_count;
     uint64_t last_access;
-    struct PageRef *next;
-    struct PageRef *prev;
+    struct PageRef *next;         /**< Next in LRU order */
+    struct PageRef *prev;         /**< Previous in LRU order */
+    struct PageRef *hash_next;    /**< Next in the same hash bucket */
 } PageRef;
 
 /**
@@ -176,6 +177,11 @@ bool page_is_full(const Page *page);
  */
 double page_utilization(const Page *page);
 
+/**
+ * @brief Heap bytes held by a page, its object slots and their contents
+ */
+size_t page_memory(const Page *page);
+
 /**
  * @brief Serialize page to raw bytes for disk I/O
  */
@@ -298,6 +304,11 @@ void page_pool_stats(const PagePool *pool, size_t *total_pages,
  * Page Cache Operations
  * ============================================================================ */
 
+/**
+ * @brief Heap bytes held by a pool's pages, tracks and lookup arrays
+ */
+size_t page_pool_memory(const PagePool *pool);
+
 /**
  * @brief Initialize a page cache
  */
@@ -308,11 +319,21 @@ int page_cache_init(PageCache *cache, PagePool *pool, size_t capacity);
  */
 void page_cache_free(PageCache *cache);
 
+/**
+ * @brief Heap bytes held by a cache's entries and hash table
+ */
+size_t page_cache_memory(const PageCache *cache);
+
 /**
  * @brief Get a page from cache (loads if not present)
  */
 Page* page_cache_get(PageCache *cache, uint32_t page_id);
 
+/**
+ * @brief Check whether a page is resident in the cache without touching it
+ */
+bool page_cache_contains(const PageCache *cache, uint32_t page_id);
+
 /**
  * @brief Pin a page in cache (prevents eviction)
  */
@@ -42,7 +42,8 @@ typedef enum {
     PARSE_ERR_SYNTAX = -4,
     PARSE_ERR_INVALID_GEOM = -5,
     PARSE_ERR_UNSUPPORTED = -6,
-    PARSE_ERR_OVERFLOW = -7
+    PARSE_ERR_OVERFLOW = -7,
+    PARSE_ERR_EMPTY_GEOM = -8
 } ParseError;
 
 /**
@@ -100,6 +101,14 @@ typedef struct {
     char *id_str;                      /**< Optional string ID */
 } ParsedFeature;
 
+/**
+ * @brief A feature that could not be parsed
+ */
+typedef struct {
+    size_t index;                      /**< Position of the feature in the input */
+    int code;                          /**< ParseError code */
+} FeatureError;
+
 /**
  * @brief Collection of parsed features
  */
@@ -108,6 +117,9 @@ typedef struct {
     size_t count;                      /**< Number of features */
     size_t capacity;                   /**< Array capacity */
     MBR bounds;                        /**< Overall bounds */
+    size_t skipped;                    /**< Features left out for a null or empty geometry */
+    FeatureError *errors;              /**< Features left out because they failed to parse */
+    size_t error_count;                /**< Number of errors */
 } FeatureCollection;
 
 /**
@@ -141,15 +153,22 @@ int geojson_parse_stream(FILE *file, const ParserCallbacks *callbacks);
 
 /**
  * @brief Parse a single GeoJSON geometry
+ *
+ * Positions with a third number keep it as Z, and with a fourth as M; see
+ * spatial_object_set_zm. Positions shorter than the rest set the dimension.
  */
 int geojson_parse_geometry(const char *json, SpatialObject *obj);
 
 /**
- * @brief Export spatial object to GeoJSON string
+ * @brief Export spatial object to a GeoJSON geometry object
+ *
+ * Polygons include their holes, and coordinates are written with enough
+ * digits to read back exactly. Z and M follow x and y in each position.
  * @param obj Spatial object to export
- * @param buffer Output buffer
- * @param buffer_size Buffer size
- * @return Number of characters written, or negative on error
+ * @param buffer Output buffer, or NULL to measure
+ * @param buffer_size Buffer size including the terminating NUL
+ * @return Length of the JSON text excluding the NUL (like snprintf; text
+ *         past buffer_size is dropped), or negative on error
  */
 int geojson_export(const SpatialObject *obj, char *buffer, size_t buffer_size);
 
@@ -165,14 +184,47 @@ int geojson_export_collection(const FeatureCollection *fc,
 
 /**
  * @brief Parse a WKT string into a spatial object
+ *
+ * PostGIS EWKT is accepted too: a leading "SRID=<code>;" is skipped. The
+ * caller decides what the SRID means; see wkt_srid. A Z, M or ZM tag after
+ * the type name says what follows x and y; untagged coordinates with three
+ * or four numbers are read as Z or ZM.
  */
 int wkt_parse(const char *wkt, SpatialObject *obj);
 
 /**
- * @brief Export spatial object to WKT string
+ * @brief Read the SRID of an EWKT string
+ *
+ * Stores the code in *srid (0 when there is no "SRID=" prefix) and returns
+ * a pointer to the WKT after the prefix, or NULL if the prefix is malformed.
+ */
+const char* wkt_srid(const char *wkt, int32_t *srid);
+
+/**
+ * @brief Export spatial object to WKT string, tagged Z, M or ZM for 3D and 4D
  */
 int wkt_export(const SpatialObject *obj, char *buffer, size_t buffer_size);
 
+/* ============================================================================
+ * WKB Parsing
+ * ============================================================================ */
+
+/**
+ * @brief Parse one WKB geometry into a spatial object
+ *
+ * Both byte orders are accepted, and Z and M in ISO or PostGIS form. If
+ * consumed is non-NULL it receives the number of bytes read, so
+ * concatenated geometries can be parsed in turn.
+ */
+int wkb_parse(const uint8_t *data, size_t size, SpatialObject *obj, size_t *consumed);
+
+/**
+ * @brief Export spatial object to little-endian WKB, with ISO Z/M types
+ * for 3D and 4D
+ * @return Number of bytes required; nothing past buffer_size is written
+ */
+int wkb_export(const SpatialObject *obj, uint8_t *buffer, size_t buffer_size);
+
 /* ============================================================================
  * Feature Collection Operations
  * ============================================================================ */
@@ -211,6 +263,14 @@ void feature_collection_clear(FeatureCollection *fc);
  */
 void json_value_free(JsonValue *value);
 
+/**
+ * @brief Serialize a JSON value as compact JSON text
+ *
+ * Writes at most size bytes including the terminating NUL and returns the
+ * full length excluding it, like snprintf. Call with size 0 to measure.
+ */
+size_t json_write(const JsonValue *value, char *buffer, size_t size);
+
 /**
  * @brief Get JSON object property by key
  */
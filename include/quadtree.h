@@ -189,6 +189,11 @@ int quadtree_get_all(const QuadTree *qt, QTQueryResult *result);
 void quadtree_stats(const QuadTree *qt, size_t *total_items, size_t *total_nodes,
                     size_t *max_depth, size_t *leaf_count);
 
+/**
+ * @brief Heap bytes held by the tree's nodes and item arrays
+ */
+size_t quadtree_memory(const QuadTree *qt);
+
 /**
  * @brief Clear all items from quadtree
  */
@@ -29,6 +29,10 @@ extern "C" {
 
 #define SI_DEFAULT_BLOCK_SIZE 1024     /**< Default objects per block */
 #define SI_DEFAULT_PAGE_CAPACITY 64    /**< Default objects per page */
+#define SI_ROTATIONAL_SEEK_MS 8.0      /**< Hard disk seek time */
+#define SI_ROTATIONAL_TRANSFER_MB_S 150.0  /**< Hard disk read rate */
+#define SI_SSD_SEEK_MS 0.1             /**< Flash access latency */
+#define SI_SSD_TRANSFER_MB_S 2000.0    /**< Flash read rate */
 
 /* ============================================================================
  * Types
@@ -44,6 +48,16 @@ typedef struct {
     bool build_quadtree;               /**< Build quadtree for adjacency */
     bool persist;                      /**< Persist to disk */
     char *data_path;                   /**< Path for data file */
+    double snap_grid;                  /**< Grid size coordinates snap to (0 = off) */
+    double simplify_tolerance;         /**< Douglas-Peucker tolerance for lines and rings (0 = off) */
+    bool dedup_points;                 /**< Merge identical points, counting duplicates */
+    double seek_ms;                    /**< Estimated time per disk seek */
+    double transfer_mb_s;              /**< Estimated sequential read rate in MB/s */
+    size_t build_threads;              /**< Threads the KD-tree build may use (0 = 1) */
+    uint64_t seed;                     /**< Orders tied centroids in the KD-tree build */
+    bool keep_duplicates;              /**< Skip dropping repeat objects from range results */
+    bool bounded;                      /**< Reject objects reaching outside valid_bounds */
+    MBR valid_bounds;                  /**< Box every object must lie in (if bounded) */
 } SpatialIndexConfig;
 
 /**
@@ -57,6 +71,37 @@ typedef struct {
     size_t object_count;               /**< Number of objects in block */
 } SpatialBlock;
 
+/**
+ * @brief Object a block-tree point stands for, and the page holding it
+ */
+typedef struct {
+    SpatialObject *object;             /**< Object in its page */
+    Page *page;                        /**< Page holding the object */
+} BlockEntry;
+
+/**
+ * @brief Structure used to answer a query
+ *
+ * Range and point queries accept one of these as a hint. AUTO keeps the
+ * default page scan; a hinted tree that has not been built (or is stale
+ * after a change) falls back to the other tree, then to the page scan.
+ */
+typedef enum {
+    SI_STRUCTURE_AUTO = 0,             /**< Let the index choose */
+    SI_STRUCTURE_KDTREE = 1,           /**< Object KD-tree */
+    SI_STRUCTURE_QUADTREE = 2,         /**< Page quadtree */
+    SI_STRUCTURE_SCAN = 3              /**< Linear scan of page extents */
+} SpatialStructure;
+
+/**
+ * @brief Rule deciding whether an object falls within a region
+ */
+typedef enum {
+    SI_MATCH_CONTAINED = 0,            /**< Object MBR lies entirely inside the region */
+    SI_MATCH_CENTROID = 1,             /**< Object centroid lies inside the region */
+    SI_MATCH_INTERSECTS = 2            /**< Object MBR intersects the region */
+} SpatialMatch;
+
 /**
  * @brief Query result containing spatial objects
  */
@@ -66,6 +111,11 @@ typedef struct {
     size_t capacity;                   /**< Array capacity */
     uint32_t *page_ids;                /**< Pages accessed */
     size_t pages_accessed;             /**< Number of pages accessed */
+    size_t page_capacity;              /**< Capacity of page_ids array */
+    SpatialStructure structure;        /**< Structure that answered the query */
+    uint32_t *failed_page_ids;         /**< Accessed pages that failed verification */
+    size_t pages_failed;               /**< Number of failed pages */
+    size_t duplicates;                 /**< Repeat objects dropped by spatial_result_dedupe */
 } SpatialQueryResult;
 
 /**
@@ -90,6 +140,9 @@ typedef struct {
     double avg_objects_per_page;       /**< Average objects per page */
     double page_utilization;           /**< Average page utilization */
     MBR bounds;                        /**< Overall spatial bounds */
+    size_t memory_bytes;               /**< Heap bytes held by the index */
+    size_t disk_bytes;                 /**< Size of the data file (0 if none) */
+    size_t build_threads;              /**< Threads the last build used (0 before one) */
 } SpatialIndexStats;
 
 /**
@@ -98,15 +151,23 @@ typedef struct {
 typedef struct {
     SpatialIndexConfig config;
     KDTree block_tree;                 /**< KD-tree for block partitioning */
+    BlockEntry *block_entries;         /**< What each block-tree point's data points to */
+    size_t block_entry_count;          /**< Number of block entries */
     QuadTree *page_tree;               /**< Quadtree for page adjacency */
     DiskManager disk;                  /**< Disk manager */
     SpatialBlock *blocks;              /**< Array of blocks */
     size_t block_count;                /**< Number of blocks */
     size_t block_capacity;             /**< Block array capacity */
     uint64_t next_object_id;           /**< Next object ID */
+    uint64_t version_clock;            /**< Version given to the last modified object */
     uint32_t next_block_id;            /**< Next block ID */
     bool is_built;                     /**< True if index is built */
+    size_t changes_since_build;        /**< Inserts and removals since the last build */
     MBR bounds;                        /**< Overall bounds */
+    Point object_reach;                /**< Largest centroid-to-MBR-edge distance per axis */
+    size_t build_threads_used;         /**< Threads the last KD-tree build used */
+    size_t out_of_bounds;              /**< Objects rejected for leaving valid_bounds */
+    uint64_t change_count;             /**< Inserts, removals and updates ever made */
 } SpatialIndex;
 
 /* ============================================================================
@@ -121,9 +182,29 @@ typedef enum {
     SI_ERR_NOT_FOUND = -4,
     SI_ERR_FULL = -5,
     SI_ERR_IO = -6,
-    SI_ERR_INVALID = -7
+    SI_ERR_INVALID = -7,
+    SI_ERR_EXISTS = -8,
+    SI_ERR_CANCELLED = -9,
+    SI_ERR_VERSION = -10,
+    SI_ERR_OUT_OF_BOUNDS = -11
 } SpatialIndexError;
 
+/**
+ * @brief Build progress callback
+ *
+ * Receives the number of objects processed so far and the total. The
+ * final call always has done == total.
+ */
+typedef void (*BuildProgressFn)(void *user_data, size_t done, size_t total);
+
+/**
+ * @brief Build cancellation check
+ *
+ * Polled between build phases and while objects are gathered; returning
+ * true abandons the build.
+ */
+typedef bool (*BuildCancelFn)(void *user_data);
+
 /* ============================================================================
  * Spatial Index Operations
  * ============================================================================ */
@@ -158,6 +239,15 @@ void spatial_index_destroy(SpatialIndex *idx);
  */
 int spatial_index_insert(SpatialIndex *idx, SpatialObject *obj);
 
+/**
+ * @brief Insert a spatial object under a caller-chosen ID
+ *
+ * Fails with SI_ERR_EXISTS if the ID is taken, or if point deduplication
+ * would merge the point into an existing object. Later automatic IDs
+ * continue after the highest ID inserted.
+ */
+int spatial_index_insert_as(SpatialIndex *idx, SpatialObject *obj, uint64_t id);
+
 /**
  * @brief Bulk insert spatial objects
  */
@@ -174,24 +264,80 @@ int spatial_index_remove(SpatialIndex *idx, uint64_t object_id);
  */
 int spatial_index_build(SpatialIndex *idx);
 
+/**
+ * @brief Build/rebuild the spatial index, reporting progress
+ */
+int spatial_index_build_progress(SpatialIndex *idx, BuildProgressFn on_progress,
+                                 void *user_data);
+
+/**
+ * @brief Build/rebuild the spatial index, reporting progress until cancelled
+ *
+ * When should_cancel returns true the build stops with SI_ERR_CANCELLED,
+ * dropping the block tree and blocks of any earlier build, so the index is
+ * left unbuilt rather than half-built. Either callback may be NULL.
+ */
+int spatial_index_build_cancellable(SpatialIndex *idx, BuildProgressFn on_progress,
+                                    BuildCancelFn should_cancel, void *user_data);
+
 /**
  * @brief Find all objects intersecting a region
  */
 int spatial_index_query_range(SpatialIndex *idx, const MBR *range,
                                SpatialQueryResult *result);
 
+/**
+ * @brief Find all objects intersecting a region using a preferred structure
+ *
+ * result->structure records the structure actually used.
+ */
+int spatial_index_query_range_using(SpatialIndex *idx, const MBR *range,
+                                     SpatialStructure structure,
+                                     SpatialQueryResult *result);
+
 /**
  * @brief Find objects at a specific point
  */
 int spatial_index_query_point(SpatialIndex *idx, Point p,
                                SpatialQueryResult *result);
 
+/**
+ * @brief Find objects at a specific point using a preferred structure
+ */
+int spatial_index_query_point_using(SpatialIndex *idx, Point p,
+                                     SpatialStructure structure,
+                                     SpatialQueryResult *result);
+
 /**
  * @brief Find k nearest neighbors to a point
  */
 int spatial_index_query_knn(SpatialIndex *idx, Point p, size_t k,
                              SpatialQueryResult *result);
 
+/**
+ * @brief Find the single nearest object to a point
+ *
+ * Ranks objects by centroid distance like spatial_index_query_knn, but
+ * walks the KD-tree once without building a result list.
+ *
+ * @param nearest Output: the nearest object, owned by the index
+ * @param distance Output: distance from p to its centroid (may be NULL)
+ * @return SI_ERR_NOT_FOUND if the index holds no objects
+ */
+int spatial_index_nearest(SpatialIndex *idx, Point p, SpatialObject **nearest,
+                          double *distance);
+
+/**
+ * @brief Find objects modified at or after a time
+ *
+ * Scans every page, so the index need not be built. Objects are returned
+ * in ascending version order. Removed objects leave no trace.
+ *
+ * @param since Milliseconds since the Unix epoch
+ */
+int spatial_index_query_changed_since(SpatialIndex *idx, int64_t since,
+                                       SpatialQueryResult *result);
+
 /**
  * @brief Find adjacent pages to a region (uses quadtree)
  */
@@ -209,6 +355,29 @@ SpatialObject* spatial_index_get(SpatialIndex *idx, uint64_t object_id);
 int spatial_index_update(SpatialIndex *idx, uint64_t object_id,
                           const SpatialObject *new_obj);
 
+/**
+ * @brief Remove every object matching a region
+ *
+ * Boundaries are inclusive. The number of objects removed is stored in
+ * *removed.
+ */
+int spatial_index_remove_range(SpatialIndex *idx, const MBR *region,
+                                SpatialMatch match, size_t *removed);
+
+/**
+ * @brief Replace an object's properties, leaving geometry and trees untouched
+ */
+int spatial_index_set_properties(SpatialIndex *idx, uint64_t object_id,
+                                  const void *data, size_t size);
+
+/**
+ * @brief Replace the Z/M values of an object's vertices; see spatial_object_set_zm
+ *
+ * count must be geom_dims_width(dims) times the object's vertex count.
+ */
+int spatial_index_set_zm(SpatialIndex *idx, uint64_t object_id, uint8_t dims,
+                          const double *zm, size_t count);
+
 /* ============================================================================
  * Block Operations
  * ============================================================================ */
@@ -239,13 +408,71 @@ int spatial_index_get_all_blocks(SpatialIndex *idx,
  */
 void spatial_index_stats(const SpatialIndex *idx, SpatialIndexStats *stats);
 
+/**
+ * @brief Heap bytes held by the index
+ *
+ * Sums the allocations the index owns: pages and their objects' coordinates
+ * and properties, tracks, the page cache, blocks and both trees. Allocator
+ * overhead is not included.
+ */
+size_t spatial_index_memory(const SpatialIndex *idx);
+
 /**
  * @brief Optimize index for better query performance
  */
 int spatial_index_optimize(SpatialIndex *idx);
 
+/**
+ * @brief Optimize the index until cancelled
+ *
+ * Optimizing rebuilds the index. The new build replaces the current one
+ * only once it can no longer be cancelled, so a cancelled optimize leaves
+ * the index as it was, still built if it was built. should_cancel may be
+ * NULL.
+ */
+int spatial_index_optimize_cancellable(SpatialIndex *idx, BuildCancelFn should_cancel,
+                                       void *user_data);
+
+/**
+ * @brief Copy an index onto pages of a different capacity
+ *
+ * Creates a built, in-memory copy of the index whose pages hold at most
+ * page_capacity objects. Object IDs, properties and configuration are
+ * preserved.
+ *
+ * @return The new index, or NULL on allocation failure
+ */
+SpatialIndex* spatial_index_repage(const SpatialIndex *idx, size_t page_capacity);
+
+/**
+ * @brief Copy an index onto pages of a different capacity until cancelled
+ *
+ * should_cancel is polled after each page is copied and while the copy is
+ * built; once it returns true the copy is dropped and SI_ERR_CANCELLED
+ * returned. The original is never changed. should_cancel may be NULL.
+ *
+ * @param out Output: the new index on success
+ */
+int spatial_index_repage_cancellable(const SpatialIndex *idx, size_t page_capacity,
+                                     BuildCancelFn should_cancel, void *user_data,
+                                     SpatialIndex **out);
+
+/**
+ * @brief Move the data file an index is attached to onto a copy of it
+ *
+ * The file is rewritten with the copy's pages and stays attached to the
+ * copy; idx is detached from it, so destroying idx writes nothing. If the
+ * rewrite fails, idx is saved back to the file. Does nothing when idx has
+ * no data file.
+ */
+int spatial_index_adopt_file(SpatialIndex *copy, SpatialIndex *idx);
+
 /**
  * @brief Save index to disk
+ *
+ * The file is written to path with ".tmp" appended and renamed to path once
+ * complete, so a failed save leaves any previous file at path whole. The
+ * index keeps path as its data file, or none if the save fails.
  */
 int spatial_index_save(SpatialIndex *idx, const char *path);
 
@@ -288,6 +515,18 @@ void spatial_result_clear(SpatialQueryResult *result);
  */
 int spatial_result_add(SpatialQueryResult *result, SpatialObject *obj);
 
+/**
+ * @brief Record a page accessed by a query (duplicates are ignored)
+ */
+int spatial_result_add_page(SpatialQueryResult *result, uint32_t page_id);
+
+/**
+ * @brief Drop objects whose ID is already in the result, keeping the first
+ *
+ * Order is otherwise kept. The number dropped is added to result->duplicates.
+ */
+int spatial_result_dedupe(SpatialQueryResult *result);
+
 /* ============================================================================
  * Adjacent Pages Result Operations
  * ============================================================================ */
@@ -63,6 +63,27 @@ typedef SpatialIndex UrbisIndex;
 /**
  * @brief Index configuration
  */
+/**
+ * @brief Storage medium the seek and transfer cost estimates assume
+ */
+typedef enum {
+    URBIS_STORAGE_ROTATIONAL = 0,  /**< Hard disk: 8 ms per seek, 150 MB/s */
+    URBIS_STORAGE_SSD = 1,         /**< Flash: 0.1 ms per seek, 2000 MB/s */
+    URBIS_STORAGE_CUSTOM = 2       /**< The seek_ms and transfer_mb_s given */
+} UrbisStorageKind;
+
+/**
+ * @brief Cost model turning page accesses into estimated read time
+ *
+ * A query costs one seek to reach its first track plus one per track
+ * change, and the transfer time of every page it reads.
+ */
+typedef struct {
+    UrbisStorageKind kind;
+    double seek_ms;               /**< Time per seek (custom only, >= 0) */
+    double transfer_mb_s;         /**< Sequential read rate in MB/s (custom only, > 0) */
+} UrbisSeekCostModel;
+
 typedef struct {
     size_t block_size;            /**< Max objects per block (default: 1024) */
     size_t page_capacity;         /**< Max objects per page (default: 64) */
@@ -70,14 +91,42 @@ typedef struct {
     bool enable_quadtree;         /**< Enable quadtree for adjacency (default: true) */
     bool persist;                 /**< Enable persistence (default: false) */
     const char *data_path;        /**< Path for data file (if persist=true) */
+    double snap_grid;             /**< Snap coordinates to this grid size on insert (default: 0, off) */
+    double simplify_tolerance;    /**< Simplify lines and rings on insert with this tolerance (default: 0, off) */
+    bool dedup_points;            /**< Collapse identical points into one counted object (default: false) */
+    UrbisSeekCostModel seek_cost; /**< Storage the cost estimates assume (default: rotational) */
+    size_t build_threads;         /**< Threads urbis_build() may use for the KD-tree (default: 1) */
+    uint64_t seed;                /**< Orders objects with tied centroids in the build (default: 0) */
+    bool keep_duplicates;         /**< List an object in a range result once per time it is found (default: false) */
+    const MBR *valid_bounds;      /**< Reject objects reaching outside this box (default: NULL, off) */
 } UrbisConfig;
 
+/**
+ * @brief Page and seek statistics for a single query
+ */
+typedef struct {
+    size_t pages_visited;         /**< Distinct pages touched by the query */
+    size_t tracks_visited;        /**< Distinct tracks touched by the query */
+    size_t estimated_seeks;       /**< Track transitions across visited pages */
+    size_t cache_hits;            /**< Visited pages already resident in memory */
+    size_t cache_misses;          /**< Visited pages that had to be read from disk */
+    SpatialStructure structure;   /**< Structure that answered the query */
+    bool structure_fallback;      /**< Requested structure was unavailable */
+    size_t duplicates_suppressed; /**< Repeat objects dropped from the result */
+} UrbisQueryStats;
+
 /**
  * @brief List of objects returned from queries
  */
 typedef struct {
     SpatialObject **objects;
     size_t count;
+    UrbisQueryStats stats;        /**< Page/seek statistics for the query */
+    uint32_t *failed_pages;       /**< Pages skipped because they failed verification */
+    size_t failed_count;          /**< Number of failed pages */
+    uint32_t *visited_pages;      /**< Pages the query touched, in visit order (range and point queries) */
+    uint32_t *visited_tracks;     /**< Track of each visited page */
+    size_t visited_count;         /**< Number of visited pages */
 } UrbisObjectList;
 
 /**
@@ -88,8 +137,33 @@ typedef struct {
     uint32_t *track_ids;
     size_t count;
     size_t estimated_seeks;
+    double estimated_cost_ms;     /**< Seek and transfer time under the index's cost model */
 } UrbisPageList;
 
+/**
+ * @brief Layout of one page
+ */
+typedef struct {
+    uint32_t page_id;
+    uint32_t track_id;
+    uint32_t object_count;
+    MBR extent;                   /**< Bounds of the page's objects */
+} UrbisPageInfo;
+
+/**
+ * @brief One node of the KD-tree or page quadtree, for visualization
+ */
+typedef struct {
+    uint32_t depth;               /**< 0 for the root */
+    int64_t parent;               /**< Index of the parent entry, -1 for the root */
+    MBR bounds;                   /**< KD-tree: bounds of the subtree's centroids; quadtree: the node's cell */
+    uint64_t object_count;        /**< Objects in the subtree */
+    uint64_t item_count;          /**< Entries in the subtree: objects (KD-tree) or pages (quadtree) */
+    int split_dim;                /**< KD-tree inner nodes: 0 = x, 1 = y; otherwise -1 */
+    double split_value;           /**< Coordinate of the split along split_dim */
+    bool is_leaf;                 /**< No children, as opposed to children cut off by max_depth */
+} UrbisTreeNode;
+
 /**
  * @brief Index statistics
  */
@@ -103,8 +177,39 @@ typedef struct {
     size_t kdtree_depth;
     size_t quadtree_depth;
     MBR bounds;
+    size_t page_capacity;         /**< Max objects per page */
+    size_t memory_bytes;          /**< Heap bytes held by the index */
+    size_t disk_bytes;            /**< Size of the data file (0 if none) */
+    size_t build_threads;         /**< Threads the last build used (0 before one) */
 } UrbisStats;
 
+/** Largest settings blob urbis_set_settings() accepts, in bytes */
+#define URBIS_MAX_SETTINGS DM_MAX_SETTINGS
+
+/** Number of page capacities urbis_autotune() evaluates */
+#define URBIS_TUNE_CANDIDATES 4
+
+/**
+ * @brief Layout metrics for one page capacity tried by urbis_autotune()
+ */
+typedef struct {
+    size_t page_capacity;         /**< Max objects per page */
+    size_t total_pages;           /**< Pages needed for the data */
+    double page_utilization;      /**< Average page fill */
+    double avg_seeks;             /**< Mean estimated seeks per sample query */
+    double avg_cost_ms;           /**< Mean estimated read time per sample query */
+    double cost;                  /**< avg_cost_ms / page_utilization; lower is better */
+} UrbisTuneCandidate;
+
+/**
+ * @brief Result of urbis_autotune()
+ */
+typedef struct {
+    size_t page_capacity;         /**< Recommended capacity (lowest cost) */
+    size_t candidate_count;       /**< Entries used in candidates */
+    UrbisTuneCandidate candidates[URBIS_TUNE_CANDIDATES];
+} UrbisTuneResult;
+
 /**
  * @brief Error codes
  */
@@ -116,7 +221,10 @@ typedef enum {
     URBIS_ERR_PARSE = -4,
     URBIS_ERR_NOT_FOUND = -5,
     URBIS_ERR_FULL = -6,
-    URBIS_ERR_INVALID = -7
+    URBIS_ERR_INVALID = -7,
+    URBIS_ERR_EXISTS = -8,
+    URBIS_ERR_CANCELLED = -9,
+    URBIS_ERR_VERSION = -10
 } UrbisError;
 
 /* ============================================================================
@@ -162,11 +270,79 @@ int urbis_load_geojson(UrbisIndex *idx, const char *path);
  */
 int urbis_load_geojson_string(UrbisIndex *idx, const char *json);
 
+/**
+ * @brief Load a GeoJSON file, counting the features left out
+ *
+ * Features whose geometry is null or has no coordinates are skipped, as by
+ * urbis_load_geojson; their number is stored in *skipped if it is not NULL.
+ */
+int urbis_load_geojson_counting(UrbisIndex *idx, const char *path, size_t *skipped);
+
+/**
+ * @brief Load a GeoJSON string, counting the features left out
+ */
+int urbis_load_geojson_string_counting(UrbisIndex *idx, const char *json,
+                                       size_t *skipped);
+
+/**
+ * @brief Outcome of checking GeoJSON without loading it
+ */
+typedef struct {
+    size_t valid;                     /**< Features a load would insert */
+    size_t skipped;                   /**< Features with a null or empty geometry */
+    FeatureError *errors;             /**< Features that failed to parse, in input order */
+    size_t error_count;               /**< Number of errors */
+} UrbisGeoJSONCheck;
+
+/**
+ * @brief Parse a GeoJSON string as urbis_load_geojson_string would, without
+ * inserting anything
+ *
+ * Fails with URBIS_ERR_PARSE only when the document itself is unreadable;
+ * features that fail to parse are listed in check->errors. Free the check
+ * with urbis_geojson_check_free.
+ */
+int urbis_check_geojson_string(const char *json, UrbisGeoJSONCheck *check);
+
+/**
+ * @brief Free the errors of a GeoJSON check
+ */
+void urbis_geojson_check_free(UrbisGeoJSONCheck *check);
+
 /**
  * @brief Load data from a WKT string
+ *
+ * PostGIS EWKT ("SRID=4326;POINT(1 2)") is accepted; the SRID is not
+ * checked. Use urbis_wkt_srid to read it first.
  */
 int urbis_load_wkt(UrbisIndex *idx, const char *wkt);
 
+/**
+ * @brief Get the SRID of an EWKT string
+ * @return The SRID, 0 for plain WKT, or -1 if the SRID prefix is malformed
+ */
+int32_t urbis_wkt_srid(const char *wkt);
+
+/**
+ * @brief Load one or more concatenated WKB geometries
+ */
+int urbis_load_wkb(UrbisIndex *idx, const uint8_t *data, size_t size);
+
+/**
+ * @brief Export an object as little-endian WKB
+ * @return Number of bytes required (call with a NULL buffer to size it),
+ *         or a negative error code
+ */
+int urbis_export_wkb(UrbisIndex *idx, uint64_t object_id, uint8_t *buffer, size_t buffer_size);
+
+/**
+ * @brief Export an object's geometry as a GeoJSON geometry object
+ * @return Length of the JSON text excluding the terminating NUL (call with
+ *         a NULL buffer to size it, then pass length + 1), or a negative
+ *         error code
+ */
+int urbis_export_geojson(UrbisIndex *idx, uint64_t object_id, char *buffer, size_t buffer_size);
+
 /* ============================================================================
  * Object Operations
  * ============================================================================ */
@@ -194,16 +370,119 @@ uint64_t urbis_insert_linestring(UrbisIndex *idx, const Point *points, size_t co
  */
 uint64_t urbis_insert_polygon(UrbisIndex *idx, const Point *exterior, size_t count);
 
+/**
+ * @brief Insert a point under a caller-chosen ID
+ *
+ * The _id inserts let callers keep IDs from another system. They fail with
+ * URBIS_ERR_INVALID for ID 0 and URBIS_ERR_EXISTS if the ID is taken (or,
+ * with point deduplication, if a point already exists at the location).
+ * Automatic IDs continue after the highest ID inserted.
+ */
+int urbis_insert_point_id(UrbisIndex *idx, uint64_t id, double x, double y);
+
+/**
+ * @brief Insert a linestring under a caller-chosen ID
+ */
+int urbis_insert_linestring_id(UrbisIndex *idx, uint64_t id,
+                               const Point *points, size_t count);
+
+/**
+ * @brief Insert a polygon under a caller-chosen ID
+ */
+int urbis_insert_polygon_id(UrbisIndex *idx, uint64_t id,
+                            const Point *exterior, size_t count);
+
+/**
+ * @brief Get the ID the next automatically numbered insert will assign
+ *
+ * An insert that returns a lower ID stored nothing new: with point
+ * deduplication, the point was counted against an existing one.
+ */
+uint64_t urbis_next_id(const UrbisIndex *idx);
+
+/**
+ * @brief Insert a multipoint
+ */
+uint64_t urbis_insert_multipoint(UrbisIndex *idx, const Point *points, size_t count);
+
+/**
+ * @brief Insert a multilinestring
+ *
+ * Parts are passed flattened: counts[i] points of part i follow
+ * the points of part i-1 in the points array.
+ */
+uint64_t urbis_insert_multilinestring(UrbisIndex *idx, const Point *points,
+                                      const size_t *counts, size_t num_parts);
+
+/**
+ * @brief Insert a multipolygon
+ *
+ * Exterior rings are passed flattened in the same layout as
+ * urbis_insert_multilinestring.
+ */
+uint64_t urbis_insert_multipolygon(UrbisIndex *idx, const Point *points,
+                                   const size_t *counts, size_t num_parts);
+
 /**
  * @brief Remove an object by ID
  */
 int urbis_remove(UrbisIndex *idx, uint64_t object_id);
 
+/**
+ * @brief Remove every object in a region
+ *
+ * match selects the inclusion rule: SI_MATCH_CONTAINED (MBR entirely
+ * inside), SI_MATCH_CENTROID (centroid inside) or SI_MATCH_INTERSECTS (MBR
+ * touches the region). Boundaries are inclusive. The count removed is
+ * stored in *removed.
+ */
+int urbis_remove_range(UrbisIndex *idx, const MBR *region, SpatialMatch match,
+                       size_t *removed);
+
 /**
  * @brief Get an object by ID
  */
 SpatialObject* urbis_get(UrbisIndex *idx, uint64_t object_id);
 
+/**
+ * @brief Replace an object's properties blob
+ *
+ * Geometry, ID and the spatial structures are left untouched, so the index
+ * does not need rebuilding. Passing size 0 clears the properties.
+ */
+int urbis_set_properties(UrbisIndex *idx, uint64_t object_id,
+                         const void *data, size_t size);
+
+/**
+ * @brief Replace the Z and/or M values of an object's vertices
+ *
+ * dims is GEOM_DIM_Z and/or GEOM_DIM_M, or 0 to make the object 2D. zm
+ * holds count values: one per selected ordinate per vertex, Z before M, in
+ * coordinate order (exterior ring, then holes, then the next part). A count
+ * that does not match the object fails with URBIS_ERR_INVALID. The index
+ * stays built.
+ */
+int urbis_set_zm(UrbisIndex *idx, uint64_t object_id, uint8_t dims,
+                 const double *zm, size_t count);
+
+/**
+ * @brief Number of vertices an insert of a linestring or polygon ring keeps
+ *
+ * Applies the index's snapping and simplification to a copy of points, so
+ * per-vertex values for urbis_set_zm can be checked before inserting.
+ * Returns 0 for a NULL argument, no points or a failed allocation.
+ */
+size_t urbis_stored_vertex_count(UrbisIndex *idx, bool polygon,
+                                 const Point *points, size_t count);
+
+/**
+ * @brief Get an object's properties blob
+ *
+ * Returns NULL when the object is missing or has no properties; *size is
+ * set to the blob length. The pointer is owned by the index.
+ */
+const void* urbis_get_properties(UrbisIndex *idx, uint64_t object_id, size_t *size);
+
 /* ============================================================================
  * Index Building
  * ============================================================================ */
@@ -216,30 +495,133 @@ SpatialObject* urbis_get(UrbisIndex *idx, uint64_t object_id);
  */
 int urbis_build(UrbisIndex *idx);
 
+/**
+ * @brief Build the spatial index, reporting progress
+ *
+ * on_progress is called synchronously with the number of objects
+ * processed and the total; the last call has done == total.
+ */
+int urbis_build_progress(UrbisIndex *idx, BuildProgressFn on_progress, void *user_data);
+
+/**
+ * @brief Build the spatial index, reporting progress until cancelled
+ *
+ * should_cancel is polled between build phases and while objects are
+ * gathered. Once it returns true the build stops with URBIS_ERR_CANCELLED
+ * and the index is left unbuilt, even if it was built before.
+ */
+int urbis_build_cancellable(UrbisIndex *idx, BuildProgressFn on_progress,
+                            BuildCancelFn should_cancel, void *user_data);
+
 /**
  * @brief Optimize index for better query performance
  */
 int urbis_optimize(UrbisIndex *idx);
 
+/**
+ * @brief Optimize the index until cancelled
+ *
+ * should_cancel is polled as in urbis_build_cancellable. Once it returns
+ * true the optimization stops with URBIS_ERR_CANCELLED. Unlike a cancelled
+ * build, the index is left as it was, so a built index keeps answering
+ * queries.
+ */
+int urbis_optimize_cancellable(UrbisIndex *idx, BuildCancelFn should_cancel, void *user_data);
+
 /* ============================================================================
  * Spatial Queries
  * ============================================================================ */
 
 /**
  * @brief Query objects in a bounding box
+ *
+ * Each page the query reads is checked against its checksum. Objects on a
+ * page that fails are left out and the page is listed in failed_pages.
  */
 UrbisObjectList* urbis_query_range(UrbisIndex *idx, const MBR *range);
 
+/**
+ * @brief Query objects in a bounding box using a preferred structure
+ *
+ * SI_STRUCTURE_KDTREE and SI_STRUCTURE_QUADTREE are honoured when that
+ * structure is built and current; otherwise the other tree (or a page scan)
+ * is used and stats.structure_fallback is set. SI_STRUCTURE_AUTO behaves
+ * like urbis_query_range.
+ */
+UrbisObjectList* urbis_query_range_using(UrbisIndex *idx, const MBR *range,
+                                         SpatialStructure structure);
+
 /**
  * @brief Query objects at a point
  */
 UrbisObjectList* urbis_query_point(UrbisIndex *idx, double x, double y);
 
+/**
+ * @brief Query objects at a point using a preferred structure
+ */
+UrbisObjectList* urbis_query_point_using(UrbisIndex *idx, double x, double y,
+                                         SpatialStructure structure);
+
+/**
+ * @brief Query polygons whose interior contains a point
+ *
+ * Candidates are pruned by MBR, then tested against the exact geometry.
+ * Points on a polygon's boundary (including hole edges) are not contained,
+ * and points inside a hole are excluded.
+ */
+UrbisObjectList* urbis_query_containing(UrbisIndex *idx, double x, double y);
+
+/**
+ * @brief Query polygons containing a point using a preferred structure
+ */
+UrbisObjectList* urbis_query_containing_using(UrbisIndex *idx, double x, double y,
+                                              SpatialStructure structure);
+
+/**
+ * @brief Query polygons that contain a polygon
+ *
+ * Candidates whose MBR holds the ring's are tested against the exact
+ * geometry, holes included. A polygon contains the ring when no part of
+ * the ring lies outside it or in one of its holes, so the ring may touch or
+ * share edges with its boundary; a multipolygon does when one of its parts
+ * does. The ring needs at least 3 points and may repeat its first point at
+ * the end or not.
+ */
+UrbisObjectList* urbis_query_containing_polygon(UrbisIndex *idx, const Point *ring,
+                                                size_t count);
+
 /**
  * @brief Query k nearest neighbors
  */
 UrbisObjectList* urbis_query_knn(UrbisIndex *idx, double x, double y, size_t k);
 
+/**
+ * @brief Find the object nearest to a point
+ *
+ * The same ranking as urbis_query_knn with k = 1, without allocating a
+ * result list. The index must be built.
+ *
+ * @param nearest Output: the nearest object, owned by the index and valid
+ *                until it is next modified
+ * @param distance Output: distance from (x, y) to the object's centroid in
+ *                 index coordinates (may be NULL)
+ * @return URBIS_OK, or URBIS_ERR_NOT_FOUND if the index is empty
+ */
+int urbis_nearest(UrbisIndex *idx, double x, double y,
+                  SpatialObject **nearest, double *distance);
+
+/**
+ * @brief Query objects inserted or modified at or after a time
+ *
+ * Every object records the time of its last insert, geometry update or
+ * properties change along with an index-wide version that increases with
+ * each modification. Results are in ascending version order. The index
+ * need not be built. Removed objects are not reported.
+ *
+ * @param since_ms Milliseconds since the Unix epoch
+ */
+UrbisObjectList* urbis_query_changed_since(UrbisIndex *idx, int64_t since_ms);
+
 /**
  * @brief Find adjacent pages to a region (uses quadtree)
  * 
@@ -249,6 +631,39 @@ UrbisObjectList* urbis_query_knn(UrbisIndex *idx, double x, double y, size_t k);
  */
 UrbisPageList* urbis_find_adjacent_pages(UrbisIndex *idx, const MBR *region);
 
+/**
+ * @brief Warm the page cache with the pages a region touches
+ * 
+ * Loads the pages urbis_find_adjacent_pages() would return into the page
+ * cache, so later queries over the region count them as cache hits.
+ * Pages beyond the cache capacity evict the least recently used ones.
+ * 
+ * @param loaded Receives the number of pages that were not yet cached (may be NULL)
+ */
+int urbis_prefetch_region(UrbisIndex *idx, const MBR *region, size_t *loaded);
+
+/**
+ * @brief Describe every page of the index
+ *
+ * Writes up to capacity entries, in page pool order, to pages.
+ * @return The number of pages in the index; call with capacity 0 to size
+ *         the buffer
+ */
+size_t urbis_list_pages(const UrbisIndex *idx, UrbisPageInfo *pages, size_t capacity);
+
+/**
+ * @brief Describe the nodes of the KD-tree or the page quadtree
+ *
+ * Writes up to capacity entries, in depth-first order with each parent
+ * before its children, to nodes. Only the top max_depth levels are listed
+ * (0 lists every level); counts still cover the whole subtree.
+ * @param structure SI_STRUCTURE_KDTREE or SI_STRUCTURE_QUADTREE
+ * @return The number of nodes listed, 0 when the tree is not built; call
+ *         with capacity 0 to size the buffer
+ */
+size_t urbis_list_tree_nodes(const UrbisIndex *idx, SpatialStructure structure,
+                             size_t max_depth, UrbisTreeNode *nodes, size_t capacity);
+
 /**
  * @brief Query objects in adjacent pages
  * 
@@ -262,6 +677,9 @@ UrbisObjectList* urbis_query_adjacent(UrbisIndex *idx, const MBR *region);
 
 /**
  * @brief Save index to a file
+ *
+ * As spatial_index_save, the file replaces any at path only once complete,
+ * and path becomes the data file urbis_sync writes to.
  */
 int urbis_save(UrbisIndex *idx, const char *path);
 
@@ -270,6 +688,62 @@ int urbis_save(UrbisIndex *idx, const char *path);
  */
 UrbisIndex* urbis_load(const char *path);
 
+/**
+ * @brief Load index from a file, reporting why it failed
+ *
+ * Fails with URBIS_ERR_VERSION if the file was saved in a newer format
+ * than urbis_format_version(), and URBIS_ERR_IO if it cannot be read or
+ * is not an index file. Files in older formats are migrated as they load.
+ * @param out Receives the index, or NULL on error
+ */
+int urbis_load_checked(const char *path, UrbisIndex **out);
+
+/**
+ * @brief Load index from a file into an index created with config
+ *
+ * As urbis_load_checked, but the quadtree, cache, snapping, simplification,
+ * deduplication and build settings come from config (NULL for the
+ * defaults) rather than the defaults. Valid bounds saved in the file
+ * replace those of config. config->persist and config->data_path should be
+ * unset: the index uses the file at path.
+ */
+int urbis_load_config(const char *path, const UrbisConfig *config, UrbisIndex **out);
+
+/**
+ * @brief Get the file format version urbis_save writes
+ */
+uint32_t urbis_format_version(void);
+
+/**
+ * @brief Read the format version of a saved index file
+ * @return URBIS_OK, or URBIS_ERR_IO if the file cannot be read or is not
+ *         an index file
+ */
+int urbis_file_format_version(const char *path, uint32_t *version);
+
+/**
+ * @brief Attach settings to be saved with the index
+ *
+ * The library does not interpret the bytes; a binding stores its own
+ * configuration here so a loaded index can be set up as the saved one
+ * was. The valid_bounds of the index are saved without this.
+ * @return URBIS_OK, or URBIS_ERR_FULL if size exceeds URBIS_MAX_SETTINGS
+ */
+int urbis_set_settings(UrbisIndex *idx, const void *data, size_t size);
+
+/**
+ * @brief Get the settings attached or loaded with the index
+ * @param size Receives the size in bytes (0 when there are none)
+ * @return The settings, owned by the index, or NULL when there are none
+ */
+const void* urbis_get_settings(const UrbisIndex *idx, size_t *size);
+
+/**
+ * @brief Get the valid_bounds the index was created or loaded with
+ * @return true and the box in out if the index is bounded
+ */
+bool urbis_valid_bounds(const UrbisIndex *idx, MBR *out);
+
 /**
  * @brief Sync changes to disk (if persistence enabled)
  */
@@ -284,6 +758,13 @@ int urbis_sync(UrbisIndex *idx);
  */
 void urbis_get_stats(const UrbisIndex *idx, UrbisStats *stats);
 
+/**
+ * @brief Heap bytes held by the index
+ *
+ * The memory_bytes figure of urbis_get_stats without the other statistics.
+ */
+size_t urbis_memory_usage(const UrbisIndex *idx);
+
 /**
  * @brief Get number of objects in index
  */
@@ -294,6 +775,35 @@ size_t urbis_count(const UrbisIndex *idx);
  */
 MBR urbis_bounds(const UrbisIndex *idx);
 
+/**
+ * @brief Check whether the index has been built since the last change
+ */
+bool urbis_is_built(const UrbisIndex *idx);
+
+/**
+ * @brief Count the inserts and removals made since the last successful build
+ */
+size_t urbis_pending_changes(const UrbisIndex *idx);
+
+/**
+ * @brief Count the objects refused for reaching outside the configured
+ * valid_bounds since the index was created
+ *
+ * Inserts and updates of such objects fail with URBIS_ERR_INVALID. GeoJSON
+ * loads leave them out and go on, so the count is how a caller learns how
+ * many a load skipped.
+ */
+size_t urbis_out_of_bounds(const UrbisIndex *idx);
+
+/**
+ * @brief Count every insert, removal, property and Z/M update made to the
+ * index since it was created or loaded
+ *
+ * The count only grows, so a caller that notes it after a save can tell
+ * whether the index has changed since.
+ */
+uint64_t urbis_change_count(const UrbisIndex *idx);
+
 /**
  * @brief Print statistics to a file
  */
@@ -305,6 +815,81 @@ void urbis_print_stats(const UrbisIndex *idx, FILE *out);
 size_t urbis_estimate_seeks(const UrbisIndex *idx, 
                             const MBR *regions, size_t count);
 
+/**
+ * @brief Estimate read time in milliseconds for a sequence of queries
+ *
+ * Sums the estimated_cost_ms of urbis_find_adjacent_pages() over regions,
+ * under the cost model the index was created with.
+ */
+double urbis_estimate_cost_ms(const UrbisIndex *idx,
+                              const MBR *regions, size_t count);
+
+/**
+ * @brief Estimate how many objects a range query would return
+ *
+ * Sums the object counts of the pages whose extents intersect region,
+ * without reading any object. Every match lies on such a page, so the
+ * estimate is an upper bound; objects near but outside region are counted
+ * too.
+ */
+size_t urbis_estimate_count(const UrbisIndex *idx, const MBR *region);
+
+/**
+ * @brief Recommend a page capacity for the current data
+ * 
+ * Lays the data out on scratch copies with page capacities of 8, 16, 32
+ * and 64 objects and scores each by estimated read time over the sample
+ * queries and page utilization. Without sample queries, the central
+ * quarter and the four quadrants of the index bounds are used. An empty
+ * index recommends its current capacity and evaluates no candidates.
+ */
+int urbis_autotune(const UrbisIndex *idx, const MBR *queries, size_t query_count,
+                   UrbisTuneResult *result);
+
+/**
+ * @brief Recommend a page capacity until cancelled
+ *
+ * should_cancel is polled while each scratch copy is made, as in
+ * urbis_repage_cancellable. Once it returns true the tuning stops with
+ * URBIS_ERR_CANCELLED. The index is never changed.
+ */
+int urbis_autotune_cancellable(const UrbisIndex *idx, const MBR *queries, size_t query_count,
+                               BuildCancelFn should_cancel, void *user_data,
+                               UrbisTuneResult *result);
+
+/**
+ * @brief Create a built copy of an index with a different page capacity
+ * 
+ * @return The new index, or NULL on failure. The original is unchanged.
+ */
+UrbisIndex* urbis_repage(const UrbisIndex *idx, size_t page_capacity);
+
+/**
+ * @brief Create a built copy of an index with a different page capacity
+ *        until cancelled
+ *
+ * should_cancel is polled after each page is copied and while the copy is
+ * built. Once it returns true the copy is dropped and URBIS_ERR_CANCELLED
+ * returned. The original is unchanged either way.
+ *
+ * @param out Output: the new index on success
+ * @return URBIS_OK, URBIS_ERR_INVALID for a page capacity of 0 or above
+ *         the page limit, URBIS_ERR_CANCELLED or URBIS_ERR_ALLOC
+ */
+int urbis_repage_cancellable(const UrbisIndex *idx, size_t page_capacity,
+                             BuildCancelFn should_cancel, void *user_data, UrbisIndex **out);
+
+/**
+ * @brief Move an index's data file onto a copy that is to replace it
+ *
+ * The file is rewritten with the copy's pages, so later syncs of the copy
+ * go to it, and idx no longer writes to it. If the rewrite fails, idx is
+ * saved back to the file. Does nothing when idx has no data file.
+ *
+ * @return URBIS_OK, URBIS_ERR_IO or URBIS_ERR_ALLOC
+ */
+int urbis_adopt_data_file(UrbisIndex *copy, UrbisIndex *idx);
+
 /* ============================================================================
  * Result List Operations
  * ============================================================================ */
@@ -106,10 +106,19 @@ static int write_header(DiskManager *dm) {
     
     dm->header.modified_time = get_current_time();
     
+    dm->header.settings_size = (uint32_t)dm->settings_size;
     if (fwrite(&dm->header, sizeof(DiskFileHeader), 1, dm->data_file) != 1) {
         return DM_ERR_IO;
     }
     
+    /* Settings live in the region reserved between the header and the pages */
+    if (dm->settings_size > 0) {
+        if (fseek(dm->data_file, (long)dm->header.index_offset, SEEK_SET) != 0 ||
+            fwrite(dm->settings, dm->settings_size, 1, dm->data_file) != 1) {
+            return DM_ERR_IO;
+        }
+    }
+    
     fflush(dm->data_file);
     
     return DM_OK;
@@ -138,6 +147,24 @@ static int read_header(DiskManager *dm) {
         return DM_ERR_VERSION;
     }
     
+    /* Read the settings, which must fit before the first page */
+    free(dm->settings);
+    dm->settings = NULL;
+    dm->settings_size = 0;
+    size_t size = dm->header.settings_size;
+    if (size == 0) return DM_OK;
+    if (size > DM_MAX_SETTINGS || dm->header.index_offset + size > dm->header.data_offset) {
+        return DM_ERR_CORRUPT;
+    }
+    
+    dm->settings = malloc(size);
+    if (!dm->settings) return DM_ERR_ALLOC;
+    if (fseek(dm->data_file, (long)dm->header.index_offset, SEEK_SET) != 0 ||
+        fread(dm->settings, size, 1, dm->data_file) != 1) {
+        return DM_ERR_IO;
+    }
+    dm->settings_size = size;
+    
     return DM_OK;
 }
 
@@ -171,6 +198,7 @@ DiskManagerConfig disk_manager_default_config(void) {
         .cache_size = DM_DEFAULT_CACHE_SIZE,
         .page_size = PAGE_SIZE,
         .pages_per_track = PAGES_PER_TRACK,
+        .page_capacity = MAX_OBJECTS_PER_PAGE,
         .strategy = ALLOC_BEST_FIT,
         .use_mmap = false,
         .sync_on_write = false
@@ -220,6 +248,7 @@ void disk_manager_free(DiskManager *dm) {
     page_cache_free(&dm->cache);
     page_pool_free(&dm->pool);
     free(dm->file_path);
+    free(dm->settings);
     
     memset(dm, 0, sizeof(DiskManager));
 }
@@ -305,6 +334,10 @@ int disk_manager_open(DiskManager *dm, const char *path) {
         return err;
     }
     
+    /* Every earlier version shares this layout; fields added since
+     * (next_object_id, the settings and valid bounds) read as 0 */
+    dm->header.version = DM_VERSION;
+    
     /* Load pages into pool */
     for (uint32_t i = 1; i <= dm->header.page_count; i++) {
         Page *page = page_pool_alloc(&dm->pool, 0);
@@ -313,6 +346,9 @@ int disk_manager_open(DiskManager *dm, const char *path) {
         page->header.page_id = i;
         read_page_from_disk(dm, page);
         
+        /* The stored flags are from when the page was written; it matches the file now */
+        page->header.flags &= ~PAGE_STATUS_DIRTY;
+        
         /* Update allocation tree */
         if (page->header.object_count > 0) {
             update_allocation_tree(dm, page);
@@ -325,6 +361,25 @@ int disk_manager_open(DiskManager *dm, const char *path) {
     return DM_OK;
 }
 
+int disk_manager_set_settings(DiskManager *dm, const void *data, size_t size) {
+    if (!dm || (!data && size > 0)) return DM_ERR_NULL_PTR;
+    if (size > DM_MAX_SETTINGS) return DM_ERR_FULL;
+    
+    void *copy = NULL;
+    if (size > 0) {
+        copy = malloc(size);
+        if (!copy) return DM_ERR_ALLOC;
+        memcpy(copy, data, size);
+    }
+    
+    free(dm->settings);
+    dm->settings = copy;
+    dm->settings_size = size;
+    dm->is_dirty = true;
+    
+    return DM_OK;
+}
+
 int disk_manager_close(DiskManager *dm) {
     if (!dm) return DM_ERR_NULL_PTR;
     
@@ -333,6 +388,14 @@ int disk_manager_close(DiskManager *dm) {
     /* Flush dirty pages */
     disk_manager_sync(dm);
     
+    return disk_manager_detach(dm);
+}
+
+int disk_manager_detach(DiskManager *dm) {
+    if (!dm) return DM_ERR_NULL_PTR;
+    
+    if (!dm->is_open) return DM_OK;
+    
     /* Unmap if using mmap */
 #ifdef __linux__
     if (dm->mmap_base && dm->mmap_size > 0) {
@@ -347,12 +410,32 @@ int disk_manager_close(DiskManager *dm) {
         fclose(dm->data_file);
         dm->data_file = NULL;
     }
+    free(dm->file_path);
+    dm->file_path = NULL;
     
     dm->is_open = false;
     
     return DM_OK;
 }
 
+int disk_manager_rename(DiskManager *dm, const char *path) {
+    if (!dm || !path) return DM_ERR_NULL_PTR;
+    if (!dm->is_open) return DM_ERR_NOT_OPEN;
+    
+    char *copy = strdup(path);
+    if (!copy) return DM_ERR_ALLOC;
+    
+    /* The open stream follows the file to its new name */
+    if (fflush(dm->data_file) != 0 || rename(dm->file_path, path) != 0) {
+        free(copy);
+        return DM_ERR_IO;
+    }
+    free(dm->file_path);
+    dm->file_path = copy;
+    
+    return DM_OK;
+}
+
 int disk_manager_sync(DiskManager *dm) {
     if (!dm || !dm->is_open) return DM_ERR_NOT_OPEN;
     
@@ -396,6 +479,14 @@ Page* disk_manager_alloc_page(DiskManager *dm, Point centroid) {
     Page *page = page_pool_alloc(&dm->pool, track->track_id);
     if (!page) return NULL;
     
+    if (dm->config.page_capacity > 0 && dm->config.page_capacity < page->object_capacity) {
+        /* Give back the unused slots; if the shrink fails the larger array still works */
+        SpatialObject *objects = (SpatialObject *)realloc(page->objects,
+            dm->config.page_capacity * sizeof(SpatialObject));
+        if (objects) page->objects = objects;
+        page->object_capacity = dm->config.page_capacity;
+    }
+    
     page->header.centroid = centroid;
     
     /* Add to track */
@@ -431,17 +522,14 @@ int disk_manager_free_page(DiskManager *dm, uint32_t page_id) {
 Page* disk_manager_get_page(DiskManager *dm, uint32_t page_id) {
     if (!dm) return NULL;
     
-    /* Try cache first */
-    Page *page = page_cache_get(&dm->cache, page_id);
-    if (page) {
+    /* Count residency before the lookup, which caches the page */
+    if (page_cache_contains(&dm->cache, page_id)) {
         dm->stats.cache_hits++;
-        return page;
+    } else {
+        dm->stats.cache_misses++;
     }
     
-    dm->stats.cache_misses++;
-    
-    /* Get from pool */
-    page = page_pool_get(&dm->pool, page_id);
+    Page *page = page_cache_get(&dm->cache, page_id);
     if (!page) return NULL;
     
     /* Load from disk if needed */
@@ -652,7 +740,13 @@ int disk_manager_query_tracks(DiskManager *dm, const MBR *region,
  * ============================================================================ */
 
 size_t disk_manager_file_size(const DiskManager *dm) {
-    if (!dm || !dm->data_file) return 0;
+    if (!dm) return 0;
+    
+    if (!dm->data_file) {
+        struct stat st;
+        if (!dm->file_path || stat(dm->file_path, &st) != 0) return 0;
+        return (size_t)st.st_size;
+    }
     
     long current = ftell(dm->data_file);
     fseek(dm->data_file, 0, SEEK_END);
@@ -662,6 +756,32 @@ size_t disk_manager_file_size(const DiskManager *dm) {
     return (size_t)size;
 }
 
+size_t disk_manager_memory(const DiskManager *dm) {
+    if (!dm) return 0;
+    
+    size_t bytes = page_pool_memory(&dm->pool) + page_cache_memory(&dm->cache) +
+                   kdtree_memory(&dm->allocation_tree);
+    if (dm->file_path) bytes += strlen(dm->file_path) + 1;
+    
+    return bytes;
+}
+
+int disk_manager_read_version(const char *path, uint32_t *version) {
+    if (!path || !version) return DM_ERR_NULL_PTR;
+    
+    FILE *f = fopen(path, "rb");
+    if (!f) return DM_ERR_IO;
+    
+    DiskFileHeader header;
+    size_t n = fread(&header, sizeof(DiskFileHeader), 1, f);
+    fclose(f);
+    if (n != 1) return DM_ERR_IO;
+    if (header.magic != DM_MAGIC) return DM_ERR_CORRUPT;
+    
+    *version = header.version;
+    return DM_OK;
+}
+
 bool disk_manager_file_exists(const char *path) {
     if (!path) return false;
     struct stat st;
@@ -384,6 +384,250 @@ int polygon_copy(Polygon *dest, const Polygon *src) {
     return GEOM_OK;
 }
 
+/**
+ * @brief Check if p lies on segment ab (within EPSILON)
+ */
+static bool point_on_segment(const Point *p, const Point *a, const Point *b) {
+    double cross = (b->x - a->x) * (p->y - a->y) - (b->y - a->y) * (p->x - a->x);
+    if (fabs(cross) > EPSILON * fmax(1.0, point_distance(a, b))) return false;
+    
+    return p->x >= fmin(a->x, b->x) - EPSILON && p->x <= fmax(a->x, b->x) + EPSILON &&
+           p->y >= fmin(a->y, b->y) - EPSILON && p->y <= fmax(a->y, b->y) + EPSILON;
+}
+
+/**
+ * @brief Locate a point against a ring: 1 inside, 0 on the ring, -1 outside
+ */
+static int ring_locate_point(const Point *ring, size_t count, const Point *p) {
+    if (count < 3) return -1;
+    
+    bool inside = false;
+    for (size_t i = 0, j = count - 1; i < count; j = i++) {
+        const Point *a = &ring[i];
+        const Point *b = &ring[j];
+        
+        if (point_on_segment(p, a, b)) return 0;
+        
+        /* Ray casting towards +x */
+        if ((a->y > p->y) != (b->y > p->y)) {
+            double x_cross = a->x + (p->y - a->y) * (b->x - a->x) / (b->y - a->y);
+            if (p->x < x_cross) inside = !inside;
+        }
+    }
+    
+    return inside ? 1 : -1;
+}
+
+int polygon_locate_point(const Polygon *poly, const Point *p) {
+    if (!poly || !p) return -1;
+    
+    int loc = ring_locate_point(poly->exterior, poly->ext_count, p);
+    if (loc <= 0) return loc;
+    
+    for (size_t h = 0; h < poly->num_holes; h++) {
+        int hole_loc = ring_locate_point(poly->holes[h], poly->hole_counts[h], p);
+        if (hole_loc == 0) return 0;
+        if (hole_loc > 0) return -1;
+    }
+    
+    return 1;
+}
+
+bool polygon_contains_point(const Polygon *poly, const Point *p) {
+    return polygon_locate_point(poly, p) > 0;
+}
+
+/* Where the pieces of a ring's edges lie, as a mask */
+#define PIECE_INSIDE   1
+#define PIECE_BOUNDARY 2
+#define PIECE_OUTSIDE  4
+
+/**
+ * @brief Add to t the parameters along ab where it meets segment cd
+ *
+ * A crossing or touch adds one parameter; a collinear overlap adds the
+ * projections of c and d. Parameters outside (0, 1) are left out, and
+ * extra ones only split ab more finely.
+ * @return Number of parameters added
+ */
+static size_t segment_meets(const Point *a, const Point *b, const Point *c, const Point *d,
+                            double *t) {
+    double rx = b->x - a->x, ry = b->y - a->y;
+    double sx = d->x - c->x, sy = d->y - c->y;
+    double qx = c->x - a->x, qy = c->y - a->y;
+    double len_sq = rx * rx + ry * ry;
+    if (len_sq == 0) return 0;
+    
+    double denom = rx * sy - ry * sx;
+    if (fabs(denom) > EPSILON * sqrt(len_sq * (sx * sx + sy * sy))) {
+        double along_cd = (qx * ry - qy * rx) / denom;
+        double along_ab = (qx * sy - qy * sx) / denom;
+        if (along_cd < -EPSILON || along_cd > 1 + EPSILON) return 0;
+        if (along_ab <= 0 || along_ab >= 1) return 0;
+        t[0] = along_ab;
+        return 1;
+    }
+    
+    /* Parallel segments only meet when collinear */
+    if (fabs(qx * ry - qy * rx) > EPSILON * sqrt(len_sq)) return 0;
+    
+    size_t n = 0;
+    double tc = (qx * rx + qy * ry) / len_sq;
+    double td = ((d->x - a->x) * rx + (d->y - a->y) * ry) / len_sq;
+    if (tc > 0 && tc < 1) t[n++] = tc;
+    if (td > 0 && td < 1) t[n++] = td;
+    return n;
+}
+
+static int compare_params(const void *a, const void *b) {
+    double x = *(const double *)a, y = *(const double *)b;
+    return (x > y) - (x < y);
+}
+
+/**
+ * @brief Add to t the parameters along ab where it meets a ring's edges
+ */
+static size_t ring_meets(const Point *a, const Point *b, const Point *ring, size_t count,
+                         double *t) {
+    size_t n = 0;
+    for (size_t i = 0, j = count - 1; i < count; j = i++) {
+        n += segment_meets(a, b, &ring[j], &ring[i], t + n);
+    }
+    return n;
+}
+
+/**
+ * @brief Locate the pieces of a ring's edges against a polygon
+ *
+ * Each edge is split wherever it meets one of the polygon's rings, and the
+ * midpoint of every piece is located. A piece crosses no edge, so its
+ * midpoint stands for all of it.
+ * @return Mask of PIECE_* flags seen, or GEOM_ERR_ALLOC
+ */
+static int ring_piece_locations(const Point *ring, size_t count, const Polygon *area) {
+    size_t area_edges = area->ext_count;
+    for (size_t h = 0; h < area->num_holes; h++) {
+        area_edges += area->hole_counts[h];
+    }
+    double *t = (double *)malloc((2 * area_edges + 2) * sizeof(double));
+    if (!t) return GEOM_ERR_ALLOC;
+    
+    int seen = 0;
+    for (size_t i = 0, j = count - 1; i < count; j = i++) {
+        const Point *a = &ring[j];
+        const Point *b = &ring[i];
+        
+        size_t n = 0;
+        t[n++] = 0;
+        t[n++] = 1;
+        n += ring_meets(a, b, area->exterior, area->ext_count, t + n);
+        for (size_t h = 0; h < area->num_holes; h++) {
+            n += ring_meets(a, b, area->holes[h], area->hole_counts[h], t + n);
+        }
+        qsort(t, n, sizeof(double), compare_params);
+        
+        for (size_t k = 1; k < n; k++) {
+            if (t[k] == t[k - 1]) continue;
+            double mid = (t[k - 1] + t[k]) / 2;
+            Point p = point_create(a->x + mid * (b->x - a->x), a->y + mid * (b->y - a->y));
+            int loc = polygon_locate_point(area, &p);
+            seen |= loc > 0 ? PIECE_INSIDE : loc == 0 ? PIECE_BOUNDARY : PIECE_OUTSIDE;
+        }
+    }
+    
+    free(t);
+    return seen;
+}
+
+int polygon_covers_ring(const Polygon *poly, const Point *ring, size_t count) {
+    if (!poly || !ring || count < 3 || poly->ext_count < 3) return 0;
+    
+    int seen = ring_piece_locations(ring, count, poly);
+    if (seen < 0) return seen;
+    if (seen & PIECE_OUTSIDE) return 0;
+    
+    /* The ring's edges stay out of every hole, so each hole lies wholly
+     * inside the ring or wholly outside it; one inside leaves a gap */
+    Polygon area = {0};
+    area.exterior = (Point *)ring;
+    area.ext_count = count;
+    for (size_t h = 0; h < poly->num_holes; h++) {
+        if (poly->hole_counts[h] < 3) continue;
+        seen = ring_piece_locations(poly->holes[h], poly->hole_counts[h], &area);
+        if (seen < 0) return seen;
+        if (!(seen & PIECE_OUTSIDE)) return 0;
+    }
+    
+    return 1;
+}
+
+/* ============================================================================
+ * Multi-Geometry Operations
+ * ============================================================================ */
+
+/**
+ * @brief Ensure a parts array has room for one more element
+ */
+static int grow_parts(void **items, size_t *capacity, size_t count, size_t item_size) {
+    if (count < *capacity) return GEOM_OK;
+    
+    size_t new_capacity = *capacity > 0 ? *capacity * GROWTH_FACTOR : 4;
+    void *new_items = realloc(*items, new_capacity * item_size);
+    if (!new_items) return GEOM_ERR_ALLOC;
+    
+    *items = new_items;
+    *capacity = new_capacity;
+    return GEOM_OK;
+}
+
+int multipoint_add_point(MultiPoint *mp, Point p) {
+    if (!mp) return GEOM_ERR_NULL_PTR;
+    
+    int err = grow_parts((void **)&mp->points, &mp->capacity, mp->count, sizeof(Point));
+    if (err != GEOM_OK) return err;
+    
+    mp->points[mp->count++] = p;
+    return GEOM_OK;
+}
+
+int multilinestring_add_line(MultiLineString *mls, size_t capacity) {
+    if (!mls) return GEOM_ERR_NULL_PTR;
+    
+    int err = grow_parts((void **)&mls->lines, &mls->capacity, mls->count, sizeof(LineString));
+    if (err != GEOM_OK) return err;
+    
+    err = linestring_init(&mls->lines[mls->count], capacity);
+    if (err != GEOM_OK) return err;
+    
+    return (int)mls->count++;
+}
+
+int multipolygon_add_polygon(MultiPolygon *mp, size_t ext_capacity) {
+    if (!mp) return GEOM_ERR_NULL_PTR;
+    
+    int err = grow_parts((void **)&mp->polygons, &mp->capacity, mp->count, sizeof(Polygon));
+    if (err != GEOM_OK) return err;
+    
+    err = polygon_init(&mp->polygons[mp->count], ext_capacity);
+    if (err != GEOM_OK) return err;
+    
+    return (int)mp->count++;
+}
+
+int geometry_collection_add(GeometryCollection *gc, const SpatialObject *geom) {
+    if (!gc || !geom) return GEOM_ERR_NULL_PTR;
+    
+    int err = grow_parts((void **)&gc->geometries, &gc->capacity, gc->count,
+                         sizeof(SpatialObject));
+    if (err != GEOM_OK) return err;
+    
+    err = spatial_object_copy(&gc->geometries[gc->count], geom);
+    if (err != GEOM_OK) return err;
+    
+    gc->count++;
+    return GEOM_OK;
+}
+
 /* ============================================================================
  * MBR Operations
  * ============================================================================ */
@@ -549,6 +793,52 @@ int spatial_object_init_polygon(SpatialObject *obj, uint64_t id, size_t ext_capa
     return GEOM_OK;
 }
 
+/**
+ * @brief Common setup for multi-geometry spatial objects
+ */
+static int init_multi(SpatialObject *obj, uint64_t id, GeomType type,
+                      void **items, size_t *capacity, size_t count, size_t item_size) {
+    if (!obj) return GEOM_ERR_NULL_PTR;
+    
+    memset(obj, 0, sizeof(SpatialObject));
+    obj->id = id;
+    obj->type = type;
+    obj->centroid = point_create(0, 0);
+    obj->mbr = mbr_empty();
+    
+    if (count == 0) return GEOM_OK;
+    
+    *items = malloc(count * item_size);
+    if (!*items) return GEOM_ERR_ALLOC;
+    *capacity = count;
+    
+    return GEOM_OK;
+}
+
+int spatial_object_init_multipoint(SpatialObject *obj, uint64_t id, size_t capacity) {
+    if (!obj) return GEOM_ERR_NULL_PTR;
+    return init_multi(obj, id, GEOM_MULTIPOINT, (void **)&obj->geom.multi_point.points,
+                      &obj->geom.multi_point.capacity, capacity, sizeof(Point));
+}
+
+int spatial_object_init_multilinestring(SpatialObject *obj, uint64_t id, size_t capacity) {
+    if (!obj) return GEOM_ERR_NULL_PTR;
+    return init_multi(obj, id, GEOM_MULTILINESTRING, (void **)&obj->geom.multi_line.lines,
+                      &obj->geom.multi_line.capacity, capacity, sizeof(LineString));
+}
+
+int spatial_object_init_multipolygon(SpatialObject *obj, uint64_t id, size_t capacity) {
+    if (!obj) return GEOM_ERR_NULL_PTR;
+    return init_multi(obj, id, GEOM_MULTIPOLYGON, (void **)&obj->geom.multi_polygon.polygons,
+                      &obj->geom.multi_polygon.capacity, capacity, sizeof(Polygon));
+}
+
+int spatial_object_init_collection(SpatialObject *obj, uint64_t id, size_t capacity) {
+    if (!obj) return GEOM_ERR_NULL_PTR;
+    return init_multi(obj, id, GEOM_GEOMETRYCOLLECTION, (void **)&obj->geom.collection.geometries,
+                      &obj->geom.collection.capacity, capacity, sizeof(SpatialObject));
+}
+
 void spatial_object_free(SpatialObject *obj) {
     if (!obj) return;
     
@@ -559,12 +849,34 @@ void spatial_object_free(SpatialObject *obj) {
         case GEOM_POLYGON:
             polygon_free(&obj->geom.polygon);
             break;
+        case GEOM_MULTIPOINT:
+            free(obj->geom.multi_point.points);
+            break;
+        case GEOM_MULTILINESTRING:
+            for (size_t i = 0; i < obj->geom.multi_line.count; i++) {
+                linestring_free(&obj->geom.multi_line.lines[i]);
+            }
+            free(obj->geom.multi_line.lines);
+            break;
+        case GEOM_MULTIPOLYGON:
+            for (size_t i = 0; i < obj->geom.multi_polygon.count; i++) {
+                polygon_free(&obj->geom.multi_polygon.polygons[i]);
+            }
+            free(obj->geom.multi_polygon.polygons);
+            break;
+        case GEOM_GEOMETRYCOLLECTION:
+            for (size_t i = 0; i < obj->geom.collection.count; i++) {
+                spatial_object_free(&obj->geom.collection.geometries[i]);
+            }
+            free(obj->geom.collection.geometries);
+            break;
         case GEOM_POINT:
         default:
             break;
     }
     
     free(obj->properties);
+    free(obj->zm);
     memset(obj, 0, sizeof(SpatialObject));
 }
 
@@ -591,6 +903,86 @@ int spatial_object_update_derived(SpatialObject *obj) {
             if (err != GEOM_OK) return err;
             err = polygon_mbr(&obj->geom.polygon, &obj->mbr);
             break;
+            
+        case GEOM_MULTIPOINT: {
+            const MultiPoint *mp = &obj->geom.multi_point;
+            if (mp->count == 0) return GEOM_ERR_EMPTY_GEOM;
+            
+            double cx = 0, cy = 0;
+            obj->mbr = mbr_empty();
+            for (size_t i = 0; i < mp->count; i++) {
+                cx += mp->points[i].x;
+                cy += mp->points[i].y;
+                mbr_expand_point(&obj->mbr, &mp->points[i]);
+            }
+            obj->centroid = point_create(cx / mp->count, cy / mp->count);
+            break;
+        }
+            
+        case GEOM_MULTILINESTRING: {
+            /* Length-weighted average of part centroids */
+            const MultiLineString *mls = &obj->geom.multi_line;
+            if (mls->count == 0) return GEOM_ERR_EMPTY_GEOM;
+            
+            double cx = 0, cy = 0, total = 0;
+            obj->mbr = mbr_empty();
+            for (size_t i = 0; i < mls->count; i++) {
+                Point c;
+                MBR m;
+                if (linestring_centroid(&mls->lines[i], &c) != GEOM_OK) continue;
+                linestring_mbr(&mls->lines[i], &m);
+                mbr_expand_mbr(&obj->mbr, &m);
+                
+                double w = linestring_length(&mls->lines[i]);
+                if (w < EPSILON) w = EPSILON;
+                cx += c.x * w;
+                cy += c.y * w;
+                total += w;
+            }
+            if (total == 0) return GEOM_ERR_EMPTY_GEOM;
+            obj->centroid = point_create(cx / total, cy / total);
+            break;
+        }
+            
+        case GEOM_MULTIPOLYGON: {
+            /* Area-weighted average of part centroids */
+            const MultiPolygon *mp = &obj->geom.multi_polygon;
+            if (mp->count == 0) return GEOM_ERR_EMPTY_GEOM;
+            
+            double cx = 0, cy = 0, total = 0;
+            obj->mbr = mbr_empty();
+            for (size_t i = 0; i < mp->count; i++) {
+                Point c;
+                MBR m;
+                if (polygon_centroid(&mp->polygons[i], &c) != GEOM_OK) continue;
+                polygon_mbr(&mp->polygons[i], &m);
+                mbr_expand_mbr(&obj->mbr, &m);
+                
+                double w = fabs(polygon_area(&mp->polygons[i]));
+                if (w < EPSILON) w = EPSILON;
+                cx += c.x * w;
+                cy += c.y * w;
+                total += w;
+            }
+            if (total == 0) return GEOM_ERR_EMPTY_GEOM;
+            obj->centroid = point_create(cx / total, cy / total);
+            break;
+        }
+            
+        case GEOM_GEOMETRYCOLLECTION: {
+            const GeometryCollection *gc = &obj->geom.collection;
+            if (gc->count == 0) return GEOM_ERR_EMPTY_GEOM;
+            
+            double cx = 0, cy = 0;
+            obj->mbr = mbr_empty();
+            for (size_t i = 0; i < gc->count; i++) {
+                cx += gc->geometries[i].centroid.x;
+                cy += gc->geometries[i].centroid.y;
+                mbr_expand_mbr(&obj->mbr, &gc->geometries[i].mbr);
+            }
+            obj->centroid = point_create(cx / gc->count, cy / gc->count);
+            break;
+        }
     }
     
     return err;
@@ -604,6 +996,8 @@ int spatial_object_copy(SpatialObject *dest, const SpatialObject *src) {
     dest->type = src->type;
     dest->centroid = src->centroid;
     dest->mbr = src->mbr;
+    dest->version = src->version;
+    dest->modified_at = src->modified_at;
     
     int err = GEOM_OK;
     
@@ -619,17 +1013,117 @@ int spatial_object_copy(SpatialObject *dest, const SpatialObject *src) {
         case GEOM_POLYGON:
             err = polygon_copy(&dest->geom.polygon, &src->geom.polygon);
             break;
+            
+        case GEOM_MULTIPOINT:
+            for (size_t i = 0; i < src->geom.multi_point.count && err == GEOM_OK; i++) {
+                err = multipoint_add_point(&dest->geom.multi_point,
+                                           src->geom.multi_point.points[i]);
+            }
+            break;
+            
+        case GEOM_MULTILINESTRING:
+            for (size_t i = 0; i < src->geom.multi_line.count && err == GEOM_OK; i++) {
+                err = grow_parts((void **)&dest->geom.multi_line.lines,
+                                 &dest->geom.multi_line.capacity,
+                                 dest->geom.multi_line.count, sizeof(LineString));
+                if (err != GEOM_OK) break;
+                err = linestring_copy(&dest->geom.multi_line.lines[i],
+                                      &src->geom.multi_line.lines[i]);
+                if (err == GEOM_OK) dest->geom.multi_line.count++;
+            }
+            break;
+            
+        case GEOM_MULTIPOLYGON:
+            for (size_t i = 0; i < src->geom.multi_polygon.count && err == GEOM_OK; i++) {
+                err = grow_parts((void **)&dest->geom.multi_polygon.polygons,
+                                 &dest->geom.multi_polygon.capacity,
+                                 dest->geom.multi_polygon.count, sizeof(Polygon));
+                if (err != GEOM_OK) break;
+                err = polygon_copy(&dest->geom.multi_polygon.polygons[i],
+                                   &src->geom.multi_polygon.polygons[i]);
+                if (err == GEOM_OK) dest->geom.multi_polygon.count++;
+            }
+            break;
+            
+        case GEOM_GEOMETRYCOLLECTION:
+            for (size_t i = 0; i < src->geom.collection.count && err == GEOM_OK; i++) {
+                err = geometry_collection_add(&dest->geom.collection,
+                                              &src->geom.collection.geometries[i]);
+            }
+            break;
     }
     
-    if (err != GEOM_OK) return err;
+    if (err != GEOM_OK) {
+        spatial_object_free(dest);
+        return err;
+    }
     
     if (src->properties && src->properties_size > 0) {
         err = spatial_object_set_properties(dest, src->properties, src->properties_size);
     }
+    if (err == GEOM_OK && src->dims) {
+        err = spatial_object_set_zm(dest, src->dims, src->zm);
+    }
     
     return err;
 }
 
+static size_t polygon_memory(const Polygon *poly) {
+    size_t bytes = poly->ext_capacity * sizeof(Point);
+    bytes += poly->holes_capacity * (sizeof(Point *) + 2 * sizeof(size_t));
+    for (size_t i = 0; i < poly->num_holes; i++) {
+        bytes += poly->hole_capacities[i] * sizeof(Point);
+    }
+    return bytes;
+}
+
+size_t spatial_object_memory(const SpatialObject *obj) {
+    if (!obj) return 0;
+    
+    size_t bytes = obj->properties_size;
+    bytes += geom_dims_width(obj->dims) * spatial_object_vertex_count(obj) * sizeof(double);
+    
+    switch (obj->type) {
+        case GEOM_POINT:
+            break;
+            
+        case GEOM_LINESTRING:
+            bytes += obj->geom.line.capacity * sizeof(Point);
+            break;
+            
+        case GEOM_POLYGON:
+            bytes += polygon_memory(&obj->geom.polygon);
+            break;
+            
+        case GEOM_MULTIPOINT:
+            bytes += obj->geom.multi_point.capacity * sizeof(Point);
+            break;
+            
+        case GEOM_MULTILINESTRING:
+            bytes += obj->geom.multi_line.capacity * sizeof(LineString);
+            for (size_t i = 0; i < obj->geom.multi_line.count; i++) {
+                bytes += obj->geom.multi_line.lines[i].capacity * sizeof(Point);
+            }
+            break;
+            
+        case GEOM_MULTIPOLYGON:
+            bytes += obj->geom.multi_polygon.capacity * sizeof(Polygon);
+            for (size_t i = 0; i < obj->geom.multi_polygon.count; i++) {
+                bytes += polygon_memory(&obj->geom.multi_polygon.polygons[i]);
+            }
+            break;
+            
+        case GEOM_GEOMETRYCOLLECTION:
+            bytes += obj->geom.collection.capacity * sizeof(SpatialObject);
+            for (size_t i = 0; i < obj->geom.collection.count; i++) {
+                bytes += spatial_object_memory(&obj->geom.collection.geometries[i]);
+            }
+            break;
+    }
+    
+    return bytes;
+}
+
 int spatial_object_set_properties(SpatialObject *obj, const void *data, size_t size) {
     if (!obj) return GEOM_ERR_NULL_PTR;
     
@@ -647,3 +1141,319 @@ int spatial_object_set_properties(SpatialObject *obj, const void *data, size_t s
     return GEOM_OK;
 }
 
+bool spatial_object_contains_point(const SpatialObject *obj, const Point *p) {
+    if (!obj || !p || !mbr_contains_point(&obj->mbr, p)) return false;
+    
+    switch (obj->type) {
+        case GEOM_POLYGON:
+            return polygon_contains_point(&obj->geom.polygon, p);
+            
+        case GEOM_MULTIPOLYGON:
+            for (size_t i = 0; i < obj->geom.multi_polygon.count; i++) {
+                if (polygon_contains_point(&obj->geom.multi_polygon.polygons[i], p)) return true;
+            }
+            return false;
+            
+        default:
+            return false;
+    }
+}
+
+int spatial_object_covers_ring(const SpatialObject *obj, const Point *ring, size_t count) {
+    if (!obj || !ring) return 0;
+    
+    switch (obj->type) {
+        case GEOM_POLYGON:
+            return polygon_covers_ring(&obj->geom.polygon, ring, count);
+            
+        case GEOM_MULTIPOLYGON:
+            for (size_t i = 0; i < obj->geom.multi_polygon.count; i++) {
+                int covers = polygon_covers_ring(&obj->geom.multi_polygon.polygons[i], ring, count);
+                if (covers != 0) return covers;
+            }
+            return 0;
+            
+        default:
+            return 0;
+    }
+}
+
+/**
+ * @brief Callback for each coordinate array of an object; false stops the walk
+ */
+typedef bool (*PointArrayFn)(Point *points, size_t count, void *ctx);
+
+static bool polygon_visit_points(Polygon *poly, PointArrayFn fn, void *ctx) {
+    if (!fn(poly->exterior, poly->ext_count, ctx)) return false;
+    for (size_t h = 0; h < poly->num_holes; h++) {
+        if (!fn(poly->holes[h], poly->hole_counts[h], ctx)) return false;
+    }
+    return true;
+}
+
+/**
+ * @brief Visit every coordinate array of an object, including collection members
+ */
+static bool visit_points(SpatialObject *obj, PointArrayFn fn, void *ctx) {
+    switch (obj->type) {
+        case GEOM_POINT:
+            return fn(&obj->geom.point, 1, ctx);
+            
+        case GEOM_LINESTRING:
+            return fn(obj->geom.line.points, obj->geom.line.count, ctx);
+            
+        case GEOM_POLYGON:
+            return polygon_visit_points(&obj->geom.polygon, fn, ctx);
+            
+        case GEOM_MULTIPOINT:
+            return fn(obj->geom.multi_point.points, obj->geom.multi_point.count, ctx);
+            
+        case GEOM_MULTILINESTRING:
+            for (size_t i = 0; i < obj->geom.multi_line.count; i++) {
+                LineString *ls = &obj->geom.multi_line.lines[i];
+                if (!fn(ls->points, ls->count, ctx)) return false;
+            }
+            return true;
+            
+        case GEOM_MULTIPOLYGON:
+            for (size_t i = 0; i < obj->geom.multi_polygon.count; i++) {
+                if (!polygon_visit_points(&obj->geom.multi_polygon.polygons[i], fn, ctx)) return false;
+            }
+            return true;
+            
+        case GEOM_GEOMETRYCOLLECTION:
+            for (size_t i = 0; i < obj->geom.collection.count; i++) {
+                if (!visit_points(&obj->geom.collection.geometries[i], fn, ctx)) return false;
+            }
+            return true;
+    }
+    
+    return false;
+}
+
+static bool points_are_finite(Point *points, size_t count, void *ctx) {
+    (void)ctx;
+    for (size_t i = 0; i < count; i++) {
+        if (!isfinite(points[i].x) || !isfinite(points[i].y)) return false;
+    }
+    return true;
+}
+
+bool spatial_object_is_finite(const SpatialObject *obj) {
+    if (!obj) return false;
+    /* The visitor takes mutable arrays, but points_are_finite only reads */
+    return visit_points((SpatialObject *)obj, points_are_finite, NULL);
+}
+
+static bool snap_points(Point *points, size_t count, void *ctx) {
+    double grid = *(const double *)ctx;
+    for (size_t i = 0; i < count; i++) {
+        points[i].x = round(points[i].x / grid) * grid;
+        points[i].y = round(points[i].y / grid) * grid;
+    }
+    return true;
+}
+
+void spatial_object_snap(SpatialObject *obj, double grid) {
+    if (!obj || !(grid > 0)) return;
+    visit_points(obj, snap_points, &grid);
+}
+
+size_t geom_dims_width(uint8_t dims) {
+    return ((dims & GEOM_DIM_Z) ? 1 : 0) + ((dims & GEOM_DIM_M) ? 1 : 0);
+}
+
+static bool count_points(Point *points, size_t count, void *ctx) {
+    (void)points;
+    *(size_t *)ctx += count;
+    return true;
+}
+
+size_t spatial_object_vertex_count(const SpatialObject *obj) {
+    if (!obj || obj->type == GEOM_GEOMETRYCOLLECTION) return 0;
+    
+    size_t count = 0;
+    /* The visitor takes mutable arrays, but count_points only reads */
+    visit_points((SpatialObject *)obj, count_points, &count);
+    return count;
+}
+
+int spatial_object_set_zm(SpatialObject *obj, uint8_t dims, const double *zm) {
+    if (!obj) return GEOM_ERR_NULL_PTR;
+    
+    free(obj->zm);
+    obj->zm = NULL;
+    obj->dims = 0;
+    
+    size_t n = geom_dims_width(dims) * spatial_object_vertex_count(obj);
+    if (n == 0) return GEOM_OK;
+    if (!zm) return GEOM_ERR_NULL_PTR;
+    
+    obj->zm = (double *)malloc(n * sizeof(double));
+    if (!obj->zm) return GEOM_ERR_ALLOC;
+    memcpy(obj->zm, zm, n * sizeof(double));
+    obj->dims = dims & (GEOM_DIM_Z | GEOM_DIM_M);
+    
+    return GEOM_OK;
+}
+
+
+/* Distance from p to the segment a-b (to a itself if the segment is degenerate) */
+static double segment_distance(const Point *p, const Point *a, const Point *b) {
+    double dx = b->x - a->x;
+    double dy = b->y - a->y;
+    double len2 = dx * dx + dy * dy;
+    double t = 0.0;
+    if (len2 > 0) {
+        t = ((p->x - a->x) * dx + (p->y - a->y) * dy) / len2;
+        if (t < 0) t = 0;
+        if (t > 1) t = 1;
+    }
+    double ex = p->x - (a->x + t * dx);
+    double ey = p->y - (a->y + t * dy);
+    return sqrt(ex * ex + ey * ey);
+}
+
+/**
+ * Compacts an object's Z/M values along with its vertices. Runs are visited
+ * in coordinate order; read and write are the vertex positions of the next
+ * run before and after compaction.
+ */
+typedef struct {
+    double *zm;       /* NULL when the object is 2D */
+    size_t width;
+    size_t read;
+    size_t write;
+} ZmCompactor;
+
+/* Move the Z/M values of a run's kept vertices (all of them if keep is NULL) */
+static void zm_keep(ZmCompactor *zc, const bool *keep, size_t count) {
+    if (zc->zm) {
+        for (size_t i = 0; i < count; i++) {
+            if (keep && !keep[i]) continue;
+            if (zc->write != zc->read + i) {
+                memmove(&zc->zm[zc->write * zc->width], &zc->zm[(zc->read + i) * zc->width],
+                        zc->width * sizeof(double));
+            }
+            zc->write++;
+        }
+    } else {
+        zc->write += count;
+    }
+    zc->read += count;
+}
+
+/**
+ * Douglas-Peucker over points[0..count), compacting the kept points to the
+ * front of the array. Returns the new count, or count unchanged if the
+ * result would have fewer than min_count points or scratch space is short.
+ */
+static size_t simplify_run(Point *points, size_t count, double tolerance, size_t min_count,
+                           ZmCompactor *zc) {
+    if (count <= 2 || count <= min_count) {
+        zm_keep(zc, NULL, count);
+        return count;
+    }
+    
+    bool *keep = (bool *)calloc(count, sizeof(bool));
+    size_t *stack = (size_t *)malloc(2 * count * sizeof(size_t));
+    if (!keep || !stack) {
+        free(keep);
+        free(stack);
+        zm_keep(zc, NULL, count);
+        return count;
+    }
+    
+    /* An explicit stack of [first, last] spans avoids deep recursion */
+    keep[0] = keep[count - 1] = true;
+    size_t top = 0;
+    stack[top++] = 0;
+    stack[top++] = count - 1;
+    while (top > 0) {
+        size_t last = stack[--top];
+        size_t first = stack[--top];
+        double max_dist = 0;
+        size_t index = first;
+        for (size_t i = first + 1; i < last; i++) {
+            double d = segment_distance(&points[i], &points[first], &points[last]);
+            if (d > max_dist) {
+                max_dist = d;
+                index = i;
+            }
+        }
+        if (max_dist > tolerance) {
+            keep[index] = true;
+            stack[top++] = first;
+            stack[top++] = index;
+            stack[top++] = index;
+            stack[top++] = last;
+        }
+    }
+    
+    size_t kept = 0;
+    for (size_t i = 0; i < count; i++) {
+        if (keep[i]) kept++;
+    }
+    if (kept >= min_count) {
+        size_t n = 0;
+        for (size_t i = 0; i < count; i++) {
+            if (keep[i]) points[n++] = points[i];
+        }
+        zm_keep(zc, keep, count);
+        count = n;
+    } else {
+        zm_keep(zc, NULL, count);
+    }
+    
+    free(keep);
+    free(stack);
+    return count;
+}
+
+static void polygon_simplify(Polygon *poly, double tolerance, ZmCompactor *zc) {
+    /* Closed rings need four points; a ring that would collapse is kept whole */
+    poly->ext_count = simplify_run(poly->exterior, poly->ext_count, tolerance, 4, zc);
+    for (size_t i = 0; i < poly->num_holes; i++) {
+        poly->hole_counts[i] = simplify_run(poly->holes[i], poly->hole_counts[i], tolerance, 4, zc);
+    }
+}
+
+void spatial_object_simplify(SpatialObject *obj, double tolerance) {
+    if (!obj || !(tolerance > 0)) return;
+    
+    ZmCompactor zc = { obj->zm, geom_dims_width(obj->dims), 0, 0 };
+    
+    switch (obj->type) {
+        case GEOM_POINT:
+        case GEOM_MULTIPOINT:
+            break;
+            
+        case GEOM_LINESTRING:
+            obj->geom.line.count = simplify_run(obj->geom.line.points,
+                                                obj->geom.line.count, tolerance, 2, &zc);
+            break;
+            
+        case GEOM_POLYGON:
+            polygon_simplify(&obj->geom.polygon, tolerance, &zc);
+            break;
+            
+        case GEOM_MULTILINESTRING:
+            for (size_t i = 0; i < obj->geom.multi_line.count; i++) {
+                LineString *ls = &obj->geom.multi_line.lines[i];
+                ls->count = simplify_run(ls->points, ls->count, tolerance, 2, &zc);
+            }
+            break;
+            
+        case GEOM_MULTIPOLYGON:
+            for (size_t i = 0; i < obj->geom.multi_polygon.count; i++) {
+                polygon_simplify(&obj->geom.multi_polygon.polygons[i], tolerance, &zc);
+            }
+            break;
+            
+        case GEOM_GEOMETRYCOLLECTION:
+            for (size_t i = 0; i < obj->geom.collection.count; i++) {
+                spatial_object_simplify(&obj->geom.collection.geometries[i], tolerance);
+            }
+            break;
+    }
+}
@@ -4,6 +4,7 @@
  */
 
 #include "kdtree.h"
+#include <pthread.h>
 #include <stdlib.h>
 #include <string.h>
 #include <math.h>
@@ -15,49 +16,81 @@
 
 #define GROWTH_FACTOR 2
 
+/** Smallest subtree worth building on a thread of its own */
+#define KD_PARALLEL_MIN_POINTS 4096
+
+/**
+ * @brief A point being bulk loaded, with the key that orders it among
+ *        points sharing its coordinate
+ */
+typedef struct {
+    KDPointData pd;
+    uint64_t tie;             /**< Seeded hash of the object ID */
+} KDSortItem;
+
+/**
+ * @brief Mix an object ID with the build seed (splitmix64 finalizer)
+ */
+static uint64_t tie_key(uint64_t object_id, uint64_t seed) {
+    uint64_t z = object_id ^ (seed + 0x9E3779B97F4A7C15ULL);
+    z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9ULL;
+    z = (z ^ (z >> 27)) * 0x94D049BB133111EBULL;
+    return z ^ (z >> 31);
+}
+
+/**
+ * @brief Order points sharing a coordinate by tie key, then ID, so every
+ *        sort gives the same order whatever the qsort implementation
+ */
+static int compare_ties(const KDSortItem *a, const KDSortItem *b) {
+    if (a->tie != b->tie) return a->tie < b->tie ? -1 : 1;
+    if (a->pd.object_id != b->pd.object_id) return a->pd.object_id < b->pd.object_id ? -1 : 1;
+    return 0;
+}
+
 /**
  * @brief Compare function for sorting points by X coordinate
  */
 static int compare_by_x(const void *a, const void *b) {
-    const KDPointData *pa = (const KDPointData *)a;
-    const KDPointData *pb = (const KDPointData *)b;
-    if (pa->point.x < pb->point.x) return -1;
-    if (pa->point.x > pb->point.x) return 1;
-    return 0;
+    const KDSortItem *pa = (const KDSortItem *)a;
+    const KDSortItem *pb = (const KDSortItem *)b;
+    if (pa->pd.point.x < pb->pd.point.x) return -1;
+    if (pa->pd.point.x > pb->pd.point.x) return 1;
+    return compare_ties(pa, pb);
 }
 
 /**
  * @brief Compare function for sorting points by Y coordinate
  */
 static int compare_by_y(const void *a, const void *b) {
-    const KDPointData *pa = (const KDPointData *)a;
-    const KDPointData *pb = (const KDPointData *)b;
-    if (pa->point.y < pb->point.y) return -1;
-    if (pa->point.y > pb->point.y) return 1;
-    return 0;
+    const KDSortItem *pa = (const KDSortItem *)a;
+    const KDSortItem *pb = (const KDSortItem *)b;
+    if (pa->pd.point.y < pb->pd.point.y) return -1;
+    if (pa->pd.point.y > pb->pd.point.y) return 1;
+    return compare_ties(pa, pb);
 }
 
 /**
  * @brief Recursively build a balanced KD-tree
  */
-static KDNode* build_tree_recursive(KDPointData *points, size_t count, int depth) {
+static KDNode* build_tree_recursive(KDSortItem *points, size_t count, int depth) {
     if (count == 0) return NULL;
     
     int dim = depth % 2;
     
     /* Sort by current dimension */
     if (dim == 0) {
-        qsort(points, count, sizeof(KDPointData), compare_by_x);
+        qsort(points, count, sizeof(KDSortItem), compare_by_x);
     } else {
-        qsort(points, count, sizeof(KDPointData), compare_by_y);
+        qsort(points, count, sizeof(KDSortItem), compare_by_y);
     }
     
     /* Find median */
     size_t median = count / 2;
     
     /* Create node */
-    KDNode *node = kdnode_create(points[median].point, points[median].object_id,
-                                  points[median].data, dim);
+    KDNode *node = kdnode_create(points[median].pd.point, points[median].pd.object_id,
+                                  points[median].pd.data, dim);
     if (!node) return NULL;
     
     /* Build subtrees */
@@ -70,6 +103,67 @@ static KDNode* build_tree_recursive(KDPointData *points, size_t count, int depth
     return node;
 }
 
+/**
+ * @brief A subtree for build_task_run() to build
+ */
+typedef struct {
+    KDSortItem *points;
+    size_t count;
+    int depth;
+    size_t threads;           /**< Threads the subtree may use, the caller's included */
+    size_t used;              /**< Output: threads that built part of it */
+    KDNode *root;             /**< Output: the subtree */
+} BuildTask;
+
+static void* build_task_thread(void *arg);
+
+/**
+ * @brief Build a subtree like build_tree_recursive(), handing one half of
+ *        each split to a new thread while threads remain
+ */
+static void build_task_run(BuildTask *task) {
+    if (task->threads <= 1 || task->count < KD_PARALLEL_MIN_POINTS) {
+        task->root = build_tree_recursive(task->points, task->count, task->depth);
+        task->used = 1;
+        return;
+    }
+    
+    int dim = task->depth % 2;
+    qsort(task->points, task->count, sizeof(KDSortItem), dim == 0 ? compare_by_x : compare_by_y);
+    
+    size_t median = task->count / 2;
+    KDNode *node = kdnode_create(task->points[median].pd.point, task->points[median].pd.object_id,
+                                  task->points[median].pd.data, dim);
+    task->root = node;
+    task->used = 1;
+    if (!node) return;
+    
+    BuildTask left = {task->points, median, task->depth + 1, task->threads / 2, 0, NULL};
+    BuildTask right = {task->points + median + 1, task->count - median - 1, task->depth + 1,
+                       task->threads - task->threads / 2, 0, NULL};
+    
+    /* The halves are disjoint slices, so they can be sorted concurrently */
+    pthread_t thread;
+    bool spawned = pthread_create(&thread, NULL, build_task_thread, &left) == 0;
+    build_task_run(&right);
+    if (spawned) {
+        pthread_join(thread, NULL);
+        task->used = left.used + right.used;
+    } else {
+        build_task_run(&left);
+        task->used = left.used > right.used ? left.used : right.used;
+    }
+    
+    node->left = left.root;
+    node->right = right.root;
+    kdnode_update_bounds(node);
+}
+
+static void* build_task_thread(void *arg) {
+    build_task_run((BuildTask *)arg);
+    return NULL;
+}
+
 /**
  * @brief Insert a node into the tree recursively
  */
@@ -229,6 +323,12 @@ int kdtree_insert(KDTree *tree, Point p, uint64_t object_id, void *data) {
 }
 
 int kdtree_bulk_load(KDTree *tree, KDPointData *points, size_t count) {
+    return kdtree_bulk_load_parallel(tree, points, count, 1, 0, NULL);
+}
+
+int kdtree_bulk_load_parallel(KDTree *tree, KDPointData *points, size_t count,
+                              size_t threads, uint64_t seed, size_t *threads_used) {
+    if (threads_used) *threads_used = 0;
     if (!tree) return KD_ERR_NULL_PTR;
     if (count == 0) return KD_OK;
     if (!points) return KD_ERR_NULL_PTR;
@@ -239,14 +339,20 @@ int kdtree_bulk_load(KDTree *tree, KDPointData *points, size_t count) {
     tree->size = 0;
     tree->bounds = mbr_empty();
     
-    /* Make a copy of points array (will be modified during sort) */
-    KDPointData *points_copy = (KDPointData *)malloc(count * sizeof(KDPointData));
+    /* Copy the points with their tie keys (the copy is reordered by sorting) */
+    KDSortItem *points_copy = (KDSortItem *)malloc(count * sizeof(KDSortItem));
     if (!points_copy) return KD_ERR_ALLOC;
-    memcpy(points_copy, points, count * sizeof(KDPointData));
+    for (size_t i = 0; i < count; i++) {
+        points_copy[i].pd = points[i];
+        points_copy[i].tie = tie_key(points[i].object_id, seed);
+    }
     
     /* Build balanced tree */
-    tree->root = build_tree_recursive(points_copy, count, 0);
+    BuildTask task = {points_copy, count, 0, threads > 0 ? threads : 1, 0, NULL};
+    build_task_run(&task);
+    tree->root = task.root;
     free(points_copy);
+    if (threads_used) *threads_used = task.used;
     
     if (!tree->root && count > 0) return KD_ERR_ALLOC;
     
@@ -434,6 +540,11 @@ size_t kdtree_depth(const KDTree *tree) {
     return depth_recursive(tree->root);
 }
 
+size_t kdtree_memory(const KDTree *tree) {
+    if (!tree) return 0;
+    return tree->size * sizeof(KDNode);
+}
+
 bool kdtree_is_balanced(const KDTree *tree) {
     if (!tree || !tree->root) return true;
     
@@ -184,6 +184,18 @@ double page_utilization(const Page *page) {
     return (double)page->header.object_count / page->object_capacity;
 }
 
+size_t page_memory(const Page *page) {
+    if (!page) return 0;
+    
+    size_t bytes = sizeof(Page) + page->object_capacity * sizeof(SpatialObject);
+    
+    for (size_t i = 0; i < page->header.object_count; i++) {
+        bytes += spatial_object_memory(&page->objects[i]);
+    }
+    
+    return bytes;
+}
+
 int page_serialize(const Page *page, uint8_t *buffer, size_t buffer_size) {
     if (!page || !buffer) return PAGE_ERR_NULL_PTR;
     if (buffer_size < PAGE_SIZE) return PAGE_ERR_ALLOC;
@@ -231,7 +243,7 @@ int page_deserialize(Page *page, const uint8_t *buffer, size_t buffer_size) {
     memcpy(&page->header, buffer, sizeof(PageHeader));
     
     /* Verify basic sanity */
-    if (page->header.object_count > MAX_OBJECTS_PER_PAGE) {
+    if (page->header.object_count > page->object_capacity) {
         return PAGE_ERR_CORRUPT;
     }
     
@@ -256,6 +268,11 @@ int page_deserialize(Page *page, const uint8_t *buffer, size_t buffer_size) {
         /* MBR */
         memcpy(&obj->mbr, buffer + offset, sizeof(MBR));
         offset += sizeof(MBR);
+        
+        /* A point is its own centroid */
+        if (obj->type == GEOM_POINT) {
+            obj->geom.point = obj->centroid;
+        }
     }
     
     page->in_memory = true;
@@ -457,6 +474,22 @@ void page_pool_free(PagePool *pool) {
     memset(pool, 0, sizeof(PagePool));
 }
 
+size_t page_pool_memory(const PagePool *pool) {
+    if (!pool) return 0;
+    
+    size_t bytes = pool->page_capacity * sizeof(Page *) +
+                   pool->track_capacity * sizeof(DiskTrack *);
+    
+    for (size_t i = 0; i < pool->page_count; i++) {
+        bytes += page_memory(pool->pages[i]);
+    }
+    for (size_t i = 0; i < pool->track_count; i++) {
+        bytes += sizeof(DiskTrack) + pool->tracks[i]->page_capacity * sizeof(Page *);
+    }
+    
+    return bytes;
+}
+
 Page* page_pool_alloc(PagePool *pool, uint32_t track_id) {
     if (!pool) return NULL;
     
@@ -642,6 +675,11 @@ void page_cache_free(PageCache *cache) {
     memset(cache, 0, sizeof(PageCache));
 }
 
+size_t page_cache_memory(const PageCache *cache) {
+    if (!cache) return 0;
+    return cache->count * sizeof(PageRef) + cache->hash_size * sizeof(PageRef *);
+}
+
 Page* page_cache_get(PageCache *cache, uint32_t page_id) {
     if (!cache) return NULL;
     
@@ -670,7 +708,7 @@ Page* page_cache_get(PageCache *cache, uint32_t page_id) {
             
             return page_pool_get(cache->pool, page_id);
         }
-        ref = ref->next;
+        ref = ref->hash_next;
     }
     
     /* Not in cache - get from pool and add to cache */
@@ -691,7 +729,7 @@ Page* page_cache_get(PageCache *cache, uint32_t page_id) {
     ref->last_access = get_timestamp();
     
     /* Add to hash table */
-    ref->next = cache->hash_table[idx];
+    ref->hash_next = cache->hash_table[idx];
     cache->hash_table[idx] = ref;
     
     /* Add to LRU list head */
@@ -706,6 +744,17 @@ Page* page_cache_get(PageCache *cache, uint32_t page_id) {
     return page;
 }
 
+bool page_cache_contains(const PageCache *cache, uint32_t page_id) {
+    if (!cache || !cache->hash_table) return false;
+    
+    PageRef *ref = cache->hash_table[page_hash(page_id, cache->hash_size)];
+    while (ref) {
+        if (ref->page_id == page_id) return true;
+        ref = ref->hash_next;
+    }
+    return false;
+}
+
 int page_cache_pin(PageCache *cache, uint32_t page_id) {
     if (!cache) return PAGE_ERR_NULL_PTR;
     
@@ -755,15 +804,15 @@ int page_cache_evict(PageCache *cache, size_t count) {
     
     size_t evicted = 0;
     
-    while (evicted < count && cache->tail) {
-        PageRef *victim = cache->tail;
-        
+    PageRef *victim = cache->tail;
+    while (evicted < count && victim) {
         /* Skip pinned pages */
         Page *page = page_pool_get(cache->pool, victim->page_id);
         if (page && (page->header.flags & PAGE_STATUS_PINNED)) {
             victim = victim->prev;
             continue;
         }
+        PageRef *prev = victim->prev;
         
         /* Remove from LRU list */
         if (victim->prev) victim->prev->next = victim->next;
@@ -776,15 +825,16 @@ int page_cache_evict(PageCache *cache, size_t count) {
         PageRef **pp = &cache->hash_table[idx];
         while (*pp) {
             if (*pp == victim) {
-                *pp = victim->next;
+                *pp = victim->hash_next;
                 break;
             }
-            pp = &(*pp)->next;
+            pp = &(*pp)->hash_next;
         }
         
         free(victim);
         cache->count--;
         evicted++;
+        victim = prev;
     }
     
     return PAGE_OK;
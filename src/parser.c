@@ -11,6 +11,8 @@
 #include <ctype.h>
 #include <math.h>
 #include <errno.h>
+#include <limits.h>
+#include <stdarg.h>
 
 /* ============================================================================
  * Internal Helpers
@@ -102,14 +104,16 @@ static int parse_string(ParserState *state, char **out) {
         len++;
     }
     
+    size_t end = state->pos;
+    
     *out = (char *)malloc(len + 1);
     if (!*out) return PARSE_ERR_ALLOC;
     
     /* Copy string, handling escapes */
     size_t j = 0;
-    for (size_t i = start; i < start + len && j < len; i++) {
+    for (size_t i = start; i < end && j < len; i++) {
         char c = state->input[i];
-        if (c == '\\' && i + 1 < start + len) {
+        if (c == '\\' && i + 1 < end) {
             i++;
             c = state->input[i];
             switch (c) {
@@ -179,12 +183,18 @@ static int parse_array(ParserState *state, JsonValue *value) {
             capacity *= 2;
             JsonValue *new_items = (JsonValue *)realloc(value->data.array.items,
                                                          capacity * sizeof(JsonValue));
-            if (!new_items) return PARSE_ERR_ALLOC;
+            if (!new_items) {
+                json_value_free(value);
+                return PARSE_ERR_ALLOC;
+            }
             value->data.array.items = new_items;
         }
         
         int err = parse_value(state, &value->data.array.items[value->data.array.count]);
-        if (err != PARSE_OK) return err;
+        if (err != PARSE_OK) {
+            json_value_free(value);
+            return err;
+        }
         value->data.array.count++;
         
         skip_whitespace(state);
@@ -192,7 +202,10 @@ static int parse_array(ParserState *state, JsonValue *value) {
             consume(state);
             break;
         }
-        if (expect(state, ',') != PARSE_OK) return PARSE_ERR_SYNTAX;
+        if (expect(state, ',') != PARSE_OK) {
+            json_value_free(value);
+            return PARSE_ERR_SYNTAX;
+        }
     }
     
     return PARSE_OK;
@@ -213,6 +226,7 @@ static int parse_object(ParserState *state, JsonValue *value) {
     value->data.object.keys = (char **)malloc(capacity * sizeof(char *));
     value->data.object.values = (JsonValue *)malloc(capacity * sizeof(JsonValue));
     if (!value->data.object.keys || !value->data.object.values) {
+        json_value_free(value);
         return PARSE_ERR_ALLOC;
     }
     
@@ -227,23 +241,34 @@ static int parse_object(ParserState *state, JsonValue *value) {
             capacity *= 2;
             char **new_keys = (char **)realloc(value->data.object.keys,
                                                 capacity * sizeof(char *));
+            if (new_keys) value->data.object.keys = new_keys;
             JsonValue *new_vals = (JsonValue *)realloc(value->data.object.values,
                                                         capacity * sizeof(JsonValue));
-            if (!new_keys || !new_vals) return PARSE_ERR_ALLOC;
-            value->data.object.keys = new_keys;
-            value->data.object.values = new_vals;
+            if (new_vals) value->data.object.values = new_vals;
+            if (!new_keys || !new_vals) {
+                json_value_free(value);
+                return PARSE_ERR_ALLOC;
+            }
         }
         
         /* Parse key */
         int err = parse_string(state, &value->data.object.keys[value->data.object.count]);
-        if (err != PARSE_OK) return err;
-        
-        /* Expect colon */
-        if (expect(state, ':') != PARSE_OK) return PARSE_ERR_SYNTAX;
+        if (err != PARSE_OK) {
+            json_value_free(value);
+            return err;
+        }
         
-        /* Parse value */
-        err = parse_value(state, &value->data.object.values[value->data.object.count]);
-        if (err != PARSE_OK) return err;
+        /* Expect colon, then the value; a failure frees the pending key too */
+        if (expect(state, ':') != PARSE_OK) {
+            err = PARSE_ERR_SYNTAX;
+        } else {
+            err = parse_value(state, &value->data.object.values[value->data.object.count]);
+        }
+        if (err != PARSE_OK) {
+            free(value->data.object.keys[value->data.object.count]);
+            json_value_free(value);
+            return err;
+        }
         
         value->data.object.count++;
         
@@ -252,7 +277,10 @@ static int parse_object(ParserState *state, JsonValue *value) {
             consume(state);
             break;
         }
-        if (expect(state, ',') != PARSE_OK) return PARSE_ERR_SYNTAX;
+        if (expect(state, ',') != PARSE_OK) {
+            json_value_free(value);
+            return PARSE_ERR_SYNTAX;
+        }
     }
     
     return PARSE_OK;
@@ -316,6 +344,120 @@ static int parse_value(ParserState *state, JsonValue *value) {
     return PARSE_ERR_SYNTAX;
 }
 
+/* ============================================================================
+ * Z and M Ordinates
+ * ============================================================================ */
+
+/**
+ * @brief Z and M values gathered per vertex while parsing (NaN where absent)
+ */
+typedef struct {
+    double *zm;        /**< Z, M pair per vertex */
+    size_t count;      /**< Vertices gathered */
+    size_t capacity;
+} ZmBuffer;
+
+/**
+ * @brief Reads an object's Z/M values in coordinate order while exporting
+ */
+typedef struct {
+    const double *zm;  /**< Values of the next vertex; NULL when 2D */
+    uint8_t dims;
+} ZmCursor;
+
+static int zm_push(ZmBuffer *buf, double z, double m) {
+    if (buf->count == buf->capacity) {
+        size_t capacity = buf->capacity ? buf->capacity * GROWTH_FACTOR : 16;
+        double *zm = (double *)realloc(buf->zm, 2 * capacity * sizeof(double));
+        if (!zm) return PARSE_ERR_ALLOC;
+        buf->zm = zm;
+        buf->capacity = capacity;
+    }
+    buf->zm[2 * buf->count] = z;
+    buf->zm[2 * buf->count + 1] = m;
+    buf->count++;
+    return PARSE_OK;
+}
+
+/**
+ * @brief Dims implied by the fewest numbers in any position: x y z, x y z m
+ */
+static uint8_t zm_dims_for(size_t min_len) {
+    if (min_len >= 4) return GEOM_DIM_Z | GEOM_DIM_M;
+    return min_len == 3 ? GEOM_DIM_Z : 0;
+}
+
+/**
+ * @brief Attach the gathered values that dims selects to an object, and free
+ * the buffer
+ *
+ * Nothing is attached when the values do not line up with the object's
+ * vertices, e.g. after an unreadable hole point was skipped.
+ */
+static int zm_apply(ZmBuffer *buf, SpatialObject *obj, uint8_t dims) {
+    int err = PARSE_OK;
+    
+    if (geom_dims_width(dims) > 0 && buf->count > 0 &&
+        buf->count == spatial_object_vertex_count(obj)) {
+        /* Compact the pairs in place down to the selected ordinates */
+        size_t n = 0;
+        for (size_t i = 0; i < buf->count; i++) {
+            if (dims & GEOM_DIM_Z) buf->zm[n++] = buf->zm[2 * i];
+            if (dims & GEOM_DIM_M) buf->zm[n++] = buf->zm[2 * i + 1];
+        }
+        if (spatial_object_set_zm(obj, dims, buf->zm) != GEOM_OK) err = PARSE_ERR_ALLOC;
+    }
+    
+    free(buf->zm);
+    memset(buf, 0, sizeof(ZmBuffer));
+    return err;
+}
+
+/**
+ * @brief Take the next vertex's Z and M from a cursor (NaN where absent)
+ */
+static void zm_next(ZmCursor *zc, double *z, double *m) {
+    *z = NAN;
+    *m = NAN;
+    if (!zc->zm) return;
+    
+    if (zc->dims & GEOM_DIM_Z) *z = *zc->zm++;
+    if (zc->dims & GEOM_DIM_M) *m = *zc->zm++;
+}
+
+/**
+ * @brief Step a cursor past vertices an exporter does not write
+ */
+static void zm_skip(ZmCursor *zc, size_t count) {
+    if (zc->zm) zc->zm += count * geom_dims_width(zc->dims);
+}
+
+/**
+ * @brief Gather the Z and M of every position under a GeoJSON coordinates value
+ *
+ * Positions are [x, y], [x, y, z] or [x, y, z, m]; min_len tracks the
+ * fewest numbers in any of them.
+ */
+static int gather_zm(const JsonValue *coords, ZmBuffer *buf, size_t *min_len) {
+    if (coords->type != JSON_ARRAY) return PARSE_OK;
+    
+    const JsonValue *items = coords->data.array.items;
+    size_t n = coords->data.array.count;
+    
+    if (n > 0 && items[0].type == JSON_NUMBER) {
+        if (n < *min_len) *min_len = n;
+        double z = (n > 2 && items[2].type == JSON_NUMBER) ? items[2].data.number : NAN;
+        double m = (n > 3 && items[3].type == JSON_NUMBER) ? items[3].data.number : NAN;
+        return zm_push(buf, z, m);
+    }
+    
+    for (size_t i = 0; i < n; i++) {
+        int err = gather_zm(&items[i], buf, min_len);
+        if (err != PARSE_OK) return err;
+    }
+    return PARSE_OK;
+}
+
 /**
  * @brief Parse GeoJSON coordinates into a Point
  */
@@ -336,18 +478,174 @@ static int parse_coordinates_point(const JsonValue *coords, Point *p) {
 }
 
 /**
- * @brief Parse GeoJSON geometry
+ * @brief Parse a GeoJSON position array into a LineString
  */
-static int parse_geojson_geometry(const JsonValue *geom, SpatialObject *obj) {
+static int parse_coordinates_line(const JsonValue *coords, LineString *ls) {
+    if (coords->type != JSON_ARRAY) return PARSE_ERR_INVALID_GEOM;
+    
+    for (size_t i = 0; i < coords->data.array.count; i++) {
+        Point p;
+        int err = parse_coordinates_point(&coords->data.array.items[i], &p);
+        if (err != PARSE_OK) return err;
+        if (linestring_add_point(ls, p) != GEOM_OK) return PARSE_ERR_ALLOC;
+    }
+    
+    return PARSE_OK;
+}
+
+/**
+ * @brief Parse GeoJSON polygon rings into an initialized Polygon
+ */
+static int parse_coordinates_polygon(const JsonValue *coords, Polygon *poly) {
+    if (coords->type != JSON_ARRAY || coords->data.array.count < 1) {
+        return PARSE_ERR_INVALID_GEOM;
+    }
+    
+    JsonValue *ext_ring = &coords->data.array.items[0];
+    if (ext_ring->type != JSON_ARRAY) return PARSE_ERR_INVALID_GEOM;
+    
+    for (size_t i = 0; i < ext_ring->data.array.count; i++) {
+        Point p;
+        int err = parse_coordinates_point(&ext_ring->data.array.items[i], &p);
+        if (err != PARSE_OK) return err;
+        if (polygon_add_exterior_point(poly, p) != GEOM_OK) return PARSE_ERR_ALLOC;
+    }
+    
+    for (size_t h = 1; h < coords->data.array.count; h++) {
+        JsonValue *hole_ring = &coords->data.array.items[h];
+        if (hole_ring->type != JSON_ARRAY) continue;
+        
+        if (polygon_add_hole(poly, hole_ring->data.array.count) != GEOM_OK) continue;
+        
+        for (size_t i = 0; i < hole_ring->data.array.count; i++) {
+            Point p;
+            if (parse_coordinates_point(&hole_ring->data.array.items[i], &p) == PARSE_OK) {
+                polygon_add_hole_point(poly, poly->num_holes - 1, p);
+            }
+        }
+    }
+    
+    return PARSE_OK;
+}
+
+/**
+ * @brief Fill an initialized multi-geometry from GeoJSON Multi* coordinates
+ */
+static int parse_coordinates_multi(const JsonValue *coords, SpatialObject *obj) {
+    for (size_t i = 0; i < coords->data.array.count; i++) {
+        const JsonValue *part = &coords->data.array.items[i];
+        int err = PARSE_OK;
+        int idx;
+        Point p;
+        
+        switch (obj->type) {
+            case GEOM_MULTIPOINT:
+                err = parse_coordinates_point(part, &p);
+                if (err == PARSE_OK && multipoint_add_point(&obj->geom.multi_point, p) != GEOM_OK) {
+                    err = PARSE_ERR_ALLOC;
+                }
+                break;
+                
+            case GEOM_MULTILINESTRING:
+                if (part->type != JSON_ARRAY) return PARSE_ERR_INVALID_GEOM;
+                idx = multilinestring_add_line(&obj->geom.multi_line, part->data.array.count);
+                if (idx < 0) return PARSE_ERR_ALLOC;
+                err = parse_coordinates_line(part, &obj->geom.multi_line.lines[idx]);
+                break;
+                
+            case GEOM_MULTIPOLYGON:
+                if (part->type != JSON_ARRAY || part->data.array.count < 1 ||
+                    part->data.array.items[0].type != JSON_ARRAY) {
+                    return PARSE_ERR_INVALID_GEOM;
+                }
+                idx = multipolygon_add_polygon(&obj->geom.multi_polygon,
+                                               part->data.array.items[0].data.array.count);
+                if (idx < 0) return PARSE_ERR_ALLOC;
+                err = parse_coordinates_polygon(part, &obj->geom.multi_polygon.polygons[idx]);
+                break;
+                
+            default:
+                return PARSE_ERR_UNSUPPORTED;
+        }
+        
+        if (err != PARSE_OK) return err;
+    }
+    
+    return PARSE_OK;
+}
+
+static int parse_geojson_geometry(const JsonValue *geom, SpatialObject *obj);
+
+/**
+ * @brief Parse the x and y of a GeoJSON geometry
+ */
+static int parse_geojson_geometry_xy(const JsonValue *geom, SpatialObject *obj) {
     JsonValue *type = json_object_get(geom, "type");
-    JsonValue *coords = json_object_get(geom, "coordinates");
     
-    if (!type || type->type != JSON_STRING || !coords) {
+    if (!type || type->type != JSON_STRING) {
         return PARSE_ERR_INVALID_GEOM;
     }
     
     const char *type_str = type->data.string;
     
+    if (strcmp(type_str, "GeometryCollection") == 0) {
+        JsonValue *geoms = json_object_get(geom, "geometries");
+        if (!geoms || geoms->type != JSON_ARRAY) return PARSE_ERR_INVALID_GEOM;
+        
+        int err = spatial_object_init_collection(obj, 0, geoms->data.array.count);
+        if (err != GEOM_OK) return PARSE_ERR_ALLOC;
+        
+        for (size_t i = 0; i < geoms->data.array.count; i++) {
+            SpatialObject member;
+            err = parse_geojson_geometry(&geoms->data.array.items[i], &member);
+            if (err != PARSE_OK) {
+                spatial_object_free(obj);
+                return err;
+            }
+            
+            err = geometry_collection_add(&obj->geom.collection, &member);
+            spatial_object_free(&member);
+            if (err != GEOM_OK) {
+                spatial_object_free(obj);
+                return PARSE_ERR_ALLOC;
+            }
+        }
+        
+        if (spatial_object_update_derived(obj) != GEOM_OK) {
+            spatial_object_free(obj);
+            return PARSE_ERR_INVALID_GEOM;
+        }
+        return PARSE_OK;
+    }
+    
+    JsonValue *coords = json_object_get(geom, "coordinates");
+    if (!coords) return PARSE_ERR_INVALID_GEOM;
+    
+    if (strncmp(type_str, "Multi", 5) == 0) {
+        if (coords->type != JSON_ARRAY) return PARSE_ERR_INVALID_GEOM;
+        
+        size_t count = coords->data.array.count;
+        int err;
+        
+        if (strcmp(type_str, "MultiPoint") == 0) {
+            err = spatial_object_init_multipoint(obj, 0, count);
+        } else if (strcmp(type_str, "MultiLineString") == 0) {
+            err = spatial_object_init_multilinestring(obj, 0, count);
+        } else if (strcmp(type_str, "MultiPolygon") == 0) {
+            err = spatial_object_init_multipolygon(obj, 0, count);
+        } else {
+            return PARSE_ERR_UNSUPPORTED;
+        }
+        if (err != GEOM_OK) return PARSE_ERR_ALLOC;
+        
+        err = parse_coordinates_multi(coords, obj);
+        if (err == PARSE_OK && spatial_object_update_derived(obj) != GEOM_OK) {
+            err = PARSE_ERR_INVALID_GEOM;
+        }
+        if (err != PARSE_OK) spatial_object_free(obj);
+        return err;
+    }
+    
     if (strcmp(type_str, "Point") == 0) {
         Point p;
         int err = parse_coordinates_point(coords, &p);
@@ -422,14 +720,48 @@ static int parse_geojson_geometry(const JsonValue *geom, SpatialObject *obj) {
     return PARSE_ERR_UNSUPPORTED;
 }
 
+/**
+ * @brief Parse GeoJSON geometry, keeping the Z and M of 3D and 4D positions
+ */
+static int parse_geojson_geometry(const JsonValue *geom, SpatialObject *obj) {
+    int err = parse_geojson_geometry_xy(geom, obj);
+    if (err != PARSE_OK || obj->type == GEOM_GEOMETRYCOLLECTION) return err;
+    
+    ZmBuffer buf = {0};
+    size_t min_len = SIZE_MAX;
+    err = gather_zm(json_object_get(geom, "coordinates"), &buf, &min_len);
+    if (err == PARSE_OK) {
+        err = zm_apply(&buf, obj, zm_dims_for(min_len));
+    } else {
+        free(buf.zm);
+    }
+    if (err != PARSE_OK) spatial_object_free(obj);
+    
+    return err;
+}
+
+/**
+ * @brief Check for a null geometry, or one with no coordinates or members
+ */
+static bool geojson_geometry_empty(const JsonValue *geom) {
+    if (json_is_null(geom)) return true;
+    
+    const JsonValue *items = json_object_get(geom, "coordinates");
+    if (!items) items = json_object_get(geom, "geometries");
+    return items && items->type == JSON_ARRAY && items->data.array.count == 0;
+}
+
 /**
  * @brief Parse a GeoJSON feature
+ *
+ * Returns PARSE_ERR_EMPTY_GEOM for a feature whose geometry is null or empty.
  */
 static int parse_geojson_feature(const JsonValue *feature, ParsedFeature *parsed) {
     memset(parsed, 0, sizeof(ParsedFeature));
     
     JsonValue *geometry = json_object_get(feature, "geometry");
     if (!geometry) return PARSE_ERR_INVALID_GEOM;
+    if (geojson_geometry_empty(geometry)) return PARSE_ERR_EMPTY_GEOM;
     
     int err = parse_geojson_geometry(geometry, &parsed->object);
     if (err != PARSE_OK) return err;
@@ -449,11 +781,51 @@ static int parse_geojson_feature(const JsonValue *feature, ParsedFeature *parsed
     if (props && props->type == JSON_OBJECT) {
         parsed->properties = *props;
         /* Note: shallow copy - properties share memory with parsed JSON */
+        
+        /* Keep a serialized copy on the object, which outlives the JSON */
+        if (props->data.object.count > 0) {
+            size_t len = json_write(props, NULL, 0);
+            char *text = (char *)malloc(len + 1);
+            if (!text) {
+                spatial_object_free(&parsed->object);
+                free(parsed->id_str);
+                return PARSE_ERR_ALLOC;
+            }
+            json_write(props, text, len + 1);
+            err = spatial_object_set_properties(&parsed->object, text, len);
+            free(text);
+            if (err != GEOM_OK) {
+                spatial_object_free(&parsed->object);
+                free(parsed->id_str);
+                return PARSE_ERR_ALLOC;
+            }
+        }
     }
     
     return PARSE_OK;
 }
 
+/**
+ * @brief Record the outcome of parsing one feature of a collection
+ */
+static void collect_feature(FeatureCollection *fc, size_t index, int err,
+                            ParsedFeature *parsed) {
+    if (err == PARSE_OK) {
+        feature_collection_add(fc, parsed);
+        return;
+    }
+    if (err == PARSE_ERR_EMPTY_GEOM) {
+        fc->skipped++;
+        return;
+    }
+    
+    FeatureError *errors = (FeatureError *)realloc(fc->errors,
+                                                   (fc->error_count + 1) * sizeof(FeatureError));
+    if (!errors) return;
+    fc->errors = errors;
+    fc->errors[fc->error_count++] = (FeatureError){ index, err };
+}
+
 /* ============================================================================
  * GeoJSON Parsing
  * ============================================================================ */
@@ -503,24 +875,18 @@ int geojson_parse_string(const char *json, FeatureCollection *result) {
         for (size_t i = 0; i < features->data.array.count; i++) {
             ParsedFeature parsed;
             err = parse_geojson_feature(&features->data.array.items[i], &parsed);
-            if (err == PARSE_OK) {
-                feature_collection_add(result, &parsed);
-            }
+            collect_feature(result, i, err, &parsed);
         }
     } else if (strcmp(type->data.string, "Feature") == 0) {
         ParsedFeature parsed;
         err = parse_geojson_feature(&root, &parsed);
-        if (err == PARSE_OK) {
-            feature_collection_add(result, &parsed);
-        }
+        collect_feature(result, 0, err, &parsed);
     } else {
         /* Single geometry */
         ParsedFeature parsed;
         memset(&parsed, 0, sizeof(parsed));
         err = parse_geojson_geometry(&root, &parsed.object);
-        if (err == PARSE_OK) {
-            feature_collection_add(result, &parsed);
-        }
+        collect_feature(result, 0, err, &parsed);
     }
     
     /* Update bounds */
@@ -627,46 +993,59 @@ int geojson_parse_geometry(const char *json, SpatialObject *obj) {
     return err;
 }
 
-int geojson_export(const SpatialObject *obj, char *buffer, size_t buffer_size) {
-    if (!obj || !buffer || buffer_size == 0) return PARSE_ERR_NULL_PTR;
+/**
+ * @brief Append formatted text at offset, never writing past the buffer
+ */
+static int append_format(char *buffer, size_t buffer_size, int written, const char *fmt, ...) {
+    va_list args;
+    va_start(args, fmt);
     
-    int written = 0;
+    int n;
+    if (written >= 0 && (size_t)written < buffer_size) {
+        n = vsnprintf(buffer + written, buffer_size - written, fmt, args);
+    } else {
+        n = vsnprintf(NULL, 0, fmt, args);
+    }
     
-    switch (obj->type) {
-        case GEOM_POINT:
-            written = snprintf(buffer, buffer_size,
-                "{\"type\":\"Point\",\"coordinates\":[%.6f,%.6f]}",
-                obj->geom.point.x, obj->geom.point.y);
-            break;
-            
-        case GEOM_LINESTRING: {
-            written = snprintf(buffer, buffer_size, "{\"type\":\"LineString\",\"coordinates\":[");
-            for (size_t i = 0; i < obj->geom.line.count && written < (int)buffer_size; i++) {
-                if (i > 0) written += snprintf(buffer + written, buffer_size - written, ",");
-                written += snprintf(buffer + written, buffer_size - written,
-                    "[%.6f,%.6f]",
-                    obj->geom.line.points[i].x, obj->geom.line.points[i].y);
-            }
-            written += snprintf(buffer + written, buffer_size - written, "]}");
-            break;
-        }
-            
-        case GEOM_POLYGON: {
-            written = snprintf(buffer, buffer_size, "{\"type\":\"Polygon\",\"coordinates\":[[");
-            for (size_t i = 0; i < obj->geom.polygon.ext_count && written < (int)buffer_size; i++) {
-                if (i > 0) written += snprintf(buffer + written, buffer_size - written, ",");
-                written += snprintf(buffer + written, buffer_size - written,
-                    "[%.6f,%.6f]",
-                    obj->geom.polygon.exterior[i].x, obj->geom.polygon.exterior[i].y);
-            }
-            written += snprintf(buffer + written, buffer_size - written, "]]}");
-            break;
+    va_end(args);
+    return n < 0 ? written : written + n;
+}
+
+/**
+ * @brief Append a coordinate sequence as a GeoJSON or WKT position list,
+ * with the Z and M values the cursor holds
+ */
+static int append_points(char *buffer, size_t buffer_size, int written,
+                         const Point *points, size_t count, bool wkt, ZmCursor *zc) {
+    const char *sep = wkt ? " " : ",";
+    for (size_t i = 0; i < count; i++) {
+        if (i > 0) written = append_format(buffer, buffer_size, written, wkt ? ", " : ",");
+        written = append_format(buffer, buffer_size, written, wkt ? "%.6f%s%.6f" : "[%.6f%s%.6f",
+                                points[i].x, sep, points[i].y);
+        if (zc->zm) {
+            double z, m;
+            zm_next(zc, &z, &m);
+            if (zc->dims & GEOM_DIM_Z) written = append_format(buffer, buffer_size, written, "%s%.6f", sep, z);
+            if (zc->dims & GEOM_DIM_M) written = append_format(buffer, buffer_size, written, "%s%.6f", sep, m);
         }
+        if (!wkt) written = append_format(buffer, buffer_size, written, "]");
     }
-    
     return written;
 }
 
+/* Defined with the JSON writer below, which owns the bounded-buffer helpers */
+static void geojson_put_geometry(char *buffer, size_t size, size_t *len, const SpatialObject *obj);
+
+int geojson_export(const SpatialObject *obj, char *buffer, size_t buffer_size) {
+    if (!obj) return PARSE_ERR_NULL_PTR;
+    
+    size_t len = 0;
+    geojson_put_geometry(buffer, buffer ? buffer_size : 0, &len, obj);
+    if (buffer && buffer_size > 0) buffer[len < buffer_size ? len : buffer_size - 1] = '\0';
+    
+    return len > INT_MAX ? PARSE_ERR_ALLOC : (int)len;
+}
+
 int geojson_export_collection(const FeatureCollection *fc, char *buffer, size_t buffer_size) {
     if (!fc || !buffer || buffer_size == 0) return PARSE_ERR_NULL_PTR;
     
@@ -691,36 +1070,116 @@ int geojson_export_collection(const FeatureCollection *fc, char *buffer, size_t
  * WKT Parsing
  * ============================================================================ */
 
+const char* wkt_srid(const char *wkt, int32_t *srid) {
+    if (!wkt) return NULL;
+    if (srid) *srid = 0;
+    
+    while (*wkt && isspace(*wkt)) wkt++;
+    if (strncasecmp(wkt, "SRID", 4) != 0) return wkt;
+    
+    const char *p = wkt + 4;
+    while (*p && isspace(*p)) p++;
+    if (*p++ != '=') return NULL;
+    while (*p && isspace(*p)) p++;
+    if (!isdigit((unsigned char)*p)) return NULL;
+    
+    char *end;
+    long code = strtol(p, &end, 10);
+    if (code > INT32_MAX) return NULL;
+    while (*end && isspace(*end)) end++;
+    if (*end != ';') return NULL;
+    
+    if (srid) *srid = (int32_t)code;
+    end++;
+    while (*end && isspace(*end)) end++;
+    return end;
+}
+
+/**
+ * @brief Skip an optional Z, M or ZM dimension tag after a WKT type name
+ */
+static const char* wkt_tag(const char *wkt, uint8_t *tag) {
+    while (*wkt && isspace(*wkt)) wkt++;
+    
+    *tag = 0;
+    if (strncasecmp(wkt, "ZM", 2) == 0) {
+        *tag = GEOM_DIM_Z | GEOM_DIM_M;
+        wkt += 2;
+    } else if (toupper((unsigned char)*wkt) == 'Z') {
+        *tag = GEOM_DIM_Z;
+        wkt++;
+    } else if (toupper((unsigned char)*wkt) == 'M') {
+        *tag = GEOM_DIM_M;
+        wkt++;
+    }
+    return wkt;
+}
+
+/**
+ * @brief Read one WKT coordinate: x and y, then up to two more ordinates
+ *
+ * The extra ordinates are Z then M, or M alone under an M tag. They go
+ * onto buf, and min_len tracks the fewest numbers in any coordinate.
+ */
+static int wkt_read_coord(const char *wkt, uint8_t tag, Point *p, ZmBuffer *buf, size_t *min_len) {
+    double v[4];
+    size_t n = 0;
+    while (n < 4) {
+        char *end;
+        double d = strtod(wkt, &end);
+        if (end == wkt) break;
+        v[n++] = d;
+        wkt = end;
+    }
+    if (n < 2) return PARSE_ERR_SYNTAX;
+    
+    *p = point_create(v[0], v[1]);
+    if (n < *min_len) *min_len = n;
+    
+    double z = NAN, m = NAN;
+    if (tag == GEOM_DIM_M) {
+        if (n > 2) m = v[2];
+    } else {
+        if (n > 2) z = v[2];
+        if (n > 3) m = v[3];
+    }
+    return zm_push(buf, z, m);
+}
+
 int wkt_parse(const char *wkt, SpatialObject *obj) {
     if (!wkt || !obj) return PARSE_ERR_NULL_PTR;
     
-    /* Skip whitespace */
-    while (*wkt && isspace(*wkt)) wkt++;
+    /* Skip whitespace and any EWKT SRID prefix */
+    wkt = wkt_srid(wkt, NULL);
+    if (!wkt) return PARSE_ERR_SYNTAX;
+    
+    ZmBuffer buf = {0};
+    size_t min_len = SIZE_MAX;
+    uint8_t tag;
+    int err;
+    Point p;
     
     if (strncasecmp(wkt, "POINT", 5) == 0) {
-        wkt += 5;
+        wkt = wkt_tag(wkt + 5, &tag);
         while (*wkt && (isspace(*wkt) || *wkt == '(')) wkt++;
         
-        double x, y;
-        if (sscanf(wkt, "%lf %lf", &x, &y) != 2) {
-            return PARSE_ERR_SYNTAX;
+        err = wkt_read_coord(wkt, tag, &p, &buf, &min_len);
+        if (err == PARSE_OK) err = spatial_object_init_point(obj, 0, p);
+        if (err != PARSE_OK) {
+            free(buf.zm);
+            return err;
         }
-        
-        return spatial_object_init_point(obj, 0, point_create(x, y));
-    }
-    
-    if (strncasecmp(wkt, "LINESTRING", 10) == 0) {
-        wkt += 10;
+    } else if (strncasecmp(wkt, "LINESTRING", 10) == 0) {
+        wkt = wkt_tag(wkt + 10, &tag);
         while (*wkt && (isspace(*wkt) || *wkt == '(')) wkt++;
         
-        int err = spatial_object_init_linestring(obj, 0, 16);
+        err = spatial_object_init_linestring(obj, 0, 16);
         if (err != GEOM_OK) return PARSE_ERR_ALLOC;
         
         while (*wkt && *wkt != ')') {
-            double x, y;
-            if (sscanf(wkt, "%lf %lf", &x, &y) == 2) {
-                linestring_add_point(&obj->geom.line, point_create(x, y));
-            }
+            err = wkt_read_coord(wkt, tag, &p, &buf, &min_len);
+            if (err == PARSE_ERR_ALLOC) break;
+            if (err == PARSE_OK) linestring_add_point(&obj->geom.line, p);
             
             /* Skip to next coordinate */
             while (*wkt && *wkt != ',' && *wkt != ')') wkt++;
@@ -729,25 +1188,18 @@ int wkt_parse(const char *wkt, SpatialObject *obj) {
         }
         
         spatial_object_update_derived(obj);
-        return PARSE_OK;
-    }
-    
-    if (strncasecmp(wkt, "POLYGON", 7) == 0) {
-        wkt += 7;
+    } else if (strncasecmp(wkt, "POLYGON", 7) == 0) {
+        wkt = wkt_tag(wkt + 7, &tag);
         while (*wkt && (isspace(*wkt) || *wkt == '(')) wkt++;
         
-        int err = spatial_object_init_polygon(obj, 0, 16);
+        err = spatial_object_init_polygon(obj, 0, 16);
         if (err != GEOM_OK) return PARSE_ERR_ALLOC;
         
-        /* Skip to first ring */
-        while (*wkt && *wkt != '(') wkt++;
-        if (*wkt == '(') wkt++;
-        
+        /* The loop above skipped the ring's own parenthesis too */
         while (*wkt && *wkt != ')') {
-            double x, y;
-            if (sscanf(wkt, "%lf %lf", &x, &y) == 2) {
-                polygon_add_exterior_point(&obj->geom.polygon, point_create(x, y));
-            }
+            err = wkt_read_coord(wkt, tag, &p, &buf, &min_len);
+            if (err == PARSE_ERR_ALLOC) break;
+            if (err == PARSE_OK) polygon_add_exterior_point(&obj->geom.polygon, p);
             
             while (*wkt && *wkt != ',' && *wkt != ')') wkt++;
             if (*wkt == ',') wkt++;
@@ -755,51 +1207,534 @@ int wkt_parse(const char *wkt, SpatialObject *obj) {
         }
         
         spatial_object_update_derived(obj);
-        return PARSE_OK;
+    } else {
+        return PARSE_ERR_UNSUPPORTED;
     }
     
-    return PARSE_ERR_UNSUPPORTED;
+    if (err == PARSE_ERR_ALLOC) {
+        free(buf.zm);
+        spatial_object_free(obj);
+        return err;
+    }
+    
+    /* A tag fixes the dimensions; untagged coordinates imply them */
+    err = zm_apply(&buf, obj, tag ? tag : zm_dims_for(min_len));
+    if (err != PARSE_OK) spatial_object_free(obj);
+    return err;
 }
 
 int wkt_export(const SpatialObject *obj, char *buffer, size_t buffer_size) {
     if (!obj || !buffer || buffer_size == 0) return PARSE_ERR_NULL_PTR;
     
+    static const char *const tags[] = { "", " Z", " M", " ZM" };
+    const char *tag = tags[obj->zm ? obj->dims & (GEOM_DIM_Z | GEOM_DIM_M) : 0];
+    ZmCursor zc = { obj->zm, obj->dims };
     int written = 0;
     
     switch (obj->type) {
         case GEOM_POINT:
-            written = snprintf(buffer, buffer_size, "POINT (%.6f %.6f)",
-                obj->geom.point.x, obj->geom.point.y);
+            written = append_format(buffer, buffer_size, 0, "POINT%s (", tag);
+            written = append_points(buffer, buffer_size, written, &obj->geom.point, 1, true, &zc);
+            written = append_format(buffer, buffer_size, written, ")");
+            break;
+            
+        case GEOM_LINESTRING:
+            written = append_format(buffer, buffer_size, 0, "LINESTRING%s (", tag);
+            written = append_points(buffer, buffer_size, written, obj->geom.line.points,
+                                    obj->geom.line.count, true, &zc);
+            written = append_format(buffer, buffer_size, written, ")");
+            break;
+            
+        case GEOM_POLYGON:
+            written = append_format(buffer, buffer_size, 0, "POLYGON%s ((", tag);
+            written = append_points(buffer, buffer_size, written, obj->geom.polygon.exterior,
+                                    obj->geom.polygon.ext_count, true, &zc);
+            written = append_format(buffer, buffer_size, written, "))");
             break;
             
-        case GEOM_LINESTRING: {
-            written = snprintf(buffer, buffer_size, "LINESTRING (");
-            for (size_t i = 0; i < obj->geom.line.count && written < (int)buffer_size; i++) {
-                if (i > 0) written += snprintf(buffer + written, buffer_size - written, ", ");
-                written += snprintf(buffer + written, buffer_size - written,
-                    "%.6f %.6f",
-                    obj->geom.line.points[i].x, obj->geom.line.points[i].y);
+        case GEOM_MULTIPOINT:
+            written = append_format(buffer, buffer_size, 0, "MULTIPOINT%s (", tag);
+            written = append_points(buffer, buffer_size, written, obj->geom.multi_point.points,
+                                    obj->geom.multi_point.count, true, &zc);
+            written = append_format(buffer, buffer_size, written, ")");
+            break;
+            
+        case GEOM_MULTILINESTRING:
+            written = append_format(buffer, buffer_size, 0, "MULTILINESTRING%s (", tag);
+            for (size_t i = 0; i < obj->geom.multi_line.count; i++) {
+                const LineString *ls = &obj->geom.multi_line.lines[i];
+                written = append_format(buffer, buffer_size, written, i > 0 ? ", (" : "(");
+                written = append_points(buffer, buffer_size, written, ls->points, ls->count, true, &zc);
+                written = append_format(buffer, buffer_size, written, ")");
             }
-            written += snprintf(buffer + written, buffer_size - written, ")");
+            written = append_format(buffer, buffer_size, written, ")");
             break;
-        }
             
-        case GEOM_POLYGON: {
-            written = snprintf(buffer, buffer_size, "POLYGON ((");
-            for (size_t i = 0; i < obj->geom.polygon.ext_count && written < (int)buffer_size; i++) {
-                if (i > 0) written += snprintf(buffer + written, buffer_size - written, ", ");
-                written += snprintf(buffer + written, buffer_size - written,
-                    "%.6f %.6f",
-                    obj->geom.polygon.exterior[i].x, obj->geom.polygon.exterior[i].y);
+        case GEOM_MULTIPOLYGON:
+            written = append_format(buffer, buffer_size, 0, "MULTIPOLYGON%s (", tag);
+            for (size_t i = 0; i < obj->geom.multi_polygon.count; i++) {
+                const Polygon *poly = &obj->geom.multi_polygon.polygons[i];
+                written = append_format(buffer, buffer_size, written, i > 0 ? ", ((" : "((");
+                written = append_points(buffer, buffer_size, written, poly->exterior, poly->ext_count, true, &zc);
+                written = append_format(buffer, buffer_size, written, "))");
+                /* Holes are not written, so neither are their values */
+                for (size_t h = 0; h < poly->num_holes; h++) zm_skip(&zc, poly->hole_counts[h]);
             }
-            written += snprintf(buffer + written, buffer_size - written, "))");
+            written = append_format(buffer, buffer_size, written, ")");
+            break;
+            
+        case GEOM_GEOMETRYCOLLECTION:
+            written = append_format(buffer, buffer_size, 0, "GEOMETRYCOLLECTION (");
+            for (size_t i = 0; i < obj->geom.collection.count; i++) {
+                if (i > 0) written = append_format(buffer, buffer_size, written, ", ");
+                size_t offset = (size_t)written < buffer_size ? (size_t)written : buffer_size - 1;
+                int n = wkt_export(&obj->geom.collection.geometries[i],
+                                   buffer + offset, buffer_size - offset);
+                if (n > 0) written += n;
+            }
+            written = append_format(buffer, buffer_size, written, ")");
             break;
-        }
     }
     
     return written;
 }
 
+/* ============================================================================
+ * WKB Parsing
+ * ============================================================================ */
+
+#define WKB_POINT              1
+#define WKB_LINESTRING         2
+#define WKB_POLYGON            3
+#define WKB_MULTIPOINT         4
+#define WKB_MULTILINESTRING    5
+#define WKB_MULTIPOLYGON       6
+#define WKB_GEOMETRYCOLLECTION 7
+
+#define EWKB_SRID_FLAG 0x20000000u
+#define EWKB_Z_FLAG    0x80000000u
+#define EWKB_M_FLAG    0x40000000u
+#define EWKB_DIM_FLAGS (EWKB_Z_FLAG | EWKB_M_FLAG)
+
+/* ISO WKB adds 1000 for Z, 2000 for M and 3000 for ZM to the type */
+#define WKB_ISO_DIM_STEP 1000
+
+/* Deepest GeometryCollection nesting accepted, to bound recursion */
+#define WKB_MAX_DEPTH 32
+
+typedef struct {
+    const uint8_t *data;
+    size_t size;
+    size_t pos;
+    bool little_endian;
+    uint8_t dims;       /**< Extra ordinates of the geometry being read */
+    ZmBuffer *zm;       /**< Where its Z/M values go */
+} WkbReader;
+
+static int wkb_read_u32(WkbReader *r, uint32_t *out) {
+    if (r->size - r->pos < 4) return PARSE_ERR_SYNTAX;
+    
+    const uint8_t *b = r->data + r->pos;
+    if (r->little_endian) {
+        *out = (uint32_t)b[0] | (uint32_t)b[1] << 8 | (uint32_t)b[2] << 16 | (uint32_t)b[3] << 24;
+    } else {
+        *out = (uint32_t)b[3] | (uint32_t)b[2] << 8 | (uint32_t)b[1] << 16 | (uint32_t)b[0] << 24;
+    }
+    r->pos += 4;
+    return PARSE_OK;
+}
+
+static int wkb_read_point(WkbReader *r, Point *p) {
+    size_t width = geom_dims_width(r->dims);
+    if (r->size - r->pos < 16 + 8 * width) return PARSE_ERR_SYNTAX;
+    
+    double coords[4] = { 0 };
+    for (size_t c = 0; c < 2 + width; c++) {
+        const uint8_t *b = r->data + r->pos;
+        uint64_t bits = 0;
+        for (int i = 0; i < 8; i++) {
+            int shift = r->little_endian ? i * 8 : (7 - i) * 8;
+            bits |= (uint64_t)b[i] << shift;
+        }
+        memcpy(&coords[c], &bits, sizeof(double));
+        r->pos += 8;
+    }
+    
+    p->x = coords[0];
+    p->y = coords[1];
+    if (width == 0) return PARSE_OK;
+    
+    double z = (r->dims & GEOM_DIM_Z) ? coords[2] : NAN;
+    double m = (r->dims & GEOM_DIM_M) ? coords[width + 1] : NAN;
+    return zm_push(r->zm, z, m);
+}
+
+/**
+ * @brief Read an element count, rejecting counts the input cannot hold
+ */
+static int wkb_read_count(WkbReader *r, size_t min_item_size, size_t *count) {
+    uint32_t n;
+    int err = wkb_read_u32(r, &n);
+    if (err != PARSE_OK) return err;
+    
+    if ((size_t)n > (r->size - r->pos) / min_item_size) return PARSE_ERR_SYNTAX;
+    
+    *count = n;
+    return PARSE_OK;
+}
+
+/**
+ * @brief Read a byte-order marker, geometry type and its Z/M dimensions
+ */
+static int wkb_read_header(WkbReader *r, uint32_t *type, uint8_t *dims) {
+    if (r->pos >= r->size) return PARSE_ERR_SYNTAX;
+    
+    uint8_t order = r->data[r->pos++];
+    if (order > 1) return PARSE_ERR_SYNTAX;
+    r->little_endian = (order == 1);
+    
+    int err = wkb_read_u32(r, type);
+    if (err != PARSE_OK) return err;
+    
+    /* Skip the SRID of PostGIS extended WKB */
+    if (*type & EWKB_SRID_FLAG) {
+        uint32_t srid;
+        err = wkb_read_u32(r, &srid);
+        if (err != PARSE_OK) return err;
+        *type &= ~EWKB_SRID_FLAG;
+    }
+    
+    /* Z and M come as PostGIS flags or as an ISO type offset */
+    *dims = 0;
+    if (*type & EWKB_Z_FLAG) *dims |= GEOM_DIM_Z;
+    if (*type & EWKB_M_FLAG) *dims |= GEOM_DIM_M;
+    *type &= ~EWKB_DIM_FLAGS;
+    if (*type >= WKB_ISO_DIM_STEP && *type < 4 * WKB_ISO_DIM_STEP) {
+        *dims |= (uint8_t)(*type / WKB_ISO_DIM_STEP);
+        *type %= WKB_ISO_DIM_STEP;
+    }
+    
+    if (*type > WKB_GEOMETRYCOLLECTION) return PARSE_ERR_UNSUPPORTED;
+    
+    return PARSE_OK;
+}
+
+static int wkb_read_line(WkbReader *r, LineString *ls) {
+    size_t count;
+    int err = wkb_read_count(r, 16, &count);
+    if (err != PARSE_OK) return err;
+    
+    for (size_t i = 0; i < count; i++) {
+        Point p;
+        err = wkb_read_point(r, &p);
+        if (err != PARSE_OK) return err;
+        if (linestring_add_point(ls, p) != GEOM_OK) return PARSE_ERR_ALLOC;
+    }
+    
+    return PARSE_OK;
+}
+
+static int wkb_read_polygon(WkbReader *r, Polygon *poly) {
+    size_t rings;
+    int err = wkb_read_count(r, 4, &rings);
+    if (err != PARSE_OK) return err;
+    if (rings == 0) return PARSE_ERR_INVALID_GEOM;
+    
+    for (size_t ring = 0; ring < rings; ring++) {
+        size_t count;
+        err = wkb_read_count(r, 16, &count);
+        if (err != PARSE_OK) return err;
+        
+        if (ring > 0 && polygon_add_hole(poly, count) != GEOM_OK) return PARSE_ERR_ALLOC;
+        
+        for (size_t i = 0; i < count; i++) {
+            Point p;
+            err = wkb_read_point(r, &p);
+            if (err != PARSE_OK) return err;
+            
+            int gerr = (ring == 0) ? polygon_add_exterior_point(poly, p)
+                                   : polygon_add_hole_point(poly, ring - 1, p);
+            if (gerr != GEOM_OK) return PARSE_ERR_ALLOC;
+        }
+    }
+    
+    return PARSE_OK;
+}
+
+/**
+ * @brief Read the header of a Multi* member and check its type
+ */
+static int wkb_read_part(WkbReader *r, uint32_t expected) {
+    uint32_t type;
+    uint8_t dims;
+    int err = wkb_read_header(r, &type, &dims);
+    if (err != PARSE_OK) return err;
+    return (type == expected && dims == r->dims) ? PARSE_OK : PARSE_ERR_INVALID_GEOM;
+}
+
+static int wkb_read_geometry(WkbReader *r, SpatialObject *obj, int depth);
+
+/**
+ * @brief Fill an initialized object with the body of a WKB geometry
+ */
+static int wkb_read_body(WkbReader *r, uint32_t type, SpatialObject *obj, int depth) {
+    size_t count;
+    int err;
+    
+    switch (type) {
+        case WKB_LINESTRING:
+            return wkb_read_line(r, &obj->geom.line);
+            
+        case WKB_POLYGON:
+            return wkb_read_polygon(r, &obj->geom.polygon);
+            
+        case WKB_MULTIPOINT:
+            err = wkb_read_count(r, 21, &count);
+            for (size_t i = 0; i < count && err == PARSE_OK; i++) {
+                Point p;
+                err = wkb_read_part(r, WKB_POINT);
+                if (err == PARSE_OK) err = wkb_read_point(r, &p);
+                if (err == PARSE_OK && multipoint_add_point(&obj->geom.multi_point, p) != GEOM_OK) {
+                    err = PARSE_ERR_ALLOC;
+                }
+            }
+            return err;
+            
+        case WKB_MULTILINESTRING:
+            err = wkb_read_count(r, 9, &count);
+            for (size_t i = 0; i < count && err == PARSE_OK; i++) {
+                err = wkb_read_part(r, WKB_LINESTRING);
+                if (err != PARSE_OK) break;
+                
+                int part = multilinestring_add_line(&obj->geom.multi_line, 4);
+                if (part < 0) return PARSE_ERR_ALLOC;
+                err = wkb_read_line(r, &obj->geom.multi_line.lines[part]);
+            }
+            return err;
+            
+        case WKB_MULTIPOLYGON:
+            err = wkb_read_count(r, 9, &count);
+            for (size_t i = 0; i < count && err == PARSE_OK; i++) {
+                err = wkb_read_part(r, WKB_POLYGON);
+                if (err != PARSE_OK) break;
+                
+                int part = multipolygon_add_polygon(&obj->geom.multi_polygon, 4);
+                if (part < 0) return PARSE_ERR_ALLOC;
+                err = wkb_read_polygon(r, &obj->geom.multi_polygon.polygons[part]);
+            }
+            return err;
+            
+        case WKB_GEOMETRYCOLLECTION:
+            err = wkb_read_count(r, 5, &count);
+            for (size_t i = 0; i < count && err == PARSE_OK; i++) {
+                SpatialObject member;
+                err = wkb_read_geometry(r, &member, depth + 1);
+                if (err != PARSE_OK) break;
+                
+                if (geometry_collection_add(&obj->geom.collection, &member) != GEOM_OK) {
+                    err = PARSE_ERR_ALLOC;
+                }
+                spatial_object_free(&member);
+            }
+            return err;
+            
+        default:
+            return PARSE_ERR_UNSUPPORTED;
+    }
+}
+
+/**
+ * @brief Read one geometry's coordinates, which are 2D in the object
+ */
+static int wkb_read_geometry_xy(WkbReader *r, uint32_t type, SpatialObject *obj, int depth) {
+    int err;
+    
+    if (type == WKB_POINT) {
+        Point p;
+        err = wkb_read_point(r, &p);
+        if (err != PARSE_OK) return err;
+        return spatial_object_init_point(obj, 0, p);
+    }
+    
+    switch (type) {
+        case WKB_LINESTRING:      err = spatial_object_init_linestring(obj, 0, 4); break;
+        case WKB_POLYGON:         err = spatial_object_init_polygon(obj, 0, 4); break;
+        case WKB_MULTIPOINT:      err = spatial_object_init_multipoint(obj, 0, 0); break;
+        case WKB_MULTILINESTRING: err = spatial_object_init_multilinestring(obj, 0, 0); break;
+        case WKB_MULTIPOLYGON:    err = spatial_object_init_multipolygon(obj, 0, 0); break;
+        default:                  err = spatial_object_init_collection(obj, 0, 0); break;
+    }
+    if (err != GEOM_OK) return PARSE_ERR_ALLOC;
+    
+    err = wkb_read_body(r, type, obj, depth);
+    if (err == PARSE_OK && spatial_object_update_derived(obj) != GEOM_OK) {
+        err = PARSE_ERR_INVALID_GEOM;
+    }
+    if (err != PARSE_OK) spatial_object_free(obj);
+    
+    return err;
+}
+
+static int wkb_read_geometry(WkbReader *r, SpatialObject *obj, int depth) {
+    if (depth > WKB_MAX_DEPTH) return PARSE_ERR_OVERFLOW;
+    
+    uint32_t type;
+    uint8_t dims;
+    int err = wkb_read_header(r, &type, &dims);
+    if (err != PARSE_OK) return err;
+    
+    /* Collection members are objects with values of their own */
+    ZmBuffer buf = {0};
+    uint8_t outer_dims = r->dims;
+    ZmBuffer *outer = r->zm;
+    r->dims = dims;
+    r->zm = &buf;
+    
+    err = wkb_read_geometry_xy(r, type, obj, depth);
+    
+    r->dims = outer_dims;
+    r->zm = outer;
+    if (err != PARSE_OK) {
+        free(buf.zm);
+        return err;
+    }
+    
+    err = zm_apply(&buf, obj, dims);
+    if (err != PARSE_OK) spatial_object_free(obj);
+    return err;
+}
+
+int wkb_parse(const uint8_t *data, size_t size, SpatialObject *obj, size_t *consumed) {
+    if (!data || !obj) return PARSE_ERR_NULL_PTR;
+    
+    WkbReader reader = { data, size, 0, true, 0, NULL };
+    int err = wkb_read_geometry(&reader, obj, 0);
+    
+    if (consumed) *consumed = reader.pos;
+    return err;
+}
+
+typedef struct {
+    uint8_t *buffer;
+    size_t size;
+    size_t pos;
+} WkbWriter;
+
+static void wkb_write_u32(WkbWriter *w, uint32_t v) {
+    for (int i = 0; i < 4; i++, w->pos++) {
+        if (w->pos < w->size) w->buffer[w->pos] = (uint8_t)(v >> (i * 8));
+    }
+}
+
+/**
+ * @brief Write a byte-order marker and a type, as ISO Z/M types for 3D and 4D
+ */
+static void wkb_write_header(WkbWriter *w, uint32_t type, const ZmCursor *zc) {
+    if (w->pos < w->size) w->buffer[w->pos] = 1;  /* little endian */
+    w->pos++;
+    if (zc->zm) type += (uint32_t)(zc->dims & (GEOM_DIM_Z | GEOM_DIM_M)) * WKB_ISO_DIM_STEP;
+    wkb_write_u32(w, type);
+}
+
+static void wkb_write_points(WkbWriter *w, const Point *points, size_t count, ZmCursor *zc) {
+    for (size_t i = 0; i < count; i++) {
+        double coords[4] = { points[i].x, points[i].y, NAN, NAN };
+        size_t n = 2;
+        if (zc->zm) {
+            double z, m;
+            zm_next(zc, &z, &m);
+            if (zc->dims & GEOM_DIM_Z) coords[n++] = z;
+            if (zc->dims & GEOM_DIM_M) coords[n++] = m;
+        }
+        for (size_t c = 0; c < n; c++) {
+            uint64_t bits;
+            memcpy(&bits, &coords[c], sizeof(double));
+            for (int b = 0; b < 8; b++, w->pos++) {
+                if (w->pos < w->size) w->buffer[w->pos] = (uint8_t)(bits >> (b * 8));
+            }
+        }
+    }
+}
+
+static void wkb_write_polygon(WkbWriter *w, const Polygon *poly, ZmCursor *zc) {
+    wkb_write_u32(w, (uint32_t)(1 + poly->num_holes));
+    wkb_write_u32(w, (uint32_t)poly->ext_count);
+    wkb_write_points(w, poly->exterior, poly->ext_count, zc);
+    
+    for (size_t h = 0; h < poly->num_holes; h++) {
+        wkb_write_u32(w, (uint32_t)poly->hole_counts[h]);
+        wkb_write_points(w, poly->holes[h], poly->hole_counts[h], zc);
+    }
+}
+
+static void wkb_write_geometry(WkbWriter *w, const SpatialObject *obj) {
+    ZmCursor zc = { obj->zm, obj->dims };
+    
+    switch (obj->type) {
+        case GEOM_POINT:
+            wkb_write_header(w, WKB_POINT, &zc);
+            wkb_write_points(w, &obj->geom.point, 1, &zc);
+            break;
+            
+        case GEOM_LINESTRING:
+            wkb_write_header(w, WKB_LINESTRING, &zc);
+            wkb_write_u32(w, (uint32_t)obj->geom.line.count);
+            wkb_write_points(w, obj->geom.line.points, obj->geom.line.count, &zc);
+            break;
+            
+        case GEOM_POLYGON:
+            wkb_write_header(w, WKB_POLYGON, &zc);
+            wkb_write_polygon(w, &obj->geom.polygon, &zc);
+            break;
+            
+        case GEOM_MULTIPOINT:
+            wkb_write_header(w, WKB_MULTIPOINT, &zc);
+            wkb_write_u32(w, (uint32_t)obj->geom.multi_point.count);
+            for (size_t i = 0; i < obj->geom.multi_point.count; i++) {
+                wkb_write_header(w, WKB_POINT, &zc);
+                wkb_write_points(w, &obj->geom.multi_point.points[i], 1, &zc);
+            }
+            break;
+            
+        case GEOM_MULTILINESTRING:
+            wkb_write_header(w, WKB_MULTILINESTRING, &zc);
+            wkb_write_u32(w, (uint32_t)obj->geom.multi_line.count);
+            for (size_t i = 0; i < obj->geom.multi_line.count; i++) {
+                const LineString *ls = &obj->geom.multi_line.lines[i];
+                wkb_write_header(w, WKB_LINESTRING, &zc);
+                wkb_write_u32(w, (uint32_t)ls->count);
+                wkb_write_points(w, ls->points, ls->count, &zc);
+            }
+            break;
+            
+        case GEOM_MULTIPOLYGON:
+            wkb_write_header(w, WKB_MULTIPOLYGON, &zc);
+            wkb_write_u32(w, (uint32_t)obj->geom.multi_polygon.count);
+            for (size_t i = 0; i < obj->geom.multi_polygon.count; i++) {
+                wkb_write_header(w, WKB_POLYGON, &zc);
+                wkb_write_polygon(w, &obj->geom.multi_polygon.polygons[i], &zc);
+            }
+            break;
+            
+        case GEOM_GEOMETRYCOLLECTION:
+            wkb_write_header(w, WKB_GEOMETRYCOLLECTION, &zc);
+            wkb_write_u32(w, (uint32_t)obj->geom.collection.count);
+            for (size_t i = 0; i < obj->geom.collection.count; i++) {
+                wkb_write_geometry(w, &obj->geom.collection.geometries[i]);
+            }
+            break;
+    }
+}
+
+int wkb_export(const SpatialObject *obj, uint8_t *buffer, size_t buffer_size) {
+    if (!obj) return PARSE_ERR_NULL_PTR;
+    
+    WkbWriter writer = { buffer, buffer ? buffer_size : 0, 0 };
+    wkb_write_geometry(&writer, obj);
+    
+    return (int)writer.pos;
+}
+
 /* ============================================================================
  * Feature Collection Operations
  * ============================================================================ */
@@ -828,6 +1763,7 @@ void feature_collection_free(FeatureCollection *fc) {
     }
     
     free(fc->features);
+    free(fc->errors);
     memset(fc, 0, sizeof(FeatureCollection));
 }
 
@@ -939,6 +1875,218 @@ const char* json_get_string(const JsonValue *value, const char *default_val) {
     return value->data.string;
 }
 
+/**
+ * @brief Append n bytes to a bounded buffer, counting what does not fit
+ */
+static void json_put(char *buffer, size_t size, size_t *len, const char *s, size_t n) {
+    for (size_t i = 0; i < n; i++, (*len)++) {
+        if (*len + 1 < size) buffer[*len] = s[i];
+    }
+}
+
+static void json_put_number(char *buffer, size_t size, size_t *len, double number) {
+    char num[32];
+    
+    if (!isfinite(number)) {
+        json_put(buffer, size, len, "null", 4);
+        return;
+    }
+    /* Shortest of 15 or 17 digits that reads back the same */
+    snprintf(num, sizeof(num), "%.15g", number);
+    if (strtod(num, NULL) != number) {
+        snprintf(num, sizeof(num), "%.17g", number);
+    }
+    json_put(buffer, size, len, num, strlen(num));
+}
+
+static void json_put_string(char *buffer, size_t size, size_t *len, const char *s) {
+    json_put(buffer, size, len, "\"", 1);
+    for (; *s; s++) {
+        unsigned char c = (unsigned char)*s;
+        char esc[8];
+        switch (c) {
+            case '"':  json_put(buffer, size, len, "\\\"", 2); break;
+            case '\\': json_put(buffer, size, len, "\\\\", 2); break;
+            case '\n': json_put(buffer, size, len, "\\n", 2); break;
+            case '\t': json_put(buffer, size, len, "\\t", 2); break;
+            case '\r': json_put(buffer, size, len, "\\r", 2); break;
+            default:
+                if (c < 0x20) {
+                    snprintf(esc, sizeof(esc), "\\u%04x", c);
+                    json_put(buffer, size, len, esc, 6);
+                } else {
+                    json_put(buffer, size, len, s, 1);
+                }
+                break;
+        }
+    }
+    json_put(buffer, size, len, "\"", 1);
+}
+
+static void json_put_value(char *buffer, size_t size, size_t *len, const JsonValue *value) {
+    switch (value->type) {
+        case JSON_BOOL:
+            if (value->data.boolean) json_put(buffer, size, len, "true", 4);
+            else json_put(buffer, size, len, "false", 5);
+            break;
+            
+        case JSON_NUMBER:
+            json_put_number(buffer, size, len, value->data.number);
+            break;
+            
+        case JSON_STRING:
+            json_put_string(buffer, size, len, value->data.string);
+            break;
+            
+        case JSON_ARRAY:
+            json_put(buffer, size, len, "[", 1);
+            for (size_t i = 0; i < value->data.array.count; i++) {
+                if (i > 0) json_put(buffer, size, len, ",", 1);
+                json_put_value(buffer, size, len, &value->data.array.items[i]);
+            }
+            json_put(buffer, size, len, "]", 1);
+            break;
+            
+        case JSON_OBJECT:
+            json_put(buffer, size, len, "{", 1);
+            for (size_t i = 0; i < value->data.object.count; i++) {
+                if (i > 0) json_put(buffer, size, len, ",", 1);
+                json_put_string(buffer, size, len, value->data.object.keys[i]);
+                json_put(buffer, size, len, ":", 1);
+                json_put_value(buffer, size, len, &value->data.object.values[i]);
+            }
+            json_put(buffer, size, len, "}", 1);
+            break;
+            
+        default:
+            json_put(buffer, size, len, "null", 4);
+            break;
+    }
+}
+
+size_t json_write(const JsonValue *value, char *buffer, size_t size) {
+    size_t len = 0;
+    if (value) json_put_value(buffer, size, &len, value);
+    if (size > 0) buffer[len < size ? len : size - 1] = '\0';
+    return len;
+}
+
+/**
+ * @brief Write a position: [x, y], [x, y, z], or [x, y, z, m] with a null
+ * z when the object has M but no Z
+ */
+static void geojson_put_position(char *buffer, size_t size, size_t *len, const Point *p,
+                                 ZmCursor *zc) {
+    json_put(buffer, size, len, "[", 1);
+    json_put_number(buffer, size, len, p->x);
+    json_put(buffer, size, len, ",", 1);
+    json_put_number(buffer, size, len, p->y);
+    if (zc->zm) {
+        double z, m;
+        zm_next(zc, &z, &m);
+        json_put(buffer, size, len, ",", 1);
+        json_put_number(buffer, size, len, z);
+        if (zc->dims & GEOM_DIM_M) {
+            json_put(buffer, size, len, ",", 1);
+            json_put_number(buffer, size, len, m);
+        }
+    }
+    json_put(buffer, size, len, "]", 1);
+}
+
+static void geojson_put_positions(char *buffer, size_t size, size_t *len,
+                                  const Point *points, size_t count, ZmCursor *zc) {
+    json_put(buffer, size, len, "[", 1);
+    for (size_t i = 0; i < count; i++) {
+        if (i > 0) json_put(buffer, size, len, ",", 1);
+        geojson_put_position(buffer, size, len, &points[i], zc);
+    }
+    json_put(buffer, size, len, "]", 1);
+}
+
+static void geojson_put_rings(char *buffer, size_t size, size_t *len, const Polygon *poly,
+                              ZmCursor *zc) {
+    json_put(buffer, size, len, "[", 1);
+    geojson_put_positions(buffer, size, len, poly->exterior, poly->ext_count, zc);
+    for (size_t h = 0; h < poly->num_holes; h++) {
+        json_put(buffer, size, len, ",", 1);
+        geojson_put_positions(buffer, size, len, poly->holes[h], poly->hole_counts[h], zc);
+    }
+    json_put(buffer, size, len, "]", 1);
+}
+
+static void geojson_put_type(char *buffer, size_t size, size_t *len, const char *type) {
+    json_put(buffer, size, len, "{\"type\":", 8);
+    json_put_string(buffer, size, len, type);
+}
+
+static void geojson_put_geometry(char *buffer, size_t size, size_t *len, const SpatialObject *obj) {
+    static const char coordinates[] = ",\"coordinates\":";
+    const size_t coordinates_len = sizeof(coordinates) - 1;
+    ZmCursor zc = { obj->zm, obj->dims };
+    
+    switch (obj->type) {
+        case GEOM_POINT:
+            geojson_put_type(buffer, size, len, "Point");
+            json_put(buffer, size, len, coordinates, coordinates_len);
+            geojson_put_position(buffer, size, len, &obj->geom.point, &zc);
+            break;
+            
+        case GEOM_LINESTRING:
+            geojson_put_type(buffer, size, len, "LineString");
+            json_put(buffer, size, len, coordinates, coordinates_len);
+            geojson_put_positions(buffer, size, len, obj->geom.line.points, obj->geom.line.count, &zc);
+            break;
+            
+        case GEOM_POLYGON:
+            geojson_put_type(buffer, size, len, "Polygon");
+            json_put(buffer, size, len, coordinates, coordinates_len);
+            geojson_put_rings(buffer, size, len, &obj->geom.polygon, &zc);
+            break;
+            
+        case GEOM_MULTIPOINT:
+            geojson_put_type(buffer, size, len, "MultiPoint");
+            json_put(buffer, size, len, coordinates, coordinates_len);
+            geojson_put_positions(buffer, size, len, obj->geom.multi_point.points,
+                                  obj->geom.multi_point.count, &zc);
+            break;
+            
+        case GEOM_MULTILINESTRING:
+            geojson_put_type(buffer, size, len, "MultiLineString");
+            json_put(buffer, size, len, coordinates, coordinates_len);
+            json_put(buffer, size, len, "[", 1);
+            for (size_t i = 0; i < obj->geom.multi_line.count; i++) {
+                const LineString *ls = &obj->geom.multi_line.lines[i];
+                if (i > 0) json_put(buffer, size, len, ",", 1);
+                geojson_put_positions(buffer, size, len, ls->points, ls->count, &zc);
+            }
+            json_put(buffer, size, len, "]", 1);
+            break;
+            
+        case GEOM_MULTIPOLYGON:
+            geojson_put_type(buffer, size, len, "MultiPolygon");
+            json_put(buffer, size, len, coordinates, coordinates_len);
+            json_put(buffer, size, len, "[", 1);
+            for (size_t i = 0; i < obj->geom.multi_polygon.count; i++) {
+                if (i > 0) json_put(buffer, size, len, ",", 1);
+                geojson_put_rings(buffer, size, len, &obj->geom.multi_polygon.polygons[i], &zc);
+            }
+            json_put(buffer, size, len, "]", 1);
+            break;
+            
+        case GEOM_GEOMETRYCOLLECTION:
+            geojson_put_type(buffer, size, len, "GeometryCollection");
+            json_put(buffer, size, len, ",\"geometries\":[", 15);
+            for (size_t i = 0; i < obj->geom.collection.count; i++) {
+                if (i > 0) json_put(buffer, size, len, ",", 1);
+                geojson_put_geometry(buffer, size, len, &obj->geom.collection.geometries[i]);
+            }
+            json_put(buffer, size, len, "]", 1);
+            break;
+    }
+    json_put(buffer, size, len, "}", 1);
+}
+
 /* ============================================================================
  * Parser Utilities
  * ============================================================================ */
@@ -981,6 +2129,26 @@ GeomType parser_detect_type(const char *input) {
     
     while (*input && isspace(*input)) input++;
     
+    if (strncasecmp(input, "GEOMETRYCOLLECTION", 18) == 0 ||
+        strstr(input, "\"GeometryCollection\"") != NULL) {
+        return GEOM_GEOMETRYCOLLECTION;
+    }
+    
+    if (strncasecmp(input, "MULTIPOINT", 10) == 0 ||
+        strstr(input, "\"MultiPoint\"") != NULL) {
+        return GEOM_MULTIPOINT;
+    }
+    
+    if (strncasecmp(input, "MULTILINESTRING", 15) == 0 ||
+        strstr(input, "\"MultiLineString\"") != NULL) {
+        return GEOM_MULTILINESTRING;
+    }
+    
+    if (strncasecmp(input, "MULTIPOLYGON", 12) == 0 ||
+        strstr(input, "\"MultiPolygon\"") != NULL) {
+        return GEOM_MULTIPOLYGON;
+    }
+    
     if (strncasecmp(input, "POINT", 5) == 0 ||
         strstr(input, "\"Point\"") != NULL) {
         return GEOM_POINT;
@@ -63,6 +63,7 @@ static int qtnode_split(QTNode *node, size_t capacity) {
     node->is_leaf = false;
     
     /* Redistribute items to children */
+    size_t kept = 0;
     for (size_t i = 0; i < node->item_count; i++) {
         QTItem *item = &node->items[i];
         bool inserted = false;
@@ -79,18 +80,14 @@ static int qtnode_split(QTNode *node, size_t capacity) {
             }
         }
         
-        /* If item spans multiple quadrants, keep in parent */
+        /* Items that span multiple quadrants stay in this node, compacted
+         * to the front of the items array */
         if (!inserted) {
-            /* Items that don't fit in any child stay in this node */
-            /* Move to front of items array */
-            if (i > 0) {
-                /* Already processed items that stayed are at front */
-            }
+            node->items[kept++] = *item;
         }
     }
     
-    /* Clear parent items (they've been moved to children or will be reinserted) */
-    node->item_count = 0;
+    node->item_count = kept;
     
     return QT_OK;
 }
@@ -508,6 +505,21 @@ void quadtree_stats(const QuadTree *qt, size_t *total_items, size_t *total_nodes
     if (leaf_count) *leaf_count = leaves;
 }
 
+static size_t qtnode_memory(const QTNode *node) {
+    if (!node) return 0;
+    
+    size_t bytes = sizeof(QTNode) + node->item_capacity * sizeof(QTItem);
+    for (int i = 0; i < 4; i++) {
+        bytes += qtnode_memory(node->children[i]);
+    }
+    return bytes;
+}
+
+size_t quadtree_memory(const QuadTree *qt) {
+    if (!qt) return 0;
+    return qtnode_memory(qt->root);
+}
+
 void quadtree_clear(QuadTree *qt) {
     if (!qt || !qt->root) return;
     
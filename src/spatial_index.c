@@ -7,6 +7,7 @@
 #include <stdlib.h>
 #include <string.h>
 #include <math.h>
+#include <time.h>
 
 /* ============================================================================
  * Internal Helpers
@@ -121,7 +122,13 @@ SpatialIndexConfig spatial_index_default_config(void) {
         .cache_size = DM_DEFAULT_CACHE_SIZE,
         .build_quadtree = true,
         .persist = false,
-        .data_path = NULL
+        .data_path = NULL,
+        .snap_grid = 0,
+        .simplify_tolerance = 0,
+        .dedup_points = false,
+        .seek_ms = SI_ROTATIONAL_SEEK_MS,
+        .transfer_mb_s = SI_ROTATIONAL_TRANSFER_MB_S,
+        .build_threads = 1
     };
     return config;
 }
@@ -153,9 +160,15 @@ int spatial_index_init(SpatialIndex *idx, const SpatialIndexConfig *config) {
     int err = kdtree_init(&idx->block_tree);
     if (err != KD_OK) return SI_ERR_ALLOC;
     
+    /* Pages are fixed-size on disk, so capacity is capped at what fits */
+    if (idx->config.page_capacity == 0 || idx->config.page_capacity > MAX_OBJECTS_PER_PAGE) {
+        idx->config.page_capacity = MAX_OBJECTS_PER_PAGE;
+    }
+    
     /* Initialize disk manager */
     DiskManagerConfig dm_config = disk_manager_default_config();
     dm_config.cache_size = idx->config.cache_size;
+    dm_config.page_capacity = idx->config.page_capacity;
     
     err = disk_manager_init(&idx->disk, &dm_config);
     if (err != DM_OK) {
@@ -176,6 +189,7 @@ int spatial_index_init(SpatialIndex *idx, const SpatialIndexConfig *config) {
     idx->next_block_id = 1;
     idx->bounds = mbr_empty();
     idx->is_built = false;
+    idx->changes_since_build = 0;
     idx->page_tree = NULL;
     
     return SI_OK;
@@ -185,6 +199,7 @@ void spatial_index_free(SpatialIndex *idx) {
     if (!idx) return;
     
     kdtree_free(&idx->block_tree);
+    free(idx->block_entries);
     
     if (idx->page_tree) {
         quadtree_destroy(idx->page_tree);
@@ -203,17 +218,115 @@ void spatial_index_destroy(SpatialIndex *idx) {
     free(idx);
 }
 
+/**
+ * @brief Find a stored point object with exactly these coordinates
+ */
+static SpatialObject* find_point(SpatialIndex *idx, const Point *p) {
+    for (size_t i = 0; i < idx->disk.pool.page_count; i++) {
+        Page *page = idx->disk.pool.pages[i];
+        if (!mbr_contains_point(&page->header.extent, p)) continue;
+        
+        for (size_t j = 0; j < page->header.object_count; j++) {
+            SpatialObject *obj = &page->objects[j];
+            if (obj->type == GEOM_POINT &&
+                obj->geom.point.x == p->x && obj->geom.point.y == p->y) {
+                return obj;
+            }
+        }
+    }
+    return NULL;
+}
+
+/**
+ * @brief Record one more duplicate of a deduplicated point
+ *
+ * The count is kept in the object's properties as {"count":N}.
+ */
+static int count_duplicate(SpatialObject *obj) {
+    unsigned long long count = 1;
+    const char *prefix = "{\"count\":";
+    
+    if (obj->properties && obj->properties_size > strlen(prefix) &&
+        memcmp(obj->properties, prefix, strlen(prefix)) == 0) {
+        char buf[32] = {0};
+        size_t n = obj->properties_size - strlen(prefix);
+        memcpy(buf, (const char *)obj->properties + strlen(prefix), n < sizeof(buf) - 1 ? n : sizeof(buf) - 1);
+        count = strtoull(buf, NULL, 10);
+    }
+    
+    char props[48];
+    int len = snprintf(props, sizeof(props), "{\"count\":%llu}", count + 1);
+    
+    return spatial_object_set_properties(obj, props, (size_t)len) == GEOM_OK ? SI_OK : SI_ERR_ALLOC;
+}
+
+/**
+ * @brief Record a modification of obj with the next version and the current time
+ */
+static void stamp_modified(SpatialIndex *idx, SpatialObject *obj) {
+    struct timespec ts;
+    clock_gettime(CLOCK_REALTIME, &ts);
+    
+    obj->version = ++idx->version_clock;
+    obj->modified_at = (int64_t)ts.tv_sec * 1000 + ts.tv_nsec / 1000000;
+    idx->change_count++;
+}
+
+/**
+ * @brief Check an object against the configured valid bounds, before any
+ * snapping or simplification moves it
+ */
+static bool within_valid_bounds(const SpatialIndex *idx, SpatialObject *obj) {
+    if (!idx->config.bounded) return true;
+    
+    spatial_object_update_derived(obj);
+    return mbr_contains_mbr(&idx->config.valid_bounds, &obj->mbr);
+}
+
 int spatial_index_insert(SpatialIndex *idx, SpatialObject *obj) {
     if (!idx || !obj) return SI_ERR_NULL_PTR;
     
-    /* Assign ID if not set */
+    /* NaN/Inf coordinates would poison the MBR comparisons of every query */
+    if (!spatial_object_is_finite(obj)) return SI_ERR_INVALID;
+    
+    if (!within_valid_bounds(idx, obj)) {
+        idx->out_of_bounds++;
+        return SI_ERR_OUT_OF_BOUNDS;
+    }
+    
+    if (idx->config.snap_grid > 0) {
+        spatial_object_snap(obj, idx->config.snap_grid);
+    }
+    if (idx->config.simplify_tolerance > 0) {
+        spatial_object_simplify(obj, idx->config.simplify_tolerance);
+    }
+    
+    if (idx->config.dedup_points && obj->type == GEOM_POINT) {
+        SpatialObject *existing = find_point(idx, &obj->geom.point);
+        if (existing) {
+            obj->id = existing->id;
+            int err = count_duplicate(existing);
+            if (err == SI_OK) stamp_modified(idx, existing);
+            return err;
+        }
+    }
+    
+    /* Assign ID if not set, keeping automatic IDs clear of explicit ones */
     if (obj->id == 0) {
         obj->id = idx->next_object_id++;
+    } else if (obj->id >= idx->next_object_id && obj->id < UINT64_MAX) {
+        idx->next_object_id = obj->id + 1;
     }
+    idx->disk.header.next_object_id = idx->next_object_id;
     
     /* Update derived properties */
     spatial_object_update_derived(obj);
     
+    /* Objects moved from another index (see spatial_index_repage) keep their stamp */
+    if (obj->version == 0) {
+        stamp_modified(idx, obj);
+    }
+    
     /* Find or create page for this object */
     Page *page = get_page_for_insert(idx, obj);
     if (!page) return SI_ERR_ALLOC;
@@ -240,6 +353,7 @@ int spatial_index_insert(SpatialIndex *idx, SpatialObject *obj) {
     
     /* Invalidate built state */
     idx->is_built = false;
+    idx->changes_since_build++;
     
     return SI_OK;
 }
@@ -268,11 +382,105 @@ int spatial_index_remove(SpatialIndex *idx, uint64_t object_id) {
     disk_manager_rebuild_allocation_tree(&idx->disk);
     
     idx->is_built = false;
+    idx->changes_since_build++;
+    idx->change_count++;
+    
+    return SI_OK;
+}
+
+/**
+ * @brief Check whether an object falls within a region under a match rule
+ */
+static bool object_matches(const SpatialObject *obj, const MBR *region,
+                           SpatialMatch match) {
+    switch (match) {
+        case SI_MATCH_CONTAINED:
+            return mbr_contains_mbr(region, &obj->mbr);
+        case SI_MATCH_CENTROID:
+            return mbr_contains_point(region, &obj->centroid);
+        case SI_MATCH_INTERSECTS:
+            return mbr_intersects(region, &obj->mbr);
+    }
+    return false;
+}
+
+int spatial_index_remove_range(SpatialIndex *idx, const MBR *region,
+                                SpatialMatch match, size_t *removed) {
+    if (!idx || !region || !removed) return SI_ERR_NULL_PTR;
+    if (match != SI_MATCH_CONTAINED && match != SI_MATCH_CENTROID &&
+        match != SI_MATCH_INTERSECTS) {
+        return SI_ERR_INVALID;
+    }
+    
+    *removed = 0;
+    
+    for (size_t i = 0; i < idx->disk.pool.page_count; i++) {
+        Page *page = idx->disk.pool.pages[i];
+        if (!mbr_intersects(&page->header.extent, region)) continue;
+        
+        /* Walk backwards so removals don't shift unvisited objects */
+        for (size_t j = page->header.object_count; j > 0; j--) {
+            SpatialObject *obj = &page->objects[j - 1];
+            if (object_matches(obj, region, match) &&
+                page_remove_object(page, obj->id) == PAGE_OK) {
+                (*removed)++;
+            }
+        }
+    }
+    
+    if (*removed > 0) {
+        disk_manager_rebuild_allocation_tree(&idx->disk);
+        idx->is_built = false;
+        idx->changes_since_build += *removed;
+        idx->change_count += *removed;
+    }
     
     return SI_OK;
 }
 
 int spatial_index_build(SpatialIndex *idx) {
+    return spatial_index_build_progress(idx, NULL, NULL);
+}
+
+int spatial_index_build_progress(SpatialIndex *idx, BuildProgressFn on_progress,
+                                 void *user_data) {
+    return spatial_index_build_cancellable(idx, on_progress, NULL, user_data);
+}
+
+/**
+ * @brief Drop the structures of a cancelled build, leaving the index unbuilt
+ */
+static int abandon_build(SpatialIndex *idx) {
+    kdtree_free(&idx->block_tree);
+    kdtree_init(&idx->block_tree);
+    free(idx->block_entries);
+    idx->block_entries = NULL;
+    idx->block_entry_count = 0;
+    idx->block_count = 0;
+    idx->build_threads_used = 0;
+    idx->is_built = false;
+    return SI_ERR_CANCELLED;
+}
+
+/*
+ * Progress is reported in objects. Gathering centroids covers the first
+ * half of the range, tree and block construction the next phases, and the
+ * final report (done == total) is only sent once the build has succeeded.
+ */
+#define REPORT_PROGRESS(done, total) \
+    do { if (on_progress) on_progress(user_data, (done), (total)); } while (0)
+#define CANCELLED() (should_cancel && should_cancel(user_data))
+
+/**
+ * @brief Build the index, replacing any earlier build only once it succeeds
+ *
+ * The new block tree is built and partitioned before any of the index's
+ * own structures change, and cancellation is only checked until then.
+ * A cancelled build leaves the index as it was when keep_on_cancel is set,
+ * and unbuilt otherwise.
+ */
+static int build_index(SpatialIndex *idx, BuildProgressFn on_progress,
+                       BuildCancelFn should_cancel, void *user_data, bool keep_on_cancel) {
     if (!idx) return SI_ERR_NULL_PTR;
     
     /* Collect all objects for partitioning */
@@ -281,56 +489,122 @@ int spatial_index_build(SpatialIndex *idx) {
     
     if (total_objects == 0) {
         idx->is_built = true;
+        idx->changes_since_build = 0;
+        idx->build_threads_used = 0;
+        REPORT_PROGRESS(0, 0);
         return SI_OK;
     }
     
-    /* Build KD-tree from object centroids for block partitioning */
+    REPORT_PROGRESS(0, total_objects);
+    if (CANCELLED()) return keep_on_cancel ? SI_ERR_CANCELLED : abandon_build(idx);
+    size_t report_every = total_objects / 100 > 0 ? total_objects / 100 : 1;
+    
+    /* Build KD-tree from object centroids for block partitioning; each
+     * point carries the object's page so queries need not look it up */
     KDPointData *points = (KDPointData *)malloc(total_objects * sizeof(KDPointData));
-    if (!points) return SI_ERR_ALLOC;
+    BlockEntry *entries = (BlockEntry *)malloc(total_objects * sizeof(BlockEntry));
+    if (!points || !entries) {
+        free(points);
+        free(entries);
+        return SI_ERR_ALLOC;
+    }
     
     size_t point_idx = 0;
+    Point reach = {0.0, 0.0};
     for (size_t i = 0; i < idx->disk.pool.page_count; i++) {
         Page *page = idx->disk.pool.pages[i];
         for (size_t j = 0; j < page->header.object_count; j++) {
-            points[point_idx].point = page->objects[j].centroid;
-            points[point_idx].object_id = page->objects[j].id;
-            points[point_idx].data = &page->objects[j];
+            SpatialObject *obj = &page->objects[j];
+            points[point_idx].point = obj->centroid;
+            points[point_idx].object_id = obj->id;
+            entries[point_idx].object = obj;
+            entries[point_idx].page = page;
+            points[point_idx].data = &entries[point_idx];
             point_idx++;
+            
+            /* Track how far an MBR can extend past its centroid so KD-tree
+             * range queries over centroids can be widened to stay exact */
+            reach.x = fmax(reach.x, fmax(obj->centroid.x - obj->mbr.min_x,
+                                         obj->mbr.max_x - obj->centroid.x));
+            reach.y = fmax(reach.y, fmax(obj->centroid.y - obj->mbr.min_y,
+                                         obj->mbr.max_y - obj->centroid.y));
+            
+            if (point_idx % report_every == 0) {
+                REPORT_PROGRESS(point_idx / 2, total_objects);
+                if (CANCELLED()) {
+                    free(points);
+                    free(entries);
+                    return keep_on_cancel ? SI_ERR_CANCELLED : abandon_build(idx);
+                }
+            }
         }
     }
     
-    /* Build block tree */
-    kdtree_free(&idx->block_tree);
-    kdtree_init(&idx->block_tree);
-    int err = kdtree_bulk_load(&idx->block_tree, points, point_idx);
+    /* Build the new block tree beside the current one */
+    KDTree tree;
+    kdtree_init(&tree);
+    size_t threads_used = 0;
+    int err = kdtree_bulk_load_parallel(&tree, points, point_idx,
+                                        idx->config.build_threads, idx->config.seed,
+                                        &threads_used);
     free(points);
     
-    if (err != KD_OK) return SI_ERR_ALLOC;
+    if (err != KD_OK) {
+        kdtree_free(&tree);
+        free(entries);
+        return SI_ERR_ALLOC;
+    }
+    REPORT_PROGRESS(total_objects * 3 / 4, total_objects);
+    if (CANCELLED()) {
+        kdtree_free(&tree);
+        free(entries);
+        return keep_on_cancel ? SI_ERR_CANCELLED : abandon_build(idx);
+    }
     
     /* Partition into blocks */
     MBR *block_bounds = NULL;
     size_t block_count = 0;
     
-    err = kdtree_partition(&idx->block_tree, idx->config.block_size,
-                           &block_count, &block_bounds);
-    if (err != KD_OK) return SI_ERR_ALLOC;
+    err = kdtree_partition(&tree, idx->config.block_size, &block_count, &block_bounds);
+    if (err != KD_OK) {
+        kdtree_free(&tree);
+        free(entries);
+        return SI_ERR_ALLOC;
+    }
+    REPORT_PROGRESS(total_objects * 9 / 10, total_objects);
+    if (CANCELLED()) {
+        free(block_bounds);
+        kdtree_free(&tree);
+        free(entries);
+        return keep_on_cancel ? SI_ERR_CANCELLED : abandon_build(idx);
+    }
     
     /* Create blocks from partitions */
-    free(idx->blocks);
-    idx->blocks = NULL;
-    idx->block_count = 0;
-    idx->block_capacity = block_count > 0 ? block_count : 16;
-    idx->blocks = (SpatialBlock *)calloc(idx->block_capacity, sizeof(SpatialBlock));
-    
-    if (!idx->blocks) {
+    size_t block_capacity = block_count > 0 ? block_count : 16;
+    SpatialBlock *blocks = (SpatialBlock *)calloc(block_capacity, sizeof(SpatialBlock));
+    if (!blocks) {
         free(block_bounds);
+        kdtree_free(&tree);
+        free(entries);
         return SI_ERR_ALLOC;
     }
     
+    /* Past this point the build can no longer be cancelled; swap it in */
+    kdtree_free(&idx->block_tree);
+    idx->block_tree = tree;
+    free(idx->block_entries);
+    idx->block_entries = entries;
+    idx->block_entry_count = point_idx;
+    idx->build_threads_used = threads_used;
+    idx->object_reach = reach;
+    
+    free(idx->blocks);
+    idx->blocks = blocks;
+    idx->block_count = 0;
+    idx->block_capacity = block_capacity;
     for (size_t i = 0; i < block_count; i++) {
         create_block(idx, block_bounds[i]);
     }
-    
     free(block_bounds);
     
     /* Build page quadtree */
@@ -338,26 +612,28 @@ int spatial_index_build(SpatialIndex *idx) {
     if (err != SI_OK) return err;
     
     idx->is_built = true;
+    idx->changes_since_build = 0;
+    REPORT_PROGRESS(total_objects, total_objects);
     
     return SI_OK;
 }
 
-int spatial_index_query_range(SpatialIndex *idx, const MBR *range,
+int spatial_index_build_cancellable(SpatialIndex *idx, BuildProgressFn on_progress,
+                                    BuildCancelFn should_cancel, void *user_data) {
+    return build_index(idx, on_progress, should_cancel, user_data, false);
+}
+
+#undef REPORT_PROGRESS
+#undef CANCELLED
+
+/**
+ * @brief Add intersecting objects from a set of pages to a result
+ */
+static void collect_from_pages(Page **pages, size_t page_count, const MBR *range,
                                SpatialQueryResult *result) {
-    if (!idx || !range || !result) return SI_ERR_NULL_PTR;
-    
-    spatial_result_clear(result);
-    
-    /* Get pages intersecting range */
-    Page **pages = NULL;
-    size_t page_count = 0;
-    
-    int err = page_pool_query_region(&idx->disk.pool, range, &pages, &page_count);
-    if (err != PAGE_OK) return SI_ERR_IO;
-    
-    /* Collect objects from matching pages */
     for (size_t i = 0; i < page_count; i++) {
         Page *page = pages[i];
+        spatial_result_add_page(result, page->header.page_id);
         for (size_t j = 0; j < page->header.object_count; j++) {
             SpatialObject *obj = &page->objects[j];
             if (mbr_intersects(&obj->mbr, range)) {
@@ -365,20 +641,184 @@ int spatial_index_query_range(SpatialIndex *idx, const MBR *range,
             }
         }
     }
+}
+
+/**
+ * @brief Check whether a structure is built and current
+ */
+static bool structure_available(const SpatialIndex *idx, SpatialStructure structure) {
+    switch (structure) {
+        case SI_STRUCTURE_KDTREE:
+            return idx->is_built && idx->block_tree.root != NULL;
+        case SI_STRUCTURE_QUADTREE:
+            return idx->is_built && idx->page_tree != NULL;
+        case SI_STRUCTURE_AUTO:
+        case SI_STRUCTURE_SCAN:
+            return true;
+    }
+    return false;
+}
+
+static int query_range_scan(SpatialIndex *idx, const MBR *range,
+                            SpatialQueryResult *result) {
+    Page **pages = NULL;
+    size_t page_count = 0;
     
+    int err = page_pool_query_region(&idx->disk.pool, range, &pages, &page_count);
+    if (err != PAGE_OK) return SI_ERR_IO;
+    
+    collect_from_pages(pages, page_count, range, result);
     free(pages);
     
     return SI_OK;
 }
 
-int spatial_index_query_point(SpatialIndex *idx, Point p,
+static int query_range_quadtree(SpatialIndex *idx, const MBR *range,
+                                SpatialQueryResult *result) {
+    QTQueryResult qt_result;
+    if (qtresult_init(&qt_result, 64) != QT_OK) return SI_ERR_ALLOC;
+    
+    int err = quadtree_query_range(idx->page_tree, range, &qt_result);
+    if (err != QT_OK) {
+        qtresult_free(&qt_result);
+        return SI_ERR_NOT_FOUND;
+    }
+    
+    for (size_t i = 0; i < qt_result.count; i++) {
+        Page *page = (Page *)qt_result.items[i].data;
+        if (page) collect_from_pages(&page, 1, range, result);
+    }
+    
+    qtresult_free(&qt_result);
+    
+    return SI_OK;
+}
+
+static int query_range_kdtree(SpatialIndex *idx, const MBR *range,
+                              SpatialQueryResult *result) {
+    /* The tree holds centroids; widen the range by the largest MBR reach so
+     * every object whose MBR intersects the range is a candidate */
+    MBR widened = mbr_create(range->min_x - idx->object_reach.x,
+                             range->min_y - idx->object_reach.y,
+                             range->max_x + idx->object_reach.x,
+                             range->max_y + idx->object_reach.y);
+    
+    KDQueryResult kd_result;
+    if (kdresult_init(&kd_result, 64) != KD_OK) return SI_ERR_ALLOC;
+    
+    int err = kdtree_range_query(&idx->block_tree, &widened, &kd_result);
+    if (err != KD_OK) {
+        kdresult_free(&kd_result);
+        return SI_ERR_NOT_FOUND;
+    }
+    
+    for (size_t i = 0; i < kd_result.count; i++) {
+        const BlockEntry *entry = (const BlockEntry *)kd_result.data[i];
+        if (!entry || !mbr_intersects(&entry->object->mbr, range)) continue;
+        
+        spatial_result_add(result, entry->object);
+        spatial_result_add_page(result, entry->page->header.page_id);
+    }
+    
+    kdresult_free(&kd_result);
+    
+    return SI_OK;
+}
+
+/**
+ * @brief Verify the pages a query accessed, dropping objects on bad pages
+ *
+ * Pages whose checksum does not match are listed in failed_page_ids, so a
+ * corrupt page costs the query its own objects rather than the whole result.
+ */
+static int drop_corrupt_pages(SpatialIndex *idx, SpatialQueryResult *result) {
+    for (size_t i = 0; i < result->pages_accessed; i++) {
+        Page *page = page_pool_get(&idx->disk.pool, result->page_ids[i]);
+        if (!page || page_verify(page)) continue;
+        
+        uint32_t *failed = (uint32_t *)realloc(result->failed_page_ids,
+                                               (result->pages_failed + 1) * sizeof(uint32_t));
+        if (!failed) return SI_ERR_ALLOC;
+        result->failed_page_ids = failed;
+        result->failed_page_ids[result->pages_failed++] = page->header.page_id;
+    }
+    if (result->pages_failed == 0) return SI_OK;
+    
+    /* Only the failed pages are checked, so a clean query pays nothing */
+    size_t kept = 0;
+    for (size_t i = 0; i < result->count; i++) {
+        const SpatialObject *obj = result->objects[i];
+        bool failed = false;
+        for (size_t j = 0; !failed && j < result->pages_failed; j++) {
+            Page *page = page_pool_get(&idx->disk.pool, result->failed_page_ids[j]);
+            failed = page && obj >= page->objects &&
+                     obj < page->objects + page->header.object_count;
+        }
+        if (!failed) result->objects[kept++] = result->objects[i];
+    }
+    result->count = kept;
+    
+    return SI_OK;
+}
+
+int spatial_index_query_range_using(SpatialIndex *idx, const MBR *range,
+                                     SpatialStructure structure,
+                                     SpatialQueryResult *result) {
+    if (!idx || !range || !result) return SI_ERR_NULL_PTR;
+    
+    spatial_result_clear(result);
+    
+    /* Fall back to the other tree, then to a page scan */
+    if (!structure_available(idx, structure)) {
+        SpatialStructure other = structure == SI_STRUCTURE_KDTREE ?
+                                 SI_STRUCTURE_QUADTREE : SI_STRUCTURE_KDTREE;
+        structure = structure_available(idx, other) ? other : SI_STRUCTURE_SCAN;
+    }
+    
+    int err;
+    switch (structure) {
+        case SI_STRUCTURE_KDTREE:
+            result->structure = SI_STRUCTURE_KDTREE;
+            err = query_range_kdtree(idx, range, result);
+            break;
+        case SI_STRUCTURE_QUADTREE:
+            result->structure = SI_STRUCTURE_QUADTREE;
+            err = query_range_quadtree(idx, range, result);
+            break;
+        case SI_STRUCTURE_AUTO:
+        case SI_STRUCTURE_SCAN:
+        default:
+            result->structure = SI_STRUCTURE_SCAN;
+            err = query_range_scan(idx, range, result);
+            break;
+    }
+    if (err != SI_OK) return err;
+    
+    err = drop_corrupt_pages(idx, result);
+    if (err != SI_OK || idx->config.keep_duplicates) return err;
+    
+    return spatial_result_dedupe(result);
+}
+
+int spatial_index_query_range(SpatialIndex *idx, const MBR *range,
                                SpatialQueryResult *result) {
+    return spatial_index_query_range_using(idx, range, SI_STRUCTURE_AUTO, result);
+}
+
+int spatial_index_query_point_using(SpatialIndex *idx, Point p,
+                                     SpatialStructure structure,
+                                     SpatialQueryResult *result) {
     if (!idx || !result) return SI_ERR_NULL_PTR;
     
     /* Create tiny MBR around point */
     MBR range = mbr_create(p.x, p.y, p.x, p.y);
     
-    return spatial_index_query_range(idx, &range, result);
+    return spatial_index_query_range_using(idx, &range, structure, result);
+}
+
+int spatial_index_query_point(SpatialIndex *idx, Point p,
+                               SpatialQueryResult *result) {
+    return spatial_index_query_point_using(idx, p, SI_STRUCTURE_AUTO, result);
 }
 
 int spatial_index_query_knn(SpatialIndex *idx, Point p, size_t k,
@@ -387,6 +827,7 @@ int spatial_index_query_knn(SpatialIndex *idx, Point p, size_t k,
     if (k == 0) return SI_OK;
     
     spatial_result_clear(result);
+    result->structure = SI_STRUCTURE_KDTREE;
     
     /* Use KD-tree for k-NN query */
     KDQueryResult kd_result;
@@ -399,10 +840,12 @@ int spatial_index_query_knn(SpatialIndex *idx, Point p, size_t k,
         return SI_ERR_NOT_FOUND;
     }
     
-    /* Convert results */
+    /* Convert results, recording the page holding each object */
     for (size_t i = 0; i < kd_result.count; i++) {
-        if (kd_result.data[i]) {
-            spatial_result_add(result, (SpatialObject *)kd_result.data[i]);
+        const BlockEntry *entry = (const BlockEntry *)kd_result.data[i];
+        if (entry) {
+            spatial_result_add(result, entry->object);
+            spatial_result_add_page(result, entry->page->header.page_id);
         }
     }
     
@@ -411,6 +854,56 @@ int spatial_index_query_knn(SpatialIndex *idx, Point p, size_t k,
     return SI_OK;
 }
 
+int spatial_index_nearest(SpatialIndex *idx, Point p, SpatialObject **nearest,
+                          double *distance) {
+    if (!idx || !nearest) return SI_ERR_NULL_PTR;
+    
+    Point centroid;
+    void *data = NULL;
+    if (kdtree_nearest(&idx->block_tree, p, &centroid, NULL, &data) != KD_OK || !data) {
+        return SI_ERR_NOT_FOUND;
+    }
+    
+    *nearest = ((BlockEntry *)data)->object;
+    if (distance) *distance = point_distance(&p, &centroid);
+    return SI_OK;
+}
+
+static int compare_version(const void *a, const void *b) {
+    const SpatialObject *oa = *(SpatialObject *const *)a;
+    const SpatialObject *ob = *(SpatialObject *const *)b;
+    return (oa->version > ob->version) - (oa->version < ob->version);
+}
+
+int spatial_index_query_changed_since(SpatialIndex *idx, int64_t since,
+                                       SpatialQueryResult *result) {
+    if (!idx || !result) return SI_ERR_NULL_PTR;
+    
+    spatial_result_clear(result);
+    result->structure = SI_STRUCTURE_SCAN;
+    
+    for (size_t i = 0; i < idx->disk.pool.page_count; i++) {
+        Page *page = idx->disk.pool.pages[i];
+        bool touched = false;
+        
+        for (size_t j = 0; j < page->header.object_count; j++) {
+            SpatialObject *obj = &page->objects[j];
+            if (obj->modified_at < since) continue;
+            
+            if (spatial_result_add(result, obj) != SI_OK) return SI_ERR_ALLOC;
+            touched = true;
+        }
+        
+        if (touched && spatial_result_add_page(result, page->header.page_id) != SI_OK) {
+            return SI_ERR_ALLOC;
+        }
+    }
+    
+    qsort(result->objects, result->count, sizeof(SpatialObject *), compare_version);
+    
+    return SI_OK;
+}
+
 int spatial_index_find_adjacent_pages(SpatialIndex *idx, const MBR *region,
                                        AdjacentPagesResult *result) {
     if (!idx || !region || !result) return SI_ERR_NULL_PTR;
@@ -470,6 +963,23 @@ int spatial_index_find_adjacent_pages(SpatialIndex *idx, const MBR *region,
     return SI_OK;
 }
 
+int spatial_index_insert_as(SpatialIndex *idx, SpatialObject *obj, uint64_t id) {
+    if (!idx || !obj) return SI_ERR_NULL_PTR;
+    if (id == 0) return SI_ERR_INVALID;
+    
+    if (spatial_index_get(idx, id)) return SI_ERR_EXISTS;
+    if (idx->config.dedup_points && obj->type == GEOM_POINT) {
+        /* Snap first so the lookup sees the point insert would store */
+        if (idx->config.snap_grid > 0) {
+            spatial_object_snap(obj, idx->config.snap_grid);
+        }
+        if (find_point(idx, &obj->geom.point)) return SI_ERR_EXISTS;
+    }
+    
+    obj->id = id;
+    return spatial_index_insert(idx, obj);
+}
+
 SpatialObject* spatial_index_get(SpatialIndex *idx, uint64_t object_id) {
     if (!idx) return NULL;
     
@@ -485,21 +995,71 @@ int spatial_index_update(SpatialIndex *idx, uint64_t object_id,
                           const SpatialObject *new_obj) {
     if (!idx || !new_obj) return SI_ERR_NULL_PTR;
     
-    /* Remove old object */
-    int err = spatial_index_remove(idx, object_id);
-    if (err != SI_OK) return err;
-    
-    /* Insert new object with same ID */
     SpatialObject obj_copy;
     spatial_object_copy(&obj_copy, new_obj);
     obj_copy.id = object_id;
+    obj_copy.version = 0;
+    
+    /* Refuse before removing, so the old object survives */
+    if (!within_valid_bounds(idx, &obj_copy)) {
+        spatial_object_free(&obj_copy);
+        idx->out_of_bounds++;
+        return SI_ERR_OUT_OF_BOUNDS;
+    }
     
+    /* Remove old object */
+    int err = spatial_index_remove(idx, object_id);
+    if (err != SI_OK) {
+        spatial_object_free(&obj_copy);
+        return err;
+    }
+    
+    /* Insert new object with same ID */
     err = spatial_index_insert(idx, &obj_copy);
     spatial_object_free(&obj_copy);
     
     return err;
 }
 
+int spatial_index_set_properties(SpatialIndex *idx, uint64_t object_id,
+                                  const void *data, size_t size) {
+    if (!idx || (!data && size > 0)) return SI_ERR_NULL_PTR;
+    
+    SpatialObject *obj = spatial_index_get(idx, object_id);
+    if (!obj) return SI_ERR_NOT_FOUND;
+    
+    if (spatial_object_set_properties(obj, data, size) != GEOM_OK) {
+        return SI_ERR_ALLOC;
+    }
+    stamp_modified(idx, obj);
+    
+    /* Geometry is unchanged, so the index stays built */
+    idx->disk.is_dirty = true;
+    
+    return SI_OK;
+}
+
+int spatial_index_set_zm(SpatialIndex *idx, uint64_t object_id, uint8_t dims,
+                          const double *zm, size_t count) {
+    if (!idx || (!zm && count > 0)) return SI_ERR_NULL_PTR;
+    
+    SpatialObject *obj = spatial_index_get(idx, object_id);
+    if (!obj) return SI_ERR_NOT_FOUND;
+    if (count != geom_dims_width(dims) * spatial_object_vertex_count(obj)) {
+        return SI_ERR_INVALID;
+    }
+    
+    if (spatial_object_set_zm(obj, dims, zm) != GEOM_OK) {
+        return SI_ERR_ALLOC;
+    }
+    stamp_modified(idx, obj);
+    
+    /* Z and M are not indexed, so the index stays built */
+    idx->disk.is_dirty = true;
+    
+    return SI_OK;
+}
+
 /* ============================================================================
  * Block Operations
  * ============================================================================ */
@@ -594,37 +1154,194 @@ void spatial_index_stats(const SpatialIndex *idx, SpatialIndexStats *stats) {
     }
     
     stats->bounds = idx->bounds;
+    stats->memory_bytes = spatial_index_memory(idx);
+    stats->disk_bytes = disk_manager_file_size(&idx->disk);
+    stats->build_threads = idx->build_threads_used;
+}
+
+size_t spatial_index_memory(const SpatialIndex *idx) {
+    if (!idx) return 0;
+    
+    size_t bytes = sizeof(SpatialIndex) + disk_manager_memory(&idx->disk) +
+                   kdtree_memory(&idx->block_tree) +
+                   idx->block_entry_count * sizeof(BlockEntry) +
+                   idx->block_capacity * sizeof(SpatialBlock);
+    if (idx->page_tree) bytes += sizeof(QuadTree) + quadtree_memory(idx->page_tree);
+    if (idx->config.data_path) bytes += strlen(idx->config.data_path) + 1;
+    
+    return bytes;
 }
 
 int spatial_index_optimize(SpatialIndex *idx) {
+    return spatial_index_optimize_cancellable(idx, NULL, NULL);
+}
+
+int spatial_index_optimize_cancellable(SpatialIndex *idx, BuildCancelFn should_cancel,
+                                       void *user_data) {
     if (!idx) return SI_ERR_NULL_PTR;
     
-    /* Rebuild index */
-    return spatial_index_build(idx);
+    /* Rebuild index, keeping the current build if cancelled */
+    return build_index(idx, NULL, should_cancel, user_data, true);
+}
+
+SpatialIndex* spatial_index_repage(const SpatialIndex *idx, size_t page_capacity) {
+    SpatialIndex *copy = NULL;
+    return spatial_index_repage_cancellable(idx, page_capacity, NULL, NULL, &copy) == SI_OK
+        ? copy : NULL;
+}
+
+int spatial_index_repage_cancellable(const SpatialIndex *idx, size_t page_capacity,
+                                     BuildCancelFn should_cancel, void *user_data,
+                                     SpatialIndex **out) {
+    if (!idx || !out) return SI_ERR_NULL_PTR;
+    *out = NULL;
+    
+    /* Objects are already snapped, simplified and deduplicated; skip all three on reinsert */
+    SpatialIndexConfig config = idx->config;
+    config.page_capacity = page_capacity;
+    config.data_path = NULL;
+    config.snap_grid = 0;
+    config.simplify_tolerance = 0;
+    config.dedup_points = false;
+    
+    SpatialIndex *copy = spatial_index_create(&config);
+    if (!copy) return SI_ERR_ALLOC;
+    
+    for (size_t i = 0; i < idx->disk.pool.page_count; i++) {
+        const Page *page = idx->disk.pool.pages[i];
+        for (size_t j = 0; j < page->header.object_count; j++) {
+            /* Insert works on a shallow copy; the page stores a deep copy */
+            SpatialObject obj = page->objects[j];
+            if (spatial_index_insert(copy, &obj) != SI_OK) {
+                spatial_index_destroy(copy);
+                return SI_ERR_ALLOC;
+            }
+        }
+        if (should_cancel && should_cancel(user_data)) {
+            spatial_index_destroy(copy);
+            return SI_ERR_CANCELLED;
+        }
+    }
+    
+    copy->config.snap_grid = idx->config.snap_grid;
+    copy->config.simplify_tolerance = idx->config.simplify_tolerance;
+    copy->config.dedup_points = idx->config.dedup_points;
+    copy->config.keep_duplicates = idx->config.keep_duplicates;
+    if (idx->config.data_path) {
+        copy->config.data_path = strdup(idx->config.data_path);
+    }
+    copy->next_object_id = idx->next_object_id;
+    copy->disk.header.next_object_id = idx->next_object_id;
+    copy->version_clock = idx->version_clock;
+    copy->change_count = idx->change_count;
+    
+    int err = disk_manager_set_settings(&copy->disk, idx->disk.settings, idx->disk.settings_size);
+    if (err != DM_OK) {
+        spatial_index_destroy(copy);
+        return SI_ERR_ALLOC;
+    }
+    
+    err = build_index(copy, NULL, should_cancel, user_data, false);
+    if (err != SI_OK) {
+        spatial_index_destroy(copy);
+        return err;
+    }
+    *out = copy;
+    return SI_OK;
+}
+
+int spatial_index_adopt_file(SpatialIndex *copy, SpatialIndex *idx) {
+    if (!copy || !idx) return SI_ERR_NULL_PTR;
+    if (!idx->disk.is_open) return SI_OK;
+    
+    char *path = strdup(idx->disk.file_path);
+    if (!path) return SI_ERR_ALLOC;
+    
+    /* Closing normally would write idx's pages over the copy's */
+    disk_manager_detach(&idx->disk);
+    int err = spatial_index_save(copy, path);
+    if (err != SI_OK) {
+        disk_manager_detach(&copy->disk);
+        spatial_index_save(idx, path);
+    }
+    free(path);
+    return err;
 }
 
 int spatial_index_save(SpatialIndex *idx, const char *path) {
     if (!idx || !path) return SI_ERR_NULL_PTR;
     
-    /* Create or open data file */
-    int err = disk_manager_create(&idx->disk, path);
-    if (err != DM_OK) return SI_ERR_IO;
+    /* The file is written beside path and replaces it only once complete,
+     * so a failed save leaves the previous file whole */
+    size_t len = strlen(path);
+    char *tmp = (char *)malloc(len + sizeof(".tmp"));
+    if (!tmp) return SI_ERR_ALLOC;
+    memcpy(tmp, path, len);
+    memcpy(tmp + len, ".tmp", sizeof(".tmp"));
+    
+    /* Closing normally would first write pending pages into the file
+     * being replaced, in place */
+    if (idx->disk.is_open && strcmp(idx->disk.file_path, path) == 0) {
+        disk_manager_detach(&idx->disk);
+    }
     
-    /* Sync all data */
-    err = disk_manager_sync(&idx->disk);
-    if (err != DM_OK) return SI_ERR_IO;
+    int err = disk_manager_create(&idx->disk, tmp);
+    if (err == DM_OK) {
+        idx->disk.header.next_object_id = idx->next_object_id;
+        idx->disk.header.bounded = idx->config.bounded;
+        idx->disk.header.valid_bounds = idx->config.valid_bounds;
+        
+        /* The new file holds no pages yet, including those an earlier
+         * save wrote and left clean */
+        for (size_t i = 0; i < idx->disk.pool.page_count; i++) {
+            idx->disk.pool.pages[i]->header.flags |= PAGE_STATUS_DIRTY;
+        }
+        err = disk_manager_sync(&idx->disk);
+    }
+    if (err == DM_OK) {
+        err = disk_manager_rename(&idx->disk, path);
+    }
+    if (err != DM_OK) {
+        disk_manager_detach(&idx->disk);
+        remove(tmp);
+    }
+    free(tmp);
     
-    return SI_OK;
+    if (err == DM_ERR_ALLOC) return SI_ERR_ALLOC;
+    return (err == DM_OK) ? SI_OK : SI_ERR_IO;
 }
 
 int spatial_index_load(SpatialIndex *idx, const char *path) {
     if (!idx || !path) return SI_ERR_NULL_PTR;
     
     int err = disk_manager_open(&idx->disk, path);
+    if (err == DM_ERR_VERSION) return SI_ERR_VERSION;
     if (err != DM_OK) return SI_ERR_IO;
     
-    /* Rebuild index structures */
-    idx->bounds = idx->disk.header.bounds;
+    /* Rebuild index structures. The header bounds only cover page
+     * centroids, so the bounds come from the objects themselves. */
+    idx->bounds = mbr_empty();
+    if (idx->disk.header.bounded) {
+        idx->config.bounded = true;
+        idx->config.valid_bounds = idx->disk.header.valid_bounds;
+    }
+    
+    /* New objects must not reuse the IDs of loaded ones, nor those of
+     * objects removed before the save; files written before the counter
+     * was recorded leave it 0 */
+    if (idx->disk.header.next_object_id > idx->next_object_id) {
+        idx->next_object_id = idx->disk.header.next_object_id;
+    }
+    for (size_t i = 0; i < idx->disk.pool.page_count; i++) {
+        const Page *page = idx->disk.pool.pages[i];
+        for (size_t j = 0; j < page->header.object_count; j++) {
+            mbr_expand_mbr(&idx->bounds, &page->objects[j].mbr);
+            uint64_t id = page->objects[j].id;
+            if (id >= idx->next_object_id && id < UINT64_MAX) {
+                idx->next_object_id = id + 1;
+            }
+        }
+    }
     
     err = spatial_index_build(idx);
     if (err != SI_OK) return err;
@@ -637,6 +1354,9 @@ void spatial_index_clear(SpatialIndex *idx) {
     
     kdtree_free(&idx->block_tree);
     kdtree_init(&idx->block_tree);
+    free(idx->block_entries);
+    idx->block_entries = NULL;
+    idx->block_entry_count = 0;
     
     if (idx->page_tree) {
         quadtree_clear(idx->page_tree);
@@ -659,6 +1379,7 @@ void spatial_index_clear(SpatialIndex *idx) {
     
     idx->bounds = mbr_empty();
     idx->is_built = false;
+    idx->changes_since_build = 0;
 }
 
 void spatial_index_print_stats(const SpatialIndex *idx, FILE *out) {
@@ -694,8 +1415,9 @@ int spatial_result_init(SpatialQueryResult *result, size_t capacity) {
     memset(result, 0, sizeof(SpatialQueryResult));
     
     result->capacity = capacity > 0 ? capacity : 64;
+    result->page_capacity = result->capacity;
     result->objects = (SpatialObject **)malloc(result->capacity * sizeof(SpatialObject *));
-    result->page_ids = (uint32_t *)malloc(result->capacity * sizeof(uint32_t));
+    result->page_ids = (uint32_t *)malloc(result->page_capacity * sizeof(uint32_t));
     
     if (!result->objects || !result->page_ids) {
         free(result->objects);
@@ -710,6 +1432,7 @@ void spatial_result_free(SpatialQueryResult *result) {
     if (!result) return;
     free(result->objects);
     free(result->page_ids);
+    free(result->failed_page_ids);
     memset(result, 0, sizeof(SpatialQueryResult));
 }
 
@@ -717,6 +1440,9 @@ void spatial_result_clear(SpatialQueryResult *result) {
     if (!result) return;
     result->count = 0;
     result->pages_accessed = 0;
+    result->pages_failed = 0;
+    result->duplicates = 0;
+    result->structure = SI_STRUCTURE_AUTO;
 }
 
 int spatial_result_add(SpatialQueryResult *result, SpatialObject *obj) {
@@ -726,13 +1452,9 @@ int spatial_result_add(SpatialQueryResult *result, SpatialObject *obj) {
         size_t new_cap = result->capacity * GROWTH_FACTOR;
         SpatialObject **new_objs = (SpatialObject **)realloc(result->objects,
                                                               new_cap * sizeof(SpatialObject *));
-        uint32_t *new_ids = (uint32_t *)realloc(result->page_ids,
-                                                 new_cap * sizeof(uint32_t));
-        
-        if (!new_objs || !new_ids) return SI_ERR_ALLOC;
+        if (!new_objs) return SI_ERR_ALLOC;
         
         result->objects = new_objs;
-        result->page_ids = new_ids;
         result->capacity = new_cap;
     }
     
@@ -741,6 +1463,64 @@ int spatial_result_add(SpatialQueryResult *result, SpatialObject *obj) {
     return SI_OK;
 }
 
+int spatial_result_add_page(SpatialQueryResult *result, uint32_t page_id) {
+    if (!result) return SI_ERR_NULL_PTR;
+    
+    for (size_t i = 0; i < result->pages_accessed; i++) {
+        if (result->page_ids[i] == page_id) return SI_OK;
+    }
+    
+    if (result->pages_accessed >= result->page_capacity) {
+        size_t new_cap = result->page_capacity > 0 ? result->page_capacity * GROWTH_FACTOR : 16;
+        uint32_t *new_ids = (uint32_t *)realloc(result->page_ids,
+                                                 new_cap * sizeof(uint32_t));
+        if (!new_ids) return SI_ERR_ALLOC;
+        
+        result->page_ids = new_ids;
+        result->page_capacity = new_cap;
+    }
+    
+    result->page_ids[result->pages_accessed++] = page_id;
+    
+    return SI_OK;
+}
+
+int spatial_result_dedupe(SpatialQueryResult *result) {
+    if (!result) return SI_ERR_NULL_PTR;
+    if (result->count < 2) return SI_OK;
+    
+    /* Open-addressed seen-set of IDs, at most half full */
+    size_t slots = 4;
+    while (slots < result->count * 2) slots *= 2;
+    uint64_t *ids = (uint64_t *)malloc(slots * sizeof(uint64_t));
+    bool *used = (bool *)calloc(slots, sizeof(bool));
+    if (!ids || !used) {
+        free(ids);
+        free(used);
+        return SI_ERR_ALLOC;
+    }
+    
+    size_t kept = 0;
+    for (size_t i = 0; i < result->count; i++) {
+        uint64_t id = result->objects[i]->id;
+        size_t slot = (size_t)((id * 0x9E3779B97F4A7C15ULL) >> 32) & (slots - 1);
+        while (used[slot] && ids[slot] != id) slot = (slot + 1) & (slots - 1);
+        
+        if (used[slot]) {
+            result->duplicates++;
+            continue;
+        }
+        used[slot] = true;
+        ids[slot] = id;
+        result->objects[kept++] = result->objects[i];
+    }
+    result->count = kept;
+    
+    free(ids);
+    free(used);
+    return SI_OK;
+}
+
 /* ============================================================================
  * Adjacent Pages Result Operations
  * ============================================================================ */
@@ -4,9 +4,73 @@
  */
 
 #include "urbis.h"
+#include <math.h>
 #include <stdlib.h>
 #include <string.h>
 
+/* ============================================================================
+ * Internal Helpers
+ * ============================================================================ */
+
+/**
+ * @brief Compute query statistics from the pages a query visited
+ */
+static void collect_query_stats(UrbisIndex *idx, const uint32_t *page_ids,
+                                size_t count, UrbisQueryStats *stats) {
+    memset(stats, 0, sizeof(UrbisQueryStats));
+    if (count == 0) return;
+    
+    stats->pages_visited = count;
+    stats->estimated_seeks = disk_manager_estimate_seeks(&idx->disk, page_ids, count);
+    
+    for (size_t i = 0; i < count; i++) {
+        Page *page = page_pool_get(&idx->disk.pool, page_ids[i]);
+        if (!page) continue;
+        
+        if (page->in_memory && page_cache_contains(&idx->disk.cache, page_ids[i])) {
+            stats->cache_hits++;
+        } else {
+            stats->cache_misses++;
+        }
+        
+        /* Count the track only on its first occurrence */
+        bool seen = false;
+        for (size_t j = 0; j < i && !seen; j++) {
+            Page *prev = page_pool_get(&idx->disk.pool, page_ids[j]);
+            seen = prev && prev->header.track_id == page->header.track_id;
+        }
+        if (!seen) stats->tracks_visited++;
+    }
+}
+
+/**
+ * @brief Hand a query's visited pages to its list, with the track of each
+ *
+ * Takes ownership of page_ids. The list is left without pages if the
+ * track array cannot be allocated.
+ */
+static void record_visited_pages(UrbisIndex *idx, uint32_t *page_ids,
+                                 size_t count, UrbisObjectList *list) {
+    if (count == 0) {
+        free(page_ids);
+        return;
+    }
+    
+    uint32_t *tracks = (uint32_t *)malloc(count * sizeof(uint32_t));
+    if (!tracks) {
+        free(page_ids);
+        return;
+    }
+    for (size_t i = 0; i < count; i++) {
+        Page *page = page_pool_get(&idx->disk.pool, page_ids[i]);
+        tracks[i] = page ? page->header.track_id : 0;
+    }
+    
+    list->visited_pages = page_ids;
+    list->visited_tracks = tracks;
+    list->visited_count = count;
+}
+
 /* ============================================================================
  * Initialization and Cleanup
  * ============================================================================ */
@@ -18,7 +82,11 @@ UrbisConfig urbis_default_config(void) {
         .cache_size = DM_DEFAULT_CACHE_SIZE,
         .enable_quadtree = true,
         .persist = false,
-        .data_path = NULL
+        .data_path = NULL,
+        .snap_grid = 0,
+        .simplify_tolerance = 0,
+        .dedup_points = false,
+        .build_threads = 1
     };
     return config;
 }
@@ -32,6 +100,43 @@ UrbisIndex* urbis_create(const UrbisConfig *config) {
         si_config.cache_size = config->cache_size;
         si_config.build_quadtree = config->enable_quadtree;
         si_config.persist = config->persist;
+        si_config.snap_grid = config->snap_grid;
+        si_config.simplify_tolerance = config->simplify_tolerance;
+        si_config.dedup_points = config->dedup_points;
+        si_config.build_threads = config->build_threads;
+        si_config.seed = config->seed;
+        si_config.keep_duplicates = config->keep_duplicates;
+        
+        if (config->valid_bounds) {
+            const MBR *b = config->valid_bounds;
+            if (!isfinite(b->min_x) || !isfinite(b->min_y) || !isfinite(b->max_x) ||
+                !isfinite(b->max_y) || b->min_x > b->max_x || b->min_y > b->max_y) {
+                return NULL;
+            }
+            si_config.bounded = true;
+            si_config.valid_bounds = *b;
+        }
+        
+        const UrbisSeekCostModel *cost = &config->seek_cost;
+        switch (cost->kind) {
+            case URBIS_STORAGE_ROTATIONAL:
+                break;
+            case URBIS_STORAGE_SSD:
+                si_config.seek_ms = SI_SSD_SEEK_MS;
+                si_config.transfer_mb_s = SI_SSD_TRANSFER_MB_S;
+                break;
+            case URBIS_STORAGE_CUSTOM:
+                if (!(cost->seek_ms >= 0) || !isfinite(cost->seek_ms) ||
+                    !(cost->transfer_mb_s > 0) || !isfinite(cost->transfer_mb_s)) {
+                    return NULL;
+                }
+                si_config.seek_ms = cost->seek_ms;
+                si_config.transfer_mb_s = cost->transfer_mb_s;
+                break;
+            default:
+                return NULL;
+        }
+        
         if (config->data_path) {
             si_config.data_path = strdup(config->data_path);
         }
@@ -52,43 +157,85 @@ const char* urbis_version(void) {
  * Data Loading
  * ============================================================================ */
 
+/**
+ * @brief Map a failed spatial_index_insert to an Urbis error code
+ */
+static int insert_error(int err) {
+    return (err == SI_ERR_INVALID || err == SI_ERR_OUT_OF_BOUNDS) ? URBIS_ERR_INVALID : URBIS_ERR_ALLOC;
+}
+
+/**
+ * @brief Insert every parsed feature, then free the collection. Features
+ * outside the valid bounds are left out; see urbis_out_of_bounds.
+ */
+static int insert_features(UrbisIndex *idx, FeatureCollection *fc, size_t *skipped) {
+    if (skipped) *skipped = fc->skipped;
+    
+    for (size_t i = 0; i < fc->count; i++) {
+        int err = spatial_index_insert(idx, &fc->features[i].object);
+        if (err != SI_OK && err != SI_ERR_OUT_OF_BOUNDS) {
+            feature_collection_free(fc);
+            return insert_error(err);
+        }
+    }
+    
+    feature_collection_free(fc);
+    return URBIS_OK;
+}
+
 int urbis_load_geojson(UrbisIndex *idx, const char *path) {
+    return urbis_load_geojson_counting(idx, path, NULL);
+}
+
+int urbis_load_geojson_counting(UrbisIndex *idx, const char *path, size_t *skipped) {
     if (!idx || !path) return URBIS_ERR_NULL;
     
     FeatureCollection fc;
     int err = geojson_parse_file(path, &fc);
     if (err != PARSE_OK) return URBIS_ERR_PARSE;
     
-    /* Insert all features */
-    for (size_t i = 0; i < fc.count; i++) {
-        err = spatial_index_insert(idx, &fc.features[i].object);
-        if (err != SI_OK) {
-            feature_collection_free(&fc);
-            return URBIS_ERR_ALLOC;
-        }
-    }
+    return insert_features(idx, &fc, skipped);
+}
+
+int urbis_check_geojson_string(const char *json, UrbisGeoJSONCheck *check) {
+    if (!json || !check) return URBIS_ERR_NULL;
+    
+    memset(check, 0, sizeof(UrbisGeoJSONCheck));
+    
+    FeatureCollection fc;
+    int err = geojson_parse_string(json, &fc);
+    if (err != PARSE_OK) return URBIS_ERR_PARSE;
     
+    check->valid = fc.count;
+    check->skipped = fc.skipped;
+    check->errors = fc.errors;
+    check->error_count = fc.error_count;
+    
+    fc.errors = NULL;
     feature_collection_free(&fc);
     return URBIS_OK;
 }
 
+void urbis_geojson_check_free(UrbisGeoJSONCheck *check) {
+    if (!check) return;
+    
+    free(check->errors);
+    memset(check, 0, sizeof(UrbisGeoJSONCheck));
+}
+
 int urbis_load_geojson_string(UrbisIndex *idx, const char *json) {
+    return urbis_load_geojson_string_counting(idx, json, NULL);
+}
+
+int urbis_load_geojson_string_counting(UrbisIndex *idx, const char *json,
+                                       size_t *skipped) {
     if (!idx || !json) return URBIS_ERR_NULL;
     
     FeatureCollection fc;
     int err = geojson_parse_string(json, &fc);
     if (err != PARSE_OK) return URBIS_ERR_PARSE;
     
-    for (size_t i = 0; i < fc.count; i++) {
-        err = spatial_index_insert(idx, &fc.features[i].object);
-        if (err != SI_OK) {
-            feature_collection_free(&fc);
-            return URBIS_ERR_ALLOC;
-        }
-    }
-    
-    feature_collection_free(&fc);
-    return URBIS_OK;
+    return insert_features(idx, &fc, skipped);
 }
 
 int urbis_load_wkt(UrbisIndex *idx, const char *wkt) {
@@ -101,7 +248,52 @@ int urbis_load_wkt(UrbisIndex *idx, const char *wkt) {
     err = spatial_index_insert(idx, &obj);
     spatial_object_free(&obj);
     
-    return (err == SI_OK) ? URBIS_OK : URBIS_ERR_ALLOC;
+    return (err == SI_OK) ? URBIS_OK : insert_error(err);
+}
+
+int32_t urbis_wkt_srid(const char *wkt) {
+    int32_t srid;
+    return wkt_srid(wkt, &srid) ? srid : -1;
+}
+
+int urbis_load_wkb(UrbisIndex *idx, const uint8_t *data, size_t size) {
+    if (!idx || !data) return URBIS_ERR_NULL;
+    if (size == 0) return URBIS_ERR_PARSE;
+    
+    size_t offset = 0;
+    while (offset < size) {
+        SpatialObject obj;
+        size_t consumed = 0;
+        int err = wkb_parse(data + offset, size - offset, &obj, &consumed);
+        if (err != PARSE_OK) return URBIS_ERR_PARSE;
+        
+        err = spatial_index_insert(idx, &obj);
+        spatial_object_free(&obj);
+        if (err != SI_OK) return insert_error(err);
+        
+        offset += consumed;
+    }
+    
+    return URBIS_OK;
+}
+
+int urbis_export_wkb(UrbisIndex *idx, uint64_t object_id, uint8_t *buffer, size_t buffer_size) {
+    if (!idx) return URBIS_ERR_NULL;
+    
+    SpatialObject *obj = spatial_index_get(idx, object_id);
+    if (!obj) return URBIS_ERR_NOT_FOUND;
+    
+    return wkb_export(obj, buffer, buffer_size);
+}
+
+int urbis_export_geojson(UrbisIndex *idx, uint64_t object_id, char *buffer, size_t buffer_size) {
+    if (!idx) return URBIS_ERR_NULL;
+    
+    SpatialObject *obj = spatial_index_get(idx, object_id);
+    if (!obj) return URBIS_ERR_NOT_FOUND;
+    
+    int len = geojson_export(obj, buffer, buffer_size);
+    return len < 0 ? URBIS_ERR_FULL : len;
 }
 
 /* ============================================================================
@@ -114,6 +306,9 @@ uint64_t urbis_insert(UrbisIndex *idx, const SpatialObject *obj) {
     SpatialObject copy;
     if (spatial_object_copy(&copy, obj) != GEOM_OK) return 0;
     
+    /* A stamp copied from another index means nothing here */
+    copy.version = 0;
+    
     int err = spatial_index_insert(idx, &copy);
     if (err != SI_OK) {
         spatial_object_free(&copy);
@@ -196,6 +391,162 @@ uint64_t urbis_insert_polygon(UrbisIndex *idx, const Point *exterior, size_t cou
     return id;
 }
 
+/**
+ * @brief Insert a fully built object under id, taking ownership of it
+ */
+static int insert_as(UrbisIndex *idx, uint64_t id, SpatialObject *obj) {
+    int err = spatial_index_insert_as(idx, obj, id);
+    spatial_object_free(obj);
+    
+    switch (err) {
+        case SI_OK:         return URBIS_OK;
+        case SI_ERR_EXISTS: return URBIS_ERR_EXISTS;
+        default:            return insert_error(err);
+    }
+}
+
+int urbis_insert_point_id(UrbisIndex *idx, uint64_t id, double x, double y) {
+    if (!idx) return URBIS_ERR_NULL;
+    
+    SpatialObject obj;
+    if (spatial_object_init_point(&obj, 0, point_create(x, y)) != GEOM_OK) {
+        return URBIS_ERR_ALLOC;
+    }
+    return insert_as(idx, id, &obj);
+}
+
+int urbis_insert_linestring_id(UrbisIndex *idx, uint64_t id,
+                               const Point *points, size_t count) {
+    if (!idx || !points) return URBIS_ERR_NULL;
+    if (count < 2) return URBIS_ERR_INVALID;
+    
+    SpatialObject obj;
+    if (spatial_object_init_linestring(&obj, 0, count) != GEOM_OK) {
+        return URBIS_ERR_ALLOC;
+    }
+    for (size_t i = 0; i < count; i++) {
+        linestring_add_point(&obj.geom.line, points[i]);
+    }
+    spatial_object_update_derived(&obj);
+    
+    return insert_as(idx, id, &obj);
+}
+
+int urbis_insert_polygon_id(UrbisIndex *idx, uint64_t id,
+                            const Point *exterior, size_t count) {
+    if (!idx || !exterior) return URBIS_ERR_NULL;
+    if (count < 3) return URBIS_ERR_INVALID;
+    
+    SpatialObject obj;
+    if (spatial_object_init_polygon(&obj, 0, count) != GEOM_OK) {
+        return URBIS_ERR_ALLOC;
+    }
+    for (size_t i = 0; i < count; i++) {
+        polygon_add_exterior_point(&obj.geom.polygon, exterior[i]);
+    }
+    spatial_object_update_derived(&obj);
+    
+    return insert_as(idx, id, &obj);
+}
+
+uint64_t urbis_next_id(const UrbisIndex *idx) {
+    return idx ? idx->next_object_id : 0;
+}
+
+/**
+ * @brief Insert a fully built object, taking ownership of it
+ */
+static uint64_t insert_owned(UrbisIndex *idx, SpatialObject *obj) {
+    if (spatial_object_update_derived(obj) != GEOM_OK) {
+        spatial_object_free(obj);
+        return 0;
+    }
+    
+    int err = spatial_index_insert(idx, obj);
+    uint64_t id = obj->id;
+    
+    spatial_object_free(obj);
+    
+    return (err == SI_OK) ? id : 0;
+}
+
+uint64_t urbis_insert_multipoint(UrbisIndex *idx, const Point *points, size_t count) {
+    if (!idx || !points || count == 0) return 0;
+    
+    SpatialObject obj;
+    if (spatial_object_init_multipoint(&obj, 0, count) != GEOM_OK) {
+        return 0;
+    }
+    
+    for (size_t i = 0; i < count; i++) {
+        multipoint_add_point(&obj.geom.multi_point, points[i]);
+    }
+    
+    return insert_owned(idx, &obj);
+}
+
+uint64_t urbis_insert_multilinestring(UrbisIndex *idx, const Point *points,
+                                      const size_t *counts, size_t num_parts) {
+    if (!idx || !points || !counts || num_parts == 0) return 0;
+    
+    SpatialObject obj;
+    if (spatial_object_init_multilinestring(&obj, 0, num_parts) != GEOM_OK) {
+        return 0;
+    }
+    
+    size_t offset = 0;
+    for (size_t i = 0; i < num_parts; i++) {
+        if (counts[i] < 2) {
+            spatial_object_free(&obj);
+            return 0;
+        }
+        
+        int part = multilinestring_add_line(&obj.geom.multi_line, counts[i]);
+        if (part < 0) {
+            spatial_object_free(&obj);
+            return 0;
+        }
+        
+        for (size_t j = 0; j < counts[i]; j++) {
+            linestring_add_point(&obj.geom.multi_line.lines[part], points[offset + j]);
+        }
+        offset += counts[i];
+    }
+    
+    return insert_owned(idx, &obj);
+}
+
+uint64_t urbis_insert_multipolygon(UrbisIndex *idx, const Point *points,
+                                   const size_t *counts, size_t num_parts) {
+    if (!idx || !points || !counts || num_parts == 0) return 0;
+    
+    SpatialObject obj;
+    if (spatial_object_init_multipolygon(&obj, 0, num_parts) != GEOM_OK) {
+        return 0;
+    }
+    
+    size_t offset = 0;
+    for (size_t i = 0; i < num_parts; i++) {
+        if (counts[i] < 3) {
+            spatial_object_free(&obj);
+            return 0;
+        }
+        
+        int part = multipolygon_add_polygon(&obj.geom.multi_polygon, counts[i]);
+        if (part < 0) {
+            spatial_object_free(&obj);
+            return 0;
+        }
+        
+        for (size_t j = 0; j < counts[i]; j++) {
+            polygon_add_exterior_point(&obj.geom.multi_polygon.polygons[part], points[offset + j]);
+        }
+        offset += counts[i];
+    }
+    
+    return insert_owned(idx, &obj);
+}
+
 int urbis_remove(UrbisIndex *idx, uint64_t object_id) {
     if (!idx) return URBIS_ERR_NULL;
     
@@ -203,11 +554,85 @@ int urbis_remove(UrbisIndex *idx, uint64_t object_id) {
     return (err == SI_OK) ? URBIS_OK : URBIS_ERR_NOT_FOUND;
 }
 
+int urbis_remove_range(UrbisIndex *idx, const MBR *region, SpatialMatch match,
+                       size_t *removed) {
+    if (!idx || !region || !removed) return URBIS_ERR_NULL;
+    
+    int err = spatial_index_remove_range(idx, region, match, removed);
+    return (err == SI_OK) ? URBIS_OK : URBIS_ERR_INVALID;
+}
+
 SpatialObject* urbis_get(UrbisIndex *idx, uint64_t object_id) {
     if (!idx) return NULL;
     return spatial_index_get(idx, object_id);
 }
 
+int urbis_set_properties(UrbisIndex *idx, uint64_t object_id,
+                         const void *data, size_t size) {
+    if (!idx) return URBIS_ERR_NULL;
+    
+    switch (spatial_index_set_properties(idx, object_id, data, size)) {
+        case SI_OK:            return URBIS_OK;
+        case SI_ERR_NOT_FOUND: return URBIS_ERR_NOT_FOUND;
+        case SI_ERR_NULL_PTR:  return URBIS_ERR_NULL;
+        default:               return URBIS_ERR_ALLOC;
+    }
+}
+
+int urbis_set_zm(UrbisIndex *idx, uint64_t object_id, uint8_t dims,
+                 const double *zm, size_t count) {
+    if (!idx) return URBIS_ERR_NULL;
+    
+    switch (spatial_index_set_zm(idx, object_id, dims, zm, count)) {
+        case SI_OK:            return URBIS_OK;
+        case SI_ERR_NOT_FOUND: return URBIS_ERR_NOT_FOUND;
+        case SI_ERR_INVALID:   return URBIS_ERR_INVALID;
+        case SI_ERR_NULL_PTR:  return URBIS_ERR_NULL;
+        default:               return URBIS_ERR_ALLOC;
+    }
+}
+
+size_t urbis_stored_vertex_count(UrbisIndex *idx, bool polygon,
+                                 const Point *points, size_t count) {
+    if (!idx || !points || count == 0) return 0;
+    
+    SpatialObject obj;
+    int err = polygon ? spatial_object_init_polygon(&obj, 0, count)
+                      : spatial_object_init_linestring(&obj, 0, count);
+    if (err != GEOM_OK) return 0;
+    
+    for (size_t i = 0; i < count; i++) {
+        err = polygon ? polygon_add_exterior_point(&obj.geom.polygon, points[i])
+                      : linestring_add_point(&obj.geom.line, points[i]);
+        if (err != GEOM_OK) {
+            spatial_object_free(&obj);
+            return 0;
+        }
+    }
+    
+    /* The same steps, in the same order, as spatial_index_insert */
+    if (idx->config.snap_grid > 0) {
+        spatial_object_snap(&obj, idx->config.snap_grid);
+    }
+    if (idx->config.simplify_tolerance > 0) {
+        spatial_object_simplify(&obj, idx->config.simplify_tolerance);
+    }
+    
+    size_t n = spatial_object_vertex_count(&obj);
+    spatial_object_free(&obj);
+    return n;
+}
+
+const void* urbis_get_properties(UrbisIndex *idx, uint64_t object_id, size_t *size) {
+    if (size) *size = 0;
+    
+    SpatialObject *obj = urbis_get(idx, object_id);
+    if (!obj || !obj->properties) return NULL;
+    
+    if (size) *size = obj->properties_size;
+    return obj->properties;
+}
+
 /* ============================================================================
  * Index Building
  * ============================================================================ */
@@ -219,6 +644,24 @@ int urbis_build(UrbisIndex *idx) {
     return (err == SI_OK) ? URBIS_OK : URBIS_ERR_ALLOC;
 }
 
+int urbis_build_progress(UrbisIndex *idx, BuildProgressFn on_progress, void *user_data) {
+    if (!idx) return URBIS_ERR_NULL;
+    
+    int err = spatial_index_build_progress(idx, on_progress, user_data);
+    return (err == SI_OK) ? URBIS_OK : URBIS_ERR_ALLOC;
+}
+
+int urbis_build_cancellable(UrbisIndex *idx, BuildProgressFn on_progress,
+                            BuildCancelFn should_cancel, void *user_data) {
+    if (!idx) return URBIS_ERR_NULL;
+    
+    switch (spatial_index_build_cancellable(idx, on_progress, should_cancel, user_data)) {
+        case SI_OK:            return URBIS_OK;
+        case SI_ERR_CANCELLED: return URBIS_ERR_CANCELLED;
+        default:               return URBIS_ERR_ALLOC;
+    }
+}
+
 int urbis_optimize(UrbisIndex *idx) {
     if (!idx) return URBIS_ERR_NULL;
     
@@ -226,11 +669,35 @@ int urbis_optimize(UrbisIndex *idx) {
     return (err == SI_OK) ? URBIS_OK : URBIS_ERR_ALLOC;
 }
 
+int urbis_optimize_cancellable(UrbisIndex *idx, BuildCancelFn should_cancel, void *user_data) {
+    if (!idx) return URBIS_ERR_NULL;
+    
+    switch (spatial_index_optimize_cancellable(idx, should_cancel, user_data)) {
+        case SI_OK:            return URBIS_OK;
+        case SI_ERR_CANCELLED: return URBIS_ERR_CANCELLED;
+        default:               return URBIS_ERR_ALLOC;
+    }
+}
+
 /* ============================================================================
  * Spatial Queries
  * ============================================================================ */
 
+/**
+ * @brief Record which structure answered a query and whether a hint was ignored
+ */
+static void record_structure(UrbisQueryStats *stats, SpatialStructure requested,
+                             SpatialStructure used) {
+    stats->structure = used;
+    stats->structure_fallback = requested != SI_STRUCTURE_AUTO && requested != used;
+}
+
 UrbisObjectList* urbis_query_range(UrbisIndex *idx, const MBR *range) {
+    return urbis_query_range_using(idx, range, SI_STRUCTURE_AUTO);
+}
+
+UrbisObjectList* urbis_query_range_using(UrbisIndex *idx, const MBR *range,
+                                         SpatialStructure structure) {
     if (!idx || !range) return NULL;
     
     UrbisObjectList *list = (UrbisObjectList *)calloc(1, sizeof(UrbisObjectList));
@@ -242,7 +709,7 @@ UrbisObjectList* urbis_query_range(UrbisIndex *idx, const MBR *range) {
         return NULL;
     }
     
-    int err = spatial_index_query_range(idx, range, &result);
+    int err = spatial_index_query_range_using(idx, range, structure, &result);
     if (err != SI_OK) {
         spatial_result_free(&result);
         free(list);
@@ -251,14 +718,24 @@ UrbisObjectList* urbis_query_range(UrbisIndex *idx, const MBR *range) {
     
     list->objects = result.objects;
     list->count = result.count;
+    list->failed_pages = result.failed_page_ids;
+    list->failed_count = result.pages_failed;
+    collect_query_stats(idx, result.page_ids, result.pages_accessed, &list->stats);
+    record_structure(&list->stats, structure, result.structure);
+    list->stats.duplicates_suppressed = result.duplicates;
     
     /* Don't free result.objects since we're transferring ownership */
-    free(result.page_ids);
+    record_visited_pages(idx, result.page_ids, result.pages_accessed, list);
     
     return list;
 }
 
 UrbisObjectList* urbis_query_point(UrbisIndex *idx, double x, double y) {
+    return urbis_query_point_using(idx, x, y, SI_STRUCTURE_AUTO);
+}
+
+UrbisObjectList* urbis_query_point_using(UrbisIndex *idx, double x, double y,
+                                         SpatialStructure structure) {
     if (!idx) return NULL;
     
     UrbisObjectList *list = (UrbisObjectList *)calloc(1, sizeof(UrbisObjectList));
@@ -271,7 +748,7 @@ UrbisObjectList* urbis_query_point(UrbisIndex *idx, double x, double y) {
     }
     
     Point p = point_create(x, y);
-    int err = spatial_index_query_point(idx, p, &result);
+    int err = spatial_index_query_point_using(idx, p, structure, &result);
     if (err != SI_OK) {
         spatial_result_free(&result);
         free(list);
@@ -280,8 +757,66 @@ UrbisObjectList* urbis_query_point(UrbisIndex *idx, double x, double y) {
     
     list->objects = result.objects;
     list->count = result.count;
+    list->failed_pages = result.failed_page_ids;
+    list->failed_count = result.pages_failed;
+    collect_query_stats(idx, result.page_ids, result.pages_accessed, &list->stats);
+    record_structure(&list->stats, structure, result.structure);
+    list->stats.duplicates_suppressed = result.duplicates;
     
-    free(result.page_ids);
+    record_visited_pages(idx, result.page_ids, result.pages_accessed, list);
+    
+    return list;
+}
+
+UrbisObjectList* urbis_query_containing(UrbisIndex *idx, double x, double y) {
+    return urbis_query_containing_using(idx, x, y, SI_STRUCTURE_AUTO);
+}
+
+UrbisObjectList* urbis_query_containing_using(UrbisIndex *idx, double x, double y,
+                                              SpatialStructure structure) {
+    UrbisObjectList *list = urbis_query_point_using(idx, x, y, structure);
+    if (!list) return NULL;
+    
+    /* Refine MBR candidates with exact point-in-polygon tests */
+    Point p = point_create(x, y);
+    size_t kept = 0;
+    for (size_t i = 0; i < list->count; i++) {
+        if (spatial_object_contains_point(list->objects[i], &p)) {
+            list->objects[kept++] = list->objects[i];
+        }
+    }
+    list->count = kept;
+    
+    return list;
+}
+
+UrbisObjectList* urbis_query_containing_polygon(UrbisIndex *idx, const Point *ring,
+                                                size_t count) {
+    if (!idx || !ring || count < 3) return NULL;
+    
+    MBR bounds = mbr_empty();
+    for (size_t i = 0; i < count; i++) {
+        mbr_expand_point(&bounds, &ring[i]);
+    }
+    
+    UrbisObjectList *list = urbis_query_range_using(idx, &bounds, SI_STRUCTURE_AUTO);
+    if (!list) return NULL;
+    
+    /* Only objects whose MBR holds the ring's can contain it; refine those
+     * with exact geometry */
+    size_t kept = 0;
+    for (size_t i = 0; i < list->count; i++) {
+        SpatialObject *obj = list->objects[i];
+        if (!mbr_contains_mbr(&obj->mbr, &bounds)) continue;
+        
+        int covers = spatial_object_covers_ring(obj, ring, count);
+        if (covers < 0) {
+            urbis_object_list_free(list);
+            return NULL;
+        }
+        if (covers) list->objects[kept++] = obj;
+    }
+    list->count = kept;
     
     return list;
 }
@@ -308,12 +843,175 @@ UrbisObjectList* urbis_query_knn(UrbisIndex *idx, double x, double y, size_t k)
     
     list->objects = result.objects;
     list->count = result.count;
+    collect_query_stats(idx, result.page_ids, result.pages_accessed, &list->stats);
+    record_structure(&list->stats, SI_STRUCTURE_AUTO, result.structure);
     
     free(result.page_ids);
     
     return list;
 }
 
+int urbis_nearest(UrbisIndex *idx, double x, double y,
+                  SpatialObject **nearest, double *distance) {
+    if (!idx || !nearest) return URBIS_ERR_NULL;
+    
+    switch (spatial_index_nearest(idx, point_create(x, y), nearest, distance)) {
+        case SI_OK:            return URBIS_OK;
+        case SI_ERR_NOT_FOUND: return URBIS_ERR_NOT_FOUND;
+        default:               return URBIS_ERR_NULL;
+    }
+}
+
+UrbisObjectList* urbis_query_changed_since(UrbisIndex *idx, int64_t since_ms) {
+    if (!idx) return NULL;
+    
+    UrbisObjectList *list = (UrbisObjectList *)calloc(1, sizeof(UrbisObjectList));
+    if (!list) return NULL;
+    
+    SpatialQueryResult result;
+    if (spatial_result_init(&result, 64) != SI_OK) {
+        free(list);
+        return NULL;
+    }
+    
+    int err = spatial_index_query_changed_since(idx, since_ms, &result);
+    if (err != SI_OK) {
+        spatial_result_free(&result);
+        free(list);
+        return NULL;
+    }
+    
+    list->objects = result.objects;
+    list->count = result.count;
+    collect_query_stats(idx, result.page_ids, result.pages_accessed, &list->stats);
+    record_structure(&list->stats, SI_STRUCTURE_AUTO, result.structure);
+    
+    free(result.page_ids);
+    
+    return list;
+}
+
+size_t urbis_list_pages(const UrbisIndex *idx, UrbisPageInfo *pages, size_t capacity) {
+    if (!idx) return 0;
+    
+    size_t count = idx->disk.pool.page_count;
+    for (size_t i = 0; i < count && i < capacity && pages; i++) {
+        const PageHeader *header = &idx->disk.pool.pages[i]->header;
+        pages[i].page_id = header->page_id;
+        pages[i].track_id = header->track_id;
+        pages[i].object_count = header->object_count;
+        pages[i].extent = header->extent;
+    }
+    return count;
+}
+
+/**
+ * @brief State of a urbis_list_tree_nodes walk
+ */
+typedef struct {
+    UrbisTreeNode *nodes;
+    size_t capacity;
+    size_t count;
+    size_t max_depth;
+} TreeWalk;
+
+/**
+ * @brief Whether nodes at depth are listed
+ */
+static bool walk_lists(const TreeWalk *walk, uint32_t depth) {
+    return walk->max_depth == 0 || depth < walk->max_depth;
+}
+
+/**
+ * @brief Append a node to the walk
+ * @return The node's entry, or NULL when it is past the buffer
+ */
+static UrbisTreeNode* walk_add(TreeWalk *walk, uint32_t depth, int64_t parent,
+                               MBR bounds, bool is_leaf, size_t *slot) {
+    *slot = walk->count++;
+    if (!walk->nodes || *slot >= walk->capacity) return NULL;
+    
+    UrbisTreeNode *node = &walk->nodes[*slot];
+    memset(node, 0, sizeof(*node));
+    node->depth = depth;
+    node->parent = parent;
+    node->bounds = bounds;
+    node->split_dim = -1;
+    node->is_leaf = is_leaf;
+    return node;
+}
+
+static void walk_kdtree(TreeWalk *walk, const KDNode *kd, uint32_t depth, int64_t parent) {
+    if (!kd || !walk_lists(walk, depth)) return;
+    
+    bool leaf = !kd->left && !kd->right;
+    size_t slot;
+    UrbisTreeNode *node = walk_add(walk, depth, parent, kd->bounds, leaf, &slot);
+    if (node) {
+        node->object_count = kd->subtree_size;
+        node->item_count = kd->subtree_size;
+        if (!leaf) {
+            node->split_dim = kd->split_dim;
+            node->split_value = kd->split_dim == 0 ? kd->point.x : kd->point.y;
+        }
+    }
+    walk_kdtree(walk, kd->left, depth + 1, (int64_t)slot);
+    walk_kdtree(walk, kd->right, depth + 1, (int64_t)slot);
+}
+
+/**
+ * @brief List a quadtree node and add its subtree's counts to objects and pages
+ *
+ * Nodes below max_depth are visited only for their counts.
+ */
+static void walk_quadtree(TreeWalk *walk, const QTNode *qt, uint32_t depth, int64_t parent,
+                          uint64_t *objects, uint64_t *pages) {
+    size_t slot = 0;
+    bool listed = walk_lists(walk, depth);
+    if (listed) walk_add(walk, depth, parent, qt->bounds, qt->is_leaf, &slot);
+    
+    uint64_t sub_objects = 0;
+    uint64_t sub_pages = qt->item_count;
+    for (size_t i = 0; i < qt->item_count; i++) {
+        const Page *page = (const Page *)qt->items[i].data;
+        if (page) sub_objects += page->header.object_count;
+    }
+    for (int q = 0; q < 4 && !qt->is_leaf; q++) {
+        if (qt->children[q]) {
+            walk_quadtree(walk, qt->children[q], depth + 1, (int64_t)slot, &sub_objects, &sub_pages);
+        }
+    }
+    
+    /* Counts are known only once the children are walked */
+    if (listed && walk->nodes && slot < walk->capacity) {
+        walk->nodes[slot].object_count = sub_objects;
+        walk->nodes[slot].item_count = sub_pages;
+    }
+    *objects += sub_objects;
+    *pages += sub_pages;
+}
+
+size_t urbis_list_tree_nodes(const UrbisIndex *idx, SpatialStructure structure,
+                             size_t max_depth, UrbisTreeNode *nodes, size_t capacity) {
+    if (!idx || !idx->is_built) return 0;
+    
+    TreeWalk walk = {nodes, capacity, 0, max_depth};
+    switch (structure) {
+        case SI_STRUCTURE_KDTREE:
+            walk_kdtree(&walk, idx->block_tree.root, 0, -1);
+            break;
+        case SI_STRUCTURE_QUADTREE:
+            if (idx->page_tree && idx->page_tree->root) {
+                uint64_t objects = 0, pages = 0;
+                walk_quadtree(&walk, idx->page_tree->root, 0, -1, &objects, &pages);
+            }
+            break;
+        default:
+            break;
+    }
+    return walk.count;
+}
+
 UrbisPageList* urbis_find_adjacent_pages(UrbisIndex *idx, const MBR *region) {
     if (!idx || !region) return NULL;
     
@@ -361,12 +1059,44 @@ UrbisPageList* urbis_find_adjacent_pages(UrbisIndex *idx, const MBR *region) {
         last_track = list->track_ids[i];
     }
     
+    /* Reaching the first track is a seek too, then every page is transferred */
+    if (result.count > 0) {
+        double page_ms = (double)idx->disk.config.page_size / (idx->config.transfer_mb_s * 1e6) * 1e3;
+        list->estimated_cost_ms = (double)(list->estimated_seeks + 1) * idx->config.seek_ms +
+                                  (double)result.count * page_ms;
+    }
+    
     /* Free the result - we've copied everything we need */
     adjacent_result_free(&result);
     
     return list;
 }
 
+int urbis_prefetch_region(UrbisIndex *idx, const MBR *region, size_t *loaded) {
+    if (loaded) *loaded = 0;
+    if (!idx || !region) return URBIS_ERR_NULL;
+    
+    AdjacentPagesResult result;
+    if (adjacent_result_init(&result, 64) != SI_OK) return URBIS_ERR_ALLOC;
+    
+    int err = spatial_index_find_adjacent_pages(idx, region, &result);
+    if (err != SI_OK) {
+        adjacent_result_free(&result);
+        return URBIS_ERR_ALLOC;
+    }
+    
+    for (size_t i = 0; i < result.count; i++) {
+        uint32_t page_id = result.pages[i]->header.page_id;
+        if (loaded && !page_cache_contains(&idx->disk.cache, page_id)) {
+            (*loaded)++;
+        }
+        disk_manager_get_page(&idx->disk, page_id);
+    }
+    
+    adjacent_result_free(&result);
+    return URBIS_OK;
+}
+
 UrbisObjectList* urbis_query_adjacent(UrbisIndex *idx, const MBR *region) {
     if (!idx || !region) return NULL;
     
@@ -425,6 +1155,18 @@ UrbisObjectList* urbis_query_adjacent(UrbisIndex *idx, const MBR *region) {
     
     list->count = count;
     
+    /* Statistics cover every adjacent page that was scanned */
+    uint32_t *page_ids = (uint32_t *)malloc(pages.count * sizeof(uint32_t));
+    if (page_ids) {
+        size_t visited = 0;
+        for (size_t i = 0; i < pages.count; i++) {
+            if (pages.pages[i]) page_ids[visited++] = pages.pages[i]->header.page_id;
+        }
+        collect_query_stats(idx, page_ids, visited, &list->stats);
+        free(page_ids);
+    }
+    list->stats.structure = SI_STRUCTURE_QUADTREE;
+    
     adjacent_result_free(&pages);
     
     return list;
@@ -442,18 +1184,64 @@ int urbis_save(UrbisIndex *idx, const char *path) {
 }
 
 UrbisIndex* urbis_load(const char *path) {
-    if (!path) return NULL;
+    UrbisIndex *idx = NULL;
+    urbis_load_checked(path, &idx);
+    return idx;
+}
+
+int urbis_load_checked(const char *path, UrbisIndex **out) {
+    return urbis_load_config(path, NULL, out);
+}
+
+int urbis_load_config(const char *path, const UrbisConfig *config, UrbisIndex **out) {
+    if (!out) return URBIS_ERR_NULL;
+    *out = NULL;
+    if (!path) return URBIS_ERR_NULL;
     
-    UrbisIndex *idx = urbis_create(NULL);
-    if (!idx) return NULL;
+    UrbisIndex *idx = urbis_create(config);
+    if (!idx) return URBIS_ERR_ALLOC;
     
     int err = spatial_index_load(idx, path);
     if (err != SI_OK) {
         urbis_destroy(idx);
-        return NULL;
+        return (err == SI_ERR_VERSION) ? URBIS_ERR_VERSION : URBIS_ERR_IO;
     }
     
-    return idx;
+    *out = idx;
+    return URBIS_OK;
+}
+
+uint32_t urbis_format_version(void) {
+    return DM_VERSION;
+}
+
+int urbis_file_format_version(const char *path, uint32_t *version) {
+    if (!path || !version) return URBIS_ERR_NULL;
+    
+    int err = disk_manager_read_version(path, version);
+    return (err == DM_OK) ? URBIS_OK : URBIS_ERR_IO;
+}
+
+int urbis_set_settings(UrbisIndex *idx, const void *data, size_t size) {
+    if (!idx) return URBIS_ERR_NULL;
+    
+    switch (disk_manager_set_settings(&idx->disk, data, size)) {
+        case DM_OK: return URBIS_OK;
+        case DM_ERR_FULL: return URBIS_ERR_FULL;
+        case DM_ERR_ALLOC: return URBIS_ERR_ALLOC;
+        default: return URBIS_ERR_NULL;
+    }
+}
+
+const void* urbis_get_settings(const UrbisIndex *idx, size_t *size) {
+    if (size) *size = idx ? idx->disk.settings_size : 0;
+    return idx ? idx->disk.settings : NULL;
+}
+
+bool urbis_valid_bounds(const UrbisIndex *idx, MBR *out) {
+    if (!idx || !idx->config.bounded) return false;
+    if (out) *out = idx->config.valid_bounds;
+    return true;
 }
 
 int urbis_sync(UrbisIndex *idx) {
@@ -482,6 +1270,14 @@ void urbis_get_stats(const UrbisIndex *idx, UrbisStats *stats) {
     stats->kdtree_depth = si_stats.kdtree_depth;
     stats->quadtree_depth = si_stats.quadtree_depth;
     stats->bounds = si_stats.bounds;
+    stats->page_capacity = idx->config.page_capacity;
+    stats->memory_bytes = si_stats.memory_bytes;
+    stats->disk_bytes = si_stats.disk_bytes;
+    stats->build_threads = si_stats.build_threads;
+}
+
+size_t urbis_memory_usage(const UrbisIndex *idx) {
+    return spatial_index_memory(idx);
 }
 
 size_t urbis_count(const UrbisIndex *idx) {
@@ -497,6 +1293,22 @@ MBR urbis_bounds(const UrbisIndex *idx) {
     return idx->bounds;
 }
 
+bool urbis_is_built(const UrbisIndex *idx) {
+    return idx && idx->is_built;
+}
+
+size_t urbis_pending_changes(const UrbisIndex *idx) {
+    return idx ? idx->changes_since_build : 0;
+}
+
+size_t urbis_out_of_bounds(const UrbisIndex *idx) {
+    return idx ? idx->out_of_bounds : 0;
+}
+
+uint64_t urbis_change_count(const UrbisIndex *idx) {
+    return idx ? idx->change_count : 0;
+}
+
 void urbis_print_stats(const UrbisIndex *idx, FILE *out) {
     if (!idx || !out) return;
     
@@ -523,6 +1335,125 @@ size_t urbis_estimate_seeks(const UrbisIndex *idx,
     return total_seeks;
 }
 
+double urbis_estimate_cost_ms(const UrbisIndex *idx,
+                              const MBR *regions, size_t count) {
+    if (!idx || !regions || count == 0) return 0;
+    
+    double total_ms = 0;
+    
+    for (size_t i = 0; i < count; i++) {
+        UrbisPageList *pages = urbis_find_adjacent_pages((UrbisIndex *)idx, &regions[i]);
+        if (pages) {
+            total_ms += pages->estimated_cost_ms;
+            urbis_page_list_free(pages);
+        }
+    }
+    
+    return total_ms;
+}
+
+size_t urbis_estimate_count(const UrbisIndex *idx, const MBR *region) {
+    if (!idx || !region) return 0;
+    
+    size_t total = 0;
+    for (size_t i = 0; i < idx->disk.pool.page_count; i++) {
+        const PageHeader *header = &idx->disk.pool.pages[i]->header;
+        if (header->object_count > 0 && mbr_intersects(&header->extent, region)) {
+            total += header->object_count;
+        }
+    }
+    return total;
+}
+
+static const size_t tune_capacities[URBIS_TUNE_CANDIDATES] = {8, 16, 32, 64};
+
+int urbis_autotune(const UrbisIndex *idx, const MBR *queries, size_t query_count,
+                   UrbisTuneResult *result) {
+    return urbis_autotune_cancellable(idx, queries, query_count, NULL, NULL, result);
+}
+
+int urbis_autotune_cancellable(const UrbisIndex *idx, const MBR *queries, size_t query_count,
+                               BuildCancelFn should_cancel, void *user_data,
+                               UrbisTuneResult *result) {
+    if (!idx || !result) return URBIS_ERR_NULL;
+    if (!queries && query_count > 0) return URBIS_ERR_NULL;
+    
+    memset(result, 0, sizeof(UrbisTuneResult));
+    result->page_capacity = idx->config.page_capacity;
+    if (urbis_count(idx) == 0) return URBIS_OK;
+    
+    /* Default probes: the central quarter and the four quadrants */
+    MBR probes[5];
+    if (query_count == 0) {
+        MBR b = idx->bounds;
+        double cx = (b.min_x + b.max_x) / 2;
+        double cy = (b.min_y + b.max_y) / 2;
+        double qw = (b.max_x - b.min_x) / 4;
+        double qh = (b.max_y - b.min_y) / 4;
+        probes[0] = mbr_create(cx - qw, cy - qh, cx + qw, cy + qh);
+        probes[1] = mbr_create(b.min_x, b.min_y, cx, cy);
+        probes[2] = mbr_create(cx, b.min_y, b.max_x, cy);
+        probes[3] = mbr_create(b.min_x, cy, cx, b.max_y);
+        probes[4] = mbr_create(cx, cy, b.max_x, b.max_y);
+        queries = probes;
+        query_count = 5;
+    }
+    
+    double best = 0;
+    for (size_t i = 0; i < URBIS_TUNE_CANDIDATES; i++) {
+        UrbisIndex *copy = NULL;
+        int err = urbis_repage_cancellable(idx, tune_capacities[i], should_cancel, user_data, &copy);
+        if (err != URBIS_OK) return err;
+        
+        UrbisStats stats;
+        urbis_get_stats(copy, &stats);
+        
+        UrbisTuneCandidate *c = &result->candidates[result->candidate_count++];
+        c->page_capacity = tune_capacities[i];
+        c->total_pages = stats.total_pages;
+        c->page_utilization = stats.page_utilization;
+        c->avg_seeks = (double)urbis_estimate_seeks(copy, queries, query_count) / query_count;
+        c->avg_cost_ms = urbis_estimate_cost_ms(copy, queries, query_count) / query_count;
+        c->cost = c->avg_cost_ms / (c->page_utilization > 0 ? c->page_utilization : 1e-9);
+        urbis_destroy(copy);
+        
+        if (i == 0 || c->cost < best) {
+            best = c->cost;
+            result->page_capacity = c->page_capacity;
+        }
+    }
+    
+    return URBIS_OK;
+}
+
+UrbisIndex* urbis_repage(const UrbisIndex *idx, size_t page_capacity) {
+    UrbisIndex *copy = NULL;
+    return urbis_repage_cancellable(idx, page_capacity, NULL, NULL, &copy) == URBIS_OK ? copy : NULL;
+}
+
+int urbis_repage_cancellable(const UrbisIndex *idx, size_t page_capacity,
+                             BuildCancelFn should_cancel, void *user_data, UrbisIndex **out) {
+    if (!idx || !out) return URBIS_ERR_NULL;
+    *out = NULL;
+    if (page_capacity == 0 || page_capacity > MAX_OBJECTS_PER_PAGE) return URBIS_ERR_INVALID;
+    
+    switch (spatial_index_repage_cancellable(idx, page_capacity, should_cancel, user_data, out)) {
+        case SI_OK:            return URBIS_OK;
+        case SI_ERR_CANCELLED: return URBIS_ERR_CANCELLED;
+        default:               return URBIS_ERR_ALLOC;
+    }
+}
+
+int urbis_adopt_data_file(UrbisIndex *copy, UrbisIndex *idx) {
+    if (!copy || !idx) return URBIS_ERR_NULL;
+    
+    switch (spatial_index_adopt_file(copy, idx)) {
+        case SI_OK:        return URBIS_OK;
+        case SI_ERR_ALLOC: return URBIS_ERR_ALLOC;
+        default:           return URBIS_ERR_IO;
+    }
+}
+
 /* ============================================================================
  * Result List Operations
  * ============================================================================ */
@@ -530,6 +1461,9 @@ size_t urbis_estimate_seeks(const UrbisIndex *idx,
 void urbis_object_list_free(UrbisObjectList *list) {
     if (!list) return;
     free(list->objects);
+    free(list->failed_pages);
+    free(list->visited_pages);
+    free(list->visited_tracks);
     free(list);
 }
 
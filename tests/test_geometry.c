@@ -348,6 +348,72 @@ TEST(spatial_object_copy) {
     spatial_object_free(&dest);
 }
 
+TEST(spatial_object_multipolygon) {
+    SpatialObject obj;
+    int err = spatial_object_init_multipolygon(&obj, 5, 2);
+    assert(err == GEOM_OK);
+    assert(obj.type == GEOM_MULTIPOLYGON);
+    
+    /* Two unit squares at (0,0) and (10,0) */
+    for (int part = 0; part < 2; part++) {
+        int idx = multipolygon_add_polygon(&obj.geom.multi_polygon, 4);
+        assert(idx == part);
+        
+        double ox = part * 10.0;
+        Polygon *poly = &obj.geom.multi_polygon.polygons[idx];
+        polygon_add_exterior_point(poly, point_create(ox, 0));
+        polygon_add_exterior_point(poly, point_create(ox + 1, 0));
+        polygon_add_exterior_point(poly, point_create(ox + 1, 1));
+        polygon_add_exterior_point(poly, point_create(ox, 1));
+    }
+    
+    err = spatial_object_update_derived(&obj);
+    assert(err == GEOM_OK);
+    ASSERT_NEAR(obj.centroid.x, 5.5);
+    ASSERT_NEAR(obj.centroid.y, 0.5);
+    ASSERT_NEAR(obj.mbr.min_x, 0);
+    ASSERT_NEAR(obj.mbr.max_x, 11);
+    
+    SpatialObject copy;
+    err = spatial_object_copy(&copy, &obj);
+    assert(err == GEOM_OK);
+    assert(copy.geom.multi_polygon.count == 2);
+    assert(copy.geom.multi_polygon.polygons[1].ext_count == 4);
+    
+    spatial_object_free(&obj);
+    spatial_object_free(&copy);
+}
+
+TEST(polygon_contains_point) {
+    Polygon poly;
+    polygon_init(&poly, 4);
+    polygon_add_exterior_point(&poly, point_create(0, 0));
+    polygon_add_exterior_point(&poly, point_create(10, 0));
+    polygon_add_exterior_point(&poly, point_create(10, 10));
+    polygon_add_exterior_point(&poly, point_create(0, 10));
+    
+    polygon_add_hole(&poly, 4);
+    polygon_add_hole_point(&poly, 0, point_create(4, 4));
+    polygon_add_hole_point(&poly, 0, point_create(6, 4));
+    polygon_add_hole_point(&poly, 0, point_create(6, 6));
+    polygon_add_hole_point(&poly, 0, point_create(4, 6));
+    
+    Point inside = point_create(2, 2);
+    Point in_hole = point_create(5, 5);
+    Point on_edge = point_create(10, 5);
+    Point on_hole_edge = point_create(4, 5);
+    Point outside = point_create(11, 5);
+    
+    assert(polygon_contains_point(&poly, &inside));
+    assert(!polygon_contains_point(&poly, &in_hole));
+    assert(!polygon_contains_point(&poly, &on_edge));
+    assert(polygon_locate_point(&poly, &on_edge) == 0);
+    assert(polygon_locate_point(&poly, &on_hole_edge) == 0);
+    assert(polygon_locate_point(&poly, &outside) == -1);
+    
+    polygon_free(&poly);
+}
+
 /* ============================================================================
  * Main
  * ============================================================================ */
@@ -388,6 +454,8 @@ int main(void) {
     RUN_TEST(spatial_object_point);
     RUN_TEST(spatial_object_linestring);
     RUN_TEST(spatial_object_copy);
+    RUN_TEST(spatial_object_multipolygon);
+    RUN_TEST(polygon_contains_point);
     
     printf("\n=================================\n");
     printf("Tests passed: %d\n", tests_passed);
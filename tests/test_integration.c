@@ -9,6 +9,7 @@
 #include <string.h>
 #include <assert.h>
 #include <math.h>
+#include <unistd.h>
 
 static int tests_passed = 0;
 static int tests_failed = 0;
@@ -286,6 +287,1568 @@ TEST(wkt_loading) {
     urbis_destroy(idx);
 }
 
+TEST(query_stats) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    for (int i = 0; i < 10; i++) {
+        for (int j = 0; j < 10; j++) {
+            urbis_insert_point(idx, i * 10, j * 10);
+        }
+    }
+    
+    urbis_build(idx);
+    
+    MBR range = urbis_mbr(0, 0, 100, 100);
+    UrbisObjectList *result = urbis_query_range(idx, &range);
+    assert(result != NULL);
+    assert(result->count == 100);
+    assert(result->stats.pages_visited > 0);
+    assert(result->stats.tracks_visited > 0);
+    assert(result->stats.tracks_visited <= result->stats.pages_visited);
+    assert(result->stats.cache_hits + result->stats.cache_misses ==
+           result->stats.pages_visited);
+    urbis_object_list_free(result);
+    
+    /* Empty region touches no pages */
+    range = urbis_mbr(1000, 1000, 2000, 2000);
+    result = urbis_query_range(idx, &range);
+    assert(result != NULL);
+    assert(result->stats.pages_visited == 0);
+    urbis_object_list_free(result);
+    
+    result = urbis_query_knn(idx, 0, 0, 3);
+    assert(result != NULL);
+    assert(result->stats.pages_visited > 0);
+    urbis_object_list_free(result);
+    
+    urbis_destroy(idx);
+}
+
+TEST(multi_geometry_loading) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    const char *geojson = "{"
+        "\"type\": \"FeatureCollection\","
+        "\"features\": ["
+        "  {\"type\": \"Feature\", \"geometry\": {\"type\": \"MultiPoint\", \"coordinates\": [[1,1],[3,3]]}},"
+        "  {\"type\": \"Feature\", \"geometry\": {\"type\": \"MultiLineString\", \"coordinates\": [[[0,0],[5,5]],[[10,10],[20,20]]]}},"
+        "  {\"type\": \"Feature\", \"geometry\": {\"type\": \"MultiPolygon\", \"coordinates\": [[[[0,0],[4,0],[4,4],[0,4],[0,0]]]]}},"
+        "  {\"type\": \"Feature\", \"geometry\": {\"type\": \"GeometryCollection\", \"geometries\": ["
+        "    {\"type\": \"Point\", \"coordinates\": [50, 50]},"
+        "    {\"type\": \"LineString\", \"coordinates\": [[60,60],[70,70]]}]}}"
+        "]"
+    "}";
+    
+    int err = urbis_load_geojson_string(idx, geojson);
+    assert(err == URBIS_OK);
+    assert(urbis_count(idx) == 4);
+    
+    Point parts[] = {{100, 100}, {101, 100}, {101, 101}, {200, 200}, {201, 200}, {201, 201}};
+    size_t counts[] = {3, 3};
+    uint64_t id = urbis_insert_multipolygon(idx, parts, counts, 2);
+    assert(id != 0);
+    
+    SpatialObject *obj = urbis_get(idx, id);
+    assert(obj != NULL);
+    assert(obj->type == GEOM_MULTIPOLYGON);
+    assert(obj->geom.multi_polygon.count == 2);
+    assert(obj->mbr.max_x >= 201);
+    
+    urbis_destroy(idx);
+}
+
+typedef struct {
+    size_t calls;
+    size_t last_done;
+    size_t last_total;
+    bool monotonic;
+} ProgressLog;
+
+static void record_progress(void *user_data, size_t done, size_t total) {
+    ProgressLog *log = (ProgressLog *)user_data;
+    if (done < log->last_done) log->monotonic = false;
+    log->calls++;
+    log->last_done = done;
+    log->last_total = total;
+}
+
+TEST(build_progress) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    for (int i = 0; i < 500; i++) {
+        urbis_insert_point(idx, i * 1.5, (i % 37) * 2.0);
+    }
+    
+    ProgressLog log = {0, 0, 0, true};
+    int err = urbis_build_progress(idx, record_progress, &log);
+    assert(err == URBIS_OK);
+    assert(log.calls >= 2);
+    assert(log.monotonic);
+    assert(log.last_total == 500);
+    assert(log.last_done == 500);
+    
+    urbis_destroy(idx);
+}
+
+TEST(wkb_roundtrip) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    /* Big-endian POINT(1 2) followed by little-endian LINESTRING(0 0, 3 4) */
+    const uint8_t wkb[] = {
+        0x00, 0x00, 0x00, 0x00, 0x01,
+        0x3F, 0xF0, 0, 0, 0, 0, 0, 0,
+        0x40, 0x00, 0, 0, 0, 0, 0, 0,
+        0x01, 0x02, 0x00, 0x00, 0x00,
+        0x02, 0x00, 0x00, 0x00,
+        0, 0, 0, 0, 0, 0, 0, 0,
+        0, 0, 0, 0, 0, 0, 0, 0,
+        0, 0, 0, 0, 0, 0, 0x08, 0x40,
+        0, 0, 0, 0, 0, 0, 0x10, 0x40
+    };
+    
+    int err = urbis_load_wkb(idx, wkb, sizeof(wkb));
+    assert(err == URBIS_OK);
+    assert(urbis_count(idx) == 2);
+    
+    SpatialObject *pt = urbis_get(idx, 1);
+    assert(pt && pt->type == GEOM_POINT);
+    assert(fabs(pt->geom.point.x - 1) < 1e-9 && fabs(pt->geom.point.y - 2) < 1e-9);
+    
+    /* Export the linestring and parse it back */
+    int size = urbis_export_wkb(idx, 2, NULL, 0);
+    assert(size == 41);
+    uint8_t out[64];
+    assert(urbis_export_wkb(idx, 2, out, sizeof(out)) == size);
+    assert(memcmp(out, wkb + 21, size) == 0);
+    
+    assert(urbis_load_wkb(idx, wkb, 10) == URBIS_ERR_PARSE);
+    
+    urbis_destroy(idx);
+}
+
+TEST(query_containing) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    /* Triangle whose MBR covers (9, 1) but whose interior does not */
+    Point tri[] = {{0, 0}, {10, 0}, {0, 10}};
+    uint64_t tri_id = urbis_insert_polygon(idx, tri, 3);
+    Point square[] = {{5, 0}, {15, 0}, {15, 10}, {5, 10}};
+    uint64_t square_id = urbis_insert_polygon(idx, square, 4);
+    urbis_insert_point(idx, 9, 1);
+    urbis_build(idx);
+    
+    UrbisObjectList *mbr_hits = urbis_query_point(idx, 9, 8);
+    assert(mbr_hits && mbr_hits->count >= 2);
+    urbis_object_list_free(mbr_hits);
+    
+    UrbisObjectList *result = urbis_query_containing(idx, 9, 8);
+    assert(result != NULL);
+    assert(result->count == 1);
+    assert(result->objects[0]->id == square_id);
+    urbis_object_list_free(result);
+    
+    result = urbis_query_containing(idx, 2, 2);
+    assert(result && result->count == 1 && result->objects[0]->id == tri_id);
+    urbis_object_list_free(result);
+    
+    urbis_destroy(idx);
+}
+
+TEST(reject_non_finite) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    urbis_insert_point(idx, 1, 1);
+    urbis_insert_point(idx, 5, 5);
+    
+    assert(urbis_insert_point(idx, NAN, 3) == 0);
+    assert(urbis_insert_point(idx, 3, INFINITY) == 0);
+    
+    Point line[] = {{0, 0}, {NAN, 2}, {4, 4}};
+    assert(urbis_insert_linestring(idx, line, 3) == 0);
+    
+    assert(urbis_load_wkt(idx, "POINT (inf 2)") == URBIS_ERR_INVALID);
+    
+    assert(urbis_count(idx) == 2);
+    MBR bounds = urbis_bounds(idx);
+    assert(bounds.min_x == 1 && bounds.min_y == 1);
+    assert(bounds.max_x == 5 && bounds.max_y == 5);
+    
+    urbis_destroy(idx);
+}
+
+TEST(snap_and_dedup) {
+    UrbisConfig config = urbis_default_config();
+    config.snap_grid = 0.01;
+    config.dedup_points = true;
+    UrbisIndex *idx = urbis_create(&config);
+    
+    uint64_t a = urbis_insert_point(idx, 1.001, 2.002);
+    uint64_t next = urbis_next_id(idx);
+    assert(next == a + 1);
+    uint64_t b = urbis_insert_point(idx, 0.999, 1.998);
+    uint64_t c = urbis_insert_point(idx, 1.0, 2.0);
+    /* The duplicates took no new ID */
+    assert(urbis_next_id(idx) == next);
+    uint64_t d = urbis_insert_point(idx, 3.456, 7.891);
+    
+    assert(a != 0 && a == b && b == c);
+    assert(d == next && urbis_next_id(idx) == next + 1);
+    assert(urbis_count(idx) == 2);
+    
+    SpatialObject *obj = urbis_get(idx, a);
+    assert(obj->geom.point.x == 1.0 && obj->geom.point.y == 2.0);
+    assert(obj->properties_size == strlen("{\"count\":3}"));
+    assert(memcmp(obj->properties, "{\"count\":3}", obj->properties_size) == 0);
+    
+    obj = urbis_get(idx, d);
+    assert(fabs(obj->geom.point.x - 3.46) < 1e-9 && fabs(obj->geom.point.y - 7.89) < 1e-9);
+    
+    urbis_destroy(idx);
+}
+
+TEST(query_structure_hint) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    /* Points plus polygons whose MBRs reach well past their centroids */
+    for (int i = 0; i < 500; i++) {
+        urbis_insert_point(idx, (i * 37) % 100, (i * 53) % 100);
+    }
+    for (int i = 0; i < 20; i++) {
+        double x = i * 5.0;
+        Point ring[] = {{x, 0}, {x + 30, 0}, {x + 30, 30}, {x, 30}, {x, 0}};
+        urbis_insert_polygon(idx, ring, 5);
+    }
+    
+    MBR range = mbr_create(20, 20, 45, 45);
+    
+    /* Unbuilt trees fall back to a page scan */
+    UrbisObjectList *scan = urbis_query_range_using(idx, &range, SI_STRUCTURE_KDTREE);
+    assert(scan->stats.structure == SI_STRUCTURE_SCAN);
+    assert(scan->stats.structure_fallback);
+    
+    urbis_build(idx);
+    
+    UrbisObjectList *kd = urbis_query_range_using(idx, &range, SI_STRUCTURE_KDTREE);
+    UrbisObjectList *qt = urbis_query_range_using(idx, &range, SI_STRUCTURE_QUADTREE);
+    UrbisObjectList *dflt = urbis_query_range(idx, &range);
+    
+    assert(kd->stats.structure == SI_STRUCTURE_KDTREE && !kd->stats.structure_fallback);
+    assert(qt->stats.structure == SI_STRUCTURE_QUADTREE && !qt->stats.structure_fallback);
+    assert(dflt->stats.structure == SI_STRUCTURE_SCAN && !dflt->stats.structure_fallback);
+    assert(dflt->count > 0);
+    assert(kd->count == dflt->count);
+    assert(qt->count == dflt->count);
+    assert(scan->count == dflt->count);
+    
+    /* The KD-tree visits only the pages holding its results */
+    assert(kd->stats.pages_visited > 0);
+    assert(kd->stats.pages_visited <= qt->stats.pages_visited);
+    
+    urbis_object_list_free(scan);
+    urbis_object_list_free(kd);
+    urbis_object_list_free(qt);
+    urbis_object_list_free(dflt);
+    
+    /* Without a quadtree the hint falls back to the KD-tree */
+    UrbisConfig config = urbis_default_config();
+    config.enable_quadtree = false;
+    UrbisIndex *noqt = urbis_create(&config);
+    urbis_insert_point(noqt, 1, 1);
+    urbis_build(noqt);
+    
+    UrbisObjectList *list = urbis_query_point_using(noqt, 1, 1, SI_STRUCTURE_QUADTREE);
+    assert(list->count == 1);
+    assert(list->stats.structure == SI_STRUCTURE_KDTREE);
+    assert(list->stats.structure_fallback);
+    urbis_object_list_free(list);
+    
+    urbis_destroy(noqt);
+    urbis_destroy(idx);
+}
+
+TEST(set_properties) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    uint64_t id = urbis_insert_point(idx, 3.0, 4.0);
+    urbis_build(idx);
+    
+    size_t size = 1;
+    assert(urbis_get_properties(idx, id, &size) == NULL);
+    assert(size == 0);
+    
+    const char *props = "{\"inspected\":true}";
+    assert(urbis_set_properties(idx, id, props, strlen(props)) == URBIS_OK);
+    
+    const char *got = urbis_get_properties(idx, id, &size);
+    assert(size == strlen(props));
+    assert(memcmp(got, props, size) == 0);
+    
+    /* Geometry and build state are untouched */
+    SpatialObject *obj = urbis_get(idx, id);
+    assert(obj->geom.point.x == 3.0 && obj->geom.point.y == 4.0);
+    assert(urbis_is_built(idx));
+    
+    assert(urbis_set_properties(idx, id, NULL, 0) == URBIS_OK);
+    assert(urbis_get_properties(idx, id, &size) == NULL);
+    assert(urbis_set_properties(idx, id + 100, props, strlen(props)) == URBIS_ERR_NOT_FOUND);
+    
+    urbis_destroy(idx);
+}
+
+TEST(remove_range) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    urbis_insert_point(idx, 1, 1);
+    urbis_insert_point(idx, 5, 5);
+    urbis_insert_point(idx, 20, 20);
+    
+    /* Straddles the region edge: MBR 8..12, centroid 10,10 */
+    Point ring[] = {{8, 8}, {12, 8}, {12, 12}, {8, 12}, {8, 8}};
+    uint64_t poly = urbis_insert_polygon(idx, ring, 5);
+    urbis_build(idx);
+    
+    MBR region = mbr_create(0, 0, 10, 10);
+    size_t removed = 0;
+    
+    /* Contained only takes the two points; the polygon sticks out */
+    assert(urbis_remove_range(idx, &region, SI_MATCH_CONTAINED, &removed) == URBIS_OK);
+    assert(removed == 2);
+    assert(urbis_count(idx) == 2);
+    assert(urbis_get(idx, poly) != NULL);
+    assert(!urbis_is_built(idx));
+    
+    /* Centroid on the boundary counts as inside */
+    assert(urbis_remove_range(idx, &region, SI_MATCH_CENTROID, &removed) == URBIS_OK);
+    assert(removed == 1);
+    assert(urbis_get(idx, poly) == NULL);
+    
+    MBR far = mbr_create(15, 15, 25, 25);
+    assert(urbis_remove_range(idx, &far, SI_MATCH_INTERSECTS, &removed) == URBIS_OK);
+    assert(removed == 1);
+    assert(urbis_count(idx) == 0);
+    
+    assert(urbis_remove_range(idx, &far, (SpatialMatch)7, &removed) == URBIS_ERR_INVALID);
+    
+    urbis_destroy(idx);
+}
+
+TEST(prefetch_region) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    for (int i = 0; i < 20; i++) {
+        for (int j = 0; j < 20; j++) {
+            urbis_insert_point(idx, i * 5, j * 5);
+        }
+    }
+    urbis_build(idx);
+    
+    /* Nothing has been read through the cache yet */
+    MBR range = urbis_mbr(0, 0, 30, 30);
+    UrbisObjectList *result = urbis_query_range(idx, &range);
+    assert(result != NULL);
+    assert(result->stats.pages_visited > 0);
+    assert(result->stats.cache_hits == 0);
+    urbis_object_list_free(result);
+    
+    size_t loaded = 0;
+    assert(urbis_prefetch_region(idx, &range, &loaded) == URBIS_OK);
+    assert(loaded > 0);
+    
+    result = urbis_query_range(idx, &range);
+    assert(result != NULL);
+    assert(result->stats.cache_hits == result->stats.pages_visited);
+    assert(result->stats.cache_misses == 0);
+    urbis_object_list_free(result);
+    
+    /* A second prefetch finds everything resident */
+    assert(urbis_prefetch_region(idx, &range, &loaded) == URBIS_OK);
+    assert(loaded == 0);
+    
+    assert(urbis_prefetch_region(NULL, &range, NULL) == URBIS_ERR_NULL);
+    
+    urbis_destroy(idx);
+}
+
+TEST(autotune_page_capacity) {
+    UrbisConfig config = urbis_default_config();
+    config.page_capacity = 16;
+    UrbisIndex *idx = urbis_create(&config);
+    
+    UrbisStats stats;
+    urbis_get_stats(idx, &stats);
+    assert(stats.page_capacity == 16);
+    
+    /* Empty index: nothing to evaluate */
+    UrbisTuneResult tune;
+    assert(urbis_autotune(idx, NULL, 0, &tune) == URBIS_OK);
+    assert(tune.candidate_count == 0);
+    assert(tune.page_capacity == 16);
+    
+    for (int i = 0; i < 30; i++) {
+        for (int j = 0; j < 30; j++) {
+            urbis_insert_point(idx, i, j);
+        }
+    }
+    urbis_build(idx);
+    
+    /* Pages honor the configured capacity */
+    urbis_get_stats(idx, &stats);
+    assert(stats.total_pages >= 900 / 16);
+    
+    assert(urbis_autotune(idx, NULL, 0, &tune) == URBIS_OK);
+    assert(tune.candidate_count == URBIS_TUNE_CANDIDATES);
+    bool found = false;
+    for (size_t i = 0; i < tune.candidate_count; i++) {
+        assert(tune.candidates[i].total_pages > 0);
+        assert(tune.candidates[i].cost > 0);
+        found = found || tune.candidates[i].page_capacity == tune.page_capacity;
+    }
+    assert(found);
+    
+    /* Repaging keeps every object and ID */
+    UrbisIndex *copy = urbis_repage(idx, 8);
+    assert(copy != NULL);
+    assert(urbis_count(copy) == 900);
+    assert(urbis_is_built(copy));
+    urbis_get_stats(copy, &stats);
+    assert(stats.page_capacity == 8);
+    assert(stats.total_pages >= 900 / 8);
+    assert(urbis_get(copy, 450) != NULL);
+    urbis_destroy(copy);
+    
+    assert(urbis_repage(idx, 0) == NULL);
+    assert(urbis_repage(idx, 65) == NULL);
+    
+    urbis_destroy(idx);
+}
+
+TEST(changed_since) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    uint64_t a = urbis_insert_point(idx, 1, 1);
+    uint64_t b = urbis_insert_point(idx, 2, 2);
+    uint64_t c = urbis_insert_point(idx, 3, 3);
+    
+    SpatialObject *obj = urbis_get(idx, a);
+    assert(obj != NULL);
+    assert(obj->version > 0);
+    assert(obj->modified_at > 0);
+    int64_t inserted_at = obj->modified_at;
+    
+    /* Properties changes bump the version without rebuilding */
+    assert(urbis_set_properties(idx, a, "{}", 2) == URBIS_OK);
+    obj = urbis_get(idx, a);
+    assert(obj->version > urbis_get(idx, c)->version);
+    assert(obj->modified_at >= inserted_at);
+    
+    /* Everything changed since the first insert, ordered by version */
+    UrbisObjectList *result = urbis_query_changed_since(idx, inserted_at);
+    assert(result != NULL);
+    assert(result->count == 3);
+    assert(result->objects[0]->id == b);
+    assert(result->objects[1]->id == c);
+    assert(result->objects[2]->id == a);
+    urbis_object_list_free(result);
+    
+    /* Nothing is newer than now */
+    result = urbis_query_changed_since(idx, obj->modified_at + 60000);
+    assert(result != NULL);
+    assert(result->count == 0);
+    urbis_object_list_free(result);
+    
+    urbis_destroy(idx);
+}
+
+TEST(memory_and_disk_stats) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    UrbisStats empty;
+    urbis_get_stats(idx, &empty);
+    assert(empty.memory_bytes > 0);
+    assert(empty.disk_bytes == 0);
+    
+    Point line[100];
+    for (int i = 0; i < 100; i++) {
+        line[i] = (Point){ i, i % 7 };
+    }
+    for (int i = 0; i < 500; i++) {
+        urbis_insert_linestring(idx, line, 100);
+    }
+    urbis_build(idx);
+    
+    /* 500 lines of 100 points own at least their coordinates */
+    UrbisStats full;
+    urbis_get_stats(idx, &full);
+    assert(full.memory_bytes >= empty.memory_bytes + 500 * 100 * sizeof(Point));
+    
+    const char *path = "/tmp/urbis_test_memory_stats.dat";
+    assert(urbis_save(idx, path) == URBIS_OK);
+    urbis_get_stats(idx, &full);
+    assert(full.disk_bytes > 0);
+    remove(path);
+    
+    urbis_destroy(idx);
+}
+
+TEST(ewkt_loading) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    assert(urbis_wkt_srid("POINT (1 2)") == 0);
+    assert(urbis_wkt_srid("SRID=4326;POINT (1 2)") == 4326);
+    assert(urbis_wkt_srid(" srid = 3857 ; POINT (1 2)") == 3857);
+    assert(urbis_wkt_srid("SRID=;POINT (1 2)") == -1);
+    assert(urbis_wkt_srid("SRID=4326 POINT (1 2)") == -1);
+    
+    assert(urbis_load_wkt(idx, "SRID=4326;POINT (10 20)") == URBIS_OK);
+    assert(urbis_load_wkt(idx, "SRID=4326;LINESTRING (0 0, 10 10)") == URBIS_OK);
+    assert(urbis_load_wkt(idx, "SRID=x;POINT (1 2)") == URBIS_ERR_PARSE);
+    assert(urbis_count(idx) == 2);
+    
+    MBR bounds = urbis_bounds(idx);
+    assert(bounds.max_x == 10 && bounds.max_y == 20);
+    
+    urbis_destroy(idx);
+}
+
+TEST(explicit_ids) {
+    UrbisConfig config = urbis_default_config();
+    config.dedup_points = true;
+    UrbisIndex *idx = urbis_create(&config);
+    
+    assert(urbis_insert_point_id(idx, 1000, 1, 1) == URBIS_OK);
+    assert(urbis_get(idx, 1000) != NULL);
+    assert(urbis_insert_point_id(idx, 1000, 5, 5) == URBIS_ERR_EXISTS);
+    assert(urbis_insert_point_id(idx, 0, 5, 5) == URBIS_ERR_INVALID);
+    
+    /* A point at an existing location would be merged and lose its ID */
+    assert(urbis_insert_point_id(idx, 7, 1, 1) == URBIS_ERR_EXISTS);
+    
+    Point line[] = {{0, 0}, {2, 2}};
+    assert(urbis_insert_linestring_id(idx, 42, line, 2) == URBIS_OK);
+    Point ring[] = {{0, 0}, {1, 0}, {1, 1}, {0, 0}};
+    assert(urbis_insert_polygon_id(idx, 42, ring, 4) == URBIS_ERR_EXISTS);
+    assert(urbis_insert_polygon_id(idx, 43, ring, 4) == URBIS_OK);
+    
+    /* Automatic IDs continue after the highest explicit one */
+    assert(urbis_insert_point(idx, 9, 9) == 1001);
+    assert(urbis_count(idx) == 4);
+    
+    urbis_destroy(idx);
+}
+
+TEST(list_pages) {
+    UrbisConfig config = urbis_default_config();
+    config.page_capacity = 4;
+    UrbisIndex *idx = urbis_create(&config);
+    
+    for (int i = 0; i < 20; i++) {
+        urbis_insert_point(idx, i, i);
+    }
+    urbis_build(idx);
+    
+    size_t count = urbis_list_pages(idx, NULL, 0);
+    assert(count >= 5);
+    
+    UrbisPageInfo *pages = malloc(count * sizeof(UrbisPageInfo));
+    assert(urbis_list_pages(idx, pages, count) == count);
+    
+    size_t objects = 0;
+    for (size_t i = 0; i < count; i++) {
+        objects += pages[i].object_count;
+        if (pages[i].object_count > 0) {
+            assert(pages[i].extent.min_x <= pages[i].extent.max_x);
+        }
+    }
+    assert(objects == 20);
+    
+    free(pages);
+    urbis_destroy(idx);
+}
+
+TEST(geojson_properties) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    const char *geojson =
+        "{\"type\":\"FeatureCollection\",\"features\":["
+        "{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[1,2]},"
+        "\"properties\":{\"name\":\"say \\\"hi\\\"\",\"rank\":2.5,\"open\":true,\"tags\":[1,null]}},"
+        "{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[3,4]},"
+        "\"properties\":{}}]}";
+    assert(urbis_load_geojson_string(idx, geojson) == URBIS_OK);
+    
+    UrbisObjectList *all = urbis_query_changed_since(idx, INT64_MIN);
+    assert(all && all->count == 2);
+    for (size_t i = 0; i < all->count; i++) {
+        SpatialObject *obj = all->objects[i];
+        size_t size = 0;
+        const char *props = urbis_get_properties(idx, obj->id, &size);
+        if (obj->centroid.x == 1) {
+            const char *want = "{\"name\":\"say \\\"hi\\\"\",\"rank\":2.5,\"open\":true,\"tags\":[1,null]}";
+            assert(props && size == strlen(want) && memcmp(props, want, size) == 0);
+        } else {
+            assert(props == NULL);
+        }
+    }
+    urbis_object_list_free(all);
+    
+    urbis_destroy(idx);
+}
+
+TEST(corrupt_page_skipped) {
+    UrbisConfig config = urbis_default_config();
+    config.page_capacity = 4;
+    UrbisIndex *idx = urbis_create(&config);
+    
+    for (int i = 0; i < 16; i++) {
+        urbis_insert_point(idx, i, i);
+    }
+    assert(urbis_build(idx) == URBIS_OK);
+    
+    MBR all = mbr_create(-1, -1, 20, 20);
+    UrbisObjectList *list = urbis_query_range(idx, &all);
+    assert(list && list->count == 16 && list->failed_count == 0);
+    urbis_object_list_free(list);
+    
+    /* Flip a stored centroid behind the checksum's back */
+    Page *bad = idx->disk.pool.pages[0];
+    size_t lost = bad->header.object_count;
+    assert(lost > 0);
+    bad->objects[0].centroid.x += 0.5;
+    
+    list = urbis_query_range(idx, &all);
+    assert(list && list->failed_count == 1);
+    assert(list->failed_pages[0] == bad->header.page_id);
+    assert(list->count == 16 - lost);
+    urbis_object_list_free(list);
+    
+    urbis_destroy(idx);
+}
+
+TEST(simplify_on_insert) {
+    UrbisConfig config = urbis_default_config();
+    config.simplify_tolerance = 0.1;
+    UrbisIndex *idx = urbis_create(&config);
+    
+    /* A nearly flat line with a spike at x=5: the flat runs collapse to
+     * the spike and its two feet */
+    Point line[] = {
+        {0, 0}, {1, 0.01}, {2, -0.02}, {3, 0.03}, {4, 0}, {5, 2},
+        {6, 0.01}, {7, 0}, {8, -0.01}, {9, 0}
+    };
+    uint64_t lid = urbis_insert_linestring(idx, line, 10);
+    assert(lid != 0);
+    SpatialObject *obj = urbis_get(idx, lid);
+    assert(obj->geom.line.count == 5);
+    assert(obj->geom.line.points[0].x == 0 && obj->geom.line.points[4].x == 9);
+    assert(obj->geom.line.points[1].x == 4 && obj->geom.line.points[3].x == 6);
+    assert(obj->geom.line.points[2].x == 5 && obj->geom.line.points[2].y == 2);
+    
+    /* A square with jittered midpoints keeps its four corners */
+    Point square[] = {
+        {0, 0}, {5, 0.02}, {10, 0}, {10.01, 5}, {10, 10},
+        {5, 10}, {0, 10}, {0, 0}
+    };
+    uint64_t pid = urbis_insert_polygon(idx, square, 8);
+    obj = urbis_get(idx, pid);
+    assert(obj->geom.polygon.ext_count == 5);
+    assert(obj->mbr.max_x == 10 && obj->mbr.max_y == 10);
+    
+    /* A ring smaller than the tolerance is kept whole rather than collapsed */
+    Point tiny[] = {{0, 0}, {0.01, 0}, {0.01, 0.01}, {0, 0.01}, {0, 0}};
+    uint64_t tid = urbis_insert_polygon(idx, tiny, 5);
+    assert(urbis_get(idx, tid)->geom.polygon.ext_count == 5);
+    
+    urbis_destroy(idx);
+}
+
+TEST(export_geojson) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    /* Loaded as GeoJSON because the WKT parser drops holes */
+    assert(urbis_load_geojson_string(idx,
+        "{\"type\":\"Polygon\",\"coordinates\":"
+        "[[[0,0],[10,0],[10,10],[0,10],[0,0]],[[2,2],[4,2],[4,4],[2,2]]]}") == URBIS_OK);
+    assert(urbis_load_wkt(idx, "POINT(1.5 -0.1)") == URBIS_OK);
+    
+    const char *polygon = "{\"type\":\"Polygon\",\"coordinates\":"
+                          "[[[0,0],[10,0],[10,10],[0,10],[0,0]],[[2,2],[4,2],[4,4],[2,2]]]}";
+    int len = urbis_export_geojson(idx, 1, NULL, 0);
+    assert(len == (int)strlen(polygon));
+    char out[128];
+    assert(urbis_export_geojson(idx, 1, out, sizeof(out)) == len);
+    assert(strcmp(out, polygon) == 0);
+    
+    /* A short buffer is filled and terminated, still reporting the full length */
+    char small[8];
+    assert(urbis_export_geojson(idx, 2, small, sizeof(small)) ==
+           (int)strlen("{\"type\":\"Point\",\"coordinates\":[1.5,-0.1]}"));
+    assert(strcmp(small, "{\"type\"") == 0);
+    
+    assert(urbis_export_geojson(idx, 99, out, sizeof(out)) == URBIS_ERR_NOT_FOUND);
+    
+    urbis_destroy(idx);
+}
+
+TEST(seek_cost_model) {
+    MBR region = mbr_create(0, 0, 29, 29);
+    double cost[3];
+    size_t seeks[3];
+    
+    for (int kind = 0; kind < 3; kind++) {
+        UrbisConfig config = urbis_default_config();
+        config.page_capacity = 8;
+        config.seek_cost.kind = (UrbisStorageKind)kind;
+        config.seek_cost.seek_ms = 1.0;
+        config.seek_cost.transfer_mb_s = 4.096;  /* One 4 KB page per ms */
+        UrbisIndex *idx = urbis_create(&config);
+        assert(idx);
+        
+        for (int i = 0; i < 30; i++) {
+            for (int j = 0; j < 30; j++) {
+                urbis_insert_point(idx, i, j);
+            }
+        }
+        urbis_build(idx);
+        
+        UrbisPageList *pages = urbis_find_adjacent_pages(idx, &region);
+        assert(pages && pages->count > 0);
+        seeks[kind] = pages->estimated_seeks;
+        cost[kind] = pages->estimated_cost_ms;
+        if (kind == URBIS_STORAGE_CUSTOM) {
+            /* One ms per seek, including the first, plus one per page */
+            double want = (double)(pages->estimated_seeks + 1) + (double)pages->count;
+            assert(fabs(cost[kind] - want) < 1e-9);
+        }
+        assert(fabs(urbis_estimate_cost_ms(idx, &region, 1) - cost[kind]) < 1e-9);
+        urbis_page_list_free(pages);
+        urbis_destroy(idx);
+    }
+    
+    /* Same layout, so a hard disk only costs more */
+    assert(seeks[URBIS_STORAGE_ROTATIONAL] == seeks[URBIS_STORAGE_SSD]);
+    assert(cost[URBIS_STORAGE_ROTATIONAL] > 10 * cost[URBIS_STORAGE_SSD]);
+    
+    UrbisConfig bad = urbis_default_config();
+    bad.seek_cost.kind = URBIS_STORAGE_CUSTOM;
+    bad.seek_cost.transfer_mb_s = 0;
+    assert(urbis_create(&bad) == NULL);
+}
+
+TEST(nearest_object) {
+    UrbisIndex *idx = urbis_create(NULL);
+    SpatialObject *nearest = NULL;
+    double distance = -1;
+    
+    /* Nothing to find in an empty index */
+    urbis_build(idx);
+    assert(urbis_nearest(idx, 0, 0, &nearest, &distance) == URBIS_ERR_NOT_FOUND);
+    
+    urbis_insert_point(idx, 0, 0);
+    uint64_t near_id = urbis_insert_point(idx, 10, 10);
+    urbis_insert_point(idx, 20, 20);
+    urbis_build(idx);
+    
+    assert(urbis_nearest(idx, 13, 14, &nearest, &distance) == URBIS_OK);
+    assert(nearest->id == near_id);
+    assert(fabs(distance - 5.0) < 1e-9);
+    
+    urbis_destroy(idx);
+}
+
+TEST(append_after_load) {
+    UrbisConfig config = urbis_default_config();
+    config.page_capacity = 8;
+    UrbisIndex *idx = urbis_create(&config);
+    for (int i = 0; i < 100; i++) {
+        urbis_insert_point(idx, i, i);
+    }
+    
+    const char *path = "/tmp/urbis_test_append.dat";
+    assert(urbis_save(idx, path) == URBIS_OK);
+    size_t saved_pages = idx->disk.pool.page_count;
+    urbis_destroy(idx);
+    
+    /* Loaded pages match the file, so a sync writes only what changes */
+    idx = urbis_load(path);
+    assert(idx != NULL);
+    idx->disk.stats.pages_written = 0;
+    uint64_t id = urbis_insert_point(idx, 1000, 1000);
+    assert(id == 101);
+    assert(urbis_sync(idx) == URBIS_OK);
+    assert(idx->disk.stats.pages_written >= 1);
+    assert(idx->disk.stats.pages_written < saved_pages);
+    urbis_destroy(idx);
+    
+    idx = urbis_load(path);
+    assert(urbis_count(idx) == 101);
+    assert(urbis_get(idx, 101) != NULL);
+    urbis_destroy(idx);
+    remove(path);
+}
+
+TEST(tree_nodes) {
+    UrbisConfig config = urbis_default_config();
+    config.page_capacity = 4;
+    UrbisIndex *idx = urbis_create(&config);
+    for (int i = 0; i < 64; i++) {
+        urbis_insert_point(idx, i % 8, i / 8);
+    }
+    assert(urbis_list_tree_nodes(idx, SI_STRUCTURE_KDTREE, 0, NULL, 0) == 0);
+    urbis_build(idx);
+    
+    /* One KD-tree node per object, root first and covering them all */
+    size_t count = urbis_list_tree_nodes(idx, SI_STRUCTURE_KDTREE, 0, NULL, 0);
+    assert(count == 64);
+    UrbisTreeNode *nodes = malloc(count * sizeof(UrbisTreeNode));
+    assert(urbis_list_tree_nodes(idx, SI_STRUCTURE_KDTREE, 0, nodes, count) == count);
+    assert(nodes[0].parent == -1 && nodes[0].depth == 0);
+    assert(nodes[0].object_count == 64 && !nodes[0].is_leaf);
+    assert(nodes[0].split_dim == 0 || nodes[0].split_dim == 1);
+    assert(nodes[1].parent == 0 && nodes[1].depth == 1);
+    free(nodes);
+    
+    /* Limiting the depth lists the root and its children only */
+    assert(urbis_list_tree_nodes(idx, SI_STRUCTURE_KDTREE, 2, NULL, 0) == 3);
+    
+    /* The quadtree root counts every page's objects */
+    count = urbis_list_tree_nodes(idx, SI_STRUCTURE_QUADTREE, 1, NULL, 0);
+    assert(count == 1);
+    UrbisTreeNode root;
+    urbis_list_tree_nodes(idx, SI_STRUCTURE_QUADTREE, 1, &root, 1);
+    assert(root.object_count == 64);
+    assert(root.item_count == urbis_list_pages(idx, NULL, 0));
+    assert(root.split_dim == -1);
+    
+    urbis_destroy(idx);
+}
+
+TEST(estimate_count) {
+    UrbisConfig config = urbis_default_config();
+    config.page_capacity = 4;
+    UrbisIndex *idx = urbis_create(&config);
+    for (int i = 0; i < 100; i++) {
+        urbis_insert_point(idx, i % 10, i / 10);
+    }
+    urbis_build(idx);
+    
+    /* Never fewer than the query returns, and everything for the bounds */
+    MBR region = urbis_mbr(2, 2, 4.5, 4.5);
+    UrbisObjectList *result = urbis_query_range(idx, &region);
+    size_t estimate = urbis_estimate_count(idx, &region);
+    assert(estimate >= result->count && estimate <= 100);
+    urbis_object_list_free(result);
+    
+    MBR all = urbis_bounds(idx);
+    assert(urbis_estimate_count(idx, &all) == 100);
+    
+    MBR outside = urbis_mbr(50, 50, 60, 60);
+    assert(urbis_estimate_count(idx, &outside) == 0);
+    
+    urbis_destroy(idx);
+}
+
+TEST(build_threads) {
+    UrbisConfig config = urbis_default_config();
+    UrbisIndex *serial = urbis_create(&config);
+    config.build_threads = 4;
+    UrbisIndex *parallel = urbis_create(&config);
+    for (int i = 0; i < 20000; i++) {
+        double x = (i * 7919) % 1000, y = (i * 104729) % 997;
+        urbis_insert_point(serial, x, y);
+        urbis_insert_point(parallel, x, y);
+    }
+    urbis_build(serial);
+    urbis_build(parallel);
+    
+    UrbisStats stats;
+    urbis_get_stats(serial, &stats);
+    assert(stats.build_threads == 1);
+    urbis_get_stats(parallel, &stats);
+    assert(stats.build_threads == 4);
+    
+    /* Threads only change who builds each subtree, not its shape */
+    size_t count = urbis_list_tree_nodes(serial, SI_STRUCTURE_KDTREE, 0, NULL, 0);
+    assert(count == 20000);
+    UrbisTreeNode *a = malloc(count * sizeof(UrbisTreeNode));
+    UrbisTreeNode *b = malloc(count * sizeof(UrbisTreeNode));
+    assert(urbis_list_tree_nodes(serial, SI_STRUCTURE_KDTREE, 0, a, count) == count);
+    assert(urbis_list_tree_nodes(parallel, SI_STRUCTURE_KDTREE, 0, b, count) == count);
+    for (size_t i = 0; i < count; i++) {
+        assert(a[i].parent == b[i].parent && a[i].object_count == b[i].object_count);
+        assert(a[i].split_dim == b[i].split_dim && a[i].split_value == b[i].split_value);
+        assert(a[i].bounds.min_x == b[i].bounds.min_x && a[i].bounds.max_x == b[i].bounds.max_x);
+        assert(a[i].bounds.min_y == b[i].bounds.min_y && a[i].bounds.max_y == b[i].bounds.max_y);
+    }
+    free(a);
+    free(b);
+    
+    urbis_destroy(serial);
+    urbis_destroy(parallel);
+}
+
+TEST(build_seed) {
+    UrbisIndex *idx[3];
+    uint64_t seeds[3] = {7, 7, 8};
+    for (int k = 0; k < 3; k++) {
+        UrbisConfig config = urbis_default_config();
+        config.seed = seeds[k];
+        idx[k] = urbis_create(&config);
+        /* Only four distinct x values, so most splits on x are ties */
+        for (int i = 0; i < 400; i++) {
+            urbis_insert_point(idx[k], i % 4, (i * 37) % 101);
+        }
+        urbis_build(idx[k]);
+    }
+    
+    UrbisTreeNode a[400], b[400], c[400];
+    assert(urbis_list_tree_nodes(idx[0], SI_STRUCTURE_KDTREE, 0, a, 400) == 400);
+    assert(urbis_list_tree_nodes(idx[1], SI_STRUCTURE_KDTREE, 0, b, 400) == 400);
+    assert(urbis_list_tree_nodes(idx[2], SI_STRUCTURE_KDTREE, 0, c, 400) == 400);
+    
+    /* The same seed gives the same tree; another seed splits ties differently */
+    bool differs = false;
+    for (size_t i = 0; i < 400; i++) {
+        assert(a[i].parent == b[i].parent && a[i].split_value == b[i].split_value);
+        assert(a[i].bounds.min_y == b[i].bounds.min_y && a[i].bounds.max_y == b[i].bounds.max_y);
+        if (a[i].bounds.min_y != c[i].bounds.min_y || a[i].bounds.max_y != c[i].bounds.max_y) {
+            differs = true;
+        }
+    }
+    assert(differs);
+    
+    for (int k = 0; k < 3; k++) {
+        urbis_destroy(idx[k]);
+    }
+}
+
+TEST(zm_round_trip) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    /* GeoJSON: a third number is Z */
+    assert(urbis_load_geojson_string(idx,
+        "{\"type\":\"LineString\",\"coordinates\":[[0,0,10],[5,0,12.5],[9,1,11]]}") == URBIS_OK);
+    SpatialObject *line = urbis_get(idx, 1);
+    assert(line && line->dims == GEOM_DIM_Z);
+    assert(line->zm[0] == 10 && line->zm[1] == 12.5 && line->zm[2] == 11);
+    
+    char json[256];
+    urbis_export_geojson(idx, 1, json, sizeof(json));
+    assert(strstr(json, "[5,0,12.5]") != NULL);
+    
+    /* WKT: an M tag makes the third number M; ZM carries both */
+    assert(urbis_load_wkt(idx, "POINT M (3 4 7)") == URBIS_OK);
+    SpatialObject *pt = urbis_get(idx, 2);
+    assert(pt->dims == GEOM_DIM_M && pt->zm[0] == 7);
+    assert(urbis_load_wkt(idx, "POLYGON ZM ((0 0 1 2, 4 0 1 3, 4 4 1 4, 0 0 1 2))") == URBIS_OK);
+    SpatialObject *poly = urbis_get(idx, 3);
+    assert(poly->dims == (GEOM_DIM_Z | GEOM_DIM_M) && poly->zm[3] == 3 && poly->zm[6] == 1);
+    
+    /* WKB export writes ISO Z types, and reads back */
+    uint8_t wkb[256];
+    int size = urbis_export_wkb(idx, 1, wkb, sizeof(wkb));
+    assert(size == 1 + 4 + 4 + 3 * 24);
+    assert(wkb[1] == (1002 & 0xff) && wkb[2] == (1002 >> 8));
+    assert(urbis_load_wkb(idx, wkb, (size_t)size) == URBIS_OK);
+    SpatialObject *copy = urbis_get(idx, 4);
+    assert(copy->dims == GEOM_DIM_Z && copy->zm[1] == 12.5);
+    
+    /* Values can be replaced after inserting; a wrong count is refused */
+    double zm[3] = {1, 2, 3};
+    assert(urbis_set_zm(idx, 1, GEOM_DIM_M, zm, 2) == URBIS_ERR_INVALID);
+    assert(urbis_set_zm(idx, 1, GEOM_DIM_M, zm, 3) == URBIS_OK);
+    line = urbis_get(idx, 1);
+    assert(line->dims == GEOM_DIM_M && line->zm[2] == 3);
+    
+    /* 2D input stays 2D */
+    assert(urbis_load_wkt(idx, "POINT (1 1)") == URBIS_OK);
+    assert(urbis_get(idx, 5)->dims == 0 && urbis_get(idx, 5)->zm == NULL);
+    
+    urbis_destroy(idx);
+}
+
+/* Simplification drops the Z of the vertices it drops */
+TEST(zm_simplify) {
+    UrbisConfig config = urbis_default_config();
+    config.simplify_tolerance = 0.5;
+    UrbisIndex *idx = urbis_create(&config);
+    
+    assert(urbis_load_geojson_string(idx,
+        "{\"type\":\"MultiLineString\",\"coordinates\":["
+        "[[0,0,1],[1,0.01,2],[2,0,3]],[[0,5,4],[1,5,5],[2,9,6]]]}") == URBIS_OK);
+    SpatialObject *obj = urbis_get(idx, 1);
+    assert(obj->geom.multi_line.lines[0].count == 2);
+    assert(obj->geom.multi_line.lines[1].count == 3);
+    assert(spatial_object_vertex_count(obj) == 5);
+    assert(obj->zm[0] == 1 && obj->zm[1] == 3 && obj->zm[2] == 4 && obj->zm[4] == 6);
+    
+    urbis_destroy(idx);
+}
+
+/* A result lists each object ID once after deduplication */
+TEST(result_dedupe) {
+    SpatialObject a = { .id = 7 }, b = { .id = 9 }, copy = { .id = 7 };
+    SpatialQueryResult result;
+    assert(spatial_result_init(&result, 2) == SI_OK);
+    
+    spatial_result_add(&result, &a);
+    spatial_result_add(&result, &b);
+    spatial_result_add(&result, &copy);
+    spatial_result_add(&result, &b);
+    assert(spatial_result_dedupe(&result) == SI_OK);
+    assert(result.count == 2 && result.duplicates == 2);
+    assert(result.objects[0] == &a && result.objects[1] == &b);
+    
+    spatial_result_free(&result);
+}
+
+/* Features with null or empty geometries are skipped and counted */
+TEST(geojson_skip_empty) {
+    UrbisIndex *idx = urbis_create(NULL);
+    size_t skipped = 99;
+    
+    assert(urbis_load_geojson_string_counting(idx,
+        "{\"type\":\"FeatureCollection\",\"features\":["
+        "{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[1,2]},\"properties\":{}},"
+        "{\"type\":\"Feature\",\"geometry\":null,\"properties\":{}},"
+        "{\"type\":\"Feature\",\"geometry\":{\"type\":\"LineString\",\"coordinates\":[]},\"properties\":{}},"
+        "{\"type\":\"Feature\",\"geometry\":{\"type\":\"GeometryCollection\",\"geometries\":[]},\"properties\":{}},"
+        "{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[3,4]},\"properties\":{}}"
+        "]}", &skipped) == URBIS_OK);
+    assert(urbis_count(idx) == 2);
+    assert(skipped == 3);
+    
+    assert(urbis_load_geojson_string_counting(idx,
+        "{\"type\":\"Feature\",\"geometry\":null,\"properties\":{}}", &skipped) == URBIS_OK);
+    assert(skipped == 1 && urbis_count(idx) == 2);
+    
+    urbis_destroy(idx);
+}
+
+/* Object IDs and point geometry survive a save and load, and the ID
+ * counter is not rewound by removing the newest object */
+TEST(save_load_ids) {
+    UrbisIndex *idx = urbis_create(NULL);
+    uint64_t ids[20];
+    
+    for (int i = 0; i < 20; i++) {
+        ids[i] = urbis_insert_point(idx, i, i * 2);
+    }
+    uint64_t last = urbis_insert_point(idx, -5, -5);
+    assert(urbis_remove(idx, last) == URBIS_OK);
+    urbis_build(idx);
+    
+    const char *path = "/tmp/urbis_test_save_load_ids.dat";
+    assert(urbis_save(idx, path) == URBIS_OK);
+    urbis_destroy(idx);
+    
+    idx = urbis_load(path);
+    assert(idx != NULL);
+    for (int i = 0; i < 20; i++) {
+        SpatialObject *obj = urbis_get(idx, ids[i]);
+        assert(obj != NULL && obj->type == GEOM_POINT);
+        assert(obj->geom.point.x == i && obj->geom.point.y == i * 2);
+    }
+    assert(urbis_insert_point(idx, 1, 1) > last);
+    
+    urbis_destroy(idx);
+    remove(path);
+}
+
+static bool cancel_at_start(void *user_data) {
+    int *polls = (int *)user_data;
+    (*polls)++;
+    return true;
+}
+
+/* A cancelled build leaves a previously built index unbuilt; a cancelled
+ * optimize leaves it built */
+TEST(build_cancel) {
+    UrbisIndex *idx = urbis_create(NULL);
+    for (int i = 0; i < 100; i++) {
+        urbis_insert_point(idx, i, i);
+    }
+    assert(urbis_build(idx) == URBIS_OK);
+    assert(urbis_is_built(idx));
+    
+    int polls = 0;
+    assert(urbis_build_cancellable(idx, NULL, cancel_at_start, &polls) == URBIS_ERR_CANCELLED);
+    assert(polls == 1);
+    assert(!urbis_is_built(idx));
+    
+    UrbisStats stats;
+    urbis_get_stats(idx, &stats);
+    assert(stats.total_blocks == 0);
+    
+    assert(urbis_build_cancellable(idx, NULL, NULL, NULL) == URBIS_OK);
+    assert(urbis_is_built(idx));
+    
+    /* A cancelled optimize keeps the build it was replacing */
+    polls = 0;
+    assert(urbis_optimize_cancellable(idx, cancel_at_start, &polls) == URBIS_ERR_CANCELLED);
+    assert(polls == 1);
+    assert(urbis_is_built(idx));
+    MBR range = {10, 10, 20, 20};
+    UrbisObjectList *list = urbis_query_range(idx, &range);
+    assert(list && list->count == 11);
+    urbis_object_list_free(list);
+    assert(urbis_optimize_cancellable(idx, NULL, NULL) == URBIS_OK);
+    assert(urbis_is_built(idx));
+    
+    urbis_destroy(idx);
+}
+
+/* Checking GeoJSON reports bad features by position and inserts nothing */
+TEST(geojson_check) {
+    UrbisGeoJSONCheck check;
+    
+    assert(urbis_check_geojson_string(
+        "{\"type\":\"FeatureCollection\",\"features\":["
+        "{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[1,2]},\"properties\":{}},"
+        "{\"type\":\"Feature\",\"geometry\":{\"type\":\"Blob\",\"coordinates\":[1,2]},\"properties\":{}},"
+        "{\"type\":\"Feature\",\"geometry\":null,\"properties\":{}},"
+        "{\"type\":\"Feature\",\"properties\":{}},"
+        "{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[3,4]},\"properties\":{}}"
+        "]}", &check) == URBIS_OK);
+    assert(check.valid == 2);
+    assert(check.skipped == 1);
+    assert(check.error_count == 2);
+    assert(check.errors[0].index == 1 && check.errors[0].code == PARSE_ERR_UNSUPPORTED);
+    assert(check.errors[1].index == 3 && check.errors[1].code == PARSE_ERR_INVALID_GEOM);
+    urbis_geojson_check_free(&check);
+    
+    assert(urbis_check_geojson_string("{\"type\":", &check) == URBIS_ERR_PARSE);
+    assert(check.errors == NULL);
+}
+
+/* Inserts and removals count up until a build clears them */
+TEST(pending_changes) {
+    UrbisIndex *idx = urbis_create(NULL);
+    assert(urbis_pending_changes(idx) == 0);
+    assert(urbis_pending_changes(NULL) == 0);
+    
+    uint64_t first = urbis_insert_point(idx, 0, 0);
+    for (int i = 1; i < 10; i++) {
+        urbis_insert_point(idx, i, i);
+    }
+    assert(urbis_pending_changes(idx) == 10);
+    assert(urbis_build(idx) == URBIS_OK);
+    assert(urbis_pending_changes(idx) == 0);
+    
+    assert(urbis_remove(idx, first) == URBIS_OK);
+    MBR region = mbr_create(4.5, 4.5, 9.5, 9.5);
+    size_t removed = 0;
+    assert(urbis_remove_range(idx, &region, SI_MATCH_CONTAINED, &removed) == URBIS_OK);
+    assert(removed == 5);
+    assert(urbis_pending_changes(idx) == 6);
+    assert(!urbis_is_built(idx));
+    
+    assert(urbis_build(idx) == URBIS_OK);
+    assert(urbis_pending_changes(idx) == 0);
+    
+    urbis_destroy(idx);
+}
+
+TEST(visited_pages) {
+    UrbisConfig config = urbis_default_config();
+    config.page_capacity = 4;
+    UrbisIndex *idx = urbis_create(&config);
+    for (int i = 0; i < 40; i++) {
+        urbis_insert_point(idx, i, i);
+    }
+    assert(urbis_build(idx) == URBIS_OK);
+    
+    MBR region = mbr_create(0, 0, 39, 39);
+    UrbisObjectList *all = urbis_query_range(idx, &region);
+    assert(all != NULL);
+    assert(all->visited_count == all->stats.pages_visited);
+    assert(all->visited_count >= 10);
+    for (size_t i = 0; i < all->visited_count; i++) {
+        for (size_t j = 0; j < i; j++) {
+            assert(all->visited_pages[i] != all->visited_pages[j]);
+        }
+    }
+    urbis_object_list_free(all);
+    
+    UrbisObjectList *point = urbis_query_point(idx, 7, 7);
+    assert(point != NULL);
+    assert(point->visited_count == point->stats.pages_visited);
+    assert(point->visited_count >= 1);
+    urbis_object_list_free(point);
+    
+    MBR empty = mbr_create(100, 100, 101, 101);
+    UrbisObjectList *none = urbis_query_range(idx, &empty);
+    assert(none != NULL);
+    assert(none->visited_count == 0 && none->visited_pages == NULL);
+    urbis_object_list_free(none);
+    
+    urbis_destroy(idx);
+}
+
+TEST(degenerate_range) {
+    UrbisConfig config = urbis_default_config();
+    config.page_capacity = 8;
+    UrbisIndex *idx = urbis_create(&config);
+    for (int i = 0; i < 100; i++) {
+        urbis_insert_point(idx, i % 10, i / 10);
+    }
+    assert(urbis_build(idx) == URBIS_OK);
+    
+    /* Edges are inclusive, so boxes with no width or height still match */
+    struct {
+        MBR region;
+        size_t want;
+    } cases[] = {
+        {{4, 5, 4, 5}, 1},          /* zero area, on a point */
+        {{0.5, 0.5, 0.5, 0.5}, 0},  /* zero area, between points */
+        {{3, 0, 3, 9}, 10},         /* zero width */
+        {{0, 9, 9, 9}, 10},         /* zero height */
+    };
+    SpatialStructure structures[] = {SI_STRUCTURE_KDTREE, SI_STRUCTURE_QUADTREE, SI_STRUCTURE_SCAN};
+    for (size_t i = 0; i < sizeof(cases) / sizeof(cases[0]); i++) {
+        for (size_t s = 0; s < 3; s++) {
+            UrbisObjectList *list = urbis_query_range_using(idx, &cases[i].region, structures[s]);
+            assert(list != NULL);
+            assert(list->count == cases[i].want);
+            urbis_object_list_free(list);
+        }
+    }
+    
+    urbis_destroy(idx);
+}
+
+static void set_file_version(const char *path, uint32_t version) {
+    FILE *f = fopen(path, "r+b");
+    assert(f != NULL);
+    assert(fseek(f, sizeof(uint32_t), SEEK_SET) == 0);  /* after the magic */
+    assert(fwrite(&version, sizeof(version), 1, f) == 1);
+    fclose(f);
+}
+
+TEST(format_version) {
+    UrbisIndex *idx = urbis_create(NULL);
+    urbis_insert_point(idx, 1, 2);
+    urbis_build(idx);
+    
+    const char *path = "/tmp/urbis_test_format_version.dat";
+    assert(urbis_save(idx, path) == URBIS_OK);
+    urbis_destroy(idx);
+    
+    uint32_t version = 0;
+    assert(urbis_file_format_version(path, &version) == URBIS_OK);
+    assert(version == urbis_format_version());
+    assert(urbis_file_format_version("/tmp/urbis_test_no_such_file.dat", &version) == URBIS_ERR_IO);
+    
+    /* A newer format is refused rather than misread */
+    set_file_version(path, urbis_format_version() + 1);
+    assert(urbis_load_checked(path, &idx) == URBIS_ERR_VERSION);
+    assert(idx == NULL);
+    assert(urbis_load(path) == NULL);
+    
+    /* An older one loads and is saved back in the current format */
+    set_file_version(path, 0);
+    assert(urbis_load_checked(path, &idx) == URBIS_OK);
+    assert(idx != NULL && urbis_count(idx) == 1);
+    assert(urbis_save(idx, path) == URBIS_OK);
+    urbis_destroy(idx);
+    assert(urbis_file_format_version(path, &version) == URBIS_OK);
+    assert(version == urbis_format_version());
+    
+    assert(urbis_load_checked("/tmp/urbis_test_no_such_file.dat", &idx) == URBIS_ERR_IO);
+    remove(path);
+}
+
+TEST(valid_bounds) {
+    MBR bounds = urbis_mbr(88.2, 22.4, 88.5, 22.7);
+    UrbisConfig config = urbis_default_config();
+    config.valid_bounds = &bounds;
+    UrbisIndex *idx = urbis_create(&config);
+    assert(idx != NULL);
+    
+    assert(urbis_insert_point(idx, 88.36, 22.57) != 0);
+    assert(urbis_insert_point(idx, 22.57, 88.36) == 0);  /* Swapped */
+    assert(urbis_load_wkt(idx, "LINESTRING (88.3 22.5, 88.3 23.5)") == URBIS_ERR_INVALID);
+    assert(urbis_out_of_bounds(idx) == 2);
+    
+    /* A load leaves the feature out and goes on */
+    size_t skipped = 0;
+    assert(urbis_load_geojson_string_counting(idx,
+        "{\"type\":\"FeatureCollection\",\"features\":["
+        "{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[0,0]}},"
+        "{\"type\":\"Feature\",\"geometry\":{\"type\":\"Point\",\"coordinates\":[88.5,22.7]}}]}",
+        &skipped) == URBIS_OK);
+    assert(skipped == 0);
+    assert(urbis_out_of_bounds(idx) == 3);
+    assert(urbis_count(idx) == 2);
+    urbis_destroy(idx);
+    
+    MBR inverted = urbis_mbr(1, 0, 0, 1);
+    config.valid_bounds = &inverted;
+    assert(urbis_create(&config) == NULL);
+}
+
+TEST(save_twice) {
+    UrbisIndex *idx = urbis_create(NULL);
+    urbis_insert_point(idx, 1, 1);
+    
+    const char *first = "/tmp/urbis_test_save_first.dat";
+    const char *second = "/tmp/urbis_test_save_second.dat";
+    assert(urbis_save(idx, first) == URBIS_OK);
+    uint64_t changes = urbis_change_count(idx);
+    urbis_insert_point(idx, 2, 2);
+    assert(urbis_change_count(idx) == changes + 1);
+    assert(urbis_save(idx, second) == URBIS_OK);
+    urbis_destroy(idx);
+    
+    /* The second file holds the point saved to the first one too. The
+     * index stays attached to the file it last saved to, so moving to the
+     * second one flushed the new point into the first as well. */
+    idx = urbis_load(first);
+    assert(idx != NULL && urbis_count(idx) == 2);
+    urbis_destroy(idx);
+    idx = urbis_load(second);
+    assert(idx != NULL && urbis_count(idx) == 2);
+    assert(urbis_change_count(idx) == 0);
+    urbis_destroy(idx);
+    
+    remove(first);
+    remove(second);
+}
+
+static bool contains_only(UrbisIndex *idx, const Point *ring, size_t count, uint64_t id) {
+    UrbisObjectList *result = urbis_query_containing_polygon(idx, ring, count);
+    bool ok = result && (id == 0 ? result->count == 0
+                                 : result->count == 1 && result->objects[0]->id == id);
+    urbis_object_list_free(result);
+    return ok;
+}
+
+TEST(query_containing_polygon) {
+    UrbisIndex *idx = urbis_create(NULL);
+    
+    assert(urbis_load_geojson_string(idx, "{\"type\":\"Polygon\",\"coordinates\":["
+        "[[0,0],[10,0],[10,10],[0,10],[0,0]],[[4,4],[6,4],[6,6],[4,6],[4,4]]]}") == URBIS_OK);
+    Point notched[] = {{20, 0}, {30, 0}, {30, 4}, {24, 4}, {24, 10}, {20, 10}};
+    uint64_t l_shape = urbis_insert_polygon(idx, notched, 6);
+    urbis_build(idx);
+    
+    UrbisObjectList *loaded = urbis_query_containing(idx, 1, 1);
+    assert(loaded && loaded->count == 1);
+    uint64_t region = loaded->objects[0]->id;
+    urbis_object_list_free(loaded);
+    
+    Point inside[] = {{1, 1}, {3, 1}, {3, 3}, {1, 3}};
+    assert(contains_only(idx, inside, 4, region));
+    
+    /* Shared edges and touching a hole from outside still count */
+    Point corner[] = {{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}};
+    assert(contains_only(idx, corner, 5, region));
+    Point beside_hole[] = {{6, 4}, {8, 4}, {8, 6}, {6, 6}};
+    assert(contains_only(idx, beside_hole, 4, region));
+    Point same[] = {{0, 0}, {10, 0}, {10, 10}, {0, 10}};
+    assert(contains_only(idx, same, 4, 0));
+    
+    /* A hole inside the ring, or the hole itself, is a gap */
+    Point around_hole[] = {{3, 3}, {7, 3}, {7, 7}, {3, 7}};
+    assert(contains_only(idx, around_hole, 4, 0));
+    Point hole[] = {{4, 4}, {6, 4}, {6, 6}, {4, 6}};
+    assert(contains_only(idx, hole, 4, 0));
+    
+    Point crossing[] = {{8, 8}, {12, 8}, {12, 9}, {8, 9}};
+    assert(contains_only(idx, crossing, 4, 0));
+    
+    /* Every vertex inside, but an edge cuts across the notch */
+    Point across_notch[] = {{21, 9}, {21, 1}, {29, 1}};
+    assert(contains_only(idx, across_notch, 3, 0));
+    Point in_leg[] = {{21, 9}, {21, 1}, {23, 1}};
+    assert(contains_only(idx, in_leg, 3, l_shape));
+    
+    urbis_destroy(idx);
+}
+
+TEST(saved_settings) {
+    MBR bounds = urbis_mbr(0, 0, 10, 10);
+    UrbisConfig config = urbis_default_config();
+    config.valid_bounds = &bounds;
+    UrbisIndex *idx = urbis_create(&config);
+    assert(urbis_insert_point(idx, 1, 1) != 0);
+    
+    char big[URBIS_MAX_SETTINGS + 1];
+    memset(big, 'x', sizeof(big));
+    assert(urbis_set_settings(idx, big, sizeof(big)) == URBIS_ERR_FULL);
+    assert(urbis_set_settings(idx, "{\"crs\":4326}", 12) == URBIS_OK);
+    
+    const char *path = "/tmp/urbis_test_saved_settings.dat";
+    assert(urbis_save(idx, path) == URBIS_OK);
+    urbis_destroy(idx);
+    
+    /* Settings and valid bounds come back with the objects */
+    idx = urbis_load(path);
+    assert(idx != NULL && urbis_count(idx) == 1);
+    size_t size = 0;
+    const char *settings = (const char *)urbis_get_settings(idx, &size);
+    assert(size == 12 && memcmp(settings, "{\"crs\":4326}", 12) == 0);
+    MBR loaded;
+    assert(urbis_valid_bounds(idx, &loaded));
+    assert(loaded.min_x == 0 && loaded.max_x == 10 && loaded.max_y == 10);
+    assert(urbis_insert_point(idx, 20, 20) == 0);
+    assert(urbis_insert_point(idx, 2, 2) != 0);
+    
+    /* They survive compaction and a second save */
+    UrbisIndex *repaged = urbis_repage(idx, 32);
+    assert(repaged != NULL);
+    assert(urbis_save(repaged, path) == URBIS_OK);
+    urbis_destroy(repaged);
+    urbis_destroy(idx);
+    idx = urbis_load(path);
+    assert(idx != NULL && urbis_count(idx) == 2);
+    assert(urbis_get_settings(idx, &size) != NULL && size == 12);
+    assert(urbis_valid_bounds(idx, NULL));
+    urbis_destroy(idx);
+    
+    /* An unbounded index without settings saves neither */
+    idx = urbis_create(NULL);
+    assert(urbis_save(idx, path) == URBIS_OK);
+    urbis_destroy(idx);
+    idx = urbis_load(path);
+    assert(urbis_get_settings(idx, &size) == NULL && size == 0);
+    assert(!urbis_valid_bounds(idx, NULL));
+    urbis_destroy(idx);
+    remove(path);
+}
+
+/* Vertex counts are predicted as insert will store them */
+TEST(stored_vertex_count) {
+    UrbisConfig config = urbis_default_config();
+    config.simplify_tolerance = 0.1;
+    UrbisIndex *idx = urbis_create(&config);
+    
+    Point line[] = {
+        {0, 0}, {1, 0.01}, {2, -0.02}, {3, 0.03}, {4, 0}, {5, 2},
+        {6, 0.01}, {7, 0}, {8, -0.01}, {9, 0}
+    };
+    assert(urbis_stored_vertex_count(idx, false, line, 10) == 5);
+    uint64_t lid = urbis_insert_linestring(idx, line, 10);
+    assert(spatial_object_vertex_count(urbis_get(idx, lid)) == 5);
+    
+    Point square[] = {
+        {0, 0}, {5, 0.02}, {10, 0}, {10.01, 5}, {10, 10},
+        {5, 10}, {0, 10}, {0, 0}
+    };
+    assert(urbis_stored_vertex_count(idx, true, square, 8) == 5);
+    assert(urbis_stored_vertex_count(idx, false, NULL, 3) == 0);
+    urbis_destroy(idx);
+    
+    /* Without simplification every vertex is kept */
+    idx = urbis_create(NULL);
+    assert(urbis_stored_vertex_count(idx, false, line, 10) == 10);
+    assert(urbis_stored_vertex_count(idx, true, square, 8) == 8);
+    urbis_destroy(idx);
+}
+
+/* A load with a config applies it to the loaded index */
+TEST(load_config) {
+    UrbisConfig config = urbis_default_config();
+    config.enable_quadtree = false;
+    config.snap_grid = 1.0;
+    UrbisIndex *idx = urbis_create(&config);
+    assert(urbis_insert_point(idx, 1.2, 1.2) != 0);
+    const char *path = "/tmp/urbis_test_load_config.dat";
+    assert(urbis_save(idx, path) == URBIS_OK);
+    urbis_destroy(idx);
+    
+    UrbisIndex *loaded = NULL;
+    assert(urbis_load_config(path, &config, &loaded) == URBIS_OK);
+    assert(urbis_count(loaded) == 1 && loaded->page_tree == NULL);
+    MBR bounds = urbis_bounds(loaded);
+    assert(bounds.min_x == 1 && bounds.max_x == 1 && bounds.max_y == 1);
+    uint64_t id = urbis_insert_point(loaded, 2.4, 2.4);
+    assert(urbis_get(loaded, id)->geom.point.x == 2);
+    urbis_destroy(loaded);
+    
+    /* NULL means the defaults, as urbis_load_checked */
+    assert(urbis_load_config(path, NULL, &loaded) == URBIS_OK);
+    assert(loaded->page_tree != NULL);
+    urbis_destroy(loaded);
+    
+    assert(urbis_load_config("/tmp/urbis_test_missing.dat", &config, &loaded) == URBIS_ERR_IO);
+    assert(loaded == NULL);
+    remove(path);
+}
+
+TEST(adopt_data_file) {
+    UrbisIndex *idx = urbis_create(NULL);
+    for (int i = 0; i < 200; i++) {
+        assert(urbis_insert_point(idx, i % 20, i / 20) != 0);
+    }
+    const char *path = "/tmp/urbis_test_adopt_data_file.dat";
+    assert(urbis_save(idx, path) == URBIS_OK);
+    
+    UrbisIndex *copy = urbis_repage(idx, 8);
+    assert(copy != NULL);
+    assert(urbis_adopt_data_file(copy, idx) == URBIS_OK);
+    /* Destroying the original no longer writes its pages to the file */
+    urbis_destroy(idx);
+    
+    assert(urbis_insert_point(copy, 50, 50) != 0);
+    assert(urbis_sync(copy) == URBIS_OK);
+    UrbisStats stats;
+    urbis_get_stats(copy, &stats);
+    urbis_destroy(copy);
+    
+    UrbisIndex *loaded = urbis_load(path);
+    assert(loaded != NULL);
+    assert(urbis_count(loaded) == 201);
+    UrbisStats loaded_stats;
+    urbis_get_stats(loaded, &loaded_stats);
+    assert(loaded_stats.total_pages == stats.total_pages);
+    urbis_destroy(loaded);
+    
+    /* Nothing to move from an index without a data file */
+    idx = urbis_create(NULL);
+    copy = urbis_repage(idx, 8);
+    assert(urbis_adopt_data_file(copy, idx) == URBIS_OK);
+    assert(urbis_sync(copy) == URBIS_ERR_IO);
+    urbis_destroy(idx);
+    urbis_destroy(copy);
+    remove(path);
+}
+
+TEST(save_replaces_file) {
+    UrbisIndex *idx = urbis_create(NULL);
+    for (int i = 0; i < 10; i++) {
+        assert(urbis_insert_point(idx, i, i) != 0);
+    }
+    const char *path = "/tmp/urbis_test_save_replaces_file.dat";
+    const char *tmp = "/tmp/urbis_test_save_replaces_file.dat.tmp";
+    assert(urbis_save(idx, path) == URBIS_OK);
+    
+    /* Saving over the index's own data file writes a new one in its place */
+    assert(urbis_insert_point(idx, 20, 20) != 0);
+    assert(urbis_save(idx, path) == URBIS_OK);
+    assert(access(tmp, F_OK) != 0);
+    
+    /* and leaves path the file urbis_sync writes to */
+    assert(urbis_insert_point(idx, 30, 30) != 0);
+    assert(urbis_sync(idx) == URBIS_OK);
+    assert(access(tmp, F_OK) != 0);
+    
+    UrbisIndex *loaded = urbis_load(path);
+    assert(loaded != NULL);
+    assert(urbis_count(loaded) == 12);
+    urbis_destroy(loaded);
+    
+    /* A failed save leaves the index without a data file */
+    assert(urbis_save(idx, "/nonexistent/urbis/index.dat") == URBIS_ERR_IO);
+    assert(urbis_sync(idx) == URBIS_ERR_IO);
+    urbis_destroy(idx);
+    
+    loaded = urbis_load(path);
+    assert(loaded != NULL);
+    assert(urbis_count(loaded) == 12);
+    urbis_destroy(loaded);
+    remove(path);
+}
+
 /* ============================================================================
  * Main
  * ============================================================================ */
@@ -306,6 +1869,53 @@ int main(void) {
     RUN_TEST(bounds);
     RUN_TEST(stats);
     RUN_TEST(wkt_loading);
+    RUN_TEST(query_stats);
+    RUN_TEST(multi_geometry_loading);
+    RUN_TEST(build_progress);
+    RUN_TEST(wkb_roundtrip);
+    RUN_TEST(query_containing);
+    RUN_TEST(reject_non_finite);
+    RUN_TEST(snap_and_dedup);
+    RUN_TEST(query_structure_hint);
+    RUN_TEST(set_properties);
+    RUN_TEST(remove_range);
+    RUN_TEST(prefetch_region);
+    RUN_TEST(autotune_page_capacity);
+    RUN_TEST(changed_since);
+    RUN_TEST(memory_and_disk_stats);
+    RUN_TEST(ewkt_loading);
+    RUN_TEST(explicit_ids);
+    RUN_TEST(list_pages);
+    RUN_TEST(geojson_properties);
+    RUN_TEST(corrupt_page_skipped);
+    RUN_TEST(simplify_on_insert);
+    RUN_TEST(export_geojson);
+    RUN_TEST(seek_cost_model);
+    RUN_TEST(nearest_object);
+    RUN_TEST(append_after_load);
+    RUN_TEST(tree_nodes);
+    RUN_TEST(estimate_count);
+    RUN_TEST(build_threads);
+    RUN_TEST(build_seed);
+    RUN_TEST(zm_round_trip);
+    RUN_TEST(zm_simplify);
+    RUN_TEST(result_dedupe);
+    RUN_TEST(geojson_skip_empty);
+    RUN_TEST(save_load_ids);
+    RUN_TEST(build_cancel);
+    RUN_TEST(geojson_check);
+    RUN_TEST(pending_changes);
+    RUN_TEST(visited_pages);
+    RUN_TEST(degenerate_range);
+    RUN_TEST(format_version);
+    RUN_TEST(valid_bounds);
+    RUN_TEST(save_twice);
+    RUN_TEST(query_containing_polygon);
+    RUN_TEST(saved_settings);
+    RUN_TEST(stored_vertex_count);
+    RUN_TEST(load_config);
+    RUN_TEST(adopt_data_file);
+    RUN_TEST(save_replaces_file);
     
     printf("\n=================================\n");
     printf("Tests passed: %d\n", tests_passed);